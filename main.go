@@ -6,8 +6,8 @@ import (
 	"fmt"
 	"github.com/dikhan/terraform-provider-openapi/openapi"
 	"github.com/dikhan/terraform-provider-openapi/openapi/version"
-	"github.com/hashicorp/terraform-plugin-sdk/plugin"
-	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 	"os"
 	"regexp"
 )
@@ -16,6 +16,21 @@ func main() {
 
 	log.Printf("Running OpenAPI Terraform Provider v%s-%s; Released on: %s", version.Version, version.Commit, version.Date)
 
+	// The source code of a standalone provider can be generated with any binary name, the provider name is an argument
+	if len(os.Args) > 1 && os.Args[1] == openapi.GenerateProviderCommand {
+		if len(os.Args) < 4 {
+			log.Fatalf("[ERROR] Missing arguments, usage: terraform-provider-openapi %s <provider_name> <swagger_url> [output_dir]", openapi.GenerateProviderCommand)
+		}
+		outputDir := ""
+		if len(os.Args) > 4 {
+			outputDir = os.Args[4]
+		}
+		if err := openapi.GenerateProvider(os.Stdout, os.Args[2], os.Args[3], outputDir); err != nil {
+			log.Fatalf("[ERROR] There was an error generating the provider: %s", err)
+		}
+		return
+	}
+
 	ex, err := os.Executable()
 	if err != nil {
 		log.Fatalf("[ERROR] There was an error when getting the provider binary name: %s", err)
@@ -27,6 +42,46 @@ func main() {
 	}
 
 	p := openapi.ProviderOpenAPI{ProviderName: providerName}
+
+	if len(os.Args) > 1 && os.Args[1] == openapi.PrintConfigCommand {
+		if err := p.PrintEffectiveConfiguration(os.Stdout); err != nil {
+			log.Fatalf("[ERROR] There was an error printing the effective configuration of the provider: %s", err)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == openapi.PrintSchemaCommand {
+		sourceAddress := ""
+		if len(os.Args) > 2 {
+			sourceAddress = os.Args[2]
+		}
+		if err := p.PrintSchemaJSON(os.Stdout, sourceAddress); err != nil {
+			log.Fatalf("[ERROR] There was an error printing the schema of the provider: %s", err)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == openapi.SpecLoginCommand {
+		if err := p.SpecLogin(os.Stdout); err != nil {
+			log.Fatalf("[ERROR] There was an error logging in to fetch the OpenAPI document of the provider: %s", err)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == openapi.DriftScanCommand {
+		if len(os.Args) < 3 {
+			log.Fatalf("[ERROR] Missing the terraform state file, usage: terraform-provider-%s %s <state_file> [resource_type_filter...]", providerName, openapi.DriftScanCommand)
+		}
+		driftFound, err := p.DriftScan(os.Stdout, os.Args[2], os.Args[3:])
+		if err != nil {
+			log.Fatalf("[ERROR] There was an error scanning the API objects for drift: %s", err)
+		}
+		if driftFound {
+			os.Exit(2)
+		}
+		return
+	}
+
 	provider, err := p.CreateSchemaProvider()
 	if err != nil {
 		log.Fatalf("[ERROR] There was an error initialising the terraform provider: %s", err)
@@ -34,7 +89,7 @@ func main() {
 
 	plugin.Serve(
 		&plugin.ServeOpts{
-			ProviderFunc: func() terraform.ResourceProvider {
+			ProviderFunc: func() *schema.Provider {
 				return provider
 			},
 		})
@@ -0,0 +1,42 @@
+package openapi
+
+import (
+	"log"
+)
+
+// apiCallLimiter caps the number of API calls that can be in flight at the same time, regardless of the terraform parallelism,
+// for APIs that can not handle bursts of requests. A nil apiCallLimiter is valid and means no limit is applied.
+type apiCallLimiter struct {
+	slots chan struct{}
+}
+
+// newAPICallLimiter returns an apiCallLimiter allowing the given number of concurrent API calls. Nil is returned if the
+// number is not greater than zero
+func newAPICallLimiter(maxParallelAPICalls int) *apiCallLimiter {
+	if maxParallelAPICalls <= 0 {
+		return nil
+	}
+	return &apiCallLimiter{slots: make(chan struct{}, maxParallelAPICalls)}
+}
+
+// acquire blocks until there is a slot available for an API call
+func (l *apiCallLimiter) acquire() {
+	if l == nil {
+		return
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return
+	default:
+	}
+	log.Printf("[DEBUG] max parallel API calls (%d) reached, waiting for a slot", cap(l.slots))
+	l.slots <- struct{}{}
+}
+
+// release frees the slot acquired by an API call once it is done
+func (l *apiCallLimiter) release() {
+	if l == nil {
+		return
+	}
+	<-l.slots
+}
@@ -0,0 +1,43 @@
+package openapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAPICallLimiter(t *testing.T) {
+	assert.Nil(t, newAPICallLimiter(0))
+	assert.Nil(t, newAPICallLimiter(-1))
+	assert.Equal(t, 2, cap(newAPICallLimiter(2).slots))
+}
+
+func TestAPICallLimiter_Nil(t *testing.T) {
+	var limiter *apiCallLimiter
+	limiter.acquire()
+	limiter.acquire()
+	limiter.release()
+	limiter.release()
+}
+
+func TestAPICallLimiter_AcquireBlocksUntilRelease(t *testing.T) {
+	limiter := newAPICallLimiter(1)
+	limiter.acquire()
+	acquired := make(chan struct{})
+	go func() {
+		limiter.acquire()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		assert.Fail(t, "the slot should not be acquired while the limit is reached")
+	case <-time.After(50 * time.Millisecond):
+	}
+	limiter.release()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		assert.Fail(t, "the slot should be acquired once released")
+	}
+}
@@ -0,0 +1,177 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// slowestCallsSummarySize is the max number of calls listed in the summary of the slowest calls
+const slowestCallsSummarySize = 10
+
+// Resource operations the API calls are made for
+const (
+	callOperationCreate = "create"
+	callOperationRead   = "read"
+	callOperationUpdate = "update"
+	callOperationDelete = "delete"
+	callOperationList   = "list"
+)
+
+// apiCall describes an API call made for a resource operation and the time it took. The URL is left out on purpose since
+// it may contain credentials (e.g. api keys sent as query parameters)
+type apiCall struct {
+	resourceName string
+	operation    string
+	// id is the id of the resource instance the call was made for; empty for create and list operations
+	id       string
+	method   httpMethodSupported
+	duration time.Duration
+}
+
+func (c apiCall) String() string {
+	resource := fmt.Sprintf("resource '%s'", c.resourceName)
+	if c.id != "" {
+		resource = fmt.Sprintf("resource '%s' (id: %s)", c.resourceName, c.id)
+	}
+	return fmt.Sprintf("%s %s operation (%s) took %s", resource, c.operation, c.method, c.duration)
+}
+
+// callTracker keeps track of the time taken by the API calls made across the whole terraform run, warning about the calls
+// exceeding the slow call threshold and keeping the slowest ones. Each create, update and delete operation also gets its
+// own callTracker (see wrapResource) so the slowest calls made for it are summarised once the operation completes. A nil
+// callTracker is valid and means the calls are not tracked.
+type callTracker struct {
+	mutex sync.Mutex
+	// threshold is the latency above which the calls are reported as slow
+	threshold time.Duration
+	calls     int
+	slowCalls int
+	// slowest contains the slowest calls tracked so far, sorted from the slowest
+	slowest []apiCall
+}
+
+// newCallTracker returns a callTracker reporting the calls exceeding the given threshold. Nil is returned if the threshold
+// is not configured.
+func newCallTracker(threshold time.Duration) *callTracker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &callTracker{threshold: threshold}
+}
+
+// track records the given call, logging a warning if it exceeded the slow call threshold
+func (c *callTracker) track(call apiCall) {
+	if c == nil {
+		return
+	}
+	if call.duration > c.threshold {
+		log.Printf("[WARN] slow API call: %s, exceeding the slow call threshold (%s)", call, c.threshold)
+	}
+	c.record(call)
+}
+
+// record records the given call without logging it
+func (c *callTracker) record(call apiCall) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.calls++
+	if call.duration > c.threshold {
+		c.slowCalls++
+	}
+	i := sort.Search(len(c.slowest), func(i int) bool { return c.slowest[i].duration < call.duration })
+	if i >= slowestCallsSummarySize {
+		return
+	}
+	c.slowest = append(c.slowest, apiCall{})
+	copy(c.slowest[i+1:], c.slowest[i:])
+	c.slowest[i] = call
+	if len(c.slowest) > slowestCallsSummarySize {
+		c.slowest = c.slowest[:slowestCallsSummarySize]
+	}
+}
+
+// summary returns the summary of the calls tracked, listing the slowest ones. Empty is returned if no calls were tracked
+func (c *callTracker) summary() string {
+	if c == nil {
+		return ""
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.calls == 0 {
+		return ""
+	}
+	summary := []string{fmt.Sprintf("%d API calls made, %d of them exceeded the slow call threshold (%s). Slowest calls:", c.calls, c.slowCalls, c.threshold)}
+	for i, call := range c.slowest {
+		summary = append(summary, fmt.Sprintf("  %d. %s", i+1, call))
+	}
+	return strings.Join(summary, "\n")
+}
+
+// logOperationSummary logs the summary of the calls tracked for the given resource operation, as a warning if any of them
+// exceeded the slow call threshold
+func (c *callTracker) logOperationSummary(operation, resourceType, id string) {
+	summary := c.summary()
+	if summary == "" {
+		return
+	}
+	resource := resourceType
+	if id != "" {
+		resource = fmt.Sprintf("%s (id: %s)", resourceType, id)
+	}
+	if c.slowCalls > 0 {
+		log.Printf("[WARN] [resource='%s'] %s operation: %s", resource, operation, summary)
+		return
+	}
+	log.Printf("[DEBUG] [resource='%s'] %s operation: %s", resource, operation, summary)
+}
+
+// operationCallTrackerKey is the key of the context value holding the callTracker of the resource operation in progress
+type operationCallTrackerKey struct{}
+
+// operationCallTracker returns the callTracker of the resource operation the given context belongs to, nil if none
+func operationCallTracker(ctx context.Context) *callTracker {
+	tracker, _ := ctx.Value(operationCallTrackerKey{}).(*callTracker)
+	return tracker
+}
+
+// wrapResource makes the create, update and delete operations of the given resource log the summary of the slowest API
+// calls made for them once they complete. Terraform does not pass the address of the resource (e.g. cdn_v1.my_cdn) to
+// the provider, so the resource is identified by its type and id
+func (c *callTracker) wrapResource(resourceType string, resource *schema.Resource) {
+	if c == nil {
+		return
+	}
+	if resource.CreateContext != nil {
+		resource.CreateContext = c.wrap(callOperationCreate, resourceType, resource.CreateContext)
+	}
+	if resource.UpdateContext != nil {
+		resource.UpdateContext = c.wrap(callOperationUpdate, resourceType, resource.UpdateContext)
+	}
+	if resource.DeleteContext != nil {
+		resource.DeleteContext = c.wrap(callOperationDelete, resourceType, resource.DeleteContext)
+	}
+}
+
+func (c *callTracker) wrap(operation, resourceType string, resourceOperation func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+		id := data.Id()
+		tracker := newCallTracker(c.threshold)
+		diags := resourceOperation(context.WithValue(ctx, operationCallTrackerKey{}, tracker), data, i)
+		if data.Id() != "" {
+			id = data.Id()
+		}
+		tracker.logOperationSummary(operation, resourceType, id)
+		return diags
+	}
+}
@@ -0,0 +1,143 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewCallTracker(t *testing.T) {
+	Convey("Given no slow call threshold", t, func() {
+		Convey("When newCallTracker is called", func() {
+			c := newCallTracker(0)
+			Convey("Then the call tracker returned should be nil", func() {
+				So(c, ShouldBeNil)
+			})
+		})
+	})
+	Convey("Given a slow call threshold", t, func() {
+		Convey("When newCallTracker is called", func() {
+			c := newCallTracker(5 * time.Second)
+			Convey("Then the call tracker returned should be configured with the threshold", func() {
+				So(c.threshold, ShouldEqual, 5*time.Second)
+			})
+		})
+	})
+}
+
+func TestCallTrackerTrack(t *testing.T) {
+	Convey("Given a nil call tracker", t, func() {
+		var c *callTracker
+		Convey("When track is called", func() {
+			c.track(apiCall{resourceName: "cdn_v1", operation: callOperationCreate, method: httpPost, duration: time.Minute})
+			Convey("Then the summary returned should be empty", func() {
+				So(c.summary(), ShouldBeEmpty)
+			})
+		})
+	})
+	Convey("Given a call tracker", t, func() {
+		c := newCallTracker(5 * time.Second)
+		Convey("When no calls are tracked", func() {
+			Convey("Then the summary returned should be empty", func() {
+				So(c.summary(), ShouldBeEmpty)
+			})
+		})
+		Convey("When several calls are tracked", func() {
+			c.track(apiCall{resourceName: "cdn_v1", operation: callOperationRead, id: "1234", method: httpGet, duration: 2 * time.Second})
+			c.track(apiCall{resourceName: "cdn_v1", operation: callOperationCreate, method: httpPost, duration: 10 * time.Second})
+			c.track(apiCall{resourceName: "lb_v1", operation: callOperationDelete, id: "5678", method: httpDelete, duration: 6 * time.Second})
+			Convey("Then the calls exceeding the threshold should be counted as slow", func() {
+				So(c.calls, ShouldEqual, 3)
+				So(c.slowCalls, ShouldEqual, 2)
+			})
+			Convey("And the summary returned should list the calls from the slowest", func() {
+				So(c.summary(), ShouldEqual, `3 API calls made, 2 of them exceeded the slow call threshold (5s). Slowest calls:
+  1. resource 'cdn_v1' create operation (POST) took 10s
+  2. resource 'lb_v1' (id: 5678) delete operation (DELETE) took 6s
+  3. resource 'cdn_v1' (id: 1234) read operation (GET) took 2s`)
+			})
+		})
+		Convey("When more calls than the summary size are tracked", func() {
+			for i := 1; i <= slowestCallsSummarySize+5; i++ {
+				c.track(apiCall{resourceName: "cdn_v1", operation: callOperationRead, id: fmt.Sprintf("%d", i), method: httpGet, duration: time.Duration(i) * time.Second})
+			}
+			Convey("Then only the slowest calls should be kept, sorted from the slowest", func() {
+				So(c.calls, ShouldEqual, slowestCallsSummarySize+5)
+				So(c.slowest, ShouldHaveLength, slowestCallsSummarySize)
+				So(c.slowest[0].duration, ShouldEqual, time.Duration(slowestCallsSummarySize+5)*time.Second)
+				So(c.slowest[slowestCallsSummarySize-1].duration, ShouldEqual, 6*time.Second)
+			})
+		})
+	})
+}
+
+func TestCallTrackerWrapResource(t *testing.T) {
+	Convey("Given a call tracker and a resource whose operations make API calls", t, func() {
+		var out bytes.Buffer
+		log.SetOutput(&out)
+		defer log.SetOutput(os.Stderr)
+		c := newCallTracker(5 * time.Second)
+		resource := &schema.Resource{
+			Schema: map[string]*schema.Schema{"name": {Type: schema.TypeString, Optional: true}},
+			CreateContext: func(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+				operationCallTracker(ctx).record(apiCall{resourceName: "cdn_v1", operation: callOperationCreate, method: httpPost, duration: 10 * time.Second})
+				data.SetId("someID")
+				return nil
+			},
+			UpdateContext: func(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+				operationCallTracker(ctx).record(apiCall{resourceName: "cdn_v1", operation: callOperationUpdate, id: "someID", method: httpPut, duration: time.Second})
+				return nil
+			},
+			DeleteContext: func(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+				return nil
+			},
+		}
+		c.wrapResource("openapi_cdn_v1", resource)
+		data := resource.TestResourceData()
+		Convey("When the create operation completes after making a slow API call", func() {
+			diags := resource.CreateContext(context.Background(), data, nil)
+			Convey("Then the summary of the operation should be logged as a warning including the resource type and id", func() {
+				So(diags, ShouldBeEmpty)
+				So(out.String(), ShouldContainSubstring, `[WARN] [resource='openapi_cdn_v1 (id: someID)'] create operation: 1 API calls made, 1 of them exceeded the slow call threshold (5s). Slowest calls:
+  1. resource 'cdn_v1' create operation (POST) took 10s`)
+			})
+		})
+		Convey("When the update operation completes without making slow API calls", func() {
+			data.SetId("someID")
+			resource.UpdateContext(context.Background(), data, nil)
+			Convey("Then the summary of the operation should only be logged for debugging purposes", func() {
+				So(out.String(), ShouldContainSubstring, "[DEBUG] [resource='openapi_cdn_v1 (id: someID)'] update operation: 1 API calls made, 0 of them exceeded the slow call threshold (5s)")
+				So(out.String(), ShouldNotContainSubstring, "[WARN]")
+			})
+		})
+		Convey("When the delete operation completes without making API calls", func() {
+			resource.DeleteContext(context.Background(), data, nil)
+			Convey("Then no summary should be logged", func() {
+				So(out.String(), ShouldBeEmpty)
+			})
+		})
+		Convey("And the calls of each operation should not be mixed with the calls of the rest", func() {
+			resource.CreateContext(context.Background(), data, nil)
+			resource.UpdateContext(context.Background(), data, nil)
+			So(out.String(), ShouldContainSubstring, "update operation: 1 API calls made")
+		})
+	})
+	Convey("Given a nil call tracker", t, func() {
+		var c *callTracker
+		resource := &schema.Resource{}
+		Convey("When wrapResource is called", func() {
+			c.wrapResource("openapi_cdn_v1", resource)
+			Convey("Then the resource operations should be left as is", func() {
+				So(resource.CreateContext, ShouldBeNil)
+			})
+		})
+	})
+}
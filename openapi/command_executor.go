@@ -0,0 +1,166 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// commandShell defines how the commands configured in the plugin configuration file (e.g. the commands of the provider
+// properties or the credential helper command) are executed
+type commandShell string
+
+const (
+	// commandShellNone executes the command directly: the first element is the executable and the rest its arguments,
+	// which are passed as they are without any shell quoting or expansion
+	commandShellNone commandShell = "none"
+	// commandShellDefault executes the command with the default shell of the OS the provider runs in: sh in Linux and
+	// macOS, and cmd in Windows
+	commandShellDefault commandShell = "default"
+	// commandShellSh executes the command with the POSIX shell (sh -c)
+	commandShellSh commandShell = "sh"
+	// commandShellCmd executes the command with the Windows command interpreter (cmd /c)
+	commandShellCmd commandShell = "cmd"
+	// commandShellPowerShell executes the command with PowerShell, which is available in Windows (powershell) as well as
+	// in Linux and macOS (pwsh)
+	commandShellPowerShell commandShell = "powershell"
+)
+
+var commandShells = []commandShell{commandShellNone, commandShellDefault, commandShellSh, commandShellCmd, commandShellPowerShell}
+
+// isValidCommandShell returns true if the given shell is supported, an empty shell means the command is executed directly
+func isValidCommandShell(shell string) bool {
+	if shell == "" {
+		return true
+	}
+	for _, commandShell := range commandShells {
+		if string(commandShell) == shell {
+			return true
+		}
+	}
+	return false
+}
+
+// validateCommandShell makes sure the given shell is supported and the commands can be executed with it in the OS the
+// provider runs in (e.g. cmd is only available in Windows)
+func validateCommandShell(shell string) error {
+	if !isValidCommandShell(shell) {
+		return fmt.Errorf("value '%s' is not valid, supported values: %s", shell, commandShellNames())
+	}
+	if !isCommandShellAvailable(commandShell(shell).resolve()) {
+		return fmt.Errorf("value '%s' is not supported in %s", shell, runtime.GOOS)
+	}
+	return nil
+}
+
+// commandShellNames returns the names of the shells supported separated by commas
+func commandShellNames() string {
+	var names []string
+	for _, commandShell := range commandShells {
+		names = append(names, string(commandShell))
+	}
+	return strings.Join(names, ", ")
+}
+
+// resolve returns the shell the commands are executed with, replacing the default shell by the one of the OS the provider
+// runs in and the empty shell by commandShellNone
+func (s commandShell) resolve() commandShell {
+	switch s {
+	case "":
+		return commandShellNone
+	case commandShellDefault:
+		return defaultCommandShell
+	}
+	return s
+}
+
+// argv returns the executable and the arguments that execute the given command with the shell. When executed with a shell,
+// the elements of the command are joined with spaces making up the script the shell runs
+func (s commandShell) argv(command []string) []string {
+	script := strings.Join(command, " ")
+	switch s.resolve() {
+	case commandShellSh:
+		return []string{"sh", "-c", script}
+	case commandShellCmd:
+		return []string{"cmd.exe", "/d", "/s", "/c", script}
+	case commandShellPowerShell:
+		return []string{powerShellExecutable, "-NoProfile", "-NonInteractive", "-Command", script}
+	}
+	return command
+}
+
+// commandExecutor executes the commands configured in the plugin configuration file, optionally with a shell, in the
+// working directory configured (if any) and with the extra environment variables configured on top of the ones of the
+// provider process; the configured variables override the ones inherited from the provider process. The command is
+// killed if it does not finish within the timeout
+type commandExecutor struct {
+	shell      commandShell
+	workingDir string
+	env        map[string]string
+	timeout    time.Duration
+}
+
+// commandResult contains what the command printed and whether it was killed for not finishing within the timeout
+type commandResult struct {
+	stdout   string
+	stderr   string
+	timedOut bool
+}
+
+// run executes the given command passing it the given standard input (if any) and returns the result along with the error
+// returned by exec, if any (e.g. *exec.ExitError if the command exited with a non zero exit code). If the command does
+// not finish within the timeout, the command is killed along with the processes it started (e.g. the programs run by the
+// shell), which would otherwise keep the output of the command open and the execution waiting for them
+func (e commandExecutor) run(command []string, stdin io.Reader) (commandResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+
+	cmd := e.newCommand(command)
+	cmd.Stdin = stdin
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return commandResult{}, err
+	}
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			killCommand(cmd)
+		case <-done:
+		}
+	}()
+	err := cmd.Wait()
+	close(done)
+	// The error returned when the process is killed is OS specific, hence the context error is checked to know whether
+	// the command timed out
+	return commandResult{stdout: stdout.String(), stderr: stderr.String(), timedOut: ctx.Err() == context.DeadlineExceeded}, err
+}
+
+func (e commandExecutor) newCommand(command []string) *exec.Cmd {
+	argv := e.shell.argv(command)
+	cmd := exec.Command(argv[0], argv[1:]...)
+	prepareCommand(cmd, e.shell.resolve(), strings.Join(command, " "))
+	cmd.Dir = e.workingDir
+	if len(e.env) > 0 {
+		var envVarNames []string
+		for envVarName := range e.env {
+			envVarNames = append(envVarNames, envVarName)
+		}
+		sort.Strings(envVarNames)
+		cmd.Env = os.Environ()
+		for _, envVarName := range envVarNames {
+			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", envVarName, e.env[envVarName]))
+		}
+	}
+	return cmd
+}
@@ -0,0 +1,31 @@
+//go:build !windows
+// +build !windows
+
+package openapi
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// defaultCommandShell is the shell the commands configured with the default shell are executed with
+const defaultCommandShell = commandShellSh
+
+// powerShellExecutable is the executable of PowerShell Core, the PowerShell version available in Linux and macOS
+const powerShellExecutable = "pwsh"
+
+// isCommandShellAvailable returns false for cmd, which is only available in Windows
+func isCommandShellAvailable(shell commandShell) bool {
+	return shell != commandShellCmd
+}
+
+// prepareCommand starts the command in its own process group, so the processes the command starts (e.g. the programs run
+// by the shell) can be killed along with it. The arguments reach the shells as they are in Linux and macOS
+func prepareCommand(cmd *exec.Cmd, shell commandShell, script string) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killCommand kills the process group of the command
+func killCommand(cmd *exec.Cmd) {
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
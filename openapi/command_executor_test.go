@@ -0,0 +1,153 @@
+package openapi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidCommandShell(t *testing.T) {
+	testCases := []struct {
+		shell         string
+		expectedValid bool
+	}{
+		{shell: "", expectedValid: true},
+		{shell: "none", expectedValid: true},
+		{shell: "default", expectedValid: true},
+		{shell: "sh", expectedValid: true},
+		{shell: "cmd", expectedValid: true},
+		{shell: "powershell", expectedValid: true},
+		{shell: "bash", expectedValid: false},
+		{shell: "SH", expectedValid: false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expectedValid, isValidCommandShell(tc.shell), tc.shell)
+	}
+}
+
+func TestValidateCommandShell(t *testing.T) {
+	assert.NoError(t, validateCommandShell(""))
+	assert.NoError(t, validateCommandShell("sh"))
+	assert.EqualError(t, validateCommandShell("bash"), "value 'bash' is not valid, supported values: none, default, sh, cmd, powershell")
+	if runtime.GOOS == "windows" {
+		assert.NoError(t, validateCommandShell("cmd"))
+	} else {
+		assert.EqualError(t, validateCommandShell("cmd"), fmt.Sprintf("value 'cmd' is not supported in %s", runtime.GOOS))
+	}
+}
+
+func TestCommandShellArgv(t *testing.T) {
+	command := []string{"get-token", "--audience", "api"}
+	testCases := []struct {
+		name         string
+		shell        commandShell
+		expectedArgv []string
+	}{
+		{
+			name:         "no shell configured",
+			shell:        "",
+			expectedArgv: []string{"get-token", "--audience", "api"},
+		},
+		{
+			name:         "none",
+			shell:        commandShellNone,
+			expectedArgv: []string{"get-token", "--audience", "api"},
+		},
+		{
+			name:         "sh",
+			shell:        commandShellSh,
+			expectedArgv: []string{"sh", "-c", "get-token --audience api"},
+		},
+		{
+			name:         "cmd",
+			shell:        commandShellCmd,
+			expectedArgv: []string{"cmd.exe", "/d", "/s", "/c", "get-token --audience api"},
+		},
+		{
+			name:         "powershell",
+			shell:        commandShellPowerShell,
+			expectedArgv: []string{powerShellExecutable, "-NoProfile", "-NonInteractive", "-Command", "get-token --audience api"},
+		},
+		{
+			name:         "default",
+			shell:        commandShellDefault,
+			expectedArgv: defaultCommandShell.argv(command),
+		},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expectedArgv, tc.shell.argv(command), tc.name)
+	}
+}
+
+func TestCommandExecutorRun(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	t.Run("command executed directly", func(t *testing.T) {
+		result, err := commandExecutor{timeout: 5 * time.Second}.run([]string{"echo", "$HOME"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "$HOME\n", result.stdout)
+		assert.False(t, result.timedOut)
+	})
+
+	t.Run("command executed with a shell", func(t *testing.T) {
+		result, err := commandExecutor{shell: commandShellSh, timeout: 5 * time.Second}.run([]string{"echo", "token", "|", "tr", "a-z", "A-Z"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "TOKEN\n", result.stdout)
+	})
+
+	t.Run("command executed with the environment variables configured", func(t *testing.T) {
+		os.Setenv("COMMAND_EXECUTOR_TEST", "provider")
+		defer os.Unsetenv("COMMAND_EXECUTOR_TEST")
+		result, err := commandExecutor{shell: commandShellSh, env: map[string]string{"AUDIENCE": "api"}, timeout: 5 * time.Second}.run([]string{"echo", "$COMMAND_EXECUTOR_TEST-$AUDIENCE"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "provider-api\n", result.stdout)
+	})
+
+	t.Run("command executed in the working directory configured", func(t *testing.T) {
+		workingDir, err := ioutil.TempDir("", "")
+		require.NoError(t, err)
+		defer os.RemoveAll(workingDir)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(workingDir, "token"), []byte("secret"), 0600))
+		result, err := commandExecutor{workingDir: workingDir, timeout: 5 * time.Second}.run([]string{"cat", "token"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "secret", result.stdout)
+	})
+
+	t.Run("command reading the standard input", func(t *testing.T) {
+		result, err := commandExecutor{timeout: 5 * time.Second}.run([]string{"cat"}, strings.NewReader("value"))
+		require.NoError(t, err)
+		assert.Equal(t, "value", result.stdout)
+	})
+
+	t.Run("command failing", func(t *testing.T) {
+		result, err := commandExecutor{shell: commandShellSh, timeout: 5 * time.Second}.run([]string{"echo", "failure", ">&2;", "exit", "3"}, nil)
+		assert.EqualError(t, err, "exit status 3")
+		assert.Equal(t, "failure\n", result.stderr)
+		assert.False(t, result.timedOut)
+	})
+
+	t.Run("command not finishing within the timeout", func(t *testing.T) {
+		result, err := commandExecutor{timeout: 100 * time.Millisecond}.run([]string{"sleep", "2"}, nil)
+		assert.Error(t, err)
+		assert.True(t, result.timedOut)
+	})
+
+	t.Run("processes started by the shell are killed along with the command when it does not finish within the timeout", func(t *testing.T) {
+		start := time.Now()
+		result, err := commandExecutor{shell: commandShellSh, timeout: 100 * time.Millisecond}.run([]string{"sleep", "5;", "echo", "done"}, nil)
+		assert.Error(t, err)
+		assert.True(t, result.timedOut)
+		assert.True(t, time.Since(start) < 4*time.Second, "the execution should not wait for the processes started by the shell")
+	})
+}
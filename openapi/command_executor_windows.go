@@ -0,0 +1,31 @@
+package openapi
+
+import (
+	"fmt"
+	"os/exec"
+	"syscall"
+)
+
+// defaultCommandShell is the shell the commands configured with the default shell are executed with
+const defaultCommandShell = commandShellCmd
+
+// powerShellExecutable is the executable of Windows PowerShell, which is installed in all the Windows versions
+const powerShellExecutable = "powershell.exe"
+
+// isCommandShellAvailable returns true since all the shells supported are available in Windows
+func isCommandShellAvailable(shell commandShell) bool {
+	return true
+}
+
+// prepareCommand sets the command line of the commands executed with cmd, since cmd does not follow the argument quoting
+// rules of the rest of the Windows programs and the script would not reach it as written otherwise
+func prepareCommand(cmd *exec.Cmd, shell commandShell, script string) {
+	if shell == commandShellCmd {
+		cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: fmt.Sprintf(`cmd.exe /d /s /c "%s"`, script)}
+	}
+}
+
+// killCommand kills the process of the command
+func killCommand(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
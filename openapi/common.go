@@ -1,16 +1,21 @@
 package openapi
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"reflect"
+	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/dikhan/terraform-provider-openapi/openapi/openapierr"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func checkHTTPStatusCode(openAPIResource SpecResource, res *http.Response, expectedHTTPStatusCodes []int) error {
@@ -23,14 +28,23 @@ func checkHTTPStatusCode(openAPIResource SpecResource, res *http.Response, expec
 		if b != nil && len(b) > 0 {
 			resBody = string(b)
 		}
+		apiErr := &APIError{StatusCode: res.StatusCode, Body: resBody}
+		if res.Request != nil {
+			apiErr.Method = res.Request.Method
+			if res.Request.URL != nil {
+				apiErr.URL = res.Request.URL.String()
+			}
+		}
 		switch res.StatusCode {
 		case http.StatusUnauthorized:
-			return fmt.Errorf("[resource='%s'] HTTP Response Status Code %d - Unauthorized: API access is denied due to invalid credentials (%s)", openAPIResource.getResourceName(), res.StatusCode, resBody)
+			apiErr.Err = messages.error(MessageUnauthorized, messageArgs{"resource": openAPIResource.getResourceName(), "status_code": res.StatusCode, "body": resBody})
 		case http.StatusNotFound:
-			return &openapierr.NotFoundError{OriginalError: fmt.Errorf("HTTP Response Status Code %d - Not Found. Could not find resource instance: %s", res.StatusCode, resBody)}
+			apiErr.Err = messages.error(MessageNotFound, messageArgs{"status_code": res.StatusCode, "body": resBody})
+			return &openapierr.NotFoundError{OriginalError: apiErr}
 		default:
-			return fmt.Errorf("[resource='%s'] HTTP Response Status Code %d not matching expected one %v (%s)", openAPIResource.getResourceName(), res.StatusCode, expectedHTTPStatusCodes, resBody)
+			apiErr.Err = messages.error(MessageUnexpectedStatusCode, messageArgs{"resource": openAPIResource.getResourceName(), "status_code": res.StatusCode, "expected_status_codes": expectedHTTPStatusCodes, "body": resBody})
 		}
+		return apiErr
 	}
 	return nil
 }
@@ -61,6 +75,16 @@ func getParentIDs(openAPIResource SpecResource, data *schema.ResourceData) ([]st
 		return []string{}, errors.New("can't get parent ids from a resourceFactory with no openAPIResource")
 	}
 
+	compositeIDValues, err := getCompositeIDValues(openAPIResource, data)
+	if err != nil {
+		return nil, err
+	}
+	if compositeIDValues != nil {
+		// The root path of resources identified by a composite id (e.g. /v1/ns/{namespace}/things) is resolved with all
+		// the values but the last one, which identifies the instance
+		return compositeIDValues[:len(compositeIDValues)-1], nil
+	}
+
 	parentResourceInfo := openAPIResource.getParentResourceInfo()
 	if parentResourceInfo != nil {
 		parentResourceNames := parentResourceInfo.getParentPropertiesNames()
@@ -79,6 +103,66 @@ func getParentIDs(openAPIResource SpecResource, data *schema.ResourceData) ([]st
 	return []string{}, nil
 }
 
+// getResourceInstanceID returns the id used to build the resource instance path, that is the state id or the value of
+// the last property of the composite id if the resource is configured with the x-terraform-composite-id extension
+func getResourceInstanceID(openAPIResource SpecResource, data *schema.ResourceData) (string, error) {
+	compositeIDValues, err := getCompositeIDValues(openAPIResource, data)
+	if err != nil {
+		return "", err
+	}
+	if compositeIDValues != nil {
+		return compositeIDValues[len(compositeIDValues)-1], nil
+	}
+	return data.Id(), nil
+}
+
+// getCompositeIDValues returns the values of the properties that compose the id of the resources configured with the
+// x-terraform-composite-id extension, in the same order as they are listed in the extension. The values are taken from
+// the resource id (e.g. ns1/thing1) if already set (e.g. when the resource is being imported); otherwise they are read from
+// the local data (e.g. when the resource is being created). Nil is returned if the resource is not identified by a
+// composite id.
+func getCompositeIDValues(openAPIResource SpecResource, data *schema.ResourceData) ([]string, error) {
+	compositeID := openAPIResource.getCompositeID()
+	if len(compositeID) == 0 || data == nil {
+		return nil, nil
+	}
+	if data.Id() != "" {
+		return splitCompositeID(compositeID, data.Id())
+	}
+	resourceSchema, err := openAPIResource.getResourceSchema()
+	if err != nil {
+		return nil, err
+	}
+	compositeIDValues := []string{}
+	for _, propertyName := range compositeID {
+		property, err := resourceSchema.getProperty(propertyName)
+		if err != nil {
+			return nil, err
+		}
+		value, exists := data.GetOk(property.getTerraformCompliantPropertyName())
+		if !exists {
+			return nil, fmt.Errorf("could not find the value in the local data for the composite id property '%s'", propertyName)
+		}
+		compositeIDValues = append(compositeIDValues, identifierValueToString(value))
+	}
+	return compositeIDValues, nil
+}
+
+// splitCompositeID returns the values contained in the given composite id, which is expected to contain the values of the
+// composite id properties separated by '/' (e.g. ns1/thing1 for a composite id made of the namespace and the name)
+func splitCompositeID(compositeID []string, id string) ([]string, error) {
+	idValues := strings.Split(id, "/")
+	if len(idValues) != len(compositeID) {
+		return nil, fmt.Errorf("id '%s' does not match the expected composite id format '%s'", id, strings.Join(compositeID, "/"))
+	}
+	for idx, idValue := range idValues {
+		if idValue == "" {
+			return nil, fmt.Errorf("id '%s' is missing the value for the composite id property '%s'", id, compositeID[idx])
+		}
+	}
+	return idValues, nil
+}
+
 // updateStateWithPayloadData is in charge of saving the given payload into the state file. The property names are
 // converted into compliant terraform names if needed.
 func updateStateWithPayloadData(openAPIResource SpecResource, remoteData map[string]interface{}, resourceLocalData *schema.ResourceData) error {
@@ -105,6 +189,20 @@ func updateStateWithPayloadData(openAPIResource SpecResource, remoteData map[str
 			}
 		}
 	}
+	// Derived properties are not returned by the API, their values are computed out of the payload properties. The
+	// value is cleared if any of the properties the value is derived from is missing so it never gets out of sync
+	for _, property := range resourceSchema.Properties {
+		if !property.isDerivedProperty() {
+			continue
+		}
+		value, ok := property.derivedValue(remoteData)
+		if !ok {
+			log.Printf("[DEBUG] derived property '%s' value cleared since some of the properties referred in the template '%s' are missing in the payload", property.Name, property.DerivedTemplate)
+		}
+		if err := setResourceDataProperty(openAPIResource, property.Name, value, resourceLocalData); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -112,6 +210,9 @@ func convertPayloadToLocalStateDataValue(property *specSchemaDefinitionProperty,
 	if propertyValue == nil {
 		return nil, nil
 	}
+	if number, isNumber := propertyValue.(json.Number); isNumber {
+		return convertPayloadNumberToLocalStateDataValue(property, number, useString)
+	}
 	dataValueKind := reflect.TypeOf(propertyValue).Kind()
 	switch dataValueKind {
 	case reflect.Map:
@@ -147,7 +248,23 @@ func convertPayloadToLocalStateDataValue(property *specSchemaDefinitionProperty,
 		return objectInput, nil
 	case reflect.Slice, reflect.Array:
 		if isListOfPrimitives, _ := property.isTerraformListOfSimpleValues(); isListOfPrimitives {
-			return propertyValue, nil
+			arrayValue, ok := propertyValue.([]interface{})
+			if !ok {
+				return propertyValue, nil
+			}
+			itemProperty := &specSchemaDefinitionProperty{Name: property.Name, Type: property.ArrayItemsType}
+			arrayInput := []interface{}{}
+			for _, arrayItem := range arrayValue {
+				if number, isNumber := arrayItem.(json.Number); isNumber {
+					itemValue, err := convertPayloadNumberToLocalStateDataValue(itemProperty, number, false)
+					if err != nil {
+						return nil, err
+					}
+					arrayItem = itemValue
+				}
+				arrayInput = append(arrayInput, arrayItem)
+			}
+			return arrayInput, nil
 		}
 		if property.isArrayOfObjectsProperty() {
 			arrayInput := []interface{}{}
@@ -201,6 +318,36 @@ func convertPayloadToLocalStateDataValue(property *specSchemaDefinitionProperty,
 	}
 }
 
+// convertPayloadNumberToLocalStateDataValue converts the numbers decoded from the API responses (see decodeJSONPayload)
+// into the type expected by the property. Integers are parsed from the number literal so they are not rounded, and
+// string properties (e.g. numeric IDs exposed as strings) keep the literal as is
+func convertPayloadNumberToLocalStateDataValue(property *specSchemaDefinitionProperty, number json.Number, useString bool) (interface{}, error) {
+	switch property.Type {
+	case typeInt:
+		value, err := strconv.ParseInt(number.String(), 10, 0)
+		if err != nil {
+			// the API may still return integers with a decimal point or exponent (e.g. 1.0 or 1e3)
+			floatValue, floatErr := number.Float64()
+			if floatErr != nil {
+				return nil, fmt.Errorf("property '%s' value '%s' is not a valid integer: %s", property.Name, number, err)
+			}
+			value = int64(floatValue)
+		}
+		if useString {
+			return strconv.FormatInt(value, 10), nil
+		}
+		return int(value), nil
+	case typeFloat:
+		value, err := number.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("property '%s' value '%s' is not a valid number: %s", property.Name, number, err)
+		}
+		return convertPayloadToLocalStateDataValue(property, value, useString)
+	default:
+		return number.String(), nil
+	}
+}
+
 // setResourceDataProperty sets the expectedValue for the given schemaDefinitionPropertyName using the terraform compliant property name
 func setResourceDataProperty(openAPIResource SpecResource, schemaDefinitionPropertyName string, value interface{}, resourceLocalData *schema.ResourceData) error {
 	resourceSchema, _ := openAPIResource.getResourceSchema()
@@ -208,12 +355,25 @@ func setResourceDataProperty(openAPIResource SpecResource, schemaDefinitionPrope
 	if err != nil {
 		return fmt.Errorf("could not find schema definition property name %s in the resource data: %s", schemaDefinitionPropertyName, err)
 	}
-	return resourceLocalData.Set(schemaDefinitionProperty.getTerraformCompliantPropertyName(), value)
+	if err := resourceLocalData.Set(schemaDefinitionProperty.getTerraformCompliantPropertyName(), value); err != nil {
+		return err
+	}
+	for _, alias := range schemaDefinitionProperty.Aliases {
+		if err := resourceLocalData.Set(alias, value); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // setStateID sets the local resource's data ID with the newly identifier created in the POST API request. Refer to
-// r.resourceInfo.getResourceIdentifier() for more info regarding what property is selected as the identifier.
+// r.resourceInfo.getResourceIdentifier() for more info regarding what property is selected as the identifier. Resources
+// configured with the x-terraform-composite-id extension get an id made of the values of the composite id properties
+// joined by '/' (e.g. ns1/thing1).
 func setStateID(openAPIres SpecResource, resourceLocalData *schema.ResourceData, payload map[string]interface{}) error {
+	if compositeID := openAPIres.getCompositeID(); len(compositeID) > 0 {
+		return setCompositeStateID(openAPIres, compositeID, resourceLocalData, payload)
+	}
 	resourceSchema, err := openAPIres.getResourceSchema()
 	if err != nil {
 		return err
@@ -225,14 +385,228 @@ func setStateID(openAPIres SpecResource, resourceLocalData *schema.ResourceData,
 	if payload[identifierProperty] == nil {
 		return fmt.Errorf("response object returned from the API is missing mandatory identifier property '%s'", identifierProperty)
 	}
+	resourceLocalData.SetId(identifierValueToString(payload[identifierProperty]))
+	return nil
+}
+
+// setCompositeStateID sets the local resource's data ID with the values of the composite id properties. The values are
+// read from the payload, falling back to the ones in the local data for the properties the API did not return.
+func setCompositeStateID(openAPIres SpecResource, compositeID []string, resourceLocalData *schema.ResourceData, payload map[string]interface{}) error {
+	resourceSchema, err := openAPIres.getResourceSchema()
+	if err != nil {
+		return err
+	}
+	idValues := []string{}
+	for _, propertyName := range compositeID {
+		value := payload[propertyName]
+		if value == nil {
+			property, err := resourceSchema.getProperty(propertyName)
+			if err != nil {
+				return err
+			}
+			value, _ = resourceLocalData.GetOk(property.getTerraformCompliantPropertyName())
+		}
+		if value == nil || identifierValueToString(value) == "" {
+			return fmt.Errorf("could not find the value for the composite id property '%s' neither in the response object returned from the API nor in the local data", propertyName)
+		}
+		idValues = append(idValues, identifierValueToString(value))
+	}
+	resourceLocalData.SetId(strings.Join(idValues, "/"))
+	return nil
+}
 
-	switch payload[identifierProperty].(type) {
+// identifierValueToString returns the string representation of the given identifier value, making sure numbers are not
+// formatted in scientific notation
+func identifierValueToString(value interface{}) string {
+	switch v := value.(type) {
 	case int:
-		resourceLocalData.SetId(strconv.Itoa(payload[identifierProperty].(int)))
+		return strconv.Itoa(v)
 	case float64:
-		resourceLocalData.SetId(strconv.Itoa(int(payload[identifierProperty].(float64))))
+		return strconv.Itoa(int(v))
+	case json.Number:
+		return v.String()
+	case string:
+		return v
 	default:
-		resourceLocalData.SetId(payload[identifierProperty].(string))
+		return fmt.Sprintf("%v", v)
 	}
-	return nil
+}
+
+// decodeJSONPayload decodes the given JSON payload into the target (usually a pointer to the payload map or list) keeping
+// the numbers as json.Number rather than float64, so large integers such as IDs (e.g. 9223372036854775807) round-trip
+// exactly instead of being rounded and stored in scientific notation. Empty payloads are ignored.
+func decodeJSONPayload(payload []byte, target interface{}) error {
+	if len(payload) == 0 || target == nil {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	return decoder.Decode(&target)
+}
+
+// payloadPathStep is a step of a payload path: either the name of an object property or the index of an array item
+type payloadPathStep struct {
+	property string
+	index    int
+	isIndex  bool
+}
+
+// parsePayloadPath returns the steps of the given payload path. The path follows the JSONPath syntax, optionally starting
+// with '$', supporting the dot notation (e.g. $.metadata.state.phase), the bracket notation for properties containing
+// dots or other special characters (e.g. $.metadata['state.phase'] or $["metadata"]["state"]) and array indexes
+// (e.g. $.operations[0].state). For backwards compatibility, the leading '$.' can be omitted (e.g. metadata.state.phase)
+func parsePayloadPath(path string) ([]payloadPathStep, error) {
+	invalidPathErr := fmt.Errorf("path '%s' is not valid", path)
+	remaining := strings.TrimPrefix(path, "$")
+	if remaining != path && remaining != "" && remaining[0] != '.' && remaining[0] != '[' {
+		return nil, invalidPathErr
+	}
+	if remaining != "" && remaining[0] != '.' && remaining[0] != '[' {
+		remaining = "." + remaining
+	}
+	var steps []payloadPathStep
+	for remaining != "" {
+		switch remaining[0] {
+		case '.':
+			end := strings.IndexAny(remaining[1:], ".[]")
+			if end == -1 {
+				end = len(remaining) - 1
+			}
+			property := remaining[1 : end+1]
+			if property == "" {
+				return nil, invalidPathErr
+			}
+			steps = append(steps, payloadPathStep{property: property})
+			remaining = remaining[end+1:]
+		case '[':
+			end := strings.Index(remaining, "]")
+			if end == -1 {
+				return nil, invalidPathErr
+			}
+			if quote := remaining[1]; quote == '\'' || quote == '"' {
+				end = strings.Index(remaining[2:], string(quote)+"]")
+				if end == -1 || end == 0 {
+					return nil, invalidPathErr
+				}
+				steps = append(steps, payloadPathStep{property: remaining[2 : end+2]})
+				remaining = remaining[end+4:]
+				continue
+			}
+			index, err := strconv.Atoi(remaining[1:end])
+			if err != nil || index < 0 || strings.HasPrefix(remaining[1:end], "+") {
+				return nil, invalidPathErr
+			}
+			steps = append(steps, payloadPathStep{index: index, isIndex: true})
+			remaining = remaining[end+1:]
+		default:
+			return nil, invalidPathErr
+		}
+	}
+	if len(steps) == 0 {
+		return nil, invalidPathErr
+	}
+	return steps, nil
+}
+
+// validatePayloadPathExtension is the Validate hook of the extensions containing payload paths
+func validatePayloadPathExtension(value interface{}) error {
+	_, err := parsePayloadPath(value.(string))
+	return err
+}
+
+// getPayloadValue returns the value found in the given payload following the path provided (see parsePayloadPath for
+// the syntax supported, e.g. $.operations[0].state)
+func getPayloadValue(path string, payload map[string]interface{}) (interface{}, error) {
+	steps, err := parsePayloadPath(path)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{} = payload
+	for _, step := range steps {
+		if step.isIndex {
+			list, ok := value.([]interface{})
+			if !ok || step.index >= len(list) {
+				return nil, fmt.Errorf("path '%s' not found in payload", path)
+			}
+			value = list[step.index]
+			continue
+		}
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("path '%s' not found in payload", path)
+		}
+		if value, ok = object[step.property]; !ok {
+			return nil, fmt.Errorf("path '%s' not found in payload", path)
+		}
+	}
+	return value, nil
+}
+
+// checkWriteAllowed returns an error if the provider is configured in read-only mode, in which case the given operation
+// (e.g. create) can not be performed on the resource as it would mutate the remote resource
+func checkWriteAllowed(i interface{}, resourceName, operation string) error {
+	providerClient, ok := i.(*ProviderClient)
+	if !ok || !providerClient.providerConfiguration.ReadOnly {
+		return nil
+	}
+	return messages.error(MessageReadOnlyMode, messageArgs{"resource": resourceName, "operation": operation, "provider_property": providerPropertyReadOnly})
+}
+
+// isFullRefreshEnabled returns true if the provider is configured to refresh all the properties, including the ones marked
+// to be refreshed on demand only
+func isFullRefreshEnabled(i interface{}) bool {
+	providerClient, ok := i.(*ProviderClient)
+	return ok && providerClient.providerConfiguration.FullRefresh
+}
+
+var duplicateSlashesRegex = regexp.MustCompile(`/{2,}`)
+
+// headerNameRegex matches the valid HTTP header names (tokens as defined in RFC 7230)
+var headerNameRegex = regexp.MustCompile("^[!#$%&'*+\\-.^_`|~0-9A-Za-z]+$")
+
+// isValidHeaderName returns true if the given name can be used as an HTTP header name
+func isValidHeaderName(name string) bool {
+	return headerNameRegex.MatchString(name)
+}
+
+// normalizeURLPath collapses the duplicate forward slashes found in the given path (e.g. '/v1//cdns' results into '/v1/cdns')
+func normalizeURLPath(path string) string {
+	return duplicateSlashesRegex.ReplaceAllString(path, "/")
+}
+
+// joinURLPath joins the given path elements making sure there is exactly one forward slash between them and that the
+// result starts with a forward slash (e.g. '/api/' and '/v1/cdns' results into '/api/v1/cdns'). Empty elements are
+// skipped and the trailing slash of the last element, if any, is kept as is.
+func joinURLPath(elements ...string) string {
+	joined := ""
+	for _, element := range elements {
+		if element == "" {
+			continue
+		}
+		joined = strings.TrimRight(joined, "/") + "/" + strings.TrimLeft(element, "/")
+	}
+	if joined == "" {
+		return "/"
+	}
+	return joined
+}
+
+// diagnosticsError returns the error diagnostics (e.g. the ones returned when configuring the provider) as an error, with
+// their summaries (and details, if any) separated by semicolons; nil if there are none
+func diagnosticsError(diags diag.Diagnostics) error {
+	var errs []string
+	for _, d := range diags {
+		if d.Severity != diag.Error {
+			continue
+		}
+		if d.Detail != "" {
+			errs = append(errs, fmt.Sprintf("%s: %s", d.Summary, d.Detail))
+			continue
+		}
+		errs = append(errs, d.Summary)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(errs, "; "))
 }
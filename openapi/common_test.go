@@ -1,6 +1,7 @@
 package openapi
 
 import (
+	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"net/http"
@@ -8,10 +9,12 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/dikhan/terraform-provider-openapi/openapi/openapierr"
 	"github.com/go-openapi/spec"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCheckHTTPStatusCode(t *testing.T) {
@@ -61,6 +64,28 @@ func TestCheckHTTPStatusCode(t *testing.T) {
 	}
 }
 
+func TestCheckHTTPStatusCode_APIError(t *testing.T) {
+	openAPIResource := &specStubResource{name: "resourceName"}
+	req, _ := http.NewRequest(http.MethodPost, "https://api.com/v1/resource", nil)
+
+	err := checkHTTPStatusCode(openAPIResource, &http.Response{Body: ioutil.NopCloser(strings.NewReader("some backend error")), StatusCode: http.StatusBadRequest, Request: req}, []int{http.StatusCreated})
+	apiErr, ok := err.(*APIError)
+	assert.True(t, ok, "unexpected responses should be returned as APIError")
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, http.MethodPost, apiErr.Method)
+	assert.Equal(t, "https://api.com/v1/resource", apiErr.URL)
+	assert.Equal(t, "some backend error", apiErr.Body)
+
+	err = checkHTTPStatusCode(openAPIResource, &http.Response{Body: ioutil.NopCloser(strings.NewReader("")), StatusCode: http.StatusNotFound, Request: req}, []int{http.StatusOK})
+	notFoundErr, ok := err.(*openapierr.NotFoundError)
+	assert.True(t, ok, "not found responses should be returned as NotFoundError")
+	assert.IsType(t, &APIError{}, notFoundErr.OriginalError)
+	assert.Equal(t, http.StatusNotFound, notFoundErr.OriginalError.(*APIError).StatusCode)
+	var notFoundAPIErr *APIError
+	assert.True(t, errors.As(err, &notFoundAPIErr), "not found errors should unwrap to the APIError")
+	assert.Equal(t, http.StatusNotFound, notFoundAPIErr.StatusCode)
+}
+
 func TestResponseContainsExpectedStatus(t *testing.T) {
 	testCases := []struct {
 		name                        string
@@ -354,6 +379,35 @@ func TestUpdateStateWithPayloadData(t *testing.T) {
 	})
 }
 
+func TestUpdateStateWithPayloadData_DerivedProperties(t *testing.T) {
+	Convey("Given a resource factory containing a derived property", t, func() {
+		hostProperty := newStringSchemaDefinitionPropertyWithDefaults("host", "", false, false, nil)
+		portProperty := newIntSchemaDefinitionPropertyWithDefaults("port", "", false, false, nil)
+		endpointProperty := newStringSchemaDefinitionPropertyWithDefaults("endpoint", "", false, true, nil)
+		endpointProperty.DerivedTemplate = "https://{host}:{port}"
+		r, resourceData := testCreateResourceFactory(t, hostProperty, portProperty, endpointProperty)
+		Convey("When updateStateWithPayloadData is called with a payload containing the properties referred in the template", func() {
+			err := updateStateWithPayloadData(r.openAPIResource, map[string]interface{}{"host": "www.api.com", "port": float64(8443)}, resourceData)
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the derived property should be computed out of the payload values", func() {
+				So(resourceData.Get("endpoint"), ShouldEqual, "https://www.api.com:8443")
+			})
+		})
+		Convey("When updateStateWithPayloadData is called with a payload missing some of the properties referred in the template", func() {
+			resourceData.Set("endpoint", "https://www.api.com:8443")
+			err := updateStateWithPayloadData(r.openAPIResource, map[string]interface{}{"host": "www.api.com"}, resourceData)
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the derived property should be cleared", func() {
+				So(resourceData.Get("endpoint"), ShouldEqual, "")
+			})
+		})
+	})
+}
+
 func TestConvertPayloadToLocalStateDataValue(t *testing.T) {
 
 	Convey("Given a resource factory", t, func() {
@@ -688,6 +742,41 @@ func TestConvertPayloadToLocalStateDataValue(t *testing.T) {
 	})
 }
 
+func TestConvertPayloadToLocalStateDataValue_JSONNumber(t *testing.T) {
+	testCases := []struct {
+		name          string
+		property      *specSchemaDefinitionProperty
+		number        json.Number
+		useString     bool
+		expectedValue interface{}
+		expectedError string
+	}{
+		{name: "int property with a large integer", property: newIntSchemaDefinitionPropertyWithDefaults("int_property", "", false, false, nil), number: "9223372036854775807", expectedValue: 9223372036854775807},
+		{name: "int property with a large integer and string output", property: newIntSchemaDefinitionPropertyWithDefaults("int_property", "", false, false, nil), number: "9223372036854775807", useString: true, expectedValue: "9223372036854775807"},
+		{name: "int property with a decimal point", property: newIntSchemaDefinitionPropertyWithDefaults("int_property", "", false, false, nil), number: "10.0", expectedValue: 10},
+		{name: "float property", property: newNumberSchemaDefinitionPropertyWithDefaults("float_property", "", false, false, nil), number: "10.45", expectedValue: 10.45},
+		{name: "float property with string output", property: newNumberSchemaDefinitionPropertyWithDefaults("float_property", "", false, false, nil), number: "10.45", useString: true, expectedValue: "10.45"},
+		{name: "string property keeps the number literal", property: newStringSchemaDefinitionPropertyWithDefaults("string_property", "", false, false, nil), number: "9223372036854775807", expectedValue: "9223372036854775807"},
+		{name: "int property with a value that is not a number", property: newIntSchemaDefinitionPropertyWithDefaults("int_property", "", false, false, nil), number: "not a number", expectedError: "property 'int_property' value 'not a number' is not a valid integer: strconv.ParseInt: parsing \"not a number\": invalid syntax"},
+	}
+	for _, tc := range testCases {
+		value, err := convertPayloadToLocalStateDataValue(tc.property, tc.number, tc.useString)
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError, tc.name)
+			continue
+		}
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expectedValue, value, tc.name)
+	}
+}
+
+func TestConvertPayloadToLocalStateDataValue_ListOfJSONNumbers(t *testing.T) {
+	property := newListSchemaDefinitionPropertyWithDefaults("int_list_property", "", false, false, false, nil, typeInt, nil)
+	value, err := convertPayloadToLocalStateDataValue(property, []interface{}{json.Number("1"), json.Number("9223372036854775807")}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{1, 9223372036854775807}, value)
+}
+
 func TestSetResourceDataProperty(t *testing.T) {
 	Convey("Given a resource factory initialized with a spec resource with some schema definition", t, func() {
 		r, resourceData := testCreateResourceFactory(t, stringProperty, stringWithPreferredNameProperty)
@@ -724,6 +813,26 @@ func TestSetResourceDataProperty(t *testing.T) {
 			})
 		})
 	})
+
+	Convey("Given a spec resource with a property with aliases and resource data", t, func() {
+		nameProperty := newStringSchemaDefinitionPropertyWithDefaults("name", "", true, false, nil)
+		nameProperty.Aliases = []string{"label"}
+		schemaDefinition := &specSchemaDefinition{Properties: specSchemaDefinitionProperties{nameProperty}}
+		resourceSchema, err := schemaDefinition.createResourceSchema()
+		So(err, ShouldBeNil)
+		resourceData := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{})
+		specResource := newSpecStubResource("resourceName", "/v1/resource", false, schemaDefinition)
+		Convey("When setResourceDataProperty is called with the schema definition property name", func() {
+			err := setResourceDataProperty(specResource, nameProperty.Name, "newValue", resourceData)
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the value should be set for both the property and its aliases", func() {
+				So(resourceData.Get("name"), ShouldEqual, "newValue")
+				So(resourceData.Get("label"), ShouldEqual, "newValue")
+			})
+		})
+	})
 }
 
 func TestSetStateID(t *testing.T) {
@@ -756,6 +865,22 @@ func TestSetStateID(t *testing.T) {
 		})
 	})
 
+	Convey("Given a resource factory configured with a schema definition that as an id property and a response payload with a large numeric id", t, func() {
+		r, resourceData := testCreateResourceFactory(t, idProperty)
+		Convey("When setStateID is called with the resourceData and the responsePayload decoded keeping the numbers as json.Number", func() {
+			responsePayload := map[string]interface{}{}
+			err := decodeJSONPayload([]byte(`{"id": 9223372036854775807}`), &responsePayload)
+			So(err, ShouldBeNil)
+			err = setStateID(r.openAPIResource, resourceData, responsePayload)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And resourceData ID should be the exact number returned by the API", func() {
+				So(resourceData.Id(), ShouldEqual, "9223372036854775807")
+			})
+		})
+	})
+
 	Convey("Given a resource factory configured with a schema definition that DOES not have an id property but one of the properties is tagged as id", t, func() {
 		r, resourceData := testCreateResourceFactory(t, someIdentifierProperty)
 		Convey("When setStateID is called with the resourceData and responsePayload", func() {
@@ -788,3 +913,159 @@ func TestSetStateID(t *testing.T) {
 		})
 	})
 }
+
+func TestGetPayloadValue(t *testing.T) {
+	payload := map[string]interface{}{
+		"status": "pending",
+		"operation": map[string]interface{}{
+			"state": "running",
+		},
+		"operations": []interface{}{
+			map[string]interface{}{"state": "done"},
+			map[string]interface{}{"state": "running", "steps": []interface{}{[]interface{}{"step1"}}},
+		},
+		"metadata": map[string]interface{}{
+			"state":        map[string]interface{}{"phase": "provisioning"},
+			"k8s.io/phase": "ready",
+		},
+	}
+	testCases := []struct {
+		name          string
+		path          string
+		expectedValue interface{}
+		expectedError string
+	}{
+		{name: "top level property", path: "status", expectedValue: "pending"},
+		{name: "nested object property", path: "operation.state", expectedValue: "running"},
+		{name: "nested object property with the root prefix", path: "$.operation.state", expectedValue: "running"},
+		{name: "array item property", path: "$.operations[1].state", expectedValue: "running"},
+		{name: "nested array item", path: "operations[1].steps[0][0]", expectedValue: "step1"},
+		{name: "deeply nested object property", path: "$.metadata.state.phase", expectedValue: "provisioning"},
+		{name: "bracket notation with single quotes", path: "$['metadata']['state']['phase']", expectedValue: "provisioning"},
+		{name: "bracket notation with double quotes", path: `$["operations"][0]["state"]`, expectedValue: "done"},
+		{name: "bracket notation for properties with special characters", path: "$.metadata['k8s.io/phase']", expectedValue: "ready"},
+		{name: "array index at the root", path: "$[0]", expectedError: "path '$[0]' not found in payload"},
+		{name: "property not found", path: "operation.status", expectedError: "path 'operation.status' not found in payload"},
+		{name: "array index out of range", path: "operations[2].state", expectedError: "path 'operations[2].state' not found in payload"},
+		{name: "property is not an array", path: "operation[0]", expectedError: "path 'operation[0]' not found in payload"},
+		{name: "property is not an object", path: "status.state", expectedError: "path 'status.state' not found in payload"},
+		{name: "invalid path", path: "operations[a].state", expectedError: "path 'operations[a].state' is not valid"},
+		{name: "empty segment", path: "operation..state", expectedError: "path 'operation..state' is not valid"},
+		{name: "empty path", path: "", expectedError: "path '' is not valid"},
+		{name: "root only", path: "$", expectedError: "path '$' is not valid"},
+		{name: "root not followed by a dot or a bracket", path: "$status", expectedError: "path '$status' is not valid"},
+		{name: "wildcards are not supported", path: "$.operations[*].state", expectedError: "path '$.operations[*].state' is not valid"},
+		{name: "negative array index", path: "operations[-1].state", expectedError: "path 'operations[-1].state' is not valid"},
+		{name: "unterminated bracket", path: "$['metadata'", expectedError: "path '$['metadata'' is not valid"},
+		{name: "empty property in brackets", path: "$['']", expectedError: "path '$['']' is not valid"},
+	}
+	for _, tc := range testCases {
+		value, err := getPayloadValue(tc.path, payload)
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError, tc.name)
+			continue
+		}
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expectedValue, value, tc.name)
+	}
+}
+
+func TestDecodeJSONPayload(t *testing.T) {
+	payload := map[string]interface{}{}
+	err := decodeJSONPayload([]byte(`{"id": 9223372036854775807, "items": [1.5]}`), &payload)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"id": json.Number("9223372036854775807"), "items": []interface{}{json.Number("1.5")}}, payload)
+
+	listPayload := []map[string]interface{}{}
+	err = decodeJSONPayload([]byte(`[{"id": 1}]`), &listPayload)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"id": json.Number("1")}}, listPayload)
+
+	assert.NoError(t, decodeJSONPayload(nil, &payload), "empty payloads should be ignored")
+	assert.NoError(t, decodeJSONPayload([]byte(`{}`), nil), "nil targets should be ignored")
+	assert.Error(t, decodeJSONPayload([]byte(`{`), &payload))
+}
+
+func TestCheckWriteAllowed(t *testing.T) {
+	assert.NoError(t, checkWriteAllowed(&clientOpenAPIStub{}, "resourceName", "create"))
+	assert.NoError(t, checkWriteAllowed(&ProviderClient{}, "resourceName", "create"))
+	err := checkWriteAllowed(&ProviderClient{providerConfiguration: providerConfiguration{ReadOnly: true}}, "resourceName", "create")
+	assert.EqualError(t, err, "[resource='resourceName'] create is not allowed as the provider is configured in read-only mode; set the provider property 'read_only' to false to allow changes")
+}
+
+func TestNormalizeURLPath(t *testing.T) {
+	assert.Equal(t, "/v1/cdns", normalizeURLPath("/v1/cdns"))
+	assert.Equal(t, "/v1/cdns/", normalizeURLPath("//v1///cdns//"))
+	assert.Equal(t, "", normalizeURLPath(""))
+}
+
+func TestJoinURLPath(t *testing.T) {
+	testCases := []struct {
+		name         string
+		elements     []string
+		expectedPath string
+	}{
+		{name: "no elements", elements: []string{}, expectedPath: "/"},
+		{name: "empty elements", elements: []string{"", ""}, expectedPath: "/"},
+		{name: "elements with leading slashes", elements: []string{"/api", "/v1/cdns"}, expectedPath: "/api/v1/cdns"},
+		{name: "elements without leading slashes", elements: []string{"api", "v1/cdns"}, expectedPath: "/api/v1/cdns"},
+		{name: "element with trailing slash", elements: []string{"/api/", "/v1/cdns"}, expectedPath: "/api/v1/cdns"},
+		{name: "several slashes between elements", elements: []string{"/api//", "//v1/cdns"}, expectedPath: "/api/v1/cdns"},
+		{name: "root element", elements: []string{"/", "/v1/cdns"}, expectedPath: "/v1/cdns"},
+		{name: "last element trailing slash is kept", elements: []string{"/api", "/v1/cdns/"}, expectedPath: "/api/v1/cdns/"},
+		{name: "empty element in the middle", elements: []string{"/api", "", "/v1/cdns"}, expectedPath: "/api/v1/cdns"},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expectedPath, joinURLPath(tc.elements...), tc.name)
+	}
+}
+
+func TestSetStateID_CompositeID(t *testing.T) {
+	namespaceProperty := newStringSchemaDefinitionPropertyWithDefaults("namespace", "", true, false, "ns1")
+	countProperty := newIntSchemaDefinitionPropertyWithDefaults("count", "", true, false, 7)
+	testSchema := newTestSchema(namespaceProperty, countProperty)
+	specResource := newSpecStubResource("things_v1", "/v1/ns/{namespace}/things", false, testSchema.getSchemaDefinition())
+	specResource.compositeID = []string{"namespace", "count"}
+
+	data := testSchema.getResourceData(t)
+	err := setStateID(specResource, data, map[string]interface{}{"namespace": "ns2", "count": float64(3)})
+	require.NoError(t, err)
+	assert.Equal(t, "ns2/3", data.Id())
+
+	data = testSchema.getResourceData(t)
+	err = setStateID(specResource, data, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "ns1/7", data.Id(), "the values missing in the response should be taken from the local data")
+}
+
+func TestGetCompositeIDValues(t *testing.T) {
+	namespaceProperty := newStringSchemaDefinitionPropertyWithDefaults("namespace", "", true, false, "ns1")
+	nameProperty := newStringSchemaDefinitionPropertyWithDefaults("name", "", true, false, "thing1")
+	testSchema := newTestSchema(namespaceProperty, nameProperty)
+	specResource := newSpecStubResource("things_v1", "/v1/ns/{namespace}/things", false, testSchema.getSchemaDefinition())
+	specResource.compositeID = []string{"namespace", "name"}
+
+	data := testSchema.getResourceData(t)
+	parentIDs, err := getParentIDs(specResource, data)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ns1"}, parentIDs)
+	instanceID, err := getResourceInstanceID(specResource, data)
+	require.NoError(t, err)
+	assert.Equal(t, "thing1", instanceID)
+
+	data.SetId("ns2/thing2")
+	parentIDs, err = getParentIDs(specResource, data)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ns2"}, parentIDs, "the values in the id should take precedence over the local data")
+	instanceID, err = getResourceInstanceID(specResource, data)
+	require.NoError(t, err)
+	assert.Equal(t, "thing2", instanceID)
+
+	data.SetId("ns2/")
+	_, err = getParentIDs(specResource, data)
+	assert.EqualError(t, err, "id 'ns2/' is missing the value for the composite id property 'name'")
+
+	data = newTestSchema(namespaceProperty, newStringSchemaDefinitionPropertyWithDefaults("name", "", false, false, nil)).getResourceData(t)
+	_, err = getResourceInstanceID(specResource, data)
+	assert.EqualError(t, err, "could not find the value in the local data for the composite id property 'name'")
+}
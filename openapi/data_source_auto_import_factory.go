@@ -0,0 +1,238 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const dataSourceAutoImportIDsProperty = "ids"
+const dataSourceAutoImportImportIDsProperty = "import_ids"
+const dataSourceAutoImportInstancesProperty = "instances"
+const dataSourceAutoImportInstanceIDProperty = "id"
+const dataSourceAutoImportInstanceImportIDProperty = "import_id"
+
+// dataSourceAutoImportFactory builds the data source registered for the resources with the x-terraform-resource-auto-import
+// extension. The data source lists the existing resource instances (via the collection GET operation) in a shape ready
+// to be used in import blocks and for_each expressions, so whole collections of existing objects can be adopted:
+// - ids: the ids to import the instances with (e.g. parent_id/id for sub-resources), in the order returned by the API
+// - import_ids: the same ids keyed by the instance id, to be used as the for_each of import blocks
+// - instances: the instance id, the import id and the key attributes of each instance, that is the required properties
+// of the resource with primitive values, so the resource configuration can be populated from them
+type dataSourceAutoImportFactory struct {
+	openAPIResource SpecResource
+}
+
+func newDataSourceAutoImportFactory(openAPIResource SpecResource) dataSourceAutoImportFactory {
+	return dataSourceAutoImportFactory{
+		openAPIResource: openAPIResource,
+	}
+}
+
+// getDataSourceAutoImportName returns the name of the auto import data source for the given resource name
+func getDataSourceAutoImportName(resourceName string) string {
+	return fmt.Sprintf("%s_import", resourceName)
+}
+
+func (d dataSourceAutoImportFactory) createTerraformAutoImportDataSource() (*schema.Resource, error) {
+	s, err := d.createTerraformAutoImportDataSourceSchema()
+	if err != nil {
+		return nil, err
+	}
+	return &schema.Resource{
+		Schema:      s,
+		ReadContext: d.read,
+	}, nil
+}
+
+func (d dataSourceAutoImportFactory) createTerraformAutoImportDataSourceSchema() (map[string]*schema.Schema, error) {
+	keyAttributes, err := d.getKeyAttributes()
+	if err != nil {
+		return nil, err
+	}
+	instanceSchema := map[string]*schema.Schema{
+		dataSourceAutoImportInstanceIDProperty:       {Type: schema.TypeString, Computed: true},
+		dataSourceAutoImportInstanceImportIDProperty: {Type: schema.TypeString, Computed: true},
+	}
+	for _, property := range keyAttributes {
+		terraformType, err := property.terraformType()
+		if err != nil {
+			return nil, err
+		}
+		instanceSchema[property.getTerraformCompliantPropertyName()] = &schema.Schema{Type: terraformType, Computed: true}
+	}
+	dataSourceSchema := map[string]*schema.Schema{
+		dataSourceFilterPropertyName: newDataSourceFactory(d.openAPIResource).dataSourceFiltersSchema(),
+		dataSourceAutoImportIDsProperty: {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		dataSourceAutoImportImportIDsProperty: {
+			Type:     schema.TypeMap,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		dataSourceAutoImportInstancesProperty: {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Resource{Schema: instanceSchema},
+		},
+	}
+	// the parent ids (or the composite id values that scope the collection) are needed to resolve the collection path
+	for _, scopePropertyName := range d.getScopePropertyNames() {
+		dataSourceSchema[scopePropertyName] = &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+		}
+	}
+	return dataSourceSchema, nil
+}
+
+// getKeyAttributes returns the properties of the resource exposed for each of the instances listed: the required properties
+// with primitive values, leaving out the parent properties and the ones named as the built-in instance attributes
+func (d dataSourceAutoImportFactory) getKeyAttributes() ([]*specSchemaDefinitionProperty, error) {
+	resourceSchema, err := d.openAPIResource.getResourceSchema()
+	if err != nil {
+		return nil, err
+	}
+	var keyAttributes []*specSchemaDefinitionProperty
+	for _, property := range resourceSchema.Properties {
+		if !property.isRequired() || !property.isPrimitiveProperty() || property.IsParentProperty {
+			continue
+		}
+		switch property.getTerraformCompliantPropertyName() {
+		case dataSourceAutoImportInstanceIDProperty, dataSourceAutoImportInstanceImportIDProperty:
+			continue
+		}
+		keyAttributes = append(keyAttributes, property)
+	}
+	return keyAttributes, nil
+}
+
+// getScopePropertyNames returns the names of the properties that must be configured to list the instances: the parent
+// properties for sub-resources or all the composite id properties but the last one for resources identified by a composite id
+func (d dataSourceAutoImportFactory) getScopePropertyNames() []string {
+	if compositeID := d.openAPIResource.getCompositeID(); len(compositeID) > 0 {
+		resourceSchema, err := d.openAPIResource.getResourceSchema()
+		if err != nil {
+			return nil
+		}
+		var scopePropertyNames []string
+		for _, propertyName := range compositeID[:len(compositeID)-1] {
+			if property, err := resourceSchema.getProperty(propertyName); err == nil {
+				scopePropertyNames = append(scopePropertyNames, property.getTerraformCompliantPropertyName())
+			}
+		}
+		return scopePropertyNames
+	}
+	if parentResourceInfo := d.openAPIResource.getParentResourceInfo(); parentResourceInfo != nil {
+		return parentResourceInfo.getParentPropertiesNames()
+	}
+	return nil
+}
+
+// read is the ReadContext function of the data source, see readDataSource
+func (d dataSourceAutoImportFactory) read(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	ctx, cancel := operationContext(ctx, i)
+	defer cancel()
+	return operationDiagnostics(ctx, data, "", d.readDataSource(ctx, data, i))
+}
+
+// readDataSource lists the resource instances matching the filters making the API calls with the given context
+func (d dataSourceAutoImportFactory) readDataSource(ctx context.Context, data *schema.ResourceData, i interface{}) error {
+	openAPIClient := withDataSourceConditionalRequests(i.(ClientOpenAPI))
+
+	var parentIDs []string
+	for _, scopePropertyName := range d.getScopePropertyNames() {
+		parentIDs = append(parentIDs, data.Get(scopePropertyName).(string))
+	}
+	resourcePath, err := d.openAPIResource.getResourcePath(parentIDs)
+	if err != nil {
+		return err
+	}
+
+	filterFactory := newDataSourceFactory(d.openAPIResource)
+	filters, err := filterFactory.validateInput(data)
+	if err != nil {
+		return err
+	}
+
+	responsePayload := []map[string]interface{}{}
+	resp, err := contextClient(openAPIClient).ListContext(ctx, d.openAPIResource, &responsePayload, parentIDs...)
+	if err != nil {
+		return err
+	}
+	if err := checkHTTPStatusCode(d.openAPIResource, resp, []int{http.StatusOK}); err != nil {
+		return wrapError(err, "[data source='%s'] GET %s failed", getDataSourceAutoImportName(d.openAPIResource.getResourceName()), resourcePath)
+	}
+
+	keyAttributes, err := d.getKeyAttributes()
+	if err != nil {
+		return err
+	}
+	ids := []string{}
+	importIDs := map[string]interface{}{}
+	instances := []interface{}{}
+	for _, payloadItem := range responsePayload {
+		if !filterFactory.filterMatch(filters, payloadItem) {
+			continue
+		}
+		instanceID, err := d.getInstanceID(payloadItem)
+		if err != nil {
+			return err
+		}
+		if _, exists := importIDs[instanceID]; exists {
+			return fmt.Errorf("the API returned more than one instance with id '%s'", instanceID)
+		}
+		importID := strings.Join(append(append([]string{}, parentIDs...), instanceID), "/")
+		instance := map[string]interface{}{
+			dataSourceAutoImportInstanceIDProperty:       instanceID,
+			dataSourceAutoImportInstanceImportIDProperty: importID,
+		}
+		for _, property := range keyAttributes {
+			value, err := convertPayloadToLocalStateDataValue(property, payloadItem[property.Name], false)
+			if err != nil {
+				return err
+			}
+			if value != nil {
+				instance[property.getTerraformCompliantPropertyName()] = value
+			}
+		}
+		ids = append(ids, importID)
+		importIDs[instanceID] = importID
+		instances = append(instances, instance)
+	}
+
+	data.SetId(resourcePath)
+	if err := data.Set(dataSourceAutoImportIDsProperty, ids); err != nil {
+		return err
+	}
+	if err := data.Set(dataSourceAutoImportImportIDsProperty, importIDs); err != nil {
+		return err
+	}
+	return data.Set(dataSourceAutoImportInstancesProperty, instances)
+}
+
+// getInstanceID returns the id of the given instance within the collection: the value of the identifier property, or
+// the value of the last composite id property for resources identified by a composite id
+func (d dataSourceAutoImportFactory) getInstanceID(payloadItem map[string]interface{}) (string, error) {
+	resourceSchema, err := d.openAPIResource.getResourceSchema()
+	if err != nil {
+		return "", err
+	}
+	identifierProperty := ""
+	if compositeID := d.openAPIResource.getCompositeID(); len(compositeID) > 0 {
+		identifierProperty = compositeID[len(compositeID)-1]
+	} else if identifierProperty, err = resourceSchema.getResourceIdentifier(); err != nil {
+		return "", err
+	}
+	if payloadItem[identifierProperty] == nil {
+		return "", fmt.Errorf("response object returned from the API is missing mandatory identifier property '%s'", identifierProperty)
+	}
+	return identifierValueToString(payloadItem[identifierProperty]), nil
+}
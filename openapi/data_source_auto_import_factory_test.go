@@ -0,0 +1,294 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTerraformAutoImportDataSourceSchema(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		openAPIResource        *specStubResource
+		expectedInputs         []string
+		expectedInstanceFields []string
+	}{
+		{
+			name: "top level resource",
+			openAPIResource: &specStubResource{
+				schemaDefinition: &specSchemaDefinition{
+					Properties: specSchemaDefinitionProperties{
+						newStringSchemaDefinitionPropertyWithDefaults("id", "", false, true, nil),
+						newStringSchemaDefinitionPropertyWithDefaults("label", "", true, false, nil),
+						newIntSchemaDefinitionPropertyWithDefaults("port", "", true, false, nil),
+						newStringSchemaDefinitionPropertyWithDefaults("description", "", false, false, nil),
+						newListSchemaDefinitionPropertyWithDefaults("owners", "", true, false, false, nil, typeString, nil),
+					},
+				},
+			},
+			expectedInstanceFields: []string{"id", "import_id", "label", "port"},
+		},
+		{
+			name: "sub-resource",
+			openAPIResource: &specStubResource{
+				schemaDefinition: &specSchemaDefinition{
+					Properties: specSchemaDefinitionProperties{
+						newStringSchemaDefinitionPropertyWithDefaults("id", "", false, true, nil),
+						newStringSchemaDefinitionPropertyWithDefaults("label", "", true, false, nil),
+						newParentStringSchemaDefinitionPropertyWithDefaults("cdns_v1_id", "", true, false, nil),
+					},
+				},
+				fullParentResourceName: "cdns_v1",
+				parentResourceNames:    []string{"cdns_v1"},
+				parentPropertyNames:    []string{"cdns_v1_id"},
+			},
+			expectedInputs:         []string{"cdns_v1_id"},
+			expectedInstanceFields: []string{"id", "import_id", "label"},
+		},
+		{
+			name: "resource identified by a composite id",
+			openAPIResource: &specStubResource{
+				schemaDefinition: &specSchemaDefinition{
+					Properties: specSchemaDefinitionProperties{
+						newStringSchemaDefinitionPropertyWithDefaults("namespace", "", true, false, nil),
+						newStringSchemaDefinitionPropertyWithDefaults("name", "", true, false, nil),
+					},
+				},
+				compositeID: []string{"namespace", "name"},
+			},
+			expectedInputs:         []string{"namespace"},
+			expectedInstanceFields: []string{"id", "import_id", "namespace", "name"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := newDataSourceAutoImportFactory(tc.openAPIResource).createTerraformAutoImportDataSourceSchema()
+			require.NoError(t, err)
+			var inputs []string
+			for propertyName, propertySchema := range s {
+				if propertySchema.Required {
+					inputs = append(inputs, propertyName)
+				}
+			}
+			assert.ElementsMatch(t, tc.expectedInputs, inputs)
+			assert.Contains(t, s, dataSourceFilterPropertyName)
+			assert.True(t, s[dataSourceAutoImportIDsProperty].Computed)
+			assert.Equal(t, schema.TypeMap, s[dataSourceAutoImportImportIDsProperty].Type)
+			var instanceFields []string
+			for fieldName := range s[dataSourceAutoImportInstancesProperty].Elem.(*schema.Resource).Schema {
+				instanceFields = append(instanceFields, fieldName)
+			}
+			assert.ElementsMatch(t, tc.expectedInstanceFields, instanceFields)
+		})
+	}
+}
+
+func TestDataSourceAutoImportRead(t *testing.T) {
+	testCases := []struct {
+		name              string
+		openAPIResource   *specStubResource
+		input             map[string]interface{}
+		client            *clientOpenAPIStub
+		expectedParentIDs []string
+		expectedIDs       []interface{}
+		expectedImportIDs map[string]interface{}
+		expectedInstances []interface{}
+		expectedError     string
+	}{
+		{
+			name: "instances of a top level resource matching the filters",
+			openAPIResource: &specStubResource{
+				name: "cdns_v1",
+				path: "/v1/cdns",
+				schemaDefinition: &specSchemaDefinition{
+					Properties: specSchemaDefinitionProperties{
+						newStringSchemaDefinitionPropertyWithDefaults("id", "", false, true, nil),
+						newStringSchemaDefinitionPropertyWithDefaults("label", "", true, false, nil),
+						newIntSchemaDefinitionPropertyWithDefaults("port", "", true, false, nil),
+						newBoolSchemaDefinitionPropertyWithDefaults("enabled", "", false, false, nil),
+					},
+				},
+			},
+			input: map[string]interface{}{
+				dataSourceFilterPropertyName: []interface{}{newFilter("enabled", []interface{}{"true"})},
+			},
+			client: &clientOpenAPIStub{
+				responseListPayload: []map[string]interface{}{
+					{"id": "cdn1", "label": "first", "port": json.Number("80"), "enabled": true},
+					{"id": "cdn2", "label": "second", "port": json.Number("8080"), "enabled": false},
+					{"id": json.Number("3"), "label": "third", "enabled": true},
+				},
+			},
+			expectedIDs:       []interface{}{"cdn1", "3"},
+			expectedImportIDs: map[string]interface{}{"cdn1": "cdn1", "3": "3"},
+			expectedInstances: []interface{}{
+				map[string]interface{}{"id": "cdn1", "import_id": "cdn1", "label": "first", "port": 80},
+				map[string]interface{}{"id": "3", "import_id": "3", "label": "third", "port": 0},
+			},
+		},
+		{
+			name: "instances of a sub-resource are imported with the parent id",
+			openAPIResource: &specStubResource{
+				name: "cdns_v1_firewalls_v1",
+				path: "/v1/cdns/parentID/firewalls",
+				schemaDefinition: &specSchemaDefinition{
+					Properties: specSchemaDefinitionProperties{
+						newStringSchemaDefinitionPropertyWithDefaults("id", "", false, true, nil),
+						newStringSchemaDefinitionPropertyWithDefaults("label", "", true, false, nil),
+						newParentStringSchemaDefinitionPropertyWithDefaults("cdns_v1_id", "", true, false, nil),
+					},
+				},
+				fullParentResourceName: "cdns_v1",
+				parentResourceNames:    []string{"cdns_v1"},
+				parentPropertyNames:    []string{"cdns_v1_id"},
+			},
+			input: map[string]interface{}{
+				"cdns_v1_id": "parentID",
+			},
+			client: &clientOpenAPIStub{
+				responseListPayload: []map[string]interface{}{
+					{"id": "fw1", "label": "first"},
+				},
+			},
+			expectedParentIDs: []string{"parentID"},
+			expectedIDs:       []interface{}{"parentID/fw1"},
+			expectedImportIDs: map[string]interface{}{"fw1": "parentID/fw1"},
+			expectedInstances: []interface{}{
+				map[string]interface{}{"id": "fw1", "import_id": "parentID/fw1", "label": "first"},
+			},
+		},
+		{
+			name: "instances of a resource identified by a composite id are imported with all the composite id values",
+			openAPIResource: &specStubResource{
+				name: "things_v1",
+				path: "/v1/ns/ns1/things",
+				schemaDefinition: &specSchemaDefinition{
+					Properties: specSchemaDefinitionProperties{
+						newStringSchemaDefinitionPropertyWithDefaults("namespace", "", true, false, nil),
+						newStringSchemaDefinitionPropertyWithDefaults("name", "", true, false, nil),
+					},
+				},
+				compositeID: []string{"namespace", "name"},
+			},
+			input: map[string]interface{}{
+				"namespace": "ns1",
+			},
+			client: &clientOpenAPIStub{
+				responseListPayload: []map[string]interface{}{
+					{"namespace": "ns1", "name": "thing1"},
+				},
+			},
+			expectedParentIDs: []string{"ns1"},
+			expectedIDs:       []interface{}{"ns1/thing1"},
+			expectedImportIDs: map[string]interface{}{"thing1": "ns1/thing1"},
+			expectedInstances: []interface{}{
+				map[string]interface{}{"id": "thing1", "import_id": "ns1/thing1", "namespace": "ns1", "name": "thing1"},
+			},
+		},
+		{
+			name: "empty collection",
+			openAPIResource: &specStubResource{
+				name: "cdns_v1",
+				path: "/v1/cdns",
+				schemaDefinition: &specSchemaDefinition{
+					Properties: specSchemaDefinitionProperties{
+						newStringSchemaDefinitionPropertyWithDefaults("id", "", false, true, nil),
+					},
+				},
+			},
+			client:            &clientOpenAPIStub{responseListPayload: []map[string]interface{}{}},
+			expectedIDs:       []interface{}{},
+			expectedImportIDs: map[string]interface{}{},
+			expectedInstances: []interface{}{},
+		},
+		{
+			name: "instance missing the identifier",
+			openAPIResource: &specStubResource{
+				name: "cdns_v1",
+				path: "/v1/cdns",
+				schemaDefinition: &specSchemaDefinition{
+					Properties: specSchemaDefinitionProperties{
+						newStringSchemaDefinitionPropertyWithDefaults("id", "", false, true, nil),
+						newStringSchemaDefinitionPropertyWithDefaults("label", "", true, false, nil),
+					},
+				},
+			},
+			client: &clientOpenAPIStub{
+				responseListPayload: []map[string]interface{}{{"label": "first"}},
+			},
+			expectedError: "response object returned from the API is missing mandatory identifier property 'id'",
+		},
+		{
+			name: "instances with the same id",
+			openAPIResource: &specStubResource{
+				name: "cdns_v1",
+				path: "/v1/cdns",
+				schemaDefinition: &specSchemaDefinition{
+					Properties: specSchemaDefinitionProperties{
+						newStringSchemaDefinitionPropertyWithDefaults("id", "", false, true, nil),
+					},
+				},
+			},
+			client: &clientOpenAPIStub{
+				responseListPayload: []map[string]interface{}{{"id": "cdn1"}, {"id": "cdn1"}},
+			},
+			expectedError: "the API returned more than one instance with id 'cdn1'",
+		},
+		{
+			name: "list operation fails",
+			openAPIResource: &specStubResource{
+				name: "cdns_v1",
+				path: "/v1/cdns",
+				schemaDefinition: &specSchemaDefinition{
+					Properties: specSchemaDefinitionProperties{
+						newStringSchemaDefinitionPropertyWithDefaults("id", "", false, true, nil),
+					},
+				},
+			},
+			client:        &clientOpenAPIStub{error: errors.New("some error")},
+			expectedError: "some error",
+		},
+		{
+			name: "list operation returns an unexpected status code",
+			openAPIResource: &specStubResource{
+				name: "cdns_v1",
+				path: "/v1/cdns",
+				schemaDefinition: &specSchemaDefinition{
+					Properties: specSchemaDefinitionProperties{
+						newStringSchemaDefinitionPropertyWithDefaults("id", "", false, true, nil),
+					},
+				},
+			},
+			client:        &clientOpenAPIStub{returnHTTPCode: http.StatusBadRequest},
+			expectedError: "[data source='cdns_v1_import'] GET /v1/cdns failed: [resource='cdns_v1'] HTTP Response Status Code 400 not matching expected one [200] ()",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := newDataSourceAutoImportFactory(tc.openAPIResource)
+			s, err := d.createTerraformAutoImportDataSourceSchema()
+			require.NoError(t, err)
+			data := schema.TestResourceDataRaw(t, s, tc.input)
+
+			err = d.readDataSource(context.Background(), data, tc.client)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.openAPIResource.path, data.Id())
+			if tc.expectedParentIDs != nil {
+				assert.Equal(t, tc.expectedParentIDs, tc.client.parentIDsReceived)
+			}
+			assert.Equal(t, tc.expectedIDs, data.Get(dataSourceAutoImportIDsProperty))
+			assert.Equal(t, tc.expectedImportIDs, data.Get(dataSourceAutoImportImportIDsProperty))
+			assert.Equal(t, tc.expectedInstances, data.Get(dataSourceAutoImportInstancesProperty))
+		})
+	}
+}
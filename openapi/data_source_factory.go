@@ -1,12 +1,15 @@
 package openapi
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"math"
 	"net/http"
 	"strconv"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const dataSourceFilterPropertyName = "filter"
@@ -35,8 +38,8 @@ func (d dataSourceFactory) createTerraformDataSource() (*schema.Resource, error)
 		return nil, err
 	}
 	return &schema.Resource{
-		Schema: s,
-		Read:   d.read,
+		Schema:      s,
+		ReadContext: d.read,
 	}, nil
 }
 
@@ -74,8 +77,17 @@ func (d dataSourceFactory) dataSourceFiltersSchema() *schema.Schema {
 	}
 }
 
-func (d dataSourceFactory) read(data *schema.ResourceData, i interface{}) error {
-	openAPIClient := i.(ClientOpenAPI)
+// read is the ReadContext function of the data source, see readDataSource
+func (d dataSourceFactory) read(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	ctx, cancel := operationContext(ctx, i)
+	defer cancel()
+	return operationDiagnostics(ctx, data, "", d.readDataSource(ctx, data, i))
+}
+
+// readDataSource lists the resource instances making the API calls with the given context and populates the state with
+// the only instance matching the filters
+func (d dataSourceFactory) readDataSource(ctx context.Context, data *schema.ResourceData, i interface{}) error {
+	openAPIClient := withDataSourceConditionalRequests(i.(ClientOpenAPI))
 
 	parentIDs, resourcePath, err := getParentIDsAndResourcePath(d.openAPIResource, data)
 	if err != nil {
@@ -88,13 +100,13 @@ func (d dataSourceFactory) read(data *schema.ResourceData, i interface{}) error
 	}
 
 	responsePayload := []map[string]interface{}{}
-	resp, err := openAPIClient.List(d.openAPIResource, &responsePayload, parentIDs...)
+	resp, err := contextClient(openAPIClient).ListContext(ctx, d.openAPIResource, &responsePayload, parentIDs...)
 	if err != nil {
 		return err
 	}
 
 	if err := checkHTTPStatusCode(d.openAPIResource, resp, []int{http.StatusOK}); err != nil {
-		return fmt.Errorf("[data source='%s'] GET %s failed: %s", d.openAPIResource.getResourceName(), resourcePath, err)
+		return wrapError(err, "[data source='%s'] GET %s failed", d.openAPIResource.getResourceName(), resourcePath)
 	}
 
 	var filteredResults []map[string]interface{}
@@ -121,12 +133,25 @@ func (d dataSourceFactory) read(data *schema.ResourceData, i interface{}) error
 	return updateStateWithPayloadData(d.openAPIResource, filteredResults[0], data)
 }
 
+// withDataSourceConditionalRequests returns the client the data sources read with, which revalidates the responses cached
+// in disk if the data source cache is enabled in the service configuration
+func withDataSourceConditionalRequests(openAPIClient ClientOpenAPI) ClientOpenAPI {
+	if client, ok := openAPIClient.(*ProviderClient); ok {
+		return client.withConditionalRequests()
+	}
+	return openAPIClient
+}
+
 func (d dataSourceFactory) filterMatch(filters filters, payloadItem map[string]interface{}) bool {
 	specSchemaDefinition, _ := d.openAPIResource.getResourceSchema() // ignoring error because will be caught beforehand when data source is constructed via createTerraformDataSourceSchema
 	for _, filter := range filters {
 		if val, exists := payloadItem[filter.name]; exists {
 			schemaProperty, _ := specSchemaDefinition.getProperty(filter.name)
 			var value string
+			if number, isNumber := val.(json.Number); isNumber {
+				// numbers in the API responses are decoded as json.Number, convert them into the type of the property first
+				val, _ = convertPayloadNumberToLocalStateDataValue(schemaProperty, number, false)
+			}
 			switch schemaProperty.Type {
 			case typeInt:
 				value = strconv.Itoa(val.(int))
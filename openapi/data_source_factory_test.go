@@ -1,12 +1,13 @@
 package openapi
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -46,10 +47,10 @@ func TestCreateTerraformDataSource(t *testing.T) {
 		if tc.expectedError == nil {
 			assert.Nil(t, err, tc.name)
 			assert.NotNil(t, dataSource, tc.name)
-			assert.NotNil(t, dataSource.Read, tc.name)
-			assert.Nil(t, dataSource.Delete, tc.name)
-			assert.Nil(t, dataSource.Create, tc.name)
-			assert.Nil(t, dataSource.Update, tc.name)
+			assert.NotNil(t, dataSource.ReadContext, tc.name)
+			assert.Nil(t, dataSource.DeleteContext, tc.name)
+			assert.Nil(t, dataSource.CreateContext, tc.name)
+			assert.Nil(t, dataSource.UpdateContext, tc.name)
 		} else {
 			assert.Equal(t, tc.expectedError.Error(), err.Error(), tc.name)
 		}
@@ -217,7 +218,7 @@ func TestDataSourceRead(t *testing.T) {
 			responseListPayload: tc.responsePayload,
 		}
 		// When
-		err = dataSourceFactory.read(resourceData, client)
+		err = dataSourceFactory.readDataSource(context.Background(), resourceData, client)
 		// Then
 		if tc.expectedError == nil {
 			assert.Nil(t, err, tc.name)
@@ -273,7 +274,7 @@ func TestDataSourceRead_Subresource(t *testing.T) {
 			},
 		},
 	}
-	err = dataSourceFactory.read(resourceData, client)
+	err = dataSourceFactory.readDataSource(context.Background(), resourceData, client)
 	require.NoError(t, err)
 	assert.Equal(t, []string{"parentPropertyID"}, client.parentIDsReceived) // check that the parent id is passed as expected
 	assert.Equal(t, "someID", resourceData.Id())
@@ -353,7 +354,7 @@ func TestDataSourceRead_ForNestedObjects(t *testing.T) {
 		},
 	}
 	// When
-	err = dataSourceFactory.read(resourceData, client)
+	err = dataSourceFactory.readDataSource(context.Background(), resourceData, client)
 	// Then
 	assert.Nil(t, err)
 	// assert that the filtered data source contains the same values as the ones returned by the API
@@ -363,7 +364,7 @@ func TestDataSourceRead_ForNestedObjects(t *testing.T) {
 }
 
 func TestDataSourceRead_Fails_Because_Cannot_extract_ParentsID(t *testing.T) {
-	err := dataSourceFactory{}.read(nil, &clientOpenAPIStub{})
+	err := dataSourceFactory{}.readDataSource(context.Background(), nil, &clientOpenAPIStub{})
 	assert.EqualError(t, err, "can't get parent ids from a resourceFactory with no openAPIResource")
 }
 
@@ -394,7 +395,7 @@ func TestDataSourceRead_Fails_Because_List_Operation_Returns_Err(t *testing.T) {
 		},
 		error: errors.New("some error"),
 	}
-	err = dataSourceFactory.read(resourceData, client)
+	err = dataSourceFactory.readDataSource(context.Background(), resourceData, client)
 	assert.EqualError(t, err, "some error")
 }
 
@@ -423,9 +424,11 @@ func TestDataSourceRead_Fails_Because_Bad_Status_Code(t *testing.T) {
 		returnHTTPCode: 400,
 	}
 	// When
-	err = dataSourceFactory.read(resourceData, client)
+	err = dataSourceFactory.readDataSource(context.Background(), resourceData, client)
 	// Then
-	assert.Equal(t, errors.New("[data source='some resource'] GET  failed: [resource='some resource'] HTTP Response Status Code 400 not matching expected one [200] ()"), err)
+	assert.EqualError(t, err, "[data source='some resource'] GET  failed: [resource='some resource'] HTTP Response Status Code 400 not matching expected one [200] ()")
+	assert.IsType(t, &APIError{}, err)
+	assert.Equal(t, 400, err.(*APIError).StatusCode)
 }
 
 func TestValidateInput(t *testing.T) {
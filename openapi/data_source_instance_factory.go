@@ -1,14 +1,20 @@
 package openapi
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const dataSourceInstanceIDProperty = "id"
 
+// defaultDataSourceInstanceSuffix is the suffix appended to the resource names to build the names of their data source
+// instances, unless the service configuration defines a different one
+const defaultDataSourceInstanceSuffix = "_instance"
+
 type dataSourceInstanceFactory struct {
 	openAPIResource SpecResource
 }
@@ -19,8 +25,13 @@ func newDataSourceInstanceFactory(openAPIResource SpecResource) dataSourceInstan
 	}
 }
 
-func (d dataSourceInstanceFactory) getDataSourceInstanceName() string {
-	return fmt.Sprintf("%s_instance", d.openAPIResource.getResourceName())
+func (d dataSourceInstanceFactory) getDataSourceInstanceName(suffix string) string {
+	return getDataSourceInstanceName(d.openAPIResource.getResourceName(), suffix)
+}
+
+// getDataSourceInstanceName returns the name of the data source instance for the given resource name and suffix
+func getDataSourceInstanceName(resourceName, suffix string) string {
+	return fmt.Sprintf("%s%s", resourceName, suffix)
 }
 
 func (d dataSourceInstanceFactory) createTerraformInstanceDataSource() (*schema.Resource, error) {
@@ -29,8 +40,8 @@ func (d dataSourceInstanceFactory) createTerraformInstanceDataSource() (*schema.
 		return nil, err
 	}
 	return &schema.Resource{
-		Schema: s,
-		Read:   d.read,
+		Schema:      s,
+		ReadContext: d.read,
 	}, nil
 }
 
@@ -54,8 +65,24 @@ func (d dataSourceInstanceFactory) dataSourceInstanceSchema() *schema.Schema {
 	}
 }
 
-func (d dataSourceInstanceFactory) read(data *schema.ResourceData, i interface{}) error {
-	openAPIClient := i.(ClientOpenAPI)
+// read is the ReadContext function of the data source, see readDataSource
+func (d dataSourceInstanceFactory) read(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	ctx, cancel := operationContext(ctx, i)
+	defer cancel()
+	return operationDiagnostics(ctx, data, "", d.readDataSource(ctx, data, i))
+}
+
+// readDataSource reads the resource instance with the id configured making the API calls with the given context
+func (d dataSourceInstanceFactory) readDataSource(ctx context.Context, data *schema.ResourceData, i interface{}) error {
+	openAPIClient := withDataSourceConditionalRequests(i.(ClientOpenAPI))
+	isCompositeID := d.openAPIResource != nil && len(d.openAPIResource.getCompositeID()) > 0
+	if isCompositeID {
+		// The id of the resources identified by a composite id (e.g. ns1/thing1) contains all the values needed to resolve
+		// the resource instance path
+		if id, ok := data.GetOk(dataSourceInstanceIDProperty); ok {
+			data.SetId(id.(string))
+		}
+	}
 	parentIDs, resourcePath, err := getParentIDsAndResourcePath(d.openAPIResource, data)
 	if err != nil {
 		return err
@@ -64,13 +91,19 @@ func (d dataSourceInstanceFactory) read(data *schema.ResourceData, i interface{}
 	if id == nil || id == "" {
 		return fmt.Errorf("data source 'id' property value must be populated")
 	}
+	instanceID := id.(string)
+	if isCompositeID {
+		if instanceID, err = getResourceInstanceID(d.openAPIResource, data); err != nil {
+			return err
+		}
+	}
 	responsePayload := map[string]interface{}{}
-	resp, err := openAPIClient.Get(d.openAPIResource, id.(string), &responsePayload, parentIDs...)
+	resp, err := contextClient(openAPIClient).GetContext(ctx, d.openAPIResource, instanceID, &responsePayload, parentIDs...)
 	if err != nil {
 		return err
 	}
 	if err := checkHTTPStatusCode(d.openAPIResource, resp, []int{http.StatusOK}); err != nil {
-		return fmt.Errorf("[data source instance='%s'] GET %s failed: %s", d.openAPIResource.getResourceName(), resourcePath, err)
+		return wrapError(err, "[data source instance='%s'] GET %s failed", d.openAPIResource.getResourceName(), resourcePath)
 	}
 	err = setStateID(d.openAPIResource, data, responsePayload)
 	if err != nil {
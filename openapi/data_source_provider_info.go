@@ -0,0 +1,120 @@
+package openapi
+
+import (
+	"context"
+	"sort"
+
+	"github.com/dikhan/terraform-provider-openapi/openapi/version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// providerInfoDataSourceName is the name (without the provider name prefix) of the built-in data source that describes the
+// provider itself: the version of the plugin, the OpenAPI document it was built out of and the resources generated
+const providerInfoDataSourceName = "provider_info"
+
+const (
+	providerInfoPropertyProviderVersion = "provider_version"
+	providerInfoPropertySpecVersion     = "spec_version"
+	providerInfoPropertySpecHash        = "spec_hash"
+	providerInfoPropertyResourceTypes   = "resource_types"
+	providerInfoPropertyDataSourceTypes = "data_source_types"
+	providerInfoPropertySkippedPaths    = "skipped_paths"
+)
+
+// providerInfoDataSourceFactory creates the provider_info data source, which enables configurations and CI checks to
+// assert expectations about the surface generated out of the OpenAPI document (e.g. a resource type is still exposed
+// after upgrading the API). The resources and data sources maps are the ones registered in the provider, thus the types
+// listed are the ones users can actually use; deprecated aliases are not listed.
+type providerInfoDataSourceFactory struct {
+	specAnalyser   SpecAnalyser
+	resourcesMap   map[string]*schema.Resource
+	dataSourcesMap map[string]*schema.Resource
+}
+
+func (p providerInfoDataSourceFactory) createTerraformDataSource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			providerInfoPropertyProviderVersion: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Version of the OpenAPI Terraform provider plugin",
+			},
+			providerInfoPropertySpecVersion: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Version of the API declared in the OpenAPI document (info.version)",
+			},
+			providerInfoPropertySpecHash: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA-256 hash of the OpenAPI document the provider was built out of",
+			},
+			providerInfoPropertyResourceTypes: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Resource types exposed by the provider, sorted alphabetically",
+			},
+			providerInfoPropertyDataSourceTypes: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Data source types exposed by the provider, sorted alphabetically",
+			},
+			providerInfoPropertySkippedPaths: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Paths of the OpenAPI document not exposed neither as resources nor as data sources (e,g: not terraform compliant), sorted alphabetically",
+			},
+		},
+		ReadContext: p.read,
+	}
+}
+
+// read is the ReadContext function of the data source, which makes no API calls
+func (p providerInfoDataSourceFactory) read(_ context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	return diag.FromErr(p.setInfo(data))
+}
+
+// setInfo populates the state with the info of the provider
+func (p providerInfoDataSourceFactory) setInfo(data *schema.ResourceData) error {
+	documentInfo, err := p.specAnalyser.GetDocumentInfo()
+	if err != nil {
+		return wrapError(err, "[data source='%s'] failed to read the OpenAPI document info", providerInfoDataSourceName)
+	}
+	if err := data.Set(providerInfoPropertyProviderVersion, version.Version); err != nil {
+		return err
+	}
+	if err := data.Set(providerInfoPropertySpecVersion, documentInfo.Version); err != nil {
+		return err
+	}
+	if err := data.Set(providerInfoPropertySpecHash, documentInfo.Hash); err != nil {
+		return err
+	}
+	if err := data.Set(providerInfoPropertyResourceTypes, p.getTypes(p.resourcesMap)); err != nil {
+		return err
+	}
+	if err := data.Set(providerInfoPropertyDataSourceTypes, p.getTypes(p.dataSourcesMap)); err != nil {
+		return err
+	}
+	if err := data.Set(providerInfoPropertySkippedPaths, documentInfo.SkippedPaths); err != nil {
+		return err
+	}
+	data.SetId(providerInfoDataSourceName)
+	return nil
+}
+
+// getTypes returns the names of the given resources sorted alphabetically, leaving out the deprecated aliases
+func (p providerInfoDataSourceFactory) getTypes(resourcesMap map[string]*schema.Resource) []string {
+	types := []string{}
+	for name, resource := range resourcesMap {
+		if resource.DeprecationMessage != "" {
+			continue
+		}
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	return types
+}
@@ -0,0 +1,63 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dikhan/terraform-provider-openapi/openapi/version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderInfoDataSourceSchema(t *testing.T) {
+	dataSource := providerInfoDataSourceFactory{}.createTerraformDataSource()
+	assert.NoError(t, dataSource.InternalValidate(nil, false))
+	for _, property := range dataSource.Schema {
+		assert.True(t, property.Computed)
+	}
+}
+
+func TestProviderInfoDataSourceRead(t *testing.T) {
+	specAnalyser := &specAnalyserStub{
+		documentInfo: &SpecDocumentInfo{
+			Version:      "1.2.0",
+			Hash:         "someHash",
+			SkippedPaths: []string{"/v1/health"},
+		},
+	}
+	resourcesMap := map[string]*schema.Resource{
+		"openapi_cdn_v1":        {},
+		"openapi_api_object":    {},
+		"openapi_cdns_v1":       {DeprecationMessage: "'openapi_cdns_v1' is deprecated, please use 'openapi_cdn_v1' instead"},
+		"openapi_monitor_v1":    {},
+		"openapi_monitor_v1_ro": {},
+	}
+	dataSourcesMap := map[string]*schema.Resource{
+		"openapi_cdn_v1_instance": {},
+	}
+	factory := providerInfoDataSourceFactory{
+		specAnalyser:   specAnalyser,
+		resourcesMap:   resourcesMap,
+		dataSourcesMap: dataSourcesMap,
+	}
+	dataSource := factory.createTerraformDataSource()
+	dataSourcesMap["openapi_provider_info"] = dataSource
+
+	data := dataSource.TestResourceData()
+	require.Empty(t, dataSource.ReadContext(context.Background(), data, nil))
+	assert.Equal(t, providerInfoDataSourceName, data.Id())
+	assert.Equal(t, version.Version, data.Get(providerInfoPropertyProviderVersion))
+	assert.Equal(t, "1.2.0", data.Get(providerInfoPropertySpecVersion))
+	assert.Equal(t, "someHash", data.Get(providerInfoPropertySpecHash))
+	assert.Equal(t, []interface{}{"openapi_api_object", "openapi_cdn_v1", "openapi_monitor_v1", "openapi_monitor_v1_ro"}, data.Get(providerInfoPropertyResourceTypes))
+	assert.Equal(t, []interface{}{"openapi_cdn_v1_instance", "openapi_provider_info"}, data.Get(providerInfoPropertyDataSourceTypes))
+	assert.Equal(t, []interface{}{"/v1/health"}, data.Get(providerInfoPropertySkippedPaths))
+}
+
+func TestProviderInfoDataSourceReadError(t *testing.T) {
+	dataSource := providerInfoDataSourceFactory{specAnalyser: &specAnalyserStub{error: errors.New("some error")}}.createTerraformDataSource()
+	diags := dataSource.ReadContext(context.Background(), dataSource.TestResourceData(), nil)
+	assert.EqualError(t, diagnosticsError(diags), "[data source='provider_info'] failed to read the OpenAPI document info: some error")
+}
@@ -0,0 +1,130 @@
+package openapi
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// headerDeprecation is the header the APIs return when the endpoint called is deprecated. The value is either 'true', the
+	// date since the endpoint is deprecated as a unix timestamp prefixed with @ (e.g. @1688169599) or an HTTP-date
+	headerDeprecation = "Deprecation"
+	// headerSunset is the header (RFC 8594) the APIs return with the HTTP-date the endpoint called will be retired
+	headerSunset = "Sunset"
+)
+
+// deprecationNoticeDateFormat is the format of the deprecation and sunset dates included in the warnings
+const deprecationNoticeDateFormat = "2006-01-02"
+
+// deprecationLinkRegex matches the links of the Link header describing the deprecation or the sunset of the endpoint (e.g.
+// <https://api.com/deprecations/v1>; rel="sunset"). Group 1 contains the link URL
+var deprecationLinkRegex = regexp.MustCompile(`<([^>]+)>\s*;[^,]*rel="?(?:sunset|deprecation)"?`)
+
+// deprecationNotices warns about the resources whose API endpoints are deprecated or about to be retired, as announced by
+// the API via the Deprecation and Sunset response headers. Each resource is only warned about once during the terraform
+// run, regardless of the number of API calls made for it. A nil deprecationNotices is valid and means no warnings are
+// raised.
+type deprecationNotices struct {
+	mutex sync.Mutex
+	// notified contains the names of the resources already warned about
+	notified map[string]bool
+	// now returns the current time, used to tell whether the sunset date has passed; time.Now is used if nil
+	now func() time.Time
+}
+
+func newDeprecationNotices() *deprecationNotices {
+	return &deprecationNotices{notified: map[string]bool{}}
+}
+
+// notify logs a warning if the given response announces that the endpoint called for the resource is deprecated or will
+// be retired, unless the resource has already been warned about. The warning is returned so it can also be reported to
+// the user (see operationWarnings); empty if there is nothing to warn about
+func (d *deprecationNotices) notify(resourceName string, method httpMethodSupported, resourceURL string, resp *http.Response) string {
+	if d == nil || resp == nil {
+		return ""
+	}
+	deprecation := resp.Header.Get(headerDeprecation)
+	sunset := resp.Header.Get(headerSunset)
+	if (deprecation == "" || deprecation == "false") && sunset == "" {
+		return ""
+	}
+	d.mutex.Lock()
+	alreadyNotified := d.notified[resourceName]
+	d.notified[resourceName] = true
+	d.mutex.Unlock()
+	if alreadyNotified {
+		return ""
+	}
+	message := d.message(resourceName, method, resourceURL, deprecation, sunset, resp.Header["Link"])
+	log.Printf("[WARN] %s", message)
+	return message
+}
+
+// message returns the warning describing the deprecation of the resource endpoint
+func (d *deprecationNotices) message(resourceName string, method httpMethodSupported, resourceURL, deprecation, sunset string, links []string) string {
+	message := fmt.Sprintf("resource '%s' uses an API endpoint (%s %s) that is deprecated", resourceName, method, withoutQuery(resourceURL))
+	if deprecatedSince := formatDeprecationDate(deprecation); deprecatedSince != "" {
+		message += fmt.Sprintf(" since %s", deprecatedSince)
+	}
+	if sunset != "" {
+		sunsetDate, err := http.ParseTime(sunset)
+		switch {
+		case err != nil:
+			message += fmt.Sprintf(" and will be retired on %s", sunset)
+		case sunsetDate.Before(d.currentTime()):
+			message += fmt.Sprintf(" and was retired on %s", sunsetDate.UTC().Format(deprecationNoticeDateFormat))
+		default:
+			message += fmt.Sprintf(" and will be retired on %s", sunsetDate.UTC().Format(deprecationNoticeDateFormat))
+		}
+	}
+	message += ", please check with the API provider how to migrate away from it"
+	var moreInfo []string
+	for _, link := range links {
+		for _, match := range deprecationLinkRegex.FindAllStringSubmatch(link, -1) {
+			moreInfo = append(moreInfo, match[1])
+		}
+	}
+	if len(moreInfo) > 0 {
+		message += fmt.Sprintf(" (more info: %s)", strings.Join(moreInfo, ", "))
+	}
+	return message
+}
+
+func (d *deprecationNotices) currentTime() time.Time {
+	if d.now != nil {
+		return d.now()
+	}
+	return time.Now()
+}
+
+// formatDeprecationDate returns the date since the endpoint is deprecated given the value of the Deprecation header, empty
+// if the header does not contain a date (e.g. true)
+func formatDeprecationDate(deprecation string) string {
+	if strings.HasPrefix(deprecation, "@") {
+		if seconds, err := strconv.ParseInt(strings.TrimPrefix(deprecation, "@"), 10, 64); err == nil {
+			return time.Unix(seconds, 0).UTC().Format(deprecationNoticeDateFormat)
+		}
+	}
+	if deprecatedSince, err := http.ParseTime(deprecation); err == nil {
+		return deprecatedSince.UTC().Format(deprecationNoticeDateFormat)
+	}
+	return ""
+}
+
+// withoutQuery returns the URL without the query string, which may contain credentials (e.g. api keys sent as query
+// parameters)
+func withoutQuery(resourceURL string) string {
+	u, err := url.Parse(resourceURL)
+	if err != nil {
+		return resourceURL
+	}
+	u.RawQuery = ""
+	return u.String()
+}
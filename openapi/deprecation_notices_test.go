@@ -0,0 +1,154 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dikhan/http_goclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecationNoticesMessage(t *testing.T) {
+	now := time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name            string
+		deprecation     string
+		sunset          string
+		links           []string
+		expectedMessage string
+	}{
+		{
+			name:            "deprecation without date",
+			deprecation:     "true",
+			expectedMessage: "resource 'cdn_v1' uses an API endpoint (GET https://api.com/v1/cdns/1) that is deprecated, please check with the API provider how to migrate away from it",
+		},
+		{
+			name:            "deprecation with unix timestamp and sunset date",
+			deprecation:     "@1577836800",
+			sunset:          "Wed, 30 Dec 2020 23:59:59 GMT",
+			expectedMessage: "resource 'cdn_v1' uses an API endpoint (GET https://api.com/v1/cdns/1) that is deprecated since 2020-01-01 and will be retired on 2020-12-30, please check with the API provider how to migrate away from it",
+		},
+		{
+			name:            "deprecation with HTTP-date and sunset date already passed",
+			deprecation:     "Sun, 01 Dec 2019 00:00:00 GMT",
+			sunset:          "Fri, 01 May 2020 00:00:00 GMT",
+			expectedMessage: "resource 'cdn_v1' uses an API endpoint (GET https://api.com/v1/cdns/1) that is deprecated since 2019-12-01 and was retired on 2020-05-01, please check with the API provider how to migrate away from it",
+		},
+		{
+			name:            "sunset date not valid",
+			sunset:          "next year",
+			expectedMessage: "resource 'cdn_v1' uses an API endpoint (GET https://api.com/v1/cdns/1) that is deprecated and will be retired on next year, please check with the API provider how to migrate away from it",
+		},
+		{
+			name:            "sunset and deprecation links",
+			sunset:          "Wed, 30 Dec 2020 23:59:59 GMT",
+			links:           []string{`<https://api.com/v2/cdns>; rel="successor-version", <https://api.com/sunsets/cdn_v1>; rel="sunset"`, `<https://api.com/deprecations/cdn_v1>; rel=deprecation; type="text/html"`},
+			expectedMessage: "resource 'cdn_v1' uses an API endpoint (GET https://api.com/v1/cdns/1) that is deprecated and will be retired on 2020-12-30, please check with the API provider how to migrate away from it (more info: https://api.com/sunsets/cdn_v1, https://api.com/deprecations/cdn_v1)",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := &deprecationNotices{notified: map[string]bool{}, now: func() time.Time { return now }}
+			assert.Equal(t, tc.expectedMessage, d.message("cdn_v1", httpGet, "https://api.com/v1/cdns/1?apikey=secret", tc.deprecation, tc.sunset, tc.links))
+		})
+	}
+}
+
+func TestDeprecationNoticesNotify(t *testing.T) {
+	newResponse := func(headers map[string]string) *http.Response {
+		resp := &http.Response{Header: http.Header{}}
+		for name, value := range headers {
+			resp.Header.Set(name, value)
+		}
+		return resp
+	}
+
+	t.Run("each resource is only warned about once", func(t *testing.T) {
+		var out bytes.Buffer
+		log.SetOutput(&out)
+		defer log.SetOutput(os.Stderr)
+		d := newDeprecationNotices()
+		d.notify("cdn_v1", httpGet, "https://api.com/v1/cdns/1", newResponse(map[string]string{"Deprecation": "true"}))
+		d.notify("cdn_v1", httpPut, "https://api.com/v1/cdns/1", newResponse(map[string]string{"Deprecation": "true"}))
+		d.notify("lb_v1", httpGet, "https://api.com/v1/lbs/1", newResponse(map[string]string{"Sunset": "Wed, 30 Dec 2099 23:59:59 GMT"}))
+		assert.Equal(t, 1, strings.Count(out.String(), "resource 'cdn_v1'"))
+		assert.Equal(t, 1, strings.Count(out.String(), "resource 'lb_v1'"))
+	})
+
+	t.Run("responses without deprecation headers are not warned about", func(t *testing.T) {
+		var out bytes.Buffer
+		log.SetOutput(&out)
+		defer log.SetOutput(os.Stderr)
+		d := newDeprecationNotices()
+		d.notify("cdn_v1", httpGet, "https://api.com/v1/cdns/1", newResponse(map[string]string{}))
+		d.notify("cdn_v1", httpGet, "https://api.com/v1/cdns/1", newResponse(map[string]string{"Deprecation": "false"}))
+		var nilNotices *deprecationNotices
+		nilNotices.notify("cdn_v1", httpGet, "https://api.com/v1/cdns/1", newResponse(map[string]string{"Deprecation": "true"}))
+		assert.Empty(t, out.String())
+		assert.False(t, d.notified["cdn_v1"])
+	})
+}
+
+func TestProviderClient_DeprecationNotices(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Sunset", "Wed, 30 Dec 2099 23:59:59 GMT")
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer api.Close()
+	client := &ProviderClient{
+		openAPIBackendConfiguration: newStubBackendConfiguration(strings.TrimPrefix(api.URL, "http://"), "", "http"),
+		httpClient:                  &http_goclient.HttpClient{HttpClient: &http.Client{}},
+		providerConfiguration:       providerConfiguration{},
+		apiAuthenticator:            newStubAuthenticator("Authorization", "Bearer secret", nil),
+		deprecationNotices:          newDeprecationNotices(),
+	}
+	resource := &specStubResource{
+		name:                    "cdn_v1",
+		path:                    "/v1/cdns",
+		schemaDefinition:        &specSchemaDefinition{},
+		resourceGetOperation:    &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}},
+		resourceDeleteOperation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}},
+	}
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	_, err := client.Get(resource, "1", &map[string]interface{}{})
+	require.NoError(t, err)
+	_, err = client.Delete(resource, "1")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, strings.Count(out.String(), "[WARN] resource 'cdn_v1' uses an API endpoint (GET "+api.URL+"/v1/cdns/1) that is deprecated and will be retired on 2099-12-30"))
+	assert.NotContains(t, out.String(), "(DELETE ")
+
+	t.Run("the warnings are returned as warning diagnostics of the operation", func(t *testing.T) {
+		client.deprecationNotices = newDeprecationNotices()
+		operation := func() diag.Diagnostics {
+			ctx, cancel := operationContext(context.Background(), client)
+			defer cancel()
+			_, err := client.GetContext(ctx, resource, "1", &map[string]interface{}{})
+			return operationDiagnostics(ctx, nil, "", err)
+		}
+		diags := operation()
+		require.Len(t, diags, 1)
+		assert.Equal(t, diag.Warning, diags[0].Severity)
+		assert.Equal(t, "resource 'cdn_v1' uses an API endpoint (GET "+api.URL+"/v1/cdns/1) that is deprecated and will be retired on 2099-12-30, please check with the API provider how to migrate away from it", diags[0].Summary)
+
+		diags = operation()
+		assert.Empty(t, diags, "each resource is only warned about once")
+	})
+}
@@ -0,0 +1,107 @@
+package openapi
+
+import (
+	"fmt"
+)
+
+// SpecFetchError is returned when the OpenAPI document could not be retrieved from the URL configured (e.g. the URL is
+// not reachable or the file does not exist)
+type SpecFetchError struct {
+	// URL contains the location the OpenAPI document was attempted to be retrieved from
+	URL string
+	Err error
+}
+
+// Error returns the error message describing why the OpenAPI document could not be retrieved
+func (e *SpecFetchError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *SpecFetchError) Unwrap() error {
+	return e.Err
+}
+
+// SpecAnalysisError is returned when the OpenAPI document was retrieved but the provider could not be built out of it and
+// the service configuration (e.g. the document could not be expanded or it contains configuration the provider does not
+// support)
+type SpecAnalysisError struct {
+	Err error
+}
+
+// Error returns the error message describing why the provider could not be built out of the OpenAPI document
+func (e *SpecAnalysisError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *SpecAnalysisError) Unwrap() error {
+	return e.Err
+}
+
+// AuthConfigError is returned when the authentication configuration is not valid (e.g. an operation refers to a security
+// definition that does not exist, the value of a security definition is missing or the refresh token could not be
+// exchanged for an access token)
+type AuthConfigError struct {
+	Err error
+}
+
+// Error returns the error message describing what is wrong with the authentication configuration
+func (e *AuthConfigError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *AuthConfigError) Unwrap() error {
+	return e.Err
+}
+
+// APIError is returned when the API responded with a status code that was not expected for the operation performed
+type APIError struct {
+	// StatusCode contains the HTTP status code returned by the API
+	StatusCode int
+	// Method contains the HTTP method of the request (e.g. POST)
+	Method string
+	// URL contains the URL the request was sent to
+	URL string
+	// Body contains the response body returned by the API, if any
+	Body string
+	Err  error
+}
+
+// Error returns the error message describing the unexpected API response
+func (e *APIError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// isTypedError checks whether the given error is one of the typed errors exported by this package
+func isTypedError(err error) bool {
+	switch err.(type) {
+	case *SpecFetchError, *SpecAnalysisError, *AuthConfigError, *APIError:
+		return true
+	}
+	return false
+}
+
+// wrapError returns a new error prefixing the given error's message with the formatted message passed in. If the error
+// is one of the typed errors exported by this package the error returned is of the same type and keeps the same details
+// so callers can still branch on the failure category.
+func wrapError(err error, format string, a ...interface{}) error {
+	wrappedErr := fmt.Errorf("%s: %s", fmt.Sprintf(format, a...), err)
+	switch e := err.(type) {
+	case *SpecFetchError:
+		return &SpecFetchError{URL: e.URL, Err: wrappedErr}
+	case *SpecAnalysisError:
+		return &SpecAnalysisError{Err: wrappedErr}
+	case *AuthConfigError:
+		return &AuthConfigError{Err: wrappedErr}
+	case *APIError:
+		return &APIError{StatusCode: e.StatusCode, Method: e.Method, URL: e.URL, Body: e.Body, Err: wrappedErr}
+	}
+	return wrappedErr
+}
@@ -0,0 +1,70 @@
+package openapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapError(t *testing.T) {
+	testCases := []struct {
+		name          string
+		err           error
+		expectedType  error
+		expectedError string
+	}{
+		{
+			name:          "spec fetch error",
+			err:           &SpecFetchError{URL: "http://api.com/swagger.yaml", Err: errors.New("some error")},
+			expectedType:  &SpecFetchError{},
+			expectedError: "prefix 1: some error",
+		},
+		{
+			name:          "spec analysis error",
+			err:           &SpecAnalysisError{Err: errors.New("some error")},
+			expectedType:  &SpecAnalysisError{},
+			expectedError: "prefix 1: some error",
+		},
+		{
+			name:          "auth config error",
+			err:           &AuthConfigError{Err: errors.New("some error")},
+			expectedType:  &AuthConfigError{},
+			expectedError: "prefix 1: some error",
+		},
+		{
+			name:          "api error",
+			err:           &APIError{StatusCode: 500, Method: "GET", URL: "http://api.com/v1/resource", Err: errors.New("some error")},
+			expectedType:  &APIError{},
+			expectedError: "prefix 1: some error",
+		},
+		{
+			name:          "non typed error",
+			err:           errors.New("some error"),
+			expectedType:  errors.New(""),
+			expectedError: "prefix 1: some error",
+		},
+	}
+	for _, tc := range testCases {
+		err := wrapError(tc.err, "prefix %d", 1)
+		assert.EqualError(t, err, tc.expectedError, tc.name)
+		assert.IsType(t, tc.expectedType, err, tc.name)
+	}
+}
+
+func TestWrapError_KeepsAPIErrorDetails(t *testing.T) {
+	err := wrapError(&APIError{StatusCode: 500, Method: "GET", URL: "http://api.com/v1/resource", Body: "some body", Err: errors.New("some error")}, "prefix")
+	apiErr := err.(*APIError)
+	assert.Equal(t, 500, apiErr.StatusCode)
+	assert.Equal(t, "GET", apiErr.Method)
+	assert.Equal(t, "http://api.com/v1/resource", apiErr.URL)
+	assert.Equal(t, "some body", apiErr.Body)
+}
+
+func TestIsTypedError(t *testing.T) {
+	assert.True(t, isTypedError(&SpecFetchError{}))
+	assert.True(t, isTypedError(&SpecAnalysisError{}))
+	assert.True(t, isTypedError(&AuthConfigError{}))
+	assert.True(t, isTypedError(&APIError{}))
+	assert.False(t, isTypedError(errors.New("some error")))
+}
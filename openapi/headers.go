@@ -4,4 +4,8 @@ package openapi
 const (
 	authorizationHeader = "Authorization"
 	userAgentHeader     = "User-Agent"
+	contentTypeHeader   = "Content-Type"
+	acceptHeader        = "Accept"
+	etagHeader          = "ETag"
+	ifNoneMatchHeader   = "If-None-Match"
 )
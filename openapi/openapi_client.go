@@ -1,11 +1,17 @@
 package openapi
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net/http"
+	"net/url"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/dikhan/terraform-provider-openapi/openapi/version"
 
@@ -18,6 +24,7 @@ const (
 	httpGet    httpMethodSupported = "GET"
 	httpPost   httpMethodSupported = "POST"
 	httpPut    httpMethodSupported = "PUT"
+	httpPatch  httpMethodSupported = "PATCH"
 	httpDelete httpMethodSupported = "DELETE"
 )
 
@@ -30,6 +37,51 @@ type ClientOpenAPI interface {
 	List(resource SpecResource, responsePayload interface{}, parentIDs ...string) (*http.Response, error)
 }
 
+// contextClientOpenAPI defines the behaviour expected from the clients able to make the API calls with the context of the
+// resource operation they are made for, which is not part of the ClientOpenAPI interface. The in-flight API calls and the
+// waits between retries are aborted once the context is done (e.g. the operation timed out or terraform was interrupted)
+type contextClientOpenAPI interface {
+	PostContext(ctx context.Context, resource SpecResource, requestPayload interface{}, responsePayload interface{}, parentIDs ...string) (*http.Response, error)
+	PutContext(ctx context.Context, resource SpecResource, id string, requestPayload interface{}, responsePayload interface{}, parentIDs ...string) (*http.Response, error)
+	GetContext(ctx context.Context, resource SpecResource, id string, responsePayload interface{}, parentIDs ...string) (*http.Response, error)
+	DeleteContext(ctx context.Context, resource SpecResource, id string, parentIDs ...string) (*http.Response, error)
+	ListContext(ctx context.Context, resource SpecResource, responsePayload interface{}, parentIDs ...string) (*http.Response, error)
+}
+
+// contextClient returns the given client as a contextClientOpenAPI. The API calls of the clients that do not support
+// contexts are made regardless of the context passed in
+func contextClient(client ClientOpenAPI) contextClientOpenAPI {
+	if c, ok := client.(contextClientOpenAPI); ok {
+		return c
+	}
+	return contextIgnoringClient{client}
+}
+
+// contextIgnoringClient makes the API calls of a client that does not support contexts, ignoring the context passed in
+type contextIgnoringClient struct {
+	ClientOpenAPI
+}
+
+func (c contextIgnoringClient) PostContext(_ context.Context, resource SpecResource, requestPayload interface{}, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	return c.Post(resource, requestPayload, responsePayload, parentIDs...)
+}
+
+func (c contextIgnoringClient) PutContext(_ context.Context, resource SpecResource, id string, requestPayload interface{}, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	return c.Put(resource, id, requestPayload, responsePayload, parentIDs...)
+}
+
+func (c contextIgnoringClient) GetContext(_ context.Context, resource SpecResource, id string, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	return c.Get(resource, id, responsePayload, parentIDs...)
+}
+
+func (c contextIgnoringClient) DeleteContext(_ context.Context, resource SpecResource, id string, parentIDs ...string) (*http.Response, error) {
+	return c.Delete(resource, id, parentIDs...)
+}
+
+func (c contextIgnoringClient) ListContext(_ context.Context, resource SpecResource, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	return c.List(resource, responsePayload, parentIDs...)
+}
+
 // ProviderClient defines a client that is configured based on the OpenAPI server side documentation
 // The CRUD operations accept an OpenAPI operation which defines among other things the security scheme applicable to
 // the API when making the HTTP requests
@@ -38,82 +90,687 @@ type ProviderClient struct {
 	httpClient                  http_goclient.HttpClientIface
 	providerConfiguration       providerConfiguration
 	apiAuthenticator            specAuthenticator
+	// responseCache is used to cache the responses of List operations during a terraform run. If nil, caching is disabled
+	responseCache *responseCache
+	// etagCache keeps in disk the GET responses of the data sources so they are revalidated, rather than fetched again, in
+	// later terraform runs. If nil, the responses are not cached in disk
+	etagCache *etagCache
+	// conditionalRequests defines whether the GET requests are conditional requests revalidating the responses in the
+	// etagCache, which is only the case for the clients used by the data sources (see withConditionalRequests)
+	conditionalRequests bool
+	// resourceOverrides contains the resources (keyed by resource name) of the OpenAPI document configured via the provider's
+	// swagger_url property. If a resource is found in the map, its paths and operations are used instead of the ones from
+	// the OpenAPI document the provider was built with
+	resourceOverrides map[string]SpecResource
+	// methodOverrideHeader, if set, is the header used to send PUT, PATCH and DELETE requests as POST requests; the original
+	// method is sent as the value of the header
+	methodOverrideHeader string
+	// queryParameters contains the query parameters appended to the URL of the GET and PUT requests made to read and
+	// update resource instances (e.g. the fields to return when refreshing a resource or the fields updated)
+	queryParameters url.Values
+	// retryPolicy defines how the API calls failing with transient errors are retried, unless the operation overrides it.
+	// If nil, the API calls are not retried
+	retryPolicy *retryPolicy
+	// rateLimitMaxWait is the max total time a request can wait to be retried after being rate limited by the API (429
+	// responses). If zero, rate limited requests are not retried
+	rateLimitMaxWait time.Duration
+	// retryBudget is shared with the resources so the time spent waiting between retries counts towards the retry budget
+	// and the apply deadline of the run (see retryBudget). If nil, the waits between retries are not bounded by them
+	retryBudget *retryBudget
+	// sleep is used to wait between retries; time.Sleep is used if nil
+	sleep func(time.Duration)
+	// stopContext is done when terraform interrupts the provider (e.g. Ctrl-C). It is the context of the API calls made
+	// without one (e.g. Post rather than PostContext) and it is merged into the context of the resource operations (see
+	// operationContext). If nil, the API calls made without context can not be cancelled
+	stopContext context.Context
+	// callTracker keeps track of the time taken by the API calls to report the slow ones. If nil, calls are not tracked
+	callTracker *callTracker
+	// apiCallLimiter caps the number of concurrent API calls. If nil, there is no limit
+	apiCallLimiter *apiCallLimiter
+	// credentialHelper supplies the credential headers sent in the API calls that require authentication. If nil, no
+	// credential headers are added
+	credentialHelper *credentialHelper
+	// responseValidation defines whether the successful responses are validated against the schemas documented in the
+	// OpenAPI document, logging a warning for each divergence found (see validateResponse)
+	responseValidation bool
+	// deprecationNotices warns about the resources whose API endpoints are announced as deprecated by the API. If nil, no
+	// warnings are raised
+	deprecationNotices *deprecationNotices
+}
+
+// notifyDeprecation warns about the resource if the given response announces that the endpoint called is deprecated (see
+// deprecationNotices.notify), collecting the warning in the given context so it is reported to the user
+func (o *ProviderClient) notifyDeprecation(ctx context.Context, resourceName string, method httpMethodSupported, resourceURL string, resp *http.Response) {
+	if message := o.deprecationNotices.notify(resourceName, method, resourceURL, resp); message != "" {
+		contextWarnings(ctx).add("%s", message)
+	}
+}
+
+// resolveResource returns the resource the API calls should be made for, which is the override for the given resource
+// if the provider is configured with a different OpenAPI document; or the resource itself otherwise
+func (o *ProviderClient) resolveResource(resource SpecResource) SpecResource {
+	if resourceOverride, exists := o.resourceOverrides[resource.getResourceName()]; exists {
+		return resourceOverride
+	}
+	return resource
 }
 
 // Post performs a POST request to the server API based on the resource configuration and the payload passed in
 func (o *ProviderClient) Post(resource SpecResource, requestPayload interface{}, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	return o.PostContext(o.context(), resource, requestPayload, responsePayload, parentIDs...)
+}
+
+// PostContext performs the POST request as Post does, aborting it once the given context is done
+func (o *ProviderClient) PostContext(ctx context.Context, resource SpecResource, requestPayload interface{}, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	defer o.trackCall(ctx, resource, callOperationCreate, "", httpPost, time.Now())
+	resource = o.resolveResource(resource)
 	resourceURL, err := o.getResourceURL(resource, parentIDs)
 	if err != nil {
 		return nil, err
 	}
 	operation := resource.getResourceOperations().Post
-	return o.performRequest(httpPost, resourceURL, operation, requestPayload, responsePayload)
+	resp, err := o.performRequest(ctx, httpPost, resourceURL, operation, requestPayload, responsePayload)
+	if err == nil {
+		o.validateResponse(resource, httpPost, resourceURL, operation, resp, responsePayload)
+		o.notifyDeprecation(ctx, resource.getResourceName(), httpPost, resourceURL, resp)
+	}
+	return resp, err
 }
 
 // Put performs a PUT request to the server API based on the resource configuration and the payload passed in
 func (o *ProviderClient) Put(resource SpecResource, id string, requestPayload interface{}, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	return o.PutContext(o.context(), resource, id, requestPayload, responsePayload, parentIDs...)
+}
+
+// PutContext performs the PUT request as Put does, aborting it once the given context is done
+func (o *ProviderClient) PutContext(ctx context.Context, resource SpecResource, id string, requestPayload interface{}, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	defer o.trackCall(ctx, resource, callOperationUpdate, id, httpPut, time.Now())
+	resource = o.resolveResource(resource)
 	resourceURL, err := o.getResourceIDURL(resource, parentIDs, id)
 	if err != nil {
 		return nil, err
 	}
+	resourceURL = o.appendQueryParameters(resourceURL)
 	operation := resource.getResourceOperations().Put
-	return o.performRequest(httpPut, resourceURL, operation, requestPayload, responsePayload)
+	resp, err := o.performRequest(ctx, httpPut, resourceURL, operation, requestPayload, responsePayload)
+	if err == nil {
+		o.validateResponse(resource, httpPut, resourceURL, operation, resp, responsePayload)
+		o.notifyDeprecation(ctx, resource.getResourceName(), httpPut, resourceURL, resp)
+	}
+	return resp, err
+}
+
+// Patch performs a PATCH request to the server API sending the given JSON Patch (RFC 6902) operations, which describe the
+// changes to apply to the resource instance
+func (o *ProviderClient) Patch(ctx context.Context, resource SpecResource, id string, operations []jsonPatchOperation, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	defer o.trackCall(ctx, resource, callOperationUpdate, id, httpPatch, time.Now())
+	resource = o.resolveResource(resource)
+	resourceURL, err := o.getResourceIDURL(resource, parentIDs, id)
+	if err != nil {
+		return nil, err
+	}
+	resourceURL = o.appendQueryParameters(resourceURL)
+	operation := resource.getResourceOperations().Put
+	resp, err := o.performRequest(ctx, httpPatch, resourceURL, operation, operations, responsePayload)
+	if err == nil {
+		o.validateResponse(resource, httpPatch, resourceURL, operation, resp, responsePayload)
+		o.notifyDeprecation(ctx, resource.getResourceName(), httpPatch, resourceURL, resp)
+	}
+	return resp, err
 }
 
 // Get performs a GET request to the server API based on the resource configuration and the resource instance id passed in
 func (o *ProviderClient) Get(resource SpecResource, id string, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	return o.GetContext(o.context(), resource, id, responsePayload, parentIDs...)
+}
+
+// GetContext performs the GET request as Get does, aborting it once the given context is done
+func (o *ProviderClient) GetContext(ctx context.Context, resource SpecResource, id string, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	defer o.trackCall(ctx, resource, callOperationRead, id, httpGet, time.Now())
+	resource = o.resolveResource(resource)
 	resourceURL, err := o.getResourceIDURL(resource, parentIDs, id)
 	if err != nil {
 		return nil, err
 	}
+	resourceURL = o.appendQueryParameters(resourceURL)
 	operation := resource.getResourceOperations().Get
-	return o.performRequest(httpGet, resourceURL, operation, nil, responsePayload)
+	var resp *http.Response
+	if o.conditionalRequests {
+		resp, err = o.performConditionalRequest(ctx, resourceURL, operation, responsePayload)
+	} else {
+		resp, err = o.performRequest(ctx, httpGet, resourceURL, operation, nil, responsePayload)
+	}
+	if err == nil {
+		o.validateResponse(resource, httpGet, resourceURL, operation, resp, responsePayload)
+		o.notifyDeprecation(ctx, resource.getResourceName(), httpGet, resourceURL, resp)
+	}
+	return resp, err
 }
 
-// List performs a GET request to the root level endpoint of the resource (e,g: GET /v1/groups)
+// List performs a GET request to the root level endpoint of the resource (e.g. GET /v1/groups)
 func (o *ProviderClient) List(resource SpecResource, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	return o.ListContext(o.context(), resource, responsePayload, parentIDs...)
+}
+
+// ListContext performs the GET request as List does, aborting it once the given context is done
+func (o *ProviderClient) ListContext(ctx context.Context, resource SpecResource, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	defer o.trackCall(ctx, resource, callOperationList, "", httpGet, time.Now())
+	resource = o.resolveResource(resource)
 	resourceURL, err := o.getResourceURL(resource, parentIDs)
 	if err != nil {
 		return nil, err
 	}
 	operation := resource.getResourceOperations().List
-	return o.performRequest(httpGet, resourceURL, operation, nil, responsePayload)
+	var resp *http.Response
+	switch {
+	case o.responseCache != nil:
+		resp, err = o.performCachedRequest(ctx, resourceURL, operation, responsePayload)
+	case o.conditionalRequests:
+		resp, err = o.performConditionalRequest(ctx, resourceURL, operation, responsePayload)
+	default:
+		resp, err = o.performRequest(ctx, httpGet, resourceURL, operation, nil, responsePayload)
+	}
+	if err == nil {
+		o.validateResponse(resource, httpGet, resourceURL, operation, resp, responsePayload)
+		o.notifyDeprecation(ctx, resource.getResourceName(), httpGet, resourceURL, resp)
+	}
+	return resp, err
 }
 
 // Delete performs a DELETE request to the server API based on the resource configuration and the resource instance id passed in
 func (o *ProviderClient) Delete(resource SpecResource, id string, parentIDs ...string) (*http.Response, error) {
+	return o.DeleteContext(o.context(), resource, id, parentIDs...)
+}
+
+// DeleteContext performs the DELETE request as Delete does, aborting it once the given context is done
+func (o *ProviderClient) DeleteContext(ctx context.Context, resource SpecResource, id string, parentIDs ...string) (*http.Response, error) {
+	defer o.trackCall(ctx, resource, callOperationDelete, id, httpDelete, time.Now())
+	resource = o.resolveResource(resource)
 	resourceURL, err := o.getResourceIDURL(resource, parentIDs, id)
 	if err != nil {
 		return nil, err
 	}
 	operation := resource.getResourceOperations().Delete
-	return o.performRequest(httpDelete, resourceURL, operation, nil, nil)
+	resp, err := o.performRequest(ctx, httpDelete, resourceURL, operation, nil, nil)
+	if err == nil {
+		o.notifyDeprecation(ctx, resource.getResourceName(), httpDelete, resourceURL, resp)
+	}
+	return resp, err
 }
 
-func (o *ProviderClient) performRequest(method httpMethodSupported, resourceURL string, operation *specResourceOperation, requestPayload interface{}, responsePayload interface{}) (*http.Response, error) {
-	reqContext, err := o.apiAuthenticator.prepareAuth(resourceURL, operation.SecuritySchemes, o.providerConfiguration)
+// StreamEvents performs a GET request to the events path of the resource instance (e.g. GET /v1/groups/{id}/events) and
+// calls onEvent with each of the events of the NDJSON stream returned as soon as they are received. The request is
+// authenticated like the resource GET operation and returns once the API closes the stream or the context is done
+func (o *ProviderClient) StreamEvents(ctx context.Context, resource SpecResource, id string, eventsPath string, onEvent func(event json.RawMessage), parentIDs ...string) error {
+	resource = o.resolveResource(resource)
+	resourceURL, err := o.getResourceIDURL(resource, parentIDs, id)
+	if err != nil {
+		return err
+	}
+	streamClient, ok := o.httpClient.(httpStreamClient)
+	if !ok {
+		return fmt.Errorf("streamed responses not supported by the HTTP client")
+	}
+	eventsURL := fmt.Sprintf("%s/%s", strings.TrimRight(resourceURL, "/"), strings.TrimLeft(eventsPath, "/"))
+	reqContext, err := o.prepareRequest(httpGet, eventsURL, resource.getResourceOperations().Get)
+	if err != nil {
+		return err
+	}
+	reqContext.headers[acceptHeader] = ndjsonContentType
+	if err := o.signRequest(reqContext, httpGet, nil); err != nil {
+		return err
+	}
+	resp, err := streamClient.GetStream(ctx, reqContext.url, reqContext.headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned a non expected status code %d", reqContext.url, resp.StatusCode)
+	}
+	return readNDJSONEvents(resp.Body, onEvent)
+}
+
+// GetAsyncOperation performs a GET request to the URL of the asynchronous operation returned by the API for the resource
+// (e.g. in the Location header of a 202 response), authenticated as the resource instance GET operation. Relative URLs
+// are resolved against the resource URL, and absolute URLs must point at the same host so the credentials are never
+// sent elsewhere
+func (o *ProviderClient) GetAsyncOperation(ctx context.Context, resource SpecResource, operationURL string, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	defer o.trackCall(ctx, resource, callOperationRead, "", httpGet, time.Now())
+	resource = o.resolveResource(resource)
+	resourceURL, err := o.getResourceURL(resource, parentIDs)
 	if err != nil {
 		return nil, err
 	}
-	o.appendOperationHeaders(operation.HeaderParameters, o.providerConfiguration, reqContext.headers)
-	log.Printf("[DEBUG] Performing %s %s", method, reqContext.url)
+	baseURL, err := url.Parse(resourceURL)
+	if err != nil {
+		return nil, err
+	}
+	relativeURL, err := url.Parse(operationURL)
+	if err != nil {
+		return nil, fmt.Errorf("asynchronous operation URL '%s' is not valid: %s", operationURL, err)
+	}
+	resolvedURL := baseURL.ResolveReference(relativeURL)
+	if resolvedURL.Host != baseURL.Host {
+		return nil, fmt.Errorf("asynchronous operation URL '%s' does not belong to the API host '%s'", operationURL, baseURL.Host)
+	}
+	// the credentials are sent along with the request, so they must not be sent over a different protocol (e.g. plain
+	// http when the API is called over https)
+	if resolvedURL.Scheme != baseURL.Scheme {
+		return nil, fmt.Errorf("asynchronous operation URL '%s' does not use the API protocol '%s'", operationURL, baseURL.Scheme)
+	}
+	return o.performRequest(ctx, httpGet, resolvedURL.String(), resource.getResourceOperations().Get, nil, responsePayload)
+}
 
-	userAgentHeader := version.BuildUserAgent(runtime.GOOS, runtime.GOARCH)
-	o.appendUserAgentHeader(reqContext.headers, userAgentHeader)
+// CallCleanupOperation calls the operation that cleans up the resource instance with the given id after its create
+// failed (see the x-terraform-on-failure-cleanup extension), e.g. POST /v1/resource/{id}/abort. The request is
+// authenticated as the cleanup operation is documented and sent without body, since cleanup operations take no payload
+// and may respond without one either (e.g. 204 No Content)
+func (o *ProviderClient) CallCleanupOperation(ctx context.Context, resource SpecResource, id string, cleanup *onFailureCleanupOperation, parentIDs ...string) (*http.Response, error) {
+	defer o.trackCall(ctx, resource, callOperationCreate, id, cleanup.method, time.Now())
+	resource = o.resolveResource(resource)
+	resourceURL, err := o.getResourceIDURL(resource, parentIDs, id)
+	if err != nil {
+		return nil, err
+	}
+	resourceURL = fmt.Sprintf("%s%s", resourceURL, cleanup.subPath)
+	resp, err := o.sendCleanupRequest(ctx, cleanup, resourceURL)
+	if err == nil {
+		o.notifyDeprecation(ctx, resource.getResourceName(), cleanup.method, resourceURL, resp)
+	}
+	return resp, err
+}
 
-	o.logHeadersSafely(reqContext.headers)
+// sendCleanupRequest sends the request of the given cleanup operation without body if the HTTP client supports it, which
+// is not retried; otherwise, or if the cleanup operation is a DELETE, the request is performed as any other request
+func (o *ProviderClient) sendCleanupRequest(ctx context.Context, cleanup *onFailureCleanupOperation, resourceURL string) (*http.Response, error) {
+	bodylessClient, ok := o.httpClientFor(ctx).(httpBodylessClient)
+	if !ok || cleanup.method == httpDelete {
+		return o.performRequest(ctx, cleanup.method, resourceURL, cleanup.operation, nil, nil)
+	}
+	reqContext, err := o.prepareRequest(cleanup.method, resourceURL, cleanup.operation)
+	if err != nil {
+		return nil, err
+	}
+	if o.responseCache != nil {
+		o.responseCache.invalidate()
+	}
+	if err := o.signRequest(reqContext, cleanup.method, nil); err != nil {
+		return nil, err
+	}
+	o.apiCallLimiter.acquire()
+	defer o.apiCallLimiter.release()
+	resp, _, err := bodylessClient.SendWithoutBody(string(cleanup.method), reqContext.url, reqContext.headers)
+	return resp, err
+}
+
+func (o *ProviderClient) performRequest(ctx context.Context, method httpMethodSupported, resourceURL string, operation *specResourceOperation, requestPayload interface{}, responsePayload interface{}) (*http.Response, error) {
+	reqContext, err := o.prepareRequest(method, resourceURL, operation)
+	if err != nil {
+		return nil, err
+	}
+
+	if method != httpGet && o.responseCache != nil {
+		o.responseCache.invalidate()
+	}
+
+	// The response is read raw and decoded afterwards keeping the numbers as json.Number, see decodeJSONPayload
+	resp, rawResponsePayload, err := o.sendRequestWithFailover(ctx, method, resourceURL, operation, reqContext, requestPayload)
+	if err != nil {
+		return resp, err
+	}
+	return resp, decodeJSONPayload(rawResponsePayload, responsePayload)
+}
+
+// sendRequestWithFailover sends the request and, if the API rejects the credentials (401 response) and any of the
+// security definitions used supports a secondary api key (or the credentials were reused from a previous request and can
+// be renewed), sends it again authenticated with the secondary api key (or the renewed credentials)
+func (o *ProviderClient) sendRequestWithFailover(ctx context.Context, method httpMethodSupported, resourceURL string, operation *specResourceOperation, reqContext *authContext, requestPayload interface{}) (*http.Response, json.RawMessage, error) {
+	resp, rawResponsePayload, err := o.sendRequestWithRetries(ctx, method, reqContext, operation, requestPayload)
+	if resp == nil || resp.StatusCode != http.StatusUnauthorized || !reqContext.failoverCredentials() {
+		return resp, rawResponsePayload, err
+	}
+	log.Printf("[WARN] %s %s was rejected with the credentials provided (status code %d), retrying with the secondary api key or renewed credentials", method, reqContext.url, resp.StatusCode)
+	reqContext, err = o.prepareRequest(method, resourceURL, operation)
+	if err != nil {
+		return nil, nil, err
+	}
+	return o.sendRequestWithRetries(ctx, method, reqContext, operation, requestPayload)
+}
+
+// sendRequestWithRetries sends the request retrying it, as configured in the operation or the service retry policy, while
+// it fails with transient errors. The service retry policy only applies to idempotent requests (GET, PUT and DELETE), so
+// other requests (e.g. POST requests creating resources) are only retried if the operation or its resource opts in via
+// the x-terraform-retry extension. Rate limited requests (429 responses) are retried once the time requested by the API in
+// the Retry-After header has passed, as long as the total time waited does not exceed the max rate limit wait nor the time
+// left in the retry budget and until the apply deadline of the run. The waits between transient failure retries are capped
+// by the retry budget and the apply deadline, and the request is no longer retried once there is no time left. The
+// response of the last attempt is returned
+func (o *ProviderClient) sendRequestWithRetries(ctx context.Context, method httpMethodSupported, reqContext *authContext, operation *specResourceOperation, requestPayload interface{}) (*http.Response, json.RawMessage, error) {
+	policy := operation.Retry
+	if policy == nil && isIdempotentMethod(method) {
+		policy = o.retryPolicy
+	}
+	maxAttempts := policy.getMaxAttempts()
+	var rateLimitWait time.Duration
+	for attempt := 1; ; {
+		resp, rawResponsePayload, err := o.sendRequest(ctx, method, reqContext, requestPayload)
+		if ctx.Err() != nil {
+			log.Printf("[WARN] %s %s was cancelled", method, reqContext.url)
+			return nil, nil, contextError(ctx)
+		}
+		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			wait := parseRetryAfter(resp.Header.Get(retryAfterHeader), time.Now())
+			if rateLimitWait+wait > o.rateLimitMaxWait {
+				log.Printf("[WARN] %s %s was rate limited and waiting %s more would exceed the max rate limit wait (%s), giving up", method, reqContext.url, wait, o.rateLimitMaxWait)
+				return resp, rawResponsePayload, err
+			}
+			if budgetWait, budgetErr := o.retryBudget.timeoutFor(wait); budgetErr != nil || budgetWait < wait {
+				log.Printf("[WARN] %s %s was rate limited and waiting %s more would exceed the retry budget or the apply deadline, giving up", method, reqContext.url, wait)
+				return resp, rawResponsePayload, err
+			}
+			rateLimitWait += wait
+			log.Printf("[WARN] %s %s was rate limited (status code %d), retrying in %s", method, reqContext.url, resp.StatusCode, wait)
+			if err := o.waitWithinBudget(ctx, wait); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		if attempt >= maxAttempts || !isTransientFailure(resp, err) {
+			return resp, rawResponsePayload, err
+		}
+		backoff, budgetErr := o.retryBudget.timeoutFor(policy.backoff(attempt))
+		if budgetErr != nil {
+			log.Printf("[WARN] %s %s failed with a transient error (%s) and can not be retried: %s", method, reqContext.url, describeFailure(resp, err), budgetErr)
+			return resp, rawResponsePayload, err
+		}
+		log.Printf("[WARN] %s %s failed with a transient error (%s), retrying in %s (attempt %d of %d)", method, reqContext.url, describeFailure(resp, err), backoff, attempt+1, maxAttempts)
+		if err := o.waitWithinBudget(ctx, backoff); err != nil {
+			return nil, nil, err
+		}
+		attempt++
+	}
+}
+
+// trackCall records the time taken by the API call made for the given resource operation, which started at the given time,
+// both across the run and for the resource operation the given context belongs to (if any)
+func (o *ProviderClient) trackCall(ctx context.Context, resource SpecResource, operation, id string, method httpMethodSupported, start time.Time) {
+	if o.callTracker == nil {
+		return
+	}
+	call := apiCall{resourceName: resource.getResourceName(), operation: operation, id: id, method: method, duration: time.Since(start)}
+	o.callTracker.track(call)
+	operationCallTracker(ctx).record(call)
+}
+
+// waitWithinBudget waits for the given duration before retrying a request (see wait) and records the time waited as spent
+// from the retry budget
+func (o *ProviderClient) waitWithinBudget(ctx context.Context, duration time.Duration) error {
+	start := time.Now()
+	err := o.wait(ctx, duration)
+	if err != nil {
+		o.retryBudget.consume(time.Since(start))
+		return err
+	}
+	o.retryBudget.consume(duration)
+	return nil
+}
+
+// wait waits for the given duration before retrying a request. errOperationCancelled is returned if the given context is
+// cancelled meanwhile (errOperationTimedOut if it expires, e.g. the resource operation timed out)
+func (o *ProviderClient) wait(ctx context.Context, duration time.Duration) error {
+	if o.sleep != nil {
+		o.sleep(duration)
+		if ctx.Err() != nil {
+			return contextError(ctx)
+		}
+		return nil
+	}
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return contextError(ctx)
+	case <-timer.C:
+		return nil
+	}
+}
+
+// context returns the context that is done when terraform interrupts the provider; context.Background() if the client
+// does not support cancellation
+func (o *ProviderClient) context() context.Context {
+	if o.stopContext == nil {
+		return context.Background()
+	}
+	return o.stopContext
+}
+
+// httpClientFor returns the HTTP client sending the requests made with the given context, which are aborted once the
+// context is done if the HTTP client supports it (see httpContextClient)
+func (o *ProviderClient) httpClientFor(ctx context.Context) http_goclient.HttpClientIface {
+	if contextHTTPClient, ok := o.httpClient.(httpContextClient); ok {
+		return contextHTTPClient.withContext(ctx)
+	}
+	return o.httpClient
+}
 
+// sendRequest signs and sends the request once, returning the raw response payload
+func (o *ProviderClient) sendRequest(ctx context.Context, method httpMethodSupported, reqContext *authContext, requestPayload interface{}) (*http.Response, json.RawMessage, error) {
+	o.apiCallLimiter.acquire()
+	defer o.apiCallLimiter.release()
+	httpClient := o.httpClientFor(ctx)
+	var rawResponsePayload json.RawMessage
+	var resp *http.Response
+	var err error
+	if o.methodOverrideHeader != "" && (method == httpPut || method == httpPatch || method == httpDelete) {
+		log.Printf("[DEBUG] Sending %s %s as a POST request with the '%s' header", method, reqContext.url, o.methodOverrideHeader)
+		reqContext.headers[o.methodOverrideHeader] = string(method)
+		if err := o.signRequest(reqContext, httpPost, requestPayload); err != nil {
+			return nil, nil, err
+		}
+		resp, err = httpClient.PostJson(reqContext.url, reqContext.headers, requestPayload, &rawResponsePayload)
+		return resp, rawResponsePayload, err
+	}
+	if method == httpGet || method == httpDelete {
+		requestPayload = nil
+	}
+	if err := o.signRequest(reqContext, method, requestPayload); err != nil {
+		return nil, nil, err
+	}
 	switch method {
 	case httpPost:
-		return o.httpClient.PostJson(reqContext.url, reqContext.headers, requestPayload, &responsePayload)
+		resp, err = httpClient.PostJson(reqContext.url, reqContext.headers, requestPayload, &rawResponsePayload)
 	case httpPut:
-		return o.httpClient.PutJson(reqContext.url, reqContext.headers, requestPayload, &responsePayload)
+		resp, err = httpClient.PutJson(reqContext.url, reqContext.headers, requestPayload, &rawResponsePayload)
+	case httpPatch:
+		patchClient, ok := httpClient.(httpPatchClient)
+		if !ok {
+			return nil, nil, fmt.Errorf("method '%s' not supported by the HTTP client", method)
+		}
+		resp, err = patchClient.PatchJson(reqContext.url, reqContext.headers, requestPayload, &rawResponsePayload)
 	case httpGet:
-		return o.httpClient.Get(reqContext.url, reqContext.headers, &responsePayload)
+		if conditionalClient, ok := httpClient.(httpConditionalClient); ok && reqContext.headers[ifNoneMatchHeader] != "" {
+			return conditionalClient.GetConditional(reqContext.url, reqContext.headers)
+		}
+		resp, err = httpClient.Get(reqContext.url, reqContext.headers, &rawResponsePayload)
 	case httpDelete:
-		return o.httpClient.Delete(reqContext.url, reqContext.headers)
+		resp, err = httpClient.Delete(reqContext.url, reqContext.headers)
+	default:
+		return nil, nil, fmt.Errorf("method '%s' not supported", method)
 	}
-	return nil, fmt.Errorf("method '%s' not supported", method)
+	return resp, rawResponsePayload, err
+}
+
+// performCachedRequest performs a GET request making use of the response cache, so requests with the same URL and headers
+// are only sent once to the API during the terraform run
+func (o *ProviderClient) performCachedRequest(ctx context.Context, resourceURL string, operation *specResourceOperation, responsePayload interface{}) (*http.Response, error) {
+	reqContext, err := o.prepareRequest(httpGet, resourceURL, operation)
+	if err != nil {
+		return nil, err
+	}
+	key := o.responseCache.key(httpGet, reqContext.url, reqContext.headers)
+	return o.responseCache.getOrFetch(key, responsePayload, func() (*http.Response, error) {
+		var resp *http.Response
+		var rawResponsePayload json.RawMessage
+		if o.conditionalRequests {
+			resp, rawResponsePayload, err = o.sendConditionalRequest(ctx, resourceURL, operation, reqContext)
+		} else {
+			resp, rawResponsePayload, err = o.sendRequestWithFailover(ctx, httpGet, resourceURL, operation, reqContext, nil)
+		}
+		if err != nil {
+			return resp, err
+		}
+		return resp, decodeJSONPayload(rawResponsePayload, responsePayload)
+	})
+}
+
+// performConditionalRequest performs a GET request revalidating the response cached in disk for the same URL, if any
+func (o *ProviderClient) performConditionalRequest(ctx context.Context, resourceURL string, operation *specResourceOperation, responsePayload interface{}) (*http.Response, error) {
+	reqContext, err := o.prepareRequest(httpGet, resourceURL, operation)
+	if err != nil {
+		return nil, err
+	}
+	resp, rawResponsePayload, err := o.sendConditionalRequest(ctx, resourceURL, operation, reqContext)
+	if err != nil {
+		return resp, err
+	}
+	return resp, decodeJSONPayload(rawResponsePayload, responsePayload)
+}
+
+// sendConditionalRequest sends the GET request with the ETag of the response cached for the URL in the If-None-Match
+// header. If the API replies that the response was not modified (304), the cached response is returned as a 200 OK
+// response. Otherwise, the successful responses are cached if they contain an ETag header
+func (o *ProviderClient) sendConditionalRequest(ctx context.Context, resourceURL string, operation *specResourceOperation, reqContext *authContext) (*http.Response, json.RawMessage, error) {
+	cacheKey := reqContext.url
+	var cachedEntry *etagCacheEntry
+	if _, ok := o.httpClient.(httpConditionalClient); ok {
+		cachedEntry = o.etagCache.get(cacheKey)
+	}
+	if cachedEntry != nil {
+		reqContext.headers[ifNoneMatchHeader] = cachedEntry.ETag
+	}
+	resp, rawResponsePayload, err := o.sendRequestWithFailover(ctx, httpGet, resourceURL, operation, reqContext, nil)
+	if err != nil || resp == nil {
+		return resp, rawResponsePayload, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cachedEntry != nil:
+		log.Printf("[DEBUG] GET %s was not modified (ETag %s), re-using the response cached in disk", cacheKey, cachedEntry.ETag)
+		resp.StatusCode = http.StatusOK
+		resp.Status = fmt.Sprintf("%d %s", http.StatusOK, http.StatusText(http.StatusOK))
+		resp.Body = ioutil.NopCloser(bytes.NewReader(cachedEntry.Payload))
+		return resp, cachedEntry.Payload, nil
+	case resp.StatusCode == http.StatusOK && resp.Header.Get(etagHeader) != "":
+		if err := o.etagCache.put(cacheKey, etagCacheEntry{ETag: resp.Header.Get(etagHeader), Payload: rawResponsePayload}); err != nil {
+			log.Printf("[WARN] %s", err)
+		}
+	case resp.StatusCode == http.StatusOK && cachedEntry != nil:
+		o.etagCache.remove(cacheKey)
+	}
+	return resp, rawResponsePayload, nil
+}
+
+// prepareRequest returns the request context containing the final URL and the headers (auth, operation and user agent
+// headers) that should be sent for the given operation
+func (o *ProviderClient) prepareRequest(method httpMethodSupported, resourceURL string, operation *specResourceOperation) (*authContext, error) {
+	reqContext, err := o.prepareAuth(resourceURL, operation)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.appendCredentialHelperHeaders(reqContext, operation); err != nil {
+		return nil, err
+	}
+	o.appendOperationHeaders(operation.HeaderParameters, o.providerConfiguration, reqContext.headers)
+	log.Printf("[DEBUG] Performing %s %s", method, reqContext.url)
+
+	userAgentHeader := version.BuildUserAgent(runtime.GOOS, runtime.GOARCH)
+	o.appendUserAgentHeader(reqContext.headers, userAgentHeader)
+
+	if method == httpPatch {
+		reqContext.headers[contentTypeHeader] = jsonPatchContentType
+	}
+
+	o.logHeadersSafely(reqContext.headers)
+	return reqContext, nil
+}
+
+// prepareAuth returns the auth context for the given operation, which is authenticated with the security schemes declared
+// in the operation or the global ones otherwise. Operations overriding the global security with no security at all are
+// called without authentication
+func (o *ProviderClient) prepareAuth(resourceURL string, operation *specResourceOperation) (*authContext, error) {
+	if operation.AnonymousAccess {
+		log.Printf("[DEBUG] operation security for '%s' overrides the global security with no security schemes, skipping authentication", resourceURL)
+		return &authContext{headers: map[string]string{}, url: resourceURL}, nil
+	}
+	return o.apiAuthenticator.prepareAuth(resourceURL, operation.SecuritySchemes, o.providerConfiguration)
+}
+
+// appendCredentialHelperHeaders adds the headers returned by the credential helper (if configured) to the request, unless
+// the operation is called without authentication. If the headers were reused from a previous request, they are discarded
+// when the API rejects them so the request is sent again with the headers of a new execution of the credential helper
+func (o *ProviderClient) appendCredentialHelperHeaders(reqContext *authContext, operation *specResourceOperation) error {
+	if o.credentialHelper == nil || operation.AnonymousAccess {
+		return nil
+	}
+	headers, cached, err := o.credentialHelper.getHeaders()
+	if err != nil {
+		return &AuthConfigError{Err: err}
+	}
+	for headerName, headerValue := range headers {
+		reqContext.headers[headerName] = headerValue
+	}
+	if cached {
+		reqContext.failovers = append(reqContext.failovers, o.credentialHelper.invalidate)
+	}
+	return nil
+}
+
+// signRequest signs the request if any of the authenticators requires so (e.g. AWS Signature Version 4 or HMAC). This is
+// done once the final method, URL and headers of the request are known
+func (o *ProviderClient) signRequest(reqContext *authContext, method httpMethodSupported, requestPayload interface{}) error {
+	for _, signer := range reqContext.signers {
+		if err := signer.sign(string(method), reqContext.url, reqContext.headers, requestPayload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withHeaderOverrides returns a copy of the ProviderClient where the given header values (keyed by the header terraform
+// configuration name) take preference over the values configured in the provider
+func (o *ProviderClient) withHeaderOverrides(headerOverrides map[string]string) *ProviderClient {
+	client := *o
+	client.providerConfiguration.Headers = map[string]string{}
+	for headerName, headerValue := range o.providerConfiguration.Headers {
+		client.providerConfiguration.Headers[headerName] = headerValue
+	}
+	for headerName, headerValue := range headerOverrides {
+		client.providerConfiguration.Headers[headerName] = headerValue
+	}
+	return &client
+}
+
+// withConditionalRequests returns a copy of the ProviderClient whose GET requests revalidate the responses cached in disk
+// (see etagCache). If the cache is not enabled, the copy behaves the same as the ProviderClient
+func (o *ProviderClient) withConditionalRequests() *ProviderClient {
+	client := *o
+	client.conditionalRequests = o.etagCache != nil
+	return &client
+}
+
+// withQueryParameters returns a copy of the ProviderClient that appends the given query parameters to the URL of the
+// GET and PUT requests made to read and update resource instances
+func (o *ProviderClient) withQueryParameters(queryParameters url.Values) *ProviderClient {
+	client := *o
+	client.queryParameters = queryParameters
+	return &client
+}
+
+func (o *ProviderClient) appendQueryParameters(resourceURL string) string {
+	if len(o.queryParameters) == 0 {
+		return resourceURL
+	}
+	return fmt.Sprintf("%s?%s", resourceURL, o.queryParameters.Encode())
 }
 
 func (o *ProviderClient) appendUserAgentHeader(headers map[string]string, value string) {
@@ -145,22 +802,12 @@ func (o ProviderClient) appendOperationHeaders(operationHeaders []SpecHeaderPara
 
 func (o ProviderClient) getResourceURL(resource SpecResource, parentIDs []string) (string, error) {
 	var host string
-	var err error
 
-	isMultiRegion, _, regions, err := o.openAPIBackendConfiguration.isMultiRegion()
+	region, err := o.getRegion()
 	if err != nil {
 		return "", err
 	}
-	if isMultiRegion {
-		// get region value provided by user in the terraform configuration file
-		region := o.providerConfiguration.getRegion()
-		// otherwise, if not provided falling back to the default value specified in the service provider swagger file
-		if region == "" {
-			region, err = o.openAPIBackendConfiguration.getDefaultRegion(regions)
-			if err != nil {
-				return "", err
-			}
-		}
+	if region != "" {
 		host, err = o.openAPIBackendConfiguration.getHostByRegion(region)
 		if err != nil {
 			return "", err
@@ -173,7 +820,11 @@ func (o ProviderClient) getResourceURL(resource SpecResource, parentIDs []string
 	}
 
 	basePath := o.openAPIBackendConfiguration.getBasePath()
-	resourceRelativePath, err := resource.getResourcePath(parentIDs)
+	escapedParentIDs := make([]string, len(parentIDs))
+	for idx, parentID := range parentIDs {
+		escapedParentIDs[idx] = o.escapePathParameterValue(parentID)
+	}
+	resourceRelativePath, err := resource.getResourcePath(escapedParentIDs)
 	if err != nil {
 		return "", err
 	}
@@ -188,39 +839,59 @@ func (o ProviderClient) getResourceURL(resource SpecResource, parentIDs []string
 		host = hostOverride
 	}
 
-	if endPointHost := o.providerConfiguration.getEndPoint(resource.getResourceName()); endPointHost != "" {
+	// The scheme is resolved following the same precedence as the host: endpoint override, resource override and lastly
+	// the global schemes specified in the swagger file
+	scheme, err := resource.getScheme()
+	if err != nil {
+		return "", err
+	}
+
+	if endPoint := o.providerConfiguration.getEndPoint(resource.getResourceName()); endPoint != "" {
+		endPointScheme, endPointHost := splitEndPoint(endPoint)
 		log.Printf("[INFO] resource '%s' is configured with endpoint override, API calls will be made against '%s' instead of '%s'", resourceRelativePath, endPointHost, host)
 		host = endPointHost
+		if endPointScheme != "" {
+			scheme = endPointScheme
+		}
 	}
 
 	if host == "" || resourceRelativePath == "" {
 		return "", fmt.Errorf("host and path are mandatory attributes to get the resource URL - host['%s'], path['%s']", host, resourceRelativePath)
 	}
 
-	// TODO: use resource operation schemes if specified
-	defaultScheme, err := o.openAPIBackendConfiguration.getHTTPScheme()
+	if scheme == "" {
+		scheme, err = o.openAPIBackendConfiguration.getHTTPScheme()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// The base path comes straight from the spec so any duplicate slashes are removed; whereas the resource path might
+	// contain raw slashes as part of the parent ids, hence only the slashes where both are joined are normalized
+	path := joinURLPath(normalizeURLPath(basePath), resourceRelativePath)
+	return fmt.Sprintf("%s://%s%s", scheme, host, path), nil
+}
+
+// getRegion returns the region the API calls are made against if the backend is multi-region; an empty string otherwise.
+// The region configured by the user in the provider takes preference over the default region specified in the swagger file.
+func (o ProviderClient) getRegion() (string, error) {
+	isMultiRegion, _, regions, err := o.openAPIBackendConfiguration.isMultiRegion()
 	if err != nil {
 		return "", err
 	}
-
-	path := resourceRelativePath
-	if strings.Index(resourceRelativePath, "/") != 0 {
-		path = fmt.Sprintf("/%s", resourceRelativePath)
+	if !isMultiRegion {
+		return "", nil
 	}
-
-	if basePath != "" && basePath != "/" {
-		if strings.Index(basePath, "/") == 0 {
-			return fmt.Sprintf("%s://%s%s%s", defaultScheme, host, basePath, path), nil
-		}
-		return fmt.Sprintf("%s://%s/%s%s", defaultScheme, host, basePath, path), nil
+	// get region value provided by user in the terraform configuration file
+	region := o.providerConfiguration.getRegion()
+	// otherwise, if not provided falling back to the default value specified in the service provider swagger file
+	if region == "" {
+		return o.openAPIBackendConfiguration.getDefaultRegion(regions)
 	}
-	return fmt.Sprintf("%s://%s%s", defaultScheme, host, path), nil
+	return region, nil
 }
 
 func (o ProviderClient) getResourceIDURL(resource SpecResource, parentIDs []string, id string) (string, error) {
-	if strings.Contains(id, "/") {
-		return "", fmt.Errorf("instance ID (%s) contains not supported characters (forward slashes)", id)
-	}
 	url, err := o.getResourceURL(resource, parentIDs)
 	if err != nil {
 		return "", err
@@ -228,8 +899,35 @@ func (o ProviderClient) getResourceIDURL(resource SpecResource, parentIDs []stri
 	if id == "" {
 		return "", fmt.Errorf("could not build the resourceIDURL: required instance id value is missing")
 	}
+	id = o.escapePathParameterValue(id)
 	if strings.HasSuffix(url, "/") {
 		return fmt.Sprintf("%s%s", url, id), nil
 	}
 	return fmt.Sprintf("%s/%s", url, id), nil
 }
+
+// escapePathParameterValue URL encodes the given path parameter value (e.g. instance and parent ids) so it can be safely
+// used as a path segment. Only the characters allowed in path segments by RFC 3986 are kept as is (unreserved characters,
+// sub-delimiters like ';', '=' and ',' used by matrix style parameters, ':' and '@'); the rest (e.g. spaces, unicode
+// characters and forward slashes) are percent-encoded. Forward slashes are kept as is if the API expects them raw.
+func (o ProviderClient) escapePathParameterValue(value string) string {
+	allowRawSlashes := o.openAPIBackendConfiguration != nil && o.openAPIBackendConfiguration.allowsRawSlashesInPathParameters()
+	var escaped strings.Builder
+	for _, b := range []byte(value) {
+		if isPathSegmentChar(b) || (b == '/' && allowRawSlashes) {
+			escaped.WriteByte(b)
+			continue
+		}
+		fmt.Fprintf(&escaped, "%%%02X", b)
+	}
+	return escaped.String()
+}
+
+// isPathSegmentChar returns true if the given character can be used as is in a path segment as per RFC 3986 (pchar)
+func isPathSegmentChar(b byte) bool {
+	switch {
+	case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9':
+		return true
+	}
+	return strings.IndexByte("-._~!$&'()*+,;=:@", b) >= 0
+}
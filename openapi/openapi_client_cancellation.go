@@ -0,0 +1,240 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// errOperationCancelled is returned when terraform is interrupted (e.g. Ctrl-C) while the provider is making API calls,
+// waiting to retry them or polling the resources
+var errOperationCancelled = errors.New("operation cancelled")
+
+// errOperationTimedOut is returned when the timeout of the resource operation (as configured in the timeouts block of the
+// resource) expires while the provider is making API calls, waiting to retry them or polling the resources
+var errOperationTimedOut = errors.New("operation timed out")
+
+// cleanupTimeout is the max time the API calls made to clean up after a resource operation failed can take (see
+// cleanupContext)
+const cleanupTimeout = 1 * time.Minute
+
+// contextError returns the error the operations fail with once the given context is done: errOperationTimedOut if the
+// context deadline expired; errOperationCancelled otherwise
+func contextError(ctx context.Context) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return errOperationTimedOut
+	}
+	return errOperationCancelled
+}
+
+// withStopContext returns a context derived from the given one (e.g. the context of the resource operation, which expires
+// with the operation timeout) that is also cancelled once the stop context is done. Terraform does not cancel the context
+// of the resource operations when it interrupts the provider, hence the stop context needs to be merged into it
+func withStopContext(ctx context.Context, stopContext context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	if stopContext.Done() == nil {
+		return ctx, cancel
+	}
+	go func() {
+		select {
+		case <-stopContext.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// cancellableTransport aborts the in-flight requests as soon as the given context (the stop context of the provider or the
+// context of the resource operation timeout) is done. The requests sent once it is done fail straight away
+type cancellableTransport struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+// newCancellableTransport returns the transport that cancels the requests sent through the given transport when the
+// context is done; http.DefaultTransport is used if the given transport is nil
+func newCancellableTransport(ctx context.Context, next http.RoundTripper) *cancellableTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &cancellableTransport{ctx: ctx, next: next}
+}
+
+// RoundTrip sends the request with a context that is cancelled when either the request context or the stop context is
+// done. The context is kept alive until the body of the response is closed, so it can still be read once RoundTrip returns
+func (t *cancellableTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.ctx.Err() != nil {
+		return nil, contextError(t.ctx)
+	}
+	ctx, cancel := context.WithCancel(req.Context())
+	released := make(chan struct{})
+	go func() {
+		select {
+		case <-t.ctx.Done():
+			cancel()
+		case <-released:
+		}
+	}()
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			close(released)
+			cancel()
+		})
+	}
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		release()
+		if t.ctx.Err() != nil {
+			return nil, contextError(t.ctx)
+		}
+		return nil, err
+	}
+	resp.Body = &cancellableBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// cancellableBody releases the context of the request once the body of the response is closed
+type cancellableBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *cancellableBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
+
+// clientStopContext returns the context that is done when terraform interrupts the provider, if the given client supports
+// cancellation; context.Background() otherwise
+func clientStopContext(client interface{}) context.Context {
+	if providerClient, ok := client.(*ProviderClient); ok {
+		return providerClient.context()
+	}
+	return context.Background()
+}
+
+// operationContext returns the context the API calls of a resource (or data source) operation are made with, derived from
+// the given context terraform passes in to the operation, which expires with the operation timeout configured in the
+// timeouts block of the resource. The returned context is also cancelled once terraform interrupts the provider and it
+// collects the warnings raised during the operation (see operationWarnings). The returned function releases the resources
+// associated with the context and must be called once the operation is done
+func operationContext(ctx context.Context, client interface{}) (context.Context, context.CancelFunc) {
+	return withStopContext(withOperationWarnings(ctx), clientStopContext(client))
+}
+
+// operationDiagnostics returns the error the resource (or data source) operation made with the given context (see
+// operationContext) failed with as diagnostics, along with the warnings raised meanwhile. If terraform interrupted the
+// provider, the error is logged and errOperationCancelled is returned instead. If the operation timeout expired, the error
+// is wrapped in one pointing to the timeouts block of the resource; timeoutKey is empty for the operations that can not be
+// configured with a timeout (e.g. data sources)
+func operationDiagnostics(ctx context.Context, data *schema.ResourceData, timeoutKey string, err error) diag.Diagnostics {
+	switch {
+	case err == nil:
+	case ctx.Err() == context.Canceled && err != errOperationCancelled && err != errOperationTimedOut:
+		log.Printf("[WARN] the operation was cancelled, it failed with: %s", err)
+		err = errOperationCancelled
+	case ctx.Err() == context.DeadlineExceeded && timeoutKey != "":
+		err = &operationTimeoutError{timeoutKey: timeoutKey, timeout: data.Timeout(timeoutKey), err: err}
+	}
+	return append(diag.FromErr(err), contextWarnings(ctx).diagnostics()...)
+}
+
+// cleanupContext returns the context the API calls that clean up after a resource operation failed are made with (e.g.
+// deleting the resource partially created by a create that timed out). It is not bound to the given context of the
+// operation, which may have expired already, but keeps its values (e.g. the warnings collected) and expires after
+// cleanupTimeout or once terraform interrupts the provider. The returned function must be called once the cleanup is done
+func cleanupContext(ctx context.Context, client interface{}) (context.Context, context.CancelFunc) {
+	ctx, cancelTimeout := context.WithTimeout(detachedContext{ctx}, cleanupTimeout)
+	ctx, cancel := withStopContext(ctx, clientStopContext(client))
+	return ctx, func() {
+		cancel()
+		cancelTimeout()
+	}
+}
+
+// detachedContext keeps the values of the given context but it is never done, regardless of whether the given context is
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
+
+// waitForState waits for the resource to reach the target state as stateConf.WaitForState does, but returns
+// errOperationCancelled (or errOperationTimedOut) as soon as the given context is done instead of waiting for the next
+// refresh
+func waitForState(ctx context.Context, stateConf *resource.StateChangeConf) (interface{}, error) {
+	if ctx.Done() == nil {
+		return stateConf.WaitForState()
+	}
+	refresh := stateConf.Refresh
+	stateConf.Refresh = func() (interface{}, string, error) {
+		// makes the state change conf stop refreshing once cancelled
+		if ctx.Err() != nil {
+			return nil, "", contextError(ctx)
+		}
+		return refresh()
+	}
+	type waitResult struct {
+		remoteData interface{}
+		err        error
+	}
+	done := make(chan waitResult, 1)
+	go func() {
+		remoteData, err := stateConf.WaitForState()
+		done <- waitResult{remoteData: remoteData, err: err}
+	}()
+	select {
+	case result := <-done:
+		return result.remoteData, result.err
+	case <-ctx.Done():
+		return nil, contextError(ctx)
+	}
+}
+
+// operationTimeoutError is the error resource operations fail with when the timeout configured for the operation expires,
+// wrapping the error the operation failed with
+type operationTimeoutError struct {
+	timeoutKey string
+	timeout    time.Duration
+	err        error
+}
+
+// Error returns the message pointing to the timeouts block of the resource followed by the error the operation failed
+// with (e.g. the cleanup of the partially created resource failed too), unless the operation just failed because the
+// timeout expired
+func (e *operationTimeoutError) Error() string {
+	msg := fmt.Sprintf("%s operation timed out after %s, the timeout can be increased in the timeouts block of the resource", e.timeoutKey, e.timeout)
+	if errors.Is(e.err, errOperationTimedOut) {
+		return msg
+	}
+	return fmt.Sprintf("%s: %s", msg, e.err)
+}
+
+func (e *operationTimeoutError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,278 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dikhan/http_goclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCancellableProviderClient(apiURL string, stopContext context.Context) *ProviderClient {
+	return &ProviderClient{
+		openAPIBackendConfiguration: newStubBackendConfiguration(strings.TrimPrefix(apiURL, "http://"), "", "http"),
+		httpClient:                  patchableHTTPClient{&http_goclient.HttpClient{HttpClient: &http.Client{Transport: newCancellableTransport(stopContext, nil)}}},
+		providerConfiguration:       providerConfiguration{},
+		apiAuthenticator:            newStubAuthenticator("Authorization", "Bearer secret", nil),
+		stopContext:                 stopContext,
+	}
+}
+
+func TestProviderClient_Cancellation(t *testing.T) {
+	requestReceived := make(chan struct{}, 1)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestReceived <- struct{}{}
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	defer api.Close()
+	resource := &specStubResource{
+		path:                 "/v1/resource",
+		resourceGetOperation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}},
+	}
+
+	t.Run("in-flight API calls are aborted", func(t *testing.T) {
+		stopContext, stop := context.WithCancel(context.Background())
+		client := newCancellableProviderClient(api.URL, stopContext)
+		go func() {
+			<-requestReceived
+			stop()
+		}()
+		start := time.Now()
+		_, err := client.Get(resource, "1234", &map[string]interface{}{})
+		assert.Equal(t, errOperationCancelled, err)
+		assert.True(t, time.Since(start) < 5*time.Second, "the API call should be aborted straight away")
+	})
+
+	t.Run("API calls made once cancelled fail straight away", func(t *testing.T) {
+		stopContext, stop := context.WithCancel(context.Background())
+		stop()
+		client := newCancellableProviderClient(api.URL, stopContext)
+		_, err := client.Get(resource, "1234", &map[string]interface{}{})
+		assert.Equal(t, errOperationCancelled, err)
+		assert.Len(t, requestReceived, 0)
+	})
+
+	t.Run("the wait between retries is aborted", func(t *testing.T) {
+		stopContext, stop := context.WithCancel(context.Background())
+		client := newCancellableProviderClient(api.URL, stopContext)
+		time.AfterFunc(10*time.Millisecond, stop)
+		assert.Equal(t, errOperationCancelled, client.wait(client.context(), time.Hour))
+	})
+
+	t.Run("in-flight API calls made with a context are aborted once it is done", func(t *testing.T) {
+		client := newCancellableProviderClient(api.URL, context.Background())
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-requestReceived
+			cancel()
+		}()
+		start := time.Now()
+		_, err := client.GetContext(ctx, resource, "1234", &map[string]interface{}{})
+		assert.Equal(t, errOperationCancelled, err)
+		assert.True(t, time.Since(start) < 5*time.Second, "the API call should be aborted straight away")
+	})
+
+	t.Run("responses are read normally if not cancelled", func(t *testing.T) {
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id":"1234"}`))
+		}))
+		defer api.Close()
+		client := newCancellableProviderClient(api.URL, context.Background())
+		responsePayload := map[string]interface{}{}
+		_, err := client.Get(resource, "1234", &responsePayload)
+		require.NoError(t, err)
+		assert.Equal(t, "1234", responsePayload["id"])
+	})
+}
+
+func TestWaitForState_Cancellation(t *testing.T) {
+	stopContext, stop := context.WithCancel(context.Background())
+	refreshes := 0
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"pending"},
+		Target:  []string{"deployed"},
+		Refresh: func() (interface{}, string, error) {
+			refreshes++
+			return map[string]interface{}{}, "pending", nil
+		},
+		Timeout:      time.Hour,
+		PollInterval: time.Hour,
+	}
+	time.AfterFunc(10*time.Millisecond, stop)
+	start := time.Now()
+	_, err := waitForState(stopContext, stateConf)
+	assert.Equal(t, errOperationCancelled, err)
+	assert.True(t, time.Since(start) < 5*time.Second, "the polling should be aborted straight away")
+}
+
+func TestOperationContext(t *testing.T) {
+	t.Run("the context is cancelled once the provider is stopped", func(t *testing.T) {
+		stopContext, stop := context.WithCancel(context.Background())
+		ctx, cancel := operationContext(context.Background(), &ProviderClient{stopContext: stopContext})
+		defer cancel()
+		stop()
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("the context should be cancelled once the provider is stopped")
+		}
+	})
+
+	t.Run("the context collects the warnings raised during the operation", func(t *testing.T) {
+		ctx, cancel := operationContext(context.Background(), &clientOpenAPIStub{})
+		defer cancel()
+		require.NotNil(t, contextWarnings(ctx))
+		assert.Nil(t, contextWarnings(context.Background()))
+	})
+
+	t.Run("the context is released once the operation is done", func(t *testing.T) {
+		ctx, cancel := operationContext(context.Background(), &ProviderClient{})
+		cancel()
+		assert.Error(t, ctx.Err())
+	})
+}
+
+func TestOperationDiagnostics(t *testing.T) {
+	newResourceData := func(readTimeout time.Duration) *schema.ResourceData {
+		r := &schema.Resource{Schema: map[string]*schema.Schema{}, Timeouts: &schema.ResourceTimeout{Read: &readTimeout}}
+		return r.Data(nil)
+	}
+	operationErr := errors.New("GET /v1/resource/1234 failed")
+
+	t.Run("errors are returned as is if the operation is not cancelled nor times out", func(t *testing.T) {
+		diags := operationDiagnostics(context.Background(), newResourceData(time.Hour), schema.TimeoutRead, operationErr)
+		assert.EqualError(t, diagnosticsError(diags), "GET /v1/resource/1234 failed")
+		assert.Empty(t, operationDiagnostics(context.Background(), nil, schema.TimeoutRead, nil))
+	})
+
+	t.Run("errors are replaced by errOperationCancelled if the provider was stopped", func(t *testing.T) {
+		stopContext, stop := context.WithCancel(context.Background())
+		ctx, cancel := operationContext(context.Background(), &ProviderClient{stopContext: stopContext})
+		defer cancel()
+		stop()
+		<-ctx.Done()
+		diags := operationDiagnostics(ctx, newResourceData(time.Hour), schema.TimeoutRead, operationErr)
+		assert.Equal(t, errOperationCancelled.Error(), diagnosticsError(diags).Error())
+	})
+
+	t.Run("the API calls and the waits between retries are aborted once the operation timeout expires", func(t *testing.T) {
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(10 * time.Second):
+			}
+		}))
+		defer api.Close()
+		resource := &specStubResource{
+			path:                 "/v1/resource",
+			resourceGetOperation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}},
+		}
+		client := newCancellableProviderClient(api.URL, context.Background())
+		timeoutCtx, cancelTimeout := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancelTimeout()
+		ctx, cancel := operationContext(timeoutCtx, client)
+		defer cancel()
+		start := time.Now()
+		_, err := client.GetContext(ctx, resource, "1234", &map[string]interface{}{})
+		diags := operationDiagnostics(ctx, newResourceData(50*time.Millisecond), schema.TimeoutRead, err)
+		assert.EqualError(t, diagnosticsError(diags), "read operation timed out after 50ms, the timeout can be increased in the timeouts block of the resource")
+		assert.True(t, time.Since(start) < 5*time.Second, "the API call should be aborted once the timeout expires")
+
+		err = client.wait(ctx, time.Hour)
+		diags = operationDiagnostics(ctx, newResourceData(50*time.Millisecond), schema.TimeoutRead, err)
+		assert.EqualError(t, diagnosticsError(diags), "read operation timed out after 50ms, the timeout can be increased in the timeouts block of the resource")
+	})
+
+	t.Run("the error the operation failed with is kept once the operation timeout expires", func(t *testing.T) {
+		operationErr := fmt.Errorf("%s; the cleanup of the partially created resource 'id' failed too and it may need to be deleted manually: DELETE failed", errOperationTimedOut)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		<-ctx.Done()
+		diags := operationDiagnostics(ctx, newResourceData(10*time.Millisecond), schema.TimeoutRead, operationErr)
+		assert.EqualError(t, diagnosticsError(diags), "read operation timed out after 10ms, the timeout can be increased in the timeouts block of the resource: operation timed out; the cleanup of the partially created resource 'id' failed too and it may need to be deleted manually: DELETE failed")
+	})
+
+	t.Run("errors are returned as is once the context expires if the operation has no timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		<-ctx.Done()
+		diags := operationDiagnostics(ctx, nil, "", operationErr)
+		assert.EqualError(t, diagnosticsError(diags), "GET /v1/resource/1234 failed")
+	})
+
+	t.Run("the warnings raised during the operation are returned as warning diagnostics", func(t *testing.T) {
+		ctx, cancel := operationContext(context.Background(), &clientOpenAPIStub{})
+		defer cancel()
+		contextWarnings(ctx).add("resource '%s' is deprecated", "cdn_v1")
+		diags := operationDiagnostics(ctx, newResourceData(time.Hour), schema.TimeoutRead, operationErr)
+		require.Len(t, diags, 2)
+		assert.Equal(t, diag.Error, diags[0].Severity)
+		assert.Equal(t, diag.Warning, diags[1].Severity)
+		assert.Equal(t, "resource 'cdn_v1' is deprecated", diags[1].Summary)
+	})
+}
+
+func TestCleanupContext(t *testing.T) {
+	t.Run("the context is not bound to the context of the operation", func(t *testing.T) {
+		operationCtx, cancel := operationContext(context.Background(), &ProviderClient{})
+		cancel()
+		ctx, release := cleanupContext(operationCtx, &ProviderClient{})
+		defer release()
+		assert.NoError(t, ctx.Err())
+		deadline, hasDeadline := ctx.Deadline()
+		assert.True(t, hasDeadline)
+		assert.True(t, time.Until(deadline) <= cleanupTimeout)
+		assert.Equal(t, contextWarnings(operationCtx), contextWarnings(ctx), "the values of the context of the operation are kept")
+	})
+
+	t.Run("the context is cancelled once the provider is stopped", func(t *testing.T) {
+		stopContext, stop := context.WithCancel(context.Background())
+		client := &ProviderClient{stopContext: stopContext}
+		ctx, release := cleanupContext(context.Background(), client)
+		defer release()
+		stop()
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+			assert.Fail(t, "the context should be done once the provider is stopped")
+		}
+	})
+}
+
+func TestWithStopContext(t *testing.T) {
+	t.Run("the context is cancelled once the stop context is done", func(t *testing.T) {
+		stopContext, stop := context.WithCancel(context.Background())
+		ctx, cancel := withStopContext(context.Background(), stopContext)
+		defer cancel()
+		stop()
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("the context should be cancelled once the stop context is done")
+		}
+		assert.Equal(t, context.Canceled, ctx.Err())
+	})
+
+	t.Run("the context expires with the given context", func(t *testing.T) {
+		parent, cancelParent := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancelParent()
+		ctx, cancel := withStopContext(parent, context.Background())
+		defer cancel()
+		<-ctx.Done()
+		assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+	})
+}
@@ -0,0 +1,93 @@
+package openapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// etagCache keeps in disk the GET responses that contain an ETag header so they can be reused across terraform runs.
+// The responses are always revalidated with the API sending the ETag in the If-None-Match header, and they are only
+// reused if the API replies that they were not modified (304). Each response is stored in its own file, named after the
+// hash of the URL so it does not expose the URL (or the query parameters)
+type etagCache struct {
+	dir string
+}
+
+// etagCacheEntry describes the response cached for a URL
+type etagCacheEntry struct {
+	ETag    string          `json:"etag"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// newETagCache returns the cache that stores the responses in ~/.terraform.d/<provider_name>_data_source_cache
+func newETagCache(providerName string) (*etagCache, error) {
+	dir, err := expandPath(fmt.Sprintf("~/.terraform.d/%s_data_source_cache", providerName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the data source cache directory: %s", err)
+	}
+	return &etagCache{dir: dir}, nil
+}
+
+func (c *etagCache) file(url string) string {
+	hash := sha256.Sum256([]byte(url))
+	return filepath.Join(c.dir, hex.EncodeToString(hash[:])+".json")
+}
+
+// get returns the response cached for the given URL; nil if there is none or it can not be read
+func (c *etagCache) get(url string) *etagCacheEntry {
+	data, err := ioutil.ReadFile(c.file(url))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		log.Printf("[WARN] failed to read the cached response of GET %s: %s", url, err)
+		return nil
+	}
+	entry := &etagCacheEntry{}
+	if err := json.Unmarshal(data, entry); err != nil || entry.ETag == "" {
+		log.Printf("[WARN] ignoring the cached response of GET %s since it is not valid", url)
+		return nil
+	}
+	return entry
+}
+
+// put stores the given response for the URL, making sure only the user running the provider can read it. The file is
+// written under a temporary name and renamed afterwards so concurrent provider processes never read partial responses
+func (c *etagCache) put(url string, entry etagCacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create the data source cache directory '%s': %s", c.dir, err)
+	}
+	tmpFile, err := ioutil.TempFile(c.dir, "response")
+	if err != nil {
+		return fmt.Errorf("failed to write the cached response of GET %s: %s", url, err)
+	}
+	defer os.Remove(tmpFile.Name())
+	_, err = tmpFile.Write(data)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpFile.Name(), c.file(url))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write the cached response of GET %s: %s", url, err)
+	}
+	return nil
+}
+
+// remove removes the response cached for the given URL, if any (e.g. the API no longer returns an ETag for it)
+func (c *etagCache) remove(url string) {
+	if err := os.Remove(c.file(url)); err != nil && !os.IsNotExist(err) {
+		log.Printf("[WARN] failed to remove the cached response of GET %s: %s", url, err)
+	}
+}
@@ -0,0 +1,42 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestETagCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cache := &etagCache{dir: filepath.Join(dir, "goa_data_source_cache")}
+	url := "http://host.com/v1/cdns?label=prod"
+
+	assert.Nil(t, cache.get(url), "no response cached yet")
+
+	entry := etagCacheEntry{ETag: `"v1"`, Payload: json.RawMessage(`[{"id":"1234"}]`)}
+	require.NoError(t, cache.put(url, entry))
+	assert.Equal(t, &entry, cache.get(url))
+	assert.Nil(t, cache.get("http://host.com/v1/cdns"), "responses are cached per URL")
+
+	info, err := os.Stat(cache.file(url))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	assert.NotContains(t, cache.file(url), "cdns", "the file name does not expose the URL")
+	files, err := ioutil.ReadDir(cache.dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1, "the temporary files are renamed")
+
+	cache.remove(url)
+	assert.Nil(t, cache.get(url))
+	cache.remove(url)
+
+	require.NoError(t, ioutil.WriteFile(cache.file(url), []byte("not json"), 0600))
+	assert.Nil(t, cache.get(url), "files not valid are ignored")
+}
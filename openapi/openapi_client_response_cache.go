@@ -0,0 +1,107 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// responseCache is an in-memory cache of GET responses that lives as long as the ProviderClient does (that is, a single
+// terraform run). It avoids hitting the same collection endpoint over and over when several data sources read from it,
+// including when those reads happen concurrently, in which case only one request is sent and the rest wait for its result.
+type responseCache struct {
+	mutex   sync.Mutex
+	entries map[string]*responseCacheEntry
+}
+
+type responseCacheEntry struct {
+	done       chan struct{}
+	cached     bool
+	statusCode int
+	header     http.Header
+	payload    []byte
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{
+		entries: map[string]*responseCacheEntry{},
+	}
+}
+
+// key builds the cache key for a request out of the method, the URL and the headers sent (sorted by name so the key is
+// stable regardless of map iteration order)
+func (c *responseCache) key(method httpMethodSupported, url string, headers map[string]string) string {
+	headerNames := make([]string, 0, len(headers))
+	for headerName := range headers {
+		headerNames = append(headerNames, headerName)
+	}
+	sort.Strings(headerNames)
+	var key strings.Builder
+	fmt.Fprintf(&key, "%s %s", method, url)
+	for _, headerName := range headerNames {
+		fmt.Fprintf(&key, "\n%s: %s", headerName, headers[headerName])
+	}
+	return key.String()
+}
+
+// getOrFetch populates the responsePayload with the cached response for the given key. If there is no cached response
+// yet the fetch function is called and its response is cached only if it was successful (200 OK). If there is already a
+// request in flight for the same key, the call waits for it to finish and re-uses its response.
+func (c *responseCache) getOrFetch(key string, responsePayload interface{}, fetch func() (*http.Response, error)) (*http.Response, error) {
+	c.mutex.Lock()
+	entry, exists := c.entries[key]
+	if !exists {
+		entry = &responseCacheEntry{done: make(chan struct{})}
+		c.entries[key] = entry
+	}
+	c.mutex.Unlock()
+
+	if exists {
+		<-entry.done
+		if !entry.cached {
+			return fetch()
+		}
+		log.Printf("[DEBUG] re-using cached response for request '%s'", strings.SplitN(key, "\n", 2)[0])
+		if err := decodeJSONPayload(entry.payload, responsePayload); err != nil {
+			return nil, err
+		}
+		return &http.Response{
+			StatusCode: entry.statusCode,
+			Header:     entry.header,
+			Body:       ioutil.NopCloser(bytes.NewReader(entry.payload)),
+		}, nil
+	}
+
+	resp, err := fetch()
+	if err == nil && resp != nil && resp.StatusCode == http.StatusOK {
+		if payload, err := json.Marshal(responsePayload); err == nil {
+			entry.cached = true
+			entry.statusCode = resp.StatusCode
+			entry.header = resp.Header
+			entry.payload = payload
+		}
+	}
+	if !entry.cached {
+		c.mutex.Lock()
+		if c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+		c.mutex.Unlock()
+	}
+	close(entry.done)
+	return resp, err
+}
+
+// invalidate drops all the cached responses. This is called whenever a request that might change the state of the remote
+// resources (e.g. POST, PUT, DELETE) is performed so subsequent reads get fresh data
+func (c *responseCache) invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries = map[string]*responseCacheEntry{}
+}
@@ -0,0 +1,138 @@
+package openapi
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestResponseCacheKey(t *testing.T) {
+	Convey("Given a response cache", t, func() {
+		c := newResponseCache()
+		Convey("When key is called with the same method, url and headers in different order", func() {
+			key1 := c.key(httpGet, "http://host.com/v1/resource", map[string]string{"Authorization": "Bearer secret", "User-Agent": "agent"})
+			key2 := c.key(httpGet, "http://host.com/v1/resource", map[string]string{"User-Agent": "agent", "Authorization": "Bearer secret"})
+			Convey("Then the keys returned should be equal", func() {
+				So(key1, ShouldEqual, key2)
+			})
+		})
+		Convey("When key is called with the same method and url but different header values", func() {
+			key1 := c.key(httpGet, "http://host.com/v1/resource", map[string]string{"Authorization": "Bearer secret"})
+			key2 := c.key(httpGet, "http://host.com/v1/resource", map[string]string{"Authorization": "Bearer other"})
+			Convey("Then the keys returned should not be equal", func() {
+				So(key1, ShouldNotEqual, key2)
+			})
+		})
+	})
+}
+
+func TestResponseCacheGetOrFetch(t *testing.T) {
+	Convey("Given a response cache and a fetch function that returns a successful response", t, func() {
+		c := newResponseCache()
+		fetchCalls := 0
+		fetch := func(responsePayload interface{}) func() (*http.Response, error) {
+			return func() (*http.Response, error) {
+				fetchCalls++
+				*(responsePayload.(*[]map[string]interface{})) = []map[string]interface{}{{"id": "someID"}}
+				return &http.Response{StatusCode: http.StatusOK}, nil
+			}
+		}
+		Convey("When getOrFetch is called twice with the same key", func() {
+			firstPayload := []map[string]interface{}{}
+			_, err1 := c.getOrFetch("key", &firstPayload, fetch(&firstPayload))
+			secondPayload := []map[string]interface{}{}
+			resp, err2 := c.getOrFetch("key", &secondPayload, fetch(&secondPayload))
+			Convey("Then the errors returned should be nil", func() {
+				So(err1, ShouldBeNil)
+				So(err2, ShouldBeNil)
+			})
+			Convey("And the fetch function should have been called only once", func() {
+				So(fetchCalls, ShouldEqual, 1)
+			})
+			Convey("And the second response should be the cached one", func() {
+				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+				So(secondPayload, ShouldResemble, firstPayload)
+			})
+		})
+		Convey("When getOrFetch is called, then the cache is invalidated and getOrFetch is called again with the same key", func() {
+			payload := []map[string]interface{}{}
+			c.getOrFetch("key", &payload, fetch(&payload))
+			c.invalidate()
+			c.getOrFetch("key", &payload, fetch(&payload))
+			Convey("Then the fetch function should have been called twice", func() {
+				So(fetchCalls, ShouldEqual, 2)
+			})
+		})
+	})
+
+	Convey("Given a response cache and a fetch function that returns a non successful response", t, func() {
+		c := newResponseCache()
+		fetchCalls := 0
+		fetch := func() (*http.Response, error) {
+			fetchCalls++
+			return &http.Response{StatusCode: http.StatusInternalServerError}, nil
+		}
+		Convey("When getOrFetch is called twice with the same key", func() {
+			payload := []map[string]interface{}{}
+			c.getOrFetch("key", &payload, fetch)
+			resp, err := c.getOrFetch("key", &payload, fetch)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the response should not have been cached", func() {
+				So(fetchCalls, ShouldEqual, 2)
+				So(resp.StatusCode, ShouldEqual, http.StatusInternalServerError)
+			})
+		})
+	})
+
+	Convey("Given a response cache and a fetch function that returns an error", t, func() {
+		c := newResponseCache()
+		fetch := func() (*http.Response, error) {
+			return nil, errors.New("some error")
+		}
+		Convey("When getOrFetch is called", func() {
+			payload := []map[string]interface{}{}
+			_, err := c.getOrFetch("key", &payload, fetch)
+			Convey("Then the error returned should be the one returned by the fetch function", func() {
+				So(err.Error(), ShouldEqual, "some error")
+			})
+			Convey("And the key should not have been cached", func() {
+				So(c.entries, ShouldNotContainKey, "key")
+			})
+		})
+	})
+
+	Convey("Given a response cache and a fetch function that blocks until released", t, func() {
+		c := newResponseCache()
+		var mutex sync.Mutex
+		fetchCalls := 0
+		release := make(chan struct{})
+		fetch := func() (*http.Response, error) {
+			mutex.Lock()
+			fetchCalls++
+			mutex.Unlock()
+			<-release
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		}
+		Convey("When getOrFetch is called concurrently with the same key", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 5; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					payload := []map[string]interface{}{}
+					c.getOrFetch("key", &payload, fetch)
+				}()
+			}
+			close(release)
+			wg.Wait()
+			Convey("Then the fetch function should have been called only once", func() {
+				So(fetchCalls, ShouldEqual, 1)
+			})
+		})
+	})
+}
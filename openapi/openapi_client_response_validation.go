@@ -0,0 +1,190 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+)
+
+const (
+	responseDriftMissingRequired = "missing_required"
+	responseDriftWrongType       = "wrong_type"
+	responseDriftUndeclared      = "undeclared"
+)
+
+// responseDrift describes a divergence between a response payload and the schema documented in the OpenAPI document
+type responseDrift struct {
+	// field is the path to the field in the payload (e.g. 'backends[0].port')
+	field string
+	// issue is one of missing_required, wrong_type or undeclared
+	issue string
+	// expected and actual describe the type expected by the schema and the one returned, only populated for wrong_type
+	expected string
+	actual   string
+}
+
+// validateResponse logs a warning for each divergence found between the successful response returned by the API and the
+// schema documented for it, which is the one of the response status code if documented or the resource schema otherwise.
+// Nothing is validated if the response validation is disabled or the response only contains a summary of the resource
+func (o *ProviderClient) validateResponse(resource SpecResource, method httpMethodSupported, resourceURL string, operation *specResourceOperation, resp *http.Response, responsePayload interface{}) {
+	if !o.responseValidation || resp == nil || resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return
+	}
+	var responseSchema *specSchemaDefinition
+	if operation != nil {
+		if response := operation.responses.getResponse(resp.StatusCode); response != nil {
+			if response.isSummary {
+				return
+			}
+			responseSchema = response.schema
+		}
+	}
+	if responseSchema == nil {
+		resourceSchema, err := resource.getResourceSchema()
+		if err != nil {
+			log.Printf("[DEBUG] [resource='%s'] skipping the validation of the response of %s %s: %s", resource.getResourceName(), method, resourceURL, err)
+			return
+		}
+		responseSchema = resourceSchema
+	}
+	var drifts []responseDrift
+	switch payload := responsePayload.(type) {
+	case *map[string]interface{}:
+		if payload != nil && *payload != nil {
+			drifts = validateResponsePayload(responseSchema, *payload, "")
+		}
+	case *[]map[string]interface{}:
+		if payload != nil {
+			for i, item := range *payload {
+				drifts = append(drifts, validateResponsePayload(responseSchema, item, fmt.Sprintf("[%d]", i))...)
+			}
+		}
+	}
+	for _, drift := range drifts {
+		if drift.issue == responseDriftWrongType {
+			log.Printf("[WARN] response drift: resource=%s method=%s url=%s status=%d field=%s issue=%s expected=%s actual=%s", resource.getResourceName(), method, resourceURL, resp.StatusCode, drift.field, drift.issue, drift.expected, drift.actual)
+			continue
+		}
+		log.Printf("[WARN] response drift: resource=%s method=%s url=%s status=%d field=%s issue=%s", resource.getResourceName(), method, resourceURL, resp.StatusCode, drift.field, drift.issue)
+	}
+}
+
+// validateResponsePayload returns the divergences between the given payload and the schema, sorted by field. The
+// required properties must be present (except the parent properties, which are not part of the payloads), the values
+// must match the property types and all the fields must be declared in the schema. Nested objects are validated too
+func validateResponsePayload(schemaDefinition *specSchemaDefinition, payload map[string]interface{}, fieldPrefix string) []responseDrift {
+	var drifts []responseDrift
+	for _, property := range schemaDefinition.Properties {
+		value, exists := payload[property.Name]
+		field := joinResponseDriftField(fieldPrefix, property.Name)
+		if !exists || value == nil {
+			if property.isRequired() && !property.IsParentProperty {
+				drifts = append(drifts, responseDrift{field: field, issue: responseDriftMissingRequired})
+			}
+			continue
+		}
+		drifts = append(drifts, validateResponseValue(property, property.Type, value, field)...)
+	}
+	for fieldName := range payload {
+		if _, err := schemaDefinition.getProperty(fieldName); err != nil {
+			drifts = append(drifts, responseDrift{field: joinResponseDriftField(fieldPrefix, fieldName), issue: responseDriftUndeclared})
+		}
+	}
+	sort.Slice(drifts, func(i, j int) bool {
+		return drifts[i].field < drifts[j].field
+	})
+	return drifts
+}
+
+// validateResponseValue returns the divergences between the value and the given type of the property, validating the
+// items of the arrays and the properties of the objects too
+func validateResponseValue(property *specSchemaDefinitionProperty, propertyType schemaDefinitionPropertyType, value interface{}, field string) []responseDrift {
+	if !isResponseValueOfType(value, propertyType) {
+		return []responseDrift{{field: field, issue: responseDriftWrongType, expected: string(propertyType), actual: getResponseValueType(value)}}
+	}
+	switch propertyType {
+	case typeList:
+		var drifts []responseDrift
+		for i, item := range value.([]interface{}) {
+			if item == nil {
+				continue
+			}
+			drifts = append(drifts, validateResponseValue(property, property.ArrayItemsType, item, fmt.Sprintf("%s[%d]", field, i))...)
+		}
+		return drifts
+	case typeObject:
+		if property.SpecSchemaDefinition == nil {
+			return nil
+		}
+		return validateResponsePayload(property.SpecSchemaDefinition, value.(map[string]interface{}), field)
+	}
+	return nil
+}
+
+// isResponseValueOfType checks whether the value decoded from the JSON payload (numbers are decoded as json.Number) is of
+// the given type. Properties of unknown types (e.g. array items type not documented) accept any value
+func isResponseValueOfType(value interface{}, propertyType schemaDefinitionPropertyType) bool {
+	switch propertyType {
+	case typeString:
+		_, ok := value.(string)
+		return ok
+	case typeBool:
+		_, ok := value.(bool)
+		return ok
+	case typeInt:
+		switch number := value.(type) {
+		case json.Number:
+			_, err := number.Int64()
+			return err == nil
+		case float64:
+			return number == float64(int64(number))
+		case int, int64:
+			return true
+		}
+		return false
+	case typeFloat:
+		switch value.(type) {
+		case json.Number, float64, float32, int, int64:
+			return true
+		}
+		return false
+	case typeList:
+		_, ok := value.([]interface{})
+		return ok
+	case typeObject:
+		_, ok := value.(map[string]interface{})
+		return ok
+	}
+	return true
+}
+
+// getResponseValueType returns the JSON type of the value decoded from the JSON payload
+func getResponseValueType(value interface{}) string {
+	switch number := value.(type) {
+	case string:
+		return string(typeString)
+	case bool:
+		return string(typeBool)
+	case json.Number:
+		if _, err := number.Int64(); err == nil {
+			return string(typeInt)
+		}
+		return string(typeFloat)
+	case float64, float32, int, int64:
+		return string(typeFloat)
+	case []interface{}:
+		return string(typeList)
+	case map[string]interface{}:
+		return string(typeObject)
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func joinResponseDriftField(prefix, fieldName string) string {
+	if prefix == "" {
+		return fieldName
+	}
+	return prefix + "." + fieldName
+}
@@ -0,0 +1,172 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dikhan/http_goclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateResponsePayload(t *testing.T) {
+	backendSchema := &specSchemaDefinition{
+		Properties: specSchemaDefinitionProperties{
+			newStringSchemaDefinitionPropertyWithDefaults("host", "", true, false, nil),
+			newIntSchemaDefinitionPropertyWithDefaults("port", "", false, false, nil),
+		},
+	}
+	resourceSchema := &specSchemaDefinition{
+		Properties: specSchemaDefinitionProperties{
+			newStringSchemaDefinitionPropertyWithDefaults("id", "", false, true, nil),
+			newStringSchemaDefinitionPropertyWithDefaults("label", "", true, false, nil),
+			newBoolSchemaDefinitionPropertyWithDefaults("enabled", "", false, false, nil),
+			newListSchemaDefinitionPropertyWithDefaults("ips", "", false, false, false, nil, typeString, nil),
+			newListSchemaDefinitionPropertyWithDefaults("backends", "", false, false, false, nil, typeObject, backendSchema),
+			newObjectSchemaDefinitionPropertyWithDefaults("primary", "", false, false, false, nil, backendSchema),
+			newParentStringSchemaDefinitionPropertyWithDefaults("parent_id", "", true, false, nil),
+		},
+	}
+	testCases := []struct {
+		name           string
+		payload        map[string]interface{}
+		expectedDrifts []responseDrift
+	}{
+		{
+			name: "payload matching the schema",
+			payload: map[string]interface{}{
+				"id":       "42",
+				"label":    "some label",
+				"enabled":  true,
+				"ips":      []interface{}{"10.0.0.1"},
+				"backends": []interface{}{map[string]interface{}{"host": "backend1", "port": json.Number("80")}},
+				"primary":  map[string]interface{}{"host": "backend1"},
+			},
+			expectedDrifts: nil,
+		},
+		{
+			name: "required property missing or null",
+			payload: map[string]interface{}{
+				"id":      "42",
+				"label":   nil,
+				"primary": map[string]interface{}{"port": json.Number("80")},
+			},
+			expectedDrifts: []responseDrift{
+				{field: "label", issue: responseDriftMissingRequired},
+				{field: "primary.host", issue: responseDriftMissingRequired},
+			},
+		},
+		{
+			name: "values of the wrong type",
+			payload: map[string]interface{}{
+				"label":    json.Number("7"),
+				"enabled":  "true",
+				"ips":      []interface{}{"10.0.0.1", true},
+				"backends": []interface{}{map[string]interface{}{"host": "backend1", "port": "80"}, map[string]interface{}{"host": "backend2", "port": json.Number("80.5")}},
+				"primary":  []interface{}{},
+			},
+			expectedDrifts: []responseDrift{
+				{field: "backends[0].port", issue: responseDriftWrongType, expected: "integer", actual: "string"},
+				{field: "backends[1].port", issue: responseDriftWrongType, expected: "integer", actual: "number"},
+				{field: "enabled", issue: responseDriftWrongType, expected: "boolean", actual: "string"},
+				{field: "ips[1]", issue: responseDriftWrongType, expected: "string", actual: "boolean"},
+				{field: "label", issue: responseDriftWrongType, expected: "string", actual: "integer"},
+				{field: "primary", issue: responseDriftWrongType, expected: "object", actual: "list"},
+			},
+		},
+		{
+			name: "fields not declared in the schema",
+			payload: map[string]interface{}{
+				"label":   "some label",
+				"created": "2020-01-01",
+				"primary": map[string]interface{}{"host": "backend1", "weight": json.Number("1")},
+			},
+			expectedDrifts: []responseDrift{
+				{field: "created", issue: responseDriftUndeclared},
+				{field: "primary.weight", issue: responseDriftUndeclared},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedDrifts, validateResponsePayload(resourceSchema, tc.payload, ""))
+		})
+	}
+}
+
+func TestProviderClient_ResponseValidation(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/cdns" {
+			w.Write([]byte(`[{"id":"1","label":"first"},{"id":"2"}]`))
+			return
+		}
+		w.Write([]byte(`{"id":"1","label":7}`))
+	}))
+	defer api.Close()
+	newClient := func(responseValidation bool) *ProviderClient {
+		return &ProviderClient{
+			openAPIBackendConfiguration: newStubBackendConfiguration(strings.TrimPrefix(api.URL, "http://"), "", "http"),
+			httpClient:                  &http_goclient.HttpClient{HttpClient: &http.Client{}},
+			providerConfiguration:       providerConfiguration{},
+			apiAuthenticator:            newStubAuthenticator("Authorization", "Bearer secret", nil),
+			responseValidation:          responseValidation,
+		}
+	}
+	resource := &specStubResource{
+		name: "cdn_v1",
+		path: "/v1/cdns",
+		schemaDefinition: &specSchemaDefinition{
+			Properties: specSchemaDefinitionProperties{
+				newStringSchemaDefinitionPropertyWithDefaults("id", "", false, true, nil),
+				newStringSchemaDefinitionPropertyWithDefaults("label", "", true, false, nil),
+			},
+		},
+		resourceGetOperation:  &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}},
+		resourceListOperation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}},
+	}
+
+	t.Run("divergences are logged as warnings", func(t *testing.T) {
+		var out bytes.Buffer
+		log.SetOutput(&out)
+		defer log.SetOutput(os.Stderr)
+
+		_, err := newClient(true).Get(resource, "1", &map[string]interface{}{})
+		require.NoError(t, err)
+		_, err = newClient(true).List(resource, &[]map[string]interface{}{})
+		require.NoError(t, err)
+
+		assert.Contains(t, out.String(), "[WARN] response drift: resource=cdn_v1 method=GET url="+api.URL+"/v1/cdns/1 status=200 field=label issue=wrong_type expected=string actual=integer")
+		assert.Contains(t, out.String(), "[WARN] response drift: resource=cdn_v1 method=GET url="+api.URL+"/v1/cdns status=200 field=[1].label issue=missing_required")
+		assert.NotContains(t, out.String(), "field=[0]")
+	})
+
+	t.Run("responses are not validated if the validation is disabled", func(t *testing.T) {
+		var out bytes.Buffer
+		log.SetOutput(&out)
+		defer log.SetOutput(os.Stderr)
+
+		_, err := newClient(false).Get(resource, "1", &map[string]interface{}{})
+		require.NoError(t, err)
+
+		assert.NotContains(t, out.String(), "response drift")
+	})
+
+	t.Run("summary responses are not validated", func(t *testing.T) {
+		var out bytes.Buffer
+		log.SetOutput(&out)
+		defer log.SetOutput(os.Stderr)
+		summaryResource := *resource
+		summaryResource.resourceGetOperation = &specResourceOperation{responses: specResponses{http.StatusOK: {isSummary: true}}, SecuritySchemes: SpecSecuritySchemes{}}
+
+		_, err := newClient(true).Get(&summaryResource, "1", &map[string]interface{}{})
+		require.NoError(t, err)
+
+		assert.NotContains(t, out.String(), "response drift")
+	})
+}
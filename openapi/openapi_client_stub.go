@@ -1,6 +1,8 @@
 package openapi
 
 import (
+	"context"
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -14,14 +16,31 @@ type clientOpenAPIStub struct {
 	returnHTTPCode      int
 	idReceived          string
 	parentIDsReceived   []string
+	resourceReceived    SpecResource
 
-	funcPut func() (*http.Response, error)
+	patchOperationsReceived []jsonPatchOperation
+
+	funcPut  func() (*http.Response, error)
+	funcGet  func() (*http.Response, error)
+	funcList func() (*http.Response, error)
+
+	// streamedEvents are the events returned every time StreamEvents is called
+	streamedEvents     []json.RawMessage
+	eventsPathReceived string
+
+	// asyncOperationPayloads are the payloads returned by the consecutive GetAsyncOperation calls (the last one is
+	// returned once all of them have been returned)
+	asyncOperationPayloads    []map[string]interface{}
+	asyncOperationURLReceived string
+
+	cleanupReceived *onFailureCleanupOperation
 }
 
 func (c *clientOpenAPIStub) Post(resource SpecResource, requestPayload interface{}, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
 	if c.error != nil {
 		return nil, c.error
 	}
+	c.resourceReceived = resource
 	c.parentIDsReceived = parentIDs
 	switch p := responsePayload.(type) {
 	case *map[string]interface{}:
@@ -40,6 +59,7 @@ func (c *clientOpenAPIStub) Put(resource SpecResource, id string, requestPayload
 		return nil, c.error
 	}
 	c.idReceived = id
+	c.resourceReceived = resource
 	c.parentIDsReceived = parentIDs
 	switch p := responsePayload.(type) {
 	case *map[string]interface{}:
@@ -50,11 +70,32 @@ func (c *clientOpenAPIStub) Put(resource SpecResource, id string, requestPayload
 	return c.generateStubResponse(http.StatusOK), nil
 }
 
+func (c *clientOpenAPIStub) Patch(ctx context.Context, resource SpecResource, id string, operations []jsonPatchOperation, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	if c.error != nil {
+		return nil, c.error
+	}
+	c.idReceived = id
+	c.resourceReceived = resource
+	c.parentIDsReceived = parentIDs
+	c.patchOperationsReceived = operations
+	switch p := responsePayload.(type) {
+	case *map[string]interface{}:
+		*p = c.responsePayload
+	default:
+		panic("unexpected type")
+	}
+	return c.generateStubResponse(http.StatusOK), nil
+}
+
 func (c *clientOpenAPIStub) Get(resource SpecResource, id string, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	if c.funcGet != nil {
+		return c.funcGet()
+	}
 	if c.error != nil {
 		return nil, c.error
 	}
 	c.idReceived = id
+	c.resourceReceived = resource
 	c.parentIDsReceived = parentIDs
 	switch p := responsePayload.(type) {
 	case *map[string]interface{}:
@@ -67,6 +108,9 @@ func (c *clientOpenAPIStub) Get(resource SpecResource, id string, responsePayloa
 }
 
 func (c *clientOpenAPIStub) List(resource SpecResource, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	if c.funcList != nil {
+		return c.funcList()
+	}
 	if c.error != nil {
 		return nil, c.error
 	}
@@ -86,11 +130,51 @@ func (c *clientOpenAPIStub) Delete(resource SpecResource, id string, parentIDs .
 		return nil, c.error
 	}
 	c.idReceived = id
+	c.resourceReceived = resource
 	c.parentIDsReceived = parentIDs
 	delete(c.responsePayload, id)
 	return c.generateStubResponse(http.StatusNoContent), nil
 }
 
+func (c *clientOpenAPIStub) StreamEvents(ctx context.Context, resource SpecResource, id string, eventsPath string, onEvent func(event json.RawMessage), parentIDs ...string) error {
+	if c.error != nil {
+		return c.error
+	}
+	c.eventsPathReceived = eventsPath
+	for _, event := range c.streamedEvents {
+		onEvent(event)
+	}
+	return nil
+}
+
+func (c *clientOpenAPIStub) GetAsyncOperation(ctx context.Context, resource SpecResource, operationURL string, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	if c.error != nil {
+		return nil, c.error
+	}
+	c.asyncOperationURLReceived = operationURL
+	c.parentIDsReceived = parentIDs
+	switch p := responsePayload.(type) {
+	case *map[string]interface{}:
+		*p = c.asyncOperationPayloads[0]
+		if len(c.asyncOperationPayloads) > 1 {
+			c.asyncOperationPayloads = c.asyncOperationPayloads[1:]
+		}
+	default:
+		panic("unexpected type")
+	}
+	return c.generateStubResponse(http.StatusOK), nil
+}
+
+func (c *clientOpenAPIStub) CallCleanupOperation(ctx context.Context, resource SpecResource, id string, cleanup *onFailureCleanupOperation, parentIDs ...string) (*http.Response, error) {
+	if c.error != nil {
+		return nil, c.error
+	}
+	c.idReceived = id
+	c.cleanupReceived = cleanup
+	c.parentIDsReceived = parentIDs
+	return c.generateStubResponse(http.StatusNoContent), nil
+}
+
 func (c *clientOpenAPIStub) generateStubResponse(defaultHTTPCode int) *http.Response {
 	return &http.Response{
 		StatusCode: c.returnCode(defaultHTTPCode),
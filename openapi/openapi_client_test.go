@@ -1,17 +1,26 @@
 package openapi
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/go-openapi/spec"
 
 	"github.com/dikhan/http_goclient"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestProviderClient(t *testing.T) {
@@ -237,18 +246,22 @@ func TestGetResourceURL_edge_cases(t *testing.T) {
 		{name: "no trailing slash", path: "/v1/resource", id: "1234", expectedResourceURL: "http://wwww.host.com/api/v1/resource/1234"},
 		{name: "different id", path: "/v1/resource", id: "42", expectedResourceURL: "http://wwww.host.com/api/v1/resource/42"},
 		{name: "with a parent id", path: "/v1/resource/{parent_id}/v17/subresource", id: "42", parentIDs: []string{"3.14159"}, expectedResourceURL: "http://wwww.host.com/api/v1/resource/3.14159/v17/subresource/42"},
-		{name: "with a parent id with mustaches", path: "/v1/resource/{parent_id}/v17/subresource", id: "42", parentIDs: []string{"{3.14159}"}, expectedResourceURL: "http://wwww.host.com/api/v1/resource/{3.14159}/v17/subresource/42"},
-		{name: "with a parent id with a slash", path: "/v1/resource/{parent_id}/v17/subresource", id: "42", parentIDs: []string{"3.14/159"}, expectedResourceURL: "http://wwww.host.com/api/v1/resource/3.14/159/v17/subresource/42", expectedError: "could not resolve sub-resource path correctly '/v1/resource/{parent_id}/v17/subresource' due to parent IDs ([3.14/159]) containing not supported characters (forward slashes)"},
+		{name: "with a parent id with mustaches", path: "/v1/resource/{parent_id}/v17/subresource", id: "42", parentIDs: []string{"{3.14159}"}, expectedResourceURL: "http://wwww.host.com/api/v1/resource/%7B3.14159%7D/v17/subresource/42"},
+		{name: "with a parent id with a slash", path: "/v1/resource/{parent_id}/v17/subresource", id: "42", parentIDs: []string{"3.14/159"}, expectedResourceURL: "http://wwww.host.com/api/v1/resource/3.14%2F159/v17/subresource/42"},
+		{name: "with a parent id with spaces and unicode characters", path: "/v1/resource/{parent_id}/v17/subresource", id: "42", parentIDs: []string{"my résumé"}, expectedResourceURL: "http://wwww.host.com/api/v1/resource/my%20r%C3%A9sum%C3%A9/v17/subresource/42"},
 		{name: "with a token with double mustaches", path: "/v1/resource/{{parent_id}}/v17/subresource", id: "42", parentIDs: []string{"3.14159"}, expectedResourceURL: "http://wwww.host.com/api/v1/resource/{{parent_id}}/v17/subresource/42"},
 		{name: "with a parent id but no tokens", path: "/v1/resource", id: "42", parentIDs: []string{"pi"}, expectedResourceURL: "http://wwww.host.com/api/v1/resource/42"},
 		{name: "trailing slash", path: "/v1/resource/", id: "1337", expectedResourceURL: "http://wwww.host.com/api/v1/resource/1337"},
-		{name: "id with a slash", path: "/v1/resource/", id: "13/37", expectedResourceURL: "http://wwww.host.com/api/v1/resource/13/37", expectedError: "instance ID (13/37) contains not supported characters (forward slashes)"},
-		{name: "id with mustaches", path: "/v1/resource/", id: "1{33}7", expectedResourceURL: "http://wwww.host.com/api/v1/resource/1{33}7"},
+		{name: "id with a slash", path: "/v1/resource/", id: "13/37", expectedResourceURL: "http://wwww.host.com/api/v1/resource/13%2F37"},
+		{name: "id with mustaches", path: "/v1/resource/", id: "1{33}7", expectedResourceURL: "http://wwww.host.com/api/v1/resource/1%7B33%7D7"},
+		{name: "id with a percent sign, a question mark and a hash", path: "/v1/resource/", id: "50%?#", expectedResourceURL: "http://wwww.host.com/api/v1/resource/50%25%3F%23"},
+		{name: "id with matrix style parameters", path: "/v1/resource/", id: "1337;version=2,3", expectedResourceURL: "http://wwww.host.com/api/v1/resource/1337;version=2,3"},
+		{name: "double trailing slash", path: "/v1/resource//", id: "1337", expectedResourceURL: "http://wwww.host.com/api/v1/resource/1337"},
+		{name: "double leading slash", path: "//v1/resource/", id: "1337", expectedResourceURL: "http://wwww.host.com/api/v1/resource/1337"},
+		{name: "double slash in the middle", path: "/v1//resource/", id: "1337", expectedResourceURL: "http://wwww.host.com/api/v1/resource/1337"},
+		{name: "double slash next to a parent id", path: "/v1/resource//{parent_id}//v17/subresource", id: "42", parentIDs: []string{"3.14159"}, expectedResourceURL: "http://wwww.host.com/api/v1/resource/3.14159/v17/subresource/42"},
 		// Unhappy paths
 		{name: "empty id", path: "/v1/resource/", id: "", expectedError: "could not build the resourceIDURL: required instance id value is missing"},
-		{name: "double trailing slash", path: "/v1/resource//", id: "1337", expectedError: "could not resolve sub-resource path correctly '/v1/resource//' with the given ids - missing ids to resolve the path params properly: []"},
-		{name: "double leading slash", path: "//v1/resource/", id: "1337", expectedError: "could not resolve sub-resource path correctly '//v1/resource/' with the given ids - missing ids to resolve the path params properly: []"},
-		{name: "double slash in the middle", path: "/v1//resource/", id: "1337", expectedError: "could not resolve sub-resource path correctly '/v1//resource/' with the given ids - missing ids to resolve the path params properly: []"},
 		{name: "with a missing parent id", path: "/v1/resource/{parent_id}/v17/subresource", id: "42", parentIDs: []string{}, expectedError: "could not resolve sub-resource path correctly '/v1/resource/{parent_id}/v17/subresource' with the given ids - missing ids to resolve the path params properly: []"},
 		{name: "with extra parent ids", path: "/v1/resource/{parent_id}/v17/subresource", id: "42", parentIDs: []string{"-1", "-2"}, expectedError: "could not resolve sub-resource path correctly '/v1/resource/{parent_id}/v17/subresource' with the given ids - more ids than path params: [-1 -2]"},
 	}
@@ -284,7 +297,7 @@ func TestGetResourceURL_edge_cases(t *testing.T) {
 						So(err, ShouldBeNil)
 					})
 					Convey("And the resource URL returned should be the expected one", func() {
-						So(tc.expectedResourceURL, ShouldEqual, tc.expectedResourceURL)
+						So(actualResourceURL, ShouldEqual, tc.expectedResourceURL)
 					})
 				}
 			})
@@ -292,6 +305,66 @@ func TestGetResourceURL_edge_cases(t *testing.T) {
 	}
 }
 
+func TestGetResourceURL_base_path_variants(t *testing.T) {
+	testCases := []struct {
+		name                string
+		basePath            string
+		path                string
+		expectedResourceURL string
+	}{
+		{name: "empty base path", basePath: "", path: "/v1/resource", expectedResourceURL: "http://wwww.host.com/v1/resource"},
+		{name: "root base path", basePath: "/", path: "/v1/resource", expectedResourceURL: "http://wwww.host.com/v1/resource"},
+		{name: "base path without trailing slash", basePath: "/api", path: "/v1/resource", expectedResourceURL: "http://wwww.host.com/api/v1/resource"},
+		{name: "base path with trailing slash", basePath: "/api/", path: "/v1/resource", expectedResourceURL: "http://wwww.host.com/api/v1/resource"},
+		{name: "base path with several trailing slashes", basePath: "/api//", path: "/v1/resource", expectedResourceURL: "http://wwww.host.com/api/v1/resource"},
+		{name: "base path without leading slash", basePath: "api/", path: "/v1/resource", expectedResourceURL: "http://wwww.host.com/api/v1/resource"},
+		{name: "base path with duplicate slashes", basePath: "//api//v2", path: "/v1/resource", expectedResourceURL: "http://wwww.host.com/api/v2/v1/resource"},
+		{name: "resource path without leading slash", basePath: "/api/", path: "v1/resource", expectedResourceURL: "http://wwww.host.com/api/v1/resource"},
+		{name: "resource path with trailing slash", basePath: "/api/", path: "/v1/resource/", expectedResourceURL: "http://wwww.host.com/api/v1/resource/"},
+	}
+	for _, tc := range testCases {
+		providerClient := &ProviderClient{
+			openAPIBackendConfiguration: &specStubBackendConfiguration{
+				host:       "wwww.host.com",
+				basePath:   tc.basePath,
+				httpScheme: "http",
+			},
+		}
+		resourceURL, err := providerClient.getResourceURL(&specStubResource{path: tc.path}, []string{})
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expectedResourceURL, resourceURL, tc.name)
+	}
+}
+
+func TestGetResourceURL_scheme_overrides(t *testing.T) {
+	testCases := []struct {
+		name                string
+		resourceScheme      string
+		endPoint            string
+		expectedResourceURL string
+	}{
+		{name: "no overrides", expectedResourceURL: "https://wwww.host.com/v1/resource"},
+		{name: "resource scheme override", resourceScheme: "http", expectedResourceURL: "http://wwww.host.com/v1/resource"},
+		{name: "endpoint override without scheme", resourceScheme: "http", endPoint: "localhost:8080", expectedResourceURL: "http://localhost:8080/v1/resource"},
+		{name: "endpoint override with scheme", endPoint: "http://localhost:8080", expectedResourceURL: "http://localhost:8080/v1/resource"},
+		{name: "endpoint override with scheme takes precedence over the resource scheme override", resourceScheme: "http", endPoint: "https://staging.host.com", expectedResourceURL: "https://staging.host.com/v1/resource"},
+	}
+	for _, tc := range testCases {
+		providerClient := &ProviderClient{
+			openAPIBackendConfiguration: &specStubBackendConfiguration{
+				host:       "wwww.host.com",
+				httpScheme: "https",
+			},
+			providerConfiguration: providerConfiguration{
+				Endpoints: map[string]string{"resourceName": tc.endPoint},
+			},
+		}
+		resourceURL, err := providerClient.getResourceURL(&specStubResource{name: "resourceName", path: "/v1/resource", scheme: tc.resourceScheme}, []string{})
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expectedResourceURL, resourceURL, tc.name)
+	}
+}
+
 func TestGetResourceURL(t *testing.T) {
 	Convey("Given a providerClient set up with auth that injects some headers to the request and is not multiregion", t, func() {
 		providerClient := &ProviderClient{
@@ -836,7 +909,7 @@ func TestPerformRequest(t *testing.T) {
 			expectedPath := "/v1/resource"
 			resourceURL := fmt.Sprintf("%s://%s%s%s", expectedProtocol, expectedHost, expectedBasePath, expectedPath)
 
-			_, err := providerClient.performRequest("POST", resourceURL, resourcePostOperation, requestPayload, responsePayload)
+			_, err := providerClient.performRequest(context.Background(), "POST", resourceURL, resourcePostOperation, requestPayload, responsePayload)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -866,7 +939,7 @@ func TestPerformRequest(t *testing.T) {
 				responses:        specResponses{},
 				SecuritySchemes:  SpecSecuritySchemes{},
 			}
-			_, err := providerClient.performRequest("NotSupportedMethod", "", resourcePostOperation, nil, nil)
+			_, err := providerClient.performRequest(context.Background(), "NotSupportedMethod", "", resourcePostOperation, nil, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -882,7 +955,7 @@ func TestPerformRequest(t *testing.T) {
 					err:         fmt.Errorf("some error with prep auth"),
 				},
 			}
-			_, err := providerClient.performRequest("POST", "", &specResourceOperation{}, nil, nil)
+			_, err := providerClient.performRequest(context.Background(), "POST", "", &specResourceOperation{}, nil, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -1223,6 +1296,33 @@ func TestProviderClientGet(t *testing.T) {
 				So(httpClient.Headers[userAgentHeader], ShouldContainSubstring, "OpenAPI Terraform Provider")
 			})
 		})
+		Convey("When providerClient GET method is called with a specStubResource that has an override configured", func() {
+			providerClient.resourceOverrides = map[string]SpecResource{
+				"resource_v1": &specStubResource{
+					name: "resource_v1",
+					path: "/v2/resource",
+					resourceGetOperation: &specResourceOperation{
+						responses:       specResponses{},
+						SecuritySchemes: SpecSecuritySchemes{},
+					},
+				},
+			}
+			specStubResource := &specStubResource{
+				name: "resource_v1",
+				path: "/v1/resource",
+				resourceGetOperation: &specResourceOperation{
+					responses:       specResponses{},
+					SecuritySchemes: SpecSecuritySchemes{},
+				},
+			}
+			_, err := providerClient.Get(specStubResource, "1234", map[string]interface{}{})
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And then client should have received the URL of the resource override", func() {
+				So(httpClient.URL, ShouldEqual, "http://wwww.host.com/api/v2/resource/1234")
+			})
+		})
 	})
 
 	Convey("Given a providerClient set up with stub client that returns some response", t, func() {
@@ -1404,6 +1504,65 @@ func TestProviderClientList(t *testing.T) {
 		})
 	})
 
+	Convey("Given a providerClient set up with the response cache enabled", t, func() {
+		httpClient := &http_goclient.HttpClientStub{
+			Response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`[{"property1":"value1"}]`)),
+			},
+		}
+		providerClient := &ProviderClient{
+			openAPIBackendConfiguration: newStubBackendConfiguration("wwww.host.com", "/api", "http"),
+			httpClient:                  httpClient,
+			providerConfiguration:       providerConfiguration{},
+			apiAuthenticator:            newStubAuthenticator("Authentication", "Bearer secret!", nil),
+			responseCache:               newResponseCache(),
+		}
+		specStubResource := &specStubResource{
+			path: "/v1/resource",
+			resourceListOperation: &specResourceOperation{
+				HeaderParameters: SpecHeaderParameters{},
+				responses:        specResponses{},
+				SecuritySchemes:  SpecSecuritySchemes{},
+			},
+			resourcePostOperation: &specResourceOperation{
+				HeaderParameters: SpecHeaderParameters{},
+				responses:        specResponses{},
+				SecuritySchemes:  SpecSecuritySchemes{},
+			},
+		}
+		Convey("When providerClient List method is called twice for the same resource", func() {
+			responsePayload := []map[string]interface{}{}
+			_, err := providerClient.List(specStubResource, &responsePayload)
+			So(err, ShouldBeNil)
+			httpClient.URL = ""
+			resp, err := providerClient.List(specStubResource, &responsePayload)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the response returned should be the cached one", func() {
+				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+			})
+			Convey("And the second call should not have reached the API", func() {
+				So(httpClient.URL, ShouldBeEmpty)
+			})
+		})
+		Convey("When providerClient List method is called, then a POST is performed and List is called again", func() {
+			responsePayload := []map[string]interface{}{}
+			_, err := providerClient.List(specStubResource, &responsePayload)
+			So(err, ShouldBeNil)
+			_, err = providerClient.Post(specStubResource, map[string]interface{}{}, map[string]interface{}{})
+			So(err, ShouldBeNil)
+			httpClient.URL = ""
+			_, err = providerClient.List(specStubResource, &responsePayload)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the second List call should have reached the API since the cache was invalidated by the POST", func() {
+				So(httpClient.URL, ShouldEqual, "http://wwww.host.com/api/v1/resource")
+			})
+		})
+	})
 }
 
 func TestProviderClientDelete(t *testing.T) {
@@ -1520,3 +1679,1119 @@ func TestProviderClientDelete(t *testing.T) {
 		})
 	})
 }
+
+func TestGetResourceIDURL_RawSlashes(t *testing.T) {
+	providerClient := &ProviderClient{
+		openAPIBackendConfiguration: &specStubBackendConfiguration{
+			host:       "wwww.host.com",
+			basePath:   "/api",
+			httpScheme: "http",
+			rawSlashes: true,
+		},
+	}
+	r := &SpecV2Resource{
+		Path: "/v1/resource/{parent_id}/subresource",
+		RootPathItem: spec.PathItem{
+			PathItemProps: spec.PathItemProps{
+				Post: &spec.Operation{},
+			},
+		},
+	}
+	resourceURL, err := providerClient.getResourceIDURL(r, []string{"parent/a b"}, "folder/file name")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://wwww.host.com/api/v1/resource/parent/a%20b/subresource/folder/file%20name", resourceURL)
+}
+
+func TestProviderClient_MethodOverrideHeader(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		methodOverrideHeader string
+		call                 func(client *ProviderClient, resource SpecResource) error
+		expectedMethod       string
+		expectedHeaderValue  string
+	}{
+		{
+			name:                 "PUT is sent as POST with the method override header",
+			methodOverrideHeader: "X-HTTP-Method-Override",
+			call: func(client *ProviderClient, resource SpecResource) error {
+				_, err := client.Put(resource, "1234", map[string]interface{}{"label": "some label"}, nil)
+				return err
+			},
+			expectedMethod:      http.MethodPost,
+			expectedHeaderValue: http.MethodPut,
+		},
+		{
+			name:                 "DELETE is sent as POST with the method override header",
+			methodOverrideHeader: "X-HTTP-Method-Override",
+			call: func(client *ProviderClient, resource SpecResource) error {
+				_, err := client.Delete(resource, "1234")
+				return err
+			},
+			expectedMethod:      http.MethodPost,
+			expectedHeaderValue: http.MethodDelete,
+		},
+		{
+			name:                 "PATCH is sent as POST with the method override header",
+			methodOverrideHeader: "X-HTTP-Method-Override",
+			call: func(client *ProviderClient, resource SpecResource) error {
+				_, err := client.Patch(context.Background(), resource, "1234", []jsonPatchOperation{{Op: jsonPatchOpReplace, Path: "/label", Value: "some label"}}, nil)
+				return err
+			},
+			expectedMethod:      http.MethodPost,
+			expectedHeaderValue: http.MethodPatch,
+		},
+		{
+			name:                 "GET is not tunneled",
+			methodOverrideHeader: "X-HTTP-Method-Override",
+			call: func(client *ProviderClient, resource SpecResource) error {
+				_, err := client.Get(resource, "1234", nil)
+				return err
+			},
+			expectedMethod: http.MethodGet,
+		},
+		{
+			name:                 "POST does not get the method override header",
+			methodOverrideHeader: "X-HTTP-Method-Override",
+			call: func(client *ProviderClient, resource SpecResource) error {
+				_, err := client.Post(resource, map[string]interface{}{"label": "some label"}, nil)
+				return err
+			},
+			expectedMethod: http.MethodPost,
+		},
+		{
+			name:                 "PUT is sent as is when the method override header is not configured",
+			methodOverrideHeader: "",
+			call: func(client *ProviderClient, resource SpecResource) error {
+				_, err := client.Put(resource, "1234", map[string]interface{}{"label": "some label"}, nil)
+				return err
+			},
+			expectedMethod: http.MethodPut,
+		},
+		{
+			name:                 "PATCH is sent as is when the method override header is not configured",
+			methodOverrideHeader: "",
+			call: func(client *ProviderClient, resource SpecResource) error {
+				_, err := client.Patch(context.Background(), resource, "1234", []jsonPatchOperation{{Op: jsonPatchOpReplace, Path: "/label", Value: "some label"}}, nil)
+				return err
+			},
+			expectedMethod: http.MethodPatch,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var receivedMethod, receivedHeaderValue string
+			api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				receivedMethod = r.Method
+				receivedHeaderValue = r.Header.Get("X-HTTP-Method-Override")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{}`))
+			}))
+			defer api.Close()
+			client := &ProviderClient{
+				openAPIBackendConfiguration: newStubBackendConfiguration(strings.TrimPrefix(api.URL, "http://"), "", "http"),
+				httpClient:                  patchableHTTPClient{&http_goclient.HttpClient{HttpClient: &http.Client{}}},
+				providerConfiguration:       providerConfiguration{},
+				apiAuthenticator:            newStubAuthenticator("Authorization", "Bearer secret", nil),
+				methodOverrideHeader:        tc.methodOverrideHeader,
+			}
+			operation := &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}}
+			resource := &specStubResource{
+				path:                    "/v1/resource",
+				resourcePostOperation:   operation,
+				resourcePutOperation:    operation,
+				resourceGetOperation:    operation,
+				resourceDeleteOperation: operation,
+			}
+			require.NoError(t, tc.call(client, resource))
+			assert.Equal(t, tc.expectedMethod, receivedMethod)
+			assert.Equal(t, tc.expectedHeaderValue, receivedHeaderValue)
+		})
+	}
+}
+
+func TestProviderClientPatch(t *testing.T) {
+	var receivedContentType, receivedPath string
+	var receivedOperations []jsonPatchOperation
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedContentType = r.Header.Get(contentTypeHeader)
+		receivedPath = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&receivedOperations)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id":"1234","label":"new label"}`))
+	}))
+	defer api.Close()
+	client := &ProviderClient{
+		openAPIBackendConfiguration: newStubBackendConfiguration(strings.TrimPrefix(api.URL, "http://"), "", "http"),
+		httpClient:                  patchableHTTPClient{&http_goclient.HttpClient{HttpClient: &http.Client{}}},
+		providerConfiguration:       providerConfiguration{},
+		apiAuthenticator:            newStubAuthenticator("Authorization", "Bearer secret", nil),
+	}
+	resource := &specStubResource{
+		path:                 "/v1/resource",
+		resourcePutOperation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}, UpdateStrategy: updateStrategyJSONPatch},
+	}
+	operations := []jsonPatchOperation{{Op: jsonPatchOpReplace, Path: "/label", Value: "new label"}, {Op: jsonPatchOpRemove, Path: "/description"}}
+	responsePayload := map[string]interface{}{}
+	res, err := client.Patch(context.Background(), resource, "1234", operations, &responsePayload)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, jsonPatchContentType, receivedContentType)
+	assert.Equal(t, "/v1/resource/1234", receivedPath)
+	assert.Equal(t, operations, receivedOperations)
+	assert.Equal(t, map[string]interface{}{"id": "1234", "label": "new label"}, responsePayload)
+}
+
+func TestProviderClientPatch_HTTPClientWithoutPatchSupport(t *testing.T) {
+	client := &ProviderClient{
+		openAPIBackendConfiguration: newStubBackendConfiguration("wwww.host.com", "/api", "http"),
+		httpClient:                  &http_goclient.HttpClientStub{},
+		providerConfiguration:       providerConfiguration{},
+		apiAuthenticator:            newStubAuthenticator("Authorization", "Bearer secret", nil),
+	}
+	resource := &specStubResource{
+		path:                 "/v1/resource",
+		resourcePutOperation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}},
+	}
+	_, err := client.Patch(context.Background(), resource, "1234", []jsonPatchOperation{}, nil)
+	assert.EqualError(t, err, "method 'PATCH' not supported by the HTTP client")
+}
+
+func TestProviderClientStreamEvents(t *testing.T) {
+	testCases := []struct {
+		name               string
+		responseStatusCode int
+		expectedEvents     []json.RawMessage
+		expectedError      string
+	}{
+		{
+			name:               "events streamed",
+			responseStatusCode: http.StatusOK,
+			expectedEvents:     []json.RawMessage{json.RawMessage(`{"step":"provisioning"}`), json.RawMessage(`{"step":"done"}`)},
+		},
+		{
+			name:               "events path not found",
+			responseStatusCode: http.StatusNotFound,
+			expectedError:      "returned a non expected status code 404",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var receivedPath, receivedAccept, receivedAuthorization string
+			api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				receivedPath = r.URL.Path
+				receivedAccept = r.Header.Get("Accept")
+				receivedAuthorization = r.Header.Get("Authorization")
+				w.Header().Set(contentTypeHeader, ndjsonContentType)
+				w.WriteHeader(tc.responseStatusCode)
+				if tc.responseStatusCode != http.StatusOK {
+					return
+				}
+				w.Write([]byte("{\"step\":\"provisioning\"}\n"))
+				w.(http.Flusher).Flush()
+				w.Write([]byte("{\"step\":\"done\"}\n"))
+			}))
+			defer api.Close()
+			client := &ProviderClient{
+				openAPIBackendConfiguration: newStubBackendConfiguration(strings.TrimPrefix(api.URL, "http://"), "", "http"),
+				httpClient:                  patchableHTTPClient{&http_goclient.HttpClient{HttpClient: &http.Client{}}},
+				providerConfiguration:       providerConfiguration{},
+				apiAuthenticator:            newStubAuthenticator("Authorization", "Bearer secret", nil),
+			}
+			resource := &specStubResource{
+				path:                 "/v1/resource",
+				resourceGetOperation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}},
+			}
+			var events []json.RawMessage
+			err := client.StreamEvents(context.Background(), resource, "1234", "/events", func(event json.RawMessage) {
+				events = append(events, event)
+			})
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, "/v1/resource/1234/events", receivedPath)
+			assert.Equal(t, ndjsonContentType, receivedAccept)
+			assert.Equal(t, "Bearer secret", receivedAuthorization)
+			assert.Equal(t, tc.expectedEvents, events)
+		})
+	}
+}
+
+func TestProviderClientStreamEvents_HTTPClientWithoutStreamSupport(t *testing.T) {
+	client := &ProviderClient{
+		openAPIBackendConfiguration: newStubBackendConfiguration("wwww.host.com", "/api", "http"),
+		httpClient:                  &http_goclient.HttpClientStub{},
+		providerConfiguration:       providerConfiguration{},
+		apiAuthenticator:            newStubAuthenticator("Authorization", "Bearer secret", nil),
+	}
+	resource := &specStubResource{
+		path:                 "/v1/resource",
+		resourceGetOperation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}},
+	}
+	err := client.StreamEvents(context.Background(), resource, "1234", "events", func(event json.RawMessage) {})
+	assert.EqualError(t, err, "streamed responses not supported by the HTTP client")
+}
+
+func TestProviderClientGet_ConditionalRequests(t *testing.T) {
+	currentVersion := "v1"
+	var receivedIfNoneMatch []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedIfNoneMatch = append(receivedIfNoneMatch, r.Header.Get(ifNoneMatchHeader))
+		etag := fmt.Sprintf(`"%s"`, currentVersion)
+		if r.Header.Get(ifNoneMatchHeader) == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set(etagHeader, etag)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(fmt.Sprintf(`{"id":"1234","version":"%s"}`, currentVersion)))
+	}))
+	defer api.Close()
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	resource := &specStubResource{
+		path:                 "/v1/resource",
+		resourceGetOperation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}},
+	}
+	// each terraform run creates a new client, the responses are shared via the cache in disk
+	newClient := func() *ProviderClient {
+		client := &ProviderClient{
+			openAPIBackendConfiguration: newStubBackendConfiguration(strings.TrimPrefix(api.URL, "http://"), "", "http"),
+			httpClient:                  patchableHTTPClient{&http_goclient.HttpClient{HttpClient: &http.Client{}}},
+			providerConfiguration:       providerConfiguration{},
+			apiAuthenticator:            newStubAuthenticator("Authorization", "Bearer secret", nil),
+			etagCache:                   &etagCache{dir: dir},
+		}
+		return client.withConditionalRequests()
+	}
+	get := func(client *ProviderClient) map[string]interface{} {
+		responsePayload := map[string]interface{}{}
+		resp, err := client.Get(resource, "1234", &responsePayload)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		return responsePayload
+	}
+
+	assert.Equal(t, "v1", get(newClient())["version"])
+	assert.Equal(t, "v1", get(newClient())["version"], "the cached response is reused if not modified")
+	currentVersion = "v2"
+	assert.Equal(t, "v2", get(newClient())["version"], "the response is fetched again if modified")
+	assert.Equal(t, "v2", get(newClient())["version"])
+	resourceClient := newClient()
+	resourceClient.conditionalRequests = false
+	assert.Equal(t, "v2", get(resourceClient)["version"], "the clients not used by data sources do not send conditional requests")
+	assert.Equal(t, []string{"", `"v1"`, `"v1"`, `"v2"`, ""}, receivedIfNoneMatch)
+}
+
+func TestProviderClientGet_KeepsNumbersAsJSONNumbers(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": 9223372036854775807, "price": 10.5}`))
+	}))
+	defer api.Close()
+	operation := &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}}
+	resource := &specStubResource{
+		path:                  "/v1/resource",
+		resourceListOperation: operation,
+		resourceGetOperation:  operation,
+		resourcePostOperation: operation,
+	}
+	for _, disableResponseCache := range []bool{true, false} {
+		client := &ProviderClient{
+			openAPIBackendConfiguration: newStubBackendConfiguration(strings.TrimPrefix(api.URL, "http://"), "", "http"),
+			httpClient:                  &http_goclient.HttpClient{HttpClient: &http.Client{}},
+			providerConfiguration:       providerConfiguration{},
+			apiAuthenticator:            newStubAuthenticator("Authorization", "Bearer secret", nil),
+		}
+		if !disableResponseCache {
+			client.responseCache = newResponseCache()
+		}
+
+		responsePayload := map[string]interface{}{}
+		_, err := client.Get(resource, "9223372036854775807", &responsePayload)
+		require.NoError(t, err)
+		assert.Equal(t, json.Number("9223372036854775807"), responsePayload["id"])
+		assert.Equal(t, json.Number("10.5"), responsePayload["price"])
+
+		// the list responses may be served from the response cache, which should keep the numbers as they are too
+		for i := 0; i < 2; i++ {
+			listPayload := map[string]interface{}{}
+			_, err = client.List(resource, &listPayload)
+			require.NoError(t, err)
+			assert.Equal(t, json.Number("9223372036854775807"), listPayload["id"])
+		}
+	}
+}
+
+func TestProviderClient_AWSSigV4SignsRequests(t *testing.T) {
+	var receivedAuthorization, receivedDate, receivedBody string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedAuthorization = r.Header.Get("Authorization")
+		receivedDate = r.Header.Get("X-Amz-Date")
+		body, _ := ioutil.ReadAll(r.Body)
+		receivedBody = string(body)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+	client := &ProviderClient{
+		openAPIBackendConfiguration: newStubBackendConfiguration(strings.TrimPrefix(api.URL, "http://"), "", "http"),
+		httpClient:                  &http_goclient.HttpClient{HttpClient: &http.Client{}},
+		providerConfiguration: providerConfiguration{
+			SecuritySchemaDefinitions: map[string]specAPIKeyAuthenticator{
+				"aws_iam": newAWSSigV4Authenticator(awsCredentials{accessKeyID: "AKIDEXAMPLE", secretAccessKey: "secret"}, "", "us-east-1"),
+			},
+		},
+		apiAuthenticator: newAPIAuthenticator(&SpecSecuritySchemes{SpecSecurityScheme{Name: "awsIAM"}}),
+	}
+	operation := &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}}
+	resource := &specStubResource{
+		path:                  "/v1/resource",
+		resourcePostOperation: operation,
+	}
+	_, err := client.Post(resource, map[string]interface{}{"label": "some label"}, nil)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(receivedAuthorization, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"), receivedAuthorization)
+	assert.Contains(t, receivedAuthorization, "/us-east-1/execute-api/aws4_request, SignedHeaders=host;x-amz-date, Signature=")
+	assert.NotEmpty(t, receivedDate)
+
+	// the signature sent should be the one of the request received by the API, that is the payload signed should be the one sent
+	expectedHeaders := map[string]string{}
+	signer := newAWSSigV4Authenticator(awsCredentials{accessKeyID: "AKIDEXAMPLE", secretAccessKey: "secret"}, "", "us-east-1")
+	signer.now = func() time.Time {
+		date, _ := time.Parse(awsSigV4DateFormat, receivedDate)
+		return date
+	}
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(receivedBody), &payload))
+	require.NoError(t, signer.sign(http.MethodPost, api.URL+"/v1/resource", expectedHeaders, payload))
+	assert.Equal(t, expectedHeaders[authorizationHeader], receivedAuthorization)
+}
+
+func TestProviderClient_HMACSignsRequests(t *testing.T) {
+	var receivedTimestamp, receivedSignature, receivedBody string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedTimestamp = r.Header.Get("X-Timestamp")
+		receivedSignature = r.Header.Get("X-Signature")
+		body, _ := ioutil.ReadAll(r.Body)
+		receivedBody = string(body)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+	secDef := newHMACSecurityDefinition("hmacAuth", "", "", "", "")
+	client := &ProviderClient{
+		openAPIBackendConfiguration: newStubBackendConfiguration(strings.TrimPrefix(api.URL, "http://"), "", "http"),
+		httpClient:                  &http_goclient.HttpClient{HttpClient: &http.Client{}},
+		providerConfiguration: providerConfiguration{
+			SecuritySchemaDefinitions: map[string]specAPIKeyAuthenticator{
+				"hmac_auth": createAPIKeyAuthenticator(secDef, "secret"),
+			},
+		},
+		apiAuthenticator: newAPIAuthenticator(&SpecSecuritySchemes{SpecSecurityScheme{Name: "hmacAuth"}}),
+	}
+	operation := &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}}
+	resource := &specStubResource{
+		path:                  "/v1/resource",
+		resourcePostOperation: operation,
+	}
+	_, err := client.Post(resource, map[string]interface{}{"label": "some label"}, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, receivedTimestamp)
+
+	// the signature should cover the timestamp and the body received by the API
+	expectedSignature := newHMACAuthenticator(secDef, "secret").computeSignature(receivedTimestamp, []byte(receivedBody))
+	assert.Equal(t, expectedSignature, receivedSignature)
+}
+
+func TestProviderClient_WithQueryParameters(t *testing.T) {
+	httpClient := &http_goclient.HttpClientStub{
+		Response: &http.Response{
+			Body: ioutil.NopCloser(strings.NewReader(`{}`)),
+		},
+	}
+	providerClient := &ProviderClient{
+		openAPIBackendConfiguration: newStubBackendConfiguration("www.host.com", "/api", "http"),
+		httpClient:                  httpClient,
+		providerConfiguration: providerConfiguration{
+			SecuritySchemaDefinitions: map[string]specAPIKeyAuthenticator{
+				"apikey_auth": newAPIKeyQueryAuthenticator("key", "secret"),
+			},
+		},
+		apiAuthenticator: newAPIAuthenticator(&SpecSecuritySchemes{SpecSecurityScheme{Name: "apikey_auth"}}),
+	}
+	operation := &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}}
+	resource := &specStubResource{
+		path:                 "/v1/resource",
+		resourceGetOperation: operation,
+		resourcePutOperation: operation,
+	}
+	_, err := providerClient.withQueryParameters(url.Values{"fields": {"id,label"}}).Get(resource, "1234", &map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "http://www.host.com/api/v1/resource/1234?fields=id%2Clabel&key=secret", httpClient.URL)
+
+	_, err = providerClient.withQueryParameters(url.Values{"update_mask": {"label"}}).Put(resource, "1234", map[string]interface{}{"label": "some label"}, &map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "http://www.host.com/api/v1/resource/1234?update_mask=label&key=secret", httpClient.URL)
+
+	_, err = providerClient.Get(resource, "1234", &map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "http://www.host.com/api/v1/resource/1234?key=secret", httpClient.URL, "the query parameters should only be sent by the client returned")
+}
+
+func TestProviderClient_OperationSecurity(t *testing.T) {
+	testCases := []struct {
+		name            string
+		operation       *specResourceOperation
+		expectedURL     string
+		expectedHeaders map[string]string
+	}{
+		{
+			name:        "operation without security uses the global security schemes",
+			operation:   &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}},
+			expectedURL: "http://www.host.com/api/v1/resource/1234?key=secret",
+		},
+		{
+			name:            "operation security overrides the global security schemes",
+			operation:       &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{{Name: "apikey_header_auth"}}},
+			expectedURL:     "http://www.host.com/api/v1/resource/1234",
+			expectedHeaders: map[string]string{"X-API-KEY": "header-secret"},
+		},
+		{
+			name:        "operation overriding the global security with no security is called without authentication",
+			operation:   &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}, AnonymousAccess: true},
+			expectedURL: "http://www.host.com/api/v1/resource/1234",
+		},
+	}
+	for _, tc := range testCases {
+		httpClient := &http_goclient.HttpClientStub{
+			Response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`{}`)),
+			},
+		}
+		providerClient := &ProviderClient{
+			openAPIBackendConfiguration: newStubBackendConfiguration("www.host.com", "/api", "http"),
+			httpClient:                  httpClient,
+			providerConfiguration: providerConfiguration{
+				SecuritySchemaDefinitions: map[string]specAPIKeyAuthenticator{
+					"apikey_auth":        newAPIKeyQueryAuthenticator("key", "secret"),
+					"apikey_header_auth": newAPIKeyHeaderAuthenticator("X-API-KEY", "header-secret"),
+				},
+			},
+			apiAuthenticator: newAPIAuthenticator(&SpecSecuritySchemes{SpecSecurityScheme{Name: "apikey_auth"}}),
+		}
+		resource := &specStubResource{
+			path:                 "/v1/resource",
+			resourceGetOperation: tc.operation,
+		}
+		_, err := providerClient.Get(resource, "1234", &map[string]interface{}{})
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expectedURL, httpClient.URL, tc.name)
+		for name, value := range tc.expectedHeaders {
+			assert.Equal(t, value, httpClient.Headers[name], tc.name)
+		}
+		if tc.expectedHeaders == nil {
+			assert.NotContains(t, httpClient.Headers, "X-API-KEY", tc.name)
+		}
+	}
+}
+
+func TestProviderClient_Retries(t *testing.T) {
+	testCases := []struct {
+		name               string
+		retryPolicy        *retryPolicy
+		operationRetry     *retryPolicy
+		retryBudget        *retryBudget
+		responseCodes      []int
+		expectedRequests   int
+		expectedStatusCode int
+		expectedSleeps     []time.Duration
+	}{
+		{
+			name:               "transient failures are retried until the request succeeds",
+			retryPolicy:        &retryPolicy{maxAttempts: 3, initialBackoff: 10 * time.Millisecond, maxBackoff: time.Second},
+			responseCodes:      []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			expectedRequests:   3,
+			expectedStatusCode: http.StatusOK,
+			expectedSleeps:     []time.Duration{10 * time.Millisecond, 20 * time.Millisecond},
+		},
+		{
+			name:               "the response of the last attempt is returned when all the attempts fail",
+			retryPolicy:        &retryPolicy{maxAttempts: 2, initialBackoff: 10 * time.Millisecond, maxBackoff: time.Second},
+			responseCodes:      []int{http.StatusServiceUnavailable, http.StatusGatewayTimeout, http.StatusOK},
+			expectedRequests:   2,
+			expectedStatusCode: http.StatusGatewayTimeout,
+			expectedSleeps:     []time.Duration{10 * time.Millisecond},
+		},
+		{
+			name:               "client errors are not retried",
+			retryPolicy:        &retryPolicy{maxAttempts: 3, initialBackoff: 10 * time.Millisecond, maxBackoff: time.Second},
+			responseCodes:      []int{http.StatusBadRequest, http.StatusOK},
+			expectedRequests:   1,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "not implemented responses are not retried",
+			retryPolicy:        &retryPolicy{maxAttempts: 3, initialBackoff: 10 * time.Millisecond, maxBackoff: time.Second},
+			responseCodes:      []int{http.StatusNotImplemented, http.StatusOK},
+			expectedRequests:   1,
+			expectedStatusCode: http.StatusNotImplemented,
+		},
+		{
+			name:               "requests are not retried if there is no retry policy",
+			responseCodes:      []int{http.StatusServiceUnavailable, http.StatusOK},
+			expectedRequests:   1,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:               "operation retry policy overrides the service one",
+			retryPolicy:        &retryPolicy{maxAttempts: 1},
+			operationRetry:     &retryPolicy{maxAttempts: 2, initialBackoff: 5 * time.Second, maxBackoff: 5 * time.Second},
+			responseCodes:      []int{http.StatusInternalServerError, http.StatusOK},
+			expectedRequests:   2,
+			expectedStatusCode: http.StatusOK,
+			expectedSleeps:     []time.Duration{5 * time.Second},
+		},
+		{
+			name:               "the waits between retries are capped by the remaining retry budget",
+			retryPolicy:        &retryPolicy{maxAttempts: 3, initialBackoff: 10 * time.Millisecond, maxBackoff: time.Second},
+			retryBudget:        &retryBudget{budget: 15 * time.Millisecond},
+			responseCodes:      []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			expectedRequests:   3,
+			expectedStatusCode: http.StatusOK,
+			expectedSleeps:     []time.Duration{10 * time.Millisecond, 5 * time.Millisecond},
+		},
+		{
+			name:               "requests are not retried once the retry budget is exhausted",
+			retryPolicy:        &retryPolicy{maxAttempts: 3, initialBackoff: 10 * time.Millisecond, maxBackoff: time.Second},
+			retryBudget:        &retryBudget{budget: 10 * time.Millisecond, spent: 10 * time.Millisecond},
+			responseCodes:      []int{http.StatusServiceUnavailable, http.StatusOK},
+			expectedRequests:   1,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:               "requests are not retried once the apply deadline is exceeded",
+			retryPolicy:        &retryPolicy{maxAttempts: 3, initialBackoff: 10 * time.Millisecond, maxBackoff: time.Second},
+			retryBudget:        &retryBudget{applyDeadline: time.Minute, deadline: time.Now().Add(-time.Second)},
+			responseCodes:      []int{http.StatusServiceUnavailable, http.StatusOK},
+			expectedRequests:   1,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := 0
+			api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.responseCodes[requests])
+				w.Write([]byte(`{}`))
+				requests++
+			}))
+			defer api.Close()
+			var sleeps []time.Duration
+			client := &ProviderClient{
+				openAPIBackendConfiguration: newStubBackendConfiguration(strings.TrimPrefix(api.URL, "http://"), "", "http"),
+				httpClient:                  &http_goclient.HttpClient{HttpClient: &http.Client{}},
+				providerConfiguration:       providerConfiguration{},
+				apiAuthenticator:            newStubAuthenticator("Authorization", "Bearer secret", nil),
+				retryPolicy:                 tc.retryPolicy,
+				retryBudget:                 tc.retryBudget,
+				sleep:                       func(d time.Duration) { sleeps = append(sleeps, d) },
+			}
+			resource := &specStubResource{
+				path:                 "/v1/resource",
+				resourceGetOperation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}, Retry: tc.operationRetry},
+			}
+			res, _ := client.Get(resource, "1234", &map[string]interface{}{})
+			require.NotNil(t, res)
+			assert.Equal(t, tc.expectedStatusCode, res.StatusCode)
+			assert.Equal(t, tc.expectedRequests, requests)
+			assert.Equal(t, tc.expectedSleeps, sleeps)
+		})
+	}
+}
+
+func TestProviderClient_RetriesNonIdempotentRequests(t *testing.T) {
+	testCases := []struct {
+		name             string
+		operationRetry   *retryPolicy
+		expectedRequests int
+	}{
+		{
+			name:             "the service retry policy does not apply to POST requests",
+			expectedRequests: 1,
+		},
+		{
+			name:             "POST requests are retried if the operation opts in",
+			operationRetry:   &retryPolicy{maxAttempts: 2, initialBackoff: 10 * time.Millisecond, maxBackoff: time.Second},
+			expectedRequests: 2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := 0
+			api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte(`{}`))
+			}))
+			defer api.Close()
+			client := &ProviderClient{
+				openAPIBackendConfiguration: newStubBackendConfiguration(strings.TrimPrefix(api.URL, "http://"), "", "http"),
+				httpClient:                  &http_goclient.HttpClient{HttpClient: &http.Client{}},
+				providerConfiguration:       providerConfiguration{},
+				apiAuthenticator:            newStubAuthenticator("Authorization", "Bearer secret", nil),
+				retryPolicy:                 &retryPolicy{maxAttempts: 3, initialBackoff: 10 * time.Millisecond, maxBackoff: time.Second},
+				sleep:                       func(d time.Duration) {},
+			}
+			resource := &specStubResource{
+				path:                  "/v1/resource",
+				resourcePostOperation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}, Retry: tc.operationRetry},
+			}
+			res, _ := client.Post(resource, map[string]interface{}{}, &map[string]interface{}{})
+			require.NotNil(t, res)
+			assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+			assert.Equal(t, tc.expectedRequests, requests)
+		})
+	}
+}
+
+func TestProviderClient_RateLimited(t *testing.T) {
+	testCases := []struct {
+		name               string
+		rateLimitMaxWait   time.Duration
+		retryPolicy        *retryPolicy
+		retryBudget        *retryBudget
+		responseCodes      []int
+		retryAfter         string
+		expectedRequests   int
+		expectedStatusCode int
+		expectedSleeps     []time.Duration
+	}{
+		{
+			name:               "rate limited requests are retried after the time requested by the API",
+			rateLimitMaxWait:   time.Minute,
+			responseCodes:      []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:         "10",
+			expectedRequests:   3,
+			expectedStatusCode: http.StatusOK,
+			expectedSleeps:     []time.Duration{10 * time.Second, 10 * time.Second},
+		},
+		{
+			name:               "rate limited requests are not retried once the max rate limit wait would be exceeded",
+			rateLimitMaxWait:   15 * time.Second,
+			responseCodes:      []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:         "10",
+			expectedRequests:   2,
+			expectedStatusCode: http.StatusTooManyRequests,
+			expectedSleeps:     []time.Duration{10 * time.Second},
+		},
+		{
+			name:               "rate limited requests without Retry-After header are retried after a second",
+			rateLimitMaxWait:   time.Minute,
+			responseCodes:      []int{http.StatusTooManyRequests, http.StatusOK},
+			expectedRequests:   2,
+			expectedStatusCode: http.StatusOK,
+			expectedSleeps:     []time.Duration{time.Second},
+		},
+		{
+			name:               "rate limited requests are not retried if there is no max rate limit wait",
+			responseCodes:      []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:         "10",
+			expectedRequests:   1,
+			expectedStatusCode: http.StatusTooManyRequests,
+		},
+		{
+			name:               "rate limited requests do not count as transient failure attempts",
+			rateLimitMaxWait:   time.Minute,
+			retryPolicy:        &retryPolicy{maxAttempts: 2, initialBackoff: 100 * time.Millisecond, maxBackoff: time.Second},
+			responseCodes:      []int{http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:         "1",
+			expectedRequests:   4,
+			expectedStatusCode: http.StatusOK,
+			expectedSleeps:     []time.Duration{time.Second, 100 * time.Millisecond, time.Second},
+		},
+		{
+			name:               "rate limited requests are not retried if the wait would exceed the remaining retry budget",
+			rateLimitMaxWait:   time.Minute,
+			retryBudget:        &retryBudget{budget: 15 * time.Second},
+			responseCodes:      []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:         "10",
+			expectedRequests:   2,
+			expectedStatusCode: http.StatusTooManyRequests,
+			expectedSleeps:     []time.Duration{10 * time.Second},
+		},
+		{
+			name:               "rate limited requests are not retried once the apply deadline is exceeded",
+			rateLimitMaxWait:   time.Minute,
+			retryBudget:        &retryBudget{applyDeadline: time.Minute, deadline: time.Now().Add(-time.Second)},
+			responseCodes:      []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:         "10",
+			expectedRequests:   1,
+			expectedStatusCode: http.StatusTooManyRequests,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := 0
+			api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.retryAfter != "" {
+					w.Header().Set("Retry-After", tc.retryAfter)
+				}
+				w.WriteHeader(tc.responseCodes[requests])
+				w.Write([]byte(`{}`))
+				requests++
+			}))
+			defer api.Close()
+			var sleeps []time.Duration
+			client := &ProviderClient{
+				openAPIBackendConfiguration: newStubBackendConfiguration(strings.TrimPrefix(api.URL, "http://"), "", "http"),
+				httpClient:                  &http_goclient.HttpClient{HttpClient: &http.Client{}},
+				providerConfiguration:       providerConfiguration{},
+				apiAuthenticator:            newStubAuthenticator("Authorization", "Bearer secret", nil),
+				retryPolicy:                 tc.retryPolicy,
+				rateLimitMaxWait:            tc.rateLimitMaxWait,
+				retryBudget:                 tc.retryBudget,
+				sleep:                       func(d time.Duration) { sleeps = append(sleeps, d) },
+			}
+			resource := &specStubResource{
+				path:                 "/v1/resource",
+				resourceGetOperation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}},
+			}
+			res, _ := client.Get(resource, "1234", &map[string]interface{}{})
+			require.NotNil(t, res)
+			assert.Equal(t, tc.expectedStatusCode, res.StatusCode)
+			assert.Equal(t, tc.expectedRequests, requests)
+			assert.Equal(t, tc.expectedSleeps, sleeps)
+		})
+	}
+}
+
+func TestProviderClient_SecondaryAPIKeyFailover(t *testing.T) {
+	testCases := []struct {
+		name               string
+		secondaryKey       string
+		validKeys          map[string]bool
+		expectedKeys       []string
+		expectedStatusCode int
+	}{
+		{
+			name:               "requests are authenticated with the primary api key while it is accepted",
+			secondaryKey:       "secondary",
+			validKeys:          map[string]bool{"primary": true, "secondary": true},
+			expectedKeys:       []string{"primary", "primary"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "requests fail over to the secondary api key once the primary one is rejected",
+			secondaryKey:       "secondary",
+			validKeys:          map[string]bool{"secondary": true},
+			expectedKeys:       []string{"primary", "secondary", "secondary"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "the response is returned as is when the secondary api key is rejected too",
+			secondaryKey:       "secondary",
+			validKeys:          map[string]bool{},
+			expectedKeys:       []string{"primary", "secondary", "secondary"},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "requests are not sent again if there is no secondary api key",
+			validKeys:          map[string]bool{},
+			expectedKeys:       []string{"primary", "primary"},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var keys []string
+			api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				key := r.Header.Get("X-API-Key")
+				keys = append(keys, key)
+				if !tc.validKeys[key] {
+					w.WriteHeader(http.StatusUnauthorized)
+					w.Write([]byte(`{"message":"invalid api key"}`))
+					return
+				}
+				w.Write([]byte(`{}`))
+			}))
+			defer api.Close()
+			var authenticator specAPIKeyAuthenticator = newAPIKeyHeaderAuthenticator("X-API-Key", "primary")
+			if tc.secondaryKey != "" {
+				authenticator = newAPIKeyFailoverAuthenticator("api_key_auth", authenticator, newAPIKeyHeaderAuthenticator("X-API-Key", tc.secondaryKey))
+			}
+			client := &ProviderClient{
+				openAPIBackendConfiguration: newStubBackendConfiguration(strings.TrimPrefix(api.URL, "http://"), "", "http"),
+				httpClient:                  &http_goclient.HttpClient{HttpClient: &http.Client{}},
+				providerConfiguration: providerConfiguration{
+					SecuritySchemaDefinitions: map[string]specAPIKeyAuthenticator{"api_key_auth": authenticator},
+				},
+				apiAuthenticator: newAPIAuthenticator(&SpecSecuritySchemes{{Name: "api_key_auth"}}),
+			}
+			resource := &specStubResource{
+				path:                 "/v1/resource",
+				resourceGetOperation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}},
+			}
+			res, _ := client.Get(resource, "1234", &map[string]interface{}{})
+			require.NotNil(t, res)
+			res, _ = client.Get(resource, "1234", &map[string]interface{}{})
+			require.NotNil(t, res)
+			assert.Equal(t, tc.expectedStatusCode, res.StatusCode)
+			assert.Equal(t, tc.expectedKeys, keys)
+		})
+	}
+}
+
+func TestProviderClient_CredentialHelper(t *testing.T) {
+	executionsFile, err := ioutil.TempFile("", "")
+	require.NoError(t, err)
+	defer os.Remove(executionsFile.Name())
+	var tokens []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get("Authorization"))
+		// the first token is revoked after the first request
+		if len(tokens) > 1 && r.Header.Get("Authorization") == "Bearer token1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"token revoked"}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+	client := &ProviderClient{
+		openAPIBackendConfiguration: newStubBackendConfiguration(strings.TrimPrefix(api.URL, "http://"), "", "http"),
+		httpClient:                  &http_goclient.HttpClient{HttpClient: &http.Client{}},
+		providerConfiguration:       providerConfiguration{},
+		apiAuthenticator:            newAPIAuthenticator(&SpecSecuritySchemes{}),
+		credentialHelper: newCredentialHelper(&ServiceCredentialHelper{
+			Command: newCountingCredentialHelperCommand(executionsFile.Name(), `{"headers": {"Authorization": "Bearer token%d"}}`),
+			Timeout: 10 * time.Second,
+		}),
+	}
+	resource := &specStubResource{
+		path:                    "/v1/resource",
+		resourceGetOperation:    &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}},
+		resourceDeleteOperation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}, AnonymousAccess: true},
+	}
+	for i := 0; i < 3; i++ {
+		res, err := client.Get(resource, "1234", &map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+	_, err = client.Delete(resource, "1234")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bearer token1", "Bearer token1", "Bearer token2", "Bearer token2", ""}, tokens)
+}
+
+func TestProviderClient_TrackCalls(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+	tracker := newCallTracker(time.Hour)
+	client := &ProviderClient{
+		openAPIBackendConfiguration: newStubBackendConfiguration(strings.TrimPrefix(api.URL, "http://"), "", "http"),
+		httpClient:                  &http_goclient.HttpClient{HttpClient: &http.Client{}},
+		providerConfiguration:       providerConfiguration{},
+		apiAuthenticator:            newStubAuthenticator("Authorization", "Bearer secret", nil),
+		callTracker:                 tracker,
+	}
+	resource := &specStubResource{
+		name:                  "cdn_v1",
+		path:                  "/v1/cdns",
+		resourcePostOperation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}},
+		resourceGetOperation:  &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}},
+	}
+	_, err := client.Post(resource, map[string]interface{}{}, &map[string]interface{}{})
+	require.NoError(t, err)
+	_, err = client.Get(resource, "1234", &map[string]interface{}{})
+	require.NoError(t, err)
+	require.Len(t, tracker.slowest, 2)
+	assert.Equal(t, 2, tracker.calls)
+	assert.Equal(t, 0, tracker.slowCalls)
+	for _, call := range tracker.slowest {
+		assert.Equal(t, "cdn_v1", call.resourceName)
+		switch call.operation {
+		case callOperationCreate:
+			assert.Equal(t, httpPost, call.method)
+			assert.Empty(t, call.id)
+		case callOperationRead:
+			assert.Equal(t, httpGet, call.method)
+			assert.Equal(t, "1234", call.id)
+		default:
+			assert.Fail(t, "unexpected operation tracked", call.operation)
+		}
+	}
+
+	operationTracker := newCallTracker(time.Hour)
+	_, err = client.GetContext(context.WithValue(context.Background(), operationCallTrackerKey{}, operationTracker), resource, "1234", &map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, tracker.calls)
+	assert.Equal(t, 1, operationTracker.calls, "the calls made with the context of a resource operation should be tracked for the operation too")
+}
+
+func TestProviderClient_MaxParallelAPICalls(t *testing.T) {
+	var mutex sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mutex.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mutex.Lock()
+		inFlight--
+		mutex.Unlock()
+		w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+	client := &ProviderClient{
+		openAPIBackendConfiguration: newStubBackendConfiguration(strings.TrimPrefix(api.URL, "http://"), "", "http"),
+		httpClient:                  &http_goclient.HttpClient{HttpClient: &http.Client{}},
+		providerConfiguration:       providerConfiguration{},
+		apiAuthenticator:            newAPIAuthenticator(&SpecSecuritySchemes{}),
+		apiCallLimiter:              newAPICallLimiter(2),
+	}
+	resource := &specStubResource{
+		path:                 "/v1/resource",
+		resourceGetOperation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}},
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := client.Get(resource, "1234", &map[string]interface{}{})
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 2, maxInFlight)
+}
+
+func TestProviderClient_GetAsyncOperation(t *testing.T) {
+	var pathReceived, authorizationReceived string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pathReceived = r.URL.Path
+		authorizationReceived = r.Header.Get("Authorization")
+		w.Write([]byte(`{"status":"running"}`))
+	}))
+	defer api.Close()
+	apiHost := strings.TrimPrefix(api.URL, "http://")
+	resource := &specStubResource{
+		name:                 "cdn_v1",
+		path:                 "/v1/cdns",
+		resourceGetOperation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}},
+	}
+	testCases := []struct {
+		name          string
+		operationURL  string
+		expectedPath  string
+		expectedError string
+	}{
+		{
+			name:         "relative URL",
+			operationURL: "/v1/operations/op1",
+			expectedPath: "/v1/operations/op1",
+		},
+		{
+			name:         "absolute URL pointing at the API host",
+			operationURL: api.URL + "/v1/operations/op2",
+			expectedPath: "/v1/operations/op2",
+		},
+		{
+			name:          "absolute URL pointing at a different host",
+			operationURL:  "https://other.example.com/v1/operations/op1",
+			expectedError: fmt.Sprintf("asynchronous operation URL 'https://other.example.com/v1/operations/op1' does not belong to the API host '%s'", apiHost),
+		},
+		{
+			name:          "absolute URL pointing at the API host with a different protocol",
+			operationURL:  "https://" + apiHost + "/v1/operations/op1",
+			expectedError: fmt.Sprintf("asynchronous operation URL 'https://%s/v1/operations/op1' does not use the API protocol 'http'", apiHost),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pathReceived, authorizationReceived = "", ""
+			client := &ProviderClient{
+				openAPIBackendConfiguration: newStubBackendConfiguration(apiHost, "", "http"),
+				httpClient:                  &http_goclient.HttpClient{HttpClient: &http.Client{}},
+				providerConfiguration:       providerConfiguration{},
+				apiAuthenticator:            newStubAuthenticator("Authorization", "Bearer secret", nil),
+			}
+			responsePayload := map[string]interface{}{}
+			resp, err := client.GetAsyncOperation(context.Background(), resource, tc.operationURL, &responsePayload)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				assert.Empty(t, pathReceived)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, tc.expectedPath, pathReceived)
+			assert.Equal(t, "Bearer secret", authorizationReceived)
+			assert.Equal(t, "running", responsePayload["status"])
+		})
+	}
+}
+
+func TestProviderClient_CallCleanupOperation(t *testing.T) {
+	var methodReceived, pathReceived, authorizationReceived string
+	var bodyReceived []byte
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methodReceived = r.Method
+		pathReceived = r.URL.Path
+		authorizationReceived = r.Header.Get("Authorization")
+		bodyReceived, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer api.Close()
+	resource := &specStubResource{
+		name: "cdn_v1",
+		path: "/v1/cdns",
+	}
+	testCases := []struct {
+		name           string
+		cleanup        *onFailureCleanupOperation
+		expectedMethod string
+		expectedPath   string
+	}{
+		{
+			name:           "cleanup operation in a sub-path of the instance path",
+			cleanup:        &onFailureCleanupOperation{path: "/v1/cdns/{id}/abort", method: httpPost, subPath: "/abort", operation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}}},
+			expectedMethod: http.MethodPost,
+			expectedPath:   "/v1/cdns/1234/abort",
+		},
+		{
+			name:           "cleanup operation in the instance path",
+			cleanup:        &onFailureCleanupOperation{path: "/v1/cdns/{id}", method: httpDelete, operation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}}},
+			expectedMethod: http.MethodDelete,
+			expectedPath:   "/v1/cdns/1234",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			methodReceived, pathReceived, authorizationReceived = "", "", ""
+			client := &ProviderClient{
+				openAPIBackendConfiguration: newStubBackendConfiguration(strings.TrimPrefix(api.URL, "http://"), "", "http"),
+				httpClient:                  patchableHTTPClient{&http_goclient.HttpClient{HttpClient: &http.Client{}}},
+				providerConfiguration:       providerConfiguration{},
+				apiAuthenticator:            newStubAuthenticator("Authorization", "Bearer secret", nil),
+			}
+			resp, err := client.CallCleanupOperation(context.Background(), resource, "1234", tc.cleanup)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+			assert.Equal(t, tc.expectedMethod, methodReceived)
+			assert.Equal(t, tc.expectedPath, pathReceived)
+			assert.Equal(t, "Bearer secret", authorizationReceived)
+			assert.Empty(t, bodyReceived)
+		})
+	}
+}
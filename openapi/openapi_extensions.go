@@ -0,0 +1,387 @@
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Root level extensions
+const extTfProviderMultiRegionFQDN = "x-terraform-provider-multiregion-fqdn"
+const extTfProviderRegions = "x-terraform-provider-regions"
+const extTfPathParametersRawSlashes = "x-terraform-path-parameters-raw-slashes"
+const extTfResourceRegionsFmt = "x-terraform-resource-regions-%s"
+
+// Definition level extensions
+const extTfImmutable = "x-terraform-immutable"
+const extTfForceNew = "x-terraform-force-new"
+const extTfForceNewReason = "x-terraform-force-new-reason"
+const extTfSensitive = "x-terraform-sensitive"
+const extTfFieldName = "x-terraform-field-name"
+const extTfFieldStatus = "x-terraform-field-status"
+const extTfFieldStatusMessage = "x-terraform-field-status-message"
+const extTfFieldCopyTo = "x-terraform-field-copy-to"
+const extTfPropertyAlias = "x-terraform-property-alias"
+const extTfSetHashKeys = "x-terraform-set-hash-keys"
+const extTfSetHashIgnoreCase = "x-terraform-set-hash-ignore-case"
+const extTfDerived = "x-terraform-derived"
+const extTfRefreshOnDemand = "x-terraform-refresh-on-demand"
+const extTfRequiredIf = "x-terraform-required-if"
+const extTfDefaultFromAPI = "x-terraform-default-from-api"
+const extTfID = "x-terraform-id"
+const extTfComputed = "x-terraform-computed"
+const extTfComplexObjectType = "x-terraform-complex-object-legacy-config"
+
+// Operation level extensions
+const extTfResourceTimeout = "x-terraform-resource-timeout"
+const extTfExcludeResource = "x-terraform-exclude-resource"
+const extTfExcludeDataSourceInstance = "x-terraform-exclude-data-source-instance"
+const extTfExcludeDataSource = "x-terraform-exclude-data-source"
+const extTfImportOnly = "x-terraform-import-only"
+const extTfResourceAutoImport = "x-terraform-resource-auto-import"
+const extTfOnFailureCleanup = "x-terraform-on-failure-cleanup"
+const extTfConsoleURLTemplate = "x-terraform-console-url-template"
+const extTfBatchRead = "x-terraform-batch-read"
+const extTfDeleteConfirmViaList = "x-terraform-delete-confirm-via-list"
+const extTfDeleteWaitForNotFound = "x-terraform-delete-wait-for-not-found"
+const extTfEventualConsistency = "x-terraform-eventual-consistency"
+const extTfRefreshFieldsQueryParam = "x-terraform-refresh-fields-query-param"
+const extTfUpdateStrategy = "x-terraform-update-strategy"
+const extTfResourceName = "x-terraform-resource-name"
+const extTfResourceURL = "x-terraform-resource-host"
+const extTfResourceScheme = "x-terraform-resource-scheme"
+const extTfRetry = "x-terraform-retry"
+const extTfCompositeID = "x-terraform-composite-id"
+
+// Operation response level extensions
+const extTfResourcePollEnabled = "x-terraform-resource-poll-enabled"
+const extTfResourcePollTargetStatuses = "x-terraform-resource-poll-completed-statuses"
+const extTfResourcePollPendingStatuses = "x-terraform-resource-poll-pending-statuses"
+const extTfResourcePollFailedStatuses = "x-terraform-resource-poll-failed-statuses"
+const extTfResourcePollStatusPath = "x-terraform-resource-poll-status-path"
+const extTfResourcePollInterval = "x-terraform-resource-poll-interval"
+const extTfResourcePollStrategy = "x-terraform-resource-poll-strategy"
+const extTfResourcePollMaxInterval = "x-terraform-resource-poll-max-interval"
+const extTfResourcePollEventsPath = "x-terraform-resource-poll-events-path"
+const extTfResourcePollEventsProperty = "x-terraform-resource-poll-events-property"
+const extTfResourcePollLocationHeader = "x-terraform-resource-poll-location-header"
+const extTfResourceSummaryResponse = "x-terraform-resource-summary-response"
+
+// Parameter level extensions
+const extTfHeader = "x-terraform-header"
+const extTfUpdateMask = "x-terraform-update-mask"
+
+// Security definition level extensions
+const extTfAuthenticationSchemeBearer = "x-terraform-authentication-scheme-bearer"
+const extTfAuthenticationRefreshToken = "x-terraform-refresh-token-url"
+const extTfTokenIntrospectionURL = "x-terraform-token-introspection-url"
+const extTfAuth = "x-terraform-auth"
+const extTfAuthAWSService = "x-terraform-auth-aws-service"
+const extTfAuthAWSRegion = "x-terraform-auth-aws-region"
+const extTfAuthHMACSignatureHeader = "x-terraform-auth-hmac-signature-header"
+const extTfAuthHMACTimestampHeader = "x-terraform-auth-hmac-timestamp-header"
+const extTfAuthHMACAlgorithm = "x-terraform-auth-hmac-algorithm"
+const extTfAuthHMACSignaturePrefix = "x-terraform-auth-hmac-signature-prefix"
+const extTfAuthSecondaryKey = "x-terraform-auth-secondary-key"
+
+// extAmazonAPIGatewayAuthType is the extension API Gateway sets in the security definitions of the exported OpenAPI
+// documents, with the value awsSigv4 for the APIs protected by IAM
+const extAmazonAPIGatewayAuthType = "x-amazon-apigateway-authtype"
+
+// extTfPrefix is the prefix of the extensions owned by the provider. Extensions with this prefix that are not registered
+// are reported as not supported (e.g. typos)
+const extTfPrefix = "x-terraform-"
+
+// ExtensionType defines the type of the value expected for an extension
+type ExtensionType string
+
+const (
+	// ExtensionTypeString defines an extension expecting a string value
+	ExtensionTypeString ExtensionType = "string"
+	// ExtensionTypeBoolean defines an extension expecting a boolean value
+	ExtensionTypeBoolean ExtensionType = "boolean"
+	// ExtensionTypeDuration defines an extension expecting a duration, either as a string (e.g. 20s, 5m) or an integer number of seconds
+	ExtensionTypeDuration ExtensionType = "duration"
+	// ExtensionTypeAny defines an extension accepting any value (e.g. objects), which is expected to be checked by the extension Validate hook
+	ExtensionTypeAny ExtensionType = "any"
+)
+
+// ExtensionLocation defines the part of the OpenAPI document where an extension can be used
+type ExtensionLocation string
+
+const (
+	// ExtensionLocationDocument defines the root level of the OpenAPI document
+	ExtensionLocationDocument ExtensionLocation = "document"
+	// ExtensionLocationPath defines the path items
+	ExtensionLocationPath ExtensionLocation = "path"
+	// ExtensionLocationOperation defines the path operations
+	ExtensionLocationOperation ExtensionLocation = "operation"
+	// ExtensionLocationParameter defines the parameters, both the global ones and the operation ones
+	ExtensionLocationParameter ExtensionLocation = "parameter"
+	// ExtensionLocationResponse defines the responses, both the global ones and the operation ones
+	ExtensionLocationResponse ExtensionLocation = "response"
+	// ExtensionLocationSchema defines the schemas (definitions and their properties)
+	ExtensionLocationSchema ExtensionLocation = "schema"
+	// ExtensionLocationSecurityDefinition defines the security definitions
+	ExtensionLocationSecurityDefinition ExtensionLocation = "security definition"
+)
+
+// Extension describes a vendor extension supported in the OpenAPI document
+type Extension struct {
+	// Name of the extension (e.g. x-terraform-resource-name). Must start with 'x-'
+	Name string
+	// Prefix when enabled makes the extension match every extension starting with the name (e.g. x-terraform-resource-regions-)
+	Prefix bool
+	// Type of the value expected
+	Type ExtensionType
+	// Locations of the document where the extension can be used
+	Locations []ExtensionLocation
+	// Validate is an optional hook called with the value of the extension, once the type has been checked, enabling extra
+	// validations (e.g. allowed values)
+	Validate func(value interface{}) error
+}
+
+func (e Extension) matches(name string) bool {
+	if e.Prefix {
+		return strings.HasPrefix(name, strings.ToLower(e.Name))
+	}
+	return name == strings.ToLower(e.Name)
+}
+
+func (e Extension) isSupportedIn(location ExtensionLocation) bool {
+	for _, l := range e.Locations {
+		if l == location {
+			return true
+		}
+	}
+	return false
+}
+
+// validateValue checks the value is of the extension type and calls the extension Validate hook (if any)
+func (e Extension) validateValue(value interface{}) error {
+	switch e.Type {
+	case ExtensionTypeString:
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("expected a string value but got '%v'", value)
+		}
+	case ExtensionTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("expected a boolean value but got '%v'", value)
+		}
+	case ExtensionTypeDuration:
+		if _, err := parseExtensionDuration(value); err != nil {
+			return err
+		}
+	}
+	if e.Validate != nil {
+		return e.Validate(value)
+	}
+	return nil
+}
+
+// extensionRegistry contains the extensions supported by the provider, including the ones registered by the binaries
+// embedding the provider
+type extensionRegistry struct {
+	mutex      sync.RWMutex
+	extensions []Extension
+}
+
+func (r *extensionRegistry) register(extension Extension) error {
+	if !strings.HasPrefix(strings.ToLower(extension.Name), "x-") {
+		return fmt.Errorf("extension name '%s' not valid, extension names must start with 'x-'", extension.Name)
+	}
+	if len(extension.Locations) == 0 {
+		return fmt.Errorf("extension '%s' must be supported in at least one location", extension.Name)
+	}
+	switch extension.Type {
+	case ExtensionTypeString, ExtensionTypeBoolean, ExtensionTypeDuration, ExtensionTypeAny:
+	default:
+		return fmt.Errorf("extension '%s' type '%s' not supported", extension.Name, extension.Type)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, e := range r.extensions {
+		if strings.EqualFold(e.Name, extension.Name) {
+			return fmt.Errorf("extension '%s' is already registered", extension.Name)
+		}
+	}
+	r.extensions = append(r.extensions, extension)
+	return nil
+}
+
+func (r *extensionRegistry) lookup(name string) (Extension, bool) {
+	name = strings.ToLower(name)
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	for _, e := range r.extensions {
+		if e.matches(name) {
+			return e, true
+		}
+	}
+	return Extension{}, false
+}
+
+// validate returns an error if the extension is not registered (only for extensions with the x-terraform- prefix, the
+// rest are ignored), is not supported in the given location or its value is not valid
+func (r *extensionRegistry) validate(location ExtensionLocation, name string, value interface{}) error {
+	extension, registered := r.lookup(name)
+	if !registered {
+		if strings.HasPrefix(strings.ToLower(name), extTfPrefix) {
+			return fmt.Errorf("extension '%s' is not supported", name)
+		}
+		return nil
+	}
+	if !extension.isSupportedIn(location) {
+		var locations []string
+		for _, l := range extension.Locations {
+			locations = append(locations, string(l))
+		}
+		return fmt.Errorf("extension '%s' is not supported in the %s level, only in: %s", name, location, strings.Join(locations, ", "))
+	}
+	if err := extension.validateValue(value); err != nil {
+		return fmt.Errorf("extension '%s' value is not valid: %s", name, err)
+	}
+	return nil
+}
+
+// validateAll validates all the given extensions returning the errors sorted by extension name
+func (r *extensionRegistry) validateAll(location ExtensionLocation, extensions map[string]interface{}) []error {
+	var names []string
+	for name := range extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var errs []error
+	for _, name := range names {
+		if err := r.validate(location, name, extensions[name]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// oneOf returns a Validate hook that makes sure the value is one of the given ones
+func oneOf(values ...string) func(value interface{}) error {
+	return func(value interface{}) error {
+		for _, v := range values {
+			if value == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("value '%v' not supported, supported values: %s", value, strings.Join(values, ", "))
+	}
+}
+
+func newBuiltInExtensionRegistry() *extensionRegistry {
+	document := []ExtensionLocation{ExtensionLocationDocument}
+	schema := []ExtensionLocation{ExtensionLocationSchema}
+	operation := []ExtensionLocation{ExtensionLocationOperation}
+	response := []ExtensionLocation{ExtensionLocationResponse}
+	parameter := []ExtensionLocation{ExtensionLocationParameter}
+	securityDefinition := []ExtensionLocation{ExtensionLocationSecurityDefinition}
+	extensions := []Extension{
+		{Name: extTfProviderMultiRegionFQDN, Type: ExtensionTypeString, Locations: document},
+		{Name: extTfProviderRegions, Type: ExtensionTypeString, Locations: document},
+		{Name: extTfPathParametersRawSlashes, Type: ExtensionTypeBoolean, Locations: document},
+		{Name: strings.TrimSuffix(extTfResourceRegionsFmt, "%s"), Prefix: true, Type: ExtensionTypeString, Locations: document},
+
+		{Name: extTfImmutable, Type: ExtensionTypeBoolean, Locations: schema},
+		{Name: extTfForceNew, Type: ExtensionTypeBoolean, Locations: schema},
+		{Name: extTfForceNewReason, Type: ExtensionTypeString, Locations: schema},
+		{Name: extTfSensitive, Type: ExtensionTypeBoolean, Locations: schema},
+		{Name: extTfFieldName, Type: ExtensionTypeString, Locations: schema},
+		{Name: extTfFieldStatus, Type: ExtensionTypeBoolean, Locations: schema},
+		{Name: extTfFieldStatusMessage, Type: ExtensionTypeBoolean, Locations: schema},
+		{Name: extTfFieldCopyTo, Type: ExtensionTypeString, Locations: schema},
+		{Name: extTfPropertyAlias, Type: ExtensionTypeString, Locations: schema},
+		{Name: extTfSetHashKeys, Type: ExtensionTypeString, Locations: schema},
+		{Name: extTfSetHashIgnoreCase, Type: ExtensionTypeBoolean, Locations: schema},
+		{Name: extTfDerived, Type: ExtensionTypeString, Locations: schema},
+		{Name: extTfRefreshOnDemand, Type: ExtensionTypeBoolean, Locations: schema},
+		{Name: extTfRequiredIf, Type: ExtensionTypeString, Locations: schema},
+		{Name: extTfDefaultFromAPI, Type: ExtensionTypeAny, Locations: schema, Validate: validateAPIDefaultExtension},
+		{Name: extTfID, Type: ExtensionTypeBoolean, Locations: schema},
+		{Name: extTfComputed, Type: ExtensionTypeBoolean, Locations: schema},
+		{Name: extTfComplexObjectType, Type: ExtensionTypeBoolean, Locations: schema},
+
+		{Name: extTfResourceTimeout, Type: ExtensionTypeDuration, Locations: operation},
+		{Name: extTfExcludeResource, Type: ExtensionTypeBoolean, Locations: operation},
+		{Name: extTfExcludeDataSourceInstance, Type: ExtensionTypeBoolean, Locations: operation},
+		{Name: extTfExcludeDataSource, Type: ExtensionTypeBoolean, Locations: operation},
+		{Name: extTfImportOnly, Type: ExtensionTypeBoolean, Locations: operation},
+		{Name: extTfResourceAutoImport, Type: ExtensionTypeBoolean, Locations: operation},
+		{Name: extTfOnFailureCleanup, Type: ExtensionTypeAny, Locations: operation, Validate: validateOnFailureCleanupExtension},
+		{Name: extTfConsoleURLTemplate, Type: ExtensionTypeString, Locations: operation},
+		{Name: extTfBatchRead, Type: ExtensionTypeBoolean, Locations: operation},
+		{Name: extTfDeleteConfirmViaList, Type: ExtensionTypeBoolean, Locations: operation},
+		{Name: extTfDeleteWaitForNotFound, Type: ExtensionTypeBoolean, Locations: operation},
+		{Name: extTfEventualConsistency, Type: ExtensionTypeAny, Locations: operation, Validate: validateEventualConsistencyExtension},
+		{Name: extTfRefreshFieldsQueryParam, Type: ExtensionTypeString, Locations: operation},
+		{Name: extTfUpdateStrategy, Type: ExtensionTypeString, Locations: operation, Validate: oneOf(string(updateStrategyPut), string(updateStrategyJSONPatch))},
+		{Name: extTfResourceName, Type: ExtensionTypeString, Locations: operation},
+		{Name: extTfResourceURL, Type: ExtensionTypeString, Locations: operation},
+		{Name: extTfResourceScheme, Type: ExtensionTypeString, Locations: operation, Validate: oneOf(httpScheme, httpsScheme)},
+		{Name: extTfRetry, Type: ExtensionTypeAny, Locations: []ExtensionLocation{ExtensionLocationPath, ExtensionLocationOperation}, Validate: validateRetryExtension},
+		{Name: extTfCompositeID, Type: ExtensionTypeString, Locations: operation},
+
+		{Name: extTfResourcePollEnabled, Type: ExtensionTypeBoolean, Locations: response},
+		{Name: extTfResourcePollTargetStatuses, Type: ExtensionTypeString, Locations: response},
+		{Name: extTfResourcePollPendingStatuses, Type: ExtensionTypeString, Locations: response},
+		{Name: extTfResourcePollFailedStatuses, Type: ExtensionTypeString, Locations: response},
+		{Name: extTfResourcePollStatusPath, Type: ExtensionTypeString, Locations: response, Validate: validatePayloadPathExtension},
+		{Name: extTfResourcePollInterval, Type: ExtensionTypeDuration, Locations: response},
+		{Name: extTfResourcePollStrategy, Type: ExtensionTypeString, Locations: response, Validate: oneOf(string(pollStrategyConstant), string(pollStrategyLinear), string(pollStrategyExponential))},
+		{Name: extTfResourcePollMaxInterval, Type: ExtensionTypeDuration, Locations: response},
+		{Name: extTfResourcePollEventsPath, Type: ExtensionTypeString, Locations: response},
+		{Name: extTfResourcePollEventsProperty, Type: ExtensionTypeString, Locations: response},
+		{Name: extTfResourcePollLocationHeader, Type: ExtensionTypeString, Locations: response},
+		{Name: extTfResourceSummaryResponse, Type: ExtensionTypeBoolean, Locations: response},
+
+		{Name: extTfHeader, Type: ExtensionTypeString, Locations: parameter},
+		{Name: extTfUpdateMask, Type: ExtensionTypeBoolean, Locations: parameter},
+
+		{Name: extTfAuthenticationSchemeBearer, Type: ExtensionTypeBoolean, Locations: securityDefinition},
+		{Name: extTfAuthenticationRefreshToken, Type: ExtensionTypeString, Locations: securityDefinition},
+		{Name: extTfTokenIntrospectionURL, Type: ExtensionTypeString, Locations: securityDefinition},
+		{Name: extTfAuth, Type: ExtensionTypeString, Locations: securityDefinition, Validate: oneOf(tfAuthAWSSigV4, tfAuthHMAC)},
+		{Name: extTfAuthAWSService, Type: ExtensionTypeString, Locations: securityDefinition},
+		{Name: extTfAuthAWSRegion, Type: ExtensionTypeString, Locations: securityDefinition},
+		{Name: extTfAuthHMACSignatureHeader, Type: ExtensionTypeString, Locations: securityDefinition},
+		{Name: extTfAuthHMACTimestampHeader, Type: ExtensionTypeString, Locations: securityDefinition},
+		{Name: extTfAuthHMACAlgorithm, Type: ExtensionTypeString, Locations: securityDefinition},
+		{Name: extTfAuthHMACSignaturePrefix, Type: ExtensionTypeString, Locations: securityDefinition},
+		{Name: extTfAuthSecondaryKey, Type: ExtensionTypeBoolean, Locations: securityDefinition},
+		{Name: extAmazonAPIGatewayAuthType, Type: ExtensionTypeString, Locations: securityDefinition},
+	}
+	registry := &extensionRegistry{}
+	for _, extension := range extensions {
+		if err := registry.register(extension); err != nil {
+			panic(err)
+		}
+	}
+	return registry
+}
+
+// supportedExtensions is the registry of the extensions supported by the provider
+var supportedExtensions = newBuiltInExtensionRegistry()
+
+// RegisterExtension registers a custom extension so the OpenAPI document validation accepts it in the given locations and
+// checks its value. This enables binaries embedding the provider to support their own extensions (handled by their own
+// code), which must be registered before the provider is created. Extensions with the x-terraform- prefix are owned by
+// the provider, thus registering an extension already supported returns an error
+func RegisterExtension(extension Extension) error {
+	return supportedExtensions.register(extension)
+}
+
+// LookupExtension returns the registered extension matching the given name, if any
+func LookupExtension(name string) (Extension, bool) {
+	return supportedExtensions.lookup(name)
+}
+
+// ValidateExtension returns an error if the extension with the given name and value can not be used in the location
+// provided: the extension has the x-terraform- prefix but is not registered, it is not supported in the location or the
+// value is not valid. Extensions not registered without the x-terraform- prefix are ignored
+func ValidateExtension(location ExtensionLocation, name string, value interface{}) error {
+	return supportedExtensions.validate(location, name, value)
+}
@@ -0,0 +1,121 @@
+package openapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtensionRegistryRegister(t *testing.T) {
+	testCases := []struct {
+		name          string
+		extension     Extension
+		expectedError string
+	}{
+		{
+			name:      "valid extension",
+			extension: Extension{Name: "x-company-team", Type: ExtensionTypeString, Locations: []ExtensionLocation{ExtensionLocationOperation}},
+		},
+		{
+			name:          "name without the x- prefix",
+			extension:     Extension{Name: "company-team", Type: ExtensionTypeString, Locations: []ExtensionLocation{ExtensionLocationOperation}},
+			expectedError: "extension name 'company-team' not valid, extension names must start with 'x-'",
+		},
+		{
+			name:          "no locations",
+			extension:     Extension{Name: "x-company-team", Type: ExtensionTypeString},
+			expectedError: "extension 'x-company-team' must be supported in at least one location",
+		},
+		{
+			name:          "type not supported",
+			extension:     Extension{Name: "x-company-team", Type: "integer", Locations: []ExtensionLocation{ExtensionLocationOperation}},
+			expectedError: "extension 'x-company-team' type 'integer' not supported",
+		},
+		{
+			name:          "extension already registered",
+			extension:     Extension{Name: "X-Terraform-Resource-Name", Type: ExtensionTypeString, Locations: []ExtensionLocation{ExtensionLocationOperation}},
+			expectedError: "extension 'X-Terraform-Resource-Name' is already registered",
+		},
+	}
+	for _, tc := range testCases {
+		registry := newBuiltInExtensionRegistry()
+		err := registry.register(tc.extension)
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		extension, registered := registry.lookup(tc.extension.Name)
+		assert.True(t, registered, tc.name)
+		assert.Equal(t, tc.extension.Name, extension.Name, tc.name)
+	}
+}
+
+func TestExtensionRegistryValidate(t *testing.T) {
+	registry := newBuiltInExtensionRegistry()
+	require.NoError(t, registry.register(Extension{
+		Name:      "x-company-team",
+		Type:      ExtensionTypeAny,
+		Locations: []ExtensionLocation{ExtensionLocationOperation},
+		Validate: func(value interface{}) error {
+			if _, ok := value.(map[string]interface{}); !ok {
+				return errors.New("expected an object")
+			}
+			return nil
+		},
+	}))
+	testCases := []struct {
+		name          string
+		location      ExtensionLocation
+		extension     string
+		value         interface{}
+		expectedError string
+	}{
+		{name: "string extension", location: ExtensionLocationOperation, extension: extTfResourceName, value: "cdn"},
+		{name: "boolean extension", location: ExtensionLocationSchema, extension: extTfImmutable, value: true},
+		{name: "duration extension as string", location: ExtensionLocationOperation, extension: extTfResourceTimeout, value: "30s"},
+		{name: "duration extension as seconds", location: ExtensionLocationResponse, extension: extTfResourcePollInterval, value: float64(10)},
+		{name: "extension names are case insensitive", location: ExtensionLocationSchema, extension: "X-Terraform-Immutable", value: true},
+		{name: "prefix extension", location: ExtensionLocationDocument, extension: "x-terraform-resource-regions-api", value: "rst1,dub1"},
+		{name: "other vendor extensions are ignored", location: ExtensionLocationSchema, extension: "x-nullable", value: true},
+		{name: "custom extension", location: ExtensionLocationOperation, extension: "x-company-team", value: map[string]interface{}{"name": "cdn"}},
+		{name: "custom extension hook failing", location: ExtensionLocationOperation, extension: "x-company-team", value: "cdn", expectedError: "extension 'x-company-team' value is not valid: expected an object"},
+		{name: "unknown terraform extension", location: ExtensionLocationSchema, extension: "x-terraform-imutable", value: true, expectedError: "extension 'x-terraform-imutable' is not supported"},
+		{name: "wrong location", location: ExtensionLocationSchema, extension: extTfResourceName, value: "cdn", expectedError: "extension 'x-terraform-resource-name' is not supported in the schema level, only in: operation"},
+		{name: "boolean extension with string value", location: ExtensionLocationSchema, extension: extTfImmutable, value: "true", expectedError: "extension 'x-terraform-immutable' value is not valid: expected a boolean value but got 'true'"},
+		{name: "string extension with boolean value", location: ExtensionLocationOperation, extension: extTfResourceName, value: true, expectedError: "extension 'x-terraform-resource-name' value is not valid: expected a string value but got 'true'"},
+		{name: "duration extension not valid", location: ExtensionLocationOperation, extension: extTfResourceTimeout, value: "-1s", expectedError: "extension 'x-terraform-resource-timeout' value is not valid: invalid duration value: '-1s'. The value must be either a duration (e,g: 30s, 1.5m, 1h30m) or an integer number of seconds (e,g: 30); negative and zero durations are not allowed: the duration must be greater than zero"},
+		{name: "poll strategy not supported", location: ExtensionLocationResponse, extension: extTfResourcePollStrategy, value: "fibonacci", expectedError: "extension 'x-terraform-resource-poll-strategy' value is not valid: value 'fibonacci' not supported, supported values: constant, linear, exponential"},
+		{name: "value not allowed", location: ExtensionLocationOperation, extension: extTfResourceScheme, value: "ftp", expectedError: "extension 'x-terraform-resource-scheme' value is not valid: value 'ftp' not supported, supported values: http, https"},
+		{name: "object extension", location: ExtensionLocationOperation, extension: extTfRetry, value: map[string]interface{}{"max_attempts": float64(3), "jitter": true}},
+		{name: "object extension in path", location: ExtensionLocationPath, extension: extTfRetry, value: map[string]interface{}{"max_attempts": float64(3)}},
+		{name: "payload path extension", location: ExtensionLocationResponse, extension: extTfResourcePollStatusPath, value: "$.metadata['state'].phase"},
+		{name: "payload path extension not valid", location: ExtensionLocationResponse, extension: extTfResourcePollStatusPath, value: "$.operations[*].state", expectedError: "extension 'x-terraform-resource-poll-status-path' value is not valid: path '$.operations[*].state' is not valid"},
+		{name: "eventual consistency extension not valid", location: ExtensionLocationOperation, extension: extTfEventualConsistency, value: map[string]interface{}{"grace_period": "-1s"}, expectedError: "extension 'x-terraform-eventual-consistency' value is not valid: 'grace_period' is not valid: invalid duration value: '-1s'. The value must be either a duration (e,g: 30s, 1.5m, 1h30m) or an integer number of seconds (e,g: 30); negative and zero durations are not allowed: the duration must be greater than zero"},
+		{name: "on failure cleanup extension with boolean value", location: ExtensionLocationOperation, extension: extTfOnFailureCleanup, value: true, expectedError: "extension 'x-terraform-on-failure-cleanup' value is not valid: expected an object value but got 'true'"},
+		{name: "object extension not valid", location: ExtensionLocationOperation, extension: extTfRetry, value: map[string]interface{}{"max_attempts": "3"}, expectedError: "extension 'x-terraform-retry' value is not valid: 'max_attempts' is not valid: expected an integer greater than zero but got '3'"},
+	}
+	for _, tc := range testCases {
+		err := registry.validate(tc.location, tc.extension, tc.value)
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError, tc.name)
+			continue
+		}
+		assert.NoError(t, err, tc.name)
+	}
+}
+
+func TestRegisterExtension(t *testing.T) {
+	extension := Extension{Name: "x-test-register-extension", Type: ExtensionTypeBoolean, Locations: []ExtensionLocation{ExtensionLocationSchema}}
+	require.NoError(t, RegisterExtension(extension))
+	assert.EqualError(t, RegisterExtension(extension), "extension 'x-test-register-extension' is already registered")
+
+	registered, exists := LookupExtension("x-test-register-extension")
+	assert.True(t, exists)
+	assert.Equal(t, ExtensionTypeBoolean, registered.Type)
+
+	assert.NoError(t, ValidateExtension(ExtensionLocationSchema, "x-test-register-extension", true))
+	assert.EqualError(t, ValidateExtension(ExtensionLocationSchema, "x-test-register-extension", "yes"), "extension 'x-test-register-extension' value is not valid: expected a boolean value but got 'yes'")
+}
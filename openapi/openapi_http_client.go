@@ -0,0 +1,153 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/dikhan/http_goclient"
+)
+
+// httpPatchClient defines the behaviour expected from the HTTP clients able to send PATCH requests, which are not part of
+// the http_goclient.HttpClientIface
+type httpPatchClient interface {
+	PatchJson(url string, headers map[string]string, in interface{}, out interface{}) (*http.Response, error)
+}
+
+// httpStreamClient defines the behaviour expected from the HTTP clients able to return the responses without reading
+// their body, so streamed responses can be consumed as they are received
+type httpStreamClient interface {
+	GetStream(ctx context.Context, url string, headers map[string]string) (*http.Response, error)
+}
+
+// httpConditionalClient defines the behaviour expected from the HTTP clients able to send conditional GET requests
+// (e.g. If-None-Match), whose responses might not have a body (304 Not Modified)
+type httpConditionalClient interface {
+	GetConditional(url string, headers map[string]string) (*http.Response, json.RawMessage, error)
+}
+
+// httpBodylessClient defines the behaviour expected from the HTTP clients able to send POST, PUT and PATCH requests without
+// body whose responses may not have a body either
+type httpBodylessClient interface {
+	SendWithoutBody(method string, url string, headers map[string]string) (*http.Response, json.RawMessage, error)
+}
+
+// httpContextClient defines the behaviour expected from the HTTP clients able to send the requests with a context, so the
+// in-flight requests are aborted once the context is done, which is not supported by the http_goclient.HttpClientIface
+type httpContextClient interface {
+	withContext(ctx context.Context) http_goclient.HttpClientIface
+}
+
+// patchableHTTPClient extends the http_goclient.HttpClient with support for PATCH requests, requests without body, streamed
+// responses and conditional GET requests
+type patchableHTTPClient struct {
+	*http_goclient.HttpClient
+}
+
+// PatchJson performs a PATCH request sending the given payload JSON encoded. The Content-Type header defaults to
+// application/json and can be overridden via the headers passed in (e.g. application/json-patch+json). The body of the
+// successful responses, if any, is JSON decoded into out
+func (c patchableHTTPClient) PatchJson(url string, headers map[string]string, in interface{}, out interface{}) (*http.Response, error) {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPatch, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(contentTypeHeader, "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	res, err := c.HttpClient.HttpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return res, err
+	}
+	// the body is made available again so the response can still be inspected (e.g. when checking the status code)
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+	if len(resBody) == 0 || out == nil || res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return res, nil
+	}
+	return res, json.Unmarshal(resBody, out)
+}
+
+// withContext returns the HTTP client sending the requests with the given context. The http_goclient.HttpClient does not
+// take contexts, hence the requests are sent through a transport that aborts them once the context is done (see
+// cancellableTransport), sharing the connections with the rest of requests
+func (c patchableHTTPClient) withContext(ctx context.Context) http_goclient.HttpClientIface {
+	if c.HttpClient == nil || c.HttpClient.HttpClient == nil || ctx.Done() == nil {
+		return c
+	}
+	httpClient := *c.HttpClient.HttpClient
+	httpClient.Transport = newCancellableTransport(ctx, httpClient.Transport)
+	return patchableHTTPClient{&http_goclient.HttpClient{HttpClient: &httpClient}}
+}
+
+// GetStream performs a GET request that is cancelled when the given context is done. The body of the response is not read,
+// it is up to the caller to consume it and close it
+func (c patchableHTTPClient) GetStream(ctx context.Context, url string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	return c.HttpClient.HttpClient.Do(req.WithContext(ctx))
+}
+
+// GetConditional performs a GET request and returns the body of the response as is, regardless of the status code, so the
+// responses without body (e.g. 304 Not Modified) can be handled by the caller
+func (c patchableHTTPClient) GetConditional(url string, headers map[string]string) (*http.Response, json.RawMessage, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	res, err := c.HttpClient.HttpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return res, nil, err
+	}
+	// the body is made available again so the response can still be inspected (e.g. when checking the status code)
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+	return res, resBody, nil
+}
+
+// SendWithoutBody performs a request without body (e.g. POST /v1/resource/{id}/abort) and returns the body of the
+// response as is, regardless of the status code, so the responses without body (e.g. 204 No Content) are not an error
+func (c patchableHTTPClient) SendWithoutBody(method string, url string, headers map[string]string) (*http.Response, json.RawMessage, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	res, err := c.HttpClient.HttpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return res, nil, err
+	}
+	// the body is made available again so the response can still be inspected (e.g. when checking the status code)
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+	return res, resBody, nil
+}
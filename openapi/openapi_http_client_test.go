@@ -0,0 +1,118 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dikhan/http_goclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchableHTTPClientPatchJson(t *testing.T) {
+	testCases := []struct {
+		name                string
+		headers             map[string]string
+		responseStatusCode  int
+		responseBody        string
+		expectedContentType string
+		expectedOut         json.RawMessage
+	}{
+		{
+			name:                "successful response",
+			responseStatusCode:  http.StatusOK,
+			responseBody:        `{"id":"1234"}`,
+			expectedContentType: "application/json",
+			expectedOut:         json.RawMessage(`{"id":"1234"}`),
+		},
+		{
+			name:                "content type overridden via headers",
+			headers:             map[string]string{contentTypeHeader: jsonPatchContentType},
+			responseStatusCode:  http.StatusNoContent,
+			expectedContentType: jsonPatchContentType,
+		},
+		{
+			name:                "error response is not decoded",
+			responseStatusCode:  http.StatusBadRequest,
+			responseBody:        "invalid patch",
+			expectedContentType: "application/json",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var receivedMethod, receivedContentType string
+			var receivedBody []byte
+			api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				receivedMethod = r.Method
+				receivedContentType = r.Header.Get(contentTypeHeader)
+				receivedBody, _ = ioutil.ReadAll(r.Body)
+				w.WriteHeader(tc.responseStatusCode)
+				w.Write([]byte(tc.responseBody))
+			}))
+			defer api.Close()
+			client := patchableHTTPClient{&http_goclient.HttpClient{HttpClient: &http.Client{}}}
+			var out json.RawMessage
+			res, err := client.PatchJson(api.URL, tc.headers, []jsonPatchOperation{{Op: jsonPatchOpRemove, Path: "/label"}}, &out)
+			require.NoError(t, err)
+			assert.Equal(t, tc.responseStatusCode, res.StatusCode)
+			assert.Equal(t, http.MethodPatch, receivedMethod)
+			assert.Equal(t, tc.expectedContentType, receivedContentType)
+			assert.JSONEq(t, `[{"op":"remove","path":"/label"}]`, string(receivedBody))
+			assert.Equal(t, tc.expectedOut, out)
+			body, err := ioutil.ReadAll(res.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tc.responseBody, string(body))
+		})
+	}
+}
+
+func TestPatchableHTTPClientSendWithoutBody(t *testing.T) {
+	testCases := []struct {
+		name               string
+		responseStatusCode int
+		responseBody       string
+	}{
+		{
+			name:               "response without body",
+			responseStatusCode: http.StatusNoContent,
+		},
+		{
+			name:               "response with body",
+			responseStatusCode: http.StatusAccepted,
+			responseBody:       `{"status":"aborting"}`,
+		},
+		{
+			name:               "error response",
+			responseStatusCode: http.StatusConflict,
+			responseBody:       "already aborted",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var receivedMethod, receivedHeader string
+			var receivedBody []byte
+			api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				receivedMethod = r.Method
+				receivedHeader = r.Header.Get("Authorization")
+				receivedBody, _ = ioutil.ReadAll(r.Body)
+				w.WriteHeader(tc.responseStatusCode)
+				w.Write([]byte(tc.responseBody))
+			}))
+			defer api.Close()
+			client := patchableHTTPClient{&http_goclient.HttpClient{HttpClient: &http.Client{}}}
+			res, out, err := client.SendWithoutBody(http.MethodPost, api.URL, map[string]string{"Authorization": "Bearer secret"})
+			require.NoError(t, err)
+			assert.Equal(t, tc.responseStatusCode, res.StatusCode)
+			assert.Equal(t, http.MethodPost, receivedMethod)
+			assert.Equal(t, "Bearer secret", receivedHeader)
+			assert.Empty(t, receivedBody)
+			assert.Equal(t, tc.responseBody, string(out))
+			body, err := ioutil.ReadAll(res.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tc.responseBody, string(body))
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const jsonPatchContentType = "application/json-patch+json"
+
+// JSON Patch operations (RFC 6902) used to describe the changes made to a resource
+const (
+	jsonPatchOpAdd     = "add"
+	jsonPatchOpRemove  = "remove"
+	jsonPatchOpReplace = "replace"
+)
+
+// jsonPatchClient defines the behaviour expected from the clients able to update the resources sending JSON Patch (RFC
+// 6902) documents, which is not part of the ClientOpenAPI interface
+type jsonPatchClient interface {
+	Patch(ctx context.Context, resource SpecResource, id string, operations []jsonPatchOperation, responsePayload interface{}, parentIDs ...string) (*http.Response, error)
+}
+
+// jsonPatchOperation defines an operation of a JSON Patch document as described in RFC 6902
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON returns the JSON representation of the operation. The value is always part of the add and replace
+// operations, even when it is the zero value or null (e.g. a property set to false or cleared), whereas remove
+// operations do not have a value
+func (o jsonPatchOperation) MarshalJSON() ([]byte, error) {
+	if o.Op == jsonPatchOpRemove {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{Op: o.Op, Path: o.Path})
+	}
+	type operation jsonPatchOperation
+	return json.Marshal(operation(o))
+}
+
+// getJSONPatchOperations returns the operations that transform the old payload into the new one. Objects are compared
+// property by property so only the values changed are part of the patch, whereas arrays are replaced as a whole since
+// the position of the items is not meaningful for sets. The operations are sorted by path so the patch is deterministic
+func getJSONPatchOperations(oldPayload, newPayload map[string]interface{}) []jsonPatchOperation {
+	operations := []jsonPatchOperation{}
+	appendJSONPatchObjectOperations(&operations, "", oldPayload, newPayload)
+	return operations
+}
+
+func appendJSONPatchObjectOperations(operations *[]jsonPatchOperation, path string, oldObject, newObject map[string]interface{}) {
+	var names []string
+	for name := range oldObject {
+		names = append(names, name)
+	}
+	for name := range newObject {
+		if _, exists := oldObject[name]; !exists {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		propertyPath := path + "/" + escapeJSONPointerToken(name)
+		oldValue, oldExists := oldObject[name]
+		newValue, newExists := newObject[name]
+		switch {
+		case !oldExists:
+			*operations = append(*operations, jsonPatchOperation{Op: jsonPatchOpAdd, Path: propertyPath, Value: newValue})
+		case !newExists:
+			*operations = append(*operations, jsonPatchOperation{Op: jsonPatchOpRemove, Path: propertyPath})
+		default:
+			oldChildObject, oldIsObject := oldValue.(map[string]interface{})
+			newChildObject, newIsObject := newValue.(map[string]interface{})
+			if oldIsObject && newIsObject {
+				appendJSONPatchObjectOperations(operations, propertyPath, oldChildObject, newChildObject)
+				continue
+			}
+			if !reflect.DeepEqual(oldValue, newValue) {
+				*operations = append(*operations, jsonPatchOperation{Op: jsonPatchOpReplace, Path: propertyPath, Value: newValue})
+			}
+		}
+	}
+}
+
+// escapeJSONPointerToken escapes the '~' and '/' characters of the given property name so it can be used as a reference
+// token of a JSON Pointer (RFC 6901)
+func escapeJSONPointerToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
@@ -0,0 +1,115 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clientOpenAPIWithoutPatch hides the optional Patch method of the stub client
+type clientOpenAPIWithoutPatch struct {
+	ClientOpenAPI
+}
+
+func TestGetJSONPatchOperations(t *testing.T) {
+	testCases := []struct {
+		name               string
+		oldPayload         map[string]interface{}
+		newPayload         map[string]interface{}
+		expectedOperations []jsonPatchOperation
+	}{
+		{
+			name:               "no changes",
+			oldPayload:         map[string]interface{}{"label": "some label", "tags": []interface{}{"a", "b"}},
+			newPayload:         map[string]interface{}{"label": "some label", "tags": []interface{}{"a", "b"}},
+			expectedOperations: []jsonPatchOperation{},
+		},
+		{
+			name:       "properties added, removed and replaced",
+			oldPayload: map[string]interface{}{"label": "some label", "description": "some description", "enabled": true},
+			newPayload: map[string]interface{}{"label": "new label", "size": 3, "enabled": false},
+			expectedOperations: []jsonPatchOperation{
+				{Op: jsonPatchOpRemove, Path: "/description"},
+				{Op: jsonPatchOpReplace, Path: "/enabled", Value: false},
+				{Op: jsonPatchOpReplace, Path: "/label", Value: "new label"},
+				{Op: jsonPatchOpAdd, Path: "/size", Value: 3},
+			},
+		},
+		{
+			name:       "nested object properties are compared one by one",
+			oldPayload: map[string]interface{}{"origin": map[string]interface{}{"host": "old.example.com", "port": 80}},
+			newPayload: map[string]interface{}{"origin": map[string]interface{}{"host": "new.example.com", "port": 80, "protocol": "https"}},
+			expectedOperations: []jsonPatchOperation{
+				{Op: jsonPatchOpReplace, Path: "/origin/host", Value: "new.example.com"},
+				{Op: jsonPatchOpAdd, Path: "/origin/protocol", Value: "https"},
+			},
+		},
+		{
+			name:       "arrays are replaced as a whole",
+			oldPayload: map[string]interface{}{"tags": []interface{}{"a", "b"}},
+			newPayload: map[string]interface{}{"tags": []interface{}{"a", "c"}},
+			expectedOperations: []jsonPatchOperation{
+				{Op: jsonPatchOpReplace, Path: "/tags", Value: []interface{}{"a", "c"}},
+			},
+		},
+		{
+			name:       "property names are escaped",
+			oldPayload: map[string]interface{}{},
+			newPayload: map[string]interface{}{"a/b~c": "value"},
+			expectedOperations: []jsonPatchOperation{
+				{Op: jsonPatchOpAdd, Path: "/a~1b~0c", Value: "value"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expectedOperations, getJSONPatchOperations(tc.oldPayload, tc.newPayload), tc.name)
+	}
+}
+
+func TestJSONPatchOperationMarshal(t *testing.T) {
+	testCases := []struct {
+		name         string
+		operation    jsonPatchOperation
+		expectedJSON string
+	}{
+		{
+			name:         "replace with false",
+			operation:    jsonPatchOperation{Op: jsonPatchOpReplace, Path: "/enabled", Value: false},
+			expectedJSON: `{"op":"replace","path":"/enabled","value":false}`,
+		},
+		{
+			name:         "replace with zero",
+			operation:    jsonPatchOperation{Op: jsonPatchOpReplace, Path: "/size", Value: 0},
+			expectedJSON: `{"op":"replace","path":"/size","value":0}`,
+		},
+		{
+			name:         "add empty string",
+			operation:    jsonPatchOperation{Op: jsonPatchOpAdd, Path: "/label", Value: ""},
+			expectedJSON: `{"op":"add","path":"/label","value":""}`,
+		},
+		{
+			name:         "replace with null",
+			operation:    jsonPatchOperation{Op: jsonPatchOpReplace, Path: "/origin", Value: nil},
+			expectedJSON: `{"op":"replace","path":"/origin","value":null}`,
+		},
+		{
+			name:         "remove",
+			operation:    jsonPatchOperation{Op: jsonPatchOpRemove, Path: "/description"},
+			expectedJSON: `{"op":"remove","path":"/description"}`,
+		},
+	}
+	for _, tc := range testCases {
+		b, err := json.Marshal(tc.operation)
+		require.NoError(t, err, tc.name)
+		assert.JSONEq(t, tc.expectedJSON, string(b), tc.name)
+	}
+	operations := []jsonPatchOperation{
+		{Op: jsonPatchOpReplace, Path: "/enabled", Value: false},
+		{Op: jsonPatchOpRemove, Path: "/description"},
+	}
+	b, err := json.Marshal(operations)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"op":"replace","path":"/enabled","value":false},{"op":"remove","path":"/description"}]`, string(b))
+}
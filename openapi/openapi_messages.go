@@ -0,0 +1,169 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MessageID identifies the messages of the validation and CRUD errors returned to the users, which can be rephrased (e.g.
+// translated or aligned with the support documentation of the organization) via RegisterMessageTemplates
+type MessageID string
+
+// Validation messages
+const (
+	MessageEnumValueNotValid               MessageID = "enum_value_not_valid"
+	MessageArrayItemEnumValueNotValid      MessageID = "array_item_enum_value_not_valid"
+	MessageArrayItemPatternNotMatched      MessageID = "array_item_pattern_not_matched"
+	MessageProviderPropertyValueNotAllowed MessageID = "provider_property_value_not_allowed"
+	MessageHeaderNameNotValid              MessageID = "header_name_not_valid"
+)
+
+// CRUD messages
+const (
+	MessageOperationFailed       MessageID = "operation_failed"
+	MessageUnauthorized          MessageID = "unauthorized"
+	MessageNotFound              MessageID = "not_found"
+	MessageUnexpectedStatusCode  MessageID = "unexpected_status_code"
+	MessageImportOnlyCreate      MessageID = "import_only_create"
+	MessageImportOnlyUpdate      MessageID = "import_only_update"
+	MessageReadOnlyMode          MessageID = "read_only_mode"
+	MessageDestroyPrevented      MessageID = "destroy_prevented"
+	MessageImmutablePropertyEdit MessageID = "immutable_property_edit"
+)
+
+// defaultMessageTemplates contains the built-in templates of the messages. The placeholders (e.g. {property}) are replaced
+// with the values of the message arguments; the placeholders supported by each message are the ones used here
+var defaultMessageTemplates = map[MessageID]string{
+	MessageEnumValueNotValid:               "property '{property}' value '{value}' is not valid, allowed values are: {allowed_values}",
+	MessageArrayItemEnumValueNotValid:      "property '{property}' item at index {index} with value '{value}' is not valid, allowed values are: {allowed_values}",
+	MessageArrayItemPatternNotMatched:      "property '{property}' item at index {index} with value '{value}' is not valid, it must match the pattern '{pattern}'",
+	MessageProviderPropertyValueNotAllowed: "property {property} value {value} is not valid, please make sure the value is one of {allowed_values}",
+	MessageHeaderNameNotValid:              "property '{property}' value '{value}' is not a valid header name",
+
+	MessageOperationFailed:       "[resource='{resource}'] {operation} {path} failed",
+	MessageUnauthorized:          "[resource='{resource}'] HTTP Response Status Code {status_code} - Unauthorized: API access is denied due to invalid credentials ({body})",
+	MessageNotFound:              "HTTP Response Status Code {status_code} - Not Found. Could not find resource instance: {body}",
+	MessageUnexpectedStatusCode:  "[resource='{resource}'] HTTP Response Status Code {status_code} not matching expected one {expected_status_codes} ({body})",
+	MessageImportOnlyCreate:      "[resource='{resource}'] resource is import-only and can not be created by terraform; import the existing instance instead (terraform import <resource_address> <id>)",
+	MessageImportOnlyUpdate:      "[resource='{resource}'] resource is import-only and can not be updated by terraform; make the changes outside terraform and update the configuration to match the remote instance",
+	MessageReadOnlyMode:          "[resource='{resource}'] {operation} is not allowed as the provider is configured in read-only mode; set the provider property '{provider_property}' to false to allow changes",
+	MessageDestroyPrevented:      "[resource='{resource}'] resource is protected by the prevent_destroy policy defined in the service configuration and can not be destroyed; set the provider property '{provider_property}' to true to allow it",
+	MessageImmutablePropertyEdit: "validation for immutable properties failed: {error}. Update operation was aborted; no updates were performed",
+}
+
+// messagePlaceholderRegex matches the placeholders of the message templates (e.g. {property})
+var messagePlaceholderRegex = regexp.MustCompile(`{([a-z_]+)}`)
+
+// messageArgs contains the values of the message placeholders keyed by placeholder name
+type messageArgs map[string]interface{}
+
+// messageCatalog contains the templates used to build the messages, which default to the built-in ones
+type messageCatalog struct {
+	mutex     sync.RWMutex
+	templates map[MessageID]string
+}
+
+func newMessageCatalog() *messageCatalog {
+	c := &messageCatalog{}
+	c.reset()
+	return c
+}
+
+// register overrides the templates of the given messages. Unknown message ids and templates referring to placeholders
+// the message does not support are rejected, in which case none of the templates is registered
+func (c *messageCatalog) register(templates map[MessageID]string) error {
+	var ids []string
+	for id := range templates {
+		ids = append(ids, string(id))
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		defaultTemplate, exists := defaultMessageTemplates[MessageID(id)]
+		if !exists {
+			return fmt.Errorf("message '%s' not supported", id)
+		}
+		supportedPlaceholders := getMessagePlaceholders(defaultTemplate)
+		for placeholder := range getMessagePlaceholders(templates[MessageID(id)]) {
+			if _, supported := supportedPlaceholders[placeholder]; !supported {
+				return fmt.Errorf("message '%s' template refers to the placeholder '{%s}' which is not supported, supported placeholders: %s", id, placeholder, joinMessagePlaceholders(supportedPlaceholders))
+			}
+		}
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for id, template := range templates {
+		c.templates[id] = template
+	}
+	return nil
+}
+
+// reset restores the built-in templates
+func (c *messageCatalog) reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.templates = map[MessageID]string{}
+	for id, template := range defaultMessageTemplates {
+		c.templates[id] = template
+	}
+}
+
+// format returns the message with the placeholders replaced by the values of the given arguments. Placeholders without
+// value are left as is
+func (c *messageCatalog) format(id MessageID, args messageArgs) string {
+	c.mutex.RLock()
+	template := c.templates[id]
+	c.mutex.RUnlock()
+	return messagePlaceholderRegex.ReplaceAllStringFunc(template, func(placeholder string) string {
+		value, exists := args[strings.Trim(placeholder, "{}")]
+		if !exists {
+			return placeholder
+		}
+		return fmt.Sprintf("%v", value)
+	})
+}
+
+// error returns an error containing the message
+func (c *messageCatalog) error(id MessageID, args messageArgs) error {
+	return errors.New(c.format(id, args))
+}
+
+func getMessagePlaceholders(template string) map[string]struct{} {
+	placeholders := map[string]struct{}{}
+	for _, match := range messagePlaceholderRegex.FindAllStringSubmatch(template, -1) {
+		placeholders[match[1]] = struct{}{}
+	}
+	return placeholders
+}
+
+func joinMessagePlaceholders(placeholders map[string]struct{}) string {
+	var names []string
+	for name := range placeholders {
+		names = append(names, "{"+name+"}")
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// messages is the catalog of the messages returned by the provider
+var messages = newMessageCatalog()
+
+// RegisterMessageTemplates overrides the templates of the given messages, enabling binaries embedding the provider to
+// phrase the validation and CRUD errors returned to the users in their own language or wording (e.g. referring to their
+// support documentation). The templates can use the placeholders of the built-in templates (e.g. {property} or {value});
+// templates for unknown messages or using placeholders the message does not support are rejected. The templates must
+// be registered before the provider is created
+func RegisterMessageTemplates(templates map[MessageID]string) error {
+	return messages.register(templates)
+}
+
+// GetMessageTemplate returns the template currently used for the given message
+func GetMessageTemplate(id MessageID) (string, bool) {
+	messages.mutex.RLock()
+	defer messages.mutex.RUnlock()
+	template, exists := messages.templates[id]
+	return template, exists
+}
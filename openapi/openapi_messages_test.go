@@ -0,0 +1,115 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageCatalogFormat(t *testing.T) {
+	testCases := []struct {
+		name            string
+		templates       map[MessageID]string
+		id              MessageID
+		args            messageArgs
+		expectedMessage string
+	}{
+		{
+			name:            "built-in template",
+			id:              MessageEnumValueNotValid,
+			args:            messageArgs{"property": "size", "value": "XL", "allowed_values": "S, M, L"},
+			expectedMessage: "property 'size' value 'XL' is not valid, allowed values are: S, M, L",
+		},
+		{
+			name:            "built-in template with non string values",
+			id:              MessageUnexpectedStatusCode,
+			args:            messageArgs{"resource": "cdn_v1", "status_code": http.StatusBadRequest, "expected_status_codes": []int{http.StatusOK}, "body": "bad request"},
+			expectedMessage: "[resource='cdn_v1'] HTTP Response Status Code 400 not matching expected one [200] (bad request)",
+		},
+		{
+			name:            "registered template",
+			templates:       map[MessageID]string{MessageEnumValueNotValid: "El valor '{value}' de la propiedad '{property}' no es válido (valores permitidos: {allowed_values})"},
+			id:              MessageEnumValueNotValid,
+			args:            messageArgs{"property": "size", "value": "XL", "allowed_values": "S, M, L"},
+			expectedMessage: "El valor 'XL' de la propiedad 'size' no es válido (valores permitidos: S, M, L)",
+		},
+		{
+			name:            "registered template not using all the placeholders",
+			templates:       map[MessageID]string{MessageOperationFailed: "{operation} request failed, see https://support.example.com/errors"},
+			id:              MessageOperationFailed,
+			args:            messageArgs{"resource": "cdn_v1", "operation": "POST", "path": "/v1/cdns"},
+			expectedMessage: "POST request failed, see https://support.example.com/errors",
+		},
+		{
+			name:            "placeholders without value are left as is",
+			id:              MessageHeaderNameNotValid,
+			args:            messageArgs{"property": "method_override_header"},
+			expectedMessage: "property 'method_override_header' value '{value}' is not a valid header name",
+		},
+	}
+	for _, tc := range testCases {
+		catalog := newMessageCatalog()
+		require.NoError(t, catalog.register(tc.templates), tc.name)
+		assert.Equal(t, tc.expectedMessage, catalog.format(tc.id, tc.args), tc.name)
+		assert.EqualError(t, catalog.error(tc.id, tc.args), tc.expectedMessage, tc.name)
+	}
+}
+
+func TestMessageCatalogRegister(t *testing.T) {
+	testCases := []struct {
+		name          string
+		templates     map[MessageID]string
+		expectedError string
+	}{
+		{
+			name:      "valid templates",
+			templates: map[MessageID]string{MessageNotFound: "Resource not found (status code {status_code})", MessageReadOnlyMode: "{resource}: {operation} disabled"},
+		},
+		{
+			name:          "message not supported",
+			templates:     map[MessageID]string{"unknown_message": "some message"},
+			expectedError: "message 'unknown_message' not supported",
+		},
+		{
+			name:          "placeholder not supported by the message",
+			templates:     map[MessageID]string{MessageNotFound: "[resource='{resource}'] not found"},
+			expectedError: "message 'not_found' template refers to the placeholder '{resource}' which is not supported, supported placeholders: {body}, {status_code}",
+		},
+	}
+	for _, tc := range testCases {
+		catalog := newMessageCatalog()
+		err := catalog.register(tc.templates)
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError, tc.name)
+			// none of the templates is registered if any of them is not valid
+			for id := range tc.templates {
+				assert.Equal(t, defaultMessageTemplates[id], catalog.templates[id], tc.name)
+			}
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		for id, template := range tc.templates {
+			assert.Equal(t, template, catalog.templates[id], tc.name)
+		}
+	}
+}
+
+func TestMessageCatalogReset(t *testing.T) {
+	catalog := newMessageCatalog()
+	require.NoError(t, catalog.register(map[MessageID]string{MessageDestroyPrevented: "{resource} can not be destroyed"}))
+	catalog.reset()
+	assert.Equal(t, defaultMessageTemplates[MessageDestroyPrevented], catalog.templates[MessageDestroyPrevented])
+}
+
+func TestRegisterMessageTemplates(t *testing.T) {
+	defer messages.reset()
+	require.NoError(t, RegisterMessageTemplates(map[MessageID]string{MessageHeaderNameNotValid: "'{value}' is not a valid header name, see https://support.example.com/headers"}))
+	template, exists := GetMessageTemplate(MessageHeaderNameNotValid)
+	assert.True(t, exists)
+	assert.Equal(t, "'{value}' is not a valid header name, see https://support.example.com/headers", template)
+	_, errs := validateHeaderName("invalid header", "method_override_header")
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "'invalid header' is not a valid header name, see https://support.example.com/headers")
+}
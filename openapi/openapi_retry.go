@@ -0,0 +1,195 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const defaultRetryInitialBackoff = 1 * time.Second
+const defaultRetryMaxBackoff = 30 * time.Second
+
+const retryAfterHeader = "Retry-After"
+
+// defaultRateLimitMaxWait is the max total time a request waits to be retried after being rate limited, if not configured
+const defaultRateLimitMaxWait = 5 * time.Minute
+
+// minRetryAfter is the min time waited before retrying a rate limited request, so APIs responding without the Retry-After
+// header (or with a date in the past) are not flooded with requests
+const minRetryAfter = 1 * time.Second
+
+// Properties of the x-terraform-retry extension value
+const (
+	retryMaxAttempts    = "max_attempts"
+	retryInitialBackoff = "initial_backoff"
+	retryMaxBackoff     = "max_backoff"
+	retryJitter         = "jitter"
+)
+
+// retryPolicy defines how the API calls failing with transient errors are retried
+type retryPolicy struct {
+	// maxAttempts is the max number of times a request is sent, including the first attempt
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	// jitter when enabled randomises the waits between attempts (equal jitter) so concurrent clients do not retry at once
+	jitter bool
+}
+
+// newRetryPolicy returns the retry policy for the given service retry configuration; nil if not configured
+func newRetryPolicy(retry *ServiceRetry) *retryPolicy {
+	if retry == nil {
+		return nil
+	}
+	return &retryPolicy{
+		maxAttempts:    retry.MaxAttempts,
+		initialBackoff: retry.InitialBackoff,
+		maxBackoff:     retry.MaxBackoff,
+		jitter:         retry.Jitter,
+	}
+}
+
+// getMaxAttempts returns the max number of times a request is sent, which is one (no retries) if there is no policy
+func (r *retryPolicy) getMaxAttempts() int {
+	if r == nil || r.maxAttempts < 1 {
+		return 1
+	}
+	return r.maxAttempts
+}
+
+// backoff returns the wait before the given retry attempt (starting at 1), which doubles on every attempt starting at the
+// initial backoff and is capped by the max backoff. With jitter enabled, the wait is a random value between half the
+// backoff and the backoff
+func (r *retryPolicy) backoff(attempt int) time.Duration {
+	backoff := r.initialBackoff
+	for i := 1; i < attempt && backoff < r.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > r.maxBackoff {
+		backoff = r.maxBackoff
+	}
+	if r.jitter && backoff > 1 {
+		half := backoff / 2
+		backoff = half + time.Duration(rand.Int63n(int64(backoff-half)+1))
+	}
+	return backoff
+}
+
+// isTransientFailure returns true if the request failed in a way that is worth retrying: the API responded with a 5xx
+// status code (except 501 Not Implemented, which will not change), the request timed out or the connection was reset
+// (or closed by the API before responding). Other errors (e.g. TLS handshake failures, connections refused or unknown
+// hosts) are not retried since they will not go away by themselves
+func isTransientFailure(resp *http.Response, err error) bool {
+	if resp != nil {
+		return resp.StatusCode >= http.StatusInternalServerError && resp.StatusCode != http.StatusNotImplemented
+	}
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isIdempotentMethod returns true if sending a request with the given method more than once has the same effect as
+// sending it once, so it is safe to retry it even if the API may have processed it (e.g. the response timed out)
+func isIdempotentMethod(method httpMethodSupported) bool {
+	return method == httpGet || method == httpPut || method == httpDelete
+}
+
+// parseRetryAfter returns the time to wait before retrying a rate limited request as requested by the API in the value of
+// the Retry-After header, which is either a number of seconds (e.g. 120) or an HTTP date (e.g. Wed, 21 Oct 2015 07:28:00 GMT).
+// The wait returned is never lower than minRetryAfter, which is also returned if the value is not valid
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	wait := minRetryAfter
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		wait = date.Sub(now)
+	}
+	if wait < minRetryAfter {
+		return minRetryAfter
+	}
+	return wait
+}
+
+// describeFailure returns a short description of the failure used when logging the retries
+func describeFailure(resp *http.Response, err error) string {
+	if resp != nil {
+		return fmt.Sprintf("status code %d", resp.StatusCode)
+	}
+	return err.Error()
+}
+
+// parseRetryExtension parses the value of the x-terraform-retry extension, which is an object with the same properties
+// as the retry section of the plugin configuration (e.g. {max_attempts: 5, initial_backoff: 2s, jitter: true}). The
+// backoffs not specified are set to their defaults
+func parseRetryExtension(value interface{}) (*retryPolicy, error) {
+	properties, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected an object value but got '%v'", value)
+	}
+	policy := &retryPolicy{initialBackoff: defaultRetryInitialBackoff, maxBackoff: defaultRetryMaxBackoff}
+	var names []string
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var err error
+		switch property := properties[name]; name {
+		case retryMaxAttempts:
+			switch v := property.(type) {
+			case float64:
+				if v == math.Trunc(v) {
+					policy.maxAttempts = int(v)
+				}
+			case int:
+				policy.maxAttempts = v
+			}
+			if policy.maxAttempts < 1 {
+				err = fmt.Errorf("expected an integer greater than zero but got '%v'", property)
+			}
+		case retryInitialBackoff:
+			policy.initialBackoff, err = parseExtensionDuration(property)
+		case retryMaxBackoff:
+			policy.maxBackoff, err = parseExtensionDuration(property)
+		case retryJitter:
+			var isBool bool
+			if policy.jitter, isBool = property.(bool); !isBool {
+				err = fmt.Errorf("expected a boolean value but got '%v'", property)
+			}
+		default:
+			err = fmt.Errorf("property not supported, supported properties: %s", strings.Join([]string{retryMaxAttempts, retryInitialBackoff, retryMaxBackoff, retryJitter}, ", "))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not valid: %s", name, err)
+		}
+	}
+	if policy.maxAttempts == 0 {
+		return nil, fmt.Errorf("'%s' is required", retryMaxAttempts)
+	}
+	if policy.initialBackoff > policy.maxBackoff {
+		return nil, fmt.Errorf("'%s' must not be greater than '%s'", retryInitialBackoff, retryMaxBackoff)
+	}
+	return policy, nil
+}
+
+// validateRetryExtension is the Validate hook of the x-terraform-retry extension
+func validateRetryExtension(value interface{}) error {
+	_, err := parseRetryExtension(value)
+	return err
+}
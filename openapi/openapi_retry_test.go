@@ -0,0 +1,181 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &retryPolicy{maxAttempts: 10, initialBackoff: time.Second, maxBackoff: 10 * time.Second}
+	assert.Equal(t, time.Second, policy.backoff(1))
+	assert.Equal(t, 2*time.Second, policy.backoff(2))
+	assert.Equal(t, 4*time.Second, policy.backoff(3))
+	assert.Equal(t, 8*time.Second, policy.backoff(4))
+	assert.Equal(t, 10*time.Second, policy.backoff(5))
+	assert.Equal(t, 10*time.Second, policy.backoff(100))
+}
+
+func TestRetryPolicyBackoff_Jitter(t *testing.T) {
+	policy := &retryPolicy{maxAttempts: 10, initialBackoff: time.Second, maxBackoff: 10 * time.Second, jitter: true}
+	for i := 0; i < 100; i++ {
+		backoff := policy.backoff(3)
+		assert.True(t, backoff >= 2*time.Second && backoff <= 4*time.Second, "backoff %s out of range", backoff)
+	}
+}
+
+func TestRetryPolicyGetMaxAttempts(t *testing.T) {
+	var noPolicy *retryPolicy
+	assert.Equal(t, 1, noPolicy.getMaxAttempts())
+	assert.Equal(t, 1, (&retryPolicy{}).getMaxAttempts())
+	assert.Equal(t, 5, (&retryPolicy{maxAttempts: 5}).getMaxAttempts())
+}
+
+func TestNewRetryPolicy(t *testing.T) {
+	assert.Nil(t, newRetryPolicy(nil))
+	assert.Equal(t, &retryPolicy{maxAttempts: 3, initialBackoff: time.Second, maxBackoff: time.Minute, jitter: true}, newRetryPolicy(&ServiceRetry{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: time.Minute, Jitter: true}))
+}
+
+func TestIsTransientFailure(t *testing.T) {
+	testCases := []struct {
+		name     string
+		resp     *http.Response
+		err      error
+		expected bool
+	}{
+		{name: "successful response", resp: &http.Response{StatusCode: http.StatusOK}, expected: false},
+		{name: "client error response", resp: &http.Response{StatusCode: http.StatusConflict}, expected: false},
+		{name: "internal server error response", resp: &http.Response{StatusCode: http.StatusInternalServerError}, expected: true},
+		{name: "service unavailable response", resp: &http.Response{StatusCode: http.StatusServiceUnavailable}, expected: true},
+		{name: "not implemented response", resp: &http.Response{StatusCode: http.StatusNotImplemented}, expected: false},
+		{name: "connection reset", err: &url.Error{Op: "Get", URL: "http://www.host.com", Err: &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}}, expected: true},
+		{name: "connection closed before responding", err: &url.Error{Op: "Get", URL: "http://www.host.com", Err: io.EOF}, expected: true},
+		{name: "timeout", err: &url.Error{Op: "Get", URL: "http://www.host.com", Err: timeoutError{}}, expected: true},
+		{name: "connection refused", err: &url.Error{Op: "Get", URL: "http://www.host.com", Err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}}, expected: false},
+		{name: "TLS failure", err: &url.Error{Op: "Get", URL: "https://www.host.com", Err: errors.New("x509: certificate signed by unknown authority")}, expected: false},
+		{name: "operation timed out", err: &url.Error{Op: "Get", URL: "http://www.host.com", Err: context.DeadlineExceeded}, expected: false},
+		{name: "other errors", err: errors.New("method 'PATCH' not supported by the HTTP client"), expected: false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, isTransientFailure(tc.resp, tc.err), tc.name)
+	}
+}
+
+// timeoutError is a net.Error reporting a timeout, as the ones returned when the client timeout is reached
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsIdempotentMethod(t *testing.T) {
+	assert.True(t, isIdempotentMethod(httpGet))
+	assert.True(t, isIdempotentMethod(httpPut))
+	assert.True(t, isIdempotentMethod(httpDelete))
+	assert.False(t, isIdempotentMethod(httpPost))
+	assert.False(t, isIdempotentMethod(httpPatch))
+}
+
+func TestParseRetryExtension(t *testing.T) {
+	testCases := []struct {
+		name           string
+		value          interface{}
+		expectedPolicy *retryPolicy
+		expectedError  string
+	}{
+		{
+			name:           "all properties",
+			value:          map[string]interface{}{"max_attempts": float64(5), "initial_backoff": "500ms", "max_backoff": float64(10), "jitter": true},
+			expectedPolicy: &retryPolicy{maxAttempts: 5, initialBackoff: 500 * time.Millisecond, maxBackoff: 10 * time.Second, jitter: true},
+		},
+		{
+			name:           "backoffs default",
+			value:          map[string]interface{}{"max_attempts": float64(3)},
+			expectedPolicy: &retryPolicy{maxAttempts: 3, initialBackoff: defaultRetryInitialBackoff, maxBackoff: defaultRetryMaxBackoff},
+		},
+		{
+			name:           "retries disabled",
+			value:          map[string]interface{}{"max_attempts": float64(1)},
+			expectedPolicy: &retryPolicy{maxAttempts: 1, initialBackoff: defaultRetryInitialBackoff, maxBackoff: defaultRetryMaxBackoff},
+		},
+		{
+			name:          "value is not an object",
+			value:         "5",
+			expectedError: "expected an object value but got '5'",
+		},
+		{
+			name:          "max attempts missing",
+			value:         map[string]interface{}{"jitter": true},
+			expectedError: "'max_attempts' is required",
+		},
+		{
+			name:          "max attempts not valid",
+			value:         map[string]interface{}{"max_attempts": float64(0)},
+			expectedError: "'max_attempts' is not valid: expected an integer greater than zero but got '0'",
+		},
+		{
+			name:          "max attempts with decimals",
+			value:         map[string]interface{}{"max_attempts": 2.5},
+			expectedError: "'max_attempts' is not valid: expected an integer greater than zero but got '2.5'",
+		},
+		{
+			name:          "backoff not valid",
+			value:         map[string]interface{}{"max_attempts": float64(3), "initial_backoff": "-1s"},
+			expectedError: "'initial_backoff' is not valid: invalid duration value: '-1s'. The value must be either a duration (e,g: 30s, 1.5m, 1h30m) or an integer number of seconds (e,g: 30); negative and zero durations are not allowed: the duration must be greater than zero",
+		},
+		{
+			name:          "jitter not valid",
+			value:         map[string]interface{}{"max_attempts": float64(3), "jitter": "yes"},
+			expectedError: "'jitter' is not valid: expected a boolean value but got 'yes'",
+		},
+		{
+			name:          "property not supported",
+			value:         map[string]interface{}{"max_attempts": float64(3), "retry_on": "5xx"},
+			expectedError: "'retry_on' is not valid: property not supported, supported properties: max_attempts, initial_backoff, max_backoff, jitter",
+		},
+		{
+			name:          "initial backoff greater than max backoff",
+			value:         map[string]interface{}{"max_attempts": float64(3), "initial_backoff": "1m", "max_backoff": "10s"},
+			expectedError: "'initial_backoff' must not be greater than 'max_backoff'",
+		},
+	}
+	for _, tc := range testCases {
+		policy, err := parseRetryExtension(tc.value)
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError, tc.name)
+			assert.Nil(t, policy, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expectedPolicy, policy, tc.name)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2015, time.October, 21, 7, 28, 0, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "number of seconds", value: "120", expected: 2 * time.Minute},
+		{name: "http date", value: "Wed, 21 Oct 2015 07:28:30 GMT", expected: 30 * time.Second},
+		{name: "http date in the past", value: "Wed, 21 Oct 2015 07:20:00 GMT", expected: minRetryAfter},
+		{name: "zero seconds", value: "0", expected: minRetryAfter},
+		{name: "header not present", value: "", expected: minRetryAfter},
+		{name: "value not valid", value: "soon", expected: minRetryAfter},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, parseRetryAfter(tc.value, now), tc.name)
+	}
+}
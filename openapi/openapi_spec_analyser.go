@@ -1,7 +1,14 @@
 package openapi
 
 import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/go-openapi/loads"
 )
 
 // SpecAnalyser analyses the swagger doc and provides helper methods to retrieve all the end points that can
@@ -31,6 +38,25 @@ type SpecAnalyser interface {
 	// (e,g: host, protocols, etc) which is then used in the ProviderClient to communicate with the API as specified in
 	// the configuration.
 	GetAPIBackendConfiguration() (SpecBackendConfiguration, error)
+	// GetDocumentInfo returns information about the OpenAPI document itself (e.g. the API version and the hash of the
+	// document) along with the paths that are not exposed neither as resources nor as data sources
+	GetDocumentInfo() (*SpecDocumentInfo, error)
+}
+
+// SpecDocumentInfo describes the OpenAPI document the provider has been built out of
+type SpecDocumentInfo struct {
+	// Version is the version of the API declared in the OpenAPI document (info.version)
+	Version string
+	// Hash is the SHA-256 hash (hex encoded) of the OpenAPI document contents
+	Hash string
+	// SkippedPaths contains the paths of the OpenAPI document that are not exposed neither as resources nor as data sources
+	// (e.g. the paths are not terraform compliant or they are excluded via extensions), sorted alphabetically
+	SkippedPaths []string
+}
+
+// getOpenAPIDocumentHash returns the SHA-256 hash (hex encoded) of the given OpenAPI document contents
+func getOpenAPIDocumentHash(document []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(document))
 }
 
 // SpecAnalyserVersion defines the type for versions supported in the SpecAnalyser
@@ -39,23 +65,91 @@ type SpecAnalyserVersion string
 const (
 	// specAnalyserV2 version that supports OpenAPI v2 (swagger)
 	specAnalyserV2 SpecAnalyserVersion = "v2"
+	// specAnalyserV3 version that supports OpenAPI v3.0.x and v3.1.x
+	specAnalyserV3 SpecAnalyserVersion = "v3"
 )
 
 // CreateSpecAnalyser is a factory method that returns the appropriate implementation of SpecAnalyser
 // depending upon the openApiSpecAnalyserVersion passed in.
-// Currently only OpenAPI v2 version is supported but this constructor is ready to handle new implementations such as v3
-// when the time comes
 func CreateSpecAnalyser(specAnalyserVersion SpecAnalyserVersion, openAPIDocumentURL string) (SpecAnalyser, error) {
 	var err error
 	var specAnalyser SpecAnalyser
 	switch specAnalyserVersion {
 	case specAnalyserV2:
 		specAnalyser, err = newSpecAnalyserV2(openAPIDocumentURL)
+	case specAnalyserV3:
+		specAnalyser, err = newSpecAnalyserV3(openAPIDocumentURL)
 	default:
-		return nil, fmt.Errorf("open api spec analyser version '%s' not supported, please choose a valid SpecAnalyser implementation [%s]", specAnalyserVersion, specAnalyserV2)
+		return nil, fmt.Errorf("open api spec analyser version '%s' not supported, please choose a valid SpecAnalyser implementation [%s, %s]", specAnalyserVersion, specAnalyserV2, specAnalyserV3)
 	}
 	if err != nil {
 		return nil, err
 	}
 	return specAnalyser, nil
 }
+
+// createSpecAnalyserForDocument returns the SpecAnalyser implementation that understands the OpenAPI document located at
+// openAPIDocumentURL, based on the version declared in the document itself ('openapi: 3.x.x' for OpenAPI v3 documents,
+// otherwise the document is considered an OpenAPI v2 document). The document is only retrieved once.
+func createSpecAnalyserForDocument(openAPIDocumentURL string) (SpecAnalyser, error) {
+	return createAuthenticatedSpecAnalyserForDocument(openAPIDocumentURL, "")
+}
+
+// createAuthenticatedSpecAnalyserForDocument behaves as createSpecAnalyserForDocument, sending the given access token (if
+// any) when fetching the OpenAPI document
+func createAuthenticatedSpecAnalyserForDocument(openAPIDocumentURL, accessToken string) (SpecAnalyser, error) {
+	if openAPIDocumentURL == "" {
+		return nil, errors.New("open api document filename argument empty, please provide the url of the OpenAPI document")
+	}
+	apiSpec, err := loadOpenAPIDocument(openAPIDocumentURL, accessToken)
+	if err != nil {
+		return nil, &SpecFetchError{URL: openAPIDocumentURL, Err: fmt.Errorf("failed to retrieve the OpenAPI document from '%s' - error = %s", openAPIDocumentURL, err)}
+	}
+	return createSpecAnalyserFromDocument(openAPIDocumentURL, apiSpec)
+}
+
+// createSpecAnalyserForEmbeddedDocument behaves as createSpecAnalyserForDocument for the given JSON OpenAPI document, which
+// is embedded in the provider binary rather than being retrieved. The name identifies the document in the logs and errors
+func createSpecAnalyserForEmbeddedDocument(name string, document []byte) (SpecAnalyser, error) {
+	apiSpec, err := loads.Analyzed(json.RawMessage(document), "")
+	if err != nil {
+		return nil, &SpecAnalysisError{Err: fmt.Errorf("failed to read the embedded OpenAPI document '%s' - error = %s", name, err)}
+	}
+	return createSpecAnalyserFromDocument(name, apiSpec)
+}
+
+func createSpecAnalyserFromDocument(openAPIDocumentURL string, apiSpec *loads.Document) (SpecAnalyser, error) {
+	if apiSpec.Spec().Swagger == "" && isOpenAPIv3Document(apiSpec.Raw()) {
+		return newSpecAnalyserV3FromDocument(openAPIDocumentURL, apiSpec.Raw())
+	}
+	return newSpecAnalyserV2FromDocument(openAPIDocumentURL, apiSpec)
+}
+
+// loadOpenAPIDocument retrieves the OpenAPI document located at openAPIDocumentURL. If an access token is given, the
+// document is fetched over HTTP sending the token as a bearer token
+func loadOpenAPIDocument(openAPIDocumentURL, accessToken string) (*loads.Document, error) {
+	if accessToken == "" {
+		return loads.JSONSpec(openAPIDocumentURL)
+	}
+	req, err := http.NewRequest(http.MethodGet, openAPIDocumentURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(authorizationHeader, fmt.Sprintf("Bearer %s", accessToken))
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden {
+		return nil, fmt.Errorf("the OIDC access token was rejected (status code %d), please run the '%s' command to log in again", res.StatusCode, SpecLoginCommand)
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	return loads.Analyzed(json.RawMessage(data), "")
+}
@@ -7,6 +7,7 @@ type specAnalyserStub struct {
 	security             *specSecurityStub
 	headers              SpecHeaderParameters
 	backendConfiguration SpecBackendConfiguration
+	documentInfo         *SpecDocumentInfo
 	error                error
 }
 
@@ -35,3 +36,13 @@ func (s *specAnalyserStub) GetAPIBackendConfiguration() (SpecBackendConfiguratio
 	}
 	return s.backendConfiguration, nil
 }
+
+func (s *specAnalyserStub) GetDocumentInfo() (*SpecDocumentInfo, error) {
+	if s.error != nil {
+		return nil, s.error
+	}
+	if s.documentInfo == nil {
+		return &SpecDocumentInfo{}, nil
+	}
+	return s.documentInfo, nil
+}
@@ -32,6 +32,10 @@ func TestCreateSpecAnalyser(t *testing.T) {
 			Convey("Then the error message should equal", func() {
 				So(err.Error(), ShouldEqual, "failed to retrieve the OpenAPI document from 'some non valid spec file' - error = open some non valid spec file: no such file or directory")
 			})
+			Convey("And the error should be a SpecFetchError containing the URL of the document", func() {
+				So(err, ShouldHaveSameTypeAs, &SpecFetchError{})
+				So(err.(*SpecFetchError).URL, ShouldEqual, "some non valid spec file")
+			})
 		})
 
 		Convey("When CreateSpecAnalyser method is called with a non supported version", func() {
@@ -40,7 +44,21 @@ func TestCreateSpecAnalyser(t *testing.T) {
 				So(err, ShouldNotBeNil)
 			})
 			Convey("Then the error message should equal", func() {
-				So(err.Error(), ShouldEqual, "open api spec analyser version 'nonSupportedVersion' not supported, please choose a valid SpecAnalyser implementation [v2]")
+				So(err.Error(), ShouldEqual, "open api spec analyser version 'nonSupportedVersion' not supported, please choose a valid SpecAnalyser implementation [v2, v3]")
+			})
+		})
+	})
+
+	Convey("Given the v3 specAnalyserVersion and the openAPIDocumentURL of an OpenAPI v3 document", t, func() {
+		file := initAPISpecFile(openAPIv3Document)
+		defer os.Remove(file.Name())
+		Convey("When CreateSpecAnalyser method is called", func() {
+			specAnalyser, err := CreateSpecAnalyser(specAnalyserV3, file.Name())
+			Convey("Then err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("Then the specAnalyser is of type specV3Analyser", func() {
+				So(specAnalyser, ShouldHaveSameTypeAs, &specV3Analyser{})
 			})
 		})
 	})
@@ -1,11 +1,18 @@
 package openapi
 
+import (
+	"encoding/json"
+)
+
 // authType is an enum defining the different types of authentication supported
 type authType byte
 
 const ( // iota is reset to 0
 	authTypeAPIKeyHeader authType = iota
 	authTypeAPIQuery
+	authTypeAPIKeyCookie
+	authTypeAWSSigV4
+	authTypeHMAC
 )
 
 type specAuthenticator interface {
@@ -21,4 +28,37 @@ type specAuthenticator interface {
 type authContext struct {
 	headers map[string]string
 	url     string
+	// signers contains the authenticators that need the whole request to build the authentication headers (e.g. AWS
+	// Signature Version 4 or HMAC), which are called in order right before the request is sent
+	signers []requestSigner
+	// failovers contains the functions that switch the authenticators supporting a secondary api key over to it, which
+	// are called when the API rejects the credentials the request was authenticated with
+	failovers []func()
+}
+
+// failoverCredentials switches the authenticators of the request supporting a secondary api key over to it, returning
+// true if there was any so the request can be authenticated and sent again
+func (a *authContext) failoverCredentials() bool {
+	for _, failover := range a.failovers {
+		failover()
+	}
+	return len(a.failovers) > 0
+}
+
+// requestSigner defines the behaviour of authenticators that sign the requests. The signature covers the final method,
+// URL and payload of the request, thus requests are signed right before they are sent
+type requestSigner interface {
+	// sign adds to the headers the signature of the request
+	sign(method, url string, headers map[string]string, requestPayload interface{}) error
+	// getSignatureHeaders returns the names of the headers populated when the request is signed
+	getSignatureHeaders() []string
+}
+
+// getRequestPayloadBytes returns the request payload serialised the same way the http client does (JSON), or nil if
+// the request has no payload
+func getRequestPayloadBytes(requestPayload interface{}) ([]byte, error) {
+	if requestPayload == nil {
+		return nil, nil
+	}
+	return json.Marshal(requestPayload)
 }
@@ -3,6 +3,9 @@ package openapi
 import (
 	"fmt"
 	"log"
+	"net/http"
+	"sort"
+	"strings"
 )
 
 // apiAuth is an implementation of specAuthenticator encapsulating the general settings to be applied in case
@@ -36,34 +39,101 @@ func (oa apiAuth) authRequired(url string, operationSecuritySchemes SpecSecurity
 }
 
 // Validate security policies. This function will perform the following checks:
-// 1. Verify that the operation security schemes are defined as security definitions in the provider config
-func (oa apiAuth) fetchRequiredAuthenticators(operationSecuritySchemes SpecSecuritySchemes, providerConfig providerConfiguration) ([]specAPIKeyAuthenticator, error) {
+// 1. Verify that the user provided the credentials for the operation security schemes in the provider config
+// 2. Verify that the operation security schemes are defined as security definitions in the provider config
+func (oa apiAuth) fetchRequiredAuthenticators(url string, operationSecuritySchemes SpecSecuritySchemes, providerConfig providerConfiguration) ([]specAPIKeyAuthenticator, error) {
 	var authenticators []specAPIKeyAuthenticator
 	for _, operationSecurityScheme := range operationSecuritySchemes {
+		if err, missing := providerConfig.MissingCredentials[operationSecurityScheme.getTerraformConfigurationName()]; missing {
+			return nil, &AuthConfigError{Err: fmt.Errorf("calling '%s' requires the credentials of the security definition '%s': %s", url, operationSecurityScheme.getTerraformConfigurationName(), err)}
+		}
 		authenticator := providerConfig.getAuthenticatorFor(operationSecurityScheme)
 		if authenticator == nil {
-			return nil, fmt.Errorf("operation's security policy '%s' is not defined, please make sure the swagger file contains a security definition named '%s' under the securityDefinitions section", operationSecurityScheme, operationSecurityScheme)
+			return nil, &AuthConfigError{Err: fmt.Errorf("operation's security policy '%s' is not defined, please make sure the swagger file contains a security definition named '%s' under the securityDefinitions section", operationSecurityScheme.Name, operationSecurityScheme.Name)}
 		}
 		authenticators = append(authenticators, authenticator)
 	}
 	return authenticators, nil
 }
 
+// checkRequiredScopes verifies that the credentials configured have been granted the scopes required by the security
+// schemes, so the provider fails before making the API call listing the scopes that are missing
+func (oa apiAuth) checkRequiredScopes(url string, requiredSecuritySchemes SpecSecuritySchemes, providerConfig providerConfiguration) error {
+	for _, securityScheme := range requiredSecuritySchemes {
+		if missingScopes := providerConfig.getMissingScopes(securityScheme); len(missingScopes) > 0 {
+			return &AuthConfigError{Err: fmt.Errorf("the credentials configured for the security definition '%s' have not been granted the scopes required to call '%s', missing scopes: %s", securityScheme.getTerraformConfigurationName(), url, strings.Join(missingScopes, ", "))}
+		}
+	}
+	return nil
+}
+
 func (oa apiAuth) prepareAuth(url string, operationSecuritySchemes SpecSecuritySchemes, providerConfig providerConfiguration) (*authContext, error) {
 	authContext := &authContext{
 		headers: map[string]string{},
 		url:     url,
 	}
 	if required, requiredSecuritySchemes := oa.authRequired(url, operationSecuritySchemes); required {
-		authenticators, err := oa.fetchRequiredAuthenticators(requiredSecuritySchemes, providerConfig)
+		authenticators, err := oa.fetchRequiredAuthenticators(url, requiredSecuritySchemes, providerConfig)
 		if err != nil {
 			return authContext, err
 		}
-		for _, authenticator := range authenticators {
+		if err := oa.checkRequiredScopes(url, requiredSecuritySchemes, providerConfig); err != nil {
+			return authContext, err
+		}
+		// All the required security schemes are applied to the request (AND semantics), as long as they do not populate
+		// the same headers, in which case only the last one would make it to the request
+		headerOwners := map[string]string{}
+		for i, authenticator := range authenticators {
+			headers := copyHeaders(authContext.headers)
+			signers := len(authContext.signers)
 			if err := authenticator.prepareAuth(authContext); err != nil {
 				return authContext, err
 			}
+			populatedHeaders := getPopulatedHeaders(headers, authContext.headers)
+			for _, signer := range authContext.signers[signers:] {
+				populatedHeaders = append(populatedHeaders, signer.getSignatureHeaders()...)
+			}
+			if err := oa.checkHeaderConflicts(requiredSecuritySchemes[i], populatedHeaders, headerOwners); err != nil {
+				return authContext, err
+			}
 		}
 	}
 	return authContext, nil
 }
+
+// checkHeaderConflicts returns an error if any of the headers populated by the given security scheme has already been
+// populated by another of the security schemes required, keeping track of the security scheme that populated each header
+// in headerOwners. The Cookie header is shared by all the schemes since each of them appends its own cookie
+func (oa apiAuth) checkHeaderConflicts(securityScheme SpecSecurityScheme, populatedHeaders []string, headerOwners map[string]string) error {
+	for _, header := range populatedHeaders {
+		header = http.CanonicalHeaderKey(header)
+		if header == cookieHeader {
+			continue
+		}
+		if owner, exists := headerOwners[header]; exists && owner != securityScheme.Name {
+			return &AuthConfigError{Err: fmt.Errorf("the security schemes '%s' and '%s' are required together but both populate the header '%s', please make sure the security definitions use different headers", owner, securityScheme.Name, header)}
+		}
+		headerOwners[header] = securityScheme.Name
+	}
+	return nil
+}
+
+func copyHeaders(headers map[string]string) map[string]string {
+	headersCopy := map[string]string{}
+	for name, value := range headers {
+		headersCopy[name] = value
+	}
+	return headersCopy
+}
+
+// getPopulatedHeaders returns the names of the headers that were added or changed
+func getPopulatedHeaders(before, after map[string]string) []string {
+	var populatedHeaders []string
+	for name, value := range after {
+		if previousValue, exists := before[name]; !exists || previousValue != value {
+			populatedHeaders = append(populatedHeaders, name)
+		}
+	}
+	sort.Strings(populatedHeaders)
+	return populatedHeaders
+}
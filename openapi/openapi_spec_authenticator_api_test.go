@@ -76,7 +76,7 @@ func TestFetchRequiredAuthenticators(t *testing.T) {
 		operationSecuritySchemes := SpecSecuritySchemes{SpecSecurityScheme{Name: securityPolicyName}}
 		oa := apiAuth{}
 		Convey("When fetchRequiredAuthenticators method with a security policy which is also defined in the security definitions", func() {
-			authenticators, err := oa.fetchRequiredAuthenticators(operationSecuritySchemes, providerConfig)
+			authenticators, err := oa.fetchRequiredAuthenticators("https://www.host.com/v1/cdns", operationSecuritySchemes, providerConfig)
 			Convey("Then the err returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -104,7 +104,7 @@ func TestFetchRequiredAuthenticators(t *testing.T) {
 		operationSecuritySchemes := SpecSecuritySchemes{SpecSecurityScheme{Name: "non_defined_security_policy"}}
 		oa := apiAuth{}
 		Convey("When fetchRequiredAuthenticators method with a security policy which is NOT defined in the security definitions", func() {
-			authenticators, err := oa.fetchRequiredAuthenticators(operationSecuritySchemes, providerConfig)
+			authenticators, err := oa.fetchRequiredAuthenticators("https://www.host.com/v1/cdns", operationSecuritySchemes, providerConfig)
 			Convey("Then the err returned should not be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -113,6 +113,27 @@ func TestFetchRequiredAuthenticators(t *testing.T) {
 			})
 		})
 	})
+	Convey("Given a provider configuration where the user did not provide the credentials of the 'apikey_auth' security definition and an operation that requires api key header authentication", t, func() {
+		securityPolicyName := "apikey_auth"
+		providerConfig := providerConfiguration{
+			SecuritySchemaDefinitions: map[string]specAPIKeyAuthenticator{},
+			MissingCredentials: map[string]error{
+				securityPolicyName: &AuthConfigError{Err: fmt.Errorf("security schema definition 'apikey_auth' is missing the value, please make sure this value is provided in the terraform configuration")},
+			},
+		}
+		operationSecuritySchemes := SpecSecuritySchemes{SpecSecurityScheme{Name: securityPolicyName}}
+		oa := apiAuth{}
+		Convey("When fetchRequiredAuthenticators method is called", func() {
+			authenticators, err := oa.fetchRequiredAuthenticators("https://www.host.com/v1/cdns", operationSecuritySchemes, providerConfig)
+			Convey("Then the err returned should be an AuthConfigError mentioning the credentials missing", func() {
+				So(err, ShouldHaveSameTypeAs, &AuthConfigError{})
+				So(err.Error(), ShouldEqual, "calling 'https://www.host.com/v1/cdns' requires the credentials of the security definition 'apikey_auth': security schema definition 'apikey_auth' is missing the value, please make sure this value is provided in the terraform configuration")
+			})
+			Convey("Then the authenticators returned should be empty", func() {
+				So(authenticators, ShouldBeEmpty)
+			})
+		})
+	})
 }
 
 func TestPrepareAuth(t *testing.T) {
@@ -333,7 +354,7 @@ func TestPrepareAuth(t *testing.T) {
 				So(err, ShouldNotBeNil)
 			})
 			Convey("And the err message should be", func() {
-				So(err.Error(), ShouldEqual, "operation's security policy '{not_defined_scheme}' is not defined, please make sure the swagger file contains a security definition named '{not_defined_scheme}' under the securityDefinitions section")
+				So(err.Error(), ShouldEqual, "operation's security policy 'not_defined_scheme' is not defined, please make sure the swagger file contains a security definition named 'not_defined_scheme' under the securityDefinitions section")
 			})
 		})
 	})
@@ -358,7 +379,105 @@ func TestPrepareAuth(t *testing.T) {
 				So(err, ShouldNotBeNil)
 			})
 			Convey("And the err message should be", func() {
-				So(err.Error(), ShouldEqual, "operation's security policy '{not_defined_scheme}' is not defined, please make sure the swagger file contains a security definition named '{not_defined_scheme}' under the securityDefinitions section")
+				So(err.Error(), ShouldEqual, "operation's security policy 'not_defined_scheme' is not defined, please make sure the swagger file contains a security definition named 'not_defined_scheme' under the securityDefinitions section")
+			})
+		})
+	})
+
+	Convey("Given a provider configuration with the scopes granted to the 'oauth_token' security definition and an operation that requires scopes that have not been granted", t, func() {
+		providerConfig := providerConfiguration{
+			SecuritySchemaDefinitions: map[string]specAPIKeyAuthenticator{
+				"oauth_token": apiKeyHeaderAuthenticator{
+					apiKey{
+						name:  authorizationHeader,
+						value: "superSecretToken",
+					},
+				},
+			},
+			GrantedScopes: map[string][]string{"oauth_token": {"cdns:read"}},
+		}
+		operationSecuritySchemes := SpecSecuritySchemes{SpecSecurityScheme{Name: "oauth_token", Scopes: []string{"cdns:read", "cdns:write"}}}
+		url := "https://www.host.com/v1/resource"
+		oa := newAPIAuthenticator(nil)
+		Convey("When prepareAuth method is called with the providerConfiguration", func() {
+			_, err := oa.prepareAuth(url, operationSecuritySchemes, providerConfig)
+			Convey("Then the error returned should be an AuthConfigError listing the missing scopes", func() {
+				So(err, ShouldHaveSameTypeAs, &AuthConfigError{})
+				So(err.Error(), ShouldEqual, "the credentials configured for the security definition 'oauth_token' have not been granted the scopes required to call 'https://www.host.com/v1/resource', missing scopes: cdns:write")
+			})
+		})
+	})
+
+	Convey("Given a provider configuration containing an 'apiKey' header security definition and an 'hmac' security definition, an operation that requires both 'api_key' AND 'request_signature' authentication and the resource URL", t, func() {
+		hmacAuthenticator := newHMACAuthenticator(specHMACSecurityDefinition{signatureHeader: "X-Signature", timestampHeader: "X-Timestamp", algorithm: "sha256"}, "someSecret")
+		providerConfig := providerConfiguration{
+			SecuritySchemaDefinitions: map[string]specAPIKeyAuthenticator{
+				"api_key": apiKeyHeaderAuthenticator{
+					apiKey{
+						name:  "X-API-KEY",
+						value: "superSecretKeyForApiKey",
+					},
+				},
+				"request_signature": hmacAuthenticator,
+			},
+		}
+		operationSecuritySchemes := SpecSecuritySchemes{SpecSecurityScheme{Name: "api_key"}, SpecSecurityScheme{Name: "request_signature"}}
+		url := "https://www.host.com/v1/resource"
+		oa := newAPIAuthenticator(nil)
+		Convey("When prepareAuth method is called with the providerConfiguration", func() {
+			authContext, err := oa.prepareAuth(url, operationSecuritySchemes, providerConfig)
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And both security schemes should be applied to the request", func() {
+				So(authContext.headers["X-API-KEY"], ShouldEqual, "superSecretKeyForApiKey")
+				So(authContext.signers, ShouldHaveLength, 1)
+				So(authContext.signers[0], ShouldHaveSameTypeAs, hmacAuthenticator)
+			})
+		})
+	})
+
+	Convey("Given a provider configuration containing two 'apiKey' cookie security definitions, an operation that requires both 'session' AND 'csrf' authentication and the resource URL", t, func() {
+		providerConfig := providerConfiguration{
+			SecuritySchemaDefinitions: map[string]specAPIKeyAuthenticator{
+				"csrf":    newAPIKeyCookieAuthenticator("csrf_token", "someToken"),
+				"session": newAPIKeyCookieAuthenticator("session_id", "someSession"),
+			},
+		}
+		operationSecuritySchemes := SpecSecuritySchemes{SpecSecurityScheme{Name: "csrf"}, SpecSecurityScheme{Name: "session"}}
+		url := "https://www.host.com/v1/resource"
+		oa := newAPIAuthenticator(nil)
+		Convey("When prepareAuth method is called with the providerConfiguration", func() {
+			authContext, err := oa.prepareAuth(url, operationSecuritySchemes, providerConfig)
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And both cookies should be sent in the Cookie header", func() {
+				So(authContext.headers[cookieHeader], ShouldEqual, "csrf_token=someToken; session_id=someSession")
+			})
+		})
+	})
+
+	Convey("Given a provider configuration containing an 'apiKey' security definition sent in the Authorization header and an 'aws_sigv4' security definition, an operation that requires both of them and the resource URL", t, func() {
+		providerConfig := providerConfiguration{
+			SecuritySchemaDefinitions: map[string]specAPIKeyAuthenticator{
+				"api_key": apiKeyHeaderAuthenticator{
+					apiKey{
+						name:  authorizationHeader,
+						value: "superSecretKey",
+					},
+				},
+				"aws_iam": newAWSSigV4Authenticator(awsCredentials{accessKeyID: "someAccessKeyID", secretAccessKey: "someSecretAccessKey"}, "", "us-east-1"),
+			},
+		}
+		operationSecuritySchemes := SpecSecuritySchemes{SpecSecurityScheme{Name: "api_key"}, SpecSecurityScheme{Name: "aws_iam"}}
+		url := "https://www.host.com/v1/resource"
+		oa := newAPIAuthenticator(nil)
+		Convey("When prepareAuth method is called with the providerConfiguration", func() {
+			_, err := oa.prepareAuth(url, operationSecuritySchemes, providerConfig)
+			Convey("Then the error returned should be an AuthConfigError pointing at the header populated by both security schemes", func() {
+				So(err, ShouldHaveSameTypeAs, &AuthConfigError{})
+				So(err.Error(), ShouldEqual, "the security schemes 'api_key' and 'aws_iam' are required together but both populate the header 'Authorization', please make sure the security definitions use different headers")
 			})
 		})
 	})
@@ -8,6 +8,9 @@ type specAPIKeyAuthenticator interface {
 }
 
 func createAPIKeyAuthenticator(secDef SpecSecurityDefinition, value string) specAPIKeyAuthenticator {
+	if hmacSecDef, ok := secDef.(specHMACSecurityDefinition); ok {
+		return newHMACAuthenticator(hmacSecDef, value)
+	}
 	switch secDef.getAPIKey().In {
 	case inHeader:
 		if secDef.getType() == securityDefinitionAPIKeyRefreshToken {
@@ -16,6 +19,8 @@ func createAPIKeyAuthenticator(secDef SpecSecurityDefinition, value string) spec
 		return newAPIKeyHeaderAuthenticator(secDef.getAPIKey().Name, secDef.buildValue(value))
 	case inQuery:
 		return newAPIKeyQueryAuthenticator(secDef.getAPIKey().Name, secDef.buildValue(value))
+	case inCookie:
+		return newAPIKeyCookieAuthenticator(secDef.getAPIKey().Name, secDef.buildValue(value))
 	}
 	return nil
 }
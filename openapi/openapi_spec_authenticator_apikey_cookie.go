@@ -0,0 +1,39 @@
+package openapi
+
+import "net/http"
+
+const cookieHeader = "Cookie"
+
+// Api Key Cookie Auth
+type apiKeyCookieAuthenticator struct {
+	apiKey
+}
+
+func newAPIKeyCookieAuthenticator(name, value string) apiKeyCookieAuthenticator {
+	return apiKeyCookieAuthenticator{
+		apiKey: apiKey{
+			name:  name,
+			value: value,
+		},
+	}
+}
+
+func (a apiKeyCookieAuthenticator) getContext() interface{} {
+	return a.apiKey
+}
+
+func (a apiKeyCookieAuthenticator) getType() authType {
+	return authTypeAPIKeyCookie
+}
+
+// prepareAuth adds the api key cookie to the Cookie header. If the header already contains other cookies (e.g. more
+// than one cookie security scheme is required), the cookie is appended to the existing ones. The url remains the same
+func (a apiKeyCookieAuthenticator) prepareAuth(authContext *authContext) error {
+	apiKey := a.getContext().(apiKey)
+	cookie := (&http.Cookie{Name: apiKey.name, Value: apiKey.value}).String()
+	if cookies, exists := authContext.headers[cookieHeader]; exists && cookies != "" {
+		cookie = cookies + "; " + cookie
+	}
+	authContext.headers[cookieHeader] = cookie
+	return nil
+}
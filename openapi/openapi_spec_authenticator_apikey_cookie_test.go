@@ -0,0 +1,57 @@
+package openapi
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestApiKeyCookieAuthenticator(t *testing.T) {
+	Convey("Given a name and a value", t, func() {
+		Convey("When newAPIKeyCookieAuthenticator method is called", func() {
+			apiKeyCookieAuthenticator := newAPIKeyCookieAuthenticator("session", "value")
+			Convey("Then the apiKeyCookieAuthenticator should comply with specAPIKeyAuthenticator interface", func() {
+				var _ specAPIKeyAuthenticator = apiKeyCookieAuthenticator
+			})
+			Convey("And the authType returned should be api key cookie", func() {
+				So(apiKeyCookieAuthenticator.getType(), ShouldEqual, authTypeAPIKeyCookie)
+			})
+		})
+	})
+}
+
+func TestApiKeyCookieAuthenticatorPrepareAuth(t *testing.T) {
+	Convey("Given an apiKeyCookieAuthenticator", t, func() {
+		apiKeyCookieAuthenticator := newAPIKeyCookieAuthenticator("session", "secret")
+		Convey("When prepareAuth method is called with a authContext", func() {
+			ctx := &authContext{
+				headers: map[string]string{},
+				url:     "http://www.backend.com",
+			}
+			err := apiKeyCookieAuthenticator.prepareAuth(ctx)
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And then the context headers should contain the cookie", func() {
+				So(ctx.headers, ShouldContainKey, cookieHeader)
+				So(ctx.headers[cookieHeader], ShouldEqual, "session=secret")
+			})
+			Convey("And then the context url should remain the same", func() {
+				So(ctx.url, ShouldEqual, "http://www.backend.com")
+			})
+		})
+		Convey("When prepareAuth method is called with a authContext that already contains a cookie", func() {
+			ctx := &authContext{
+				headers: map[string]string{cookieHeader: "tenant=acme"},
+				url:     "http://www.backend.com",
+			}
+			err := apiKeyCookieAuthenticator.prepareAuth(ctx)
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And then the cookie should be appended to the existing ones", func() {
+				So(ctx.headers[cookieHeader], ShouldEqual, "tenant=acme; session=secret")
+			})
+		})
+	})
+}
@@ -0,0 +1,73 @@
+package openapi
+
+import (
+	"log"
+	"sync"
+)
+
+// apiKeyFailoverAuthenticator authenticates the requests with the primary api key until the API rejects it (401
+// response), from then on the secondary api key is used for the rest of the terraform run
+type apiKeyFailoverAuthenticator struct {
+	name      string
+	primary   specAPIKeyAuthenticator
+	secondary specAPIKeyAuthenticator
+	state     *apiKeyFailoverState
+}
+
+// apiKeyFailoverState is shared by the copies of the apiKeyFailoverAuthenticator so the failover is kept across requests
+type apiKeyFailoverState struct {
+	sync.Mutex
+	secondaryActive bool
+}
+
+func newAPIKeyFailoverAuthenticator(name string, primary, secondary specAPIKeyAuthenticator) apiKeyFailoverAuthenticator {
+	return apiKeyFailoverAuthenticator{
+		name:      name,
+		primary:   primary,
+		secondary: secondary,
+		state:     &apiKeyFailoverState{},
+	}
+}
+
+func (a apiKeyFailoverAuthenticator) getContext() interface{} {
+	return a.getActive().getContext()
+}
+
+func (a apiKeyFailoverAuthenticator) getType() authType {
+	return a.getActive().getType()
+}
+
+// prepareAuth authenticates the request with the api key in use. While the primary api key is used, the failover to the
+// secondary one is registered in the auth context so the request can be retried with it if the API rejects the primary one
+func (a apiKeyFailoverAuthenticator) prepareAuth(authContext *authContext) error {
+	if a.isSecondaryActive() {
+		return a.secondary.prepareAuth(authContext)
+	}
+	if err := a.primary.prepareAuth(authContext); err != nil {
+		return err
+	}
+	authContext.failovers = append(authContext.failovers, a.activateSecondary)
+	return nil
+}
+
+func (a apiKeyFailoverAuthenticator) getActive() specAPIKeyAuthenticator {
+	if a.isSecondaryActive() {
+		return a.secondary
+	}
+	return a.primary
+}
+
+func (a apiKeyFailoverAuthenticator) isSecondaryActive() bool {
+	a.state.Lock()
+	defer a.state.Unlock()
+	return a.state.secondaryActive
+}
+
+func (a apiKeyFailoverAuthenticator) activateSecondary() {
+	a.state.Lock()
+	defer a.state.Unlock()
+	if !a.state.secondaryActive {
+		log.Printf("[WARN] the primary api key of the security definition '%s' was rejected by the API, using the secondary api key from now on", a.name)
+		a.state.secondaryActive = true
+	}
+}
@@ -0,0 +1,44 @@
+package openapi
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAPIKeyFailoverAuthenticator(t *testing.T) {
+	Convey("Given an apiKeyFailoverAuthenticator with a primary and secondary api keys", t, func() {
+		authenticator := newAPIKeyFailoverAuthenticator("api_key_auth", newAPIKeyHeaderAuthenticator("X-API-Key", "primary"), newAPIKeyQueryAuthenticator("api_key", "secondary"))
+		Convey("Then the apiKeyFailoverAuthenticator should comply with specAPIKeyAuthenticator interface", func() {
+			var _ specAPIKeyAuthenticator = authenticator
+		})
+		Convey("When prepareAuth method is called", func() {
+			primaryAuthContext := &authContext{headers: map[string]string{}, url: "http://www.host.com/v1/resource"}
+			err := authenticator.prepareAuth(primaryAuthContext)
+			Convey("Then the request should be authenticated with the primary api key and the failover registered", func() {
+				So(err, ShouldBeNil)
+				So(primaryAuthContext.headers, ShouldContainKey, "X-API-Key")
+				So(primaryAuthContext.headers["X-API-Key"], ShouldEqual, "primary")
+				So(primaryAuthContext.url, ShouldEqual, "http://www.host.com/v1/resource")
+				So(primaryAuthContext.failovers, ShouldHaveLength, 1)
+				So(authenticator.getType(), ShouldEqual, authTypeAPIKeyHeader)
+				So(authenticator.getContext().(apiKey).value, ShouldEqual, "primary")
+			})
+			Convey("And when the credentials of the auth context are failed over", func() {
+				failedOver := primaryAuthContext.failoverCredentials()
+				Convey("Then the following requests should be authenticated with the secondary api key and no failover registered", func() {
+					So(failedOver, ShouldBeTrue)
+					secondaryAuthContext := &authContext{headers: map[string]string{}, url: "http://www.host.com/v1/resource"}
+					err := authenticator.prepareAuth(secondaryAuthContext)
+					So(err, ShouldBeNil)
+					So(secondaryAuthContext.headers, ShouldNotContainKey, "X-API-Key")
+					So(secondaryAuthContext.url, ShouldEqual, "http://www.host.com/v1/resource?api_key=secondary")
+					So(secondaryAuthContext.failovers, ShouldBeEmpty)
+					So(secondaryAuthContext.failoverCredentials(), ShouldBeFalse)
+					So(authenticator.getType(), ShouldEqual, authTypeAPIQuery)
+					So(authenticator.getContext().(apiKey).value, ShouldEqual, "secondary")
+				})
+			})
+		})
+	})
+}
@@ -1,6 +1,9 @@
 package openapi
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Api Key Query Auth
 type apiKeyQueryAuthenticator struct {
@@ -29,6 +32,10 @@ func (a apiKeyQueryAuthenticator) getType() authType {
 // provides the opportunity to inject some headers if needed.
 func (a apiKeyQueryAuthenticator) prepareAuth(authContext *authContext) error {
 	apiKey := a.getContext().(apiKey)
-	authContext.url = fmt.Sprintf("%s?%s=%s", authContext.url, apiKey.name, apiKey.value)
+	separator := "?"
+	if strings.Contains(authContext.url, "?") {
+		separator = "&"
+	}
+	authContext.url = fmt.Sprintf("%s%s%s=%s", authContext.url, separator, apiKey.name, apiKey.value)
 	return nil
 }
@@ -92,5 +92,18 @@ func TestApiKeyQueryAuthenticatorPrepareAuth(t *testing.T) {
 				So(ctx.headers, ShouldEqual, expectedHeaders)
 			})
 		})
+		Convey("When prepareAuth method is called with a authContext which url already contains query parameters", func() {
+			ctx := &authContext{
+				headers: map[string]string{},
+				url:     "http://www.backend.com?fields=id,name",
+			}
+			err := apiKeyQueryAuthenticator.prepareAuth(ctx)
+			Convey("Then the err returned  should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And then the query auth should be appended to the existing query parameters", func() {
+				So(ctx.url, ShouldEqual, "http://www.backend.com?fields=id,name&name=value")
+			})
+		})
 	})
 }
@@ -35,6 +35,20 @@ func TestCreateAPIKeyAuthenticator(t *testing.T) {
 		})
 	})
 
+	Convey("Given a secDef of cookie type and a auth value ", t, func() {
+		secDef := newAPIKeyCookieSecurityDefinition("cookie_auth", "session")
+		value := "value"
+		Convey("When createAPIKeyAuthenticator method is constructed", func() {
+			apiKeyAuthenticator := createAPIKeyAuthenticator(secDef, value)
+			Convey("And the the specAPIKeyAuthenticator returned Should Have Same Type As apiKeyCookieAuthenticator", func() {
+				So(apiKeyAuthenticator, ShouldHaveSameTypeAs, apiKeyCookieAuthenticator{})
+			})
+			Convey("And the the specAPIKeyAuthenticator returned should be of type authTypeAPIKeyCookie", func() {
+				So(apiKeyAuthenticator.getType(), ShouldEqual, authTypeAPIKeyCookie)
+			})
+		})
+	})
+
 	Convey("Given a RefreshToken secDef of header type and a auth value ", t, func() {
 		secDef := newAPIKeyHeaderRefreshTokenSecurityDefinition("header_auth", authorizationHeader)
 		value := "value"
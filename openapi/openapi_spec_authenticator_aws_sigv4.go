@@ -0,0 +1,210 @@
+package openapi
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const awsSigV4Algorithm = "AWS4-HMAC-SHA256"
+const awsSigV4DateFormat = "20060102T150405Z"
+const awsSigV4DefaultService = "execute-api"
+
+const awsSigV4DateHeader = "X-Amz-Date"
+const awsSigV4SecurityTokenHeader = "X-Amz-Security-Token"
+
+// awsHostRegex matches AWS service endpoints (e.g. abc123.execute-api.us-east-1.amazonaws.com) capturing the service and
+// the region
+var awsHostRegex = regexp.MustCompile(`(?:^|\.)([a-z0-9-]+)\.([a-z]{2}(?:-gov)?-[a-z]+-\d)\.amazonaws\.com(?:\.cn)?$`)
+
+// awsCredentials contains the AWS credentials used to sign the requests
+type awsCredentials struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+}
+
+// awsSigV4Authenticator signs the requests with AWS Signature Version 4 so APIs protected by IAM (e.g. API Gateway
+// with IAM authorization) can be called. Since the signature covers the method, the URL and the payload of the request,
+// the authenticator does not populate the headers when the auth is prepared but registers itself as the request signer
+// of the auth context, which is then called right before the request is sent
+type awsSigV4Authenticator struct {
+	awsCredentials
+	service string
+	region  string
+	now     func() time.Time
+}
+
+func newAWSSigV4Authenticator(credentials awsCredentials, service, region string) awsSigV4Authenticator {
+	return awsSigV4Authenticator{
+		awsCredentials: credentials,
+		service:        service,
+		region:         region,
+		now:            time.Now,
+	}
+}
+
+func (a awsSigV4Authenticator) getContext() interface{} {
+	return a.awsCredentials
+}
+
+func (a awsSigV4Authenticator) getType() authType {
+	return authTypeAWSSigV4
+}
+
+func (a awsSigV4Authenticator) prepareAuth(authContext *authContext) error {
+	authContext.signers = append(authContext.signers, a)
+	return nil
+}
+
+// sign adds to the headers the Authorization (and X-Amz-Date) headers containing the signature of the request. An empty
+// payload is hashed when the request has none
+func (a awsSigV4Authenticator) sign(method, requestURL string, headers map[string]string, requestPayload interface{}) error {
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return err
+	}
+	service, region, err := a.resolveServiceAndRegion(u.Hostname())
+	if err != nil {
+		return err
+	}
+	payload, err := getRequestPayloadBytes(requestPayload)
+	if err != nil {
+		return err
+	}
+
+	now := a.now().UTC()
+	amzDate := now.Format(awsSigV4DateFormat)
+	headers[awsSigV4DateHeader] = amzDate
+	canonicalHeaders := fmt.Sprintf("host:%s\nx-amz-date:%s\n", a.getHost(u), amzDate)
+	signedHeaders := "host;x-amz-date"
+	if a.sessionToken != "" {
+		headers[awsSigV4SecurityTokenHeader] = a.sessionToken
+		canonicalHeaders += fmt.Sprintf("x-amz-security-token:%s\n", a.sessionToken)
+		signedHeaders += ";x-amz-security-token"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		a.getCanonicalURI(u),
+		a.getCanonicalQueryString(u),
+		canonicalHeaders,
+		signedHeaders,
+		hashSHA256(payload),
+	}, "\n")
+	credentialScope := fmt.Sprintf("%s/%s/%s/aws4_request", now.Format("20060102"), region, service)
+	stringToSign := strings.Join([]string{awsSigV4Algorithm, amzDate, credentialScope, hashSHA256([]byte(canonicalRequest))}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+a.secretAccessKey), now.Format("20060102"))
+	for _, value := range []string{region, service, "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, value)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	headers[authorizationHeader] = fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", awsSigV4Algorithm, a.accessKeyID, credentialScope, signedHeaders, signature)
+	return nil
+}
+
+func (a awsSigV4Authenticator) getSignatureHeaders() []string {
+	headers := []string{authorizationHeader, awsSigV4DateHeader}
+	if a.sessionToken != "" {
+		headers = append(headers, awsSigV4SecurityTokenHeader)
+	}
+	return headers
+}
+
+// resolveServiceAndRegion returns the service and region the request is signed for. The ones configured take preference,
+// otherwise they are resolved from the host when it is an AWS endpoint (e.g. abc123.execute-api.us-east-1.amazonaws.com).
+// The service defaults to execute-api (API Gateway)
+func (a awsSigV4Authenticator) resolveServiceAndRegion(host string) (string, string, error) {
+	service, region := a.service, a.region
+	if matches := awsHostRegex.FindStringSubmatch(host); matches != nil {
+		if service == "" {
+			service = matches[1]
+		}
+		if region == "" {
+			region = matches[2]
+		}
+	}
+	if service == "" {
+		service = awsSigV4DefaultService
+	}
+	if region == "" {
+		return "", "", &AuthConfigError{Err: fmt.Errorf("failed to sign the request to '%s' with AWS Signature Version 4: the region could not be resolved from the host, please configure it via the '%s' extension", host, extTfAuthAWSRegion)}
+	}
+	return service, region, nil
+}
+
+// getHost returns the host of the URL leaving out the default ports, as they are not sent in the Host header
+func (a awsSigV4Authenticator) getHost(u *url.URL) string {
+	if (u.Scheme == httpsScheme && u.Port() == "443") || (u.Scheme == httpScheme && u.Port() == "80") {
+		return u.Hostname()
+	}
+	return u.Host
+}
+
+// getCanonicalURI returns the URI encoded path. As per the AWS spec (for services other than S3), each path segment is
+// URI encoded twice, that is the already escaped path is encoded again
+func (a awsSigV4Authenticator) getCanonicalURI(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = awsURIEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// getCanonicalQueryString returns the query parameters URI encoded and sorted by name (and value)
+func (a awsSigV4Authenticator) getCanonicalQueryString(u *url.URL) string {
+	type parameter struct{ name, value string }
+	var parameters []parameter
+	for name, values := range u.Query() {
+		for _, value := range values {
+			parameters = append(parameters, parameter{awsURIEncode(name), awsURIEncode(value)})
+		}
+	}
+	sort.Slice(parameters, func(i, j int) bool {
+		if parameters[i].name != parameters[j].name {
+			return parameters[i].name < parameters[j].name
+		}
+		return parameters[i].value < parameters[j].value
+	})
+	var canonicalParameters []string
+	for _, p := range parameters {
+		canonicalParameters = append(canonicalParameters, fmt.Sprintf("%s=%s", p.name, p.value))
+	}
+	return strings.Join(canonicalParameters, "&")
+}
+
+// awsURIEncode encodes every byte except the unreserved characters (A-Z, a-z, 0-9, '-', '.', '_' and '~') as per RFC 3986
+func awsURIEncode(value string) string {
+	var encoded strings.Builder
+	for _, b := range []byte(value) {
+		if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') || b == '-' || b == '.' || b == '_' || b == '~' {
+			encoded.WriteByte(b)
+			continue
+		}
+		fmt.Fprintf(&encoded, "%%%02X", b)
+	}
+	return encoded.String()
+}
+
+func hashSHA256(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
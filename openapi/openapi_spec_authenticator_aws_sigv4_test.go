@@ -0,0 +1,115 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The expected signatures are the ones of the AWS Signature Version 4 test suite
+func TestAWSSigV4AuthenticatorSign(t *testing.T) {
+	credentials := awsCredentials{accessKeyID: "AKIDEXAMPLE", secretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	testCases := []struct {
+		name                  string
+		method                string
+		url                   string
+		expectedAuthorization string
+	}{
+		{
+			name:                  "get vanilla",
+			method:                "GET",
+			url:                   "https://example.amazonaws.com/",
+			expectedAuthorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name:                  "get with query parameters sorted by name",
+			method:                "GET",
+			url:                   "https://example.amazonaws.com/?Param2=value2&Param1=value1",
+			expectedAuthorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+		{
+			name:                  "get with query parameters sorted by value",
+			method:                "GET",
+			url:                   "https://example.amazonaws.com/?Param1=value2&Param1=Value1",
+			expectedAuthorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=eedbc4e291e521cf13422ffca22be7d2eb8146eecf653089df300a15b2382bd1",
+		},
+		{
+			name:                  "post vanilla",
+			method:                "POST",
+			url:                   "https://example.amazonaws.com/",
+			expectedAuthorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+		},
+	}
+	for _, tc := range testCases {
+		authenticator := newAWSSigV4Authenticator(credentials, "service", "us-east-1")
+		authenticator.now = func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) }
+		headers := map[string]string{}
+		err := authenticator.sign(tc.method, tc.url, headers, nil)
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, "20150830T123600Z", headers[awsSigV4DateHeader], tc.name)
+		assert.Equal(t, tc.expectedAuthorization, headers[authorizationHeader], tc.name)
+	}
+}
+
+func TestAWSSigV4AuthenticatorSign_SessionTokenAndPayload(t *testing.T) {
+	authenticator := newAWSSigV4Authenticator(awsCredentials{accessKeyID: "AKIDEXAMPLE", secretAccessKey: "secret", sessionToken: "token"}, "", "")
+	authenticator.now = func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) }
+
+	headers := map[string]string{}
+	err := authenticator.sign("POST", "https://abc123.execute-api.eu-west-1.amazonaws.com/v1/cdns", headers, map[string]interface{}{"label": "some label"})
+	require.NoError(t, err)
+	assert.Equal(t, "token", headers[awsSigV4SecurityTokenHeader])
+	assert.True(t, strings.HasPrefix(headers[authorizationHeader], "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/eu-west-1/execute-api/aws4_request, SignedHeaders=host;x-amz-date;x-amz-security-token, Signature="), headers[authorizationHeader])
+
+	otherPayloadHeaders := map[string]string{}
+	err = authenticator.sign("POST", "https://abc123.execute-api.eu-west-1.amazonaws.com/v1/cdns", otherPayloadHeaders, map[string]interface{}{"label": "other label"})
+	require.NoError(t, err)
+	assert.NotEqual(t, headers[authorizationHeader], otherPayloadHeaders[authorizationHeader], "the signature should cover the payload")
+}
+
+func TestAWSSigV4AuthenticatorResolveServiceAndRegion(t *testing.T) {
+	testCases := []struct {
+		name            string
+		service         string
+		region          string
+		host            string
+		expectedService string
+		expectedRegion  string
+		expectedError   string
+	}{
+		{name: "resolved from the API Gateway host", host: "abc123.execute-api.us-east-1.amazonaws.com", expectedService: "execute-api", expectedRegion: "us-east-1"},
+		{name: "resolved from a GovCloud host", host: "abc123.execute-api.us-gov-west-1.amazonaws.com", expectedService: "execute-api", expectedRegion: "us-gov-west-1"},
+		{name: "configured values take preference over the host", service: "lambda", region: "eu-west-1", host: "abc123.execute-api.us-east-1.amazonaws.com", expectedService: "lambda", expectedRegion: "eu-west-1"},
+		{name: "custom domain with the region configured", region: "eu-west-1", host: "api.company.com", expectedService: "execute-api", expectedRegion: "eu-west-1"},
+		{name: "custom domain without region", host: "api.company.com", expectedError: "failed to sign the request to 'api.company.com' with AWS Signature Version 4: the region could not be resolved from the host, please configure it via the 'x-terraform-auth-aws-region' extension"},
+	}
+	for _, tc := range testCases {
+		authenticator := newAWSSigV4Authenticator(awsCredentials{}, tc.service, tc.region)
+		service, region, err := authenticator.resolveServiceAndRegion(tc.host)
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError, tc.name)
+			continue
+		}
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expectedService, service, tc.name)
+		assert.Equal(t, tc.expectedRegion, region, tc.name)
+	}
+}
+
+func TestAWSSigV4AuthenticatorPrepareAuth(t *testing.T) {
+	authenticator := newAWSSigV4Authenticator(awsCredentials{accessKeyID: "AKIDEXAMPLE"}, "", "us-east-1")
+	authContext := &authContext{headers: map[string]string{}}
+	assert.NoError(t, authenticator.prepareAuth(authContext))
+	assert.Empty(t, authContext.headers, "the headers should only be populated when the request is signed")
+	require.Len(t, authContext.signers, 1)
+	assert.IsType(t, awsSigV4Authenticator{}, authContext.signers[0], "the authenticator should be registered to sign the request")
+	assert.Equal(t, authTypeAWSSigV4, authenticator.getType())
+}
+
+func TestAWSURIEncode(t *testing.T) {
+	assert.Equal(t, "AZaz09-._~", awsURIEncode("AZaz09-._~"))
+	assert.Equal(t, "a%20b%2Fc%3D%2A", awsURIEncode("a b/c=*"))
+}
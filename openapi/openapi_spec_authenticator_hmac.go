@@ -0,0 +1,80 @@
+package openapi
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"strconv"
+	"time"
+)
+
+// hmacHashFunctions contains the hash algorithms supported to compute the HMAC signatures
+var hmacHashFunctions = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// hmacAuthenticator signs the requests with an HMAC of the request timestamp (unix time in seconds) followed by the
+// request body. The timestamp and the hex encoded signature are sent in the headers configured in the security definition.
+// Since the signature covers the payload, the authenticator registers itself as a request signer of the auth context
+// which is called right before the request is sent
+type hmacAuthenticator struct {
+	secret          string
+	signatureHeader string
+	timestampHeader string
+	algorithm       string
+	signaturePrefix string
+	now             func() time.Time
+}
+
+func newHMACAuthenticator(secDef specHMACSecurityDefinition, secret string) hmacAuthenticator {
+	return hmacAuthenticator{
+		secret:          secret,
+		signatureHeader: secDef.signatureHeader,
+		timestampHeader: secDef.timestampHeader,
+		algorithm:       secDef.algorithm,
+		signaturePrefix: secDef.signaturePrefix,
+		now:             time.Now,
+	}
+}
+
+func (a hmacAuthenticator) getContext() interface{} {
+	return a.secret
+}
+
+func (a hmacAuthenticator) getType() authType {
+	return authTypeHMAC
+}
+
+func (a hmacAuthenticator) prepareAuth(authContext *authContext) error {
+	authContext.signers = append(authContext.signers, a)
+	return nil
+}
+
+// sign adds to the headers the timestamp of the request and the signature of the timestamp followed by the request body
+// (if any)
+func (a hmacAuthenticator) sign(method, requestURL string, headers map[string]string, requestPayload interface{}) error {
+	payload, err := getRequestPayloadBytes(requestPayload)
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(a.now().Unix(), 10)
+	headers[a.timestampHeader] = timestamp
+	headers[a.signatureHeader] = a.signaturePrefix + a.computeSignature(timestamp, payload)
+	return nil
+}
+
+func (a hmacAuthenticator) getSignatureHeaders() []string {
+	return []string{a.timestampHeader, a.signatureHeader}
+}
+
+func (a hmacAuthenticator) computeSignature(timestamp string, payload []byte) string {
+	mac := hmac.New(hmacHashFunctions[a.algorithm], []byte(a.secret))
+	mac.Write([]byte(timestamp))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,60 @@
+package openapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHMACAuthenticatorSign(t *testing.T) {
+	testCases := []struct {
+		name              string
+		secDef            specHMACSecurityDefinition
+		method            string
+		requestPayload    interface{}
+		expectedHeaders   map[string]string
+		expectedSignature string
+	}{
+		{
+			name:           "default headers and algorithm",
+			secDef:         newHMACSecurityDefinition("hmac_auth", "", "", "", ""),
+			method:         "POST",
+			requestPayload: map[string]interface{}{"label": "some label"},
+			expectedHeaders: map[string]string{
+				"X-Timestamp": "1440938160",
+				"X-Signature": "1833b46bd0c8f304edec9209455a036d82b19134ae8437a07e5930a31398f23c",
+			},
+		},
+		{
+			name:           "custom headers, algorithm and signature prefix with no payload",
+			secDef:         newHMACSecurityDefinition("hmac_auth", "X-Hub-Signature", "X-Request-Time", "SHA512", "sha512="),
+			method:         "GET",
+			requestPayload: nil,
+			expectedHeaders: map[string]string{
+				"X-Request-Time":  "1440938160",
+				"X-Hub-Signature": "sha512=8903aad3842801207f7bd89a1d83ec634353fe33b075d788c6b619367f257efd564fc1bf25bce9cde17e2cdc7618c92cd5d86ad87a5b08fcefc3ed0cbcd7c6eb",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		authenticator := newHMACAuthenticator(tc.secDef, "secret")
+		authenticator.now = func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) }
+		headers := map[string]string{}
+		err := authenticator.sign(tc.method, "https://api.server.com/v1/cdns", headers, tc.requestPayload)
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expectedHeaders, headers, tc.name)
+	}
+}
+
+func TestHMACAuthenticatorPrepareAuth(t *testing.T) {
+	authenticator := newHMACAuthenticator(newHMACSecurityDefinition("hmac_auth", "", "", "", ""), "secret")
+	authContext := &authContext{headers: map[string]string{}}
+	assert.NoError(t, authenticator.prepareAuth(authContext))
+	assert.Empty(t, authContext.headers, "the headers should only be populated when the request is signed")
+	require.Len(t, authContext.signers, 1)
+	assert.IsType(t, hmacAuthenticator{}, authContext.signers[0], "the authenticator should be registered to sign the request")
+	assert.Equal(t, authTypeHMAC, authenticator.getType())
+	assert.Equal(t, "secret", authenticator.getContext())
+}
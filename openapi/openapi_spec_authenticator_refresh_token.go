@@ -1,6 +1,8 @@
 package openapi
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 
@@ -12,6 +14,9 @@ type apiRefreshTokenAuthenticator struct {
 	apiKey
 	refreshTokenURL string
 	httpClient      http_goclient.HttpClientIface
+	// tokenCache keeps the access tokens obtained so they are reused until they are about to expire. It is shared by the
+	// copies of the authenticator; if nil a new access token is requested for every request
+	tokenCache *accessTokenCache
 }
 
 func newAPIRefreshTokenAuthenticator(name, refreshToken, refreshTokenURL string) apiRefreshTokenAuthenticator {
@@ -22,6 +27,7 @@ func newAPIRefreshTokenAuthenticator(name, refreshToken, refreshTokenURL string)
 		},
 		refreshTokenURL: refreshTokenURL,
 		httpClient:      &http_goclient.HttpClient{HttpClient: &http.Client{}},
+		tokenCache:      newAccessTokenCache(),
 	}
 }
 
@@ -34,24 +40,56 @@ func (a apiRefreshTokenAuthenticator) getType() authType {
 }
 
 // prepareAuth will send a post request to the refreshTokenURL and get the access token from the response Authorization
-// header. Otherwise, it will fail.
+// header. Otherwise, it will fail. The access token is cached and reused until it is about to expire; if the API rejects
+// a cached access token, it is discarded so the request is retried with a new one.
 func (a apiRefreshTokenAuthenticator) prepareAuth(authContext *authContext) error {
+	if a.tokenCache == nil {
+		token, err := a.requestAccessToken()
+		if err != nil {
+			return err
+		}
+		a.setAuthorizationHeader(authContext, token.AccessToken)
+		return nil
+	}
+	cacheKey := a.getCacheKey()
+	accessToken, cached, err := a.tokenCache.getAccessToken(cacheKey, a.requestAccessToken)
+	if err != nil {
+		return err
+	}
+	if cached {
+		authContext.failovers = append(authContext.failovers, func() { a.tokenCache.discard(cacheKey) })
+	}
+	a.setAuthorizationHeader(authContext, accessToken)
+	return nil
+}
+
+// requestAccessToken sends a post request to the refreshTokenURL to obtain a new access token
+func (a apiRefreshTokenAuthenticator) requestAccessToken() (*accessToken, error) {
 	apiKey := a.getContext().(apiKey)
 	headers := map[string]string{apiKey.name: apiKey.value}
 	r, err := a.httpClient.PostJson(a.refreshTokenURL, headers, nil, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if r.StatusCode != http.StatusOK && r.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("refresh token POST response '%s' status code '%d' not matching expected response status code [%d, %d]", a.refreshTokenURL, r.StatusCode, http.StatusOK, http.StatusNoContent)
+		return nil, &AuthConfigError{Err: fmt.Errorf("refresh token POST response '%s' status code '%d' not matching expected response status code [%d, %d]", a.refreshTokenURL, r.StatusCode, http.StatusOK, http.StatusNoContent)}
 	}
-	accessToken := r.Header.Get(authorizationHeader)
-	if accessToken == "" {
-		return fmt.Errorf("refresh token POST response '%s' is missing the access token", a.refreshTokenURL)
+	token := r.Header.Get(authorizationHeader)
+	if token == "" {
+		return nil, &AuthConfigError{Err: fmt.Errorf("refresh token POST response '%s' is missing the access token", a.refreshTokenURL)}
 	}
+	return newAccessToken(token), nil
+}
+
+// getCacheKey returns the key the access tokens are cached with, which identifies the refresh token without exposing it
+func (a apiRefreshTokenAuthenticator) getCacheKey() string {
+	hash := sha256.Sum256([]byte(a.refreshTokenURL + "\n" + a.apiKey.value))
+	return fmt.Sprintf("%s-%s", a.apiKey.name, hex.EncodeToString(hash[:]))
+}
+
+func (a apiRefreshTokenAuthenticator) setAuthorizationHeader(authContext *authContext, accessToken string) {
 	if authContext.headers == nil {
 		authContext.headers = map[string]string{}
 	}
 	authContext.headers[authorizationHeader] = accessToken
-	return nil
 }
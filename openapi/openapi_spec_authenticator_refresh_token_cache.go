@@ -0,0 +1,177 @@
+package openapi
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// accessTokenExpiryLeeway makes sure the access tokens are refreshed ahead of their expiry, so the requests made in
+	// the middle of an apply are not rejected because the token expired on the way
+	accessTokenExpiryLeeway = 1 * time.Minute
+	// accessTokenDefaultTTL is how long the access tokens whose expiry is not known are reused. They are only cached in
+	// memory since there is no way to tell whether they are still valid in later runs
+	accessTokenDefaultTTL = 5 * time.Minute
+)
+
+// accessToken describes an access token obtained with a refresh token
+type accessToken struct {
+	AccessToken string    `json:"access_token"`
+	Expiry      time.Time `json:"expiry"`
+}
+
+// newAccessToken returns the access token with its expiry, which is read from the exp claim when the access token is a
+// JWT (e.g. 'Bearer eyJhbGciOi...'). The expiry is zero if not known
+func newAccessToken(token string) *accessToken {
+	accessToken := &accessToken{AccessToken: token}
+	parts := strings.Split(strings.TrimSpace(strings.TrimPrefix(token, "Bearer ")), ".")
+	if len(parts) != 3 {
+		return accessToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return accessToken
+	}
+	claims := struct {
+		Exp int64 `json:"exp"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp <= 0 {
+		return accessToken
+	}
+	accessToken.Expiry = time.Unix(claims.Exp, 0)
+	return accessToken
+}
+
+func (t accessToken) isValid(now time.Time) bool {
+	return t.AccessToken != "" && now.Add(accessTokenExpiryLeeway).Before(t.Expiry)
+}
+
+// accessTokenCache keeps the access tokens obtained with refresh tokens in memory and, if a file is configured, in disk
+// so they are reused across terraform runs. The tokens are keyed by the refresh token they were obtained with
+type accessTokenCache struct {
+	sync.Mutex
+	tokens map[string]accessToken
+	file   string
+	now    func() time.Time
+}
+
+func newAccessTokenCache() *accessTokenCache {
+	return &accessTokenCache{
+		tokens: map[string]accessToken{},
+		now:    time.Now,
+	}
+}
+
+// enableFileCache makes the cache store the access tokens in ~/.terraform.d/<provider_name>_access_tokens.json
+func (c *accessTokenCache) enableFileCache(providerName string) error {
+	file, err := expandPath(fmt.Sprintf("~/.terraform.d/%s_access_tokens.json", providerName))
+	if err != nil {
+		return fmt.Errorf("failed to resolve the access token cache file: %s", err)
+	}
+	c.Lock()
+	defer c.Unlock()
+	c.file = file
+	return nil
+}
+
+// getAccessToken returns the access token cached with the given key, if it is not about to expire. Otherwise, a new
+// access token is requested and cached. The lock is held while requesting it so concurrent requests wait for the new
+// access token instead of requesting one each. The returned bool is true if the access token was already cached
+func (c *accessTokenCache) getAccessToken(key string, requestAccessToken func() (*accessToken, error)) (string, bool, error) {
+	c.Lock()
+	defer c.Unlock()
+	now := c.now()
+	if token, exists := c.tokens[key]; exists && token.isValid(now) {
+		return token.AccessToken, true, nil
+	}
+	if token, exists := c.loadTokens()[key]; exists && token.isValid(now) {
+		c.tokens[key] = token
+		return token.AccessToken, true, nil
+	}
+	token, err := requestAccessToken()
+	if err != nil {
+		return "", false, err
+	}
+	if token.Expiry.IsZero() {
+		c.tokens[key] = accessToken{AccessToken: token.AccessToken, Expiry: now.Add(accessTokenDefaultTTL)}
+		return token.AccessToken, false, nil
+	}
+	c.tokens[key] = *token
+	if err := c.saveToken(key, *token); err != nil {
+		log.Printf("[WARN] %s", err)
+	}
+	return token.AccessToken, false, nil
+}
+
+// discard removes the access token cached with the given key (e.g. the API rejected it), from memory as well as from disk
+func (c *accessTokenCache) discard(key string) {
+	c.Lock()
+	defer c.Unlock()
+	if _, exists := c.tokens[key]; !exists {
+		return
+	}
+	log.Printf("[INFO] the cached access token was rejected by the API, a new access token will be requested")
+	delete(c.tokens, key)
+	if err := c.saveToken(key, accessToken{}); err != nil {
+		log.Printf("[WARN] %s", err)
+	}
+}
+
+// loadTokens reads the access tokens cached in disk; empty if the file cache is not enabled or there are no tokens
+// cached yet
+func (c *accessTokenCache) loadTokens() map[string]accessToken {
+	tokens := map[string]accessToken{}
+	if c.file == "" {
+		return tokens
+	}
+	data, err := ioutil.ReadFile(c.file)
+	if os.IsNotExist(err) {
+		return tokens
+	}
+	if err != nil {
+		log.Printf("[WARN] failed to read the access token cache file '%s': %s", c.file, err)
+		return tokens
+	}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		log.Printf("[WARN] ignoring the access token cache file '%s' since it is not valid: %s", c.file, err)
+		return map[string]accessToken{}
+	}
+	return tokens
+}
+
+// saveToken stores the given access token in disk along with the ones still valid, making sure only the user running
+// the provider can read them. An empty access token removes the one cached with the given key
+func (c *accessTokenCache) saveToken(key string, token accessToken) error {
+	if c.file == "" {
+		return nil
+	}
+	tokens := c.loadTokens()
+	for cachedKey, cachedToken := range tokens {
+		if !cachedToken.isValid(c.now()) {
+			delete(tokens, cachedKey)
+		}
+	}
+	delete(tokens, key)
+	if token.AccessToken != "" {
+		tokens[key] = token
+	}
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.file), 0700); err != nil {
+		return fmt.Errorf("failed to create the directory of the access token cache file '%s': %s", c.file, err)
+	}
+	if err := ioutil.WriteFile(c.file, data, 0600); err != nil {
+		return fmt.Errorf("failed to write the access token cache file '%s': %s", c.file, err)
+	}
+	return nil
+}
@@ -1,11 +1,16 @@
 package openapi
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/dikhan/http_goclient"
 
@@ -82,3 +87,126 @@ func Test_ApiKeyRefreshTokenAuthenticator_Fails_To_Prepare_Authorization(t *test
 		assert.EqualError(t, err, "postJSON failed")
 	})
 }
+
+func newTestJWTAccessToken(expiry time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"user","exp":%d}`, expiry.Unix())))
+	return fmt.Sprintf("Bearer eyJhbGciOiJIUzI1NiJ9.%s.c2lnbmF0dXJl", payload)
+}
+
+func Test_ApiKeyRefreshTokenAuthenticator_Caches_The_Access_Token(t *testing.T) {
+	now := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	newAccessTokenServer := func(accessTokens ...string) (*httptest.Server, *int) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add(authorizationHeader, accessTokens[requests%len(accessTokens)])
+			requests++
+		}))
+		return server, &requests
+	}
+	newAuthenticator := func(refreshTokenURL string) apiRefreshTokenAuthenticator {
+		refreshTokenAuthenticator := newAPIRefreshTokenAuthenticator("my_fancy_name", "refresh_token", refreshTokenURL)
+		refreshTokenAuthenticator.tokenCache.now = func() time.Time { return now }
+		return refreshTokenAuthenticator
+	}
+
+	t.Run("happy path -- the access token is reused while it is not about to expire", func(t *testing.T) {
+		accessToken := newTestJWTAccessToken(now.Add(1 * time.Hour))
+		server, requests := newAccessTokenServer(accessToken)
+		defer server.Close()
+		refreshTokenAuthenticator := newAuthenticator(server.URL)
+		for i := 0; i < 3; i++ {
+			ctx := &authContext{}
+			assert.NoError(t, refreshTokenAuthenticator.prepareAuth(ctx))
+			assert.Equal(t, accessToken, ctx.headers[authorizationHeader])
+		}
+		assert.Equal(t, 1, *requests)
+	})
+
+	t.Run("happy path -- a new access token is requested when the cached one is about to expire", func(t *testing.T) {
+		firstAccessToken := newTestJWTAccessToken(now.Add(30 * time.Second))
+		secondAccessToken := newTestJWTAccessToken(now.Add(1 * time.Hour))
+		server, requests := newAccessTokenServer(firstAccessToken, secondAccessToken)
+		defer server.Close()
+		refreshTokenAuthenticator := newAuthenticator(server.URL)
+		ctx := &authContext{}
+		assert.NoError(t, refreshTokenAuthenticator.prepareAuth(ctx))
+		assert.Equal(t, firstAccessToken, ctx.headers[authorizationHeader])
+		ctx = &authContext{}
+		assert.NoError(t, refreshTokenAuthenticator.prepareAuth(ctx))
+		assert.Equal(t, secondAccessToken, ctx.headers[authorizationHeader])
+		assert.Equal(t, 2, *requests)
+	})
+
+	t.Run("happy path -- the access token with no known expiry is reused for the default TTL", func(t *testing.T) {
+		server, requests := newAccessTokenServer("opaque_access_token")
+		defer server.Close()
+		refreshTokenAuthenticator := newAuthenticator(server.URL)
+		assert.NoError(t, refreshTokenAuthenticator.prepareAuth(&authContext{}))
+		assert.NoError(t, refreshTokenAuthenticator.prepareAuth(&authContext{}))
+		assert.Equal(t, 1, *requests)
+		refreshTokenAuthenticator.tokenCache.now = func() time.Time { return now.Add(accessTokenDefaultTTL) }
+		assert.NoError(t, refreshTokenAuthenticator.prepareAuth(&authContext{}))
+		assert.Equal(t, 2, *requests)
+	})
+
+	t.Run("happy path -- the cached access token is discarded when the API rejects it", func(t *testing.T) {
+		server, requests := newAccessTokenServer(newTestJWTAccessToken(now.Add(1 * time.Hour)))
+		defer server.Close()
+		refreshTokenAuthenticator := newAuthenticator(server.URL)
+		ctx := &authContext{}
+		assert.NoError(t, refreshTokenAuthenticator.prepareAuth(ctx))
+		assert.False(t, ctx.failoverCredentials(), "a freshly requested access token should not be discarded")
+		ctx = &authContext{}
+		assert.NoError(t, refreshTokenAuthenticator.prepareAuth(ctx))
+		assert.Equal(t, 1, *requests)
+		assert.True(t, ctx.failoverCredentials())
+		assert.NoError(t, refreshTokenAuthenticator.prepareAuth(&authContext{}))
+		assert.Equal(t, 2, *requests)
+	})
+
+	t.Run("happy path -- the access token cached in disk is reused across runs", func(t *testing.T) {
+		tokenCacheDir, err := ioutil.TempDir("", "")
+		assert.NoError(t, err)
+		defer os.RemoveAll(tokenCacheDir)
+		accessToken := newTestJWTAccessToken(now.Add(1 * time.Hour))
+		server, requests := newAccessTokenServer(accessToken)
+		defer server.Close()
+
+		firstRun := newAuthenticator(server.URL)
+		firstRun.tokenCache.file = filepath.Join(tokenCacheDir, "openapi_access_tokens.json")
+		assert.NoError(t, firstRun.prepareAuth(&authContext{}))
+		info, err := os.Stat(firstRun.tokenCache.file)
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		content, err := ioutil.ReadFile(firstRun.tokenCache.file)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(content), "refresh_token")
+
+		secondRun := newAuthenticator(server.URL)
+		secondRun.tokenCache.file = firstRun.tokenCache.file
+		ctx := &authContext{}
+		assert.NoError(t, secondRun.prepareAuth(ctx))
+		assert.Equal(t, accessToken, ctx.headers[authorizationHeader])
+		assert.Equal(t, 1, *requests)
+	})
+}
+
+func TestNewAccessToken(t *testing.T) {
+	expiry := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name           string
+		token          string
+		expectedExpiry time.Time
+	}{
+		{name: "JWT access token", token: newTestJWTAccessToken(expiry), expectedExpiry: expiry},
+		{name: "opaque access token", token: "Bearer opaque_access_token", expectedExpiry: time.Time{}},
+		{name: "access token with three parts that is not a JWT", token: "not.a.jwt", expectedExpiry: time.Time{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			accessToken := newAccessToken(tc.token)
+			assert.Equal(t, tc.token, accessToken.AccessToken, tc.name)
+			assert.True(t, tc.expectedExpiry.Equal(accessToken.Expiry), tc.name)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// tokenIntrospectionResponse describes the response returned by the token introspection endpoints as defined in
+// RFC 7662 (https://tools.ietf.org/html/rfc7662#section-2.2)
+type tokenIntrospectionResponse struct {
+	Active bool   `json:"active"`
+	Scope  string `json:"scope"`
+}
+
+// introspectTokenScopes sends the given token to the introspection endpoint and returns the scopes granted to it. The token
+// is sent in the request form as defined in RFC 7662 and also as a bearer token in the Authorization header so endpoints
+// protected by the token itself can also be used. An error is returned if the token is not active.
+func introspectTokenScopes(httpClient *http.Client, introspectionURL, token string) ([]string, error) {
+	token = strings.TrimSpace(strings.TrimPrefix(token, bearerScheme))
+	req, err := http.NewRequest(http.MethodPost, introspectionURL, strings.NewReader(url.Values{"token": {token}}.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(contentTypeHeader, "application/x-www-form-urlencoded")
+	req.Header.Set(authorizationHeader, fmt.Sprintf("%s %s", bearerScheme, token))
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, &AuthConfigError{Err: fmt.Errorf("token introspection POST request '%s' failed: %s", introspectionURL, err)}
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, &AuthConfigError{Err: fmt.Errorf("token introspection POST response '%s' status code '%d' not matching expected response status code [%d]", introspectionURL, res.StatusCode, http.StatusOK)}
+	}
+	introspection := tokenIntrospectionResponse{}
+	if err := json.NewDecoder(res.Body).Decode(&introspection); err != nil {
+		return nil, &AuthConfigError{Err: fmt.Errorf("token introspection POST response '%s' could not be decoded: %s", introspectionURL, err)}
+	}
+	if !introspection.Active {
+		return nil, &AuthConfigError{Err: fmt.Errorf("token introspection POST response '%s' reported the token is not active", introspectionURL)}
+	}
+	return strings.Fields(introspection.Scope), nil
+}
@@ -0,0 +1,48 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntrospectTokenScopes(t *testing.T) {
+	testCases := []struct {
+		name           string
+		token          string
+		responseStatus int
+		responseBody   string
+		expectedScopes []string
+		expectedError  string
+	}{
+		{name: "active token", token: "someToken", responseStatus: http.StatusOK, responseBody: `{"active": true, "scope": "cdns:read cdns:write"}`, expectedScopes: []string{"cdns:read", "cdns:write"}},
+		{name: "active token containing the bearer scheme", token: "Bearer someToken", responseStatus: http.StatusOK, responseBody: `{"active": true, "scope": "cdns:read"}`, expectedScopes: []string{"cdns:read"}},
+		{name: "active token without scopes", token: "someToken", responseStatus: http.StatusOK, responseBody: `{"active": true}`, expectedScopes: []string{}},
+		{name: "inactive token", token: "someToken", responseStatus: http.StatusOK, responseBody: `{"active": false}`, expectedError: "token introspection POST response '%s' reported the token is not active"},
+		{name: "unexpected status code", token: "someToken", responseStatus: http.StatusUnauthorized, expectedError: "token introspection POST response '%s' status code '401' not matching expected response status code [200]"},
+		{name: "response body not valid json", token: "someToken", responseStatus: http.StatusOK, responseBody: `not json`, expectedError: "token introspection POST response '%s' could not be decoded: invalid character 'o' in literal null (expecting 'u')"},
+	}
+	for _, tc := range testCases {
+		introspectionServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method, tc.name)
+			assert.Equal(t, "someToken", r.FormValue("token"), tc.name)
+			assert.Equal(t, "Bearer someToken", r.Header.Get(authorizationHeader), tc.name)
+			w.WriteHeader(tc.responseStatus)
+			w.Write([]byte(tc.responseBody))
+		}))
+		scopes, err := introspectTokenScopes(&http.Client{}, introspectionServer.URL, tc.token)
+		if tc.expectedError != "" {
+			require.Error(t, err, tc.name)
+			assert.IsType(t, &AuthConfigError{}, err, tc.name)
+			assert.EqualError(t, err, fmt.Sprintf(tc.expectedError, introspectionServer.URL), tc.name)
+		} else {
+			require.NoError(t, err, tc.name)
+			assert.Equal(t, tc.expectedScopes, scopes, tc.name)
+		}
+		introspectionServer.Close()
+	}
+}
@@ -8,4 +8,7 @@ type SpecBackendConfiguration interface {
 	getHostByRegion(region string) (string, error)
 	isMultiRegion() (bool, string, []string, error)
 	getDefaultRegion([]string) (string, error)
+	// allowsRawSlashesInPathParameters returns true if the API expects forward slashes in the path parameter values
+	// (e.g. ids) to be sent as is instead of URL encoded
+	allowsRawSlashesInPathParameters() bool
 }
@@ -0,0 +1,379 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SpecLoginCommand is the command that makes the provider binary log in against the identity provider protecting the
+// swagger URL (e.g. terraform-provider-<provider_name> spec-login), caching the access token used to fetch the swagger file
+const SpecLoginCommand = "spec-login"
+
+// SpecLogin runs the OIDC device authorization grant configured in the service configuration (swagger_url_oidc), writing
+// into the given writer the instructions the user has to follow to authorize the provider. The access token obtained is
+// cached so the provider can fetch the swagger file from then on.
+func (p *ProviderOpenAPI) SpecLogin(w io.Writer) error {
+	serviceConfiguration, err := getServiceConfiguration(p.ProviderName)
+	if err != nil {
+		return fmt.Errorf("plugin init error: %s", err)
+	}
+	oidcConfiguration := getServiceConfigurationOptions(serviceConfiguration).GetSwaggerURLOIDC()
+	if oidcConfiguration == nil {
+		return fmt.Errorf("provider '%s' does not have the swagger_url_oidc configured in the plugin configuration file", p.ProviderName)
+	}
+	oidcClient, err := newOIDCDeviceFlowClient(p.ProviderName, *oidcConfiguration)
+	if err != nil {
+		return err
+	}
+	return oidcClient.login(w)
+}
+
+const (
+	oidcDiscoveryPath          = "/.well-known/openid-configuration"
+	oidcDeviceCodeGrantType    = "urn:ietf:params:oauth:grant-type:device_code"
+	oidcRefreshTokenGrantType  = "refresh_token"
+	oidcDefaultPollingInterval = 5 * time.Second
+	// oidcTokenExpiryLeeway makes sure the cached tokens are not used when they are about to expire
+	oidcTokenExpiryLeeway = 30 * time.Second
+)
+
+// Errors returned by the token endpoint while the device authorization is being polled (RFC 8628 section 3.5)
+const (
+	oidcErrorAuthorizationPending = "authorization_pending"
+	oidcErrorSlowDown             = "slow_down"
+)
+
+// oidcProviderMetadata describes the endpoints published by the identity provider in its discovery document
+type oidcProviderMetadata struct {
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+}
+
+// oidcDeviceAuthorization describes the response of the device authorization endpoint
+type oidcDeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+}
+
+// oidcTokenResponse describes the response of the token endpoint, including the error ones
+type oidcTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// oidcToken describes the token cached in disk
+type oidcToken struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+}
+
+func (t oidcToken) isValid(now time.Time) bool {
+	return t.AccessToken != "" && (t.Expiry.IsZero() || now.Add(oidcTokenExpiryLeeway).Before(t.Expiry))
+}
+
+// oidcDeviceFlowClient obtains the access token used to fetch the swagger file when it is hosted behind an identity
+// provider. Terraform does not allow the provider to interact with the user, hence the device authorization grant is only
+// run by the spec-login command, which caches the token in disk. The provider then uses the cached token, refreshing it
+// when it has expired.
+type oidcDeviceFlowClient struct {
+	providerName   string
+	config         ServiceOIDC
+	tokenCacheFile string
+	httpClient     *http.Client
+	now            func() time.Time
+	sleep          func(time.Duration)
+}
+
+func newOIDCDeviceFlowClient(providerName string, config ServiceOIDC) (*oidcDeviceFlowClient, error) {
+	tokenCacheFile := config.TokenCacheFile
+	if tokenCacheFile == "" {
+		tokenCacheFile = fmt.Sprintf("~/.terraform.d/%s_oidc_token.json", providerName)
+	}
+	tokenCacheFile, err := expandPath(tokenCacheFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the OIDC token cache file: %s", err)
+	}
+	return &oidcDeviceFlowClient{
+		providerName:   providerName,
+		config:         config,
+		tokenCacheFile: tokenCacheFile,
+		httpClient:     &http.Client{Timeout: 30 * time.Second},
+		now:            time.Now,
+		sleep:          time.Sleep,
+	}, nil
+}
+
+// login runs the device authorization grant, asking the user (via the given writer) to open the verification URI and enter
+// the user code, and caches the token obtained once the user has authorized the device
+func (c *oidcDeviceFlowClient) login(w io.Writer) error {
+	metadata, err := c.discover()
+	if err != nil {
+		return err
+	}
+	if metadata.DeviceAuthorizationEndpoint == "" {
+		return fmt.Errorf("OIDC issuer '%s' does not support the device authorization grant (device_authorization_endpoint missing in the discovery document)", c.config.Issuer)
+	}
+	deviceAuthorization := oidcDeviceAuthorization{}
+	err = c.postForm(metadata.DeviceAuthorizationEndpoint, url.Values{
+		"client_id": {c.config.ClientID},
+		"scope":     {strings.Join(c.config.Scopes, " ")},
+	}, &deviceAuthorization)
+	if err != nil {
+		return fmt.Errorf("OIDC device authorization request failed: %s", err)
+	}
+	if deviceAuthorization.DeviceCode == "" {
+		return errors.New("OIDC device authorization response is missing the device_code")
+	}
+
+	fmt.Fprintf(w, "To authenticate against '%s', open the following URL in a browser and enter the code %s:\n\n    %s\n\n", c.config.Issuer, deviceAuthorization.UserCode, deviceAuthorization.VerificationURI)
+	if deviceAuthorization.VerificationURIComplete != "" {
+		fmt.Fprintf(w, "Alternatively, open the following URL which already includes the code:\n\n    %s\n\n", deviceAuthorization.VerificationURIComplete)
+	}
+	fmt.Fprintln(w, "Waiting for the authorization to complete...")
+
+	token, err := c.pollDeviceAuthorization(metadata.TokenEndpoint, deviceAuthorization)
+	if err != nil {
+		return err
+	}
+	if err := c.saveToken(token); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Authorization completed, the access token has been cached in '%s'\n", c.tokenCacheFile)
+	return nil
+}
+
+func (c *oidcDeviceFlowClient) pollDeviceAuthorization(tokenEndpoint string, deviceAuthorization oidcDeviceAuthorization) (*oidcToken, error) {
+	interval := oidcDefaultPollingInterval
+	if deviceAuthorization.Interval > 0 {
+		interval = time.Duration(deviceAuthorization.Interval) * time.Second
+	}
+	var deadline time.Time
+	if deviceAuthorization.ExpiresIn > 0 {
+		deadline = c.now().Add(time.Duration(deviceAuthorization.ExpiresIn) * time.Second)
+	}
+	for {
+		if !deadline.IsZero() && c.now().After(deadline) {
+			return nil, errors.New("OIDC device authorization expired before the user completed it, please try again")
+		}
+		c.sleep(interval)
+		tokenResponse, err := c.requestToken(tokenEndpoint, url.Values{
+			"grant_type":  {oidcDeviceCodeGrantType},
+			"device_code": {deviceAuthorization.DeviceCode},
+			"client_id":   {c.config.ClientID},
+		})
+		if err != nil {
+			return nil, err
+		}
+		switch tokenResponse.Error {
+		case "":
+			return c.newToken(tokenResponse, ""), nil
+		case oidcErrorAuthorizationPending:
+			continue
+		case oidcErrorSlowDown:
+			interval += oidcDefaultPollingInterval
+			continue
+		default:
+			return nil, fmt.Errorf("OIDC device authorization failed: %s %s", tokenResponse.Error, tokenResponse.ErrorDescription)
+		}
+	}
+}
+
+// getAccessToken returns the cached access token, refreshing it if it has expired. An error is returned if there is no
+// valid token cached, asking the user to log in
+func (c *oidcDeviceFlowClient) getAccessToken() (string, error) {
+	token, err := c.loadToken()
+	if err != nil {
+		return "", err
+	}
+	if token != nil && token.isValid(c.now()) {
+		return token.AccessToken, nil
+	}
+	if token != nil && token.RefreshToken != "" {
+		refreshedToken, err := c.refreshToken(token.RefreshToken)
+		if err == nil {
+			if err := c.saveToken(refreshedToken); err != nil {
+				return "", err
+			}
+			return refreshedToken.AccessToken, nil
+		}
+		log.Printf("[WARN] failed to refresh the OIDC access token cached in '%s': %s", c.tokenCacheFile, err)
+	}
+	return "", fmt.Errorf("there is no valid OIDC access token cached in '%s' to fetch the swagger file, please run 'terraform-provider-%s %s' to log in", c.tokenCacheFile, c.providerName, SpecLoginCommand)
+}
+
+func (c *oidcDeviceFlowClient) refreshToken(refreshToken string) (*oidcToken, error) {
+	metadata, err := c.discover()
+	if err != nil {
+		return nil, err
+	}
+	tokenResponse, err := c.requestToken(metadata.TokenEndpoint, url.Values{
+		"grant_type":    {oidcRefreshTokenGrantType},
+		"refresh_token": {refreshToken},
+		"client_id":     {c.config.ClientID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tokenResponse.Error != "" {
+		return nil, fmt.Errorf("%s %s", tokenResponse.Error, tokenResponse.ErrorDescription)
+	}
+	return c.newToken(tokenResponse, refreshToken), nil
+}
+
+// newToken builds the token to cache out of the given token response. The refresh token previously obtained is kept if the
+// identity provider does not rotate it
+func (c *oidcDeviceFlowClient) newToken(tokenResponse *oidcTokenResponse, previousRefreshToken string) *oidcToken {
+	token := &oidcToken{
+		AccessToken:  tokenResponse.AccessToken,
+		RefreshToken: tokenResponse.RefreshToken,
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = previousRefreshToken
+	}
+	if tokenResponse.ExpiresIn > 0 {
+		token.Expiry = c.now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second).UTC()
+	}
+	return token
+}
+
+func (c *oidcDeviceFlowClient) discover() (*oidcProviderMetadata, error) {
+	discoveryURL := c.config.Issuer + oidcDiscoveryPath
+	res, err := c.httpClient.Get(discoveryURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the OIDC discovery document from '%s': %s", discoveryURL, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to retrieve the OIDC discovery document from '%s': unexpected status code %d", discoveryURL, res.StatusCode)
+	}
+	metadata := &oidcProviderMetadata{}
+	if err := json.NewDecoder(res.Body).Decode(metadata); err != nil {
+		return nil, fmt.Errorf("failed to read the OIDC discovery document from '%s': %s", discoveryURL, err)
+	}
+	if metadata.TokenEndpoint == "" {
+		return nil, fmt.Errorf("OIDC discovery document from '%s' is missing the token_endpoint", discoveryURL)
+	}
+	return metadata, nil
+}
+
+// requestToken POSTs the given form to the token endpoint. The error responses (e.g. authorization_pending) are returned
+// as part of the token response
+func (c *oidcDeviceFlowClient) requestToken(tokenEndpoint string, form url.Values) (*oidcTokenResponse, error) {
+	tokenResponse := &oidcTokenResponse{}
+	if err := c.postForm(tokenEndpoint, form, tokenResponse); err != nil {
+		return nil, fmt.Errorf("OIDC token request failed: %s", err)
+	}
+	if tokenResponse.Error == "" && tokenResponse.AccessToken == "" {
+		return nil, errors.New("OIDC token response is missing the access_token")
+	}
+	return tokenResponse, nil
+}
+
+func (c *oidcDeviceFlowClient) postForm(endpoint string, form url.Values, responsePayload interface{}) error {
+	res, err := c.httpClient.PostForm(endpoint, form)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	// errors are responded with 400 (or 401 for client authentication errors) along with a JSON payload describing them
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusBadRequest && res.StatusCode != http.StatusUnauthorized {
+		return fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	if err := json.Unmarshal(body, responsePayload); err != nil {
+		return fmt.Errorf("failed to read the response (status code %d): %s", res.StatusCode, err)
+	}
+	return nil
+}
+
+// loadToken reads the token cached in disk; nil is returned if there is no token cached yet
+func (c *oidcDeviceFlowClient) loadToken() (*oidcToken, error) {
+	data, err := ioutil.ReadFile(c.tokenCacheFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the OIDC token cache file '%s': %s", c.tokenCacheFile, err)
+	}
+	token := &oidcToken{}
+	if err := json.Unmarshal(data, token); err != nil {
+		log.Printf("[WARN] ignoring the OIDC token cache file '%s' since it is not valid: %s", c.tokenCacheFile, err)
+		return nil, nil
+	}
+	return token, nil
+}
+
+// saveToken caches the token in disk, making sure only the user running the provider can read it
+func (c *oidcDeviceFlowClient) saveToken(token *oidcToken) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.tokenCacheFile), 0700); err != nil {
+		return fmt.Errorf("failed to create the directory of the OIDC token cache file '%s': %s", c.tokenCacheFile, err)
+	}
+	if err := ioutil.WriteFile(c.tokenCacheFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write the OIDC token cache file '%s': %s", c.tokenCacheFile, err)
+	}
+	return nil
+}
+
+// getSwaggerURLAccessToken returns the access token to send when fetching the OpenAPI document located at swaggerURL if
+// the service configuration has OIDC configured. The token is only sent to the host of the swagger URL configured in the
+// service configuration, so it does not leak to other hosts (e.g. swagger URL overrides). Empty if no token is needed.
+func getSwaggerURLAccessToken(providerName string, serviceConfiguration ServiceConfiguration, swaggerURL string) (string, error) {
+	if serviceConfiguration == nil {
+		return "", nil
+	}
+	oidcConfiguration := getServiceConfigurationOptions(serviceConfiguration).GetSwaggerURLOIDC()
+	if oidcConfiguration == nil {
+		return "", nil
+	}
+	if !isSameHTTPHost(swaggerURL, serviceConfiguration.GetSwaggerURL()) {
+		log.Printf("[DEBUG] not sending the OIDC access token when fetching '%s' since it is not hosted in the swagger URL host", swaggerURL)
+		return "", nil
+	}
+	oidcClient, err := newOIDCDeviceFlowClient(providerName, *oidcConfiguration)
+	if err != nil {
+		return "", &SpecFetchError{URL: swaggerURL, Err: err}
+	}
+	accessToken, err := oidcClient.getAccessToken()
+	if err != nil {
+		return "", &SpecFetchError{URL: swaggerURL, Err: err}
+	}
+	return accessToken, nil
+}
+
+// isSameHTTPHost returns true if both URLs are http(s) URLs pointing at the same host
+func isSameHTTPHost(url1, url2 string) bool {
+	parsedURL1, err := url.Parse(url1)
+	if err != nil || (parsedURL1.Scheme != "http" && parsedURL1.Scheme != "https") {
+		return false
+	}
+	parsedURL2, err := url.Parse(url2)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(parsedURL1.Host, parsedURL2.Host)
+}
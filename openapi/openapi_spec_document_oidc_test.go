@@ -0,0 +1,274 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOIDCProvider serves the discovery, device authorization and token endpoints of an identity provider
+type fakeOIDCProvider struct {
+	server               *httptest.Server
+	pendingPolls         int
+	slowDownPolls        int
+	refreshTokenAccepted bool
+	tokenRequests        []map[string]string
+}
+
+func newFakeOIDCProvider() *fakeOIDCProvider {
+	p := &fakeOIDCProvider{refreshTokenAccepted: true}
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcDiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"device_authorization_endpoint": p.server.URL + "/device",
+			"token_endpoint":                p.server.URL + "/token",
+		})
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("client_id") != "terraform" || r.Form.Get("scope") != "openid offline_access" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"device_code":      "deviceCode",
+			"user_code":        "ABCD-EFGH",
+			"verification_uri": "https://login.company.com/activate",
+			"expires_in":       600,
+			"interval":         1,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		p.tokenRequests = append(p.tokenRequests, map[string]string{
+			"grant_type":    r.Form.Get("grant_type"),
+			"device_code":   r.Form.Get("device_code"),
+			"refresh_token": r.Form.Get("refresh_token"),
+		})
+		switch {
+		case r.Form.Get("grant_type") == oidcRefreshTokenGrantType && !p.refreshTokenAccepted:
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		case r.Form.Get("grant_type") == oidcRefreshTokenGrantType:
+			json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "refreshedAccessToken", "expires_in": 3600})
+		case p.slowDownPolls > 0:
+			p.slowDownPolls--
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": oidcErrorSlowDown})
+		case p.pendingPolls > 0:
+			p.pendingPolls--
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": oidcErrorAuthorizationPending})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "accessToken", "refresh_token": "refreshToken", "expires_in": 3600})
+		}
+	})
+	p.server = httptest.NewServer(mux)
+	return p
+}
+
+func newTestOIDCDeviceFlowClient(t *testing.T, issuer string) *oidcDeviceFlowClient {
+	tokenCacheDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	client, err := newOIDCDeviceFlowClient("openapi", ServiceOIDC{
+		Issuer:         issuer,
+		ClientID:       "terraform",
+		Scopes:         []string{"openid", "offline_access"},
+		TokenCacheFile: filepath.Join(tokenCacheDir, "cache", "token.json"),
+	})
+	require.NoError(t, err)
+	client.sleep = func(time.Duration) {}
+	return client
+}
+
+func TestOIDCDeviceFlowClientLogin(t *testing.T) {
+	oidcProvider := newFakeOIDCProvider()
+	defer oidcProvider.server.Close()
+	oidcProvider.pendingPolls = 1
+	oidcProvider.slowDownPolls = 1
+
+	client := newTestOIDCDeviceFlowClient(t, oidcProvider.server.URL)
+	defer os.RemoveAll(filepath.Dir(filepath.Dir(client.tokenCacheFile)))
+	var sleeps []time.Duration
+	client.sleep = func(d time.Duration) {
+		sleeps = append(sleeps, d)
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, client.login(out))
+	assert.Contains(t, out.String(), "https://login.company.com/activate")
+	assert.Contains(t, out.String(), "ABCD-EFGH")
+	assert.Equal(t, []time.Duration{time.Second, time.Second + oidcDefaultPollingInterval, time.Second + oidcDefaultPollingInterval}, sleeps)
+	require.Len(t, oidcProvider.tokenRequests, 3)
+	assert.Equal(t, oidcDeviceCodeGrantType, oidcProvider.tokenRequests[0]["grant_type"])
+	assert.Equal(t, "deviceCode", oidcProvider.tokenRequests[0]["device_code"])
+
+	info, err := os.Stat(client.tokenCacheFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	token, err := client.loadToken()
+	require.NoError(t, err)
+	assert.Equal(t, "accessToken", token.AccessToken)
+	assert.Equal(t, "refreshToken", token.RefreshToken)
+
+	accessToken, err := client.getAccessToken()
+	require.NoError(t, err)
+	assert.Equal(t, "accessToken", accessToken)
+	assert.Len(t, oidcProvider.tokenRequests, 3, "the cached token should be used without calling the identity provider")
+}
+
+func TestOIDCDeviceFlowClientLoginAccessDenied(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case oidcDiscoveryPath:
+			fmt.Fprintf(w, `{"device_authorization_endpoint":"http://%s/device","token_endpoint":"http://%s/token"}`, r.Host, r.Host)
+		case "/device":
+			fmt.Fprint(w, `{"device_code":"deviceCode","user_code":"ABCD-EFGH","verification_uri":"https://login.company.com/activate"}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"access_denied","error_description":"the user denied the request"}`)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestOIDCDeviceFlowClient(t, server.URL)
+	defer os.RemoveAll(filepath.Dir(filepath.Dir(client.tokenCacheFile)))
+
+	err := client.login(&bytes.Buffer{})
+	assert.EqualError(t, err, "OIDC device authorization failed: access_denied the user denied the request")
+	_, err = os.Stat(client.tokenCacheFile)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestOIDCDeviceFlowClientGetAccessToken(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		cachedToken          *oidcToken
+		refreshTokenAccepted bool
+		expectedAccessToken  string
+		expectedError        string
+	}{
+		{
+			name:                "valid token cached",
+			cachedToken:         &oidcToken{AccessToken: "accessToken", Expiry: time.Now().Add(time.Hour)},
+			expectedAccessToken: "accessToken",
+		},
+		{
+			name:                "token without expiry cached",
+			cachedToken:         &oidcToken{AccessToken: "accessToken"},
+			expectedAccessToken: "accessToken",
+		},
+		{
+			name:                 "expired token with refresh token cached",
+			cachedToken:          &oidcToken{AccessToken: "accessToken", RefreshToken: "refreshToken", Expiry: time.Now().Add(-time.Hour)},
+			refreshTokenAccepted: true,
+			expectedAccessToken:  "refreshedAccessToken",
+		},
+		{
+			name:          "expired token without refresh token cached",
+			cachedToken:   &oidcToken{AccessToken: "accessToken", Expiry: time.Now().Add(-time.Hour)},
+			expectedError: "there is no valid OIDC access token cached in '%s' to fetch the swagger file, please run 'terraform-provider-openapi spec-login' to log in",
+		},
+		{
+			name:                 "expired token with a refresh token that is no longer valid cached",
+			cachedToken:          &oidcToken{AccessToken: "accessToken", RefreshToken: "refreshToken", Expiry: time.Now().Add(-time.Hour)},
+			refreshTokenAccepted: false,
+			expectedError:        "there is no valid OIDC access token cached in '%s' to fetch the swagger file, please run 'terraform-provider-openapi spec-login' to log in",
+		},
+		{
+			name:          "no token cached",
+			expectedError: "there is no valid OIDC access token cached in '%s' to fetch the swagger file, please run 'terraform-provider-openapi spec-login' to log in",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			oidcProvider := newFakeOIDCProvider()
+			defer oidcProvider.server.Close()
+			oidcProvider.refreshTokenAccepted = tc.refreshTokenAccepted
+
+			client := newTestOIDCDeviceFlowClient(t, oidcProvider.server.URL)
+			defer os.RemoveAll(filepath.Dir(filepath.Dir(client.tokenCacheFile)))
+			if tc.cachedToken != nil {
+				require.NoError(t, client.saveToken(tc.cachedToken))
+			}
+
+			accessToken, err := client.getAccessToken()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, fmt.Sprintf(tc.expectedError, client.tokenCacheFile))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedAccessToken, accessToken)
+			token, err := client.loadToken()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedAccessToken, token.AccessToken)
+			if tc.refreshTokenAccepted {
+				assert.Equal(t, "refreshToken", token.RefreshToken, "the refresh token should be kept if the identity provider does not rotate it")
+			}
+		})
+	}
+}
+
+func TestGetSwaggerURLAccessToken(t *testing.T) {
+	tokenCacheFile, err := ioutil.TempFile("", "")
+	require.NoError(t, err)
+	defer os.Remove(tokenCacheFile.Name())
+	_, err = tokenCacheFile.WriteString(`{"access_token":"accessToken"}`)
+	require.NoError(t, err)
+	tokenCacheFile.Close()
+
+	oidc := &ServiceOIDC{Issuer: "https://login.company.com", ClientID: "terraform", TokenCacheFile: tokenCacheFile.Name()}
+	testCases := []struct {
+		name                string
+		oidc                *ServiceOIDC
+		swaggerURL          string
+		expectedAccessToken string
+	}{
+		{name: "no OIDC configured", swaggerURL: "https://portal.company.com/swagger.json", expectedAccessToken: ""},
+		{name: "swagger URL hosted in the configured host", oidc: oidc, swaggerURL: "https://portal.company.com/swagger.json", expectedAccessToken: "accessToken"},
+		{name: "swagger URL hosted in another host", oidc: oidc, swaggerURL: "https://other.company.com/swagger.json", expectedAccessToken: ""},
+		{name: "swagger file stored in the disk", oidc: oidc, swaggerURL: "/tmp/swagger.json", expectedAccessToken: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			serviceConfiguration := &ServiceConfigStub{SwaggerURL: "https://portal.company.com/swagger.json", SwaggerURLOIDC: tc.oidc}
+			accessToken, err := getSwaggerURLAccessToken("openapi", serviceConfiguration, tc.swaggerURL)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedAccessToken, accessToken)
+		})
+	}
+}
+
+func TestCreateAuthenticatedSpecAnalyserForDocument(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(authorizationHeader) != "Bearer accessToken" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"swagger":"2.0","host":"api.company.com","paths":{}}`)
+	}))
+	defer server.Close()
+
+	specAnalyser, err := createAuthenticatedSpecAnalyserForDocument(server.URL+"/swagger.json", "accessToken")
+	require.NoError(t, err)
+	backendConfiguration, err := specAnalyser.GetAPIBackendConfiguration()
+	require.NoError(t, err)
+	host, err := backendConfiguration.getHost()
+	require.NoError(t, err)
+	assert.Equal(t, "api.company.com", host)
+
+	_, err = createAuthenticatedSpecAnalyserForDocument(server.URL+"/swagger.json", "expiredAccessToken")
+	assert.IsType(t, &SpecFetchError{}, err)
+	assert.Contains(t, err.Error(), "the OIDC access token was rejected (status code 401), please run the 'spec-login' command to log in again")
+}
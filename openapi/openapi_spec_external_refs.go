@@ -0,0 +1,381 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// swaggerDefinitionsPath is the location of the models in OpenAPI v2 documents
+var swaggerDefinitionsPath = []string{"definitions"}
+
+// openAPIv3SchemasPath is the location of the models in OpenAPI v3 documents
+var openAPIv3SchemasPath = []string{"components", "schemas"}
+
+// externalRefsInlinedSections contains the JSON pointer prefixes of the sections of a document that do not hold models
+// (e.g. parameters or responses). The references to these sections in external documents are replaced with the referenced
+// value itself, whereas the rest of references are considered models and imported into the document definitions
+var externalRefsInlinedSections = []string{"/paths/", "/parameters/", "/responses/", "/securityDefinitions/", "/components/"}
+
+// externalRefsResolver makes an OpenAPI document self-contained by resolving the references ($ref) to other documents,
+// either files relative to the referencing document (e.g. models/pet.json#/Pet) or remote URLs. The referenced models are
+// imported into the definitions of the document (so circular references between models are supported) and the references
+// are rewritten to point to the imported definitions. The references found in the imported models are resolved the same
+// way, relative to the document the model lives in.
+type externalRefsResolver struct {
+	documentURL     string
+	definitionsPath []string
+	// definitions contains the models already defined in the document, which the imported model names must not clash with
+	definitions map[string]interface{}
+	// documents contains the external documents retrieved so far keyed by URL, so each document is only retrieved once
+	documents map[string]interface{}
+	// models contains the local references of the imported models keyed by the absolute reference to the external model
+	models map[string]string
+	// imported contains the imported models keyed by definition name
+	imported map[string]interface{}
+	// inlining contains the references being inlined at the moment, used to detect circular references that can not be
+	// inlined
+	inlining map[string]bool
+	// resolved is the number of external references resolved
+	resolved int
+}
+
+// resolveExternalRefs returns a copy of the given OpenAPI document where the references to other documents have been
+// resolved as described in externalRefsResolver. The definitionsPath is the location of the models in the document
+// (swaggerDefinitionsPath or openAPIv3SchemasPath). The boolean returned is false (and the document returned is the one
+// given) if the document does not contain external references.
+func resolveExternalRefs(documentURL string, document map[string]interface{}, definitionsPath []string) (map[string]interface{}, bool, error) {
+	r := &externalRefsResolver{
+		documentURL:     normalizeDocumentURL(documentURL),
+		definitionsPath: definitionsPath,
+		definitions:     getNestedMap(document, definitionsPath),
+		documents:       map[string]interface{}{},
+		models:          map[string]string{},
+		imported:        map[string]interface{}{},
+		inlining:        map[string]bool{},
+	}
+	resolvedDocument, err := r.resolveValue(document, r.documentURL)
+	if err != nil {
+		return nil, false, err
+	}
+	if r.resolved == 0 {
+		return document, false, nil
+	}
+	resolved := resolvedDocument.(map[string]interface{})
+	definitions := resolved
+	for _, key := range definitionsPath {
+		next, ok := definitions[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			definitions[key] = next
+		}
+		definitions = next
+	}
+	for name, model := range r.imported {
+		definitions[name] = model
+	}
+	log.Printf("[INFO] resolved %d external references of the OpenAPI document '%s' (%d models imported)", r.resolved, documentURL, len(r.imported))
+	return resolved, true, nil
+}
+
+// resolveValue returns a copy of the given value where the external references have been resolved. The baseURL is the URL
+// of the document the value lives in, which the relative references are resolved against
+func (r *externalRefsResolver) resolveValue(value interface{}, baseURL string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if ref, ok := item.(string); ok && key == "$ref" {
+				localRef, inlinedValue, err := r.resolveRef(ref, baseURL)
+				if err != nil {
+					return nil, err
+				}
+				if inlinedValue != nil {
+					return inlinedValue, nil
+				}
+				resolved[key] = localRef
+				continue
+			}
+			resolvedItem, err := r.resolveValue(item, baseURL)
+			if err != nil {
+				return nil, err
+			}
+			resolved[key] = resolvedItem
+		}
+		return resolved, nil
+	case []interface{}:
+		resolved := make([]interface{}, len(v))
+		for idx, item := range v {
+			resolvedItem, err := r.resolveValue(item, baseURL)
+			if err != nil {
+				return nil, err
+			}
+			resolved[idx] = resolvedItem
+		}
+		return resolved, nil
+	}
+	return value, nil
+}
+
+// resolveRef returns the local reference the given reference must be replaced with: the reference itself if it points
+// to the document being resolved or a reference to the imported definition if it points to an external model. If the
+// reference points to any other section of an external document, the referenced value is returned instead so the
+// object holding the reference is replaced with it
+func (r *externalRefsResolver) resolveRef(ref, baseURL string) (string, interface{}, error) {
+	refDocument, fragment := splitRef(ref)
+	documentURL := baseURL
+	if refDocument != "" {
+		var err error
+		documentURL, err = resolveDocumentURL(baseURL, refDocument)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to resolve the reference '%s': %s", ref, err)
+		}
+	}
+	if documentURL == r.documentURL {
+		return "#" + fragment, nil, nil
+	}
+	absoluteRef := documentURL + "#" + fragment
+	r.resolved++
+	if isInlinedSection(fragment) {
+		value, err := r.inlineRef(absoluteRef, documentURL, fragment)
+		return "", value, err
+	}
+	localRef, err := r.importModel(absoluteRef, documentURL, fragment)
+	return localRef, nil, err
+}
+
+// importModel imports the model the given absolute reference points to into the definitions (only the first time the
+// model is referenced), returning the local reference to the imported definition
+func (r *externalRefsResolver) importModel(absoluteRef, documentURL, fragment string) (string, error) {
+	if localRef, ok := r.models[absoluteRef]; ok {
+		return localRef, nil
+	}
+	model, err := r.getReferencedValue(documentURL, fragment)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the reference '%s': %s", absoluteRef, err)
+	}
+	name := r.getDefinitionName(documentURL, fragment)
+	localRef := "#/" + strings.Join(append(append([]string{}, r.definitionsPath...), escapeJSONPointerToken(name)), "/")
+	// the model is registered before resolving its own references so circular references point to the same definition
+	r.models[absoluteRef] = localRef
+	r.imported[name] = nil
+	log.Printf("[DEBUG] importing the external model '%s' as definition '%s'", absoluteRef, name)
+	resolvedModel, err := r.resolveValue(model, documentURL)
+	if err != nil {
+		return "", err
+	}
+	r.imported[name] = resolvedModel
+	return localRef, nil
+}
+
+// inlineRef returns the value the given absolute reference points to, with its own references resolved
+func (r *externalRefsResolver) inlineRef(absoluteRef, documentURL, fragment string) (interface{}, error) {
+	if r.inlining[absoluteRef] {
+		return nil, fmt.Errorf("circular reference found: '%s' refers to itself", absoluteRef)
+	}
+	value, err := r.getReferencedValue(documentURL, fragment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the reference '%s': %s", absoluteRef, err)
+	}
+	r.inlining[absoluteRef] = true
+	defer delete(r.inlining, absoluteRef)
+	return r.resolveValue(value, documentURL)
+}
+
+// getReferencedValue returns the value located at the given JSON pointer (fragment) of the external document
+func (r *externalRefsResolver) getReferencedValue(documentURL, fragment string) (interface{}, error) {
+	document, ok := r.documents[documentURL]
+	if !ok {
+		var err error
+		document, err = loadExternalDocument(documentURL)
+		if err != nil {
+			return nil, err
+		}
+		r.documents[documentURL] = document
+	}
+	value := document
+	for _, token := range strings.Split(strings.TrimPrefix(fragment, "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = unescapeJSONPointerToken(token)
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if value, ok = v[token]; !ok {
+				return nil, fmt.Errorf("'%s' not found in '%s'", fragment, documentURL)
+			}
+		case []interface{}:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return nil, fmt.Errorf("'%s' not found in '%s'", fragment, documentURL)
+			}
+			value = v[idx]
+		default:
+			return nil, fmt.Errorf("'%s' not found in '%s'", fragment, documentURL)
+		}
+	}
+	return value, nil
+}
+
+// getDefinitionName returns the name of the definition the external model is imported as: the last token of the JSON
+// pointer (e.g. Pet for models.json#/definitions/Pet) or the file name when the whole document is the model (e.g. Pet for
+// models/Pet.yaml). A numeric suffix is appended if the name is already taken by another model
+func (r *externalRefsResolver) getDefinitionName(documentURL, fragment string) string {
+	name := ""
+	if tokens := strings.Split(fragment, "/"); len(tokens) > 0 {
+		name = unescapeJSONPointerToken(tokens[len(tokens)-1])
+	}
+	if name == "" {
+		name = path.Base(filepath.ToSlash(documentURL))
+		name = strings.TrimSuffix(name, path.Ext(name))
+	}
+	candidate := name
+	for i := 2; r.isDefinitionNameTaken(candidate); i++ {
+		candidate = fmt.Sprintf("%s_%d", name, i)
+	}
+	return candidate
+}
+
+func (r *externalRefsResolver) isDefinitionNameTaken(name string) bool {
+	_, defined := r.definitions[name]
+	_, imported := r.imported[name]
+	return defined || imported
+}
+
+// loadExternalDocument retrieves and parses (either JSON or YAML) the document located at the given URL or file path.
+// Remote documents are retrieved with the default HTTP client, so the insecure skip verify setting of the provider is
+// honoured
+func loadExternalDocument(documentURL string) (interface{}, error) {
+	var data []byte
+	var err error
+	if isHTTPURL(documentURL) {
+		data, err = fetchExternalDocument(documentURL)
+	} else {
+		data, err = ioutil.ReadFile(documentURL)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the document '%s': %s", documentURL, err)
+	}
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err == nil {
+		return document, nil
+	}
+	var yamlDocument interface{}
+	if err := yaml.Unmarshal(data, &yamlDocument); err != nil {
+		return nil, fmt.Errorf("failed to parse the document '%s': %s", documentURL, err)
+	}
+	return convertYAMLValue(yamlDocument), nil
+}
+
+func fetchExternalDocument(documentURL string) ([]byte, error) {
+	res, err := http.DefaultClient.Get(documentURL)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return ioutil.ReadAll(res.Body)
+}
+
+// convertYAMLValue converts the maps with interface{} keys produced when unmarshalling YAML documents into maps with
+// string keys, as produced when unmarshalling JSON documents
+func convertYAMLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprintf("%v", key)] = convertYAMLValue(item)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for idx, item := range v {
+			converted[idx] = convertYAMLValue(item)
+		}
+		return converted
+	}
+	return value
+}
+
+// resolveDocumentURL returns the URL (or file path) of the document the reference points to, resolving relative
+// references against the URL of the referencing document
+func resolveDocumentURL(baseURL, refDocument string) (string, error) {
+	if isHTTPURL(refDocument) {
+		return refDocument, nil
+	}
+	if isHTTPURL(baseURL) {
+		base, err := url.Parse(baseURL)
+		if err != nil {
+			return "", err
+		}
+		ref, err := url.Parse(refDocument)
+		if err != nil {
+			return "", err
+		}
+		return base.ResolveReference(ref).String(), nil
+	}
+	refPath := normalizeDocumentURL(refDocument)
+	if filepath.IsAbs(refPath) {
+		return refPath, nil
+	}
+	return filepath.Join(filepath.Dir(normalizeDocumentURL(baseURL)), refPath), nil
+}
+
+// normalizeDocumentURL returns the given URL as is if it is an HTTP URL; otherwise, the URL is considered a file path
+// (optionally prefixed with 'file://') and the cleaned path is returned
+func normalizeDocumentURL(documentURL string) string {
+	if isHTTPURL(documentURL) {
+		return documentURL
+	}
+	return filepath.Clean(filepath.FromSlash(strings.TrimPrefix(documentURL, "file://")))
+}
+
+func isHTTPURL(documentURL string) bool {
+	lowerCaseURL := strings.ToLower(documentURL)
+	return strings.HasPrefix(lowerCaseURL, "http://") || strings.HasPrefix(lowerCaseURL, "https://")
+}
+
+// splitRef splits the given reference into the document and the JSON pointer (e.g. models.json#/definitions/Pet is split
+// into models.json and /definitions/Pet)
+func splitRef(ref string) (string, string) {
+	if idx := strings.Index(ref, "#"); idx >= 0 {
+		return ref[:idx], ref[idx+1:]
+	}
+	return ref, ""
+}
+
+func isInlinedSection(fragment string) bool {
+	if strings.HasPrefix(fragment, "/components/schemas/") {
+		return false
+	}
+	for _, section := range externalRefsInlinedSections {
+		if strings.HasPrefix(fragment, section) {
+			return true
+		}
+	}
+	return false
+}
+
+func getNestedMap(document map[string]interface{}, keys []string) map[string]interface{} {
+	nested := document
+	for _, key := range keys {
+		nested, _ = nested[key].(map[string]interface{})
+	}
+	return nested
+}
+
+// unescapeJSONPointerToken reverts escapeJSONPointerToken
+func unescapeJSONPointerToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+}
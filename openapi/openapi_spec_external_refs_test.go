@@ -0,0 +1,229 @@
+package openapi
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const externalRefsRootDocument = `{
+  "swagger": "2.0",
+  "paths": {
+    "/v1/cdns": {
+      "post": {
+        "parameters": [{"in": "body", "name": "body", "schema": {"$ref": "models/cdn.json#/definitions/ContentDeliveryNetworkV1"}}],
+        "responses": {"201": {"description": "created", "schema": {"$ref": "models/cdn.json#/definitions/ContentDeliveryNetworkV1"}}}
+      }
+    },
+    "/v1/cdns/{id}": {
+      "get": {
+        "parameters": [{"$ref": "parameters.yaml#/parameters/id"}],
+        "responses": {"200": {"description": "found", "schema": {"$ref": "#/definitions/ContentDeliveryNetworkV1"}}}
+      }
+    }
+  },
+  "definitions": {
+    "ContentDeliveryNetworkV1": {"type": "object", "properties": {"id": {"type": "string"}}}
+  }
+}`
+
+func writeExternalRefsDocuments(t *testing.T, documents map[string]string) string {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	for name, content := range documents {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+		require.NoError(t, ioutil.WriteFile(filePath, []byte(content), 0644))
+	}
+	return dir
+}
+
+func newExternalRefsDocument(t *testing.T, content string) map[string]interface{} {
+	document := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal([]byte(content), &document))
+	return document
+}
+
+func TestResolveExternalRefs(t *testing.T) {
+	dir := writeExternalRefsDocuments(t, map[string]string{
+		"models/cdn.json": `{"definitions": {"ContentDeliveryNetworkV1": {"type": "object", "properties": {"label": {"type": "string"}, "origin": {"$ref": "origin.yaml", "description": "origin of the cdn"}}}}}`,
+		"models/origin.yaml": `type: object
+properties:
+  hostname:
+    type: string
+  cdn:
+    $ref: "cdn.json#/definitions/ContentDeliveryNetworkV1"`,
+		"parameters.yaml": `parameters:
+  id:
+    in: path
+    name: id
+    required: true
+    type: string`,
+	})
+	defer os.RemoveAll(dir)
+	document := newExternalRefsDocument(t, externalRefsRootDocument)
+
+	resolvedDocument, resolved, err := resolveExternalRefs(filepath.Join(dir, "swagger.json"), document, swaggerDefinitionsPath)
+	require.NoError(t, err)
+	assert.True(t, resolved)
+
+	definitions := resolvedDocument["definitions"].(map[string]interface{})
+	assert.Len(t, definitions, 3)
+	assert.Contains(t, definitions, "ContentDeliveryNetworkV1", "the model already defined in the document should be kept")
+	// the external model is named as the one already defined in the document so a suffix is appended
+	cdn := definitions["ContentDeliveryNetworkV1_2"].(map[string]interface{})
+	origin := cdn["properties"].(map[string]interface{})["origin"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"$ref": "#/definitions/origin", "description": "origin of the cdn"}, origin)
+	originModel := definitions["origin"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"$ref": "#/definitions/ContentDeliveryNetworkV1_2"}, originModel["properties"].(map[string]interface{})["cdn"], "the circular reference should point to the imported model")
+
+	paths := resolvedDocument["paths"].(map[string]interface{})
+	post := paths["/v1/cdns"].(map[string]interface{})["post"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"$ref": "#/definitions/ContentDeliveryNetworkV1_2"}, post["parameters"].([]interface{})[0].(map[string]interface{})["schema"])
+	get := paths["/v1/cdns/{id}"].(map[string]interface{})["get"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"in": "path", "name": "id", "required": true, "type": "string"}, get["parameters"].([]interface{})[0], "the external parameter should be inlined")
+	assert.Equal(t, map[string]interface{}{"$ref": "#/definitions/ContentDeliveryNetworkV1"}, get["responses"].(map[string]interface{})["200"].(map[string]interface{})["schema"], "the local references should be kept")
+}
+
+func TestResolveExternalRefs_NoExternalRefs(t *testing.T) {
+	document := newExternalRefsDocument(t, `{"swagger": "2.0", "definitions": {"Pet": {"type": "object", "properties": {"owner": {"$ref": "#/definitions/Owner"}}}}}`)
+	resolvedDocument, resolved, err := resolveExternalRefs("swagger.json", document, swaggerDefinitionsPath)
+	require.NoError(t, err)
+	assert.False(t, resolved)
+	assert.Equal(t, document, resolvedDocument)
+}
+
+func TestResolveExternalRefs_Remote(t *testing.T) {
+	documents := map[string]string{
+		"/models/cdn.json": `{"type": "object", "properties": {"label": {"type": "string"}, "tags": {"type": "array", "items": {"$ref": "../shared/tag.json#/Tag"}}}}`,
+		"/shared/tag.json": `{"Tag": {"type": "object", "properties": {"key": {"type": "string"}, "parent": {"$ref": "#/Tag"}}}}`,
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := documents[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(content))
+	}))
+	defer server.Close()
+	// the documents are retrieved with the default transport, which holds the insecure skip verify setting of the provider
+	transport := http.DefaultTransport.(*http.Transport)
+	tlsClientConfig := transport.TLSClientConfig
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	defer func() { transport.TLSClientConfig = tlsClientConfig }()
+
+	document := newExternalRefsDocument(t, `{"openapi": "3.0.1", "components": {"schemas": {"ContentDeliveryNetworkV1": {"$ref": "models/cdn.json"}}}}`)
+	resolvedDocument, resolved, err := resolveExternalRefs(server.URL+"/openapi.json", document, openAPIv3SchemasPath)
+	require.NoError(t, err)
+	assert.True(t, resolved)
+
+	schemas := resolvedDocument["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"$ref": "#/components/schemas/cdn"}, schemas["ContentDeliveryNetworkV1"])
+	cdn := schemas["cdn"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"$ref": "#/components/schemas/Tag"}, cdn["properties"].(map[string]interface{})["tags"].(map[string]interface{})["items"])
+	tag := schemas["Tag"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"$ref": "#/components/schemas/Tag"}, tag["properties"].(map[string]interface{})["parent"])
+}
+
+func TestResolveExternalRefs_Errors(t *testing.T) {
+	dir := writeExternalRefsDocuments(t, map[string]string{
+		"models.json":     `{"definitions": {"Pet": {"type": "object"}}}`,
+		"parameters.json": `{"parameters": {"first": {"$ref": "#/parameters/second"}, "second": {"$ref": "#/parameters/first"}}}`,
+	})
+	defer os.RemoveAll(dir)
+	testCases := []struct {
+		name          string
+		ref           string
+		expectedError string
+	}{
+		{
+			name:          "document does not exist",
+			ref:           "other.json#/definitions/Pet",
+			expectedError: "failed to resolve the reference '" + filepath.Join(dir, "other.json") + "#/definitions/Pet': failed to retrieve the document '" + filepath.Join(dir, "other.json") + "'",
+		},
+		{
+			name:          "model does not exist",
+			ref:           "models.json#/definitions/Owner",
+			expectedError: "failed to resolve the reference '" + filepath.Join(dir, "models.json") + "#/definitions/Owner': '/definitions/Owner' not found in '" + filepath.Join(dir, "models.json") + "'",
+		},
+		{
+			name:          "circular reference between inlined values",
+			ref:           "parameters.json#/parameters/first",
+			expectedError: "circular reference found: '" + filepath.Join(dir, "parameters.json") + "#/parameters/first' refers to itself",
+		},
+	}
+	for _, tc := range testCases {
+		document := map[string]interface{}{"swagger": "2.0", "definitions": map[string]interface{}{"Pet": map[string]interface{}{"$ref": tc.ref}}}
+		_, _, err := resolveExternalRefs(filepath.Join(dir, "swagger.json"), document, swaggerDefinitionsPath)
+		require.Error(t, err, tc.name)
+		assert.Contains(t, err.Error(), tc.expectedError, tc.name)
+	}
+}
+
+func TestResolveDocumentURL(t *testing.T) {
+	testCases := []struct {
+		name        string
+		baseURL     string
+		refDocument string
+		expected    string
+	}{
+		{name: "relative file", baseURL: filepath.FromSlash("/specs/swagger.yaml"), refDocument: "models/pet.yaml", expected: filepath.FromSlash("/specs/models/pet.yaml")},
+		{name: "relative file in parent directory", baseURL: filepath.FromSlash("/specs/models/pet.yaml"), refDocument: "../shared/tag.yaml", expected: filepath.FromSlash("/specs/shared/tag.yaml")},
+		{name: "file url", baseURL: "file:///specs/swagger.yaml", refDocument: "pet.yaml", expected: filepath.FromSlash("/specs/pet.yaml")},
+		{name: "relative url", baseURL: "https://api.com/specs/swagger.json", refDocument: "../models/pet.json", expected: "https://api.com/models/pet.json"},
+		{name: "absolute url", baseURL: filepath.FromSlash("/specs/swagger.yaml"), refDocument: "https://api.com/models/pet.json", expected: "https://api.com/models/pet.json"},
+	}
+	for _, tc := range testCases {
+		documentURL, err := resolveDocumentURL(tc.baseURL, tc.refDocument)
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expected, documentURL, tc.name)
+	}
+}
+
+func TestNewSpecAnalyserV2_ExternalRefs(t *testing.T) {
+	dir := writeExternalRefsDocuments(t, map[string]string{
+		"swagger.json": `{
+  "swagger": "2.0",
+  "host": "localhost:8443",
+  "paths": {
+    "/v1/cdns": {
+      "post": {
+        "parameters": [{"in": "body", "name": "body", "schema": {"$ref": "models.json#/definitions/ContentDeliveryNetworkV1"}}],
+        "responses": {"201": {"description": "created", "schema": {"$ref": "models.json#/definitions/ContentDeliveryNetworkV1"}}}
+      }
+    },
+    "/v1/cdns/{id}": {
+      "get": {
+        "parameters": [{"in": "path", "name": "id", "required": true, "type": "string"}],
+        "responses": {"200": {"description": "found", "schema": {"$ref": "models.json#/definitions/ContentDeliveryNetworkV1"}}}
+      }
+    }
+  }
+}`,
+		"models.json": `{"definitions": {"ContentDeliveryNetworkV1": {"type": "object", "required": ["label"], "properties": {"id": {"type": "string", "readOnly": true}, "label": {"type": "string"}}}}}`,
+	})
+	defer os.RemoveAll(dir)
+
+	specAnalyser, err := newSpecAnalyserV2(filepath.Join(dir, "swagger.json"))
+	require.NoError(t, err)
+	resources, err := specAnalyser.GetTerraformCompliantResources()
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	resourceSchema, err := resources[0].getResourceSchema()
+	require.NoError(t, err)
+	label, err := resourceSchema.getProperty("label")
+	require.NoError(t, err)
+	assert.True(t, label.Required)
+	id, err := resourceSchema.getProperty("id")
+	require.NoError(t, err)
+	assert.True(t, id.ReadOnly)
+}
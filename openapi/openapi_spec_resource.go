@@ -8,11 +8,30 @@ import (
 type SpecResource interface {
 	getResourceName() string
 	getHost() (string, error)
+	// getScheme returns the scheme (http or https) used when calling the API for this specific resource, or an empty
+	// string if the global scheme applies
+	getScheme() (string, error)
 	getResourcePath(parentIDs []string) (string, error)
 	getResourceSchema() (*specSchemaDefinition, error)
 	shouldIgnoreResource() bool
+	// shouldIgnoreDataSourceInstance returns true if the data source instance for the resource should not be registered
+	// in the provider. The resource itself is not affected.
+	shouldIgnoreDataSourceInstance() bool
 	getResourceOperations() specResourceOperations
 	getTimeouts() (*specTimeouts, error)
+	// getConsoleURLTemplate returns the template used to build the URL of the resource instances in the vendor's console,
+	// or an empty string if the resource does not have one
+	getConsoleURLTemplate() string
+	// isImportOnly returns true if the resource instances can only be imported (e.g. pre-provisioned objects) and must
+	// not be created, updated or deleted via terraform
+	isImportOnly() bool
+	// isAutoImportEnabled returns true if the data source that lists the existing resource instances in a shape ready to
+	// be imported (<resource>_import) should be registered in the provider
+	isAutoImportEnabled() bool
+	// getCompositeID returns the names of the properties that together identify the resource instances (e.g. namespace
+	// and name for /v1/ns/{namespace}/things/{name}) in the order their path parameters show up in the instance path;
+	// nil if the resource is identified by a single id property
+	getCompositeID() []string
 	// getParentResourceInfo returns a struct populated with relevant parentResourceInfo if the resource is considered
 	// a subresource; nil otherwise.
 	getParentResourceInfo() *parentResourceInfo
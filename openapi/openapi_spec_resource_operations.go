@@ -1,16 +1,64 @@
 package openapi
 
 type specResourceOperations struct {
-	List   *specResourceOperation
-	Post   *specResourceOperation
-	Get    *specResourceOperation
+	List *specResourceOperation
+	Post *specResourceOperation
+	Get  *specResourceOperation
+	// Put is the operation used to update the resource instances, which is the instance PATCH operation if the resource
+	// has no PUT operation and the PATCH operation uses the json-patch update strategy
 	Put    *specResourceOperation
 	Delete *specResourceOperation
 }
 
+// updateStrategy defines how the changes are sent to the API when a resource instance is updated
+type updateStrategy string
+
+const (
+	// updateStrategyPut sends a PUT request containing the whole resource payload
+	updateStrategyPut updateStrategy = "put"
+	// updateStrategyJSONPatch sends a PATCH request containing the JSON Patch (RFC 6902) operations computed from the diff
+	updateStrategyJSONPatch updateStrategy = "json-patch"
+)
+
 // specResourceOperation defines a resource operation
 type specResourceOperation struct {
-	SecuritySchemes  SpecSecuritySchemes
+	// SecuritySchemes contains the security schemes declared in the operation, which override the global ones. If empty,
+	// the global security schemes are used unless AnonymousAccess is set
+	SecuritySchemes SpecSecuritySchemes
+	// AnonymousAccess defines whether the operation overrides the global security with no security at all (e.g. security: [])
+	// so it is called without authentication
+	AnonymousAccess  bool
 	HeaderParameters SpecHeaderParameters
-	responses        specResponses
+	// RequiredHeaderParameters contains the subset of HeaderParameters that are marked as required in the operation. The
+	// values for these headers can be overridden per resource instance
+	RequiredHeaderParameters SpecHeaderParameters
+	// OnFailureCleanup contains the operation called to clean up the resource if the operation fails after the API returned
+	// the resource id (e.g. the polling mechanism failed), so no partially created resources are left behind. If nil, no
+	// cleanup is performed
+	OnFailureCleanup *onFailureCleanupOperation
+	// BatchRead defines whether the collection GET operation can be used to refresh many resource instances at once,
+	// instead of sending one GET request per instance
+	BatchRead bool
+	// ConfirmDeleteViaList defines whether the deletion of the resource is confirmed by polling the collection GET operation
+	// until the resource instance is no longer listed
+	ConfirmDeleteViaList bool
+	// WaitForNotFound defines whether the deletion of the resource is confirmed by polling the instance GET operation until
+	// the API returns 404 Not Found or 410 Gone
+	WaitForNotFound bool
+	// EventualConsistency contains how long the reads made right after the create are retried while the API returns 404
+	// Not Found, if the POST operation is configured with the x-terraform-eventual-consistency extension
+	EventualConsistency *eventualConsistencyPolicy
+	// RefreshFieldsQueryParam contains the name of the query parameter (e.g. fields) the instance GET operation accepts to
+	// limit the properties returned by the API. If set, routine refreshes send the names of the properties that are not
+	// refreshed on demand so the API does not compute the expensive ones
+	RefreshFieldsQueryParam string
+	// UpdateMaskQueryParam contains the name of the query parameter (e.g. update_mask) the update operation expects with
+	// the list of fields changed, as required by Google style APIs
+	UpdateMaskQueryParam string
+	// UpdateStrategy defines how the changes are sent to the API when the operation is used to update resource instances
+	UpdateStrategy updateStrategy
+	// Retry defines how the operation calls failing with transient errors are retried, overriding the retry configuration
+	// of the service. If nil, the service retry configuration applies
+	Retry     *retryPolicy
+	responses specResponses
 }
@@ -1,11 +1,39 @@
 package openapi
 
+import "time"
+
 type specResponses map[int]*specResponse
 
 type specResponse struct {
 	isPollingEnabled    bool
 	pollTargetStatuses  []string
 	pollPendingStatuses []string
+	// pollFailedStatuses contains the statuses that make the polling mechanism fail straight away
+	pollFailedStatuses []string
+	// pollStatusPath is the path to the status value in the payload (e.g. operation.state), if empty the status property
+	// of the resource schema is used
+	pollStatusPath string
+	// pollInterval is the time to wait between the polling requests, if zero the default poll interval is used
+	pollInterval time.Duration
+	// pollStrategy defines how the time to wait between the polling requests evolves (see pollBackoff), if empty the poll
+	// interval is constant
+	pollStrategy pollStrategy
+	// pollMaxInterval caps the time to wait between the polling requests of the linear and exponential strategies, if
+	// zero the default max poll interval is used
+	pollMaxInterval time.Duration
+	// pollEventsPath is the path relative to the resource instance URL (e.g. events) that streams the progress events of
+	// the operation as NDJSON, if empty no events are consumed while polling
+	pollEventsPath string
+	// pollEventsProperty is the name of the computed property that stores the last event received once the polling completes
+	pollEventsProperty string
+	// pollLocationHeader is the name of the response header (e.g. Location or Operation-Location) containing the URL of the
+	// asynchronous operation to poll instead of the resource instance, if empty the resource instance is polled
+	pollLocationHeader string
+	// isSummary defines whether the response only contains a summary of the resource, in which case the resource needs
+	// to be read again to get all its properties
+	isSummary bool
+	// schema contains the schema documented for this specific response, nil if not documented
+	schema *specSchemaDefinition
 }
 
 func (s specResponses) getResponse(responseStatusCode int) *specResponse {
@@ -15,3 +43,23 @@ func (s specResponses) getResponse(responseStatusCode int) *specResponse {
 	}
 	return response
 }
+
+// isPollPendingStatus returns true if the given status is one of the statuses the resource is expected to go through
+// while it is being processed
+func (s *specResponse) isPollPendingStatus(status string) bool {
+	for _, pendingStatus := range s.pollPendingStatuses {
+		if pendingStatus == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *specResponse) isPollFailedStatus(status string) bool {
+	for _, failedStatus := range s.pollFailedStatuses {
+		if failedStatus == status {
+			return true
+		}
+	}
+	return false
+}
@@ -3,7 +3,7 @@ package openapi
 import (
 	"fmt"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 // specSchemaDefinitionProperties defines a collection of schema definition properties
@@ -15,7 +15,14 @@ type specSchemaDefinition struct {
 }
 
 func (s *specSchemaDefinition) createResourceSchema() (map[string]*schema.Schema, error) {
-	return s.createResourceSchemaIgnoreID(true)
+	terraformSchema, err := s.createResourceSchemaIgnoreID(true)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.addPropertyAliases(terraformSchema); err != nil {
+		return nil, err
+	}
+	return terraformSchema, nil
 }
 
 func (s *specSchemaDefinition) createDataSourceSchema() (map[string]*schema.Schema, error) {
@@ -32,9 +39,57 @@ func (s *specSchemaDefinition) createDataSourceSchema() (map[string]*schema.Sche
 			terraformSchema[propertyName] = setPropertyForDataSourceSchema(terraformSchema[propertyName])
 		}
 	}
+	if err := s.addPropertyAliases(terraformSchema); err != nil {
+		return nil, err
+	}
 	return terraformSchema, nil
 }
 
+// addPropertyAliases registers in the given terraform schema the previous names the properties were known by (as
+// specified in the x-terraform-property-alias extension) so renaming a property in the OpenAPI document does not break
+// existing terraform configurations. The aliases are registered as deprecated copies of the property, and since the value
+// can be configured under any of the names, the property and its aliases are made optional-computed and conflicting
+// with each other.
+func (s *specSchemaDefinition) addPropertyAliases(terraformSchema map[string]*schema.Schema) error {
+	for _, property := range s.Properties {
+		propertyName := property.getTerraformCompliantPropertyName()
+		propertySchema, exists := terraformSchema[propertyName]
+		if !exists || len(property.Aliases) == 0 {
+			continue
+		}
+		names := append([]string{propertyName}, property.Aliases...)
+		if !propertySchema.Computed || propertySchema.Optional {
+			propertySchema.Required = false
+			propertySchema.Optional = true
+			propertySchema.Computed = true
+			propertySchema.Default = nil
+			propertySchema.ConflictsWith = property.Aliases
+		}
+		for _, alias := range property.Aliases {
+			if _, exists := terraformSchema[alias]; exists {
+				return fmt.Errorf("property '%s' alias '%s' conflicts with an existing property with the same name", propertyName, alias)
+			}
+			aliasSchema := *propertySchema
+			aliasSchema.Deprecated = fmt.Sprintf("'%s' has been renamed to '%s', please use '%s' instead", alias, propertyName, propertyName)
+			if propertySchema.ConflictsWith != nil {
+				aliasSchema.ConflictsWith = s.excludeName(names, alias)
+			}
+			terraformSchema[alias] = &aliasSchema
+		}
+	}
+	return nil
+}
+
+func (s *specSchemaDefinition) excludeName(names []string, name string) []string {
+	var result []string
+	for _, n := range names {
+		if n != name {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
 func setPropertyForDataSourceSchema(inputProperty *schema.Schema) (outputProperty *schema.Schema) {
 
 	outputProperty = inputProperty // the output is a clone of the input, do changes on the output var
@@ -150,6 +205,20 @@ func (s *specSchemaDefinition) getStatusIdentifierFor(schemaDefinition *specSche
 			break
 		}
 	}
+	// properties nested in objects marked with extTfFieldStatus are also honored (e.g. operation.state) if no top level
+	// property was found
+	if statusProperty == nil {
+		for _, property := range schemaDefinition.Properties {
+			nestedStatusHierarchy := property.getNestedStatusIdentifier()
+			if nestedStatusHierarchy == nil {
+				continue
+			}
+			if !property.ReadOnly && shouldEnforceReadOnly {
+				return nil, fmt.Errorf("schema definition property '%s' containing the status property must be readOnly", property.Name)
+			}
+			return append([]string{property.Name}, nestedStatusHierarchy...), nil
+		}
+	}
 	// if the id field is missing and there isn't any properties set with extTfFieldStatus, there is not way for the resource
 	// to be identified and therefore an error is returned
 	if statusProperty == nil {
@@ -170,6 +239,70 @@ func (s *specSchemaDefinition) getStatusIdentifierFor(schemaDefinition *specSche
 	return statusHierarchy, nil
 }
 
+// getStatusMessageIdentifier returns the name of the property configured with metadata 'x-terraform-field-status-message'
+// set to true. If none of the properties is configured as such, an empty string is returned
+func (s *specSchemaDefinition) getStatusMessageIdentifier() string {
+	for _, property := range s.Properties {
+		if property.IsStatusMessage {
+			return property.Name
+		}
+	}
+	return ""
+}
+
+// validateDerivedProperties checks that the placeholders of the derived properties' templates refer to primitive properties
+// of the schema that are not derived themselves
+func (s *specSchemaDefinition) validateDerivedProperties() error {
+	for _, property := range s.Properties {
+		for _, placeholder := range property.getDerivedTemplatePlaceholders() {
+			referredProperty, err := s.getProperty(placeholder)
+			if err != nil || !referredProperty.isPrimitiveProperty() || referredProperty.isDerivedProperty() {
+				return fmt.Errorf("failed to process property '%s': derived template placeholder '{%s}' must refer to a primitive property (that is not derived) of the same schema", property.Name, placeholder)
+			}
+		}
+	}
+	return nil
+}
+
+// validateRequiredIfProperties checks that the conditions of the conditionally required properties refer to other primitive
+// properties of the same schema. Conditionally required properties are only supported at the top level of the schema since
+// the conditions are checked against the top level attributes when planning
+func (s *specSchemaDefinition) validateRequiredIfProperties() error {
+	for _, property := range s.Properties {
+		if property.SpecSchemaDefinition != nil {
+			for _, nestedProperty := range property.SpecSchemaDefinition.Properties {
+				if nestedProperty.isConditionallyRequired() {
+					return fmt.Errorf("failed to process property '%s': extension '%s' is not supported in nested properties ('%s')", property.Name, extTfRequiredIf, nestedProperty.Name)
+				}
+			}
+		}
+		if !property.isConditionallyRequired() {
+			continue
+		}
+		referredProperty, err := s.getProperty(property.RequiredIfProperty)
+		if err != nil || referredProperty == property || !referredProperty.isPrimitiveProperty() {
+			return fmt.Errorf("failed to process property '%s': extension '%s' condition property '%s' must refer to another primitive property of the same schema", property.Name, extTfRequiredIf, property.RequiredIfProperty)
+		}
+	}
+	return nil
+}
+
+// validateDefaultFromAPIProperties checks that the properties whose default value is fetched from the API are at the top
+// level of the schema, since the default values are only fetched for the top level attributes of the resources
+func (s *specSchemaDefinition) validateDefaultFromAPIProperties() error {
+	for _, property := range s.Properties {
+		if property.SpecSchemaDefinition == nil {
+			continue
+		}
+		for _, nestedProperty := range property.SpecSchemaDefinition.Properties {
+			if nestedProperty.isDefaultFromAPI() {
+				return fmt.Errorf("failed to process property '%s': extension '%s' is not supported in nested properties ('%s')", property.Name, extTfDefaultFromAPI, nestedProperty.Name)
+			}
+		}
+	}
+	return nil
+}
+
 func (s *specSchemaDefinition) getProperty(name string) (*specSchemaDefinitionProperty, error) {
 	for _, property := range s.Properties {
 		if property.Name == name {
@@ -1,10 +1,16 @@
 package openapi
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/dikhan/terraform-provider-openapi/openapi/terraformutils"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 // schemaDefinitionPropertyType defines the type of a property
@@ -19,6 +25,9 @@ const (
 	typeObject schemaDefinitionPropertyType = "object"
 )
 
+// derivedTemplatePlaceholderRegex matches the placeholders of the derived templates (e.g. {host} in 'https://{host}:{port}')
+var derivedTemplatePlaceholderRegex = regexp.MustCompile(`{([^{}]+)}`)
+
 const idDefaultPropertyName = "id"
 const statusDefaultPropertyName = "status"
 
@@ -28,19 +37,33 @@ type specSchemaDefinitionProperty struct {
 	PreferredName  string
 	Type           schemaDefinitionPropertyType
 	ArrayItemsType schemaDefinitionPropertyType
-	Required       bool
+	// Description contains the property description as documented in the openapi spec
+	Description string
+	Required    bool
 	// ReadOnly properties are included in responses but not in request
 	ReadOnly bool
 	// Computed properties describe properties where the value is computed by the API
 	Computed bool
 	// IsParentProperty defines whether the property is a parent property in which case it will be treated differently in
 	// different parts of the code. For instance, the property will not be posted to the API.
-	IsParentProperty   bool
-	ForceNew           bool
+	IsParentProperty bool
+	ForceNew         bool
+	// ForceNewReason explains in API terms why changing the ForceNew property requires replacing the resource (e.g. the
+	// region can not be changed once the database is provisioned)
+	ForceNewReason     string
 	Sensitive          bool
 	Immutable          bool
 	IsIdentifier       bool
 	IsStatusIdentifier bool
+	// IsStatusMessage defines whether the property contains a human readable message describing the current status of the
+	// resource (e.g. provisioning progress) which is surfaced while waiting for the resource to reach a completion status
+	IsStatusMessage bool
+	// CopyTo contains the names of other payload fields that should be populated with the same value as this property
+	// when building the request payload (e.g. APIs that expect the same value in both name and display_name)
+	CopyTo []string
+	// Aliases contains the previous terraform names the property was known by, which are still accepted in the terraform
+	// configuration (flagged as deprecated) and mapped to the same API field
+	Aliases []string
 	// EnableLegacyComplexObjectBlockConfiguration defines whether this specSchemaDefinitionProperty should be handled with special treatment following
 	// the recommendation from hashi maintainers (https://github.com/hashicorp/terraform/issues/22511#issuecomment-522655851)
 	// to support complex object types with the legacy SDK (objects that contain properties with different types and configurations
@@ -49,6 +72,36 @@ type specSchemaDefinitionProperty struct {
 	// Default field is only for informative purposes to know what the openapi spec for the property stated the default value is
 	// As per the openapi spec default attributes, the value is expected to be computed by the API
 	Default interface{}
+	// Enum contains the values allowed for the property as specified in the openapi spec 'enum' attribute. Only applicable
+	// to primitive properties (string, integer, number and boolean)
+	Enum []interface{}
+	// ArrayItemsEnum and ArrayItemsPattern contain the values allowed and the regular expression the items must match as
+	// specified in the openapi spec array 'items' attribute. Only applicable to arrays of primitives
+	ArrayItemsEnum    []interface{}
+	ArrayItemsPattern string
+	// MinItems and MaxItems contain the min and max number of items allowed as specified in the openapi spec 'minItems' and
+	// 'maxItems' attributes. Only applicable to array properties, zero means there is no limit
+	MinItems int
+	MaxItems int
+	// SetHashKeys and SetHashIgnoreCase turn arrays of objects into terraform sets where elements are identified by the
+	// values of the given item properties (all the primitive item properties if no keys are specified), optionally
+	// ignoring case differences. Only applicable to arrays of objects
+	SetHashKeys       []string
+	SetHashIgnoreCase bool
+	// DerivedTemplate contains the template used to compute the value of state only properties out of other properties
+	// of the payload (e.g. 'https://{host}:{port}')
+	DerivedTemplate string
+	// RefreshOnDemand defines whether the computed property is expensive for the API to compute, in which case the value
+	// is only populated when the resource is created, updated or imported (or when the provider is configured to perform
+	// full refreshes) and routine refreshes keep the value already stored in the state
+	RefreshOnDemand bool
+	// RequiredIfProperty and RequiredIfValues make the optional property required when the given property of the same
+	// schema is set to any of the values (e.g. 'pre_shared_key' required when 'type' is 'vpn')
+	RequiredIfProperty string
+	RequiredIfValues   []string
+	// DefaultFromAPI defines the API endpoint the default value of the optional property is fetched from when planning
+	// (e.g. the default network id returned by GET /v1/defaults)
+	DefaultFromAPI *apiDefault
 	// only for object type properties or arrays type properties with array items of type object
 	SpecSchemaDefinition *specSchemaDefinition
 }
@@ -114,6 +167,55 @@ func (s *specSchemaDefinitionProperty) isArrayOfObjectsProperty() bool {
 	return s.Type == typeList && s.ArrayItemsType == typeObject
 }
 
+// isSetProperty returns true if the property is an array of objects configured to be represented as a terraform set
+func (s *specSchemaDefinitionProperty) isSetProperty() bool {
+	return s.isArrayOfObjectsProperty() && (len(s.SetHashKeys) > 0 || s.SetHashIgnoreCase)
+}
+
+// isConditionallyRequired returns true if the property is required only when another property is set to certain values
+func (s *specSchemaDefinitionProperty) isConditionallyRequired() bool {
+	return s.RequiredIfProperty != ""
+}
+
+// isDefaultFromAPI returns true if the default value of the property is fetched from the API
+func (s *specSchemaDefinitionProperty) isDefaultFromAPI() bool {
+	return s.DefaultFromAPI != nil
+}
+
+func (s *specSchemaDefinitionProperty) isDerivedProperty() bool {
+	return s.DerivedTemplate != ""
+}
+
+// getDerivedTemplatePlaceholders returns the names of the properties referred in the derived template
+func (s *specSchemaDefinitionProperty) getDerivedTemplatePlaceholders() []string {
+	var placeholders []string
+	for _, match := range derivedTemplatePlaceholderRegex.FindAllStringSubmatch(s.DerivedTemplate, -1) {
+		placeholders = append(placeholders, match[1])
+	}
+	return placeholders
+}
+
+// derivedValue computes the value of the derived property replacing the placeholders of the template with the values
+// of the corresponding properties in the given payload. False is returned if any of the values is missing
+func (s *specSchemaDefinitionProperty) derivedValue(payload map[string]interface{}) (string, bool) {
+	found := true
+	value := derivedTemplatePlaceholderRegex.ReplaceAllStringFunc(s.DerivedTemplate, func(placeholder string) string {
+		switch v := payload[strings.Trim(placeholder, "{}")].(type) {
+		case nil:
+			found = false
+			return ""
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			return fmt.Sprintf("%v", v)
+		}
+	})
+	if !found {
+		return "", false
+	}
+	return value, true
+}
+
 func (s *specSchemaDefinitionProperty) isReadOnly() bool {
 	return s.ReadOnly
 }
@@ -211,6 +313,14 @@ func (s *specSchemaDefinitionProperty) shouldUseLegacyTerraformSDKBlockApproachF
 	return s.isLegacyComplexObjectExtensionEnabled()
 }
 
+// terraformObjectMapElem returns the Elem of the TypeMap schema used for the objects that are not configured as blocks. The
+// Terraform SDK v2 rejects maps whose Elem is a *schema.Resource; the SDK v1 accepted them but already treated them as maps
+// of strings (both in the configuration and in the state), so declaring the map of strings explicitly does not change the
+// shape of the states created with previous versions of the provider and no state upgrade is needed
+func terraformObjectMapElem() *schema.Schema {
+	return &schema.Schema{Type: schema.TypeString}
+}
+
 // terraformSchema returns the terraform schema for a the given specSchemaDefinitionProperty
 func (s *specSchemaDefinitionProperty) terraformSchema() (*schema.Schema, error) {
 	var terraformSchema = &schema.Schema{}
@@ -224,18 +334,27 @@ func (s *specSchemaDefinitionProperty) terraformSchema() (*schema.Schema, error)
 	// complex data structures
 	switch s.Type {
 	case typeObject:
-		if s.shouldUseLegacyTerraformSDKBlockApproachForComplexObjects() {
-			terraformSchema.Type = schema.TypeList
-			terraformSchema.MaxItems = 1
-		}
 		objectSchema, err := s.terraformObjectSchema()
 		if err != nil {
 			return nil, err
 		}
-		terraformSchema.Elem = objectSchema
+		if s.shouldUseLegacyTerraformSDKBlockApproachForComplexObjects() {
+			terraformSchema.Type = schema.TypeList
+			terraformSchema.MaxItems = 1
+			terraformSchema.Elem = objectSchema
+		} else {
+			terraformSchema.Elem = terraformObjectMapElem()
+		}
 
 	case typeList:
 		if isListOfPrimitives, elemSchema := s.isTerraformListOfSimpleValues(); isListOfPrimitives {
+			if !s.ReadOnly && (len(s.ArrayItemsEnum) > 0 || s.ArrayItemsPattern != "") {
+				validateFunc, err := s.arrayItemsValidateFunc()
+				if err != nil {
+					return nil, err
+				}
+				elemSchema.ValidateFunc = validateFunc
+			}
 			terraformSchema.Elem = elemSchema
 		} else {
 			objectSchema, err := s.terraformObjectSchema()
@@ -243,6 +362,10 @@ func (s *specSchemaDefinitionProperty) terraformSchema() (*schema.Schema, error)
 				return nil, err
 			}
 			terraformSchema.Elem = objectSchema
+			if s.isSetProperty() {
+				terraformSchema.Type = schema.TypeSet
+				terraformSchema.Set = s.setHashFunc()
+			}
 		}
 	}
 
@@ -266,9 +389,41 @@ func (s *specSchemaDefinitionProperty) terraformSchema() (*schema.Schema, error)
 		terraformSchema.Optional = true
 	}
 
+	terraformSchema.Description = s.Description
+	if s.ForceNew && s.ForceNewReason != "" {
+		forceNewReason := fmt.Sprintf("Changing this forces a new resource to be created: %s", s.ForceNewReason)
+		if terraformSchema.Description != "" {
+			terraformSchema.Description = fmt.Sprintf("%s. %s", strings.TrimSuffix(terraformSchema.Description, "."), forceNewReason)
+		} else {
+			terraformSchema.Description = forceNewReason
+		}
+	}
+
 	// ValidateFunc is not yet supported on lists or sets
 	if !s.isArrayProperty() && !s.isObjectProperty() {
 		terraformSchema.ValidateFunc = s.validateFunc()
+		if len(s.Enum) > 0 {
+			allowedValues := fmt.Sprintf("Allowed values: %s", s.getEnumValues())
+			if terraformSchema.Description != "" {
+				terraformSchema.Description = fmt.Sprintf("%s. %s", strings.TrimSuffix(terraformSchema.Description, "."), allowedValues)
+			} else {
+				terraformSchema.Description = allowedValues
+			}
+		}
+	}
+
+	// The number of items is only validated for lists the user can configure
+	if s.isArrayProperty() && !s.ReadOnly {
+		terraformSchema.MinItems = s.MinItems
+		terraformSchema.MaxItems = s.MaxItems
+		if len(s.ArrayItemsEnum) > 0 {
+			allowedValues := fmt.Sprintf("Allowed item values: %s", joinEnumValues(s.ArrayItemsEnum))
+			if terraformSchema.Description != "" {
+				terraformSchema.Description = fmt.Sprintf("%s. %s", strings.TrimSuffix(terraformSchema.Description, "."), allowedValues)
+			} else {
+				terraformSchema.Description = allowedValues
+			}
+		}
 	}
 
 	// Don't populate Default if property is readOnly as the property is expected to be computed by the API. Terraform does
@@ -281,6 +436,55 @@ func (s *specSchemaDefinitionProperty) terraformSchema() (*schema.Schema, error)
 	return terraformSchema, nil
 }
 
+// setHashFunc returns the function terraform uses to identify the elements of set properties, so elements that only differ
+// in properties that are not part of the hash keys (or in the case of the values when SetHashIgnoreCase is enabled) are
+// considered the same element and do not produce diffs
+func (s *specSchemaDefinitionProperty) setHashFunc() schema.SchemaSetFunc {
+	return func(v interface{}) int {
+		return s.setElementHashCode(v, (*specSchemaDefinitionProperty).getTerraformCompliantPropertyName)
+	}
+}
+
+// setElementHashCode calculates the hash of the given set element. The elementKey function returns the key the item
+// property values are stored under in the element, which differs for terraform state elements and API payload elements
+func (s *specSchemaDefinitionProperty) setElementHashCode(v interface{}, elementKey func(*specSchemaDefinitionProperty) string) int {
+	var buf bytes.Buffer
+	element, _ := v.(map[string]interface{})
+	for _, property := range s.setHashProperties() {
+		value := fmt.Sprintf("%v", element[elementKey(property)])
+		if s.SetHashIgnoreCase {
+			value = strings.ToLower(value)
+		}
+		buf.WriteString(fmt.Sprintf("%s-", value))
+	}
+	return schema.HashString(buf.String())
+}
+
+// setHashProperties returns the item properties which values are used to calculate the hash of the set elements, sorted
+// by name so the hash is stable across executions
+func (s *specSchemaDefinitionProperty) setHashProperties() []*specSchemaDefinitionProperty {
+	var properties []*specSchemaDefinitionProperty
+	if s.SpecSchemaDefinition == nil {
+		return properties
+	}
+	for _, property := range s.SpecSchemaDefinition.Properties {
+		if len(s.SetHashKeys) == 0 {
+			if property.isPrimitiveProperty() {
+				properties = append(properties, property)
+			}
+			continue
+		}
+		for _, key := range s.SetHashKeys {
+			if property.Name == key {
+				properties = append(properties, property)
+				break
+			}
+		}
+	}
+	sort.Slice(properties, func(i, j int) bool { return properties[i].Name < properties[j].Name })
+	return properties
+}
+
 func (s *specSchemaDefinitionProperty) validateFunc() schema.SchemaValidateFunc {
 	return func(v interface{}, k string) (ws []string, errors []error) {
 		if s.ForceNew && s.Immutable {
@@ -289,6 +493,111 @@ func (s *specSchemaDefinitionProperty) validateFunc() schema.SchemaValidateFunc
 		if s.Required && s.ReadOnly {
 			errors = append(errors, fmt.Errorf("property '%s' is configured as required and can not be configured as computed too", s.Name))
 		}
+		if v != nil && len(s.Enum) > 0 && !s.isEnumValue(v) {
+			errors = append(errors, messages.error(MessageEnumValueNotValid, messageArgs{"property": s.Name, "value": v, "allowed_values": s.getEnumValues()}))
+		}
 		return
 	}
 }
+
+// arrayItemsValidateFunc returns the function validating each of the items of an array of primitives against the items
+// enum and pattern. The errors returned refer to the index of the item that is not valid.
+func (s *specSchemaDefinitionProperty) arrayItemsValidateFunc() (schema.SchemaValidateFunc, error) {
+	var pattern *regexp.Regexp
+	if s.ArrayItemsPattern != "" {
+		var err error
+		if pattern, err = regexp.Compile(s.ArrayItemsPattern); err != nil {
+			return nil, fmt.Errorf("property '%s' items pattern '%s' is not valid: %s", s.Name, s.ArrayItemsPattern, err)
+		}
+	}
+	return func(v interface{}, k string) (ws []string, errors []error) {
+		// the key of the items is formed by the property name followed by the index of the item (e.g. tags.1)
+		index := k[strings.LastIndex(k, ".")+1:]
+		if len(s.ArrayItemsEnum) > 0 && !isEnumValue(s.ArrayItemsEnum, s.ArrayItemsType, v) {
+			errors = append(errors, messages.error(MessageArrayItemEnumValueNotValid, messageArgs{"property": s.Name, "index": index, "value": v, "allowed_values": joinEnumValues(s.ArrayItemsEnum)}))
+		}
+		if value, ok := v.(string); ok && pattern != nil && !pattern.MatchString(value) {
+			errors = append(errors, messages.error(MessageArrayItemPatternNotMatched, messageArgs{"property": s.Name, "index": index, "value": value, "pattern": s.ArrayItemsPattern}))
+		}
+		return
+	}, nil
+}
+
+// isEnumValue checks whether the given value is one of the values allowed by the property Enum
+func (s *specSchemaDefinitionProperty) isEnumValue(value interface{}) bool {
+	return isEnumValue(s.Enum, s.Type, value)
+}
+
+// isEnumValue checks whether the given value of the given type is one of the enum values. Numeric values are compared
+// by their numeric value regardless of their type (e.g. enum values coming from the openapi spec are decoded as float64
+// whereas terraform integer values are int)
+func isEnumValue(enum []interface{}, valueType schemaDefinitionPropertyType, value interface{}) bool {
+	for _, enumValue := range enum {
+		switch valueType {
+		case typeInt, typeFloat:
+			enumNumber, isEnumNumber := toFloat64(enumValue)
+			number, isNumber := toFloat64(value)
+			if isEnumNumber && isNumber && enumNumber == number {
+				return true
+			}
+		default:
+			if enumValue == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getEnumValues returns the property Enum values as a comma separated string
+func (s *specSchemaDefinitionProperty) getEnumValues() string {
+	return joinEnumValues(s.Enum)
+}
+
+// joinEnumValues returns the given enum values as a comma separated string
+func joinEnumValues(enum []interface{}) string {
+	var enumValues []string
+	for _, enumValue := range enum {
+		enumValues = append(enumValues, fmt.Sprintf("%v", enumValue))
+	}
+	return strings.Join(enumValues, ", ")
+}
+
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case json.Number:
+		number, err := v.Float64()
+		return number, err == nil
+	}
+	return 0, false
+}
+
+// getNestedStatusIdentifier returns the hierarchy of property names (starting from the properties of the object) leading
+// to the nested property marked with IsStatusIdentifier. Nil is returned if the property is not an object or none of
+// its nested properties is marked as the status identifier
+func (s *specSchemaDefinitionProperty) getNestedStatusIdentifier() []string {
+	if !s.isObjectProperty() || s.SpecSchemaDefinition == nil {
+		return nil
+	}
+	for _, property := range s.SpecSchemaDefinition.Properties {
+		if property.IsStatusIdentifier {
+			return []string{property.Name}
+		}
+	}
+	for _, property := range s.SpecSchemaDefinition.Properties {
+		if nestedStatusHierarchy := property.getNestedStatusIdentifier(); nestedStatusHierarchy != nil {
+			return append([]string{property.Name}, nestedStatusHierarchy...)
+		}
+	}
+	return nil
+}
@@ -6,7 +6,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -896,7 +897,7 @@ func TestTerraformSchema(t *testing.T) {
 				nestedObject1 := tfPropSchema.Elem.(*schema.Resource).Schema["nested_object1"]
 				So(nestedObject1, ShouldNotBeNil)
 				So(nestedObject1.Type, ShouldEqual, schema.TypeMap)
-				So(nestedObject1.Elem.(*schema.Resource).Schema["string_property_1"].Type, ShouldEqual, schema.TypeString)
+				So(nestedObject1.Elem, ShouldResemble, &schema.Schema{Type: schema.TypeString})
 			})
 			Convey("And the returned terraform schema contains the 'nested_float_2' with the right configuration", func() {
 				nestedObject2 := tfPropSchema.Elem.(*schema.Resource).Schema["nested_float_2"]
@@ -950,13 +951,13 @@ func TestTerraformSchema(t *testing.T) {
 				nestedObject1 := tfPropSchema.Elem.(*schema.Resource).Schema[expectedNestedObjectPropertyName1]
 				So(nestedObject1, ShouldNotBeNil)
 				So(nestedObject1.Type, ShouldEqual, schema.TypeMap)
-				So(nestedObject1.Elem.(*schema.Resource).Schema["string_property_1"].Type, ShouldEqual, schema.TypeString)
+				So(nestedObject1.Elem, ShouldResemble, &schema.Schema{Type: schema.TypeString})
 			})
 			Convey("And the returned terraform schema contains the schema for the Second nested object property with the right configuration", func() {
 				nestedObject2 := tfPropSchema.Elem.(*schema.Resource).Schema[expectedNestedObjectPropertyName2]
 				So(nestedObject2, ShouldNotBeNil)
 				So(nestedObject2.Type, ShouldEqual, schema.TypeMap)
-				So(nestedObject2.Elem.(*schema.Resource).Schema["string_property_2"].Type, ShouldEqual, schema.TypeString)
+				So(nestedObject2.Elem, ShouldResemble, &schema.Schema{Type: schema.TypeString})
 			})
 		})
 	})
@@ -1099,10 +1100,52 @@ func TestTerraformSchema(t *testing.T) {
 			}}
 		Convey("When terraformSchema method is called", func() {
 			tfPropSchema, err := s.terraformSchema()
-			Convey("Then the resulting tfPropSchema should match the following using TypeMap as type", func() {
+			Convey("Then the resulting tfPropSchema should match the following using TypeMap of strings as type", func() {
 				So(err, ShouldBeNil)
 				So(tfPropSchema.Type, ShouldEqual, schema.TypeMap)
-				So(tfPropSchema.Elem.(*schema.Resource).Schema["my_int_prop"].Computed, ShouldBeTrue)
+				So(tfPropSchema.Elem, ShouldResemble, &schema.Schema{Type: schema.TypeString})
+			})
+		})
+	})
+
+	Convey("Given a resource containing a simple object property and a state created by previous versions of the provider", t, func() {
+		s := &specSchemaDefinitionProperty{
+			Name: "object_property",
+			Type: typeObject,
+			SpecSchemaDefinition: &specSchemaDefinition{
+				Properties: specSchemaDefinitionProperties{
+					&specSchemaDefinitionProperty{
+						Type:                 typeString,
+						Name:                 "message",
+						SpecSchemaDefinition: &specSchemaDefinition{},
+					},
+					&specSchemaDefinitionProperty{
+						Type:                 typeInt,
+						Name:                 "count",
+						SpecSchemaDefinition: &specSchemaDefinition{},
+					},
+				},
+			}}
+		tfPropSchema, err := s.terraformSchema()
+		So(err, ShouldBeNil)
+		tfPropSchema.Optional = true
+		resource := &schema.Resource{Schema: map[string]*schema.Schema{"object_property": tfPropSchema}}
+		// the object values were already stored as strings in the states of the maps declared with *schema.Resource elements
+		state := &terraform.InstanceState{
+			ID: "id",
+			Attributes: map[string]string{
+				"id":                      "id",
+				"object_property.%":       "2",
+				"object_property.message": "hello",
+				"object_property.count":   "1",
+			},
+		}
+		Convey("When the resource schema is validated and the state is read", func() {
+			validationErr := resource.InternalValidate(nil, true)
+			data := resource.Data(state)
+			Convey("Then the schema should be valid and the object values should be read as is", func() {
+				So(validationErr, ShouldBeNil)
+				So(data.Get("object_property"), ShouldResemble, map[string]interface{}{"message": "hello", "count": "1"})
 			})
 		})
 	})
@@ -1331,16 +1374,16 @@ func TestTerraformSchema(t *testing.T) {
 				},
 			},
 		}
-		Convey("When terraformSchema method is called", func() {
-			tfPropSchema, err := s.terraformSchema()
+		Convey("When terraformObjectSchema method is called", func() {
+			tfObjectSchema, err := s.terraformObjectSchema()
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
 			Convey("And the tf resource schema returned should not be nil", func() {
-				So(tfPropSchema, ShouldNotBeNil)
+				So(tfObjectSchema, ShouldNotBeNil)
 			})
 			Convey("And the tf resource schema returned should contained the sub property - 'message'", func() {
-				So(tfPropSchema.Elem.(*schema.Resource).Schema, ShouldContainKey, "message")
+				So(tfObjectSchema.Schema, ShouldContainKey, "message")
 			})
 		})
 	})
@@ -1364,19 +1407,19 @@ func TestTerraformSchema(t *testing.T) {
 				},
 			},
 		}
-		Convey("When terraformSchema method is called", func() {
-			tfPropSchema, err := s.terraformSchema()
+		Convey("When terraformObjectSchema method is called", func() {
+			tfObjectSchema, err := s.terraformObjectSchema()
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
 			Convey("And the tf resource schema returned should not be nil", func() {
-				So(tfPropSchema, ShouldNotBeNil)
+				So(tfObjectSchema, ShouldNotBeNil)
 			})
 			Convey("And the tf resource schema returned should contain the sub property - 'message'", func() {
-				So(tfPropSchema.Elem.(*schema.Resource).Schema, ShouldContainKey, "message")
+				So(tfObjectSchema.Schema, ShouldContainKey, "message")
 			})
 			Convey("And the tf resource schema returned should contain the sub property - 'id' and should not be ignored", func() {
-				So(tfPropSchema.Elem.(*schema.Resource).Schema, ShouldContainKey, "id")
+				So(tfObjectSchema.Schema, ShouldContainKey, "id")
 			})
 		})
 	})
@@ -1552,6 +1595,319 @@ func TestTerraformSchema(t *testing.T) {
 			})
 		})
 	})
+
+	Convey("Given an integer schemaDefinitionProperty with enum values (decoded from the openapi spec as float64)", t, func() {
+		s := newIntSchemaDefinitionPropertyWithDefaults("propertyName", "", false, false, nil)
+		s.Enum = []interface{}{float64(1), float64(2), float64(3)}
+		Convey("When the validate function is called with a value that is part of the enum", func() {
+			_, err := s.validateFunc()(2, "")
+			Convey("Then the errors returned should be empty", func() {
+				So(err, ShouldBeEmpty)
+			})
+		})
+		Convey("When the validate function is called with a value that is not part of the enum", func() {
+			_, err := s.validateFunc()(4, "")
+			Convey("Then the error returned should contain the allowed values", func() {
+				So(err, ShouldNotBeEmpty)
+				So(err[0].Error(), ShouldEqual, "property 'propertyName' value '4' is not valid, allowed values are: 1, 2, 3")
+			})
+		})
+	})
+
+	Convey("Given a number schemaDefinitionProperty with enum values", t, func() {
+		s := newNumberSchemaDefinitionPropertyWithDefaults("propertyName", "", false, false, nil)
+		s.Enum = []interface{}{float64(1.5), float64(2.5)}
+		Convey("When the validate function is called with a value that is part of the enum", func() {
+			_, err := s.validateFunc()(1.5, "")
+			Convey("Then the errors returned should be empty", func() {
+				So(err, ShouldBeEmpty)
+			})
+		})
+		Convey("When the validate function is called with a value that is not part of the enum", func() {
+			_, err := s.validateFunc()(3.5, "")
+			Convey("Then the errors returned should not be empty", func() {
+				So(err, ShouldNotBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given a boolean schemaDefinitionProperty with enum values", t, func() {
+		s := newBoolSchemaDefinitionPropertyWithDefaults("propertyName", "", false, false, nil)
+		s.Enum = []interface{}{true}
+		Convey("When the validate function is called with a value that is part of the enum", func() {
+			_, err := s.validateFunc()(true, "")
+			Convey("Then the errors returned should be empty", func() {
+				So(err, ShouldBeEmpty)
+			})
+		})
+		Convey("When the validate function is called with a value that is not part of the enum", func() {
+			_, err := s.validateFunc()(false, "")
+			Convey("Then the error returned should contain the allowed values", func() {
+				So(err, ShouldNotBeEmpty)
+				So(err[0].Error(), ShouldEqual, "property 'propertyName' value 'false' is not valid, allowed values are: true")
+			})
+		})
+	})
+}
+
+func TestTerraformSchema_Enum(t *testing.T) {
+	Convey("Given an integer schemaDefinitionProperty with enum values", t, func() {
+		s := newIntSchemaDefinitionPropertyWithDefaults("propertyName", "", false, false, nil)
+		s.Enum = []interface{}{float64(10), float64(20)}
+		Convey("When terraformSchema method is called", func() {
+			terraformPropertySchema, err := s.terraformSchema()
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the schema description should document the allowed values", func() {
+				So(terraformPropertySchema.Description, ShouldEqual, "Allowed values: 10, 20")
+			})
+		})
+	})
+	Convey("Given a string schemaDefinitionProperty with a description and enum values", t, func() {
+		s := newStringSchemaDefinitionPropertyWithDefaults("propertyName", "", false, false, nil)
+		s.Description = "The protocol used."
+		s.Enum = []interface{}{"http", "https"}
+		Convey("When terraformSchema method is called", func() {
+			terraformPropertySchema, err := s.terraformSchema()
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the schema description should contain both the property description and the allowed values", func() {
+				So(terraformPropertySchema.Description, ShouldEqual, "The protocol used. Allowed values: http, https")
+			})
+		})
+	})
+}
+
+func TestTerraformSchema_ForceNewReason(t *testing.T) {
+	Convey("Given a force new string schemaDefinitionProperty with a description and a force new reason", t, func() {
+		s := newStringSchemaDefinitionPropertyWithDefaults("region", "", true, false, nil)
+		s.Description = "The region where the database is provisioned."
+		s.ForceNew = true
+		s.ForceNewReason = "the API does not support moving databases across regions"
+		Convey("When terraformSchema method is called", func() {
+			terraformPropertySchema, err := s.terraformSchema()
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the schema description should contain both the property description and the force new reason", func() {
+				So(terraformPropertySchema.ForceNew, ShouldBeTrue)
+				So(terraformPropertySchema.Description, ShouldEqual, "The region where the database is provisioned. Changing this forces a new resource to be created: the API does not support moving databases across regions")
+			})
+		})
+	})
+	Convey("Given a force new string schemaDefinitionProperty without a description and with a force new reason", t, func() {
+		s := newStringSchemaDefinitionPropertyWithDefaults("region", "", true, false, nil)
+		s.ForceNew = true
+		s.ForceNewReason = "the API does not support moving databases across regions"
+		Convey("When terraformSchema method is called", func() {
+			terraformPropertySchema, err := s.terraformSchema()
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the schema description should be the force new reason", func() {
+				So(terraformPropertySchema.Description, ShouldEqual, "Changing this forces a new resource to be created: the API does not support moving databases across regions")
+			})
+		})
+	})
+}
+
+func TestTerraformSchema_ArrayItemsValidation(t *testing.T) {
+	Convey("Given a list of strings schemaDefinitionProperty with items enum, items pattern, min items and max items", t, func() {
+		s := newListSchemaDefinitionPropertyWithDefaults("tags", "", false, false, false, nil, typeString, nil)
+		s.ArrayItemsEnum = []interface{}{"dev", "prod", "prod-eu"}
+		s.ArrayItemsPattern = "^[a-z]+$"
+		s.MinItems = 1
+		s.MaxItems = 2
+		Convey("When terraformSchema method is called", func() {
+			terraformPropertySchema, err := s.terraformSchema()
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the schema should be configured with the min and max items", func() {
+				So(terraformPropertySchema.MinItems, ShouldEqual, 1)
+				So(terraformPropertySchema.MaxItems, ShouldEqual, 2)
+			})
+			Convey("And the schema description should document the allowed item values", func() {
+				So(terraformPropertySchema.Description, ShouldEqual, "Allowed item values: dev, prod, prod-eu")
+			})
+			Convey("And the list itself should not have a validate function as it is not supported by terraform", func() {
+				So(terraformPropertySchema.ValidateFunc, ShouldBeNil)
+			})
+			elemValidateFunc := terraformPropertySchema.Elem.(*schema.Schema).ValidateFunc
+			Convey("And the validate function of the items should not return errors for valid items", func() {
+				_, errs := elemValidateFunc("prod", "tags.0")
+				So(errs, ShouldBeEmpty)
+			})
+			Convey("And the validate function of the items should return an error referencing the index of the item that is not part of the enum", func() {
+				_, errs := elemValidateFunc("staging", "tags.1")
+				So(errs, ShouldHaveLength, 1)
+				So(errs[0].Error(), ShouldEqual, "property 'tags' item at index 1 with value 'staging' is not valid, allowed values are: dev, prod, prod-eu")
+			})
+			Convey("And the validate function of the items should return an error referencing the index of the item that does not match the pattern", func() {
+				_, errs := elemValidateFunc("prod-eu", "tags.3")
+				So(errs, ShouldHaveLength, 1)
+				So(errs[0].Error(), ShouldEqual, "property 'tags' item at index 3 with value 'prod-eu' is not valid, it must match the pattern '^[a-z]+$'")
+			})
+		})
+	})
+	Convey("Given a list of integers schemaDefinitionProperty with items enum values (decoded from the openapi spec as float64)", t, func() {
+		s := newListSchemaDefinitionPropertyWithDefaults("ports", "", false, false, false, nil, typeInt, nil)
+		s.ArrayItemsEnum = []interface{}{float64(80), float64(443)}
+		Convey("When terraformSchema method is called", func() {
+			terraformPropertySchema, err := s.terraformSchema()
+			So(err, ShouldBeNil)
+			elemValidateFunc := terraformPropertySchema.Elem.(*schema.Schema).ValidateFunc
+			Convey("Then the validate function of the items should compare the numeric values", func() {
+				_, errs := elemValidateFunc(443, "ports.0")
+				So(errs, ShouldBeEmpty)
+				_, errs = elemValidateFunc(8080, "ports.1")
+				So(errs[0].Error(), ShouldEqual, "property 'ports' item at index 1 with value '8080' is not valid, allowed values are: 80, 443")
+			})
+		})
+	})
+	Convey("Given a readOnly list of strings schemaDefinitionProperty with items enum and max items", t, func() {
+		s := newListSchemaDefinitionPropertyWithDefaults("tags", "", false, true, false, nil, typeString, nil)
+		s.ArrayItemsEnum = []interface{}{"dev", "prod"}
+		s.MaxItems = 2
+		Convey("When terraformSchema method is called", func() {
+			terraformPropertySchema, err := s.terraformSchema()
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the schema should not be configured with any validation since the value is not configured by the user", func() {
+				So(terraformPropertySchema.MaxItems, ShouldEqual, 0)
+				So(terraformPropertySchema.Elem.(*schema.Schema).ValidateFunc, ShouldBeNil)
+			})
+		})
+	})
+	Convey("Given a list of strings schemaDefinitionProperty with an items pattern that is not valid", t, func() {
+		s := newListSchemaDefinitionPropertyWithDefaults("tags", "", false, false, false, nil, typeString, nil)
+		s.ArrayItemsPattern = "^[a-z+$("
+		Convey("When terraformSchema method is called", func() {
+			_, err := s.terraformSchema()
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldStartWith, "property 'tags' items pattern '^[a-z+$(' is not valid")
+			})
+		})
+	})
+}
+
+func TestTerraformSchema_SetHash(t *testing.T) {
+	newListenersProperty := func() *specSchemaDefinitionProperty {
+		objectSchemaDefinition := &specSchemaDefinition{
+			Properties: specSchemaDefinitionProperties{
+				newStringSchemaDefinitionPropertyWithDefaults("protocol", "", false, false, nil),
+				newStringSchemaDefinitionPropertyWithDefaults("name", "", false, false, nil),
+				newStringSchemaDefinitionPropertyWithDefaults("description", "", false, false, nil),
+			},
+		}
+		return newListSchemaDefinitionPropertyWithDefaults("listeners", "", false, false, false, nil, typeObject, objectSchemaDefinition)
+	}
+	Convey("Given a list of objects schemaDefinitionProperty with set hash keys and ignore case enabled", t, func() {
+		s := newListenersProperty()
+		s.SetHashKeys = []string{"protocol", "name"}
+		s.SetHashIgnoreCase = true
+		Convey("When terraformSchema method is called", func() {
+			terraformPropertySchema, err := s.terraformSchema()
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the schema should be a set with a custom hash function", func() {
+				So(terraformPropertySchema.Type, ShouldEqual, schema.TypeSet)
+				So(terraformPropertySchema.Set, ShouldNotBeNil)
+			})
+			Convey("And elements with the same keys that only differ in case or in other properties should have the same hash", func() {
+				hash := terraformPropertySchema.Set(map[string]interface{}{"protocol": "HTTP", "name": "Web", "description": "some description"})
+				So(terraformPropertySchema.Set(map[string]interface{}{"protocol": "http", "name": "web", "description": "other description"}), ShouldEqual, hash)
+			})
+			Convey("And elements with different keys should have different hashes", func() {
+				hash := terraformPropertySchema.Set(map[string]interface{}{"protocol": "http", "name": "web"})
+				So(terraformPropertySchema.Set(map[string]interface{}{"protocol": "https", "name": "web"}), ShouldNotEqual, hash)
+			})
+		})
+	})
+	Convey("Given a list of objects schemaDefinitionProperty with only ignore case enabled", t, func() {
+		s := newListenersProperty()
+		s.SetHashIgnoreCase = true
+		Convey("When terraformSchema method is called", func() {
+			terraformPropertySchema, err := s.terraformSchema()
+			So(err, ShouldBeNil)
+			Convey("Then all the item properties should be considered when calculating the hash of the elements", func() {
+				So(terraformPropertySchema.Type, ShouldEqual, schema.TypeSet)
+				hash := terraformPropertySchema.Set(map[string]interface{}{"protocol": "HTTP", "name": "Web", "description": "some description"})
+				So(terraformPropertySchema.Set(map[string]interface{}{"protocol": "http", "name": "web", "description": "SOME DESCRIPTION"}), ShouldEqual, hash)
+				So(terraformPropertySchema.Set(map[string]interface{}{"protocol": "http", "name": "web", "description": "other description"}), ShouldNotEqual, hash)
+			})
+		})
+	})
+	Convey("Given a list of objects schemaDefinitionProperty with set hash keys and ignore case disabled", t, func() {
+		s := newListenersProperty()
+		s.SetHashKeys = []string{"name"}
+		Convey("When terraformSchema method is called", func() {
+			terraformPropertySchema, err := s.terraformSchema()
+			So(err, ShouldBeNil)
+			Convey("Then elements that differ in the case of the keys should have different hashes", func() {
+				hash := terraformPropertySchema.Set(map[string]interface{}{"name": "Web"})
+				So(terraformPropertySchema.Set(map[string]interface{}{"name": "web"}), ShouldNotEqual, hash)
+			})
+		})
+	})
+	Convey("Given a list of objects schemaDefinitionProperty without set hash configuration", t, func() {
+		s := newListenersProperty()
+		Convey("When terraformSchema method is called", func() {
+			terraformPropertySchema, err := s.terraformSchema()
+			So(err, ShouldBeNil)
+			Convey("Then the schema should be a list", func() {
+				So(terraformPropertySchema.Type, ShouldEqual, schema.TypeList)
+				So(terraformPropertySchema.Set, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestDerivedValue(t *testing.T) {
+	Convey("Given a derived schemaDefinitionProperty", t, func() {
+		s := newStringSchemaDefinitionPropertyWithDefaults("endpoint", "", false, true, nil)
+		s.DerivedTemplate = "{scheme}://{host}:{port}/{enabled}"
+		Convey("When getDerivedTemplatePlaceholders method is called", func() {
+			placeholders := s.getDerivedTemplatePlaceholders()
+			Convey("Then the placeholders returned should be the names of the properties referred in the template", func() {
+				So(placeholders, ShouldResemble, []string{"scheme", "host", "port", "enabled"})
+			})
+		})
+		Convey("When derivedValue method is called with a payload containing all the properties referred in the template", func() {
+			value, ok := s.derivedValue(map[string]interface{}{"scheme": "https", "host": "www.api.com", "port": float64(1000000), "enabled": true})
+			Convey("Then the value should be computed replacing the placeholders with the payload values", func() {
+				So(ok, ShouldBeTrue)
+				So(value, ShouldEqual, "https://www.api.com:1000000/true")
+			})
+		})
+		Convey("When derivedValue method is called with a payload missing some of the properties referred in the template", func() {
+			value, ok := s.derivedValue(map[string]interface{}{"scheme": "https", "host": "www.api.com", "port": nil})
+			Convey("Then the value should be empty and flagged as not found", func() {
+				So(ok, ShouldBeFalse)
+				So(value, ShouldEqual, "")
+			})
+		})
+	})
+}
+
+func TestTerraformSchema_Description(t *testing.T) {
+	Convey("Given a string schemaDefinitionProperty with a description", t, func() {
+		s := newStringSchemaDefinitionPropertyWithDefaults("propertyName", "", false, false, nil)
+		s.Description = "some description"
+		Convey("When terraformSchema method is called", func() {
+			terraformPropertySchema, err := s.terraformSchema()
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the schema description should be the property description", func() {
+				So(terraformPropertySchema.Description, ShouldEqual, "some description")
+			})
+		})
+	})
 }
 
 func TestValidateFunc(t *testing.T) {
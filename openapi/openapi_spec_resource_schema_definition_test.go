@@ -4,7 +4,7 @@ import (
 	"errors"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/stretchr/testify/assert"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -159,11 +159,8 @@ func TestCreateDataSourceSchema_ForNestedObjects(t *testing.T) {
 		assertDataSourceSchemaProperty(t, objectResource.Schema["id"], schema.TypeString)
 		assertDataSourceSchemaProperty(t, objectResource.Schema["nested_object"], schema.TypeMap)
 
-		// 2^ level
-		nestedObjectResource := objectResource.Schema["nested_object"].Elem.(*schema.Resource)
-		assert.Equal(t, 2, len(nestedObjectResource.Schema))
-		assertDataSourceSchemaProperty(t, nestedObjectResource.Schema["origin_port"], schema.TypeInt)
-		assertDataSourceSchemaProperty(t, nestedObjectResource.Schema["protocol"], schema.TypeString)
+		// 2^ level: the values of the nested object properties are stored as strings in the map
+		assert.Equal(t, &schema.Schema{Type: schema.TypeString}, objectResource.Schema["nested_object"].Elem)
 	})
 }
 
@@ -286,9 +283,8 @@ func TestCreateResourceSchema(t *testing.T) {
 			Convey("And the resulted tfResourceSchema status field be of type map", func() {
 				So(tfResourceSchema[statusDefaultPropertyName].Type, ShouldEqual, schema.TypeMap)
 			})
-			Convey("And the resulted tfResourceSchema status field should contain all the sub-properties including the id property", func() {
-				So(tfResourceSchema[statusDefaultPropertyName].Elem.(*schema.Resource).Schema, ShouldContainKey, "id")
-				So(tfResourceSchema[statusDefaultPropertyName].Elem.(*schema.Resource).Schema, ShouldContainKey, "name")
+			Convey("And the resulted tfResourceSchema status field should store the values of the sub-properties as strings", func() {
+				So(tfResourceSchema[statusDefaultPropertyName].Elem, ShouldResemble, &schema.Schema{Type: schema.TypeString})
 			})
 		})
 	})
@@ -416,6 +412,62 @@ func TestCreateResourceSchema(t *testing.T) {
 			})
 		})
 	})
+
+	Convey("Given a swagger schema definition that has a required property with aliases", t, func() {
+		s := &specSchemaDefinition{
+			Properties: specSchemaDefinitionProperties{
+				&specSchemaDefinitionProperty{
+					Name:     "name",
+					Type:     typeString,
+					Required: true,
+					Aliases:  []string{"label", "display_name"},
+				},
+			},
+		}
+		Convey("When createResourceSchema method is called", func() {
+			tfResourceSchema, err := s.createResourceSchema()
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the property should be optional computed and conflict with its aliases", func() {
+				So(tfResourceSchema["name"].Required, ShouldBeFalse)
+				So(tfResourceSchema["name"].Optional, ShouldBeTrue)
+				So(tfResourceSchema["name"].Computed, ShouldBeTrue)
+				So(tfResourceSchema["name"].ConflictsWith, ShouldResemble, []string{"label", "display_name"})
+				So(tfResourceSchema["name"].Deprecated, ShouldBeEmpty)
+			})
+			Convey("And the aliases should be deprecated copies of the property conflicting with the rest of names", func() {
+				So(tfResourceSchema["label"].Type, ShouldEqual, schema.TypeString)
+				So(tfResourceSchema["label"].Optional, ShouldBeTrue)
+				So(tfResourceSchema["label"].Computed, ShouldBeTrue)
+				So(tfResourceSchema["label"].ConflictsWith, ShouldResemble, []string{"name", "display_name"})
+				So(tfResourceSchema["label"].Deprecated, ShouldEqual, "'label' has been renamed to 'name', please use 'name' instead")
+				So(tfResourceSchema["display_name"].ConflictsWith, ShouldResemble, []string{"name", "label"})
+			})
+		})
+	})
+
+	Convey("Given a swagger schema definition that has a property with an alias matching the name of other property", t, func() {
+		s := &specSchemaDefinition{
+			Properties: specSchemaDefinitionProperties{
+				&specSchemaDefinitionProperty{
+					Name:    "name",
+					Type:    typeString,
+					Aliases: []string{"label"},
+				},
+				&specSchemaDefinitionProperty{
+					Name: "label",
+					Type: typeString,
+				},
+			},
+		}
+		Convey("When createResourceSchema method is called", func() {
+			_, err := s.createResourceSchema()
+			Convey("Then the err returned should describe the conflict", func() {
+				So(err.Error(), ShouldEqual, "property 'name' alias 'label' conflicts with an existing property with the same name")
+			})
+		})
+	})
 }
 
 func TestGetImmutableProperties(t *testing.T) {
@@ -752,6 +804,41 @@ func TestGetStatusIdentifierFor(t *testing.T) {
 	})
 }
 
+func TestGetStatusMessageIdentifier(t *testing.T) {
+	Convey("Given a SpecSchemaDefinition containing a property marked as status message", t, func() {
+		s := &specSchemaDefinition{
+			Properties: specSchemaDefinitionProperties{
+				newStringSchemaDefinitionPropertyWithDefaults("status", "", false, true, nil),
+				&specSchemaDefinitionProperty{
+					Name:            "status_details",
+					Type:            typeString,
+					ReadOnly:        true,
+					IsStatusMessage: true,
+				},
+			},
+		}
+		Convey("When getStatusMessageIdentifier method is called", func() {
+			statusMessageIdentifier := s.getStatusMessageIdentifier()
+			Convey("Then the property name returned should be the one marked as status message", func() {
+				So(statusMessageIdentifier, ShouldEqual, "status_details")
+			})
+		})
+	})
+	Convey("Given a SpecSchemaDefinition that does not contain any property marked as status message", t, func() {
+		s := &specSchemaDefinition{
+			Properties: specSchemaDefinitionProperties{
+				newStringSchemaDefinitionPropertyWithDefaults("status", "", false, true, nil),
+			},
+		}
+		Convey("When getStatusMessageIdentifier method is called", func() {
+			statusMessageIdentifier := s.getStatusMessageIdentifier()
+			Convey("Then the property name returned should be empty", func() {
+				So(statusMessageIdentifier, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
 func TestGetProperty(t *testing.T) {
 	Convey("Given a specSchemaDefinition", t, func() {
 		existingPropertyName := "existingPropertyName"
@@ -800,3 +887,30 @@ func TestGetPropertyBasedOnTerraformName(t *testing.T) {
 	assert.EqualError(t, err, "property with terraform name 'badTerraformPropertyName' not existing in resource schema definition")
 
 }
+
+func TestGetStatusIdentifier_NestedStatusIdentifier(t *testing.T) {
+	nestedStatus := func(readOnly bool) *specSchemaDefinition {
+		return &specSchemaDefinition{
+			Properties: specSchemaDefinitionProperties{
+				idProperty,
+				&specSchemaDefinitionProperty{
+					Name:     "operation",
+					Type:     typeObject,
+					ReadOnly: readOnly,
+					SpecSchemaDefinition: &specSchemaDefinition{
+						Properties: specSchemaDefinitionProperties{
+							&specSchemaDefinitionProperty{Name: "id", Type: typeString},
+							&specSchemaDefinitionProperty{Name: "state", Type: typeString, IsStatusIdentifier: true},
+						},
+					},
+				},
+			},
+		}
+	}
+	status, err := nestedStatus(true).getStatusIdentifier()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"operation", "state"}, status)
+
+	_, err = nestedStatus(false).getStatusIdentifier()
+	assert.EqualError(t, err, "schema definition property 'operation' containing the status property must be readOnly")
+}
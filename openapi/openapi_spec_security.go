@@ -10,4 +10,10 @@ type SpecSecurity interface {
 	// GetGlobalSecuritySchemes returns all the global security schemes from the OpenAPI document and translates those
 	// into SpecSecuritySchemes
 	GetGlobalSecuritySchemes() (SpecSecuritySchemes, error)
+	// GetGlobalSecurityRequirements returns the alternative global security requirements from the OpenAPI document, any of
+	// which can be used to call the API. The first one is the requirement used by default
+	GetGlobalSecurityRequirements() ([]SpecSecuritySchemes, error)
+	// GetTokenIntrospectionURLs returns the token introspection endpoints declared for the security definitions, keyed by
+	// the security definition terraform configuration name
+	GetTokenIntrospectionURLs() (map[string]string, error)
 }
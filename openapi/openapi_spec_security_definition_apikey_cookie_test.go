@@ -0,0 +1,49 @@
+package openapi
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewAPIKeyCookieSecurityDefinition(t *testing.T) {
+	Convey("Given a name and an apikey name", t, func() {
+		Convey("When newAPIKeyCookieSecurityDefinition method is called", func() {
+			apiKeyCookieSecurityDefinition := newAPIKeyCookieSecurityDefinition("cookieAuth", "session")
+			Convey("Then the apiKeyCookieSecurityDefinition should comply with SpecSecurityDefinition interface", func() {
+				var _ SpecSecurityDefinition = apiKeyCookieSecurityDefinition
+			})
+			Convey("And the security definition should be of type api key", func() {
+				So(apiKeyCookieSecurityDefinition.getName(), ShouldEqual, "cookieAuth")
+				So(apiKeyCookieSecurityDefinition.getType(), ShouldEqual, securityDefinitionAPIKey)
+				So(apiKeyCookieSecurityDefinition.getTerraformConfigurationName(), ShouldEqual, "cookie_auth")
+				So(apiKeyCookieSecurityDefinition.buildValue("someValue"), ShouldEqual, "someValue")
+			})
+			Convey("And the api key should contain the cookie name and location", func() {
+				So(apiKeyCookieSecurityDefinition.getAPIKey().Name, ShouldEqual, "session")
+				So(apiKeyCookieSecurityDefinition.getAPIKey().In, ShouldEqual, inCookie)
+			})
+		})
+	})
+}
+
+func TestAPIKeyCookieSecurityDefinitionValidate(t *testing.T) {
+	Convey("Given an APIKeyCookieSecurityDefinition with a security definition name and an apiKeyName", t, func() {
+		apiKeyCookieSecurityDefinition := newAPIKeyCookieSecurityDefinition("cookie_auth", "session")
+		Convey("When validate method is called", func() {
+			err := apiKeyCookieSecurityDefinition.validate()
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+	Convey("Given an APIKeyCookieSecurityDefinition with an empty apiKeyName", t, func() {
+		apiKeyCookieSecurityDefinition := newAPIKeyCookieSecurityDefinition("cookie_auth", "")
+		Convey("When validate method is called", func() {
+			err := apiKeyCookieSecurityDefinition.validate()
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "specAPIKeyCookieSecurityDefinition missing mandatory apiKey name")
+			})
+		})
+	})
+}
@@ -0,0 +1,76 @@
+package openapi
+
+import (
+	"fmt"
+
+	"github.com/dikhan/terraform-provider-openapi/openapi/terraformutils"
+)
+
+const awsSigV4AccessKeyIDSuffix = "_access_key_id"
+const awsSigV4SecretAccessKeySuffix = "_secret_access_key"
+const awsSigV4SessionTokenSuffix = "_session_token"
+
+// specAWSSigV4SecurityDefinition defines a security definition that requires the requests to be signed with AWS Signature
+// Version 4 (e.g. API Gateway APIs protected by IAM). The AWS credentials are exposed in the provider configuration as
+// three properties (the access key id, the secret access key and the optional session token). The service and region
+// the requests are signed for are optional, when not set they are resolved from the provider region (multi-region
+// providers) or the host of the API
+type specAWSSigV4SecurityDefinition struct {
+	name    string
+	service string
+	region  string
+}
+
+// newAWSSigV4SecurityDefinition constructs a SpecSecurityDefinition for AWS Signature Version 4. The secDefName value is
+// the identifier of the security definition
+func newAWSSigV4SecurityDefinition(secDefName, service, region string) specAWSSigV4SecurityDefinition {
+	return specAWSSigV4SecurityDefinition{
+		name:    secDefName,
+		service: service,
+		region:  region,
+	}
+}
+
+func (s specAWSSigV4SecurityDefinition) getName() string {
+	return s.name
+}
+
+func (s specAWSSigV4SecurityDefinition) getType() securityDefinitionType {
+	return securityDefinitionAWSSigV4
+}
+
+func (s specAWSSigV4SecurityDefinition) getTerraformConfigurationName() string {
+	return terraformutils.ConvertToTerraformCompliantName(s.name)
+}
+
+// getAccessKeyIDTerraformConfigurationName returns the name of the provider property containing the AWS access key id
+func (s specAWSSigV4SecurityDefinition) getAccessKeyIDTerraformConfigurationName() string {
+	return s.getTerraformConfigurationName() + awsSigV4AccessKeyIDSuffix
+}
+
+// getSecretAccessKeyTerraformConfigurationName returns the name of the provider property containing the AWS secret access key
+func (s specAWSSigV4SecurityDefinition) getSecretAccessKeyTerraformConfigurationName() string {
+	return s.getTerraformConfigurationName() + awsSigV4SecretAccessKeySuffix
+}
+
+// getSessionTokenTerraformConfigurationName returns the name of the provider property containing the AWS session token,
+// only needed for temporary credentials
+func (s specAWSSigV4SecurityDefinition) getSessionTokenTerraformConfigurationName() string {
+	return s.getTerraformConfigurationName() + awsSigV4SessionTokenSuffix
+}
+
+func (s specAWSSigV4SecurityDefinition) getAPIKey() specAPIKey {
+	return newAPIKeyHeader(authorizationHeader)
+}
+
+// buildValue returns the value as is, the Authorization header value is computed when the requests are signed
+func (s specAWSSigV4SecurityDefinition) buildValue(value string) string {
+	return value
+}
+
+func (s specAWSSigV4SecurityDefinition) validate() error {
+	if s.name == "" {
+		return fmt.Errorf("specAWSSigV4SecurityDefinition missing mandatory security definition name")
+	}
+	return nil
+}
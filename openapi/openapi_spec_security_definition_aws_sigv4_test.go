@@ -0,0 +1,21 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAWSSigV4SecurityDefinition(t *testing.T) {
+	var _ SpecSecurityDefinition = newAWSSigV4SecurityDefinition("awsIAM", "", "")
+
+	secDef := newAWSSigV4SecurityDefinition("awsIAM", "execute-api", "us-west-2")
+	assert.Equal(t, "awsIAM", secDef.getName())
+	assert.Equal(t, securityDefinitionAWSSigV4, secDef.getType())
+	assert.Equal(t, "aws_iam", secDef.getTerraformConfigurationName())
+	assert.Equal(t, []string{"aws_iam_access_key_id", "aws_iam_secret_access_key", "aws_iam_session_token"}, getSecurityDefinitionPropertyNames(secDef))
+	assert.Equal(t, newAPIKeyHeader(authorizationHeader), secDef.getAPIKey())
+	assert.Equal(t, "value", secDef.buildValue("value"))
+	assert.NoError(t, secDef.validate())
+	assert.EqualError(t, newAWSSigV4SecurityDefinition("", "", "").validate(), "specAWSSigV4SecurityDefinition missing mandatory security definition name")
+}
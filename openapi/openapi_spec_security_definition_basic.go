@@ -0,0 +1,70 @@
+package openapi
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/dikhan/terraform-provider-openapi/openapi/terraformutils"
+)
+
+const basicScheme = "Basic"
+
+const basicAuthUsernameSuffix = "_username"
+const basicAuthPasswordSuffix = "_password"
+
+// specBasicAuthSecurityDefinition defines a security definition of type basic. The credentials are exposed in the provider
+// configuration as two properties (the username and the password) which are sent in the Authorization header using the
+// Basic authentication scheme
+type specBasicAuthSecurityDefinition struct {
+	name string
+}
+
+// newBasicAuthSecurityDefinition constructs a SpecSecurityDefinition of basic type. The secDefName value is the identifier
+// of the security definition
+func newBasicAuthSecurityDefinition(secDefName string) specBasicAuthSecurityDefinition {
+	return specBasicAuthSecurityDefinition{secDefName}
+}
+
+func (s specBasicAuthSecurityDefinition) getName() string {
+	return s.name
+}
+
+func (s specBasicAuthSecurityDefinition) getType() securityDefinitionType {
+	return securityDefinitionBasic
+}
+
+func (s specBasicAuthSecurityDefinition) getTerraformConfigurationName() string {
+	return terraformutils.ConvertToTerraformCompliantName(s.name)
+}
+
+// getUsernameTerraformConfigurationName returns the name of the provider property containing the username
+func (s specBasicAuthSecurityDefinition) getUsernameTerraformConfigurationName() string {
+	return s.getTerraformConfigurationName() + basicAuthUsernameSuffix
+}
+
+// getPasswordTerraformConfigurationName returns the name of the provider property containing the password
+func (s specBasicAuthSecurityDefinition) getPasswordTerraformConfigurationName() string {
+	return s.getTerraformConfigurationName() + basicAuthPasswordSuffix
+}
+
+func (s specBasicAuthSecurityDefinition) getAPIKey() specAPIKey {
+	return newAPIKeyHeader(authorizationHeader)
+}
+
+// buildValue expects the credentials in the form username:password and returns them encoded as per the Basic
+// authentication scheme
+func (s specBasicAuthSecurityDefinition) buildValue(credentials string) string {
+	return fmt.Sprintf("%s %s", basicScheme, base64.StdEncoding.EncodeToString([]byte(credentials)))
+}
+
+// buildCredentials returns the credentials in the form expected by buildValue
+func (s specBasicAuthSecurityDefinition) buildCredentials(username, password string) string {
+	return fmt.Sprintf("%s:%s", username, password)
+}
+
+func (s specBasicAuthSecurityDefinition) validate() error {
+	if s.name == "" {
+		return fmt.Errorf("specBasicAuthSecurityDefinition missing mandatory security definition name")
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasicAuthSecurityDefinition(t *testing.T) {
+	var _ SpecSecurityDefinition = newBasicAuthSecurityDefinition("basicAuth")
+
+	secDef := newBasicAuthSecurityDefinition("basicAuth")
+	assert.Equal(t, "basicAuth", secDef.getName())
+	assert.Equal(t, securityDefinitionBasic, secDef.getType())
+	assert.Equal(t, "basic_auth", secDef.getTerraformConfigurationName())
+	assert.Equal(t, "basic_auth_username", secDef.getUsernameTerraformConfigurationName())
+	assert.Equal(t, "basic_auth_password", secDef.getPasswordTerraformConfigurationName())
+	assert.Equal(t, []string{"basic_auth_username", "basic_auth_password"}, getSecurityDefinitionPropertyNames(secDef))
+	assert.Equal(t, newAPIKeyHeader(authorizationHeader), secDef.getAPIKey())
+	assert.NoError(t, secDef.validate())
+	assert.EqualError(t, newBasicAuthSecurityDefinition("").validate(), "specBasicAuthSecurityDefinition missing mandatory security definition name")
+}
+
+func TestBasicAuthSecurityDefinitionBuildValue(t *testing.T) {
+	testCases := []struct {
+		name          string
+		username      string
+		password      string
+		expectedValue string
+	}{
+		{name: "username and password", username: "Aladdin", password: "open sesame", expectedValue: "Basic QWxhZGRpbjpvcGVuIHNlc2FtZQ=="},
+		{name: "password containing a colon", username: "user", password: "pass:word", expectedValue: "Basic dXNlcjpwYXNzOndvcmQ="},
+		{name: "empty password", username: "user", password: "", expectedValue: "Basic dXNlcjo="},
+	}
+	secDef := newBasicAuthSecurityDefinition("basicAuth")
+	for _, tc := range testCases {
+		value := secDef.buildValue(secDef.buildCredentials(tc.username, tc.password))
+		assert.Equal(t, tc.expectedValue, value, tc.name)
+	}
+}
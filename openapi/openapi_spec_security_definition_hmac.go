@@ -0,0 +1,83 @@
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dikhan/terraform-provider-openapi/openapi/terraformutils"
+)
+
+const hmacDefaultSignatureHeader = "X-Signature"
+const hmacDefaultTimestampHeader = "X-Timestamp"
+const hmacDefaultAlgorithm = "sha256"
+
+// specHMACSecurityDefinition defines a security definition that requires the requests to be signed with an HMAC of the
+// request timestamp and body, computed with the secret provided in the provider configuration (exposed as one property
+// named after the security definition). The names of the headers where the signature and timestamp are sent, the hash
+// algorithm and the prefix of the signature value (e.g. sha256=) are configurable
+type specHMACSecurityDefinition struct {
+	name            string
+	signatureHeader string
+	timestampHeader string
+	algorithm       string
+	signaturePrefix string
+}
+
+// newHMACSecurityDefinition constructs a SpecSecurityDefinition for HMAC request signing. The secDefName value is the
+// identifier of the security definition; the headers and algorithm default to X-Signature, X-Timestamp and sha256
+// respectively when not provided
+func newHMACSecurityDefinition(secDefName, signatureHeader, timestampHeader, algorithm, signaturePrefix string) specHMACSecurityDefinition {
+	if signatureHeader == "" {
+		signatureHeader = hmacDefaultSignatureHeader
+	}
+	if timestampHeader == "" {
+		timestampHeader = hmacDefaultTimestampHeader
+	}
+	if algorithm == "" {
+		algorithm = hmacDefaultAlgorithm
+	}
+	return specHMACSecurityDefinition{
+		name:            secDefName,
+		signatureHeader: signatureHeader,
+		timestampHeader: timestampHeader,
+		algorithm:       strings.ToLower(algorithm),
+		signaturePrefix: signaturePrefix,
+	}
+}
+
+func (s specHMACSecurityDefinition) getName() string {
+	return s.name
+}
+
+func (s specHMACSecurityDefinition) getType() securityDefinitionType {
+	return securityDefinitionHMAC
+}
+
+func (s specHMACSecurityDefinition) getTerraformConfigurationName() string {
+	return terraformutils.ConvertToTerraformCompliantName(s.name)
+}
+
+func (s specHMACSecurityDefinition) getAPIKey() specAPIKey {
+	return newAPIKeyHeader(s.signatureHeader)
+}
+
+// buildValue returns the value as is, the signature header value is computed when the requests are signed
+func (s specHMACSecurityDefinition) buildValue(value string) string {
+	return value
+}
+
+func (s specHMACSecurityDefinition) validate() error {
+	if s.name == "" {
+		return fmt.Errorf("specHMACSecurityDefinition missing mandatory security definition name")
+	}
+	if _, supported := hmacHashFunctions[s.algorithm]; !supported {
+		var algorithms []string
+		for algorithm := range hmacHashFunctions {
+			algorithms = append(algorithms, algorithm)
+		}
+		sort.Strings(algorithms)
+		return fmt.Errorf("specHMACSecurityDefinition '%s' algorithm '%s' not supported, supported algorithms: %s", s.name, s.algorithm, strings.Join(algorithms, ", "))
+	}
+	return nil
+}
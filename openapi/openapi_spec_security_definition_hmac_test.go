@@ -0,0 +1,34 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHMACSecurityDefinition(t *testing.T) {
+	var _ SpecSecurityDefinition = newHMACSecurityDefinition("hmacAuth", "", "", "", "")
+
+	secDef := newHMACSecurityDefinition("hmacAuth", "", "", "", "")
+	assert.Equal(t, "hmacAuth", secDef.getName())
+	assert.Equal(t, securityDefinitionHMAC, secDef.getType())
+	assert.Equal(t, "hmac_auth", secDef.getTerraformConfigurationName())
+	assert.Equal(t, []string{"hmac_auth"}, getSecurityDefinitionPropertyNames(secDef))
+	assert.Equal(t, newAPIKeyHeader("X-Signature"), secDef.getAPIKey())
+	assert.Equal(t, "X-Timestamp", secDef.timestampHeader)
+	assert.Equal(t, "sha256", secDef.algorithm)
+	assert.Equal(t, "value", secDef.buildValue("value"))
+	assert.NoError(t, secDef.validate())
+
+	assert.EqualError(t, newHMACSecurityDefinition("", "", "", "", "").validate(), "specHMACSecurityDefinition missing mandatory security definition name")
+	assert.EqualError(t, newHMACSecurityDefinition("hmacAuth", "", "", "md5", "").validate(), "specHMACSecurityDefinition 'hmacAuth' algorithm 'md5' not supported, supported algorithms: sha1, sha256, sha512")
+}
+
+func TestCreateAPIKeyAuthenticator_HMAC(t *testing.T) {
+	authenticator := createAPIKeyAuthenticator(newHMACSecurityDefinition("hmacAuth", "X-Hub-Signature", "", "sha1", "sha1="), "secret")
+	assert.Equal(t, authTypeHMAC, authenticator.getType())
+	hmacAuthenticator := authenticator.(hmacAuthenticator)
+	assert.Equal(t, "X-Hub-Signature", hmacAuthenticator.signatureHeader)
+	assert.Equal(t, "sha1", hmacAuthenticator.algorithm)
+	assert.Equal(t, "sha1=", hmacAuthenticator.signaturePrefix)
+}
@@ -0,0 +1,23 @@
+package openapi
+
+// secondaryKeyPropertySuffix is the suffix of the provider property holding the secondary api key of the security
+// definitions that support key rotation
+const secondaryKeyPropertySuffix = "_secondary"
+
+// specSecondaryKeySecurityDefinition decorates an apiKey security definition (header, query or cookie) with the
+// x-terraform-auth-secondary-key extension enabled, which accepts a secondary api key the provider fails over to when the
+// API rejects the primary one. This enables rotating the api keys without downtime, even during long applies
+type specSecondaryKeySecurityDefinition struct {
+	SpecSecurityDefinition
+}
+
+// newSecondaryKeySecurityDefinition returns the given security definition supporting a secondary api key
+func newSecondaryKeySecurityDefinition(securityDefinition SpecSecurityDefinition) specSecondaryKeySecurityDefinition {
+	return specSecondaryKeySecurityDefinition{securityDefinition}
+}
+
+// getSecondaryTerraformConfigurationName returns the name of the provider property holding the secondary api key, which is
+// the terraform configuration name of the security definition followed by the _secondary suffix
+func (s specSecondaryKeySecurityDefinition) getSecondaryTerraformConfigurationName() string {
+	return s.getTerraformConfigurationName() + secondaryKeyPropertySuffix
+}
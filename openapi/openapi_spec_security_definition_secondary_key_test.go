@@ -0,0 +1,28 @@
+package openapi
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSecondaryKeySecurityDefinition(t *testing.T) {
+	Convey("Given a security definition supporting a secondary api key", t, func() {
+		securityDefinition := newSecondaryKeySecurityDefinition(newAPIKeyHeaderSecurityDefinition("apiKeyAuth", "X-API-Key"))
+		Convey("Then it should comply with the SpecSecurityDefinition interface and keep the values of the decorated security definition", func() {
+			var _ SpecSecurityDefinition = securityDefinition
+			So(securityDefinition.getName(), ShouldEqual, "apiKeyAuth")
+			So(securityDefinition.getType(), ShouldEqual, securityDefinitionAPIKey)
+			So(securityDefinition.getAPIKey().Name, ShouldEqual, "X-API-Key")
+			So(securityDefinition.getAPIKey().In, ShouldEqual, inHeader)
+			So(securityDefinition.validate(), ShouldBeNil)
+		})
+		Convey("When getSecondaryTerraformConfigurationName method is called", func() {
+			name := securityDefinition.getSecondaryTerraformConfigurationName()
+			Convey("Then the name returned should be the terraform configuration name followed by the _secondary suffix", func() {
+				So(securityDefinition.getTerraformConfigurationName(), ShouldEqual, "api_key_auth")
+				So(name, ShouldEqual, "api_key_auth_secondary")
+			})
+		})
+	})
+}
@@ -17,8 +17,27 @@ type securityDefinitionType string
 const (
 	securityDefinitionAPIKey             securityDefinitionType = "apiKey"
 	securityDefinitionAPIKeyRefreshToken securityDefinitionType = "apiKeyRefreshToken"
+	securityDefinitionBasic              securityDefinitionType = "basic"
+	securityDefinitionAWSSigV4           securityDefinitionType = "awsSigV4"
+	securityDefinitionHMAC               securityDefinitionType = "hmac"
 )
 
+// getSecurityDefinitionPropertyNames returns the names of the provider properties holding the credentials of the given
+// security definition. Basic auth security definitions need two properties (username and password) and AWS Signature
+// Version 4 ones three (access key id, secret access key and session token) and the ones supporting a secondary api key
+// two (primary and secondary api keys) whereas the rest of them only need one named after the security definition
+func getSecurityDefinitionPropertyNames(securityDefinition SpecSecurityDefinition) []string {
+	switch secDef := securityDefinition.(type) {
+	case specBasicAuthSecurityDefinition:
+		return []string{secDef.getUsernameTerraformConfigurationName(), secDef.getPasswordTerraformConfigurationName()}
+	case specAWSSigV4SecurityDefinition:
+		return []string{secDef.getAccessKeyIDTerraformConfigurationName(), secDef.getSecretAccessKeyTerraformConfigurationName(), secDef.getSessionTokenTerraformConfigurationName()}
+	case specSecondaryKeySecurityDefinition:
+		return []string{secDef.getTerraformConfigurationName(), secDef.getSecondaryTerraformConfigurationName()}
+	}
+	return []string{securityDefinition.getTerraformConfigurationName()}
+}
+
 // SpecSecurityDefinition defines the behaviour expected for security definition implementations. This interface creates
 // an abstraction between the swagger security definitions and the openapi provider removing dependencies in external
 // libraries
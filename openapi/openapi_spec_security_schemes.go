@@ -1,22 +1,71 @@
 package openapi
 
-import "github.com/dikhan/terraform-provider-openapi/openapi/terraformutils"
+import (
+	"sort"
+	"strings"
+
+	"github.com/dikhan/terraform-provider-openapi/openapi/terraformutils"
+)
 
 // SpecSecuritySchemes groups a list of SpecSecurityScheme
 type SpecSecuritySchemes []SpecSecurityScheme
 
+// securityRequirementNameSeparator joins the names of the security schemes that must be satisfied together when building
+// the name of a security requirement (e.g. apikey_auth+hmac_auth)
+const securityRequirementNameSeparator = "+"
+
 func createSecuritySchemes(securitySchemes []map[string][]string) SpecSecuritySchemes {
-	schemes := SpecSecuritySchemes{}
+	// Choosing the first set of security schemes as defined by the service provider. The order defines the priority
+	// by which security schemes are selected, in this case the first set. Hence, disregarding the rest of security
+	// schemes (if defined)
+	for _, securityRequirement := range createSecurityRequirements(securitySchemes) {
+		return securityRequirement
+	}
+	return SpecSecuritySchemes{}
+}
+
+// createSecurityRequirements returns the alternative security requirements (OR semantics) in order of appearance, each of
+// them containing the security schemes that must be satisfied together
+func createSecurityRequirements(securitySchemes []map[string][]string) []SpecSecuritySchemes {
+	var securityRequirements []SpecSecuritySchemes
 	for _, securityScheme := range securitySchemes {
+		schemes := SpecSecuritySchemes{}
+		// All the security schemes of a security requirement must be satisfied (AND semantics). The schemes are sorted by
+		// name so they are always applied to the requests in the same order
+		var securitySchemeNames []string
 		for securitySchemeName := range securityScheme {
-			schemes = append(schemes, SpecSecurityScheme{Name: securitySchemeName})
+			securitySchemeNames = append(securitySchemeNames, securitySchemeName)
+		}
+		sort.Strings(securitySchemeNames)
+		for _, securitySchemeName := range securitySchemeNames {
+			scopes := securityScheme[securitySchemeName]
+			specSecurityScheme := SpecSecurityScheme{Name: securitySchemeName}
+			for _, scope := range scopes {
+				if scope != "" {
+					specSecurityScheme.Scopes = append(specSecurityScheme.Scopes, scope)
+				}
+			}
+			schemes = append(schemes, specSecurityScheme)
 		}
-		// Choosing the first set of security schemes as defined by the service provider. The order defines the priority
-		// by which security schemes are selected, in this case the first set. Hence, disregarding the rest of security
-		// schemes (if defined)
-		break
+		securityRequirements = append(securityRequirements, schemes)
+	}
+	return securityRequirements
+}
+
+// securityRequirementNone is the name of the empty security requirement, which allows calling the API anonymously
+const securityRequirementNone = "none"
+
+// getName returns the name identifying the security requirement, made of the terraform configuration names of its
+// security schemes (e.g. apikey_auth+hmac_auth)
+func (s SpecSecuritySchemes) getName() string {
+	if len(s) == 0 {
+		return securityRequirementNone
+	}
+	var names []string
+	for _, securityScheme := range s {
+		names = append(names, securityScheme.getTerraformConfigurationName())
 	}
-	return schemes
+	return strings.Join(names, securityRequirementNameSeparator)
 }
 
 func (s SpecSecuritySchemes) securitySchemeExists(secDef SpecSecurityDefinition) bool {
@@ -32,6 +81,8 @@ func (s SpecSecuritySchemes) securitySchemeExists(secDef SpecSecurityDefinition)
 // and the scheme that will be used by the OpenAPI Terraform provider when making API calls to the backend
 type SpecSecurityScheme struct {
 	Name string
+	// Scopes contains the scopes the credentials must have been granted to call the operation (e.g. OAuth scopes)
+	Scopes []string
 }
 
 func (o *SpecSecurityScheme) getTerraformConfigurationName() string {
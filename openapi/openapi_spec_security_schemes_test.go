@@ -79,6 +79,9 @@ func TestCreateSecuritySchemes(t *testing.T) {
 				So(specSecuritySchemes, ShouldContain, SpecSecurityScheme{Name: "secDef1"})
 				So(specSecuritySchemes, ShouldContain, SpecSecurityScheme{Name: "secDef2"})
 			})
+			Convey("And the specSecuritySchemes should be sorted by name", func() {
+				So(specSecuritySchemes, ShouldResemble, SpecSecuritySchemes{{Name: "secDef1"}, {Name: "secDef2"}})
+			})
 		})
 	})
 
@@ -106,4 +109,66 @@ func TestCreateSecuritySchemes(t *testing.T) {
 			})
 		})
 	})
+
+	Convey("Given a map of securitySchemes with scopes", t, func() {
+		securitySchemes := []map[string][]string{
+			{
+				"secDef1": {"cdns:read", "cdns:write"},
+				"secDef2": {""},
+			},
+		}
+		Convey("When createSecuritySchemes method is called with the securitySchemes", func() {
+			specSecuritySchemes := createSecuritySchemes(securitySchemes)
+			Convey("Then the specSecuritySchemes should contain the scopes required by each security scheme", func() {
+				So(specSecuritySchemes, ShouldContain, SpecSecurityScheme{Name: "secDef1", Scopes: []string{"cdns:read", "cdns:write"}})
+				So(specSecuritySchemes, ShouldContain, SpecSecurityScheme{Name: "secDef2"})
+			})
+		})
+	})
+}
+
+func TestCreateSecurityRequirements(t *testing.T) {
+	Convey("Given a list of alternative security requirements (OR semantics)", t, func() {
+		securitySchemes := []map[string][]string{
+			{
+				"secDef2": {},
+				"secDef1": {"cdns:read"},
+			},
+			{
+				"secDef3": {},
+			},
+			{},
+		}
+		Convey("When createSecurityRequirements method is called with the securitySchemes", func() {
+			securityRequirements := createSecurityRequirements(securitySchemes)
+			Convey("Then all the security requirements should be returned in order of appearance with their schemes sorted by name", func() {
+				So(securityRequirements, ShouldResemble, []SpecSecuritySchemes{
+					{{Name: "secDef1", Scopes: []string{"cdns:read"}}, {Name: "secDef2"}},
+					{{Name: "secDef3"}},
+					{},
+				})
+			})
+		})
+	})
+}
+
+func TestSpecSecuritySchemesGetName(t *testing.T) {
+	Convey("Given a security requirement with multiple security schemes", t, func() {
+		securitySchemes := SpecSecuritySchemes{{Name: "apiKeyAuth"}, {Name: "hmac_auth"}}
+		Convey("When getName method is called", func() {
+			name := securitySchemes.getName()
+			Convey("Then the name should contain the terraform compliant names of the security schemes", func() {
+				So(name, ShouldEqual, "api_key_auth+hmac_auth")
+			})
+		})
+	})
+	Convey("Given an empty security requirement", t, func() {
+		securitySchemes := SpecSecuritySchemes{}
+		Convey("When getName method is called", func() {
+			name := securitySchemes.getName()
+			Convey("Then the name should be none", func() {
+				So(name, ShouldEqual, securityRequirementNone)
+			})
+		})
+	})
 }
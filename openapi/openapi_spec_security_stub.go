@@ -1,9 +1,11 @@
 package openapi
 
 type specSecurityStub struct {
-	securityDefinitions   *SpecSecurityDefinitions
-	globalSecuritySchemes SpecSecuritySchemes
-	error                 error
+	securityDefinitions        *SpecSecurityDefinitions
+	globalSecuritySchemes      SpecSecuritySchemes
+	globalSecurityRequirements []SpecSecuritySchemes
+	introspectionURLs          map[string]string
+	error                      error
 }
 
 func (s *specSecurityStub) GetAPIKeySecurityDefinitions() (*SpecSecurityDefinitions, error) {
@@ -19,3 +21,20 @@ func (s *specSecurityStub) GetGlobalSecuritySchemes() (SpecSecuritySchemes, erro
 	}
 	return s.globalSecuritySchemes, nil
 }
+
+func (s *specSecurityStub) GetGlobalSecurityRequirements() ([]SpecSecuritySchemes, error) {
+	if s.error != nil {
+		return nil, s.error
+	}
+	if s.globalSecurityRequirements == nil && len(s.globalSecuritySchemes) > 0 {
+		return []SpecSecuritySchemes{s.globalSecuritySchemes}, nil
+	}
+	return s.globalSecurityRequirements, nil
+}
+
+func (s *specSecurityStub) GetTokenIntrospectionURLs() (map[string]string, error) {
+	if s.error != nil {
+		return nil, s.error
+	}
+	return s.introspectionURLs, nil
+}
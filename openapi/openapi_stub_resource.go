@@ -2,17 +2,23 @@ package openapi
 
 // specStubResource is a stub implementation of SpecResource interface which is used for testing purposes
 type specStubResource struct {
-	name                    string
-	host                    string
-	path                    string
-	shouldIgnore            bool
-	schemaDefinition        *specSchemaDefinition
-	resourceGetOperation    *specResourceOperation
-	resourcePostOperation   *specResourceOperation
-	resourceListOperation   *specResourceOperation
-	resourcePutOperation    *specResourceOperation
-	resourceDeleteOperation *specResourceOperation
-	timeouts                *specTimeouts
+	name                     string
+	host                     string
+	scheme                   string
+	path                     string
+	shouldIgnore             bool
+	ignoreDataSourceInstance bool
+	schemaDefinition         *specSchemaDefinition
+	resourceGetOperation     *specResourceOperation
+	resourcePostOperation    *specResourceOperation
+	resourceListOperation    *specResourceOperation
+	resourcePutOperation     *specResourceOperation
+	resourceDeleteOperation  *specResourceOperation
+	timeouts                 *specTimeouts
+	consoleURLTemplate       string
+	importOnly               bool
+	autoImport               bool
+	compositeID              []string
 
 	parentResourceNames    []string
 	parentPropertyNames    []string
@@ -62,6 +68,8 @@ func (s *specStubResource) getResourceSchema() (*specSchemaDefinition, error) {
 
 func (s *specStubResource) shouldIgnoreResource() bool { return s.shouldIgnore }
 
+func (s *specStubResource) shouldIgnoreDataSourceInstance() bool { return s.ignoreDataSourceInstance }
+
 func (s *specStubResource) getResourceOperations() specResourceOperations {
 	return specResourceOperations{
 		List:   s.resourceListOperation,
@@ -76,10 +84,22 @@ func (s *specStubResource) getTimeouts() (*specTimeouts, error) {
 	return s.timeouts, nil
 }
 
+func (s *specStubResource) getConsoleURLTemplate() string { return s.consoleURLTemplate }
+
+func (s *specStubResource) isImportOnly() bool { return s.importOnly }
+
+func (s *specStubResource) isAutoImportEnabled() bool { return s.autoImport }
+
+func (s *specStubResource) getCompositeID() []string { return s.compositeID }
+
 func (s *specStubResource) getHost() (string, error) {
 	return s.host, nil
 }
 
+func (s *specStubResource) getScheme() (string, error) {
+	return s.scheme, nil
+}
+
 func (s *specStubResource) getParentResourceInfo() *parentResourceInfo {
 	subRes := parentResourceInfo{}
 	if len(s.parentResourceNames) > 0 && s.fullParentResourceName != "" {
@@ -1,7 +1,6 @@
 package openapi
 
 import (
-	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -10,8 +9,8 @@ import (
 	"github.com/go-openapi/spec"
 )
 
-const extTfProviderMultiRegionFQDN = "x-terraform-provider-multiregion-fqdn"
-const extTfProviderRegions = "x-terraform-provider-regions"
+const httpScheme = "http"
+const httpsScheme = "https"
 
 type specV2BackendConfiguration struct {
 	openAPIDocumentURL string
@@ -115,21 +114,31 @@ func (o specV2BackendConfiguration) getProviderRegions() ([]string, error) {
 	return regions, nil
 }
 
+// allowsRawSlashesInPathParameters checks whether the root level extension 'x-terraform-path-parameters-raw-slashes' is
+// set to true, in which case the forward slashes in the path parameter values are not URL encoded
+func (o specV2BackendConfiguration) allowsRawSlashesInPathParameters() bool {
+	rawSlashes, _ := o.spec.Extensions.GetBool(extTfPathParametersRawSlashes)
+	return rawSlashes
+}
+
 func (o specV2BackendConfiguration) getBasePath() string {
 	return o.spec.BasePath
 }
 
+// getHTTPScheme returns the scheme used when calling the API. HTTPS is always preferred: it is used if the swagger file
+// specifies it or does not specify any scheme at all, and HTTP is only used if it is the only supported scheme specified
 func (o specV2BackendConfiguration) getHTTPScheme() (string, error) {
 	var defaultScheme string
 
 	if len(o.spec.Schemes) == 0 {
-		return "", errors.New("no schemes specified - must use http or https")
+		log.Printf("[WARN] schemes field not specified in the swagger configuration, falling back to '%s'", httpsScheme)
+		return httpsScheme, nil
 	}
 	for _, s := range o.spec.Schemes {
-		if s == "https" {
+		if s == httpsScheme {
 			return s, nil
 		}
-		if s == "http" {
+		if s == httpScheme {
 			defaultScheme = s
 		}
 	}
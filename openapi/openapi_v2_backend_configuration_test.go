@@ -564,6 +564,44 @@ func TestGetBasePath(t *testing.T) {
 	})
 }
 
+func TestAllowsRawSlashesInPathParameters(t *testing.T) {
+	Convey("Given a specV2BackendConfiguration with the 'x-terraform-path-parameters-raw-slashes' extension set to true", t, func() {
+		spec := &spec.Swagger{
+			VendorExtensible: spec.VendorExtensible{
+				Extensions: spec.Extensions{
+					extTfPathParametersRawSlashes: true,
+				},
+			},
+			SwaggerProps: spec.SwaggerProps{
+				Swagger: "2.0",
+				Host:    "www.some-backend.com",
+			},
+		}
+		specV2BackendConfiguration, _ := newOpenAPIBackendConfigurationV2(spec, "www.domain.com")
+		Convey("When allowsRawSlashesInPathParameters method is called", func() {
+			rawSlashes := specV2BackendConfiguration.allowsRawSlashesInPathParameters()
+			Convey("Then the value returned should be true", func() {
+				So(rawSlashes, ShouldBeTrue)
+			})
+		})
+	})
+	Convey("Given a specV2BackendConfiguration without the 'x-terraform-path-parameters-raw-slashes' extension", t, func() {
+		spec := &spec.Swagger{
+			SwaggerProps: spec.SwaggerProps{
+				Swagger: "2.0",
+				Host:    "www.some-backend.com",
+			},
+		}
+		specV2BackendConfiguration, _ := newOpenAPIBackendConfigurationV2(spec, "www.domain.com")
+		Convey("When allowsRawSlashesInPathParameters method is called", func() {
+			rawSlashes := specV2BackendConfiguration.allowsRawSlashesInPathParameters()
+			Convey("Then the value returned should be false", func() {
+				So(rawSlashes, ShouldBeFalse)
+			})
+		})
+	})
+}
+
 func TestGetHTTPSchemes(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -574,7 +612,7 @@ func TestGetHTTPSchemes(t *testing.T) {
 		{name: "both http and https schemes are configured", inputSchemes: []string{"http", "https"}, expectedScheme: "https"},
 		{name: "mix of schemes configured including supported ones without https", inputSchemes: []string{"http", "ws"}, expectedScheme: "http"},
 		{name: "mix of schemes configured including supported ones with https", inputSchemes: []string{"http", "ws", "https"}, expectedScheme: "https"},
-		{name: "none http or https schemes are configured", inputSchemes: []string{}, expectedError: "no schemes specified - must use http or https"},
+		{name: "none http or https schemes are configured", inputSchemes: []string{}, expectedScheme: "https"},
 		{name: "none of the schemes configured are supported", inputSchemes: []string{"ws"}, expectedError: "specified schemes [ws] are not supported - must use http or https"},
 	}
 	for _, tc := range testCases {
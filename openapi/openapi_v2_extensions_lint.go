@@ -0,0 +1,145 @@
+package openapi
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+
+	"github.com/go-openapi/spec"
+)
+
+// extensionsLinter walks through the OpenAPI document checking the extensions used against the extension registry, so
+// typos (e.g. x-terraform-imutable), extensions used in the wrong place (e.g. x-terraform-resource-name in a property)
+// and values of the wrong type are reported instead of being silently ignored
+type extensionsLinter struct {
+	registry *extensionRegistry
+	issues   []error
+}
+
+// lintExtensions returns the issues found in the extensions of the given document. The document is expected to not be
+// expanded yet, otherwise the issues in the definitions would be reported as many times as they are referenced
+func lintExtensions(document *spec.Swagger) []error {
+	l := &extensionsLinter{registry: supportedExtensions}
+	l.lint("document", ExtensionLocationDocument, document.Extensions)
+	for _, name := range sortedKeys(document.SecurityDefinitions) {
+		l.lint(fmt.Sprintf("security definition '%s'", name), ExtensionLocationSecurityDefinition, document.SecurityDefinitions[name].Extensions)
+	}
+	for _, name := range sortedKeys(document.Parameters) {
+		l.lintParameter(fmt.Sprintf("parameter '%s'", name), document.Parameters[name])
+	}
+	for _, name := range sortedKeys(document.Responses) {
+		l.lintResponse(fmt.Sprintf("response '%s'", name), document.Responses[name])
+	}
+	for _, name := range sortedKeys(document.Definitions) {
+		l.lintSchema(fmt.Sprintf("definition '%s'", name), document.Definitions[name])
+	}
+	if document.Paths != nil {
+		for _, path := range sortedKeys(document.Paths.Paths) {
+			l.lintPathItem(path, document.Paths.Paths[path])
+		}
+	}
+	return l.issues
+}
+
+// logExtensionsLintIssues logs the issues found in the extensions of the given document as warnings
+func logExtensionsLintIssues(document *spec.Swagger) {
+	for _, issue := range lintExtensions(document) {
+		log.Printf("[WARN] %s", issue)
+	}
+}
+
+func (l *extensionsLinter) lint(context string, location ExtensionLocation, extensions spec.Extensions) {
+	for _, err := range l.registry.validateAll(location, extensions) {
+		l.issues = append(l.issues, fmt.Errorf("%s: %s", context, err))
+	}
+}
+
+func (l *extensionsLinter) lintPathItem(path string, pathItem spec.PathItem) {
+	l.lint(fmt.Sprintf("path '%s'", path), ExtensionLocationPath, pathItem.Extensions)
+	for _, parameter := range pathItem.Parameters {
+		l.lintParameter(fmt.Sprintf("path '%s' parameter '%s'", path, parameter.Name), parameter)
+	}
+	operations := []struct {
+		method    string
+		operation *spec.Operation
+	}{
+		{"GET", pathItem.Get},
+		{"PUT", pathItem.Put},
+		{"POST", pathItem.Post},
+		{"DELETE", pathItem.Delete},
+		{"OPTIONS", pathItem.Options},
+		{"HEAD", pathItem.Head},
+		{"PATCH", pathItem.Patch},
+	}
+	for _, o := range operations {
+		if o.operation == nil {
+			continue
+		}
+		context := fmt.Sprintf("operation '%s %s'", o.method, path)
+		l.lint(context, ExtensionLocationOperation, o.operation.Extensions)
+		for _, parameter := range o.operation.Parameters {
+			l.lintParameter(fmt.Sprintf("%s parameter '%s'", context, parameter.Name), parameter)
+		}
+		if o.operation.Responses == nil {
+			continue
+		}
+		if o.operation.Responses.Default != nil {
+			l.lintResponse(fmt.Sprintf("%s response 'default'", context), *o.operation.Responses.Default)
+		}
+		var statusCodes []int
+		for statusCode := range o.operation.Responses.StatusCodeResponses {
+			statusCodes = append(statusCodes, statusCode)
+		}
+		sort.Ints(statusCodes)
+		for _, statusCode := range statusCodes {
+			l.lintResponse(fmt.Sprintf("%s response '%d'", context, statusCode), o.operation.Responses.StatusCodeResponses[statusCode])
+		}
+	}
+}
+
+func (l *extensionsLinter) lintParameter(context string, parameter spec.Parameter) {
+	l.lint(context, ExtensionLocationParameter, parameter.Extensions)
+	if parameter.Schema != nil {
+		l.lintSchema(context+" schema", *parameter.Schema)
+	}
+}
+
+func (l *extensionsLinter) lintResponse(context string, response spec.Response) {
+	l.lint(context, ExtensionLocationResponse, response.Extensions)
+	if response.Schema != nil {
+		l.lintSchema(context+" schema", *response.Schema)
+	}
+}
+
+func (l *extensionsLinter) lintSchema(context string, schema spec.Schema) {
+	l.lint(context, ExtensionLocationSchema, schema.Extensions)
+	for _, name := range sortedKeys(schema.Properties) {
+		l.lintSchema(fmt.Sprintf("%s property '%s'", context, name), schema.Properties[name])
+	}
+	if schema.Items != nil {
+		if schema.Items.Schema != nil {
+			l.lintSchema(context+" items", *schema.Items.Schema)
+		}
+		for _, item := range schema.Items.Schemas {
+			l.lintSchema(context+" items", item)
+		}
+	}
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+		l.lintSchema(context+" additional properties", *schema.AdditionalProperties.Schema)
+	}
+	for _, s := range schema.AllOf {
+		l.lintSchema(context, s)
+	}
+}
+
+// sortedKeys returns the keys of the given map (keyed by string) sorted alphabetically, so the issues are always reported
+// in the same order
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
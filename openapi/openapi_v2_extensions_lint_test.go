@@ -0,0 +1,110 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintExtensions(t *testing.T) {
+	document := `{
+  "swagger": "2.0",
+  "x-terraform-provider-regions": "rst1,dub1",
+  "x-terraform-resource-regions-api": "rst1",
+  "x-terraform-resource-name": "document",
+  "securityDefinitions": {
+    "apikey_auth": {
+      "type": "apiKey",
+      "in": "header",
+      "name": "Authorization",
+      "x-terraform-authentication-scheme-bearer": "true"
+    }
+  },
+  "paths": {
+    "/v1/cdns": {
+      "post": {
+        "x-terraform-resource-name": "cdn",
+        "x-terraform-resource-timeout": "0s",
+        "parameters": [
+          {
+            "in": "header",
+            "name": "X-Request-ID",
+            "type": "string",
+            "x-terraform-header": "request_id"
+          },
+          {
+            "in": "body",
+            "name": "body",
+            "schema": {
+              "type": "object",
+              "properties": {
+                "label": {
+                  "type": "string",
+                  "x-terraform-force-new": true
+                }
+              }
+            }
+          }
+        ],
+        "responses": {
+          "202": {
+            "description": "accepted",
+            "x-terraform-resource-poll-enabled": true,
+            "x-terraform-resource-poll-interval": "5s"
+          }
+        }
+      }
+    }
+  },
+  "definitions": {
+    "ContentDeliveryNetwork": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "x-terraform-id": true
+        },
+        "ips": {
+          "type": "array",
+          "items": {
+            "type": "object",
+            "properties": {
+              "address": {
+                "type": "string",
+                "x-terraform-imutable": true
+              }
+            }
+          }
+        },
+        "label": {
+          "type": "string",
+          "x-nullable": true,
+          "x-terraform-resource-poll-enabled": true
+        }
+      }
+    }
+  }
+}`
+	swagger := &spec.Swagger{}
+	require.NoError(t, json.Unmarshal([]byte(document), swagger))
+	var issues []string
+	for _, issue := range lintExtensions(swagger) {
+		issues = append(issues, issue.Error())
+	}
+	assert.Equal(t, []string{
+		"document: extension 'x-terraform-resource-name' is not supported in the document level, only in: operation",
+		"security definition 'apikey_auth': extension 'x-terraform-authentication-scheme-bearer' value is not valid: expected a boolean value but got 'true'",
+		"definition 'ContentDeliveryNetwork' property 'ips' items property 'address': extension 'x-terraform-imutable' is not supported",
+		"definition 'ContentDeliveryNetwork' property 'label': extension 'x-terraform-resource-poll-enabled' is not supported in the schema level, only in: response",
+		"operation 'POST /v1/cdns': extension 'x-terraform-resource-timeout' value is not valid: invalid duration value: '0s'. The value must be either a duration (e,g: 30s, 1.5m, 1h30m) or an integer number of seconds (e,g: 30); negative and zero durations are not allowed: the duration must be greater than zero",
+	}, issues)
+}
+
+func TestLintExtensions_NoIssues(t *testing.T) {
+	swagger := &spec.Swagger{}
+	require.NoError(t, json.Unmarshal([]byte(`{"swagger": "2.0", "paths": {"/v1/cdns": {"get": {"x-terraform-exclude-data-source": true}}}}`), swagger))
+	assert.Empty(t, lintExtensions(swagger))
+}
@@ -4,8 +4,6 @@ import (
 	"github.com/go-openapi/spec"
 )
 
-const extTfHeader = "x-terraform-header"
-
 type parameterGroups [][]spec.Parameter
 
 // getHeaderConfigurations gets all the header configurations for a specific
@@ -13,6 +11,17 @@ func getHeaderConfigurations(parameters []spec.Parameter) SpecHeaderParameters {
 	return getHeaderConfigurationsForParameterGroups(parameterGroups{parameters})
 }
 
+// getRequiredHeaderConfigurations gets the header configurations for the header parameters that are marked as required
+func getRequiredHeaderConfigurations(parameters []spec.Parameter) SpecHeaderParameters {
+	requiredParameters := []spec.Parameter{}
+	for _, parameter := range parameters {
+		if parameter.Required {
+			requiredParameters = append(requiredParameters, parameter)
+		}
+	}
+	return getHeaderConfigurations(requiredParameters)
+}
+
 // getHeaderConfigurationsForParameterGroups loops through the provided parametersGroup (collection of parameters per operation) and
 // returns a map containing all the header configurations; the key will either be the value specified in the extTfHeader
 // or if not present the default value will be the name of the header. In any case, the key name will be translated to
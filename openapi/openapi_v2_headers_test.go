@@ -158,6 +158,34 @@ func TestGetHeaderConfigurations(t *testing.T) {
 	})
 }
 
+func TestGetRequiredHeaderConfigurations(t *testing.T) {
+	Convey("Given a list of parameters containing a required header parameter and an optional header parameter", t, func() {
+		parameters := []spec.Parameter{
+			{
+				ParamProps: spec.ParamProps{
+					Name:     "X-Project-ID",
+					In:       "header",
+					Required: true,
+				},
+			},
+			{
+				ParamProps: spec.ParamProps{
+					Name:     "X-Request-ID",
+					In:       "header",
+					Required: false,
+				},
+			},
+		}
+		Convey("When getRequiredHeaderConfigurations method is called", func() {
+			headerConfigProps := getRequiredHeaderConfigurations(parameters)
+			Convey("Then the header configs returned should only contain the required header", func() {
+				So(len(headerConfigProps), ShouldEqual, 1)
+				So(headerConfigProps, ShouldContain, SpecHeaderParam{Name: "X-Project-ID"})
+			})
+		})
+	})
+}
+
 func TestGetAllHeaderParameters(t *testing.T) {
 	Convey("Given a swagger doc containing paths with header type parameters and different header names", t, func() {
 		spec := &spec.Swagger{
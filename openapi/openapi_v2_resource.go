@@ -3,11 +3,14 @@ package openapi
 import (
 	"fmt"
 	"log"
+	"math"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/dikhan/terraform-provider-openapi/openapi/openapiutils"
+	"github.com/dikhan/terraform-provider-openapi/openapi/terraformutils"
 	"github.com/go-openapi/spec"
 )
 
@@ -48,25 +51,6 @@ const resourceParentNameRegex = `(\/(?:\w+\/)?(?:v\d+\/)?\w+)\/{\w+}`
 
 const resourceInstanceRegex = "((?:.*)){.*}"
 
-// Definition level extensions
-const extTfImmutable = "x-terraform-immutable"
-const extTfForceNew = "x-terraform-force-new"
-const extTfSensitive = "x-terraform-sensitive"
-const extTfFieldName = "x-terraform-field-name"
-const extTfFieldStatus = "x-terraform-field-status"
-const extTfID = "x-terraform-id"
-const extTfComputed = "x-terraform-computed"
-const extTfComplexObjectType = "x-terraform-complex-object-legacy-config"
-
-// Operation level extensions
-const extTfResourceTimeout = "x-terraform-resource-timeout"
-const extTfResourcePollEnabled = "x-terraform-resource-poll-enabled"
-const extTfResourcePollTargetStatuses = "x-terraform-resource-poll-completed-statuses"
-const extTfResourcePollPendingStatuses = "x-terraform-resource-poll-pending-statuses"
-const extTfExcludeResource = "x-terraform-exclude-resource"
-const extTfResourceName = "x-terraform-resource-name"
-const extTfResourceURL = "x-terraform-resource-host"
-
 // SpecV2Resource defines a struct that implements the SpecResource interface and it's based on OpenAPI v2 specification
 type SpecV2Resource struct {
 	Name   string
@@ -85,6 +69,11 @@ type SpecV2Resource struct {
 	SchemaDefinitions map[string]spec.Schema
 
 	Paths map[string]spec.PathItem
+
+	// schemaDefinitionCache, if set, is used to share the analysis of the resource schema with other resources that have
+	// the same schema. The key is computed once when the cache is set
+	schemaDefinitionCache    *schemaDefinitionCache
+	schemaDefinitionCacheKey string
 }
 
 // newSpecV2Resource creates a SpecV2Resource with no region and default host
@@ -207,7 +196,7 @@ func (o *SpecV2Resource) buildResourceNameFromPath(resourcePath, preferredName s
 // resource path "/v1/cdns/{cdn_id}/v1/firewalls" and the []strin{"cdnID"} the returned path will be "/v1/cdns/cdnID/v1/firewalls".
 // If the resource path is not parameterised, then regular path will be returned accordingly
 func (o *SpecV2Resource) getResourcePath(parentIDs []string) (string, error) {
-	resolvedPath := o.Path
+	resolvedPath := normalizeURLPath(o.Path)
 
 	pathParameterRegex, _ := regexp.Compile(pathParameterRegex)
 	pathParamsMatches := pathParameterRegex.FindAllStringSubmatch(resolvedPath, -1)
@@ -223,12 +212,11 @@ func (o *SpecV2Resource) getResourcePath(parentIDs []string) (string, error) {
 		return "", fmt.Errorf("could not resolve sub-resource path correctly '%s' with the given ids - missing ids to resolve the path params properly: %s", resolvedPath, parentIDs)
 	}
 
-	// At this point it's assured that there is an equal number of parameters to resolved and their corresponding ID values
+	// At this point it's assured that there is an equal number of parameters to resolved and their corresponding ID values.
+	// The ids are expected to be URL encoded already by the caller if needed (e.g. the client encodes them before making
+	// the API calls)
 	for idx, parentID := range parentIDs {
-		if strings.Contains(parentID, "/") {
-			return "", fmt.Errorf("could not resolve sub-resource path correctly '%s' due to parent IDs (%s) containing not supported characters (forward slashes)", resolvedPath, parentIDs)
-		}
-		resolvedPath = strings.Replace(resolvedPath, pathParamsMatches[idx][1], parentIDs[idx], 1)
+		resolvedPath = strings.Replace(resolvedPath, pathParamsMatches[idx][1], parentID, 1)
 	}
 
 	return resolvedPath, nil
@@ -251,16 +239,47 @@ func (o *SpecV2Resource) getHost() (string, error) {
 	return overrideHost, nil
 }
 
+// getScheme returns the scheme specified in the x-terraform-resource-scheme extension of the resource root POST operation,
+// or an empty string if the resource does not override the global scheme
+func (o *SpecV2Resource) getScheme() (string, error) {
+	if o.RootPathItem.Post == nil {
+		return "", nil
+	}
+	scheme := o.getExtensionStringValue(o.RootPathItem.Post.Extensions, extTfResourceScheme)
+	if scheme != "" && scheme != httpScheme && scheme != httpsScheme {
+		return "", fmt.Errorf("'%s' extension value '%s' not supported - must use %s or %s", extTfResourceScheme, scheme, httpScheme, httpsScheme)
+	}
+	return scheme, nil
+}
+
 func (o *SpecV2Resource) getResourceOperations() specResourceOperations {
+	post := o.createResourceOperation(o.RootPathItem.Post)
+	if post != nil {
+		post.OnFailureCleanup = o.getOnFailureCleanupOperation(o.RootPathItem.Post)
+	}
 	return specResourceOperations{
 		List:   o.createResourceOperation(o.RootPathItem.Get),
-		Post:   o.createResourceOperation(o.RootPathItem.Post),
+		Post:   post,
 		Get:    o.createResourceOperation(o.InstancePathItem.Get),
-		Put:    o.createResourceOperation(o.InstancePathItem.Put),
+		Put:    o.createResourceOperation(o.getUpdateOperation()),
 		Delete: o.createResourceOperation(o.InstancePathItem.Delete),
 	}
 }
 
+// getUpdateOperation returns the operation used to update the resource instances, which is the instance PUT operation. If
+// the resource has no PUT operation, the instance PATCH operation is used as long as it is configured with the json-patch
+// update strategy
+func (o *SpecV2Resource) getUpdateOperation() *spec.Operation {
+	if o.InstancePathItem.Put != nil {
+		return o.InstancePathItem.Put
+	}
+	patchOperation := o.InstancePathItem.Patch
+	if patchOperation != nil && o.getUpdateStrategy(patchOperation) == updateStrategyJSONPatch {
+		return patchOperation
+	}
+	return nil
+}
+
 // shouldIgnoreResource checks whether the POST operation for a given resource as the 'x-terraform-exclude-resource' extension
 // defined with true value. If so, the resource will not be exposed to the OpenAPI Terraform provider; otherwise it will
 // be exposed and users will be able to manage such resource via terraform.
@@ -276,7 +295,70 @@ func (o *SpecV2Resource) shouldIgnoreResource() bool {
 	return false
 }
 
+func (o *SpecV2Resource) shouldIgnoreDataSourceInstance() bool {
+	postOperation := o.RootPathItem.Post
+	if postOperation != nil {
+		return o.isBoolExtensionEnabled(postOperation.Extensions, extTfExcludeDataSourceInstance)
+	}
+	return false
+}
+
+// isImportOnly checks whether the POST operation for a given resource has the 'x-terraform-import-only' extension defined
+// with true value. If so, the resource is still exposed in the provider but users will only be able to import existing
+// instances and reference them.
+func (o *SpecV2Resource) isImportOnly() bool {
+	postOperation := o.RootPathItem.Post
+	if postOperation != nil {
+		return o.isBoolExtensionEnabled(postOperation.Extensions, extTfImportOnly)
+	}
+	return false
+}
+
+// isAutoImportEnabled checks whether the POST operation for a given resource has the 'x-terraform-resource-auto-import'
+// extension defined with true value. The existing instances are discovered via the collection GET operation, so the
+// extension is ignored if the resource root path does not have one
+func (o *SpecV2Resource) isAutoImportEnabled() bool {
+	postOperation := o.RootPathItem.Post
+	if postOperation == nil || !o.isBoolExtensionEnabled(postOperation.Extensions, extTfResourceAutoImport) {
+		return false
+	}
+	if o.RootPathItem.Get == nil {
+		log.Printf("[WARN] resource '%s' has the '%s' extension but the root path '%s' is missing the GET operation to list the instances, ignoring the extension", o.getResourceName(), extTfResourceAutoImport, o.Path)
+		return false
+	}
+	return true
+}
+
+// getCompositeID returns the names of the properties listed in the x-terraform-composite-id extension of the root POST
+// operation (e.g. 'namespace,name'), if any. Data sources are built out of the collection GET operation and therefore are
+// never identified by a composite id
+func (o *SpecV2Resource) getCompositeID() []string {
+	if o.RootPathItem.Post == nil || o.InstancePathItem.Get == nil {
+		return nil
+	}
+	return parseCompositeIDExtension(o.RootPathItem.Post)
+}
+
+// getConsoleURLTemplate returns the value of the x-terraform-console-url-template extension defined in the root POST
+// operation. If the resource is multi-region, the region placeholder is already resolved with the resource's region.
+func (o *SpecV2Resource) getConsoleURLTemplate() string {
+	postOperation := o.RootPathItem.Post
+	if postOperation == nil {
+		return ""
+	}
+	consoleURLTemplate, _ := postOperation.Extensions.GetString(extTfConsoleURLTemplate)
+	if o.Region != "" {
+		consoleURLTemplate = strings.Replace(consoleURLTemplate, consoleURLRegionPlaceholder, o.Region, -1)
+	}
+	return consoleURLTemplate
+}
+
 func (o *SpecV2Resource) getParentResourceInfo() *parentResourceInfo {
+	// The path parameters of resources identified by a composite id (e.g. /v1/ns/{namespace}/things) are part of the
+	// resource identifier rather than references to parent resources
+	if len(o.getCompositeID()) > 0 {
+		return nil
+	}
 	resourceParentRegex, _ := regexp.Compile(resourceParentNameRegex)
 	parentMatches := resourceParentRegex.FindAllStringSubmatch(o.Path, -1)
 	if len(parentMatches) > 0 {
@@ -329,7 +411,22 @@ func (o *SpecV2Resource) getParentResourceInfo() *parentResourceInfo {
 }
 
 func (o *SpecV2Resource) getResourceSchema() (*specSchemaDefinition, error) {
-	return o.getSchemaDefinition(&o.SchemaDefinition)
+	if o.schemaDefinitionCache == nil || o.schemaDefinitionCacheKey == "" {
+		return o.getSchemaDefinition(&o.SchemaDefinition)
+	}
+	return o.schemaDefinitionCache.getOrCreate(o.schemaDefinitionCacheKey, func() (*specSchemaDefinition, error) {
+		return o.getSchemaDefinition(&o.SchemaDefinition)
+	})
+}
+
+// useSchemaDefinitionCache makes the resource use the given cache to store the analysis of its schema
+func (o *SpecV2Resource) useSchemaDefinitionCache(cache *schemaDefinitionCache) {
+	var parentPropertyNames []string
+	if parentResourceInfo := o.getParentResourceInfo(); parentResourceInfo != nil {
+		parentPropertyNames = parentResourceInfo.getParentPropertiesNames()
+	}
+	o.schemaDefinitionCache = cache
+	o.schemaDefinitionCacheKey = schemaDefinitionCacheKey(o.SchemaDefinition, parentPropertyNames)
 }
 
 func (o *SpecV2Resource) getSchemaDefinition(schema *spec.Schema) (*specSchemaDefinition, error) {
@@ -345,6 +442,15 @@ func (o *SpecV2Resource) getSchemaDefinition(schema *spec.Schema) (*specSchemaDe
 		}
 		schemaDefinition.Properties = append(schemaDefinition.Properties, schemaDefinitionProperty)
 	}
+	if err := schemaDefinition.validateDerivedProperties(); err != nil {
+		return nil, err
+	}
+	if err := schemaDefinition.validateRequiredIfProperties(); err != nil {
+		return nil, err
+	}
+	if err := schemaDefinition.validateDefaultFromAPIProperties(); err != nil {
+		return nil, err
+	}
 
 	parentResourceInfo := o.getParentResourceInfo()
 	if parentResourceInfo != nil {
@@ -387,6 +493,7 @@ func (o *SpecV2Resource) createSchemaDefinitionProperty(propertyName string, pro
 	schemaDefinitionProperty.Type = propertyType
 
 	schemaDefinitionProperty.Name = propertyName
+	schemaDefinitionProperty.Description = property.Description
 
 	if preferredPropertyName, exists := property.Extensions.GetString(extTfFieldName); exists {
 		schemaDefinitionProperty.PreferredName = preferredPropertyName
@@ -419,11 +526,71 @@ func (o *SpecV2Resource) createSchemaDefinitionProperty(propertyName string, pro
 	// schemaDefinitionProperty.ReadOnly is set to true if the property is explicitly readOnly OR if it's not readOnly but still considered optional computed
 	schemaDefinitionProperty.ReadOnly = property.ReadOnly
 
+	// Derived properties are state only, their value is computed out of other properties and never sent to the API
+	if derivedTemplate, exists := property.Extensions.GetString(extTfDerived); exists && derivedTemplate != "" {
+		if schemaDefinitionProperty.Type != typeString {
+			return nil, fmt.Errorf("failed to process property '%s': extension '%s' is only supported in properties of type string", propertyName, extTfDerived)
+		}
+		if schemaDefinitionProperty.Required {
+			return nil, fmt.Errorf("failed to process property '%s': a derived property cannot be required", propertyName)
+		}
+		schemaDefinitionProperty.DerivedTemplate = derivedTemplate
+		schemaDefinitionProperty.ReadOnly = true
+		schemaDefinitionProperty.Computed = true
+	}
+
+	// Optional properties can be required depending on the value of another property (e.g. 'type=vpn' or 'type=vpn|ipsec'),
+	// which is checked when planning
+	if requiredIf, exists := property.Extensions.GetString(extTfRequiredIf); exists && requiredIf != "" {
+		condition := strings.SplitN(requiredIf, "=", 2)
+		if len(condition) != 2 || strings.TrimSpace(condition[0]) == "" {
+			return nil, fmt.Errorf("failed to process property '%s': extension '%s' value '%s' must follow the format '<property_name>=<value>[|<value>...]'", propertyName, extTfRequiredIf, requiredIf)
+		}
+		if schemaDefinitionProperty.Required || schemaDefinitionProperty.ReadOnly {
+			return nil, fmt.Errorf("failed to process property '%s': extension '%s' is only supported in optional properties", propertyName, extTfRequiredIf)
+		}
+		schemaDefinitionProperty.RequiredIfProperty = strings.TrimSpace(condition[0])
+		for _, value := range strings.Split(condition[1], "|") {
+			schemaDefinitionProperty.RequiredIfValues = append(schemaDefinitionProperty.RequiredIfValues, strings.TrimSpace(value))
+		}
+	}
+
+	// Optional properties can default to a value returned by the API (e.g. the default network id returned by
+	// GET /v1/defaults), which is fetched when planning if the user does not provide a value
+	if value, exists := property.Extensions[extTfDefaultFromAPI]; exists {
+		defaultFromAPI, err := parseAPIDefaultExtension(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process property '%s': extension '%s' is not valid: %s", propertyName, extTfDefaultFromAPI, err)
+		}
+		if !schemaDefinitionProperty.isPrimitiveProperty() {
+			return nil, fmt.Errorf("failed to process property '%s': extension '%s' is only supported in primitive properties (string, integer, number or boolean)", propertyName, extTfDefaultFromAPI)
+		}
+		if schemaDefinitionProperty.Required || schemaDefinitionProperty.isComputed() || property.Default != nil {
+			return nil, fmt.Errorf("failed to process property '%s': extension '%s' is only supported in optional properties that are not computed and have no default value", propertyName, extTfDefaultFromAPI)
+		}
+		schemaDefinitionProperty.DefaultFromAPI = defaultFromAPI
+	}
+
+	// Expensive computed properties can be excluded from routine refreshes, the value is only populated when the resource
+	// is created, updated or imported
+	if o.isBoolExtensionEnabled(property.Extensions, extTfRefreshOnDemand) {
+		if !schemaDefinitionProperty.isComputed() {
+			return nil, fmt.Errorf("failed to process property '%s': extension '%s' is only supported in computed properties", propertyName, extTfRefreshOnDemand)
+		}
+		schemaDefinitionProperty.RefreshOnDemand = true
+	}
+
 	// If the value of the property is changed, it will force the deletion of the previous generated resource and
 	// a new resource with this new value will be created
 	if o.isBoolExtensionEnabled(property.Extensions, extTfForceNew) {
 		schemaDefinitionProperty.ForceNew = true
 	}
+	if forceNewReason, exists := property.Extensions.GetString(extTfForceNewReason); exists && forceNewReason != "" {
+		if !schemaDefinitionProperty.ForceNew {
+			return nil, fmt.Errorf("failed to process property '%s': extension '%s' is only supported in properties with the '%s' extension enabled", propertyName, extTfForceNewReason, extTfForceNew)
+		}
+		schemaDefinitionProperty.ForceNewReason = forceNewReason
+	}
 
 	// A sensitive property means that the value will not be disclosed in the state file, preventing secrets from
 	// being leaked
@@ -445,18 +612,98 @@ func (o *SpecV2Resource) createSchemaDefinitionProperty(propertyName string, pro
 		schemaDefinitionProperty.IsStatusIdentifier = true
 	}
 
+	if o.isBoolExtensionEnabled(property.Extensions, extTfFieldStatusMessage) {
+		schemaDefinitionProperty.IsStatusMessage = true
+	}
+
 	if o.isBoolExtensionEnabled(property.Extensions, extTfComplexObjectType) {
 		schemaDefinitionProperty.EnableLegacyComplexObjectBlockConfiguration = true
 	}
 
+	if copyTo, exists := property.Extensions.GetString(extTfFieldCopyTo); exists {
+		for _, fieldName := range strings.Split(strings.Replace(copyTo, " ", "", -1), ",") {
+			if fieldName != "" && fieldName != propertyName {
+				schemaDefinitionProperty.CopyTo = append(schemaDefinitionProperty.CopyTo, fieldName)
+			}
+		}
+	}
+
+	if aliases, exists := property.Extensions.GetString(extTfPropertyAlias); exists {
+		for _, alias := range strings.Split(strings.Replace(aliases, " ", "", -1), ",") {
+			alias = terraformutils.ConvertToTerraformCompliantName(alias)
+			if alias != "" && alias != schemaDefinitionProperty.getTerraformCompliantPropertyName() {
+				schemaDefinitionProperty.Aliases = append(schemaDefinitionProperty.Aliases, alias)
+			}
+		}
+	}
+
+	if hashKeys, exists := property.Extensions.GetString(extTfSetHashKeys); exists {
+		for _, key := range strings.Split(strings.Replace(hashKeys, " ", "", -1), ",") {
+			if key != "" {
+				schemaDefinitionProperty.SetHashKeys = append(schemaDefinitionProperty.SetHashKeys, key)
+			}
+		}
+	}
+	if o.isBoolExtensionEnabled(property.Extensions, extTfSetHashIgnoreCase) {
+		schemaDefinitionProperty.SetHashIgnoreCase = true
+	}
+	if err := o.validateSetHashConfiguration(schemaDefinitionProperty); err != nil {
+		return nil, err
+	}
+
 	// Use the default keyword in the parameter schema to specify the default value for an optional parameter. The default
 	// value is the one that the server uses if the client does not supply the parameter value in the request.
 	// Link: https://swagger.io/docs/specification/describing-parameters#default
 	schemaDefinitionProperty.Default = property.Default
 
+	// The enum keyword restricts the property value to a fixed set of values. Only primitive properties are supported.
+	if schemaDefinitionProperty.isPrimitiveProperty() && len(property.Enum) > 0 {
+		schemaDefinitionProperty.Enum = property.Enum
+	}
+
+	if schemaDefinitionProperty.Type == typeList {
+		if property.MinItems != nil {
+			schemaDefinitionProperty.MinItems = int(*property.MinItems)
+		}
+		if property.MaxItems != nil {
+			schemaDefinitionProperty.MaxItems = int(*property.MaxItems)
+		}
+		// The items of arrays of primitives can be restricted to a fixed set of values (enum) and/or a regular expression (pattern)
+		if o.isArrayItemPrimitiveType(schemaDefinitionProperty.ArrayItemsType) {
+			schemaDefinitionProperty.ArrayItemsEnum = property.Items.Schema.Enum
+			if pattern := property.Items.Schema.Pattern; pattern != "" {
+				if _, err := regexp.Compile(pattern); err != nil {
+					return nil, fmt.Errorf("failed to process array type property '%s': items pattern '%s' is not valid: %s", propertyName, pattern, err)
+				}
+				schemaDefinitionProperty.ArrayItemsPattern = pattern
+			}
+		}
+	}
+
 	return schemaDefinitionProperty, nil
 }
 
+// validateSetHashConfiguration checks that the set hash extensions are only used in arrays of objects and that the hash
+// keys refer to primitive properties of the array items
+func (o *SpecV2Resource) validateSetHashConfiguration(property *specSchemaDefinitionProperty) error {
+	if len(property.SetHashKeys) == 0 && !property.SetHashIgnoreCase {
+		return nil
+	}
+	if !property.isArrayOfObjectsProperty() {
+		return fmt.Errorf("failed to process property '%s': extensions '%s' and '%s' are only supported in properties of type array with items of type object", property.Name, extTfSetHashKeys, extTfSetHashIgnoreCase)
+	}
+	for _, key := range property.SetHashKeys {
+		itemProperty, err := property.SpecSchemaDefinition.getProperty(key)
+		if err != nil {
+			return fmt.Errorf("failed to process property '%s': %s key '%s' is not a property of the array items", property.Name, extTfSetHashKeys, key)
+		}
+		if !itemProperty.isPrimitiveProperty() {
+			return fmt.Errorf("failed to process property '%s': %s key '%s' must be a primitive property (string, integer, number or boolean)", property.Name, extTfSetHashKeys, key)
+		}
+	}
+	return nil
+}
+
 func (o *SpecV2Resource) isBoolExtensionEnabled(extensions spec.Extensions, extension string) bool {
 	if extensions != nil {
 		if enabled, ok := extensions.GetBool(extension); ok && enabled {
@@ -653,24 +900,157 @@ func (o *SpecV2Resource) createResourceOperation(operation *spec.Operation) *spe
 	headerParameters := getHeaderConfigurations(operation.Parameters)
 	securitySchemes := createSecuritySchemes(operation.Security)
 	return &specResourceOperation{
-		HeaderParameters: headerParameters,
-		SecuritySchemes:  securitySchemes,
-		responses:        o.createResponses(operation),
+		HeaderParameters:         headerParameters,
+		RequiredHeaderParameters: getRequiredHeaderConfigurations(operation.Parameters),
+		SecuritySchemes:          securitySchemes,
+		AnonymousAccess:          operation.Security != nil && len(securitySchemes) == 0,
+		BatchRead:                o.isBoolExtensionEnabled(operation.Extensions, extTfBatchRead),
+		ConfirmDeleteViaList:     o.isBoolExtensionEnabled(operation.Extensions, extTfDeleteConfirmViaList),
+		WaitForNotFound:          o.isBoolExtensionEnabled(operation.Extensions, extTfDeleteWaitForNotFound),
+		EventualConsistency:      o.getEventualConsistencyPolicy(operation),
+		RefreshFieldsQueryParam:  o.getExtensionStringValue(operation.Extensions, extTfRefreshFieldsQueryParam),
+		UpdateMaskQueryParam:     o.getUpdateMaskQueryParam(operation),
+		UpdateStrategy:           o.getUpdateStrategy(operation),
+		Retry:                    o.getRetryPolicy(operation),
+		responses:                o.createResponses(operation),
 	}
 }
 
+// getRetryPolicy returns the retry policy configured in the operation via the x-terraform-retry extension, if any, or
+// the one configured for the whole resource via the extension in the root path (or the instance path). Values that are
+// not valid are ignored so the service retry configuration applies
+func (o *SpecV2Resource) getRetryPolicy(operation *spec.Operation) *retryPolicy {
+	value, exists := operation.Extensions[extTfRetry]
+	if !exists {
+		value, exists = o.RootPathItem.Extensions[extTfRetry]
+	}
+	if !exists {
+		value, exists = o.InstancePathItem.Extensions[extTfRetry]
+	}
+	if !exists {
+		return nil
+	}
+	policy, err := parseRetryExtension(value)
+	if err != nil {
+		log.Printf("[WARN] '%s' extension value is not valid, ignoring it: %s", extTfRetry, err)
+		return nil
+	}
+	return policy
+}
+
+// getEventualConsistencyPolicy returns the eventual consistency policy configured in the operation via the
+// x-terraform-eventual-consistency extension, if any. Values that are not valid are ignored
+func (o *SpecV2Resource) getEventualConsistencyPolicy(operation *spec.Operation) *eventualConsistencyPolicy {
+	value, exists := operation.Extensions[extTfEventualConsistency]
+	if !exists {
+		return nil
+	}
+	policy, err := parseEventualConsistencyExtension(value)
+	if err != nil {
+		log.Printf("[WARN] '%s' extension value is not valid, ignoring it: %s", extTfEventualConsistency, err)
+		return nil
+	}
+	return policy
+}
+
+// getOnFailureCleanupOperation returns the cleanup operation the operation references via the x-terraform-on-failure-cleanup
+// extension, if any. Values that are not valid or referencing an operation not documented in the resource instance path
+// (or a sub-path of it) are ignored
+func (o *SpecV2Resource) getOnFailureCleanupOperation(operation *spec.Operation) *onFailureCleanupOperation {
+	value, exists := operation.Extensions[extTfOnFailureCleanup]
+	if !exists {
+		return nil
+	}
+	cleanup, err := parseOnFailureCleanupExtension(value)
+	if err != nil {
+		log.Printf("[WARN] '%s' extension value is not valid, ignoring it: %s", extTfOnFailureCleanup, err)
+		return nil
+	}
+	cleanupOperation, err := cleanup.resolve(o.Path, o.Paths)
+	if err != nil {
+		log.Printf("[WARN] '%s' extension of resource '%s' is not valid, ignoring it: %s", extTfOnFailureCleanup, o.Name, err)
+		return nil
+	}
+	cleanup.operation = o.createResourceOperation(cleanupOperation)
+	return cleanup
+}
+
+// getUpdateStrategy returns the update strategy configured in the operation via the x-terraform-update-strategy extension,
+// defaulting to put if the extension is not present or its value is not supported
+func (o *SpecV2Resource) getUpdateStrategy(operation *spec.Operation) updateStrategy {
+	value := o.getExtensionStringValue(operation.Extensions, extTfUpdateStrategy)
+	switch strategy := updateStrategy(value); strategy {
+	case updateStrategyPut, updateStrategyJSONPatch:
+		return strategy
+	case "":
+		return updateStrategyPut
+	default:
+		log.Printf("[WARN] '%s' extension value '%s' not supported, falling back to '%s'", extTfUpdateStrategy, value, updateStrategyPut)
+		return updateStrategyPut
+	}
+}
+
+// getUpdateMaskQueryParam returns the name of the query parameter of the operation marked with the x-terraform-update-mask
+// extension, if any
+func (o *SpecV2Resource) getUpdateMaskQueryParam(operation *spec.Operation) string {
+	for _, parameter := range operation.Parameters {
+		if parameter.In == "query" && o.isBoolExtensionEnabled(parameter.Extensions, extTfUpdateMask) {
+			return parameter.Name
+		}
+	}
+	return ""
+}
+
 func (o *SpecV2Resource) createResponses(operation *spec.Operation) specResponses {
 	responses := specResponses{}
-	for statusCode, response := range operation.Responses.StatusCodeResponses { //panics on ImportState if the swagger doesn't define status code responses
+	if operation.Responses == nil {
+		return responses
+	}
+	for statusCode, response := range operation.Responses.StatusCodeResponses {
 		responses[statusCode] = &specResponse{
 			isPollingEnabled:    o.isResourcePollingEnabled(response),
 			pollTargetStatuses:  o.getResourcePollTargetStatuses(response),
 			pollPendingStatuses: o.getResourcePollPendingStatuses(response),
+			pollFailedStatuses:  o.getPollingStatuses(response, extTfResourcePollFailedStatuses),
+			pollStatusPath:      o.getExtensionStringValue(response.Extensions, extTfResourcePollStatusPath),
+			pollInterval:        o.getResourcePollInterval(response),
+			pollStrategy:        pollStrategy(o.getExtensionStringValue(response.Extensions, extTfResourcePollStrategy)),
+			pollMaxInterval:     o.getResourcePollMaxInterval(response),
+			pollEventsPath:      o.getExtensionStringValue(response.Extensions, extTfResourcePollEventsPath),
+			pollEventsProperty:  o.getExtensionStringValue(response.Extensions, extTfResourcePollEventsProperty),
+			pollLocationHeader:  o.getExtensionStringValue(response.Extensions, extTfResourcePollLocationHeader),
+			isSummary:           o.isBoolExtensionEnabled(response.Extensions, extTfResourceSummaryResponse),
+			schema:              o.getResponseSchema(statusCode, response),
 		}
 	}
 	return responses
 }
 
+// getResponseSchema returns the schema definition documented for the given response so the payloads returned with that
+// status code can be handled with their own shape (e.g. a 201 returning a summary of the resource while a 200 returns the
+// full object). Nil is returned if the response does not document a schema with properties or the schema is not supported.
+func (o *SpecV2Resource) getResponseSchema(statusCode int, response spec.Response) *specSchemaDefinition {
+	if response.Schema == nil || len(response.Schema.Properties) == 0 {
+		return nil
+	}
+	responseSchema, err := o.getSchemaDefinition(response.Schema)
+	if err != nil {
+		log.Printf("[WARN] the schema of the response with status code %d could not be processed, the resource schema will be used instead: %s", statusCode, err)
+		return nil
+	}
+	return responseSchema
+}
+
+// getResourcePollMaxInterval returns the value of the 'x-terraform-resource-poll-max-interval' extension of the given
+// response, or zero if the extension is not present (invalid values are reported when the OpenAPI document is analysed)
+func (o *SpecV2Resource) getResourcePollMaxInterval(response spec.Response) time.Duration {
+	pollMaxInterval, err := o.getTimeDuration(response.Extensions, extTfResourcePollMaxInterval)
+	if err != nil || pollMaxInterval == nil {
+		return 0
+	}
+	return *pollMaxInterval
+}
+
 // isResourcePollingEnabled checks whether there is any response code defined for the given responseStatusCode and if so
 // whether that response contains the extension 'x-terraform-resource-poll-enabled' set to true returning true;
 // otherwise false is returned
@@ -681,6 +1061,17 @@ func (o *SpecV2Resource) isResourcePollingEnabled(response spec.Response) bool {
 	return false
 }
 
+// getResourcePollInterval returns the value of the 'x-terraform-resource-poll-interval' extension of the given response, or
+// zero if the extension is not present. Invalid values are reported when the OpenAPI document is analysed, so they are
+// just ignored here
+func (o *SpecV2Resource) getResourcePollInterval(response spec.Response) time.Duration {
+	pollInterval, err := o.getTimeDuration(response.Extensions, extTfResourcePollInterval)
+	if err != nil || pollInterval == nil {
+		return 0
+	}
+	return *pollInterval
+}
+
 func (o *SpecV2Resource) getResourcePollTargetStatuses(response spec.Response) []string {
 	return o.getPollingStatuses(response, extTfResourcePollTargetStatuses)
 }
@@ -710,7 +1101,7 @@ func (o *SpecV2Resource) getTimeouts() (*specTimeouts, error) {
 	if getTimeout, err = o.getResourceTimeout(o.InstancePathItem.Get); err != nil {
 		return nil, err
 	}
-	if putTimeout, err = o.getResourceTimeout(o.InstancePathItem.Put); err != nil {
+	if putTimeout, err = o.getResourceTimeout(o.getUpdateOperation()); err != nil {
 		return nil, err
 	}
 	if deleteTimeout, err = o.getResourceTimeout(o.InstancePathItem.Delete); err != nil {
@@ -731,18 +1122,55 @@ func (o *SpecV2Resource) getResourceTimeout(operation *spec.Operation) (*time.Du
 	return o.getTimeDuration(operation.Extensions, extTfResourceTimeout)
 }
 
+// getTimeDuration returns the duration configured in the given extension, nil if the extension is not present. The value
+// can be either a Go duration (e.g. 30s, 1.5m, 1h30m, 500ms) or an integer number of seconds (e.g. 30 or "30")
 func (o *SpecV2Resource) getTimeDuration(extensions spec.Extensions, extension string) (*time.Duration, error) {
-	if value, exists := extensions.GetString(extension); exists {
-		regex, err := regexp.Compile("^\\d+(\\.\\d+)?[smh]{1}$")
-		if err != nil {
-			return nil, err
+	value, exists := extensions[strings.ToLower(extension)]
+	if !exists {
+		return nil, nil
+	}
+	duration, err := parseExtensionDuration(value)
+	if err != nil {
+		return nil, err
+	}
+	return &duration, nil
+}
+
+// integerSecondsRegex matches the duration values expressed as an integer number of seconds (e.g. 30)
+var integerSecondsRegex = regexp.MustCompile(`^\d+$`)
+
+// parseExtensionDuration parses the value of a duration extension. Negative and zero durations are not allowed
+func parseExtensionDuration(value interface{}) (time.Duration, error) {
+	var duration time.Duration
+	var err error
+	switch v := value.(type) {
+	case string:
+		if integerSecondsRegex.MatchString(v) {
+			var seconds int64
+			seconds, err = strconv.ParseInt(v, 10, 64)
+			duration = time.Duration(seconds) * time.Second
+		} else {
+			duration, err = time.ParseDuration(v)
 		}
-		if !regex.Match([]byte(value)) {
-			return nil, fmt.Errorf("invalid duration value: '%s'. The value must be a sequence of decimal numbers each with optional fraction and a unit suffix (negative durations are not allowed). The value must be formatted either in seconds (s), minutes (m) or hours (h)", value)
+	case float64:
+		if v != math.Trunc(v) {
+			err = fmt.Errorf("fractional seconds must be expressed as a duration (e,g: 1.5s)")
 		}
-		return o.getDuration(value)
+		duration = time.Duration(v) * time.Second
+	case int:
+		duration = time.Duration(v) * time.Second
+	case int64:
+		duration = time.Duration(v) * time.Second
+	default:
+		err = fmt.Errorf("type %T not supported", value)
+	}
+	if err == nil && duration <= 0 {
+		err = fmt.Errorf("the duration must be greater than zero")
 	}
-	return nil, nil
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration value: '%v'. The value must be either a duration (e,g: 30s, 1.5m, 1h30m) or an integer number of seconds (e,g: 30); negative and zero durations are not allowed: %s", value, err)
+	}
+	return duration, nil
 }
 
 func (o *SpecV2Resource) getDuration(t string) (*time.Duration, error) {
@@ -750,6 +1178,22 @@ func (o *SpecV2Resource) getDuration(t string) (*time.Duration, error) {
 	return &duration, err
 }
 
+// parseCompositeIDExtension returns the property names listed in the x-terraform-composite-id extension of the given
+// operation, ignoring the blank spaces (e.g. 'namespace, name')
+func parseCompositeIDExtension(operation *spec.Operation) []string {
+	value, exists := operation.Extensions.GetString(extTfCompositeID)
+	if !exists || value == "" {
+		return nil
+	}
+	var compositeID []string
+	for _, propertyName := range strings.Split(strings.Replace(value, " ", "", -1), ",") {
+		if propertyName != "" {
+			compositeID = append(compositeID, propertyName)
+		}
+	}
+	return compositeID
+}
+
 // getResourceOverrideHost checks if the x-terraform-resource-host extension is present and if so returns its value. This
 // value will override the global host value, and the API calls for this resource will be made against the value returned
 func getResourceOverrideHost(rootPathItemPost *spec.Operation) string {
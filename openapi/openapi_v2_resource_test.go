@@ -373,6 +373,540 @@ func TestShouldIgnoreResource(t *testing.T) {
 	})
 }
 
+func TestGetConsoleURLTemplate(t *testing.T) {
+	Convey(fmt.Sprintf("Given a SpecV2Resource configured with a root path item that does not contain the %s extension", extTfConsoleURLTemplate), t, func() {
+		r := SpecV2Resource{
+			RootPathItem: spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Post: &spec.Operation{},
+				},
+			},
+		}
+		Convey("When getConsoleURLTemplate is called", func() {
+			consoleURLTemplate := r.getConsoleURLTemplate()
+			Convey("Then the result should be empty", func() {
+				So(consoleURLTemplate, ShouldBeEmpty)
+			})
+		})
+	})
+	Convey(fmt.Sprintf("Given a SpecV2Resource configured with a root path item that contains the %s extension", extTfConsoleURLTemplate), t, func() {
+		r := SpecV2Resource{
+			RootPathItem: spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Post: &spec.Operation{
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								extTfConsoleURLTemplate: "https://{region}.console.api.com/cdns/{id}",
+							},
+						},
+					},
+				},
+			},
+		}
+		Convey("When getConsoleURLTemplate is called", func() {
+			consoleURLTemplate := r.getConsoleURLTemplate()
+			Convey("Then the result should be the template as is", func() {
+				So(consoleURLTemplate, ShouldEqual, "https://{region}.console.api.com/cdns/{id}")
+			})
+		})
+		Convey("When getConsoleURLTemplate is called and the resource is multi-region", func() {
+			r.Region = "rst1"
+			consoleURLTemplate := r.getConsoleURLTemplate()
+			Convey("Then the result should be the template with the region of the resource", func() {
+				So(consoleURLTemplate, ShouldEqual, "https://rst1.console.api.com/cdns/{id}")
+			})
+		})
+	})
+}
+
+func TestGetCompositeID(t *testing.T) {
+	Convey(fmt.Sprintf("Given a SpecV2Resource configured with a root path item that contains the %s extension", extTfCompositeID), t, func() {
+		r := SpecV2Resource{
+			Path: "/v1/ns/{namespace}/things",
+			RootPathItem: spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Post: &spec.Operation{
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								extTfCompositeID: "namespace, name",
+							},
+						},
+					},
+				},
+			},
+			InstancePathItem: spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Get: &spec.Operation{},
+				},
+			},
+		}
+		Convey("When getCompositeID is called", func() {
+			compositeID := r.getCompositeID()
+			Convey("Then the result should contain the properties listed in the extension", func() {
+				So(compositeID, ShouldResemble, []string{"namespace", "name"})
+			})
+			Convey("And the path parameters of the resource root path should not be considered parent resources", func() {
+				So(r.getParentResourceInfo(), ShouldBeNil)
+			})
+		})
+		Convey("When getCompositeID is called and the resource is a data source (no instance path)", func() {
+			r.InstancePathItem = spec.PathItem{}
+			compositeID := r.getCompositeID()
+			Convey("Then the result should be nil", func() {
+				So(compositeID, ShouldBeNil)
+			})
+			Convey("And the path parameters of the resource root path should be considered parent resources", func() {
+				So(r.getParentResourceInfo(), ShouldNotBeNil)
+			})
+		})
+	})
+	Convey(fmt.Sprintf("Given a SpecV2Resource configured with a root path item that does not contain the %s extension", extTfCompositeID), t, func() {
+		r := SpecV2Resource{
+			RootPathItem: spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Post: &spec.Operation{},
+				},
+			},
+			InstancePathItem: spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Get: &spec.Operation{},
+				},
+			},
+		}
+		Convey("When getCompositeID is called", func() {
+			compositeID := r.getCompositeID()
+			Convey("Then the result should be nil", func() {
+				So(compositeID, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestIsImportOnly(t *testing.T) {
+	Convey(fmt.Sprintf("Given a SpecV2Resource configured with a root path item that does not contain the %s extension", extTfImportOnly), t, func() {
+		r := SpecV2Resource{
+			RootPathItem: spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Post: &spec.Operation{},
+				},
+			},
+		}
+		Convey("When isImportOnly is called", func() {
+			isImportOnly := r.isImportOnly()
+			Convey("Then the result should be false", func() {
+				So(isImportOnly, ShouldBeFalse)
+			})
+		})
+	})
+	Convey(fmt.Sprintf("Given a SpecV2Resource configured with a root path item that DOES contain the %s extension with value equal true", extTfImportOnly), t, func() {
+		r := SpecV2Resource{
+			RootPathItem: spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Post: &spec.Operation{
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								extTfImportOnly: true,
+							},
+						},
+					},
+				},
+			},
+		}
+		Convey("When isImportOnly is called", func() {
+			isImportOnly := r.isImportOnly()
+			Convey("Then the result should be true", func() {
+				So(isImportOnly, ShouldBeTrue)
+			})
+		})
+	})
+	Convey("Given a SpecV2Resource configured with a root path item that does not have a POST operation", t, func() {
+		r := SpecV2Resource{
+			RootPathItem: spec.PathItem{},
+		}
+		Convey("When isImportOnly is called", func() {
+			isImportOnly := r.isImportOnly()
+			Convey("Then the result should be false", func() {
+				So(isImportOnly, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestIsAutoImportEnabled(t *testing.T) {
+	autoImportPost := &spec.Operation{
+		VendorExtensible: spec.VendorExtensible{
+			Extensions: spec.Extensions{
+				extTfResourceAutoImport: true,
+			},
+		},
+	}
+	Convey(fmt.Sprintf("Given a SpecV2Resource configured with a root path item that DOES contain the %s extension with value equal true and the GET operation", extTfResourceAutoImport), t, func() {
+		r := SpecV2Resource{
+			Name: "cdns_v1",
+			RootPathItem: spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Get:  &spec.Operation{},
+					Post: autoImportPost,
+				},
+			},
+		}
+		Convey("When isAutoImportEnabled is called", func() {
+			isAutoImportEnabled := r.isAutoImportEnabled()
+			Convey("Then the result should be true", func() {
+				So(isAutoImportEnabled, ShouldBeTrue)
+			})
+		})
+	})
+	Convey(fmt.Sprintf("Given a SpecV2Resource configured with a root path item that contains the %s extension but is missing the GET operation", extTfResourceAutoImport), t, func() {
+		r := SpecV2Resource{
+			Name: "cdns_v1",
+			RootPathItem: spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Post: autoImportPost,
+				},
+			},
+		}
+		Convey("When isAutoImportEnabled is called", func() {
+			isAutoImportEnabled := r.isAutoImportEnabled()
+			Convey("Then the result should be false since the instances can not be listed", func() {
+				So(isAutoImportEnabled, ShouldBeFalse)
+			})
+		})
+	})
+	Convey(fmt.Sprintf("Given a SpecV2Resource configured with a root path item that does not contain the %s extension", extTfResourceAutoImport), t, func() {
+		r := SpecV2Resource{
+			RootPathItem: spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Get:  &spec.Operation{},
+					Post: &spec.Operation{},
+				},
+			},
+		}
+		Convey("When isAutoImportEnabled is called", func() {
+			isAutoImportEnabled := r.isAutoImportEnabled()
+			Convey("Then the result should be false", func() {
+				So(isAutoImportEnabled, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestShouldIgnoreDataSourceInstance(t *testing.T) {
+	Convey(fmt.Sprintf("Given a SpecV2Resource configured with a root path item that does not contain the %s extension", extTfExcludeDataSourceInstance), t, func() {
+		r := SpecV2Resource{
+			RootPathItem: spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Post: &spec.Operation{},
+				},
+			},
+		}
+		Convey("When shouldIgnoreDataSourceInstance is called", func() {
+			shouldIgnoreDataSourceInstance := r.shouldIgnoreDataSourceInstance()
+			Convey("Then the result should be false", func() {
+				So(shouldIgnoreDataSourceInstance, ShouldBeFalse)
+			})
+		})
+	})
+	Convey(fmt.Sprintf("Given a SpecV2Resource configured with a root path item that DOES contain the %s extension with value equal true", extTfExcludeDataSourceInstance), t, func() {
+		r := SpecV2Resource{
+			RootPathItem: spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Post: &spec.Operation{
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								extTfExcludeDataSourceInstance: true,
+							},
+						},
+					},
+				},
+			},
+		}
+		Convey("When shouldIgnoreDataSourceInstance is called", func() {
+			shouldIgnoreDataSourceInstance := r.shouldIgnoreDataSourceInstance()
+			Convey("Then the result should be true", func() {
+				So(shouldIgnoreDataSourceInstance, ShouldBeTrue)
+			})
+			Convey("And the resource itself should not be ignored", func() {
+				So(r.shouldIgnoreResource(), ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestCreateResourceOperation(t *testing.T) {
+	Convey("Given a SpecV2Resource", t, func() {
+		r := SpecV2Resource{}
+		Convey(fmt.Sprintf("When createResourceOperation is called with an operation that contains the %s extension with value equal true", extTfBatchRead), func() {
+			resourceOperation := r.createResourceOperation(&spec.Operation{
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfBatchRead: true,
+					},
+				},
+			})
+			Convey("Then the resource operation should be configured with batch read", func() {
+				So(resourceOperation.BatchRead, ShouldBeTrue)
+			})
+		})
+		Convey(fmt.Sprintf("When createResourceOperation is called with an operation that contains the %s extension with value equal true", extTfDeleteConfirmViaList), func() {
+			resourceOperation := r.createResourceOperation(&spec.Operation{
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfDeleteConfirmViaList: true,
+					},
+				},
+			})
+			Convey("Then the resource operation should be configured to confirm the delete via the collection GET operation", func() {
+				So(resourceOperation.ConfirmDeleteViaList, ShouldBeTrue)
+			})
+		})
+		Convey(fmt.Sprintf("When createResourceOperation is called with an operation that contains the %s extension with value equal true", extTfDeleteWaitForNotFound), func() {
+			resourceOperation := r.createResourceOperation(&spec.Operation{
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfDeleteWaitForNotFound: true,
+					},
+				},
+			})
+			Convey("Then the resource operation should be configured to wait for the instance GET operation to no longer find the instance", func() {
+				So(resourceOperation.WaitForNotFound, ShouldBeTrue)
+			})
+		})
+		Convey(fmt.Sprintf("When createResourceOperation is called with an operation that contains the %s extension", extTfRefreshFieldsQueryParam), func() {
+			resourceOperation := r.createResourceOperation(&spec.Operation{
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfRefreshFieldsQueryParam: "fields",
+					},
+				},
+			})
+			Convey("Then the resource operation should be configured with the query parameter used to limit the properties returned", func() {
+				So(resourceOperation.RefreshFieldsQueryParam, ShouldEqual, "fields")
+			})
+		})
+		Convey("When createResourceOperation is called with an operation that does not declare security", func() {
+			resourceOperation := r.createResourceOperation(&spec.Operation{})
+			Convey("Then the resource operation should use the global security", func() {
+				So(resourceOperation.SecuritySchemes, ShouldBeEmpty)
+				So(resourceOperation.AnonymousAccess, ShouldBeFalse)
+			})
+		})
+		Convey("When createResourceOperation is called with an operation that declares its own security", func() {
+			resourceOperation := r.createResourceOperation(&spec.Operation{
+				OperationProps: spec.OperationProps{
+					Security: []map[string][]string{{"apikey_auth": {}}},
+				},
+			})
+			Convey("Then the resource operation should override the global security with the operation security schemes", func() {
+				So(resourceOperation.SecuritySchemes, ShouldResemble, SpecSecuritySchemes{{Name: "apikey_auth"}})
+				So(resourceOperation.AnonymousAccess, ShouldBeFalse)
+			})
+		})
+		Convey("When createResourceOperation is called with an operation that declares an empty security", func() {
+			for _, security := range [][]map[string][]string{{}, {{}}} {
+				resourceOperation := r.createResourceOperation(&spec.Operation{
+					OperationProps: spec.OperationProps{
+						Security: security,
+					},
+				})
+				Convey(fmt.Sprintf("Then the resource operation should be called without authentication (security: %+v)", security), func() {
+					So(resourceOperation.SecuritySchemes, ShouldBeEmpty)
+					So(resourceOperation.AnonymousAccess, ShouldBeTrue)
+				})
+			}
+		})
+		Convey(fmt.Sprintf("When createResourceOperation is called with an operation that contains a query parameter with the %s extension", extTfUpdateMask), func() {
+			resourceOperation := r.createResourceOperation(&spec.Operation{
+				OperationProps: spec.OperationProps{
+					Parameters: []spec.Parameter{
+						{ParamProps: spec.ParamProps{Name: "Some-Header", In: "header"}},
+						{ParamProps: spec.ParamProps{Name: "update_mask", In: "query"}, VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfUpdateMask: true}}},
+					},
+				},
+			})
+			Convey("Then the resource operation should be configured with the update mask query parameter", func() {
+				So(resourceOperation.UpdateMaskQueryParam, ShouldEqual, "update_mask")
+			})
+		})
+		Convey(fmt.Sprintf("When createResourceOperation is called with an operation that contains a header parameter with the %s extension", extTfUpdateMask), func() {
+			resourceOperation := r.createResourceOperation(&spec.Operation{
+				OperationProps: spec.OperationProps{
+					Parameters: []spec.Parameter{
+						{ParamProps: spec.ParamProps{Name: "Update-Mask", In: "header"}, VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfUpdateMask: true}}},
+					},
+				},
+			})
+			Convey("Then the resource operation should not be configured with an update mask since only query parameters are supported", func() {
+				So(resourceOperation.UpdateMaskQueryParam, ShouldBeEmpty)
+			})
+		})
+		Convey(fmt.Sprintf("When createResourceOperation is called with an operation that does not contain the %s extension", extTfUpdateStrategy), func() {
+			resourceOperation := r.createResourceOperation(&spec.Operation{})
+			Convey("Then the resource operation should be configured with the put update strategy", func() {
+				So(resourceOperation.UpdateStrategy, ShouldEqual, updateStrategyPut)
+			})
+		})
+		Convey(fmt.Sprintf("When createResourceOperation is called with an operation that contains the %s extension with value json-patch", extTfUpdateStrategy), func() {
+			resourceOperation := r.createResourceOperation(&spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfUpdateStrategy: "json-patch"}}})
+			Convey("Then the resource operation should be configured with the json-patch update strategy", func() {
+				So(resourceOperation.UpdateStrategy, ShouldEqual, updateStrategyJSONPatch)
+			})
+		})
+		Convey(fmt.Sprintf("When createResourceOperation is called with an operation that contains the %s extension with a value not supported", extTfUpdateStrategy), func() {
+			resourceOperation := r.createResourceOperation(&spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfUpdateStrategy: "merge-patch"}}})
+			Convey("Then the resource operation should fall back to the put update strategy", func() {
+				So(resourceOperation.UpdateStrategy, ShouldEqual, updateStrategyPut)
+			})
+		})
+		Convey(fmt.Sprintf("When createResourceOperation is called with an operation that contains the %s extension", extTfRetry), func() {
+			resourceOperation := r.createResourceOperation(&spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfRetry: map[string]interface{}{"max_attempts": float64(5), "initial_backoff": "2s", "jitter": true}}}})
+			Convey("Then the resource operation should be configured with the retry policy, the max backoff defaulting to 30s", func() {
+				So(resourceOperation.Retry, ShouldResemble, &retryPolicy{maxAttempts: 5, initialBackoff: 2 * time.Second, maxBackoff: 30 * time.Second, jitter: true})
+			})
+		})
+		Convey(fmt.Sprintf("When createResourceOperation is called with an operation that contains the %s extension with a value not valid", extTfRetry), func() {
+			resourceOperation := r.createResourceOperation(&spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfRetry: map[string]interface{}{"max_attempts": "many"}}}})
+			Convey("Then the resource operation should not be configured with a retry policy so the service one applies", func() {
+				So(resourceOperation.Retry, ShouldBeNil)
+			})
+		})
+		Convey(fmt.Sprintf("When createResourceOperation is called with an operation that contains the %s extension", extTfEventualConsistency), func() {
+			resourceOperation := r.createResourceOperation(&spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfEventualConsistency: map[string]interface{}{"grace_period": "30s"}}}})
+			Convey("Then the resource operation should be configured with the eventual consistency policy, the retry interval defaulting to 2s", func() {
+				So(resourceOperation.EventualConsistency, ShouldResemble, &eventualConsistencyPolicy{gracePeriod: 30 * time.Second, retryInterval: 2 * time.Second})
+			})
+		})
+		Convey(fmt.Sprintf("When createResourceOperation is called with an operation that contains the %s extension with a value not valid", extTfEventualConsistency), func() {
+			resourceOperation := r.createResourceOperation(&spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfEventualConsistency: map[string]interface{}{"retry_interval": "5s"}}}})
+			Convey("Then the resource operation should not be configured with an eventual consistency policy", func() {
+				So(resourceOperation.EventualConsistency, ShouldBeNil)
+			})
+		})
+		Convey("When createResourceOperation is called with a nil operation", func() {
+			resourceOperation := r.createResourceOperation(nil)
+			Convey("Then the resource operation returned should be nil", func() {
+				So(resourceOperation, ShouldBeNil)
+			})
+		})
+	})
+	Convey(fmt.Sprintf("Given a SpecV2Resource with a root path item that contains the %s extension", extTfRetry), t, func() {
+		r := SpecV2Resource{
+			RootPathItem: spec.PathItem{
+				VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfRetry: map[string]interface{}{"max_attempts": float64(3)}}},
+			},
+		}
+		Convey(fmt.Sprintf("When createResourceOperation is called with an operation that does not contain the %s extension", extTfRetry), func() {
+			resourceOperation := r.createResourceOperation(&spec.Operation{})
+			Convey("Then the resource operation should be configured with the retry policy of the resource", func() {
+				So(resourceOperation.Retry, ShouldResemble, &retryPolicy{maxAttempts: 3, initialBackoff: defaultRetryInitialBackoff, maxBackoff: defaultRetryMaxBackoff})
+			})
+		})
+		Convey(fmt.Sprintf("When createResourceOperation is called with an operation that contains the %s extension", extTfRetry), func() {
+			resourceOperation := r.createResourceOperation(&spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfRetry: map[string]interface{}{"max_attempts": float64(5)}}}})
+			Convey("Then the retry policy of the operation should override the one of the resource", func() {
+				So(resourceOperation.Retry, ShouldResemble, &retryPolicy{maxAttempts: 5, initialBackoff: defaultRetryInitialBackoff, maxBackoff: defaultRetryMaxBackoff})
+			})
+		})
+	})
+}
+
+func TestGetOnFailureCleanupOperation(t *testing.T) {
+	Convey("Given a SpecV2Resource with a POST operation and an abort operation documented in a sub-path of its instance path", t, func() {
+		abortOperation := &spec.Operation{OperationProps: spec.OperationProps{Security: []map[string][]string{}}}
+		r := SpecV2Resource{
+			Name: "resource",
+			Path: "/v1/resource",
+			Paths: map[string]spec.PathItem{
+				"/v1/resource/{id}":       {PathItemProps: spec.PathItemProps{Delete: &spec.Operation{}}},
+				"/v1/resource/{id}/abort": {PathItemProps: spec.PathItemProps{Post: abortOperation}},
+			},
+		}
+		Convey(fmt.Sprintf("When getResourceOperations is called and the POST operation does not contain the %s extension", extTfOnFailureCleanup), func() {
+			r.RootPathItem = spec.PathItem{PathItemProps: spec.PathItemProps{Post: &spec.Operation{}}}
+			resourceOperations := r.getResourceOperations()
+			Convey("Then the POST operation should not be configured to cleanup on failure", func() {
+				So(resourceOperations.Post.OnFailureCleanup, ShouldBeNil)
+			})
+		})
+		Convey(fmt.Sprintf("When getResourceOperations is called and the POST operation contains the %s extension referencing the abort operation", extTfOnFailureCleanup), func() {
+			r.RootPathItem = spec.PathItem{PathItemProps: spec.PathItemProps{Post: &spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfOnFailureCleanup: map[string]interface{}{"path": "/v1/resource/{id}/abort", "method": "post"}}}}}}
+			resourceOperations := r.getResourceOperations()
+			Convey("Then the POST operation should be configured to call the abort operation on failure", func() {
+				So(resourceOperations.Post.OnFailureCleanup, ShouldNotBeNil)
+				So(resourceOperations.Post.OnFailureCleanup.path, ShouldEqual, "/v1/resource/{id}/abort")
+				So(resourceOperations.Post.OnFailureCleanup.method, ShouldEqual, httpPost)
+				So(resourceOperations.Post.OnFailureCleanup.subPath, ShouldEqual, "/abort")
+				So(resourceOperations.Post.OnFailureCleanup.operation.AnonymousAccess, ShouldBeTrue)
+			})
+		})
+		Convey(fmt.Sprintf("When getOnFailureCleanupOperation is called with the %s extension referencing the instance DELETE operation", extTfOnFailureCleanup), func() {
+			cleanup := r.getOnFailureCleanupOperation(&spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfOnFailureCleanup: map[string]interface{}{"path": "/v1/resource/{id}", "method": "delete"}}}})
+			Convey("Then the cleanup operation should be the instance DELETE operation", func() {
+				So(cleanup, ShouldNotBeNil)
+				So(cleanup.method, ShouldEqual, httpDelete)
+				So(cleanup.subPath, ShouldEqual, "")
+			})
+		})
+		Convey(fmt.Sprintf("When getOnFailureCleanupOperation is called with the %s extension referencing an operation not documented", extTfOnFailureCleanup), func() {
+			cleanup := r.getOnFailureCleanupOperation(&spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfOnFailureCleanup: map[string]interface{}{"path": "/v1/resource/{id}/abort", "method": "delete"}}}})
+			Convey("Then the extension should be ignored", func() {
+				So(cleanup, ShouldBeNil)
+			})
+		})
+		Convey(fmt.Sprintf("When getOnFailureCleanupOperation is called with the %s extension referencing an operation of a different resource", extTfOnFailureCleanup), func() {
+			r.Paths["/v1/other/{id}/abort"] = spec.PathItem{PathItemProps: spec.PathItemProps{Post: abortOperation}}
+			cleanup := r.getOnFailureCleanupOperation(&spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfOnFailureCleanup: map[string]interface{}{"path": "/v1/other/{id}/abort", "method": "post"}}}})
+			Convey("Then the extension should be ignored", func() {
+				So(cleanup, ShouldBeNil)
+			})
+		})
+		Convey(fmt.Sprintf("When getOnFailureCleanupOperation is called with the %s extension with a value that is not valid", extTfOnFailureCleanup), func() {
+			cleanup := r.getOnFailureCleanupOperation(&spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfOnFailureCleanup: true}}})
+			Convey("Then the extension should be ignored", func() {
+				So(cleanup, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestGetUpdateOperation(t *testing.T) {
+	jsonPatchOperation := &spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfUpdateStrategy: "json-patch"}}}
+	testCases := []struct {
+		name              string
+		instancePathItem  spec.PathItem
+		expectedOperation *spec.Operation
+	}{
+		{
+			name:              "resource with PUT operation",
+			instancePathItem:  spec.PathItem{PathItemProps: spec.PathItemProps{Put: &spec.Operation{}, Patch: jsonPatchOperation}},
+			expectedOperation: &spec.Operation{},
+		},
+		{
+			name:              "resource with PATCH operation configured with the json-patch update strategy",
+			instancePathItem:  spec.PathItem{PathItemProps: spec.PathItemProps{Patch: jsonPatchOperation}},
+			expectedOperation: jsonPatchOperation,
+		},
+		{
+			name:             "resource with PATCH operation not configured with the json-patch update strategy",
+			instancePathItem: spec.PathItem{PathItemProps: spec.PathItemProps{Patch: &spec.Operation{}}},
+		},
+		{
+			name: "resource without PUT nor PATCH operations",
+		},
+	}
+	for _, tc := range testCases {
+		Convey(fmt.Sprintf("Given a %s", tc.name), t, func() {
+			r := SpecV2Resource{InstancePathItem: tc.instancePathItem}
+			Convey("When getUpdateOperation is called", func() {
+				operation := r.getUpdateOperation()
+				Convey("Then the operation returned should be the expected one", func() {
+					So(operation, ShouldResemble, tc.expectedOperation)
+				})
+			})
+		})
+	}
+}
+
 func TestBuildResourceName(t *testing.T) {
 
 	testCases := []struct {
@@ -971,111 +1505,472 @@ func TestParentResourceInfo(t *testing.T) {
 				So(parentResourceInfo.parentInstanceURIs[1], ShouldEqual, "/v1/cdns/{id}/v2/firewalls/{id}")
 			})
 		})
-	})
-	Convey("Given a SpecV2Resource configured with a path that is a subresource but the path is wrongly structured not following best restful practises for building subresource paths (the 'firewalls' parent in the path is missing the id path param)", t, func() {
-		r := SpecV2Resource{
-			Path: "/v1/cdns/{id}/v2/firewalls/v3/rules",
-			Paths: map[string]spec.PathItem{
-				"/v1/cdns": {
-					PathItemProps: spec.PathItemProps{
-						Post: &spec.Operation{},
+	})
+	Convey("Given a SpecV2Resource configured with a path that is a subresource but the path is wrongly structured not following best restful practises for building subresource paths (the 'firewalls' parent in the path is missing the id path param)", t, func() {
+		r := SpecV2Resource{
+			Path: "/v1/cdns/{id}/v2/firewalls/v3/rules",
+			Paths: map[string]spec.PathItem{
+				"/v1/cdns": {
+					PathItemProps: spec.PathItemProps{
+						Post: &spec.Operation{},
+					},
+				},
+			},
+		}
+		Convey("When parentResourceInfo is called", func() {
+			parentResourceInfo := r.getParentResourceInfo()
+			Convey("Then the resource should be considered a subresource and the output should match the expected output values", func() {
+				So(parentResourceInfo, ShouldNotBeNil)
+				So(len(parentResourceInfo.parentResourceNames), ShouldEqual, 1)
+				So(parentResourceInfo.parentResourceNames[0], ShouldEqual, "cdns_v1")
+				So(parentResourceInfo.fullParentResourceName, ShouldEqual, "cdns_v1")
+			})
+			Convey("And the parentURIs should contain the expected parent URIs", func() {
+				So(len(parentResourceInfo.parentURIs), ShouldEqual, 1)
+				So(parentResourceInfo.parentURIs[0], ShouldEqual, "/v1/cdns")
+			})
+			Convey("And the parentInstanceURIs should contain the expected instances URIs", func() {
+				So(len(parentResourceInfo.parentInstanceURIs), ShouldEqual, 1)
+				So(parentResourceInfo.parentInstanceURIs[0], ShouldEqual, "/v1/cdns/{id}")
+			})
+		})
+	})
+
+	Convey("Given a SpecV2Resource configured with a path that is a subresource and the parents have preferred names", t, func() {
+		expectedCDNResourceName := "cdn"
+		expectedFirewallResourceName := "firewall"
+		r := SpecV2Resource{
+			Path: "/v1/cdns/{id}/v2/firewalls/{id}/v3/rules",
+			Paths: map[string]spec.PathItem{
+				"/v1/cdns": {
+					PathItemProps: spec.PathItemProps{
+						Post: &spec.Operation{
+							VendorExtensible: spec.VendorExtensible{
+								Extensions: spec.Extensions{
+									extTfResourceName: expectedCDNResourceName,
+								},
+							},
+						},
+					},
+				},
+				"/v1/cdns/{id}/v2/firewalls": {
+					PathItemProps: spec.PathItemProps{
+						Post: &spec.Operation{
+							VendorExtensible: spec.VendorExtensible{
+								Extensions: spec.Extensions{
+									extTfResourceName: expectedFirewallResourceName,
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		Convey("When parentResourceInfo is called", func() {
+			parentResourceInfo := r.getParentResourceInfo()
+			Convey("And the parentResourceNames should not be empty and contain the right items", func() {
+				So(len(parentResourceInfo.parentResourceNames), ShouldEqual, 2)
+				So(parentResourceInfo.parentResourceNames[0], ShouldEqual, "cdn_v1")
+				So(parentResourceInfo.parentResourceNames[1], ShouldEqual, "firewall_v2")
+			})
+			Convey("And the fullParentResourceName should match the expected name", func() {
+				So(parentResourceInfo.fullParentResourceName, ShouldEqual, "cdn_v1_firewall_v2")
+			})
+			Convey("And the parentURIs should contain the expected parent URIs", func() {
+				So(len(parentResourceInfo.parentURIs), ShouldEqual, 2)
+				So(parentResourceInfo.parentURIs[0], ShouldEqual, "/v1/cdns")
+				So(parentResourceInfo.parentURIs[1], ShouldEqual, "/v1/cdns/{id}/v2/firewalls")
+			})
+			Convey("And the parentInstanceURIs should contain the expected instances URIs", func() {
+				So(len(parentResourceInfo.parentInstanceURIs), ShouldEqual, 2)
+				So(parentResourceInfo.parentInstanceURIs[0], ShouldEqual, "/v1/cdns/{id}")
+				So(parentResourceInfo.parentInstanceURIs[1], ShouldEqual, "/v1/cdns/{id}/v2/firewalls/{id}")
+			})
+		})
+	})
+
+}
+
+func assertSchemaProperty(actualSpecSchemaDefinition *specSchemaDefinition, expectedName string, expectedType schemaDefinitionPropertyType, expectedRequired, expectedReadOnly, expectedComputed bool) {
+	prop, err := actualSpecSchemaDefinition.getProperty(expectedName)
+	So(err, ShouldBeNil)
+	fmt.Printf(">>> Validating '%s' property settings\n", prop.Name)
+	So(prop.Type, ShouldEqual, expectedType)
+	So(prop.Required, ShouldEqual, expectedRequired)
+	So(prop.ReadOnly, ShouldEqual, expectedReadOnly)
+	So(prop.Computed, ShouldEqual, expectedComputed)
+}
+
+func assertSchemaParentProperty(actualSpecSchemaDefinition *specSchemaDefinition, expectedName string) {
+	assertSchemaProperty(actualSpecSchemaDefinition, expectedName, typeString, true, false, false)
+}
+
+func TestGetResourceSchema(t *testing.T) {
+	Convey("Given a SpecV2Resource containing a root path", t, func() {
+		r := &SpecV2Resource{
+			Path: "/cdns",
+			SchemaDefinition: spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Required: []string{"number_required_prop"},
+					Properties: map[string]spec.Schema{
+						"string_readonly_prop": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"string"},
+							},
+							SwaggerSchemaProps: spec.SwaggerSchemaProps{
+								ReadOnly: true,
+							},
+						},
+						"int_optional_computed_prop": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"integer"},
+							},
+							VendorExtensible: spec.VendorExtensible{
+								Extensions: spec.Extensions{
+									extTfComputed: true,
+								},
+							},
+						},
+						"number_required_prop": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"number"},
+							},
+						},
+						"bool_prop": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"boolean"},
+							},
+						},
+					},
+				},
+			},
+		}
+		Convey("When getSchemaDefinition is called with a schema containing various properties", func() {
+			specSchemaDefinition, err := r.getResourceSchema()
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the specSchemaDefinition returned should be configured as expected", func() {
+				So(len(specSchemaDefinition.Properties), ShouldEqual, len(r.SchemaDefinition.SchemaProps.Properties))
+				assertSchemaProperty(specSchemaDefinition, "string_readonly_prop", typeString, false, true, true)
+				assertSchemaProperty(specSchemaDefinition, "int_optional_computed_prop", typeInt, false, false, true)
+				assertSchemaProperty(specSchemaDefinition, "number_required_prop", typeFloat, true, false, false)
+				assertSchemaProperty(specSchemaDefinition, "bool_prop", typeBool, false, false, false)
+			})
+		})
+	})
+}
+
+func TestGetSchemaDefinition(t *testing.T) {
+
+	Convey("Given a blank SpecV2Resource", t, func() {
+		r := &SpecV2Resource{}
+		Convey("When getSchemaDefinition is called with a nil arg", func() {
+			_, err := r.getSchemaDefinition(nil)
+			Convey("Then the error returned matches the expected one", func() {
+				So(err.Error(), ShouldEqual, "schema argument must not be nil")
+			})
+		})
+		Convey("When getSchemaDefinition is called passing a blank schema", func() {
+			d, e := r.getSchemaDefinition(&spec.Schema{})
+			Convey("Then the error returned is not nil", func() {
+				So(e, ShouldBeNil)
+			})
+			Convey("And the schema definition contains empty Properties", func() {
+				So(d, ShouldNotBeNil)
+				So(d.Properties, ShouldBeEmpty)
+			})
+		})
+		Convey("When getSchemaDefinition is called passing a schema with a derived property referring to a property that does not exist", func() {
+			schema := spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Properties: map[string]spec.Schema{
+						"host": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"string"},
+							},
+						},
+						"endpoint": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"string"},
+							},
+							VendorExtensible: spec.VendorExtensible{
+								Extensions: spec.Extensions{
+									extTfDerived: "https://{host}:{port}",
+								},
+							},
+						},
+					},
+				},
+			}
+			_, err := r.getSchemaDefinition(&schema)
+			Convey("Then the error returned matches the expected one", func() {
+				So(err.Error(), ShouldEqual, "failed to process property 'endpoint': derived template placeholder '{port}' must refer to a primitive property (that is not derived) of the same schema")
+			})
+		})
+		Convey("When getSchemaDefinition is called passing a schema with a conditionally required property whose condition refers to a property that does not exist", func() {
+			schema := spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Properties: map[string]spec.Schema{
+						"pre_shared_key": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"string"},
+							},
+							VendorExtensible: spec.VendorExtensible{
+								Extensions: spec.Extensions{
+									extTfRequiredIf: "type=vpn",
+								},
+							},
+						},
+					},
+				},
+			}
+			_, err := r.getSchemaDefinition(&schema)
+			Convey("Then the error returned matches the expected one", func() {
+				So(err.Error(), ShouldEqual, "failed to process property 'pre_shared_key': extension 'x-terraform-required-if' condition property 'type' must refer to another primitive property of the same schema")
+			})
+		})
+		Convey("When getSchemaDefinition is called passing a schema with a nested conditionally required property", func() {
+			schema := spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Properties: map[string]spec.Schema{
+						"tunnel": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"object"},
+								Properties: map[string]spec.Schema{
+									"type": {
+										SchemaProps: spec.SchemaProps{
+											Type: spec.StringOrArray{"string"},
+										},
+									},
+									"pre_shared_key": {
+										SchemaProps: spec.SchemaProps{
+											Type: spec.StringOrArray{"string"},
+										},
+										VendorExtensible: spec.VendorExtensible{
+											Extensions: spec.Extensions{
+												extTfRequiredIf: "type=vpn",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			_, err := r.getSchemaDefinition(&schema)
+			Convey("Then the error returned matches the expected one", func() {
+				So(err.Error(), ShouldEqual, "failed to process property 'tunnel': extension 'x-terraform-required-if' is not supported in nested properties ('pre_shared_key')")
+			})
+		})
+		Convey("When getSchemaDefinition is called passing a schema with a nested property whose default value is fetched from the API", func() {
+			schema := spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Properties: map[string]spec.Schema{
+						"network": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"object"},
+								Properties: map[string]spec.Schema{
+									"id": {
+										SchemaProps: spec.SchemaProps{
+											Type: spec.StringOrArray{"string"},
+										},
+										VendorExtensible: spec.VendorExtensible{
+											Extensions: spec.Extensions{
+												extTfDefaultFromAPI: map[string]interface{}{"path": "/v1/defaults"},
+											},
+										},
+									},
+								},
+							},
+						},
 					},
 				},
-			},
-		}
-		Convey("When parentResourceInfo is called", func() {
-			parentResourceInfo := r.getParentResourceInfo()
-			Convey("Then the resource should be considered a subresource and the output should match the expected output values", func() {
-				So(parentResourceInfo, ShouldNotBeNil)
-				So(len(parentResourceInfo.parentResourceNames), ShouldEqual, 1)
-				So(parentResourceInfo.parentResourceNames[0], ShouldEqual, "cdns_v1")
-				So(parentResourceInfo.fullParentResourceName, ShouldEqual, "cdns_v1")
+			}
+			_, err := r.getSchemaDefinition(&schema)
+			Convey("Then the error returned matches the expected one", func() {
+				So(err.Error(), ShouldEqual, "failed to process property 'network': extension 'x-terraform-default-from-api' is not supported in nested properties ('id')")
 			})
-			Convey("And the parentURIs should contain the expected parent URIs", func() {
-				So(len(parentResourceInfo.parentURIs), ShouldEqual, 1)
-				So(parentResourceInfo.parentURIs[0], ShouldEqual, "/v1/cdns")
+		})
+		Convey("When getSchemaDefinition is called passing a schema with a weird property type", func() {
+			schema := spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Properties: map[string]spec.Schema{
+						"string_readonly_prop": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"something weird"},
+							},
+						},
+					},
+				},
+			}
+			d, e := r.getSchemaDefinition(&schema)
+			Convey("Then the error returned is not nil", func() {
+				So(e, ShouldNotBeNil)
 			})
-			Convey("And the parentInstanceURIs should contain the expected instances URIs", func() {
-				So(len(parentResourceInfo.parentInstanceURIs), ShouldEqual, 1)
-				So(parentResourceInfo.parentInstanceURIs[0], ShouldEqual, "/v1/cdns/{id}")
+			Convey("And the schema definition returned is nil", func() {
+				So(d, ShouldBeNil)
 			})
 		})
-	})
 
-	Convey("Given a SpecV2Resource configured with a path that is a subresource and the parents have preferred names", t, func() {
-		expectedCDNResourceName := "cdn"
-		expectedFirewallResourceName := "firewall"
-		r := SpecV2Resource{
-			Path: "/v1/cdns/{id}/v2/firewalls/{id}/v3/rules",
-			Paths: map[string]spec.PathItem{
-				"/v1/cdns": {
-					PathItemProps: spec.PathItemProps{
-						Post: &spec.Operation{
+	})
+	Convey("Given a SpecV2Resource containing a root path", t, func() {
+		r := &SpecV2Resource{
+			Path: "/cdns",
+		}
+		Convey("When getSchemaDefinition is called with a schema containing various properties", func() {
+			s := &spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Required: []string{"number_required_prop"},
+					Properties: map[string]spec.Schema{
+						"string_readonly_prop": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"string"},
+							},
+							SwaggerSchemaProps: spec.SwaggerSchemaProps{
+								ReadOnly: true,
+							},
+						},
+						"int_optional_computed_prop": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"integer"},
+							},
 							VendorExtensible: spec.VendorExtensible{
 								Extensions: spec.Extensions{
-									extTfResourceName: expectedCDNResourceName,
+									extTfComputed: true,
 								},
 							},
 						},
+						"number_required_prop": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"number"},
+							},
+						},
+						"bool_prop": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"boolean"},
+							},
+						},
 					},
 				},
-				"/v1/cdns/{id}/v2/firewalls": {
-					PathItemProps: spec.PathItemProps{
-						Post: &spec.Operation{
+			}
+			specSchemaDefinition, err := r.getSchemaDefinition(s)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the specSchemaDefinition returned should be configured as expected", func() {
+				So(len(specSchemaDefinition.Properties), ShouldEqual, len(s.SchemaProps.Properties))
+				assertSchemaProperty(specSchemaDefinition, "string_readonly_prop", typeString, false, true, true)
+				assertSchemaProperty(specSchemaDefinition, "int_optional_computed_prop", typeInt, false, false, true)
+				assertSchemaProperty(specSchemaDefinition, "number_required_prop", typeFloat, true, false, false)
+				assertSchemaProperty(specSchemaDefinition, "bool_prop", typeBool, false, false, false)
+			})
+		})
+	})
+
+	Convey("Given a SpecV2Resource containing a subresource path (one level)", t, func() {
+		r := &SpecV2Resource{
+			Path: "/v1/cdns/{id}/firewalls",
+		}
+		Convey("When getSchemaDefinition is called with a schema containing various properties", func() {
+			s := &spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Required: []string{"number_required_prop"},
+					Properties: map[string]spec.Schema{
+						"string_readonly_prop": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"string"},
+							},
+							SwaggerSchemaProps: spec.SwaggerSchemaProps{
+								ReadOnly: true,
+							},
+						},
+						"int_optional_computed_prop": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"integer"},
+							},
 							VendorExtensible: spec.VendorExtensible{
 								Extensions: spec.Extensions{
-									extTfResourceName: expectedFirewallResourceName,
+									extTfComputed: true,
 								},
 							},
 						},
+						"number_required_prop": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"number"},
+							},
+						},
+						"bool_prop": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"boolean"},
+							},
+						},
 					},
 				},
-			},
-		}
-		Convey("When parentResourceInfo is called", func() {
-			parentResourceInfo := r.getParentResourceInfo()
-			Convey("And the parentResourceNames should not be empty and contain the right items", func() {
-				So(len(parentResourceInfo.parentResourceNames), ShouldEqual, 2)
-				So(parentResourceInfo.parentResourceNames[0], ShouldEqual, "cdn_v1")
-				So(parentResourceInfo.parentResourceNames[1], ShouldEqual, "firewall_v2")
+			}
+			specSchemaDefinition, err := r.getSchemaDefinition(s)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
 			})
-			Convey("And the fullParentResourceName should match the expected name", func() {
-				So(parentResourceInfo.fullParentResourceName, ShouldEqual, "cdn_v1_firewall_v2")
+			Convey("And the specSchemaDefinition returned should be configured with the expected number of properties including the parent id one", func() {
+				So(len(specSchemaDefinition.Properties), ShouldEqual, len(s.SchemaProps.Properties)+1)
 			})
-			Convey("And the parentURIs should contain the expected parent URIs", func() {
-				So(len(parentResourceInfo.parentURIs), ShouldEqual, 2)
-				So(parentResourceInfo.parentURIs[0], ShouldEqual, "/v1/cdns")
-				So(parentResourceInfo.parentURIs[1], ShouldEqual, "/v1/cdns/{id}/v2/firewalls")
+			Convey("And the specSchemaDefinition returned should be configured as expected", func() {
+				assertSchemaProperty(specSchemaDefinition, "string_readonly_prop", typeString, false, true, true)
+				assertSchemaProperty(specSchemaDefinition, "int_optional_computed_prop", typeInt, false, false, true)
+				assertSchemaProperty(specSchemaDefinition, "number_required_prop", typeFloat, true, false, false)
+				assertSchemaProperty(specSchemaDefinition, "bool_prop", typeBool, false, false, false)
 			})
-			Convey("And the parentInstanceURIs should contain the expected instances URIs", func() {
-				So(len(parentResourceInfo.parentInstanceURIs), ShouldEqual, 2)
-				So(parentResourceInfo.parentInstanceURIs[0], ShouldEqual, "/v1/cdns/{id}")
-				So(parentResourceInfo.parentInstanceURIs[1], ShouldEqual, "/v1/cdns/{id}/v2/firewalls/{id}")
+			Convey("And the specSchemaDefinition returned should be configured with the parent id property with the expected configuration", func() {
+				assertSchemaParentProperty(specSchemaDefinition, "cdns_v1_id")
 			})
 		})
 	})
 
-}
-
-func assertSchemaProperty(actualSpecSchemaDefinition *specSchemaDefinition, expectedName string, expectedType schemaDefinitionPropertyType, expectedRequired, expectedReadOnly, expectedComputed bool) {
-	prop, err := actualSpecSchemaDefinition.getProperty(expectedName)
-	So(err, ShouldBeNil)
-	fmt.Printf(">>> Validating '%s' property settings\n", prop.Name)
-	So(prop.Type, ShouldEqual, expectedType)
-	So(prop.Required, ShouldEqual, expectedRequired)
-	So(prop.ReadOnly, ShouldEqual, expectedReadOnly)
-	So(prop.Computed, ShouldEqual, expectedComputed)
-}
-
-func assertSchemaParentProperty(actualSpecSchemaDefinition *specSchemaDefinition, expectedName string) {
-	assertSchemaProperty(actualSpecSchemaDefinition, expectedName, typeString, true, false, false)
-}
+	Convey("Given a SpecV2Resource containing a subresource path (one level) that has a non restful subresource path", t, func() {
+		r := &SpecV2Resource{
+			Path: "/v1/cdns/{id}/firewalls/v1/rules",
+		}
+		Convey("When getSchemaDefinition is called with a schema containing various properties", func() {
+			s := &spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Required: []string{"number_required_prop"},
+					Properties: map[string]spec.Schema{
+						"string_readonly_prop": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"string"},
+							},
+							SwaggerSchemaProps: spec.SwaggerSchemaProps{
+								ReadOnly: true,
+							},
+						},
+					},
+				},
+			}
+			specSchemaDefinition, err := r.getSchemaDefinition(s)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the specSchemaDefinition returned should be configured with the expected number of properties including the parent id one", func() {
+				So(len(specSchemaDefinition.Properties), ShouldEqual, len(s.SchemaProps.Properties)+1)
+			})
+			Convey("And the specSchemaDefinition returned should be configured as expected", func() {
+				assertSchemaProperty(specSchemaDefinition, "string_readonly_prop", typeString, false, true, true)
+			})
+			Convey("And the specSchemaDefinition returned should be configured with the parent id property marked as IsParentProperty, with the right name, type and being required", func() {
+				assertSchemaParentProperty(specSchemaDefinition, "cdns_v1_id")
+			})
+		})
+	})
 
-func TestGetResourceSchema(t *testing.T) {
-	Convey("Given a SpecV2Resource containing a root path", t, func() {
+	Convey("Given a SpecV2Resource containing a subresource path (two level)", t, func() {
 		r := &SpecV2Resource{
-			Path: "/cdns",
-			SchemaDefinition: spec.Schema{
+			Path:  "/v1/cdns/{cdn_id}/v2/firewalls/{fw_id}/rules",
+			Paths: nil,
+		}
+		Convey("When getSchemaDefinition is called with a schema containing various properties", func() {
+			s := &spec.Schema{
 				SchemaProps: spec.SchemaProps{
 					Required: []string{"number_required_prop"},
 					Properties: map[string]spec.Schema{
@@ -1109,74 +2004,69 @@ func TestGetResourceSchema(t *testing.T) {
 						},
 					},
 				},
-			},
-		}
-		Convey("When getSchemaDefinition is called with a schema containing various properties", func() {
-			specSchemaDefinition, err := r.getResourceSchema()
+			}
+			specSchemaDefinition, err := r.getSchemaDefinition(s)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
+			Convey("And the specSchemaDefinition returned should be configured with the expected number of properties including the parent id ones", func() {
+				So(len(specSchemaDefinition.Properties), ShouldEqual, len(s.SchemaProps.Properties)+2)
+			})
 			Convey("And the specSchemaDefinition returned should be configured as expected", func() {
-				So(len(specSchemaDefinition.Properties), ShouldEqual, len(r.SchemaDefinition.SchemaProps.Properties))
 				assertSchemaProperty(specSchemaDefinition, "string_readonly_prop", typeString, false, true, true)
 				assertSchemaProperty(specSchemaDefinition, "int_optional_computed_prop", typeInt, false, false, true)
 				assertSchemaProperty(specSchemaDefinition, "number_required_prop", typeFloat, true, false, false)
 				assertSchemaProperty(specSchemaDefinition, "bool_prop", typeBool, false, false, false)
 			})
+			Convey("And the specSchemaDefinition returned should be configured with the parent id property too", func() {
+				assertSchemaParentProperty(specSchemaDefinition, "cdns_v1_id")
+				assertSchemaParentProperty(specSchemaDefinition, "firewalls_v2_id")
+			})
 		})
 	})
-}
 
-func TestGetSchemaDefinition(t *testing.T) {
-
-	Convey("Given a blank SpecV2Resource", t, func() {
-		r := &SpecV2Resource{}
-		Convey("When getSchemaDefinition is called with a nil arg", func() {
-			_, err := r.getSchemaDefinition(nil)
-			Convey("Then the error returned matches the expected one", func() {
-				So(err.Error(), ShouldEqual, "schema argument must not be nil")
-			})
-		})
-		Convey("When getSchemaDefinition is called passing a blank schema", func() {
-			d, e := r.getSchemaDefinition(&spec.Schema{})
-			Convey("Then the error returned is not nil", func() {
-				So(e, ShouldBeNil)
-			})
-			Convey("And the schema definition contains empty Properties", func() {
-				So(d, ShouldNotBeNil)
-				So(d.Properties, ShouldBeEmpty)
-			})
-		})
-		Convey("When getSchemaDefinition is called passing a schema with a weird property type", func() {
-			schema := spec.Schema{
+	Convey("Given a SpecV2Resource that is a subresource (one level parent)", t, func() {
+		r := &SpecV2Resource{
+			Path: "/parent/{parent_id}/child",
+		}
+		Convey("When getSchemaDefinition is called with a schema containing various properties", func() {
+			s := &spec.Schema{
 				SchemaProps: spec.SchemaProps{
 					Properties: map[string]spec.Schema{
 						"string_readonly_prop": {
 							SchemaProps: spec.SchemaProps{
-								Type: spec.StringOrArray{"something weird"},
+								Type: spec.StringOrArray{"string"},
+							},
+							SwaggerSchemaProps: spec.SwaggerSchemaProps{
+								ReadOnly: true,
 							},
 						},
 					},
 				},
 			}
-			d, e := r.getSchemaDefinition(&schema)
-			Convey("Then the error returned is not nil", func() {
-				So(e, ShouldNotBeNil)
+			specSchemaDefinition, err := r.getSchemaDefinition(s)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
 			})
-			Convey("And the schema definition returned is nil", func() {
-				So(d, ShouldBeNil)
+			Convey("And the specSchemaDefinition returned should contain the expected number of properties (including the parent one)", func() {
+				So(len(specSchemaDefinition.Properties), ShouldEqual, 2)
+			})
+			Convey("And the specSchemaDefinition returned should be configured as expected", func() {
+				assertSchemaProperty(specSchemaDefinition, "string_readonly_prop", typeString, false, true, true)
+			})
+			Convey("And the specSchemaDefinition returned should also include the parent property", func() {
+				assertSchemaParentProperty(specSchemaDefinition, "parent_id")
 			})
 		})
-
 	})
-	Convey("Given a SpecV2Resource containing a root path", t, func() {
+
+	Convey("Given a SpecV2Resource that is a subresource (two level parent)", t, func() {
 		r := &SpecV2Resource{
-			Path: "/cdns",
+			Path: "/parent/{parent_id}/subparent/{subparent_id}/child",
 		}
 		Convey("When getSchemaDefinition is called with a schema containing various properties", func() {
 			s := &spec.Schema{
 				SchemaProps: spec.SchemaProps{
-					Required: []string{"number_required_prop"},
 					Properties: map[string]spec.Schema{
 						"string_readonly_prop": {
 							SchemaProps: spec.SchemaProps{
@@ -1186,1070 +2076,1275 @@ func TestGetSchemaDefinition(t *testing.T) {
 								ReadOnly: true,
 							},
 						},
-						"int_optional_computed_prop": {
-							SchemaProps: spec.SchemaProps{
-								Type: spec.StringOrArray{"integer"},
-							},
+					},
+				},
+			}
+			specSchemaDefinition, err := r.getSchemaDefinition(s)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the specSchemaDefinition returned should contain the expected number of properties (including the parent ones)", func() {
+				So(len(specSchemaDefinition.Properties), ShouldEqual, 3)
+			})
+			Convey("And the specSchemaDefinition returned should be configured as expected", func() {
+				assertSchemaProperty(specSchemaDefinition, "string_readonly_prop", typeString, false, true, true)
+			})
+			Convey("And the specSchemaDefinition returned should also include the parents properties", func() {
+				assertSchemaParentProperty(specSchemaDefinition, "parent_id")
+				assertSchemaParentProperty(specSchemaDefinition, "subparent_id")
+			})
+		})
+	})
+
+	Convey("Given a SpecV2Resource containing a sub-resource path (one level) and the parent resource using a preferred resource name", t, func() {
+		r := SpecV2Resource{
+			Path: "/v1/cdns/{id}/firewalls",
+			Paths: map[string]spec.PathItem{
+				"/v1/cdns": {
+					PathItemProps: spec.PathItemProps{
+						Post: &spec.Operation{
 							VendorExtensible: spec.VendorExtensible{
 								Extensions: spec.Extensions{
-									extTfComputed: true,
+									extTfResourceName: "cdn",
 								},
 							},
 						},
-						"number_required_prop": {
+					},
+				},
+			},
+		}
+		Convey("When getSchemaDefinition is called with a schema containing various properties", func() {
+			s := &spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Required: []string{"number_required_prop"},
+					Properties: map[string]spec.Schema{
+						"string_readonly_prop": {
 							SchemaProps: spec.SchemaProps{
-								Type: spec.StringOrArray{"number"},
+								Type: spec.StringOrArray{"string"},
 							},
-						},
-						"bool_prop": {
-							SchemaProps: spec.SchemaProps{
-								Type: spec.StringOrArray{"boolean"},
+							SwaggerSchemaProps: spec.SwaggerSchemaProps{
+								ReadOnly: true,
 							},
 						},
 					},
 				},
 			}
-			specSchemaDefinition, err := r.getSchemaDefinition(s)
+			specSchemaDefinition, err := r.getSchemaDefinition(s)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the specSchemaDefinition returned should be configured with the expected number of properties including the parent id one", func() {
+				So(len(specSchemaDefinition.Properties), ShouldEqual, len(s.SchemaProps.Properties)+1)
+			})
+			Convey("And the specSchemaDefinition returned should be configured as expected", func() {
+				assertSchemaProperty(specSchemaDefinition, "string_readonly_prop", typeString, false, true, true)
+			})
+			Convey("And the specSchemaDefinition returned should be configured with the parent id property named using the preferred parent name configured in the parent resource", func() {
+				assertSchemaParentProperty(specSchemaDefinition, "cdn_v1_id")
+			})
+		})
+	})
+}
+
+func TestGetResourcePath(t *testing.T) {
+
+	Convey("Given a SpecV2Resource with path resource that is not parameterised (root resource)", t, func() {
+		r := SpecV2Resource{
+			Path: "/v1/cdns",
+		}
+		Convey("When getResourcePath is called with an empty list of IDs", func() {
+			resourcePath, err := r.getResourcePath([]string{})
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the returned resource path should match the expected one", func() {
+				So(resourcePath, ShouldEqual, "/v1/cdns")
+			})
+		})
+		Convey("When getResourcePath is called with a nil list of IDs", func() {
+			resourcePath, err := r.getResourcePath(nil)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the returned resource path should match the expected one", func() {
+				So(resourcePath, ShouldEqual, "/v1/cdns")
+			})
+		})
+	})
+
+	Convey("Given a SpecV2Resource with path resource that is parameterised (one level sub-resource)", t, func() {
+		r := SpecV2Resource{
+			Path: "/v1/cdns/{cdn_id}/v1/firewalls",
+		}
+		Convey("When getResourcePath is called with a list of IDs", func() {
+			ids := []string{"parentID"}
+			resourcePath, err := r.getResourcePath(ids)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the returned resource path should match the expected one", func() {
+				So(resourcePath, ShouldEqual, "/v1/cdns/parentID/v1/firewalls")
+			})
+		})
+		Convey("When getResourcePath is called with an empty list of IDs", func() {
+			_, err := r.getResourcePath([]string{})
+			Convey("Then the error returned should not be nil", func() {
+				So(err.Error(), ShouldEqual, "could not resolve sub-resource path correctly '/v1/cdns/{cdn_id}/v1/firewalls' with the given ids - missing ids to resolve the path params properly: []")
+			})
+		})
+		Convey("When getResourcePath is called with an nil list of IDs", func() {
+			_, err := r.getResourcePath(nil)
+			Convey("Then the error returned should not be nil", func() {
+				So(err.Error(), ShouldEqual, "could not resolve sub-resource path correctly '/v1/cdns/{cdn_id}/v1/firewalls' with the given ids - missing ids to resolve the path params properly: []")
+			})
+		})
+		Convey("When getResourcePath is called with a list of IDs that is bigger than the parameterised params in the path", func() {
+			_, err := r.getResourcePath([]string{"cdnID", "somethingThatDoesNotBelongHere"})
+			Convey("Then the error returned should not be nil", func() {
+				So(err.Error(), ShouldEqual, "could not resolve sub-resource path correctly '/v1/cdns/{cdn_id}/v1/firewalls' with the given ids - more ids than path params: [cdnID somethingThatDoesNotBelongHere]")
+			})
+		})
+		Convey("When getResourcePath is called with a list of IDs that are already URL encoded", func() {
+			resourcePath, err := r.getResourcePath([]string{"cdnID%2FsomethingElse"})
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the path returned should contain the ids as provided", func() {
+				So(resourcePath, ShouldEqual, "/v1/cdns/cdnID%2FsomethingElse/v1/firewalls")
+			})
+		})
+	})
+
+	Convey("Given a SpecV2Resource with path resource that is parameterised (few levels sub-resource)", t, func() {
+		r := SpecV2Resource{
+			Path: "/v1/cdns/{cdn_id}/v1/firewalls/{fw_id}/rules",
+		}
+		Convey("When getResourcePath is called with a list of IDs", func() {
+			ids := []string{"cdnID", "fwID"}
+			resourcePath, err := r.getResourcePath(ids)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the returned resource path should match the expected one", func() {
+				So(resourcePath, ShouldEqual, "/v1/cdns/cdnID/v1/firewalls/fwID/rules")
+			})
+		})
+	})
+}
+
+func TestCreateSchemaDefinitionProperty(t *testing.T) {
+	Convey("Given a SpecV2Resource", t, func() {
+		r := SpecV2Resource{}
+
+		//////////////////
+		// Type checks
+		//////////////////
+
+		Convey("When createSchemaDefinitionProperty is called with a propertyName and a propertySchema of type string that is not required", func() {
+			propertyName := "propertyName"
+			propertySchema := spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Type: spec.StringOrArray{"string"},
+				},
+			}
+			requiredProperties := []string{}
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the schema definition property should be configured with the right name and type", func() {
+				So(schemaDefinitionProperty.Name, ShouldEqual, propertyName)
+				So(schemaDefinitionProperty.Type, ShouldEqual, typeString)
+			})
+			Convey("And the schema definition property should not be readOnly", func() {
+				So(schemaDefinitionProperty.isReadOnly(), ShouldBeFalse)
+			})
+			Convey("And the schema definition property should not be computed", func() {
+				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
+			})
+			Convey("And the schema definition property should not be required", func() {
+				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
+			})
+		})
+		Convey("When createSchemaDefinitionProperty is called with a propertyName, propertySchema of type integer that is not required", func() {
+			propertyName := "propertyName"
+			propertySchema := spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Type: spec.StringOrArray{"integer"},
+				},
+			}
+			requiredProperties := []string{}
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the specSchemaDefinition returned should be configured as expected", func() {
-				So(len(specSchemaDefinition.Properties), ShouldEqual, len(s.SchemaProps.Properties))
-				assertSchemaProperty(specSchemaDefinition, "string_readonly_prop", typeString, false, true, true)
-				assertSchemaProperty(specSchemaDefinition, "int_optional_computed_prop", typeInt, false, false, true)
-				assertSchemaProperty(specSchemaDefinition, "number_required_prop", typeFloat, true, false, false)
-				assertSchemaProperty(specSchemaDefinition, "bool_prop", typeBool, false, false, false)
+			Convey("And the schema definition property should be configured with the right name and type", func() {
+				So(schemaDefinitionProperty.Name, ShouldEqual, propertyName)
+				So(schemaDefinitionProperty.Type, ShouldEqual, typeInt)
+			})
+			Convey("And the schema definition property should not be readOnly", func() {
+				So(schemaDefinitionProperty.isReadOnly(), ShouldBeFalse)
+			})
+			Convey("And the schema definition property should not be computed", func() {
+				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
+			})
+			Convey("And the schema definition property should not be required", func() {
+				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
 			})
 		})
-	})
-
-	Convey("Given a SpecV2Resource containing a subresource path (one level)", t, func() {
-		r := &SpecV2Resource{
-			Path: "/v1/cdns/{id}/firewalls",
-		}
-		Convey("When getSchemaDefinition is called with a schema containing various properties", func() {
-			s := &spec.Schema{
+		Convey("When createSchemaDefinitionProperty is called with a propertyName, propertySchema of type number that is not required", func() {
+			propertyName := "propertyName"
+			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
-					Required: []string{"number_required_prop"},
-					Properties: map[string]spec.Schema{
-						"string_readonly_prop": {
-							SchemaProps: spec.SchemaProps{
-								Type: spec.StringOrArray{"string"},
-							},
-							SwaggerSchemaProps: spec.SwaggerSchemaProps{
-								ReadOnly: true,
-							},
-						},
-						"int_optional_computed_prop": {
-							SchemaProps: spec.SchemaProps{
-								Type: spec.StringOrArray{"integer"},
-							},
-							VendorExtensible: spec.VendorExtensible{
-								Extensions: spec.Extensions{
-									extTfComputed: true,
-								},
-							},
-						},
-						"number_required_prop": {
-							SchemaProps: spec.SchemaProps{
-								Type: spec.StringOrArray{"number"},
-							},
-						},
-						"bool_prop": {
-							SchemaProps: spec.SchemaProps{
-								Type: spec.StringOrArray{"boolean"},
-							},
-						},
-					},
+					Type: spec.StringOrArray{"number"},
 				},
 			}
-			specSchemaDefinition, err := r.getSchemaDefinition(s)
+			requiredProperties := []string{}
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the specSchemaDefinition returned should be configured with the expected number of properties including the parent id one", func() {
-				So(len(specSchemaDefinition.Properties), ShouldEqual, len(s.SchemaProps.Properties)+1)
+			Convey("And the schema definition property should be configured with the right name and type", func() {
+				So(schemaDefinitionProperty.Name, ShouldEqual, propertyName)
+				So(schemaDefinitionProperty.Type, ShouldEqual, typeFloat)
 			})
-			Convey("And the specSchemaDefinition returned should be configured as expected", func() {
-				assertSchemaProperty(specSchemaDefinition, "string_readonly_prop", typeString, false, true, true)
-				assertSchemaProperty(specSchemaDefinition, "int_optional_computed_prop", typeInt, false, false, true)
-				assertSchemaProperty(specSchemaDefinition, "number_required_prop", typeFloat, true, false, false)
-				assertSchemaProperty(specSchemaDefinition, "bool_prop", typeBool, false, false, false)
+			Convey("And the schema definition property should not be readOnly", func() {
+				So(schemaDefinitionProperty.isReadOnly(), ShouldBeFalse)
 			})
-			Convey("And the specSchemaDefinition returned should be configured with the parent id property with the expected configuration", func() {
-				assertSchemaParentProperty(specSchemaDefinition, "cdns_v1_id")
+			Convey("And the schema definition property should not be computed", func() {
+				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
+			})
+			Convey("And the schema definition property should not be required", func() {
+				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
 			})
 		})
-	})
-
-	Convey("Given a SpecV2Resource containing a subresource path (one level) that has a non restful subresource path", t, func() {
-		r := &SpecV2Resource{
-			Path: "/v1/cdns/{id}/firewalls/v1/rules",
-		}
-		Convey("When getSchemaDefinition is called with a schema containing various properties", func() {
-			s := &spec.Schema{
+		Convey("When createSchemaDefinitionProperty is called with a propertyName, propertySchema of type boolean that is not required", func() {
+			propertyName := "propertyName"
+			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
-					Required: []string{"number_required_prop"},
-					Properties: map[string]spec.Schema{
-						"string_readonly_prop": {
-							SchemaProps: spec.SchemaProps{
-								Type: spec.StringOrArray{"string"},
-							},
-							SwaggerSchemaProps: spec.SwaggerSchemaProps{
-								ReadOnly: true,
-							},
-						},
-					},
+					Type: spec.StringOrArray{"boolean"},
 				},
 			}
-			specSchemaDefinition, err := r.getSchemaDefinition(s)
+			requiredProperties := []string{}
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the specSchemaDefinition returned should be configured with the expected number of properties including the parent id one", func() {
-				So(len(specSchemaDefinition.Properties), ShouldEqual, len(s.SchemaProps.Properties)+1)
+			Convey("And the schema definition property should be configured with the right name and type", func() {
+				So(schemaDefinitionProperty.Name, ShouldEqual, propertyName)
+				So(schemaDefinitionProperty.Type, ShouldEqual, typeBool)
 			})
-			Convey("And the specSchemaDefinition returned should be configured as expected", func() {
-				assertSchemaProperty(specSchemaDefinition, "string_readonly_prop", typeString, false, true, true)
+			Convey("And the schema definition property should not be readOnly", func() {
+				So(schemaDefinitionProperty.isReadOnly(), ShouldBeFalse)
 			})
-			Convey("And the specSchemaDefinition returned should be configured with the parent id property marked as IsParentProperty, with the right name, type and being required", func() {
-				assertSchemaParentProperty(specSchemaDefinition, "cdns_v1_id")
+			Convey("And the schema definition property should not be computed", func() {
+				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
+			})
+			Convey("And the schema definition property should not be required", func() {
+				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
 			})
 		})
-	})
 
-	Convey("Given a SpecV2Resource containing a subresource path (two level)", t, func() {
-		r := &SpecV2Resource{
-			Path:  "/v1/cdns/{cdn_id}/v2/firewalls/{fw_id}/rules",
-			Paths: nil,
-		}
-		Convey("When getSchemaDefinition is called with a schema containing various properties", func() {
-			s := &spec.Schema{
+		Convey("When createSchemaDefinitionProperty is called with a propertyName, propertySchema of unknown type", func() {
+			propertyName := "propertyName"
+			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
-					Required: []string{"number_required_prop"},
+					//Type: spec.StringOrArray{"boolean"}, NO TYPE ASSIGNED
+				},
+			}
+			requiredProperties := []string{}
+			_, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
+			Convey("Then the error returned should NOT be nil", func() {
+				So(err, ShouldNotBeNil)
+			})
+			Convey("And the error message should equal", func() {
+				So(err.Error(), ShouldEqual, "non supported '[]' type")
+			})
+		})
+
+		Convey("When createSchemaDefinitionProperty is called with a propertyName, propertySchema of type object with nested properties that is not required", func() {
+			propertyName := "propertyName"
+			propertySchema := spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Type: spec.StringOrArray{"object"},
 					Properties: map[string]spec.Schema{
-						"string_readonly_prop": {
+						"objectProperty": {
 							SchemaProps: spec.SchemaProps{
 								Type: spec.StringOrArray{"string"},
 							},
-							SwaggerSchemaProps: spec.SwaggerSchemaProps{
-								ReadOnly: true,
-							},
-						},
-						"int_optional_computed_prop": {
-							SchemaProps: spec.SchemaProps{
-								Type: spec.StringOrArray{"integer"},
-							},
-							VendorExtensible: spec.VendorExtensible{
-								Extensions: spec.Extensions{
-									extTfComputed: true,
-								},
-							},
-						},
-						"number_required_prop": {
-							SchemaProps: spec.SchemaProps{
-								Type: spec.StringOrArray{"number"},
-							},
-						},
-						"bool_prop": {
-							SchemaProps: spec.SchemaProps{
-								Type: spec.StringOrArray{"boolean"},
-							},
 						},
 					},
 				},
 			}
-			specSchemaDefinition, err := r.getSchemaDefinition(s)
+			requiredProperties := []string{}
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the specSchemaDefinition returned should be configured with the expected number of properties including the parent id ones", func() {
-				So(len(specSchemaDefinition.Properties), ShouldEqual, len(s.SchemaProps.Properties)+2)
+			Convey("And the schema definition property should be configured with the right name and type", func() {
+				So(schemaDefinitionProperty.Name, ShouldEqual, propertyName)
+				So(schemaDefinitionProperty.Type, ShouldEqual, typeObject)
 			})
-			Convey("And the specSchemaDefinition returned should be configured as expected", func() {
-				assertSchemaProperty(specSchemaDefinition, "string_readonly_prop", typeString, false, true, true)
-				assertSchemaProperty(specSchemaDefinition, "int_optional_computed_prop", typeInt, false, false, true)
-				assertSchemaProperty(specSchemaDefinition, "number_required_prop", typeFloat, true, false, false)
-				assertSchemaProperty(specSchemaDefinition, "bool_prop", typeBool, false, false, false)
+			Convey("And the schema definition property should not be readOnly", func() {
+				So(schemaDefinitionProperty.isReadOnly(), ShouldBeFalse)
 			})
-			Convey("And the specSchemaDefinition returned should be configured with the parent id property too", func() {
-				assertSchemaParentProperty(specSchemaDefinition, "cdns_v1_id")
-				assertSchemaParentProperty(specSchemaDefinition, "firewalls_v2_id")
+			Convey("And the schema definition property should not be computed", func() {
+				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
+			})
+			Convey("And the schema definition property should not be required", func() {
+				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
 			})
 		})
-	})
 
-	Convey("Given a SpecV2Resource that is a subresource (one level parent)", t, func() {
-		r := &SpecV2Resource{
-			Path: "/parent/{parent_id}/child",
-		}
-		Convey("When getSchemaDefinition is called with a schema containing various properties", func() {
-			s := &spec.Schema{
+		Convey("When createSchemaDefinitionProperty is called with a propertyName, propertySchema of type object with NO nested properties nor a REF", func() {
+			propertyName := "propertyName"
+			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
-					Properties: map[string]spec.Schema{
-						"string_readonly_prop": {
+					Type: spec.StringOrArray{"object"},
+					// Missing object schema information
+				},
+			}
+			requiredProperties := []string{}
+			_, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
+			Convey("Then the error returned should NOT be nil", func() {
+				So(err, ShouldNotBeNil)
+			})
+			Convey("And the error message should equal", func() {
+				So(err.Error(), ShouldEqual, "failed to process object type property 'propertyName': object is missing the nested schema definition or the ref is poitning to a non existing schema definition")
+			})
+		})
+
+		Convey("When createSchemaDefinitionProperty is called with a propertyName and non required propertySchema of type array with items of type string", func() {
+			propertyName := "propertyName"
+			propertySchema := spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Type: spec.StringOrArray{"array"},
+					Items: &spec.SchemaOrArray{
+						Schema: &spec.Schema{
 							SchemaProps: spec.SchemaProps{
 								Type: spec.StringOrArray{"string"},
 							},
-							SwaggerSchemaProps: spec.SwaggerSchemaProps{
-								ReadOnly: true,
-							},
 						},
 					},
 				},
 			}
-			specSchemaDefinition, err := r.getSchemaDefinition(s)
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, []string{})
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the specSchemaDefinition returned should contain the expected number of properties (including the parent one)", func() {
-				So(len(specSchemaDefinition.Properties), ShouldEqual, 2)
+			Convey("And the schema definition property should be configured with the right name and type", func() {
+				So(schemaDefinitionProperty.Name, ShouldEqual, propertyName)
+				So(schemaDefinitionProperty.Type, ShouldEqual, typeList)
+				So(schemaDefinitionProperty.ArrayItemsType, ShouldEqual, typeString)
+				So(schemaDefinitionProperty.SpecSchemaDefinition, ShouldBeNil)
 			})
-			Convey("And the specSchemaDefinition returned should be configured as expected", func() {
-				assertSchemaProperty(specSchemaDefinition, "string_readonly_prop", typeString, false, true, true)
+			Convey("And the schema definition property should not be readOnly", func() {
+				So(schemaDefinitionProperty.isReadOnly(), ShouldBeFalse)
+			})
+			Convey("And the schema definition property should not be computed", func() {
+				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
 			})
-			Convey("And the specSchemaDefinition returned should also include the parent property", func() {
-				assertSchemaParentProperty(specSchemaDefinition, "parent_id")
+			Convey("And the schema definition property should not be required", func() {
+				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
 			})
 		})
-	})
 
-	Convey("Given a SpecV2Resource that is a subresource (two level parent)", t, func() {
-		r := &SpecV2Resource{
-			Path: "/parent/{parent_id}/subparent/{subparent_id}/child",
-		}
-		Convey("When getSchemaDefinition is called with a schema containing various properties", func() {
-			s := &spec.Schema{
+		Convey("When createSchemaDefinitionProperty is called with a propertyName and propertySchema non required of type array with items of type object (nested)", func() {
+			propertyName := "propertyName"
+			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
-					Properties: map[string]spec.Schema{
-						"string_readonly_prop": {
+					Type: spec.StringOrArray{"array"},
+					Items: &spec.SchemaOrArray{
+						Schema: &spec.Schema{
 							SchemaProps: spec.SchemaProps{
-								Type: spec.StringOrArray{"string"},
-							},
-							SwaggerSchemaProps: spec.SwaggerSchemaProps{
-								ReadOnly: true,
+								Type: spec.StringOrArray{"object"},
+								Properties: map[string]spec.Schema{
+									"prop1": {
+										SchemaProps: spec.SchemaProps{
+											Type: spec.StringOrArray{"string"},
+										},
+									},
+									"prop2": {
+										SchemaProps: spec.SchemaProps{
+											Type: spec.StringOrArray{"integer"},
+										},
+									},
+								},
 							},
 						},
 					},
 				},
 			}
-			specSchemaDefinition, err := r.getSchemaDefinition(s)
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, []string{})
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the specSchemaDefinition returned should contain the expected number of properties (including the parent ones)", func() {
-				So(len(specSchemaDefinition.Properties), ShouldEqual, 3)
+			Convey("And the schema definition property should be configured with the right name, list type amd items type object", func() {
+				So(schemaDefinitionProperty.Name, ShouldEqual, propertyName)
+				So(schemaDefinitionProperty.Type, ShouldEqual, typeList)
+				So(schemaDefinitionProperty.ArrayItemsType, ShouldEqual, typeObject)
 			})
-			Convey("And the specSchemaDefinition returned should be configured as expected", func() {
-				assertSchemaProperty(specSchemaDefinition, "string_readonly_prop", typeString, false, true, true)
+			Convey("And schema definition should contain the schema of the array items", func() {
+				So(schemaDefinitionProperty.SpecSchemaDefinition, ShouldNotBeNil)
+				exists, _ := assertPropertyExists(schemaDefinitionProperty.SpecSchemaDefinition.Properties, "prop1")
+				So(exists, ShouldBeTrue)
+				exists, _ = assertPropertyExists(schemaDefinitionProperty.SpecSchemaDefinition.Properties, "prop2")
+				So(exists, ShouldBeTrue)
+
 			})
-			Convey("And the specSchemaDefinition returned should also include the parents properties", func() {
-				assertSchemaParentProperty(specSchemaDefinition, "parent_id")
-				assertSchemaParentProperty(specSchemaDefinition, "subparent_id")
+			Convey("And the schema definition property should not be readOnly", func() {
+				So(schemaDefinitionProperty.isReadOnly(), ShouldBeFalse)
+			})
+			Convey("And the schema definition property should not be computed", func() {
+				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
+			})
+			Convey("And the schema definition property should not be required", func() {
+				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
 			})
 		})
-	})
 
-	Convey("Given a SpecV2Resource containing a sub-resource path (one level) and the parent resource using a preferred resource name", t, func() {
-		r := SpecV2Resource{
-			Path: "/v1/cdns/{id}/firewalls",
-			Paths: map[string]spec.PathItem{
-				"/v1/cdns": {
-					PathItemProps: spec.PathItemProps{
-						Post: &spec.Operation{
-							VendorExtensible: spec.VendorExtensible{
-								Extensions: spec.Extensions{
-									extTfResourceName: "cdn",
+		Convey("When createSchemaDefinitionProperty is called with a propertyName and propertySchema non required of type array with items of type object (external ref definition)", func() {
+			r := SpecV2Resource{
+				SchemaDefinitions: map[string]spec.Schema{
+					"Listeners": {
+						SchemaProps: spec.SchemaProps{
+							Type: spec.StringOrArray{"object"},
+							Properties: map[string]spec.Schema{
+								"protocol": {
+									SchemaProps: spec.SchemaProps{
+										Type: spec.StringOrArray{"string"},
+									},
 								},
 							},
 						},
 					},
 				},
-			},
-		}
-		Convey("When getSchemaDefinition is called with a schema containing various properties", func() {
-			s := &spec.Schema{
+			}
+			propertyName := "propertyName"
+			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
-					Required: []string{"number_required_prop"},
-					Properties: map[string]spec.Schema{
-						"string_readonly_prop": {
+					Type: spec.StringOrArray{"array"},
+					Items: &spec.SchemaOrArray{
+						Schema: &spec.Schema{
 							SchemaProps: spec.SchemaProps{
-								Type: spec.StringOrArray{"string"},
-							},
-							SwaggerSchemaProps: spec.SwaggerSchemaProps{
-								ReadOnly: true,
+								Ref: spec.Ref{Ref: jsonreference.MustCreateRef("#/definitions/Listeners")},
 							},
 						},
 					},
 				},
 			}
-			specSchemaDefinition, err := r.getSchemaDefinition(s)
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, []string{})
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the specSchemaDefinition returned should be configured with the expected number of properties including the parent id one", func() {
-				So(len(specSchemaDefinition.Properties), ShouldEqual, len(s.SchemaProps.Properties)+1)
+			Convey("And the schema definition property should be configured with the right name, list type amd items type object", func() {
+				So(schemaDefinitionProperty.Name, ShouldEqual, propertyName)
+				So(schemaDefinitionProperty.Type, ShouldEqual, typeList)
+				So(schemaDefinitionProperty.ArrayItemsType, ShouldEqual, typeObject)
 			})
-			Convey("And the specSchemaDefinition returned should be configured as expected", func() {
-				assertSchemaProperty(specSchemaDefinition, "string_readonly_prop", typeString, false, true, true)
+			Convey("And schema definition should contain the schema of the array items", func() {
+				So(schemaDefinitionProperty.SpecSchemaDefinition, ShouldNotBeNil)
+				So(schemaDefinitionProperty.SpecSchemaDefinition.Properties, ShouldNotBeEmpty)
+				So(schemaDefinitionProperty.SpecSchemaDefinition.Properties[0].Name, ShouldEqual, "protocol")
+				So(schemaDefinitionProperty.SpecSchemaDefinition.Properties[0].Type, ShouldEqual, typeString)
 			})
-			Convey("And the specSchemaDefinition returned should be configured with the parent id property named using the preferred parent name configured in the parent resource", func() {
-				assertSchemaParentProperty(specSchemaDefinition, "cdn_v1_id")
+			Convey("And the schema definition property should not be readOnly", func() {
+				So(schemaDefinitionProperty.isReadOnly(), ShouldBeFalse)
+			})
+			Convey("And the schema definition property should not be computed", func() {
+				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
+			})
+			Convey("And the schema definition property should not be required", func() {
+				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
 			})
 		})
-	})
-}
-
-func TestGetResourcePath(t *testing.T) {
 
-	Convey("Given a SpecV2Resource with path resource that is not parameterised (root resource)", t, func() {
-		r := SpecV2Resource{
-			Path: "/v1/cdns",
-		}
-		Convey("When getResourcePath is called with an empty list of IDs", func() {
-			resourcePath, err := r.getResourcePath([]string{})
+		Convey("When createSchemaDefinitionProperty is called with a property schema that is required", func() {
+			propertyName := "propertyName"
+			propertySchema := spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Type: spec.StringOrArray{"string"},
+				},
+			}
+			requiredProperties := []string{"propertyName"}
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the returned resource path should match the expected one", func() {
-				So(resourcePath, ShouldEqual, "/v1/cdns")
+			Convey("And the schema definition property should be required", func() {
+				So(schemaDefinitionProperty.Required, ShouldBeTrue)
+			})
+			Convey("And the schema definition property should not be readOnly", func() {
+				So(schemaDefinitionProperty.isReadOnly(), ShouldBeFalse)
+			})
+			Convey("And the schema definition property should not be computed", func() {
+				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
 			})
 		})
-		Convey("When getResourcePath is called with a nil list of IDs", func() {
-			resourcePath, err := r.getResourcePath(nil)
-			Convey("Then the error returned should be nil", func() {
-				So(err, ShouldBeNil)
+
+		Convey("When createSchemaDefinitionProperty is called with a property schema that is required AND the property is also set as readOnly (nonesense)", func() {
+			propertyName := "propertyName"
+			propertySchema := spec.Schema{
+				SwaggerSchemaProps: spec.SwaggerSchemaProps{
+					ReadOnly: true,
+				},
+				SchemaProps: spec.SchemaProps{
+					Type: spec.StringOrArray{"string"},
+				},
+			}
+			requiredProperties := []string{"propertyName"}
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
+			Convey("Then the error returned should NOT be nil", func() {
+				So(err, ShouldNotBeNil)
 			})
-			Convey("And the returned resource path should match the expected one", func() {
-				So(resourcePath, ShouldEqual, "/v1/cdns")
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "failed to process property 'propertyName': a required property cannot be readOnly too")
+			})
+			Convey("Then the result should be nil", func() {
+				So(schemaDefinitionProperty, ShouldBeNil)
 			})
 		})
-	})
 
-	Convey("Given a SpecV2Resource with path resource that is parameterised (one level sub-resource)", t, func() {
-		r := SpecV2Resource{
-			Path: "/v1/cdns/{cdn_id}/v1/firewalls",
-		}
-		Convey("When getResourcePath is called with a list of IDs", func() {
-			ids := []string{"parentID"}
-			resourcePath, err := r.getResourcePath(ids)
+		Convey("When createSchemaDefinitionProperty is called with an optional property schema that is computed (readOnly)", func() {
+			propertyName := "propertyName"
+			propertySchema := spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Type: spec.StringOrArray{"string"},
+				},
+				SwaggerSchemaProps: spec.SwaggerSchemaProps{
+					ReadOnly: true,
+				},
+			}
+			requiredProperties := []string{}
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the returned resource path should match the expected one", func() {
-				So(resourcePath, ShouldEqual, "/v1/cdns/parentID/v1/firewalls")
+			Convey("And the schema definition property should not be required", func() {
+				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
 			})
-		})
-		Convey("When getResourcePath is called with an empty list of IDs", func() {
-			_, err := r.getResourcePath([]string{})
-			Convey("Then the error returned should not be nil", func() {
-				So(err.Error(), ShouldEqual, "could not resolve sub-resource path correctly '/v1/cdns/{cdn_id}/v1/firewalls' with the given ids - missing ids to resolve the path params properly: []")
+			Convey("And the schema definition property should be readOnly", func() {
+				So(schemaDefinitionProperty.isReadOnly(), ShouldBeTrue)
 			})
-		})
-		Convey("When getResourcePath is called with an nil list of IDs", func() {
-			_, err := r.getResourcePath(nil)
-			Convey("Then the error returned should not be nil", func() {
-				So(err.Error(), ShouldEqual, "could not resolve sub-resource path correctly '/v1/cdns/{cdn_id}/v1/firewalls' with the given ids - missing ids to resolve the path params properly: []")
+			Convey("And the schema definition property should be computed", func() {
+				So(schemaDefinitionProperty.isComputed(), ShouldBeTrue)
 			})
 		})
-		Convey("When getResourcePath is called with a list of IDs that is bigger than the parameterised params in the path", func() {
-			_, err := r.getResourcePath([]string{"cdnID", "somethingThatDoesNotBelongHere"})
-			Convey("Then the error returned should not be nil", func() {
-				So(err.Error(), ShouldEqual, "could not resolve sub-resource path correctly '/v1/cdns/{cdn_id}/v1/firewalls' with the given ids - more ids than path params: [cdnID somethingThatDoesNotBelongHere]")
+
+		Convey("When createSchemaDefinitionProperty is called with an optional property schema that is computed (readOnly) AND has a default value (meaning the computed value is known at runtime)", func() {
+			expectedDefaultValue := "someDefaultValue"
+			propertyName := "propertyName"
+			propertySchema := spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Type:    spec.StringOrArray{"string"},
+					Default: expectedDefaultValue,
+				},
+				SwaggerSchemaProps: spec.SwaggerSchemaProps{
+					ReadOnly: true,
+				},
+			}
+			requiredProperties := []string{}
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
 			})
-		})
-		Convey("When getResourcePath is called with a list of IDs twhere some IDs contain forward slashes", func() {
-			_, err := r.getResourcePath([]string{"cdnID/somethingElse"})
-			Convey("Then the error returned should not be nil", func() {
-				So(err.Error(), ShouldEqual, "could not resolve sub-resource path correctly '/v1/cdns/{cdn_id}/v1/firewalls' due to parent IDs ([cdnID/somethingElse]) containing not supported characters (forward slashes)")
+			Convey("And the schema definition property should not be required", func() {
+				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
+			})
+			Convey("And the schema definition property should be readOnly", func() {
+				So(schemaDefinitionProperty.isReadOnly(), ShouldBeTrue)
+			})
+			Convey("And the schema definition property should be computed", func() {
+				So(schemaDefinitionProperty.isComputed(), ShouldBeTrue)
+			})
+			Convey("And the schema definition property should have the right default value", func() {
+				So(schemaDefinitionProperty.Default, ShouldEqual, expectedDefaultValue)
 			})
 		})
-	})
 
-	Convey("Given a SpecV2Resource with path resource that is parameterised (few levels sub-resource)", t, func() {
-		r := SpecV2Resource{
-			Path: "/v1/cdns/{cdn_id}/v1/firewalls/{fw_id}/rules",
-		}
-		Convey("When getResourcePath is called with a list of IDs", func() {
-			ids := []string{"cdnID", "fwID"}
-			resourcePath, err := r.getResourcePath(ids)
+		Convey("When createSchemaDefinitionProperty is called with an optional property schema", func() {
+			propertyName := "propertyWithNestedObj"
+			propertySchema := spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Type: spec.StringOrArray{"object"},
+					Properties: map[string]spec.Schema{
+						"nested_obj": {
+							SchemaProps: spec.SchemaProps{
+								Type: spec.StringOrArray{"object"},
+								Properties: map[string]spec.Schema{
+									"nested_prop": {
+										SchemaProps: spec.SchemaProps{
+											Type: spec.StringOrArray{"string"},
+										},
+									},
+								},
+							},
+						},
+					},
+				}}
+
+			requiredProperties := []string{}
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the returned resource path should match the expected one", func() {
-				So(resourcePath, ShouldEqual, "/v1/cdns/cdnID/v1/firewalls/fwID/rules")
+			Convey("And the schema definition property type should be an object", func() {
+				So(schemaDefinitionProperty.Type, ShouldEqual, typeObject)
 			})
-		})
-	})
-}
 
-func TestCreateSchemaDefinitionProperty(t *testing.T) {
-	Convey("Given a SpecV2Resource", t, func() {
-		r := SpecV2Resource{}
+			Convey("And the schema definition property specs should contain only 1 item of type object", func() {
+				So(len(schemaDefinitionProperty.SpecSchemaDefinition.Properties), ShouldEqual, 1)
+				So(schemaDefinitionProperty.SpecSchemaDefinition.Properties[0].Type, ShouldEqual, typeObject)
+			})
 
-		//////////////////
-		// Type checks
-		//////////////////
+			Convey("And the nested object's property is a string", func() {
+				nestedSpecSchema := *(schemaDefinitionProperty.SpecSchemaDefinition.Properties)[0]
+				So(nestedSpecSchema.SpecSchemaDefinition.Properties[0].Type, ShouldEqual, typeString)
+			})
 
-		Convey("When createSchemaDefinitionProperty is called with a propertyName and a propertySchema of type string that is not required", func() {
-			propertyName := "propertyName"
+		})
+
+		Convey("When createSchemaDefinitionProperty is called with an optional property schema that has a default value (this means the property is optional-computed, since the API is expected to honour the default value (known at runtime) if input is not provided by the client)", func() {
+			expectedDefaultValue := "someDefaultValue"
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
-					Type: spec.StringOrArray{"string"},
+					Type:    spec.StringOrArray{"string"},
+					Default: expectedDefaultValue,
 				},
 			}
-			requiredProperties := []string{}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the schema definition property should be configured with the right name and type", func() {
-				So(schemaDefinitionProperty.Name, ShouldEqual, propertyName)
-				So(schemaDefinitionProperty.Type, ShouldEqual, typeString)
+			Convey("And the schema definition property should be optional", func() {
+				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
 			})
 			Convey("And the schema definition property should not be readOnly", func() {
 				So(schemaDefinitionProperty.isReadOnly(), ShouldBeFalse)
 			})
-			Convey("And the schema definition property should not be computed", func() {
+			Convey("And the schema definition property should be computed", func() {
 				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
 			})
-			Convey("And the schema definition property should not be required", func() {
-				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
+			Convey("And the schema definition property should have the right default value", func() {
+				So(schemaDefinitionProperty.Default, ShouldEqual, expectedDefaultValue)
 			})
 		})
-		Convey("When createSchemaDefinitionProperty is called with a propertyName, propertySchema of type integer that is not required", func() {
-			propertyName := "propertyName"
+
+		/////////////////////
+		// Extension checks
+		/////////////////////
+
+		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-field-name' extension", func() {
+			expectedTerraformName := "property_terraform_name"
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
-					Type: spec.StringOrArray{"integer"},
+					Type: spec.StringOrArray{"string"},
+				},
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfFieldName: expectedTerraformName,
+					},
 				},
 			}
-			requiredProperties := []string{}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the schema definition property should be configured with the right name and type", func() {
-				So(schemaDefinitionProperty.Name, ShouldEqual, propertyName)
-				So(schemaDefinitionProperty.Type, ShouldEqual, typeInt)
-			})
-			Convey("And the schema definition property should not be readOnly", func() {
-				So(schemaDefinitionProperty.isReadOnly(), ShouldBeFalse)
+			Convey("And the schema definition property should be configured with the right", func() {
+				So(schemaDefinitionProperty.PreferredName, ShouldEqual, expectedTerraformName)
 			})
 			Convey("And the schema definition property should not be computed", func() {
 				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
 			})
-			Convey("And the schema definition property should not be required", func() {
-				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
-			})
 		})
-		Convey("When createSchemaDefinitionProperty is called with a propertyName, propertySchema of type number that is not required", func() {
-			propertyName := "propertyName"
+
+		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-force-new' extension", func() {
+			expectedForceNewValue := true
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
-					Type: spec.StringOrArray{"number"},
+					Type: spec.StringOrArray{"string"},
+				},
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfForceNew: expectedForceNewValue,
+					},
 				},
 			}
-			requiredProperties := []string{}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the schema definition property should be configured with the right name and type", func() {
-				So(schemaDefinitionProperty.Name, ShouldEqual, propertyName)
-				So(schemaDefinitionProperty.Type, ShouldEqual, typeFloat)
-			})
-			Convey("And the schema definition property should not be readOnly", func() {
-				So(schemaDefinitionProperty.isReadOnly(), ShouldBeFalse)
+			Convey("And the schema definition property should be have force new enabled", func() {
+				So(schemaDefinitionProperty.ForceNew, ShouldEqual, expectedForceNewValue)
 			})
 			Convey("And the schema definition property should not be computed", func() {
 				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
 			})
-			Convey("And the schema definition property should not be required", func() {
-				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
-			})
 		})
-		Convey("When createSchemaDefinitionProperty is called with a propertyName, propertySchema of type boolean that is not required", func() {
-			propertyName := "propertyName"
+
+		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-complex-object-legacy-config' extension", func() {
+			expectedValue := true
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
-					Type: spec.StringOrArray{"boolean"},
+					Type: spec.StringOrArray{"string"},
+				},
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfComplexObjectType: expectedValue,
+					},
 				},
 			}
-			requiredProperties := []string{}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the schema definition property should be configured with the right name and type", func() {
-				So(schemaDefinitionProperty.Name, ShouldEqual, propertyName)
-				So(schemaDefinitionProperty.Type, ShouldEqual, typeBool)
-			})
-			Convey("And the schema definition property should not be readOnly", func() {
-				So(schemaDefinitionProperty.isReadOnly(), ShouldBeFalse)
+			Convey("And the schema definition property should be have EnableLegacyComplexObjectBlockConfiguration enabled", func() {
+				So(schemaDefinitionProperty.EnableLegacyComplexObjectBlockConfiguration, ShouldEqual, expectedValue)
 			})
 			Convey("And the schema definition property should not be computed", func() {
 				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
 			})
-			Convey("And the schema definition property should not be required", func() {
-				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
-			})
 		})
 
-		Convey("When createSchemaDefinitionProperty is called with a propertyName, propertySchema of unknown type", func() {
-			propertyName := "propertyName"
+		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-sensitive' extension", func() {
+			expectedSensitiveValue := true
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
-					//Type: spec.StringOrArray{"boolean"}, NO TYPE ASSIGNED
+					Type: spec.StringOrArray{"string"},
+				},
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfSensitive: expectedSensitiveValue,
+					},
 				},
 			}
-			requiredProperties := []string{}
-			_, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
-			Convey("Then the error returned should NOT be nil", func() {
-				So(err, ShouldNotBeNil)
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
 			})
-			Convey("And the error message should equal", func() {
-				So(err.Error(), ShouldEqual, "non supported '[]' type")
+			Convey("And the schema definition property should be sensitive", func() {
+				So(schemaDefinitionProperty.Sensitive, ShouldEqual, expectedSensitiveValue)
+			})
+			Convey("And the schema definition property should not be computed", func() {
+				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
 			})
 		})
 
-		Convey("When createSchemaDefinitionProperty is called with a propertyName, propertySchema of type object with nested properties that is not required", func() {
-			propertyName := "propertyName"
+		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-id' extension", func() {
+			expectedIsIdentifierValue := true
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
-					Type: spec.StringOrArray{"object"},
-					Properties: map[string]spec.Schema{
-						"objectProperty": {
-							SchemaProps: spec.SchemaProps{
-								Type: spec.StringOrArray{"string"},
-							},
-						},
+					Type: spec.StringOrArray{"string"},
+				},
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfID: expectedIsIdentifierValue,
 					},
 				},
 			}
-			requiredProperties := []string{}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the schema definition property should be configured with the right name and type", func() {
-				So(schemaDefinitionProperty.Name, ShouldEqual, propertyName)
-				So(schemaDefinitionProperty.Type, ShouldEqual, typeObject)
-			})
-			Convey("And the schema definition property should not be readOnly", func() {
-				So(schemaDefinitionProperty.isReadOnly(), ShouldBeFalse)
+			Convey("And the schema definition property should be marked as identifier", func() {
+				So(schemaDefinitionProperty.IsIdentifier, ShouldEqual, expectedIsIdentifierValue)
 			})
 			Convey("And the schema definition property should not be computed", func() {
 				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
 			})
-			Convey("And the schema definition property should not be required", func() {
-				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
-			})
 		})
 
-		Convey("When createSchemaDefinitionProperty is called with a propertyName, propertySchema of type object with NO nested properties nor a REF", func() {
-			propertyName := "propertyName"
+		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-immutable' extension", func() {
+			expectedIsImmutableValue := true
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
-					Type: spec.StringOrArray{"object"},
-					// Missing object schema information
+					Type: spec.StringOrArray{"string"},
+				},
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfImmutable: expectedIsImmutableValue,
+					},
 				},
 			}
-			requiredProperties := []string{}
-			_, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
-			Convey("Then the error returned should NOT be nil", func() {
-				So(err, ShouldNotBeNil)
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
 			})
-			Convey("And the error message should equal", func() {
-				So(err.Error(), ShouldEqual, "failed to process object type property 'propertyName': object is missing the nested schema definition or the ref is poitning to a non existing schema definition")
+			Convey("And the schema definition property should be immutable", func() {
+				So(schemaDefinitionProperty.Immutable, ShouldEqual, expectedIsImmutableValue)
+			})
+			Convey("And the schema definition property should not be computed", func() {
+				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
 			})
 		})
 
-		Convey("When createSchemaDefinitionProperty is called with a propertyName and non required propertySchema of type array with items of type string", func() {
-			propertyName := "propertyName"
+		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-field-status' extension", func() {
+			expectedIsStatusFieldValue := true
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
-					Type: spec.StringOrArray{"array"},
-					Items: &spec.SchemaOrArray{
-						Schema: &spec.Schema{
-							SchemaProps: spec.SchemaProps{
-								Type: spec.StringOrArray{"string"},
-							},
-						},
+					Type: spec.StringOrArray{"string"},
+				},
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfFieldStatus: expectedIsStatusFieldValue,
 					},
 				},
 			}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, []string{})
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the schema definition property should be configured with the right name and type", func() {
-				So(schemaDefinitionProperty.Name, ShouldEqual, propertyName)
-				So(schemaDefinitionProperty.Type, ShouldEqual, typeList)
-				So(schemaDefinitionProperty.ArrayItemsType, ShouldEqual, typeString)
-				So(schemaDefinitionProperty.SpecSchemaDefinition, ShouldBeNil)
-			})
-			Convey("And the schema definition property should not be readOnly", func() {
-				So(schemaDefinitionProperty.isReadOnly(), ShouldBeFalse)
+			Convey("And the schema definition property should be marked as the status field", func() {
+				So(schemaDefinitionProperty.IsStatusIdentifier, ShouldEqual, expectedIsStatusFieldValue)
 			})
 			Convey("And the schema definition property should not be computed", func() {
 				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
 			})
-			Convey("And the schema definition property should not be required", func() {
-				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
+		})
+
+		Convey("When createSchemaDefinitionProperty is called with an integer property schema that has enum values", func() {
+			propertySchema := spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Type: spec.StringOrArray{"integer"},
+					Enum: []interface{}{float64(1), float64(2)},
+				},
+			}
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the schema definition property should contain the enum values", func() {
+				So(schemaDefinitionProperty.Enum, ShouldResemble, []interface{}{float64(1), float64(2)})
 			})
 		})
 
-		Convey("When createSchemaDefinitionProperty is called with a propertyName and propertySchema non required of type array with items of type object (nested)", func() {
-			propertyName := "propertyName"
+		Convey("When createSchemaDefinitionProperty is called with an array of strings property schema that has items enum, items pattern, min items and max items", func() {
+			minItems := int64(1)
+			maxItems := int64(3)
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
-					Type: spec.StringOrArray{"array"},
+					Type:     spec.StringOrArray{"array"},
+					MinItems: &minItems,
+					MaxItems: &maxItems,
 					Items: &spec.SchemaOrArray{
 						Schema: &spec.Schema{
 							SchemaProps: spec.SchemaProps{
-								Type: spec.StringOrArray{"object"},
-								Properties: map[string]spec.Schema{
-									"prop1": {
-										SchemaProps: spec.SchemaProps{
-											Type: spec.StringOrArray{"string"},
-										},
-									},
-									"prop2": {
-										SchemaProps: spec.SchemaProps{
-											Type: spec.StringOrArray{"integer"},
-										},
-									},
-								},
+								Type:    spec.StringOrArray{"string"},
+								Enum:    []interface{}{"dev", "prod"},
+								Pattern: "^[a-z]+$",
 							},
 						},
 					},
 				},
 			}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, []string{})
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the schema definition property should be configured with the right name, list type amd items type object", func() {
-				So(schemaDefinitionProperty.Name, ShouldEqual, propertyName)
-				So(schemaDefinitionProperty.Type, ShouldEqual, typeList)
-				So(schemaDefinitionProperty.ArrayItemsType, ShouldEqual, typeObject)
-			})
-			Convey("And schema definition should contain the schema of the array items", func() {
-				So(schemaDefinitionProperty.SpecSchemaDefinition, ShouldNotBeNil)
-				exists, _ := assertPropertyExists(schemaDefinitionProperty.SpecSchemaDefinition.Properties, "prop1")
-				So(exists, ShouldBeTrue)
-				exists, _ = assertPropertyExists(schemaDefinitionProperty.SpecSchemaDefinition.Properties, "prop2")
-				So(exists, ShouldBeTrue)
-
-			})
-			Convey("And the schema definition property should not be readOnly", func() {
-				So(schemaDefinitionProperty.isReadOnly(), ShouldBeFalse)
-			})
-			Convey("And the schema definition property should not be computed", func() {
-				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
-			})
-			Convey("And the schema definition property should not be required", func() {
-				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
+			Convey("And the schema definition property should contain the items constraints", func() {
+				So(schemaDefinitionProperty.ArrayItemsEnum, ShouldResemble, []interface{}{"dev", "prod"})
+				So(schemaDefinitionProperty.ArrayItemsPattern, ShouldEqual, "^[a-z]+$")
+				So(schemaDefinitionProperty.MinItems, ShouldEqual, 1)
+				So(schemaDefinitionProperty.MaxItems, ShouldEqual, 3)
 			})
 		})
 
-		Convey("When createSchemaDefinitionProperty is called with a propertyName and propertySchema non required of type array with items of type object (external ref definition)", func() {
-			r := SpecV2Resource{
-				SchemaDefinitions: map[string]spec.Schema{
-					"Listeners": {
-						SchemaProps: spec.SchemaProps{
-							Type: spec.StringOrArray{"object"},
-							Properties: map[string]spec.Schema{
-								"protocol": {
-									SchemaProps: spec.SchemaProps{
-										Type: spec.StringOrArray{"string"},
-									},
-								},
-							},
-						},
-					},
-				},
-			}
-			propertyName := "propertyName"
+		Convey("When createSchemaDefinitionProperty is called with an array of strings property schema that has an items pattern that is not valid", func() {
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
 					Type: spec.StringOrArray{"array"},
 					Items: &spec.SchemaOrArray{
 						Schema: &spec.Schema{
 							SchemaProps: spec.SchemaProps{
-								Ref: spec.Ref{Ref: jsonreference.MustCreateRef("#/definitions/Listeners")},
+								Type:    spec.StringOrArray{"string"},
+								Pattern: "^[a-z+$(",
 							},
 						},
 					},
 				},
 			}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, []string{})
-			Convey("Then the error returned should be nil", func() {
-				So(err, ShouldBeNil)
-			})
-			Convey("And the schema definition property should be configured with the right name, list type amd items type object", func() {
-				So(schemaDefinitionProperty.Name, ShouldEqual, propertyName)
-				So(schemaDefinitionProperty.Type, ShouldEqual, typeList)
-				So(schemaDefinitionProperty.ArrayItemsType, ShouldEqual, typeObject)
-			})
-			Convey("And schema definition should contain the schema of the array items", func() {
-				So(schemaDefinitionProperty.SpecSchemaDefinition, ShouldNotBeNil)
-				So(schemaDefinitionProperty.SpecSchemaDefinition.Properties, ShouldNotBeEmpty)
-				So(schemaDefinitionProperty.SpecSchemaDefinition.Properties[0].Name, ShouldEqual, "protocol")
-				So(schemaDefinitionProperty.SpecSchemaDefinition.Properties[0].Type, ShouldEqual, typeString)
-			})
-			Convey("And the schema definition property should not be readOnly", func() {
-				So(schemaDefinitionProperty.isReadOnly(), ShouldBeFalse)
-			})
-			Convey("And the schema definition property should not be computed", func() {
-				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
-			})
-			Convey("And the schema definition property should not be required", func() {
-				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
+			_, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldStartWith, "failed to process array type property 'propertyName': items pattern '^[a-z+$(' is not valid")
 			})
 		})
 
-		Convey("When createSchemaDefinitionProperty is called with a property schema that is required", func() {
-			propertyName := "propertyName"
+		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-field-status-message' extension", func() {
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
 					Type: spec.StringOrArray{"string"},
 				},
+				SwaggerSchemaProps: spec.SwaggerSchemaProps{
+					ReadOnly: true,
+				},
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfFieldStatusMessage: true,
+					},
+				},
 			}
-			requiredProperties := []string{"propertyName"}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the schema definition property should be required", func() {
-				So(schemaDefinitionProperty.Required, ShouldBeTrue)
-			})
-			Convey("And the schema definition property should not be readOnly", func() {
-				So(schemaDefinitionProperty.isReadOnly(), ShouldBeFalse)
-			})
-			Convey("And the schema definition property should not be computed", func() {
-				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
+			Convey("And the schema definition property should be marked as the status message field", func() {
+				So(schemaDefinitionProperty.IsStatusMessage, ShouldBeTrue)
 			})
 		})
 
-		Convey("When createSchemaDefinitionProperty is called with a property schema that is required AND the property is also set as readOnly (nonesense)", func() {
-			propertyName := "propertyName"
+		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-field-copy-to' extension", func() {
 			propertySchema := spec.Schema{
-				SwaggerSchemaProps: spec.SwaggerSchemaProps{
-					ReadOnly: true,
-				},
 				SchemaProps: spec.SchemaProps{
 					Type: spec.StringOrArray{"string"},
 				},
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfFieldCopyTo: "display_name, title,propertyName",
+					},
+				},
 			}
-			requiredProperties := []string{"propertyName"}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
-			Convey("Then the error returned should NOT be nil", func() {
-				So(err, ShouldNotBeNil)
-			})
-			Convey("Then the error returned should match the expected one", func() {
-				So(err.Error(), ShouldEqual, "failed to process property 'propertyName': a required property cannot be readOnly too")
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
 			})
-			Convey("Then the result should be nil", func() {
-				So(schemaDefinitionProperty, ShouldBeNil)
+			Convey("And the schema definition property should contain the fields to copy the value to, excluding the property itself", func() {
+				So(schemaDefinitionProperty.CopyTo, ShouldResemble, []string{"display_name", "title"})
 			})
 		})
 
-		Convey("When createSchemaDefinitionProperty is called with an optional property schema that is computed (readOnly)", func() {
-			propertyName := "propertyName"
+		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-property-alias' extension", func() {
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
 					Type: spec.StringOrArray{"string"},
 				},
-				SwaggerSchemaProps: spec.SwaggerSchemaProps{
-					ReadOnly: true,
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfPropertyAlias: "label, displayName,property_name",
+					},
 				},
 			}
-			requiredProperties := []string{}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the schema definition property should not be required", func() {
-				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
-			})
-			Convey("And the schema definition property should be readOnly", func() {
-				So(schemaDefinitionProperty.isReadOnly(), ShouldBeTrue)
-			})
-			Convey("And the schema definition property should be computed", func() {
-				So(schemaDefinitionProperty.isComputed(), ShouldBeTrue)
+			Convey("And the schema definition property should contain the terraform compliant aliases, excluding the property itself", func() {
+				So(schemaDefinitionProperty.Aliases, ShouldResemble, []string{"label", "display_name"})
 			})
 		})
 
-		Convey("When createSchemaDefinitionProperty is called with an optional property schema that is computed (readOnly) AND has a default value (meaning the computed value is known at runtime)", func() {
-			expectedDefaultValue := "someDefaultValue"
-			propertyName := "propertyName"
+		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-derived' extension", func() {
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
-					Type:    spec.StringOrArray{"string"},
-					Default: expectedDefaultValue,
+					Type: spec.StringOrArray{"string"},
 				},
-				SwaggerSchemaProps: spec.SwaggerSchemaProps{
-					ReadOnly: true,
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfDerived: "https://{host}:{port}",
+					},
 				},
 			}
-			requiredProperties := []string{}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("endpoint", propertySchema, []string{})
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the schema definition property should not be required", func() {
-				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
-			})
-			Convey("And the schema definition property should be readOnly", func() {
+			Convey("And the schema definition property should be a readOnly property with the derived template", func() {
+				So(schemaDefinitionProperty.DerivedTemplate, ShouldEqual, "https://{host}:{port}")
 				So(schemaDefinitionProperty.isReadOnly(), ShouldBeTrue)
-			})
-			Convey("And the schema definition property should be computed", func() {
 				So(schemaDefinitionProperty.isComputed(), ShouldBeTrue)
 			})
-			Convey("And the schema definition property should have the right default value", func() {
-				So(schemaDefinitionProperty.Default, ShouldEqual, expectedDefaultValue)
-			})
 		})
 
-		Convey("When createSchemaDefinitionProperty is called with an optional property schema", func() {
-			propertyName := "propertyWithNestedObj"
+		Convey("When createSchemaDefinitionProperty is called with a non string property schema that has the 'x-terraform-derived' extension", func() {
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
-					Type: spec.StringOrArray{"object"},
-					Properties: map[string]spec.Schema{
-						"nested_obj": {
-							SchemaProps: spec.SchemaProps{
-								Type: spec.StringOrArray{"object"},
-								Properties: map[string]spec.Schema{
-									"nested_prop": {
-										SchemaProps: spec.SchemaProps{
-											Type: spec.StringOrArray{"string"},
-										},
-									},
-								},
-							},
-						},
+					Type: spec.StringOrArray{"integer"},
+				},
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfDerived: "{port}",
 					},
-				}}
-
-			requiredProperties := []string{}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty(propertyName, propertySchema, requiredProperties)
-			Convey("Then the error returned should be nil", func() {
-				So(err, ShouldBeNil)
-			})
-			Convey("And the schema definition property type should be an object", func() {
-				So(schemaDefinitionProperty.Type, ShouldEqual, typeObject)
-			})
-
-			Convey("And the schema definition property specs should contain only 1 item of type object", func() {
-				So(len(schemaDefinitionProperty.SpecSchemaDefinition.Properties), ShouldEqual, 1)
-				So(schemaDefinitionProperty.SpecSchemaDefinition.Properties[0].Type, ShouldEqual, typeObject)
-			})
-
-			Convey("And the nested object's property is a string", func() {
-				nestedSpecSchema := *(schemaDefinitionProperty.SpecSchemaDefinition.Properties)[0]
-				So(nestedSpecSchema.SpecSchemaDefinition.Properties[0].Type, ShouldEqual, typeString)
+				},
+			}
+			_, err := r.createSchemaDefinitionProperty("endpoint", propertySchema, []string{})
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "failed to process property 'endpoint': extension 'x-terraform-derived' is only supported in properties of type string")
 			})
-
 		})
 
-		Convey("When createSchemaDefinitionProperty is called with an optional property schema that has a default value (this means the property is optional-computed, since the API is expected to honour the default value (known at runtime) if input is not provided by the client)", func() {
-			expectedDefaultValue := "someDefaultValue"
+		Convey("When createSchemaDefinitionProperty is called with a required property schema that has the 'x-terraform-derived' extension", func() {
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
-					Type:    spec.StringOrArray{"string"},
-					Default: expectedDefaultValue,
+					Type: spec.StringOrArray{"string"},
 				},
-			}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
-			Convey("Then the error returned should be nil", func() {
-				So(err, ShouldBeNil)
-			})
-			Convey("And the schema definition property should be optional", func() {
-				So(schemaDefinitionProperty.isRequired(), ShouldBeFalse)
-			})
-			Convey("And the schema definition property should not be readOnly", func() {
-				So(schemaDefinitionProperty.isReadOnly(), ShouldBeFalse)
-			})
-			Convey("And the schema definition property should be computed", func() {
-				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
-			})
-			Convey("And the schema definition property should have the right default value", func() {
-				So(schemaDefinitionProperty.Default, ShouldEqual, expectedDefaultValue)
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfDerived: "https://{host}",
+					},
+				},
+			}
+			_, err := r.createSchemaDefinitionProperty("endpoint", propertySchema, []string{"endpoint"})
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "failed to process property 'endpoint': a derived property cannot be required")
 			})
 		})
 
-		/////////////////////
-		// Extension checks
-		/////////////////////
-
-		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-field-name' extension", func() {
-			expectedTerraformName := "property_terraform_name"
+		Convey("When createSchemaDefinitionProperty is called with an optional property schema that has the 'x-terraform-required-if' extension", func() {
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
 					Type: spec.StringOrArray{"string"},
 				},
 				VendorExtensible: spec.VendorExtensible{
 					Extensions: spec.Extensions{
-						extTfFieldName: expectedTerraformName,
+						extTfRequiredIf: "type = vpn|ipsec",
 					},
 				},
 			}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("pre_shared_key", propertySchema, []string{})
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the schema definition property should be configured with the right", func() {
-				So(schemaDefinitionProperty.PreferredName, ShouldEqual, expectedTerraformName)
-			})
-			Convey("And the schema definition property should not be computed", func() {
-				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
+			Convey("And the schema definition property should be conditionally required with the condition property and values", func() {
+				So(schemaDefinitionProperty.isConditionallyRequired(), ShouldBeTrue)
+				So(schemaDefinitionProperty.RequiredIfProperty, ShouldEqual, "type")
+				So(schemaDefinitionProperty.RequiredIfValues, ShouldResemble, []string{"vpn", "ipsec"})
+				So(schemaDefinitionProperty.Required, ShouldBeFalse)
 			})
 		})
 
-		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-force-new' extension", func() {
-			expectedForceNewValue := true
+		Convey("When createSchemaDefinitionProperty is called with an optional property schema that has the 'x-terraform-default-from-api' extension", func() {
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
 					Type: spec.StringOrArray{"string"},
 				},
 				VendorExtensible: spec.VendorExtensible{
 					Extensions: spec.Extensions{
-						extTfForceNew: expectedForceNewValue,
+						extTfDefaultFromAPI: map[string]interface{}{"path": "/v1/defaults", "value_path": "$.network.id"},
 					},
 				},
 			}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("network_id", propertySchema, []string{})
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the schema definition property should be have force new enabled", func() {
-				So(schemaDefinitionProperty.ForceNew, ShouldEqual, expectedForceNewValue)
+			Convey("And the schema definition property should default to the value returned by the API", func() {
+				So(schemaDefinitionProperty.isDefaultFromAPI(), ShouldBeTrue)
+				So(schemaDefinitionProperty.DefaultFromAPI, ShouldResemble, &apiDefault{path: "/v1/defaults", valuePath: "$.network.id"})
 			})
-			Convey("And the schema definition property should not be computed", func() {
-				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
+		})
+
+		Convey("When createSchemaDefinitionProperty is called with a required property schema that has the 'x-terraform-default-from-api' extension", func() {
+			propertySchema := spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Type: spec.StringOrArray{"string"},
+				},
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfDefaultFromAPI: map[string]interface{}{"path": "/v1/defaults"},
+					},
+				},
+			}
+			_, err := r.createSchemaDefinitionProperty("network_id", propertySchema, []string{"network_id"})
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "failed to process property 'network_id': extension 'x-terraform-default-from-api' is only supported in optional properties that are not computed and have no default value")
 			})
 		})
 
-		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-complex-object-legacy-config' extension", func() {
-			expectedValue := true
+		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-default-from-api' extension with a value that is not valid", func() {
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
 					Type: spec.StringOrArray{"string"},
 				},
 				VendorExtensible: spec.VendorExtensible{
 					Extensions: spec.Extensions{
-						extTfComplexObjectType: expectedValue,
+						extTfDefaultFromAPI: "/v1/defaults",
 					},
 				},
 			}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
+			_, err := r.createSchemaDefinitionProperty("network_id", propertySchema, []string{})
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "failed to process property 'network_id': extension 'x-terraform-default-from-api' is not valid: expected an object value but got '/v1/defaults'")
+			})
+		})
+
+		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-force-new' and 'x-terraform-force-new-reason' extensions", func() {
+			propertySchema := spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Type: spec.StringOrArray{"string"},
+				},
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfForceNew:       true,
+						extTfForceNewReason: "the API does not support moving databases across regions",
+					},
+				},
+			}
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("region", propertySchema, []string{})
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the schema definition property should be have EnableLegacyComplexObjectBlockConfiguration enabled", func() {
-				So(schemaDefinitionProperty.EnableLegacyComplexObjectBlockConfiguration, ShouldEqual, expectedValue)
-			})
-			Convey("And the schema definition property should not be computed", func() {
-				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
+			Convey("And the schema definition property should be force new with the reason", func() {
+				So(schemaDefinitionProperty.ForceNew, ShouldBeTrue)
+				So(schemaDefinitionProperty.ForceNewReason, ShouldEqual, "the API does not support moving databases across regions")
 			})
 		})
 
-		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-sensitive' extension", func() {
-			expectedSensitiveValue := true
+		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-force-new-reason' extension but not the 'x-terraform-force-new' one", func() {
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
 					Type: spec.StringOrArray{"string"},
 				},
 				VendorExtensible: spec.VendorExtensible{
 					Extensions: spec.Extensions{
-						extTfSensitive: expectedSensitiveValue,
+						extTfForceNewReason: "the API does not support moving databases across regions",
 					},
 				},
 			}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
-			Convey("Then the error returned should be nil", func() {
-				So(err, ShouldBeNil)
+			_, err := r.createSchemaDefinitionProperty("region", propertySchema, []string{})
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "failed to process property 'region': extension 'x-terraform-force-new-reason' is only supported in properties with the 'x-terraform-force-new' extension enabled")
 			})
-			Convey("And the schema definition property should be sensitive", func() {
-				So(schemaDefinitionProperty.Sensitive, ShouldEqual, expectedSensitiveValue)
+		})
+
+		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-required-if' extension with a value that is not valid", func() {
+			propertySchema := spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Type: spec.StringOrArray{"string"},
+				},
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfRequiredIf: "vpn",
+					},
+				},
+			}
+			_, err := r.createSchemaDefinitionProperty("pre_shared_key", propertySchema, []string{})
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "failed to process property 'pre_shared_key': extension 'x-terraform-required-if' value 'vpn' must follow the format '<property_name>=<value>[|<value>...]'")
 			})
-			Convey("And the schema definition property should not be computed", func() {
-				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
+		})
+
+		Convey("When createSchemaDefinitionProperty is called with a required property schema that has the 'x-terraform-required-if' extension", func() {
+			propertySchema := spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Type: spec.StringOrArray{"string"},
+				},
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfRequiredIf: "type=vpn",
+					},
+				},
+			}
+			_, err := r.createSchemaDefinitionProperty("pre_shared_key", propertySchema, []string{"pre_shared_key"})
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "failed to process property 'pre_shared_key': extension 'x-terraform-required-if' is only supported in optional properties")
 			})
 		})
 
-		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-id' extension", func() {
-			expectedIsIdentifierValue := true
+		Convey("When createSchemaDefinitionProperty is called with a readOnly property schema that has the 'x-terraform-refresh-on-demand' extension", func() {
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
 					Type: spec.StringOrArray{"string"},
 				},
+				SwaggerSchemaProps: spec.SwaggerSchemaProps{
+					ReadOnly: true,
+				},
 				VendorExtensible: spec.VendorExtensible{
 					Extensions: spec.Extensions{
-						extTfID: expectedIsIdentifierValue,
+						extTfRefreshOnDemand: true,
 					},
 				},
 			}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("usage_report", propertySchema, []string{})
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the schema definition property should be marked as identifier", func() {
-				So(schemaDefinitionProperty.IsIdentifier, ShouldEqual, expectedIsIdentifierValue)
-			})
-			Convey("And the schema definition property should not be computed", func() {
-				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
+			Convey("And the schema definition property should be refreshed on demand", func() {
+				So(schemaDefinitionProperty.RefreshOnDemand, ShouldBeTrue)
 			})
 		})
 
-		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-immutable' extension", func() {
-			expectedIsImmutableValue := true
+		Convey("When createSchemaDefinitionProperty is called with a property schema that is not computed and has the 'x-terraform-refresh-on-demand' extension", func() {
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
 					Type: spec.StringOrArray{"string"},
 				},
 				VendorExtensible: spec.VendorExtensible{
 					Extensions: spec.Extensions{
-						extTfImmutable: expectedIsImmutableValue,
+						extTfRefreshOnDemand: true,
 					},
 				},
 			}
-			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
+			_, err := r.createSchemaDefinitionProperty("label", propertySchema, []string{})
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "failed to process property 'label': extension 'x-terraform-refresh-on-demand' is only supported in computed properties")
+			})
+		})
+
+		Convey("When createSchemaDefinitionProperty is called with an array of objects property schema that has the set hash extensions", func() {
+			propertySchema := newSetHashArrayPropertySchema(spec.Extensions{
+				extTfSetHashKeys:       "name, protocol",
+				extTfSetHashIgnoreCase: true,
+			})
+			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("listeners", propertySchema, []string{})
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the schema definition property should be immutable", func() {
-				So(schemaDefinitionProperty.Immutable, ShouldEqual, expectedIsImmutableValue)
+			Convey("And the schema definition property should be configured as a set hashed by the given keys ignoring case", func() {
+				So(schemaDefinitionProperty.SetHashKeys, ShouldResemble, []string{"name", "protocol"})
+				So(schemaDefinitionProperty.SetHashIgnoreCase, ShouldBeTrue)
+				So(schemaDefinitionProperty.isSetProperty(), ShouldBeTrue)
 			})
-			Convey("And the schema definition property should not be computed", func() {
-				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
+		})
+
+		Convey("When createSchemaDefinitionProperty is called with an array of objects property schema that has a set hash key that is not an item property", func() {
+			propertySchema := newSetHashArrayPropertySchema(spec.Extensions{
+				extTfSetHashKeys: "name,port",
+			})
+			_, err := r.createSchemaDefinitionProperty("listeners", propertySchema, []string{})
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "failed to process property 'listeners': x-terraform-set-hash-keys key 'port' is not a property of the array items")
 			})
 		})
 
-		Convey("When createSchemaDefinitionProperty is called with a property schema that has the 'x-terraform-field-status' extension", func() {
-			expectedIsStatusFieldValue := true
+		Convey("When createSchemaDefinitionProperty is called with an array of objects property schema that has a set hash key that is not a primitive item property", func() {
+			propertySchema := newSetHashArrayPropertySchema(spec.Extensions{
+				extTfSetHashKeys: "options",
+			})
+			_, err := r.createSchemaDefinitionProperty("listeners", propertySchema, []string{})
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "failed to process property 'listeners': x-terraform-set-hash-keys key 'options' must be a primitive property (string, integer, number or boolean)")
+			})
+		})
+
+		Convey("When createSchemaDefinitionProperty is called with a string property schema that has the set hash extensions", func() {
 			propertySchema := spec.Schema{
 				SchemaProps: spec.SchemaProps{
 					Type: spec.StringOrArray{"string"},
 				},
 				VendorExtensible: spec.VendorExtensible{
 					Extensions: spec.Extensions{
-						extTfFieldStatus: expectedIsStatusFieldValue,
+						extTfSetHashIgnoreCase: true,
 					},
 				},
 			}
+			_, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "failed to process property 'propertyName': extensions 'x-terraform-set-hash-keys' and 'x-terraform-set-hash-ignore-case' are only supported in properties of type array with items of type object")
+			})
+		})
+
+		Convey("When createSchemaDefinitionProperty is called with a property schema that has a description", func() {
+			propertySchema := spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Type:        spec.StringOrArray{"string"},
+					Description: "some description",
+				},
+			}
 			schemaDefinitionProperty, err := r.createSchemaDefinitionProperty("propertyName", propertySchema, []string{})
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
-			Convey("And the schema definition property should be marked as the status field", func() {
-				So(schemaDefinitionProperty.IsStatusIdentifier, ShouldEqual, expectedIsStatusFieldValue)
-			})
-			Convey("And the schema definition property should not be computed", func() {
-				So(schemaDefinitionProperty.isComputed(), ShouldBeFalse)
+			Convey("And the schema definition property should contain the description", func() {
+				So(schemaDefinitionProperty.Description, ShouldEqual, "some description")
 			})
 		})
 
@@ -3393,6 +4488,14 @@ func TestCreateResponses(t *testing.T) {
 			extensions.Add(extTfResourcePollEnabled, true)
 			extensions.Add(extTfResourcePollTargetStatuses, expectedTarget)
 			extensions.Add(extTfResourcePollPendingStatuses, expectedStatus)
+			extensions.Add(extTfResourcePollFailedStatuses, "deploy_failed, deploy_cancelled")
+			extensions.Add(extTfResourcePollStatusPath, "$.operation.state")
+			extensions.Add(extTfResourcePollInterval, "10s")
+			extensions.Add(extTfResourcePollStrategy, "exponential")
+			extensions.Add(extTfResourcePollMaxInterval, "5m")
+			extensions.Add(extTfResourcePollEventsPath, "events")
+			extensions.Add(extTfResourcePollEventsProperty, "last_event")
+			extensions.Add(extTfResourcePollLocationHeader, "Operation-Location")
 			operation := &spec.Operation{
 				OperationProps: spec.OperationProps{
 					Responses: &spec.Responses{
@@ -3419,6 +4522,71 @@ func TestCreateResponses(t *testing.T) {
 				So(specResponses[http.StatusAccepted].isPollingEnabled, ShouldBeTrue)
 				So(specResponses[http.StatusAccepted].pollTargetStatuses, ShouldContain, expectedTarget)
 				So(specResponses[http.StatusAccepted].pollPendingStatuses, ShouldContain, expectedStatus)
+				So(specResponses[http.StatusAccepted].pollFailedStatuses, ShouldResemble, []string{"deploy_failed", "deploy_cancelled"})
+				So(specResponses[http.StatusAccepted].pollStatusPath, ShouldEqual, "$.operation.state")
+				So(specResponses[http.StatusAccepted].pollInterval, ShouldEqual, 10*time.Second)
+				So(specResponses[http.StatusAccepted].pollStrategy, ShouldEqual, pollStrategyExponential)
+				So(specResponses[http.StatusAccepted].pollMaxInterval, ShouldEqual, 5*time.Minute)
+				So(specResponses[http.StatusAccepted].pollEventsPath, ShouldEqual, "events")
+				So(specResponses[http.StatusAccepted].pollEventsProperty, ShouldEqual, "last_event")
+				So(specResponses[http.StatusAccepted].pollLocationHeader, ShouldEqual, "Operation-Location")
+			})
+		})
+
+		Convey("When createResponses method is called with an operation that has responses with different schemas and the 'x-terraform-resource-summary-response' extension set to true", func() {
+			extensions := spec.Extensions{}
+			extensions.Add(extTfResourceSummaryResponse, true)
+			operation := &spec.Operation{
+				OperationProps: spec.OperationProps{
+					Responses: &spec.Responses{
+						ResponsesProps: spec.ResponsesProps{
+							StatusCodeResponses: map[int]spec.Response{
+								http.StatusOK: {
+									ResponseProps: spec.ResponseProps{
+										Schema: &spec.Schema{
+											SchemaProps: spec.SchemaProps{
+												Properties: map[string]spec.Schema{
+													"id":    {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}},
+													"owner": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"object"}, Properties: map[string]spec.Schema{"name": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}}}}},
+												},
+											},
+										},
+									},
+								},
+								http.StatusCreated: {
+									VendorExtensible: spec.VendorExtensible{
+										Extensions: extensions,
+									},
+									ResponseProps: spec.ResponseProps{
+										Schema: &spec.Schema{
+											SchemaProps: spec.SchemaProps{
+												Properties: map[string]spec.Schema{
+													"id":    {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}},
+													"owner": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}},
+												},
+											},
+										},
+									},
+								},
+								http.StatusNoContent: {},
+							},
+						},
+					},
+				},
+			}
+			specResponses := r.createResponses(operation)
+			Convey("Then each response should contain its own schema", func() {
+				ownerProperty, err := specResponses[http.StatusOK].schema.getProperty("owner")
+				So(err, ShouldBeNil)
+				So(ownerProperty.Type, ShouldEqual, typeObject)
+				ownerProperty, err = specResponses[http.StatusCreated].schema.getProperty("owner")
+				So(err, ShouldBeNil)
+				So(ownerProperty.Type, ShouldEqual, typeString)
+				So(specResponses[http.StatusNoContent].schema, ShouldBeNil)
+			})
+			Convey("And only the response with the extension should be considered a summary", func() {
+				So(specResponses[http.StatusOK].isSummary, ShouldBeFalse)
+				So(specResponses[http.StatusCreated].isSummary, ShouldBeTrue)
 			})
 		})
 
@@ -3764,7 +4932,7 @@ func TestGetTimeDuration(t *testing.T) {
 				So(duration, ShouldBeNil)
 			})
 			Convey("And the error message should be", func() {
-				So(err.Error(), ShouldContainSubstring, "invalid duration value: ''. The value must be a sequence of decimal numbers each with optional fraction and a unit suffix (negative durations are not allowed). The value must be formatted either in seconds (s), minutes (m) or hours (h)")
+				So(err.Error(), ShouldContainSubstring, "invalid duration value: ''. The value must be either a duration (e,g: 30s, 1.5m, 1h30m) or an integer number of seconds (e,g: 30); negative and zero durations are not allowed")
 			})
 		})
 		Convey(fmt.Sprintf("When getTimeDuration method is called with a list of extensions that DOES contain the extension passed in '%s' BUT the value is a negative duration", extTfResourceTimeout), func() {
@@ -3778,21 +4946,93 @@ func TestGetTimeDuration(t *testing.T) {
 				So(duration, ShouldBeNil)
 			})
 			Convey("And the error message should be", func() {
-				So(err.Error(), ShouldContainSubstring, "invalid duration value: '-1.5h'. The value must be a sequence of decimal numbers each with optional fraction and a unit suffix (negative durations are not allowed). The value must be formatted either in seconds (s), minutes (m) or hours (h)")
+				So(err.Error(), ShouldContainSubstring, "invalid duration value: '-1.5h'. The value must be either a duration (e,g: 30s, 1.5m, 1h30m) or an integer number of seconds (e,g: 30); negative and zero durations are not allowed")
 			})
 		})
-		Convey(fmt.Sprintf("When getTimeDuration method is called with a list of extensions that DOES contain the extension passed in '%s' BUT the value is NOT supported (distinct than s,m and h)", extTfResourceTimeout), func() {
+		Convey(fmt.Sprintf("When getTimeDuration method is called with a list of extensions that contains the extension passed in '%s' with a duration using other units (ms)", extTfResourceTimeout), func() {
 			extensions := spec.Extensions{}
 			extensions.Add(extTfResourceTimeout, "300ms")
 			duration, err := r.getTimeDuration(extensions, extTfResourceTimeout)
 			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("Then the duration returned should contain", func() {
+				So(*duration, ShouldEqual, time.Duration(300*time.Millisecond))
+			})
+		})
+		Convey(fmt.Sprintf("When getTimeDuration method is called with a list of extensions that contains the extension passed in '%s' with a duration combining several units", extTfResourceTimeout), func() {
+			extensions := spec.Extensions{}
+			extensions.Add(extTfResourceTimeout, "1h30m")
+			duration, err := r.getTimeDuration(extensions, extTfResourceTimeout)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("Then the duration returned should contain", func() {
+				So(*duration, ShouldEqual, time.Duration(90*time.Minute))
+			})
+		})
+		Convey(fmt.Sprintf("When getTimeDuration method is called with a list of extensions that contains the extension passed in '%s' with an integer number of seconds as string", extTfResourceTimeout), func() {
+			extensions := spec.Extensions{}
+			extensions.Add(extTfResourceTimeout, "30")
+			duration, err := r.getTimeDuration(extensions, extTfResourceTimeout)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("Then the duration returned should contain", func() {
+				So(*duration, ShouldEqual, time.Duration(30*time.Second))
+			})
+		})
+		Convey(fmt.Sprintf("When getTimeDuration method is called with a list of extensions that contains the extension passed in '%s' with an integer number of seconds as number", extTfResourceTimeout), func() {
+			extensions := spec.Extensions{}
+			extensions.Add(extTfResourceTimeout, float64(45))
+			duration, err := r.getTimeDuration(extensions, extTfResourceTimeout)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("Then the duration returned should contain", func() {
+				So(*duration, ShouldEqual, time.Duration(45*time.Second))
+			})
+		})
+		Convey(fmt.Sprintf("When getTimeDuration method is called with a list of extensions that DOES contain the extension passed in '%s' BUT the value is a number with fractions", extTfResourceTimeout), func() {
+			extensions := spec.Extensions{}
+			extensions.Add(extTfResourceTimeout, 1.5)
+			duration, err := r.getTimeDuration(extensions, extTfResourceTimeout)
+			Convey("Then the error returned should not be nil", func() {
+				So(err, ShouldNotBeNil)
+			})
+			Convey("Then the duration returned should be nil", func() {
+				So(duration, ShouldBeNil)
+			})
+			Convey("And the error message should be", func() {
+				So(err.Error(), ShouldEqual, "invalid duration value: '1.5'. The value must be either a duration (e,g: 30s, 1.5m, 1h30m) or an integer number of seconds (e,g: 30); negative and zero durations are not allowed: fractional seconds must be expressed as a duration (e,g: 1.5s)")
+			})
+		})
+		Convey(fmt.Sprintf("When getTimeDuration method is called with a list of extensions that DOES contain the extension passed in '%s' BUT the value is zero", extTfResourceTimeout), func() {
+			extensions := spec.Extensions{}
+			extensions.Add(extTfResourceTimeout, "0s")
+			duration, err := r.getTimeDuration(extensions, extTfResourceTimeout)
+			Convey("Then the error returned should not be nil", func() {
+				So(err, ShouldNotBeNil)
+			})
+			Convey("Then the duration returned should be nil", func() {
+				So(duration, ShouldBeNil)
+			})
+			Convey("And the error message should be", func() {
+				So(err.Error(), ShouldEqual, "invalid duration value: '0s'. The value must be either a duration (e,g: 30s, 1.5m, 1h30m) or an integer number of seconds (e,g: 30); negative and zero durations are not allowed: the duration must be greater than zero")
+			})
+		})
+		Convey(fmt.Sprintf("When getTimeDuration method is called with a list of extensions that DOES contain the extension passed in '%s' BUT the value type is not supported", extTfResourceTimeout), func() {
+			extensions := spec.Extensions{}
+			extensions.Add(extTfResourceTimeout, true)
+			duration, err := r.getTimeDuration(extensions, extTfResourceTimeout)
+			Convey("Then the error returned should not be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
 			Convey("Then the duration returned should be nil", func() {
 				So(duration, ShouldBeNil)
 			})
 			Convey("And the error message should be", func() {
-				So(err.Error(), ShouldContainSubstring, "invalid duration value: '300ms'. The value must be a sequence of decimal numbers each with optional fraction and a unit suffix (negative durations are not allowed). The value must be formatted either in seconds (s), minutes (m) or hours (h)")
+				So(err.Error(), ShouldEqual, "invalid duration value: 'true'. The value must be either a duration (e,g: 30s, 1.5m, 1h30m) or an integer number of seconds (e,g: 30); negative and zero durations are not allowed: type bool not supported")
 			})
 		})
 	})
@@ -3933,6 +5173,43 @@ func TestSpecV2ResourceGetHost(t *testing.T) {
 	})
 }
 
+func TestSpecV2ResourceGetScheme(t *testing.T) {
+	testCases := []struct {
+		name           string
+		post           *spec.Operation
+		expectedScheme string
+		expectedError  string
+	}{
+		{name: "resource without POST operation", post: nil, expectedScheme: ""},
+		{name: "resource without scheme override", post: &spec.Operation{}, expectedScheme: ""},
+		{name: "resource with http scheme override", post: &spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfResourceScheme: "http"}}}, expectedScheme: "http"},
+		{name: "resource with https scheme override", post: &spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfResourceScheme: "https"}}}, expectedScheme: "https"},
+		{name: "resource with a not supported scheme override", post: &spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfResourceScheme: "ws"}}}, expectedError: "'x-terraform-resource-scheme' extension value 'ws' not supported - must use http or https"},
+	}
+	for _, tc := range testCases {
+		Convey(fmt.Sprintf("Given a SpecV2Resource configured with a %s", tc.name), t, func() {
+			r := SpecV2Resource{
+				RootPathItem: spec.PathItem{
+					PathItemProps: spec.PathItemProps{
+						Post: tc.post,
+					},
+				},
+			}
+			Convey("When getScheme is called", func() {
+				scheme, err := r.getScheme()
+				Convey("Then the scheme and error returned should be the expected ones", func() {
+					if tc.expectedError != "" {
+						So(err.Error(), ShouldEqual, tc.expectedError)
+					} else {
+						So(err, ShouldBeNil)
+					}
+					So(scheme, ShouldEqual, tc.expectedScheme)
+				})
+			})
+		})
+	}
+}
+
 func TestGetResourceOverrideHost(t *testing.T) {
 	Convey("Given a terraform compliant resource that has a POST operation containing the x-terraform-resource-host with a non parametrized host containing the host to use", t, func() {
 		expectedHost := "some.api.domain.com"
@@ -4019,3 +5296,45 @@ func TestGetResourceOverrideHost(t *testing.T) {
 		})
 	})
 }
+
+func newSetHashArrayPropertySchema(extensions spec.Extensions) spec.Schema {
+	return spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"array"},
+			Items: &spec.SchemaOrArray{
+				Schema: &spec.Schema{
+					SchemaProps: spec.SchemaProps{
+						Type: spec.StringOrArray{"object"},
+						Properties: map[string]spec.Schema{
+							"name": {
+								SchemaProps: spec.SchemaProps{
+									Type: spec.StringOrArray{"string"},
+								},
+							},
+							"protocol": {
+								SchemaProps: spec.SchemaProps{
+									Type: spec.StringOrArray{"string"},
+								},
+							},
+							"options": {
+								SchemaProps: spec.SchemaProps{
+									Type: spec.StringOrArray{"array"},
+									Items: &spec.SchemaOrArray{
+										Schema: &spec.Schema{
+											SchemaProps: spec.SchemaProps{
+												Type: spec.StringOrArray{"string"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		VendorExtensible: spec.VendorExtensible{
+			Extensions: extensions,
+		},
+	}
+}
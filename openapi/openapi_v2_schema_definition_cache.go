@@ -0,0 +1,53 @@
+package openapi
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/go-openapi/spec"
+)
+
+// schemaDefinitionCache caches the schema definitions resulting from analysing the OpenAPI schemas of the resources, so
+// resources sharing the same schema (e.g. several versions of the same resource like /v1/cdns and /v2/cdns whose model did
+// not change, or the resources of multi-region APIs) only get the schema analysed once. The schema definitions are also
+// re-used across calls, avoiding analysing the schema again every time an operation is performed on the resource.
+type schemaDefinitionCache struct {
+	mutex       sync.Mutex
+	definitions map[string]*specSchemaDefinition
+}
+
+func newSchemaDefinitionCache() *schemaDefinitionCache {
+	return &schemaDefinitionCache{
+		definitions: map[string]*specSchemaDefinition{},
+	}
+}
+
+// schemaDefinitionCacheKey builds the cache key for the given resource schema. The parent property names are part of the
+// key as they are added to the schema definition of subresources. An empty key is returned if the schema can not be
+// serialized, in which case the schema definition should not be cached.
+func schemaDefinitionCacheKey(schema spec.Schema, parentPropertyNames []string) string {
+	serializedSchema, err := json.Marshal(schema)
+	if err != nil {
+		log.Printf("[DEBUG] schema definition will not be cached as the schema could not be serialized: %s", err)
+		return ""
+	}
+	return strings.Join(parentPropertyNames, ",") + "\n" + string(serializedSchema)
+}
+
+// getOrCreate returns the schema definition cached for the given key. If there is none, the create function is called
+// and the schema definition returned is cached if there was no error.
+func (c *schemaDefinitionCache) getOrCreate(key string, create func() (*specSchemaDefinition, error)) (*specSchemaDefinition, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if schemaDefinition, exists := c.definitions[key]; exists {
+		return schemaDefinition, nil
+	}
+	schemaDefinition, err := create()
+	if err != nil {
+		return nil, err
+	}
+	c.definitions[key] = schemaDefinition
+	return schemaDefinition, nil
+}
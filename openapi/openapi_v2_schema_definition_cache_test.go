@@ -0,0 +1,51 @@
+package openapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaDefinitionCacheGetOrCreate(t *testing.T) {
+	cache := newSchemaDefinitionCache()
+	calls := 0
+	create := func() (*specSchemaDefinition, error) {
+		calls++
+		return &specSchemaDefinition{}, nil
+	}
+
+	first, err := cache.getOrCreate("key", create)
+	assert.Nil(t, err)
+	second, err := cache.getOrCreate("key", create)
+	assert.Nil(t, err)
+	assert.True(t, first == second, "expected the cached schema definition to be returned")
+	assert.Equal(t, 1, calls)
+
+	other, err := cache.getOrCreate("other_key", create)
+	assert.Nil(t, err)
+	assert.False(t, first == other, "expected a new schema definition to be created for a different key")
+	assert.Equal(t, 2, calls)
+}
+
+func TestSchemaDefinitionCacheGetOrCreate_ErrorsAreNotCached(t *testing.T) {
+	cache := newSchemaDefinitionCache()
+	_, err := cache.getOrCreate("key", func() (*specSchemaDefinition, error) {
+		return nil, errors.New("some error")
+	})
+	assert.EqualError(t, err, "some error")
+	schemaDefinition, err := cache.getOrCreate("key", func() (*specSchemaDefinition, error) {
+		return &specSchemaDefinition{}, nil
+	})
+	assert.Nil(t, err)
+	assert.NotNil(t, schemaDefinition)
+}
+
+func TestSchemaDefinitionCacheKey(t *testing.T) {
+	schema := spec.Schema{SchemaProps: spec.SchemaProps{Properties: map[string]spec.Schema{"label": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}}}}}
+	otherSchema := spec.Schema{SchemaProps: spec.SchemaProps{Properties: map[string]spec.Schema{"name": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}}}}}
+	assert.Equal(t, schemaDefinitionCacheKey(schema, nil), schemaDefinitionCacheKey(schema, nil))
+	assert.NotEqual(t, schemaDefinitionCacheKey(schema, nil), schemaDefinitionCacheKey(otherSchema, nil))
+	assert.NotEqual(t, schemaDefinitionCacheKey(schema, nil), schemaDefinitionCacheKey(schema, []string{"cdns_v1_id"}))
+}
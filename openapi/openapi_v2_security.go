@@ -2,11 +2,17 @@ package openapi
 
 import (
 	"fmt"
+
+	"github.com/dikhan/terraform-provider-openapi/openapi/terraformutils"
 	"github.com/go-openapi/spec"
 )
 
-const extTfAuthenticationSchemeBearer = "x-terraform-authentication-scheme-bearer"
-const extTfAuthenticationRefreshToken = "x-terraform-refresh-token-url"
+// Values supported by the x-terraform-auth extension
+const (
+	tfAuthAWSSigV4            = "aws_sigv4"
+	tfAuthHMAC                = "hmac"
+	amazonAPIGatewayAuthSigV4 = "awsSigv4"
+)
 
 type specV2Security struct {
 	SecurityDefinitions spec.SecurityDefinitions
@@ -14,10 +20,37 @@ type specV2Security struct {
 }
 
 // GetAPIKeySecurityDefinitions returns a list of SpecSecurityDefinition after looping through the SecurityDefinitions
-// and selecting only the SecurityDefinitions of type apiKey
+// and selecting only the SecurityDefinitions of type apiKey and basic. The security definitions with the x-terraform-auth
+// extension set to aws_sigv4 (or the API Gateway x-amazon-apigateway-authtype extension set to awsSigv4) or hmac are
+// selected too, regardless of their type. The apiKey ones with the x-terraform-auth-secondary-key extension enabled
+// support a secondary api key
 func (s *specV2Security) GetAPIKeySecurityDefinitions() (*SpecSecurityDefinitions, error) {
 	securityDefinitions := &SpecSecurityDefinitions{}
 	for secDefName, secDef := range s.SecurityDefinitions {
+		auth, _ := secDef.Extensions.GetString(extTfAuth)
+		if auth != "" && auth != tfAuthAWSSigV4 && auth != tfAuthHMAC {
+			return nil, fmt.Errorf("security definition '%s' %s extension value '%s' not supported, only '%s' and '%s' are valid", secDefName, extTfAuth, auth, tfAuthAWSSigV4, tfAuthHMAC)
+		}
+		if auth == tfAuthHMAC {
+			securityDefinition := newHMACSecurityDefinition(secDefName,
+				s.getExtensionString(secDef, extTfAuthHMACSignatureHeader),
+				s.getExtensionString(secDef, extTfAuthHMACTimestampHeader),
+				s.getExtensionString(secDef, extTfAuthHMACAlgorithm),
+				s.getExtensionString(secDef, extTfAuthHMACSignaturePrefix))
+			if err := securityDefinition.validate(); err != nil {
+				return nil, err
+			}
+			*securityDefinitions = append(*securityDefinitions, securityDefinition)
+			continue
+		}
+		if s.isAWSSigV4Auth(secDef) {
+			securityDefinition := newAWSSigV4SecurityDefinition(secDefName, s.getExtensionString(secDef, extTfAuthAWSService), s.getExtensionString(secDef, extTfAuthAWSRegion))
+			if err := securityDefinition.validate(); err != nil {
+				return nil, err
+			}
+			*securityDefinitions = append(*securityDefinitions, securityDefinition)
+			continue
+		}
 		if secDef.Type == "apiKey" {
 			var securityDefinition SpecSecurityDefinition
 			switch secDef.In {
@@ -35,9 +68,24 @@ func (s *specV2Security) GetAPIKeySecurityDefinitions() (*SpecSecurityDefinition
 				} else {
 					securityDefinition = newAPIKeyQuerySecurityDefinition(secDefName, secDef.Name)
 				}
+			case "cookie":
+				securityDefinition = newAPIKeyCookieSecurityDefinition(secDefName, secDef.Name)
 			default:
-				return nil, fmt.Errorf("apiKey In value '%s' not supported, only 'header' and 'query' values are valid", secDef.In)
+				return nil, fmt.Errorf("apiKey In value '%s' not supported, only 'header', 'query' and 'cookie' values are valid", secDef.In)
+			}
+			if err := securityDefinition.validate(); err != nil {
+				return nil, err
 			}
+			if s.isSecondaryKeyEnabled(secDef) {
+				if securityDefinition.getType() == securityDefinitionAPIKeyRefreshToken {
+					return nil, fmt.Errorf("security definition '%s' %s extension is not supported along with the %s extension", secDefName, extTfAuthSecondaryKey, extTfAuthenticationRefreshToken)
+				}
+				securityDefinition = newSecondaryKeySecurityDefinition(securityDefinition)
+			}
+			*securityDefinitions = append(*securityDefinitions, securityDefinition)
+		}
+		if secDef.Type == "basic" {
+			securityDefinition := newBasicAuthSecurityDefinition(secDefName)
 			if err := securityDefinition.validate(); err != nil {
 				return nil, err
 			}
@@ -55,6 +103,27 @@ func (s *specV2Security) isBearerScheme(secDef *spec.SecurityScheme) bool {
 	return false
 }
 
+// isSecondaryKeyEnabled returns true if the security definition accepts a secondary api key to fail over to when the
+// primary one is rejected
+func (s *specV2Security) isSecondaryKeyEnabled(secDef *spec.SecurityScheme) bool {
+	enabled, _ := secDef.Extensions.GetBool(extTfAuthSecondaryKey)
+	return enabled
+}
+
+// isAWSSigV4Auth returns true if the security definition requires the requests to be signed with AWS Signature Version 4
+func (s *specV2Security) isAWSSigV4Auth(secDef *spec.SecurityScheme) bool {
+	if auth, _ := secDef.Extensions.GetString(extTfAuth); auth == tfAuthAWSSigV4 {
+		return true
+	}
+	authType, _ := secDef.Extensions.GetString(extAmazonAPIGatewayAuthType)
+	return authType == amazonAPIGatewayAuthSigV4
+}
+
+func (s *specV2Security) getExtensionString(secDef *spec.SecurityScheme, extension string) string {
+	value, _ := secDef.Extensions.GetString(extension)
+	return value
+}
+
 func (s *specV2Security) isRefreshTokenAuth(secDef *spec.SecurityScheme) string {
 	refreshTokenURL, isRefreshTokenAuth := secDef.Extensions.GetString(extTfAuthenticationRefreshToken)
 	if isRefreshTokenAuth {
@@ -73,8 +142,46 @@ func (s *specV2Security) GetGlobalSecuritySchemes() (SpecSecuritySchemes, error)
 		}
 		secDefFound := secDef.findSecurityDefinitionFor(securityScheme.Name)
 		if secDefFound == nil {
-			return nil, fmt.Errorf("global security scheme '%s' not found or not matching supported 'apiKey' type", securityScheme.Name)
+			return nil, fmt.Errorf("global security scheme '%s' not found or not matching supported 'apiKey' or 'basic' types", securityScheme.Name)
 		}
 	}
 	return securitySchemes, nil
 }
+
+// GetGlobalSecurityRequirements returns the alternative global security requirements in order of appearance, making sure
+// all their security schemes have their corresponding SpecSecurityDefinition
+func (s *specV2Security) GetGlobalSecurityRequirements() ([]SpecSecuritySchemes, error) {
+	securityRequirements := createSecurityRequirements(s.GlobalSecurity)
+	if len(securityRequirements) == 0 {
+		return nil, nil
+	}
+	secDefs, err := s.GetAPIKeySecurityDefinitions()
+	if err != nil {
+		return nil, err
+	}
+	for _, securityRequirement := range securityRequirements {
+		for _, securityScheme := range securityRequirement {
+			if secDefs.findSecurityDefinitionFor(securityScheme.Name) == nil {
+				return nil, fmt.Errorf("global security scheme '%s' not found or not matching supported 'apiKey' or 'basic' types", securityScheme.Name)
+			}
+		}
+	}
+	return securityRequirements, nil
+}
+
+// GetTokenIntrospectionURLs returns the token introspection endpoints (x-terraform-token-introspection-url extension) of
+// the apiKey security definitions, keyed by the security definition terraform configuration name
+func (s *specV2Security) GetTokenIntrospectionURLs() (map[string]string, error) {
+	introspectionURLs := map[string]string{}
+	for secDefName, secDef := range s.SecurityDefinitions {
+		introspectionURL, exists := secDef.Extensions.GetString(extTfTokenIntrospectionURL)
+		if secDef.Type != "apiKey" || !exists {
+			continue
+		}
+		if !isURL(introspectionURL) {
+			return nil, fmt.Errorf("security definition '%s' %s extension value '%s' must be a valid URL", secDefName, extTfTokenIntrospectionURL, introspectionURL)
+		}
+		introspectionURLs[terraformutils.ConvertToTerraformCompliantName(secDefName)] = introspectionURL
+	}
+	return introspectionURLs, nil
+}
@@ -132,6 +132,34 @@ func TestGetAPIKeySecurityDefinitions(t *testing.T) {
 		})
 	})
 
+	Convey("Given a specV2Security loaded with a security definition of type cookie", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"cookie_auth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						In:   "cookie",
+						Type: "apiKey",
+						Name: "session",
+					},
+				},
+			},
+		}
+		Convey("When GetAPIKeySecurityDefinitions method is called", func() {
+			securityDefinitions, err := specV2Security.GetAPIKeySecurityDefinitions()
+			secDefs := *securityDefinitions
+			Convey("Then the the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the security schemes should be of type cookie", func() {
+				So(secDefs, ShouldHaveLength, 1)
+				So(secDefs[0], ShouldHaveSameTypeAs, specAPIKeyCookieSecurityDefinition{})
+				So(secDefs[0].getAPIKey().Name, ShouldEqual, "session")
+				So(secDefs[0].getAPIKey().In, ShouldEqual, inCookie)
+			})
+		})
+	})
+
 	Convey("Given a specV2Security loaded with a security definition of type header refresh token auth", t, func() {
 		specV2Security := specV2Security{
 			GlobalSecurity: []map[string][]string{},
@@ -248,6 +276,241 @@ func TestGetAPIKeySecurityDefinitions(t *testing.T) {
 		})
 	})
 
+	Convey("Given a specV2Security loaded with a security definition of type basic", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"basicAuth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						Type: "basic",
+					},
+				},
+			},
+		}
+		Convey("When GetAPIKeySecurityDefinitions method is called", func() {
+			securityDefinitions, err := specV2Security.GetAPIKeySecurityDefinitions()
+			secDefs := *securityDefinitions
+			Convey("Then the the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the security schemes should be of type basic", func() {
+				So(secDefs, ShouldHaveLength, 1)
+				So(secDefs[0], ShouldHaveSameTypeAs, specBasicAuthSecurityDefinition{})
+				So(secDefs[0].getAPIKey().Name, ShouldEqual, authorizationHeader)
+				So(secDefs[0].buildValue("user:password"), ShouldEqual, "Basic dXNlcjpwYXNzd29yZA==")
+			})
+		})
+	})
+
+	Convey("Given a specV2Security loaded with a security definition with the x-terraform-auth extension set to aws_sigv4", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"awsIAM": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						In:   "header",
+						Name: "Authorization",
+						Type: "apiKey",
+					},
+					VendorExtensible: spec.VendorExtensible{
+						Extensions: spec.Extensions{
+							extTfAuth:           tfAuthAWSSigV4,
+							extTfAuthAWSRegion:  "us-west-2",
+							extTfAuthAWSService: "lambda",
+						},
+					},
+				},
+			},
+		}
+		Convey("When GetAPIKeySecurityDefinitions method is called", func() {
+			securityDefinitions, err := specV2Security.GetAPIKeySecurityDefinitions()
+			secDefs := *securityDefinitions
+			Convey("Then the the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the security scheme should be of type aws sigv4 with the service and region configured", func() {
+				So(secDefs, ShouldHaveLength, 1)
+				So(secDefs[0], ShouldResemble, newAWSSigV4SecurityDefinition("awsIAM", "lambda", "us-west-2"))
+				So(secDefs[0].getType(), ShouldEqual, securityDefinitionAWSSigV4)
+			})
+		})
+	})
+
+	Convey("Given a specV2Security loaded with a security definition with the API Gateway x-amazon-apigateway-authtype extension set to awsSigv4", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"sigv4": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						In:   "header",
+						Name: "Authorization",
+						Type: "apiKey",
+					},
+					VendorExtensible: spec.VendorExtensible{
+						Extensions: spec.Extensions{
+							extAmazonAPIGatewayAuthType: amazonAPIGatewayAuthSigV4,
+						},
+					},
+				},
+			},
+		}
+		Convey("When GetAPIKeySecurityDefinitions method is called", func() {
+			securityDefinitions, err := specV2Security.GetAPIKeySecurityDefinitions()
+			secDefs := *securityDefinitions
+			Convey("Then the the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the security scheme should be of type aws sigv4 with the service and region to be resolved from the host", func() {
+				So(secDefs, ShouldHaveLength, 1)
+				So(secDefs[0], ShouldResemble, newAWSSigV4SecurityDefinition("sigv4", "", ""))
+			})
+		})
+	})
+
+	Convey("Given a specV2Security loaded with a security definition with the x-terraform-auth extension set to hmac", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"hmacAuth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						In:   "header",
+						Name: "X-Hub-Signature",
+						Type: "apiKey",
+					},
+					VendorExtensible: spec.VendorExtensible{
+						Extensions: spec.Extensions{
+							extTfAuth:                    tfAuthHMAC,
+							extTfAuthHMACSignatureHeader: "X-Hub-Signature",
+							extTfAuthHMACAlgorithm:       "sha1",
+							extTfAuthHMACSignaturePrefix: "sha1=",
+						},
+					},
+				},
+			},
+		}
+		Convey("When GetAPIKeySecurityDefinitions method is called", func() {
+			securityDefinitions, err := specV2Security.GetAPIKeySecurityDefinitions()
+			secDefs := *securityDefinitions
+			Convey("Then the the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the security scheme should be of type hmac with the configuration provided and the default timestamp header", func() {
+				So(secDefs, ShouldHaveLength, 1)
+				So(secDefs[0], ShouldResemble, newHMACSecurityDefinition("hmacAuth", "X-Hub-Signature", "X-Timestamp", "sha1", "sha1="))
+				So(secDefs[0].getType(), ShouldEqual, securityDefinitionHMAC)
+			})
+		})
+	})
+
+	Convey("Given a specV2Security loaded with a hmac security definition with an algorithm not supported", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"hmacAuth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						Type: "apiKey",
+						In:   "header",
+					},
+					VendorExtensible: spec.VendorExtensible{
+						Extensions: spec.Extensions{
+							extTfAuth:              tfAuthHMAC,
+							extTfAuthHMACAlgorithm: "md5",
+						},
+					},
+				},
+			},
+		}
+		Convey("When GetAPIKeySecurityDefinitions method is called", func() {
+			_, err := specV2Security.GetAPIKeySecurityDefinitions()
+			Convey("Then the error should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "specHMACSecurityDefinition 'hmacAuth' algorithm 'md5' not supported, supported algorithms: sha1, sha256, sha512")
+			})
+		})
+	})
+
+	Convey("Given a specV2Security loaded with an apiKey security definition with the x-terraform-auth-secondary-key extension enabled", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"apiKeyAuth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						Type: "apiKey",
+						In:   "header",
+						Name: "X-API-Key",
+					},
+					VendorExtensible: spec.VendorExtensible{
+						Extensions: spec.Extensions{
+							extTfAuthSecondaryKey: true,
+						},
+					},
+				},
+			},
+		}
+		Convey("When GetAPIKeySecurityDefinitions method is called", func() {
+			securityDefinitions, err := specV2Security.GetAPIKeySecurityDefinitions()
+			secDefs := *securityDefinitions
+			Convey("Then the the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the security scheme should be the header one supporting a secondary api key", func() {
+				So(secDefs, ShouldHaveLength, 1)
+				So(secDefs[0], ShouldResemble, newSecondaryKeySecurityDefinition(newAPIKeyHeaderSecurityDefinition("apiKeyAuth", "X-API-Key")))
+				So(getSecurityDefinitionPropertyNames(secDefs[0]), ShouldResemble, []string{"api_key_auth", "api_key_auth_secondary"})
+			})
+		})
+	})
+
+	Convey("Given a specV2Security loaded with a refresh token security definition with the x-terraform-auth-secondary-key extension enabled", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"apiKeyAuth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						Type: "apiKey",
+						In:   "header",
+						Name: "Authorization",
+					},
+					VendorExtensible: spec.VendorExtensible{
+						Extensions: spec.Extensions{
+							extTfAuthenticationRefreshToken: "https://api.iam.com/token",
+							extTfAuthSecondaryKey:           true,
+						},
+					},
+				},
+			},
+		}
+		Convey("When GetAPIKeySecurityDefinitions method is called", func() {
+			_, err := specV2Security.GetAPIKeySecurityDefinitions()
+			Convey("Then the error should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "security definition 'apiKeyAuth' x-terraform-auth-secondary-key extension is not supported along with the x-terraform-refresh-token-url extension")
+			})
+		})
+	})
+
+	Convey("Given a specV2Security loaded with a security definition with a x-terraform-auth extension value not supported", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"awsIAM": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						Type: "basic",
+					},
+					VendorExtensible: spec.VendorExtensible{
+						Extensions: spec.Extensions{
+							extTfAuth: "aws_sigv2",
+						},
+					},
+				},
+			},
+		}
+		Convey("When GetAPIKeySecurityDefinitions method is called", func() {
+			_, err := specV2Security.GetAPIKeySecurityDefinitions()
+			Convey("Then the error should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "security definition 'awsIAM' x-terraform-auth extension value 'aws_sigv2' not supported, only 'aws_sigv4' and 'hmac' are valid")
+			})
+		})
+	})
+
 	Convey("Given a specV2Security loaded with a apiKey type but the location (In) is not supported", t, func() {
 		specV2Security := specV2Security{
 			GlobalSecurity: []map[string][]string{},
@@ -263,7 +526,7 @@ func TestGetAPIKeySecurityDefinitions(t *testing.T) {
 		Convey("When GetAPIKeySecurityDefinitions method is called", func() {
 			_, err := specV2Security.GetAPIKeySecurityDefinitions()
 			Convey("And the error should match the expected one", func() {
-				So(err.Error(), ShouldEqual, "apiKey In value 'some_other_location' not supported, only 'header' and 'query' values are valid")
+				So(err.Error(), ShouldEqual, "apiKey In value 'some_other_location' not supported, only 'header', 'query' and 'cookie' values are valid")
 			})
 		})
 	})
@@ -324,7 +587,144 @@ func TestGetGlobalSecuritySchemes(t *testing.T) {
 				So(err, ShouldNotBeNil)
 			})
 			Convey("And the security schemes should not be empty", func() {
-				So(err.Error(), ShouldEqual, "global security scheme 'nonExistingScheme' not found or not matching supported 'apiKey' type")
+				So(err.Error(), ShouldEqual, "global security scheme 'nonExistingScheme' not found or not matching supported 'apiKey' or 'basic' types")
+			})
+		})
+	})
+}
+
+func TestGetGlobalSecurityRequirements(t *testing.T) {
+	Convey("Given a specV2Security loaded with alternative global security requirements which are defined in the security definitions", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{
+				{
+					"apikey_auth": []string{},
+				},
+				{
+					"basic_auth": []string{},
+				},
+			},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"apikey_auth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						In:   "header",
+						Type: "apiKey",
+						Name: authorizationHeader,
+					},
+				},
+				"basic_auth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						Type: "basic",
+					},
+				},
+			},
+		}
+		Convey("When GetGlobalSecurityRequirements method is called", func() {
+			securityRequirements, err := specV2Security.GetGlobalSecurityRequirements()
+			Convey("Then the the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And all the security requirements should be returned in order of appearance", func() {
+				So(securityRequirements, ShouldResemble, []SpecSecuritySchemes{{{Name: "apikey_auth"}}, {{Name: "basic_auth"}}})
+			})
+		})
+	})
+	Convey("Given a specV2Security loaded with an alternative global security requirement using a NON defined security scheme", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{
+				{
+					"apikey_auth": []string{},
+				},
+				{
+					"nonExistingScheme": []string{},
+				},
+			},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"apikey_auth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						In:   "header",
+						Type: "apiKey",
+						Name: authorizationHeader,
+					},
+				},
+			},
+		}
+		Convey("When GetGlobalSecurityRequirements method is called", func() {
+			_, err := specV2Security.GetGlobalSecurityRequirements()
+			Convey("Then the error returned should mention the security scheme not defined", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "global security scheme 'nonExistingScheme' not found or not matching supported 'apiKey' or 'basic' types")
+			})
+		})
+	})
+	Convey("Given a specV2Security with no global security", t, func() {
+		specV2Security := specV2Security{}
+		Convey("When GetGlobalSecurityRequirements method is called", func() {
+			securityRequirements, err := specV2Security.GetGlobalSecurityRequirements()
+			Convey("Then no security requirements should be returned", func() {
+				So(err, ShouldBeNil)
+				So(securityRequirements, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestGetTokenIntrospectionURLs(t *testing.T) {
+	Convey("Given a specV2Security loaded with security definitions where one of them declares a token introspection endpoint", t, func() {
+		specV2Security := specV2Security{
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"oauthToken": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						In:   "header",
+						Type: "apiKey",
+						Name: authorizationHeader,
+					},
+					VendorExtensible: spec.VendorExtensible{
+						Extensions: spec.Extensions{
+							extTfTokenIntrospectionURL: "https://api.iam.com/oauth/introspect",
+						},
+					},
+				},
+				"apikey_auth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						In:   "header",
+						Type: "apiKey",
+						Name: "X-API-KEY",
+					},
+				},
+			},
+		}
+		Convey("When GetTokenIntrospectionURLs method is called", func() {
+			introspectionURLs, err := specV2Security.GetTokenIntrospectionURLs()
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the introspection URLs should only contain the one declared keyed by the security definition terraform name", func() {
+				So(introspectionURLs, ShouldResemble, map[string]string{"oauth_token": "https://api.iam.com/oauth/introspect"})
+			})
+		})
+	})
+	Convey("Given a specV2Security loaded with a security definition declaring a token introspection endpoint that is not a valid URL", t, func() {
+		specV2Security := specV2Security{
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"oauth_token": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						In:   "header",
+						Type: "apiKey",
+						Name: authorizationHeader,
+					},
+					VendorExtensible: spec.VendorExtensible{
+						Extensions: spec.Extensions{
+							extTfTokenIntrospectionURL: "not a url",
+						},
+					},
+				},
+			},
+		}
+		Convey("When GetTokenIntrospectionURLs method is called", func() {
+			_, err := specV2Security.GetTokenIntrospectionURLs()
+			Convey("Then the error returned should describe the invalid extension value", func() {
+				So(err.Error(), ShouldEqual, "security definition 'oauth_token' x-terraform-token-introspection-url extension value 'not a url' must be a valid URL")
 			})
 		})
 	})
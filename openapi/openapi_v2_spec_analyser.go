@@ -1,11 +1,13 @@
 package openapi
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,14 +16,14 @@ import (
 	"github.com/go-openapi/spec"
 )
 
-const extTfResourceRegionsFmt = "x-terraform-resource-regions-%s"
-
 // specV2Analyser defines an SpecAnalyser implementation for OpenAPI v2 specification
 // Forcing creation of this object via constructor so proper input validation is performed before creating the struct
 // instance
 type specV2Analyser struct {
 	openAPIDocumentURL string
 	d                  *loads.Document
+	// documentHash is the hash of the OpenAPI document contents as retrieved from the openAPIDocumentURL
+	documentHash string
 }
 
 // newSpecAnalyserV2 creates an instance of specV2Analyser which implements the SpecAnalyser interface
@@ -32,25 +34,58 @@ func newSpecAnalyserV2(openAPIDocumentFilename string) (*specV2Analyser, error)
 	}
 	apiSpec, err := loads.JSONSpec(openAPIDocumentFilename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve the OpenAPI document from '%s' - error = %s", openAPIDocumentFilename, err)
+		return nil, &SpecFetchError{URL: openAPIDocumentFilename, Err: fmt.Errorf("failed to retrieve the OpenAPI document from '%s' - error = %s", openAPIDocumentFilename, err)}
+	}
+	return newSpecAnalyserV2FromDocument(openAPIDocumentFilename, apiSpec)
+}
+
+// newSpecAnalyserV2FromDocument creates an instance of specV2Analyser out of the already retrieved OpenAPI v2 document
+func newSpecAnalyserV2FromDocument(openAPIDocumentFilename string, apiSpec *loads.Document) (*specV2Analyser, error) {
+	documentHash := getOpenAPIDocumentHash(apiSpec.Raw())
+	apiSpec, err := resolveSwaggerExternalRefs(openAPIDocumentFilename, apiSpec)
+	if err != nil {
+		return nil, &SpecAnalysisError{Err: fmt.Errorf("failed to resolve the external references of the OpenAPI document from '%s' - error = %s", openAPIDocumentFilename, err)}
 	}
+	logExtensionsLintIssues(apiSpec.Spec())
 	apiSpec, err = apiSpec.Expanded()
 	if err != nil {
-		return nil, fmt.Errorf("failed to expand the OpenAPI document from '%s' - error = %s", openAPIDocumentFilename, err)
+		return nil, &SpecAnalysisError{Err: fmt.Errorf("failed to expand the OpenAPI document from '%s' - error = %s", openAPIDocumentFilename, err)}
 	}
 	return &specV2Analyser{
 		d:                  apiSpec,
 		openAPIDocumentURL: openAPIDocumentFilename,
+		documentHash:       documentHash,
 	}, nil
 }
 
-func (specAnalyser *specV2Analyser) createMultiRegionResources(regions []string, resourceRootPath string, resourceRoot, pathItem spec.PathItem, resourcePayloadSchemaDef *spec.Schema) ([]SpecResource, error) {
+// resolveSwaggerExternalRefs returns the given OpenAPI v2 document with the references to other documents resolved (see
+// resolveExternalRefs). The document is returned as is if it does not contain external references
+func resolveSwaggerExternalRefs(openAPIDocumentFilename string, apiSpec *loads.Document) (*loads.Document, error) {
+	swaggerDocument := map[string]interface{}{}
+	if err := json.Unmarshal(apiSpec.Raw(), &swaggerDocument); err != nil {
+		return nil, err
+	}
+	resolvedDocument, resolved, err := resolveExternalRefs(openAPIDocumentFilename, swaggerDocument, swaggerDefinitionsPath)
+	if err != nil || !resolved {
+		return apiSpec, err
+	}
+	document, err := json.Marshal(resolvedDocument)
+	if err != nil {
+		return nil, err
+	}
+	return loads.Analyzed(document, "")
+}
+
+func (specAnalyser *specV2Analyser) createMultiRegionResources(regions []string, resourceRootPath string, resourceRoot, pathItem spec.PathItem, resourcePayloadSchemaDef *spec.Schema, cache *schemaDefinitionCache) ([]SpecResource, error) {
 	var resources []SpecResource
 	for _, regionName := range regions {
 		r, err := newSpecV2ResourceWithRegion(regionName, resourceRootPath, *resourcePayloadSchemaDef, resourceRoot, pathItem, specAnalyser.d.Spec().Definitions, specAnalyser.d.Spec().Paths.Paths)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create a resource with region: %s", err)
 		}
+		if cache != nil {
+			r.useSchemaDefinitionCache(cache)
+		}
 		log.Printf("[INFO] multi region resource name = %s, region = '%s'", r.getResourceName(), regionName)
 		resources = append(resources, r)
 	}
@@ -68,6 +103,11 @@ func (specAnalyser *specV2Analyser) GetTerraformCompliantDataSources() []SpecRes
 			continue
 		}
 
+		if excludeDataSource, _ := pathItem.Get.Extensions.GetBool(extTfExcludeDataSource); excludeDataSource {
+			log.Printf("[INFO] ignoring data source for path '%s' as the GET operation is configured with the '%s' extension", resourcePath, extTfExcludeDataSource)
+			continue
+		}
+
 		d, err := newSpecV2DataSource(resourcePath, *schemaDefinition, pathItem, specAnalyser.d.Spec().Paths.Paths)
 		if err != nil {
 			log.Printf("[WARN] ignoring data source '%s' due to an error while creating a creating the SpecV2Resource: %s", resourcePath, err)
@@ -83,6 +123,7 @@ func (specAnalyser *specV2Analyser) GetTerraformCompliantDataSources() []SpecRes
 func (specAnalyser *specV2Analyser) GetTerraformCompliantResources() ([]SpecResource, error) {
 	var resources []SpecResource
 	start := time.Now()
+	cache := newSchemaDefinitionCache()
 	spec := specAnalyser.d.Spec()
 	paths := spec.Paths
 	for resourcePath, pathItem := range paths.Paths {
@@ -92,6 +133,10 @@ func (specAnalyser *specV2Analyser) GetTerraformCompliantResources() ([]SpecReso
 			continue
 		}
 
+		if err := specAnalyser.validateDurationExtensions(resourceRootPath, *resourceRoot, resourcePath, pathItem); err != nil {
+			return nil, err
+		}
+
 		isMultiRegion, regions, err := specAnalyser.isMultiRegionResource(resourceRoot, specAnalyser.d.Spec().Extensions)
 		if err != nil {
 			log.Printf("multi region configuration for resource '%s' is not valid: ", err)
@@ -99,7 +144,7 @@ func (specAnalyser *specV2Analyser) GetTerraformCompliantResources() ([]SpecReso
 		}
 		if isMultiRegion {
 			log.Printf("[INFO] resource '%s' is configured with host override AND multi region; creating one reasource per region", resourceRootPath)
-			multiRegionResources, err := specAnalyser.createMultiRegionResources(regions, resourceRootPath, *resourceRoot, pathItem, resourcePayloadSchemaDef)
+			multiRegionResources, err := specAnalyser.createMultiRegionResources(regions, resourceRootPath, *resourceRoot, pathItem, resourcePayloadSchemaDef, cache)
 			if err != nil {
 				log.Printf("[WARN] ignoring multiregion resource '%s' due to an error: %s", resourceRootPath, err)
 				continue
@@ -120,6 +165,7 @@ func (specAnalyser *specV2Analyser) GetTerraformCompliantResources() ([]SpecReso
 			continue
 		}
 
+		r.useSchemaDefinitionCache(cache)
 		log.Printf("[INFO] found terraform compliant resource [name='%s', rootPath='%s', instancePath='%s']", r.getResourceName(), resourceRootPath, resourcePath)
 		resources = append(resources, r)
 	}
@@ -127,6 +173,60 @@ func (specAnalyser *specV2Analyser) GetTerraformCompliantResources() ([]SpecReso
 	return resources, nil
 }
 
+// validateDurationExtensions makes sure the duration extensions (operation timeouts and response poll intervals) of the
+// resource operations contain valid durations. The error returned points at the operation and the extension that is not
+// valid, so service providers can find the value that needs fixing
+func (specAnalyser *specV2Analyser) validateDurationExtensions(resourceRootPath string, resourceRoot spec.PathItem, resourceInstancePath string, resourceInstance spec.PathItem) error {
+	operations := []struct {
+		method    string
+		path      string
+		operation *spec.Operation
+	}{
+		{http.MethodPost, resourceRootPath, resourceRoot.Post},
+		{http.MethodGet, resourceRootPath, resourceRoot.Get},
+		{http.MethodGet, resourceInstancePath, resourceInstance.Get},
+		{http.MethodPut, resourceInstancePath, resourceInstance.Put},
+		{http.MethodPatch, resourceInstancePath, resourceInstance.Patch},
+		{http.MethodDelete, resourceInstancePath, resourceInstance.Delete},
+	}
+	for _, o := range operations {
+		if o.operation == nil {
+			continue
+		}
+		if err := validateDurationExtension(o.operation.Extensions, extTfResourceTimeout); err != nil {
+			return fmt.Errorf("operation '%s %s' extension '%s' is not valid: %s", o.method, o.path, extTfResourceTimeout, err)
+		}
+		if o.operation.Responses == nil {
+			continue
+		}
+		var statusCodes []int
+		for statusCode := range o.operation.Responses.StatusCodeResponses {
+			statusCodes = append(statusCodes, statusCode)
+		}
+		sort.Ints(statusCodes)
+		for _, statusCode := range statusCodes {
+			response := o.operation.Responses.StatusCodeResponses[statusCode]
+			for _, extension := range []string{extTfResourcePollInterval, extTfResourcePollMaxInterval} {
+				if err := validateDurationExtension(response.Extensions, extension); err != nil {
+					return fmt.Errorf("operation '%s %s' response '%d' extension '%s' is not valid: %s", o.method, o.path, statusCode, extension, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateDurationExtension returns an error if the given extension is present and its value is not valid as per the
+// extension registry (e.g. not a valid duration)
+func validateDurationExtension(extensions spec.Extensions, extension string) error {
+	if value, exists := extensions[strings.ToLower(extension)]; exists {
+		if registeredExtension, registered := supportedExtensions.lookup(extension); registered {
+			return registeredExtension.validateValue(value)
+		}
+	}
+	return nil
+}
+
 func (specAnalyser *specV2Analyser) validateSubResourceTerraformCompliance(r SpecV2Resource) error {
 	parentResourceInfo := r.getParentResourceInfo()
 	if parentResourceInfo != nil {
@@ -156,12 +256,35 @@ func (specAnalyser *specV2Analyser) pathExists(path string) (bool, spec.PathItem
 		log.Printf("[WARN] path %s not found, falling back to checking if the path with trailing slash %s/ exists", path, path)
 		p, exists = specAnalyser.d.Spec().Paths.Paths[path+"/"]
 		if !exists {
-			return false, spec.PathItem{}
+			normalizedPath, found := specAnalyser.findNormalizedPath(path)
+			if !found {
+				return false, spec.PathItem{}
+			}
+			p = specAnalyser.d.Spec().Paths.Paths[normalizedPath]
 		}
 	}
 	return true, p
 }
 
+// findNormalizedPath looks for a path in the spec that matches the given one once both are normalized, that is ignoring
+// duplicate slashes and trailing slashes (e.g. '/v1//cdns/' matches '/v1/cdns'). This handles spec formatting quirks
+// where the paths of the same resource are not consistently defined. The spec paths are looked up in alphabetical order
+// so the same path is returned on every run if more than one matches (e.g. '/v1/cdns/' and '/v1//cdns').
+func (specAnalyser *specV2Analyser) findNormalizedPath(path string) (string, bool) {
+	normalizedPath := strings.TrimRight(normalizeURLPath(path), "/")
+	var specPaths []string
+	for specPath := range specAnalyser.d.Spec().Paths.Paths {
+		specPaths = append(specPaths, specPath)
+	}
+	sort.Strings(specPaths)
+	for _, specPath := range specPaths {
+		if strings.TrimRight(normalizeURLPath(specPath), "/") == normalizedPath {
+			return specPath, true
+		}
+	}
+	return "", false
+}
+
 // isMultiRegionResource returns true on ly if:
 // - the value is parametrized following the pattern: some.subdomain.${keyword}.domain.com, where ${keyword} must be present in the string, otherwise the resource will not be considered multi region
 // - there is a matching 'x-terraform-resource-regions-${keyword}' extension defined in the swagger root level (extensions passed in), where ${keyword} will be the value of the parameter in the above URL
@@ -223,6 +346,46 @@ func (specAnalyser *specV2Analyser) GetAPIBackendConfiguration() (SpecBackendCon
 	return newOpenAPIBackendConfigurationV2(specAnalyser.d.Spec(), specAnalyser.openAPIDocumentURL)
 }
 
+// GetDocumentInfo returns the API version and the hash of the OpenAPI document along with the paths that are not exposed
+// by any of the terraform compliant resources (root and instance paths) or data sources found in the document. Resources
+// configured to be ignored (x-terraform-exclude-resource) are considered skipped too
+func (specAnalyser *specV2Analyser) GetDocumentInfo() (*SpecDocumentInfo, error) {
+	exposedPaths := map[string]bool{}
+	resources, err := specAnalyser.GetTerraformCompliantResources()
+	if err != nil {
+		return nil, err
+	}
+	for _, resource := range resources {
+		if r, ok := resource.(*SpecV2Resource); ok && !r.shouldIgnoreResource() {
+			exposedPaths[r.Path] = true
+		}
+	}
+	for _, dataSource := range specAnalyser.GetTerraformCompliantDataSources() {
+		if d, ok := dataSource.(*SpecV2Resource); ok {
+			exposedPaths[d.Path] = true
+		}
+	}
+	skippedPaths := []string{}
+	for path := range specAnalyser.d.Spec().Paths.Paths {
+		if exposedPaths[path] {
+			continue
+		}
+		if resourceRootPath, _, _, err := specAnalyser.isEndPointFullyTerraformResourceCompliant(path); err == nil && exposedPaths[resourceRootPath] {
+			continue
+		}
+		skippedPaths = append(skippedPaths, path)
+	}
+	sort.Strings(skippedPaths)
+	documentInfo := &SpecDocumentInfo{
+		Hash:         specAnalyser.documentHash,
+		SkippedPaths: skippedPaths,
+	}
+	if specAnalyser.d.Spec().Info != nil {
+		documentInfo.Version = specAnalyser.d.Spec().Info.Version
+	}
+	return documentInfo, nil
+}
+
 // isEndPointFullyTerraformResourceCompliant returns true only if:
 // - The path given 'resourcePath' is an instance path (e,g: "/users/{username}")
 // - The path given has GET operation defined (required). PUT and DELETE are optional
@@ -283,13 +446,37 @@ func (specAnalyser *specV2Analyser) isEndPointFullyTerraformResourceCompliant(re
 	if err != nil {
 		return "", nil, nil, err
 	}
-	err = specAnalyser.validateResourceSchemaDefinition(resourceRootPostSchemaDef)
+	if compositeID := parseCompositeIDExtension(resourceRootPathItem.Post); len(compositeID) > 0 {
+		err = specAnalyser.validateCompositeID(resourcePath, compositeID, resourceRootPostSchemaDef)
+	} else {
+		err = specAnalyser.validateResourceSchemaDefinition(resourceRootPostSchemaDef)
+	}
 	if err != nil {
 		return "", nil, nil, err
 	}
 	return resourceRootPath, resourceRootPathItem, resourceRootPostSchemaDef, nil
 }
 
+// validateCompositeID checks that the properties listed in the x-terraform-composite-id extension are defined in the
+// resource schema and that there is one of them per path parameter in the resource instance path, so the resource does
+// not need an 'id' property to be identified
+func (specAnalyser *specV2Analyser) validateCompositeID(resourcePath string, compositeID []string, schema *spec.Schema) error {
+	for _, propertyName := range compositeID {
+		property, exists := schema.Properties[propertyName]
+		if !exists {
+			return fmt.Errorf("resource schema is missing the property '%s' listed in the '%s' extension", propertyName, extTfCompositeID)
+		}
+		if !property.Type.Contains("string") && !property.Type.Contains("integer") {
+			return fmt.Errorf("resource schema property '%s' listed in the '%s' extension must be of type string or integer", propertyName, extTfCompositeID)
+		}
+	}
+	pathParameters := regexp.MustCompile(`{\w+}`).FindAllString(resourcePath, -1)
+	if len(pathParameters) != len(compositeID) {
+		return fmt.Errorf("resource instance path '%s' contains %d path parameters but the '%s' extension lists %d properties", resourcePath, len(pathParameters), extTfCompositeID, len(compositeID))
+	}
+	return nil
+}
+
 func (specAnalyser *specV2Analyser) isEndPointTerraformDataSourceCompliant(path spec.PathItem) (*spec.Schema, error) {
 	if path.Get == nil {
 		return nil, errors.New("missing get operation")
@@ -480,5 +667,10 @@ func (specAnalyser *specV2Analyser) findMatchingResourceRootPath(resourceInstanc
 		return resourceRootPath, nil
 	}
 
+	if specPath, found := specAnalyser.findNormalizedPath(resourceRootPath); found {
+		log.Printf("[DEBUG] found resource root path '%s' after normalizing the slashes of '%s'", specPath, resourceRootPath)
+		return specPath, nil
+	}
+
 	return "", fmt.Errorf("resource instance path '%s' missing resource root path", resourceInstancePath)
 }
@@ -7,9 +7,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/go-openapi/loads"
 	"github.com/go-openapi/spec"
@@ -149,15 +151,57 @@ definitions:
 		})
 		Convey("When pathExists is called with a path that is listed but with a trailing slash", func() {
 			b, i := a.pathExists("/abusers/{id}/")
-			Convey("Then it returns false and the PathItem is not nil", func() {
-				So(b, ShouldBeFalse)
-				So(i, ShouldNotBeNil)
+			Convey("Then it returns true once the path is normalized and the PathItem Operation is not nil", func() {
+				So(b, ShouldBeTrue)
+				So(i.Get, ShouldNotBeNil)
 			})
 		})
 	})
 
 }
 
+func TestFindNormalizedPath(t *testing.T) {
+	Convey("Given a specV2Analyser loaded with a swagger file containing two paths that are the same once normalized", t, func() {
+		swaggerDoc := `swagger: "2.0"
+paths:
+  /v1/cdns/:
+    get:
+      responses:
+        200:
+          description: "successful operation"
+  /v1//cdns:
+    get:
+      responses:
+        200:
+          description: "successful operation"
+  /v1/lbs:
+    get:
+      responses:
+        200:
+          description: "successful operation"`
+		a := initAPISpecAnalyser(swaggerDoc)
+		Convey("When findNormalizedPath is called several times with a path matching both of them", func() {
+			var pathsFound []string
+			for i := 0; i < 20; i++ {
+				path, found := a.findNormalizedPath("/v1/cdns")
+				So(found, ShouldBeTrue)
+				pathsFound = append(pathsFound, path)
+			}
+			Convey("Then the first path in alphabetical order should be returned every time", func() {
+				for _, path := range pathsFound {
+					So(path, ShouldEqual, "/v1//cdns")
+				}
+			})
+		})
+		Convey("When findNormalizedPath is called with a path not matching any of them", func() {
+			_, found := a.findNormalizedPath("/v1/monitors")
+			Convey("Then the path should not be found", func() {
+				So(found, ShouldBeFalse)
+			})
+		})
+	})
+}
+
 func Test_bodyParameterExists(t *testing.T) {
 	Convey("Given a specV2Analyser", t, func() {
 		specV2Analyser := &specV2Analyser{}
@@ -604,7 +648,7 @@ func TestNewSpecAnalyserV2(t *testing.T) {
 		Convey("When newSpecAnalyserV2 method is called", func() {
 			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name())
 			Convey("Then the error returned should be the expected error", func() {
-				So(err.Error(), ShouldContainSubstring, "error = read .: is a directory")
+				So(err.Error(), ShouldContainSubstring, fmt.Sprintf("failed to retrieve the document '%sbadbadpath'", ts.URL))
 			})
 			Convey("AND the specAnalyserV2 struct should be nil", func() {
 				So(specAnalyserV2, ShouldBeNil)
@@ -848,8 +892,8 @@ func TestNewSpecAnalyserV2(t *testing.T) {
 		Convey("When newSpecAnalyserV2 method is called", func() {
 			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name())
 			Convey("Then the error returned should be not nil", func() {
-				So(err.Error(), ShouldContainSubstring, "failed to expand the OpenAPI document from ")
-				So(err.Error(), ShouldContainSubstring, " - error = open nosuchfile.json: no such file or directory")
+				So(err.Error(), ShouldContainSubstring, "failed to resolve the external references of the OpenAPI document from ")
+				So(err.Error(), ShouldContainSubstring, "nosuchfile.json: no such file or directory")
 			})
 			Convey("AND the specAnalyserV2 struct should be nil", func() {
 				So(specAnalyserV2, ShouldBeNil)
@@ -1505,6 +1549,52 @@ definitions:
 		})
 	})
 
+	Convey("Given an apiSpecAnalyser with a resource instance path and a root path that differ in duplicate slashes", t, func() {
+		swaggerContent := `swagger: "2.0"
+paths:
+  /v1//users:
+    post:
+      parameters:
+      - in: "body"
+        name: "body"
+        schema:
+          $ref: "#/definitions/Users"
+      responses:
+        201:
+          schema:
+            $ref: "#/definitions/Users"
+  /v1/users/{id}:
+    get:
+      parameters:
+      - name: "id"
+        in: "path"
+        required: true
+        type: "string"
+      responses:
+        200:
+          schema:
+            $ref: "#/definitions/Users"
+definitions:
+  Users:
+    type: "object"
+    properties:
+      id:
+        type: "string"
+        readOnly: true
+      name:
+        type: "string"`
+		a := initAPISpecAnalyser(swaggerContent)
+		Convey("When findMatchingResourceRootPath method is called ", func() {
+			resourceRootPath, err := a.findMatchingResourceRootPath("/v1/users/{id}")
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the value returned should be the root path as defined in the spec", func() {
+				So(resourceRootPath, ShouldEqual, "/v1//users")
+			})
+		})
+	})
+
 }
 
 func TestPostIsPresent(t *testing.T) {
@@ -2620,7 +2710,7 @@ definitions:
 			pathRootItem := a.d.Spec().Paths.Paths["/v1/cdns"]
 			pathItem := a.d.Spec().Paths.Paths["/v1/cdns/{id}"]
 			resourcePayloadSchemaDef := a.d.Spec().Definitions["ContentDeliveryNetwork"]
-			multiRegionResources, err := a.createMultiRegionResources(regions, resourceRootPath, pathRootItem, pathItem, &resourcePayloadSchemaDef)
+			multiRegionResources, err := a.createMultiRegionResources(regions, resourceRootPath, pathRootItem, pathItem, &resourcePayloadSchemaDef, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -2641,7 +2731,7 @@ definitions:
 			pathRootItem := a.d.Spec().Paths.Paths["/v1/cdns"]
 			pathItem := a.d.Spec().Paths.Paths["/v1/cdns/{id}"]
 			resourcePayloadSchemaDef := a.d.Spec().Definitions["ContentDeliveryNetwork"]
-			multiRegionResources, err := a.createMultiRegionResources(regions, resourceRootPath, pathRootItem, pathItem, &resourcePayloadSchemaDef)
+			multiRegionResources, err := a.createMultiRegionResources(regions, resourceRootPath, pathRootItem, pathItem, &resourcePayloadSchemaDef, nil)
 			Convey("Then the error returned should be as expected", func() {
 				So(err.Error(), ShouldEqual, "failed to create a resource with region: path must not be empty")
 			})
@@ -2732,6 +2822,43 @@ definitions:
 				},
 			},
 		},
+		{
+			name: "happy path: given 2 datasource endpoints that are TF compatible and one of them is excluded via x-terraform-exclude-data-source",
+			inputSwagger: `swagger: "2.0"
+host: 127.0.0.1 
+paths:
+  /v1/cdns:
+    get:
+      responses:
+        200:
+          schema:
+            $ref: "#/definitions/ContentDeliveryNetworkV1Collection"
+  /v1/audits:
+    get:
+      x-terraform-exclude-data-source: true
+      responses:
+        200:
+          schema:
+            $ref: "#/definitions/ContentDeliveryNetworkV1Collection"
+definitions:
+  ContentDeliveryNetworkV1Collection:
+    type: "array"
+    items:
+      $ref: "#/definitions/ContentDeliveryNetworkV1"
+  ContentDeliveryNetworkV1:
+    type: "object"
+    properties:
+      id:
+        type: "string"
+        readOnly: true
+      label:
+        type: "string"`,
+			expectedDataSources: []SpecResource{
+				&specStubResource{
+					name: "cdns_v1",
+				},
+			},
+		},
 		{
 			name: "happy path: given 1 datasource which is TF compatible but not parseable as a SpecV2DataSource",
 			inputSwagger: `swagger: "2.0"
@@ -2771,8 +2898,209 @@ definitions:
 	}
 }
 
+func TestGetTerraformCompliantResources_InvalidDurationExtensions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		postExtension string
+		getExtension  string
+		expectedError string
+	}{
+		{
+			name:          "invalid timeout in the root POST operation",
+			postExtension: `x-terraform-resource-timeout: "5 minutes"`,
+			expectedError: "operation 'POST /v1/cdns' extension 'x-terraform-resource-timeout' is not valid: invalid duration value: '5 minutes'",
+		},
+		{
+			name:          "negative timeout in the instance GET operation",
+			getExtension:  `x-terraform-resource-timeout: "-30s"`,
+			expectedError: "operation 'GET /v1/cdns/{id}' extension 'x-terraform-resource-timeout' is not valid: invalid duration value: '-30s'",
+		},
+		{
+			name:          "valid durations",
+			postExtension: `x-terraform-resource-timeout: 300`,
+			getExtension:  `x-terraform-resource-timeout: 1m30s`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			swaggerContent := fmt.Sprintf(`swagger: "2.0"
+paths:
+  /v1/cdns:
+    post:
+      %s
+      parameters:
+      - in: "body"
+        name: "body"
+        required: true
+        schema:
+          $ref: "#/definitions/ContentDeliveryNetwork"
+      responses:
+        202:
+          x-terraform-resource-poll-enabled: true
+          x-terraform-resource-poll-interval: 10s
+          schema:
+            $ref: "#/definitions/ContentDeliveryNetwork"
+  /v1/cdns/{id}:
+    get:
+      %s
+      parameters:
+      - name: "id"
+        in: "path"
+        required: true
+        type: "string"
+      responses:
+        200:
+          schema:
+            $ref: "#/definitions/ContentDeliveryNetwork"
+definitions:
+  ContentDeliveryNetwork:
+    type: "object"
+    properties:
+      id:
+        type: "string"
+        readOnly: true`, tc.postExtension, tc.getExtension)
+			a := initAPISpecAnalyser(swaggerContent)
+			resources, err := a.GetTerraformCompliantResources()
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, resources, 1)
+		})
+	}
+
+	swaggerContent := `swagger: "2.0"
+paths:
+  /v1/cdns:
+    post:
+      parameters:
+      - in: "body"
+        name: "body"
+        required: true
+        schema:
+          $ref: "#/definitions/ContentDeliveryNetwork"
+      responses:
+        202:
+          x-terraform-resource-poll-enabled: true
+          x-terraform-resource-poll-interval: 1.5
+          schema:
+            $ref: "#/definitions/ContentDeliveryNetwork"
+  /v1/cdns/{id}:
+    get:
+      parameters:
+      - name: "id"
+        in: "path"
+        required: true
+        type: "string"
+      responses:
+        200:
+          schema:
+            $ref: "#/definitions/ContentDeliveryNetwork"
+definitions:
+  ContentDeliveryNetwork:
+    type: "object"
+    properties:
+      id:
+        type: "string"
+        readOnly: true`
+	a := initAPISpecAnalyser(swaggerContent)
+	_, err := a.GetTerraformCompliantResources()
+	assert.EqualError(t, err, "operation 'POST /v1/cdns' response '202' extension 'x-terraform-resource-poll-interval' is not valid: invalid duration value: '1.5'. The value must be either a duration (e,g: 30s, 1.5m, 1h30m) or an integer number of seconds (e,g: 30); negative and zero durations are not allowed: fractional seconds must be expressed as a duration (e,g: 1.5s)")
+
+	a = initAPISpecAnalyser(strings.Replace(swaggerContent, "x-terraform-resource-poll-interval: 1.5", "x-terraform-resource-poll-max-interval: -5m", 1))
+	_, err = a.GetTerraformCompliantResources()
+	assert.EqualError(t, err, "operation 'POST /v1/cdns' response '202' extension 'x-terraform-resource-poll-max-interval' is not valid: invalid duration value: '-5m'. The value must be either a duration (e,g: 30s, 1.5m, 1h30m) or an integer number of seconds (e,g: 30); negative and zero durations are not allowed: the duration must be greater than zero")
+}
+
 func TestGetTerraformCompliantResources(t *testing.T) {
 
+	Convey("Given an specV2Analyser loaded with a swagger file containing the same resource in two versions /v1/cdns and /v2/cdns sharing the same model", t, func() {
+		swaggerContent := `swagger: "2.0"
+paths:
+  /v1/cdns:
+    post:
+      parameters:
+      - in: "body"
+        name: "body"
+        required: true
+        schema:
+          $ref: "#/definitions/ContentDeliveryNetwork"
+      responses:
+        201:
+          schema:
+            $ref: "#/definitions/ContentDeliveryNetwork"
+  /v1/cdns/{id}:
+    get:
+      parameters:
+      - name: "id"
+        in: "path"
+        required: true
+        type: "string"
+      responses:
+        200:
+          schema:
+            $ref: "#/definitions/ContentDeliveryNetwork"
+  /v2/cdns:
+    post:
+      parameters:
+      - in: "body"
+        name: "body"
+        required: true
+        schema:
+          $ref: "#/definitions/ContentDeliveryNetwork"
+      responses:
+        201:
+          schema:
+            $ref: "#/definitions/ContentDeliveryNetwork"
+  /v2/cdns/{id}:
+    get:
+      parameters:
+      - name: "id"
+        in: "path"
+        required: true
+        type: "string"
+      responses:
+        200:
+          schema:
+            $ref: "#/definitions/ContentDeliveryNetwork"
+definitions:
+  ContentDeliveryNetwork:
+    type: "object"
+    properties:
+      id:
+        type: "string"
+        readOnly: true
+      label:
+        type: "string"`
+
+		a := initAPISpecAnalyser(swaggerContent)
+		Convey("When GetTerraformCompliantResources method is called ", func() {
+			terraformCompliantResources, err := a.GetTerraformCompliantResources()
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And both versions of the resource should be returned", func() {
+				So(len(terraformCompliantResources), ShouldEqual, 2)
+				var resourceNames []string
+				for _, r := range terraformCompliantResources {
+					resourceNames = append(resourceNames, r.getResourceName())
+				}
+				So(resourceNames, ShouldContain, "cdns_v1")
+				So(resourceNames, ShouldContain, "cdns_v2")
+			})
+			Convey("And the analysis of the schema should be shared by both versions", func() {
+				schemaV1, err := terraformCompliantResources[0].getResourceSchema()
+				So(err, ShouldBeNil)
+				schemaV2, err := terraformCompliantResources[1].getResourceSchema()
+				So(err, ShouldBeNil)
+				So(schemaV1, ShouldPointTo, schemaV2)
+				So(len(schemaV1.Properties), ShouldEqual, 2)
+			})
+		})
+	})
+
 	Convey("Given an specV2Analyser loaded with a swagger file containing a compliant terraform subresource /v1/cdns/{id}/v1/firewalls but missing the parent resource resource description", t, func() {
 		swaggerContent := `swagger: "2.0"
 host: 127.0.0.1 
@@ -3733,7 +4061,7 @@ definitions:
 		})
 	})
 
-	Convey("Given a valid swagger doc where a definition has a ref to an external definition hosted somewhere else (in this case an HTTP server)", t, func() {
+	Convey("Given a valid swagger doc where a definition has a ref to an external definition hosted somewhere else that can not be retrieved", t, func() {
 		var swaggerJSON = createSwaggerWithExternalRef("//not.a.user@%66%6f%6f.com/just/a/path/also")
 
 		swaggerFile := initAPISpecFile(swaggerJSON)
@@ -3741,19 +4069,11 @@ definitions:
 
 		Convey("When newSpecAnalyserV2 method is called", func() {
 			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name())
-			Convey("Then the error returned by calling newSpecAnalyserV2 should be nil", func() {
-				So(err, ShouldBeNil)
-			})
-			Convey("AND the specAnalyserV2 struct should not be nil", func() {
-				So(specAnalyserV2, ShouldNotBeNil)
-			})
-
-			specResources, err := specAnalyserV2.GetTerraformCompliantResources()
-			Convey("Then the error returned by calling GetTerraformCompliantResources should be nil", func() {
-				So(err, ShouldBeNil)
+			Convey("Then the error returned should mention the reference that could not be resolved", func() {
+				So(err.Error(), ShouldContainSubstring, "not.a.user@%66%6f%6f.com/just/a/path/also#/definitions/ContentDeliveryNetwork': failed to retrieve the document")
 			})
-			Convey("AND the specResources slice should not be nil", func() {
-				So(specResources, ShouldBeEmpty)
+			Convey("AND the specAnalyserV2 struct should be nil", func() {
+				So(specAnalyserV2, ShouldBeNil)
 			})
 		})
 	})
@@ -3951,3 +4271,157 @@ func createExternalSwaggerContent() string {
   }
 }`
 }
+
+func TestGetDocumentInfo(t *testing.T) {
+	swaggerContent := `swagger: "2.0"
+info:
+  version: "1.2.0"
+paths:
+  /v1/cdns:
+    post:
+      parameters:
+      - in: "body"
+        name: "body"
+        required: true
+        schema:
+          $ref: "#/definitions/ContentDeliveryNetwork"
+      responses:
+        201:
+          schema:
+            $ref: "#/definitions/ContentDeliveryNetwork"
+  /v1/cdns/{id}:
+    get:
+      parameters:
+      - name: "id"
+        in: "path"
+        required: true
+        type: "string"
+      responses:
+        200:
+          schema:
+            $ref: "#/definitions/ContentDeliveryNetwork"
+  /v1/excluded:
+    post:
+      x-terraform-exclude-resource: true
+      parameters:
+      - in: "body"
+        name: "body"
+        required: true
+        schema:
+          $ref: "#/definitions/ContentDeliveryNetwork"
+      responses:
+        201:
+          schema:
+            $ref: "#/definitions/ContentDeliveryNetwork"
+  /v1/excluded/{id}:
+    get:
+      parameters:
+      - name: "id"
+        in: "path"
+        required: true
+        type: "string"
+      responses:
+        200:
+          schema:
+            $ref: "#/definitions/ContentDeliveryNetwork"
+  /v1/regions:
+    get:
+      responses:
+        200:
+          schema:
+            type: "array"
+            items:
+              $ref: "#/definitions/ContentDeliveryNetwork"
+  /v1/health:
+    get:
+      responses:
+        200:
+          schema:
+            $ref: "#/definitions/ContentDeliveryNetwork"
+definitions:
+  ContentDeliveryNetwork:
+    type: "object"
+    properties:
+      id:
+        type: "string"
+        readOnly: true
+      label:
+        type: "string"`
+	a := initAPISpecAnalyser(swaggerContent)
+	documentInfo, err := a.GetDocumentInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.0", documentInfo.Version)
+	assert.Len(t, documentInfo.Hash, 64)
+	assert.Equal(t, []string{"/v1/excluded", "/v1/excluded/{id}", "/v1/health"}, documentInfo.SkippedPaths)
+
+	otherAnalyser := initAPISpecAnalyser(strings.Replace(swaggerContent, `version: "1.2.0"`, `version: "1.3.0"`, 1))
+	otherDocumentInfo, err := otherAnalyser.GetDocumentInfo()
+	require.NoError(t, err)
+	assert.NotEqual(t, documentInfo.Hash, otherDocumentInfo.Hash)
+}
+
+func TestGetTerraformCompliantResources_CompositeID(t *testing.T) {
+	swaggerTemplate := `{
+  "swagger": "2.0",
+  "host": "localhost:8443",
+  "paths": {
+    "/v1/ns/{namespace}/things": {
+      "post": {
+        "x-terraform-composite-id": "%s",
+        "parameters": [{"in": "path", "name": "namespace", "required": true, "type": "string"}, {"in": "body", "name": "body", "schema": {"$ref": "#/definitions/Thing"}}],
+        "responses": {"201": {"description": "created", "schema": {"$ref": "#/definitions/Thing"}}}
+      }
+    },
+    "/v1/ns/{namespace}/things/{name}": {
+      "get": {
+        "parameters": [{"in": "path", "name": "namespace", "required": true, "type": "string"}, {"in": "path", "name": "name", "required": true, "type": "string"}],
+        "responses": {"200": {"description": "found", "schema": {"$ref": "#/definitions/Thing"}}}
+      }
+    }
+  },
+  "definitions": {
+    "Thing": {"type": "object", "properties": {"namespace": {"type": "string"}, "name": {"type": "string"}, "tags": {"type": "array", "items": {"type": "string"}}}}
+  }
+}`
+	testCases := []struct {
+		name              string
+		compositeID       string
+		expectedResources int
+	}{
+		{name: "composite id properties match the path parameters", compositeID: "namespace, name", expectedResources: 1},
+		{name: "composite id property missing in the schema", compositeID: "namespace,label", expectedResources: 0},
+		{name: "composite id property of type not supported", compositeID: "namespace,tags", expectedResources: 0},
+		{name: "composite id properties do not match the number of path parameters", compositeID: "name", expectedResources: 0},
+	}
+	for _, tc := range testCases {
+		swaggerFile := initAPISpecFile(fmt.Sprintf(swaggerTemplate, tc.compositeID))
+		specAnalyser, err := newSpecAnalyserV2(swaggerFile.Name())
+		os.Remove(swaggerFile.Name())
+		require.NoError(t, err, tc.name)
+		resources, err := specAnalyser.GetTerraformCompliantResources()
+		require.NoError(t, err, tc.name)
+		require.Len(t, resources, tc.expectedResources, tc.name)
+		if tc.expectedResources == 0 {
+			continue
+		}
+		assert.Equal(t, "things", resources[0].getResourceName(), tc.name)
+		assert.Nil(t, resources[0].getParentResourceInfo(), tc.name)
+		assert.Equal(t, []string{"namespace", "name"}, resources[0].getCompositeID(), tc.name)
+		resourcePath, err := resources[0].getResourcePath([]string{"ns1"})
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, "/v1/ns/ns1/things", resourcePath, tc.name)
+	}
+}
+
+func TestValidateCompositeID(t *testing.T) {
+	specAnalyser := &specV2Analyser{}
+	schema := &spec.Schema{SchemaProps: spec.SchemaProps{Properties: map[string]spec.Schema{
+		"namespace": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}},
+		"number":    {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"integer"}}},
+		"enabled":   {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"boolean"}}},
+	}}}
+	assert.NoError(t, specAnalyser.validateCompositeID("/v1/ns/{namespace}/things/{number}", []string{"namespace", "number"}, schema))
+	assert.EqualError(t, specAnalyser.validateCompositeID("/v1/ns/{namespace}/things/{name}", []string{"namespace", "name"}, schema), "resource schema is missing the property 'name' listed in the 'x-terraform-composite-id' extension")
+	assert.EqualError(t, specAnalyser.validateCompositeID("/v1/ns/{namespace}/things/{enabled}", []string{"namespace", "enabled"}, schema), "resource schema property 'enabled' listed in the 'x-terraform-composite-id' extension must be of type string or integer")
+	assert.EqualError(t, specAnalyser.validateCompositeID("/v1/ns/{namespace}/things/{number}", []string{"number"}, schema), "resource instance path '/v1/ns/{namespace}/things/{number}' contains 2 path parameters but the 'x-terraform-composite-id' extension lists 1 properties")
+}
@@ -0,0 +1,655 @@
+package openapi
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// openAPIv3RefReplacer translates the references to the OpenAPI v3 components into their OpenAPI v2 counterparts
+var openAPIv3RefReplacer = strings.NewReplacer(
+	"#/components/schemas/", "#/definitions/",
+	"#/components/parameters/", "#/parameters/",
+	"#/components/responses/", "#/responses/",
+)
+
+// serverVariableRegex matches the variables in the OpenAPI v3 server urls (e.g. https://{environment}.api.com)
+var serverVariableRegex = regexp.MustCompile(`{([^}]+)}`)
+
+// openAPIv3Operations contains the path item fields that describe operations
+var openAPIv3Operations = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// openAPIv3SchemaFields contains the schema fields that are copied over to the non body parameters and headers when
+// flattening their OpenAPI v3 schema (OpenAPI v2 parameters and headers do not have a schema but the schema fields
+// are part of the parameter itself)
+var openAPIv3SchemaFields = []string{"type", "format", "items", "default", "enum", "pattern", "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "minLength", "maxLength", "minItems", "maxItems", "uniqueItems", "multipleOf"}
+
+// httpBearerSecurityDefinitionName is the name of the security definition http bearer security schemes are translated
+// into, so the provider property holding the token is named 'token' regardless of the name of the security scheme
+const httpBearerSecurityDefinitionName = "token"
+
+// openAPIv3DocumentConverter translates OpenAPI v3.0.x and v3.1.x documents into their OpenAPI v2 equivalent. Only the
+// fields the spec analyser makes use of are translated; features that have no OpenAPI v2 counterpart (e.g. callbacks,
+// links, webhooks or cookie parameters) are ignored
+type openAPIv3DocumentConverter struct {
+	document   map[string]interface{}
+	components map[string]interface{}
+	// securityDefinitionNames contains the names of the security definitions the security schemes are translated into,
+	// keyed by security scheme name
+	securityDefinitionNames map[string]string
+}
+
+func newOpenAPIv3DocumentConverter(document map[string]interface{}) openAPIv3DocumentConverter {
+	components, _ := document["components"].(map[string]interface{})
+	c := openAPIv3DocumentConverter{
+		document:   document,
+		components: components,
+	}
+	c.securityDefinitionNames = c.getSecurityDefinitionNames()
+	return c
+}
+
+// convert returns the OpenAPI v2 version of the document
+func (c openAPIv3DocumentConverter) convert() (map[string]interface{}, error) {
+	swagger := map[string]interface{}{
+		"swagger": "2.0",
+	}
+	for name, value := range c.document {
+		switch {
+		case name == "info" || name == "tags" || name == "externalDocs":
+			swagger[name] = value
+		case name == "security":
+			swagger[name] = c.convertSecurityRequirements(value)
+		case isExtension(name):
+			swagger[name] = value
+		}
+	}
+
+	if servers, ok := c.document["servers"].([]interface{}); ok && len(servers) > 0 {
+		host, basePath, schemes, err := c.convertServers(servers)
+		if err != nil {
+			return nil, err
+		}
+		if host != "" {
+			swagger["host"] = host
+		}
+		if basePath != "" {
+			swagger["basePath"] = basePath
+		}
+		if len(schemes) > 0 {
+			swagger["schemes"] = schemes
+		}
+	}
+
+	if schemas := c.getComponents("schemas"); len(schemas) > 0 {
+		definitions := map[string]interface{}{}
+		for name, schema := range schemas {
+			definition, err := c.convertSchema(schema)
+			if err != nil {
+				return nil, fmt.Errorf("schema '%s' is not valid: %s", name, err)
+			}
+			definitions[name] = definition
+		}
+		swagger["definitions"] = definitions
+	}
+	if parameters := c.getComponents("parameters"); len(parameters) > 0 {
+		swaggerParameters := map[string]interface{}{}
+		for name, parameter := range parameters {
+			swaggerParameter, err := c.convertParameter(parameter)
+			if err != nil {
+				return nil, fmt.Errorf("parameter '%s' is not valid: %s", name, err)
+			}
+			if swaggerParameter != nil {
+				swaggerParameters[name] = swaggerParameter
+			}
+		}
+		swagger["parameters"] = swaggerParameters
+	}
+	if responses := c.getComponents("responses"); len(responses) > 0 {
+		swaggerResponses := map[string]interface{}{}
+		for name, response := range responses {
+			swaggerResponse, err := c.convertResponse(response)
+			if err != nil {
+				return nil, fmt.Errorf("response '%s' is not valid: %s", name, err)
+			}
+			swaggerResponses[name] = swaggerResponse
+		}
+		swagger["responses"] = swaggerResponses
+	}
+	if securitySchemes := c.getComponents("securitySchemes"); len(securitySchemes) > 0 {
+		securityDefinitions := map[string]interface{}{}
+		for name, securityScheme := range securitySchemes {
+			if securityDefinition := c.convertSecurityScheme(name, securityScheme); securityDefinition != nil {
+				securityDefinitions[c.getSecurityDefinitionName(name)] = securityDefinition
+			}
+		}
+		swagger["securityDefinitions"] = securityDefinitions
+	}
+
+	paths := map[string]interface{}{}
+	if documentPaths, ok := c.document["paths"].(map[string]interface{}); ok {
+		for path, pathItem := range documentPaths {
+			swaggerPathItem, err := c.convertPathItem(path, pathItem)
+			if err != nil {
+				return nil, err
+			}
+			paths[path] = swaggerPathItem
+		}
+	}
+	swagger["paths"] = paths
+
+	return replaceOpenAPIv3Refs(swagger).(map[string]interface{}), nil
+}
+
+// convertServers translates the servers into the host, base path and schemes. The host and base path are taken from the
+// first server, and the schemes from all the servers pointing at the same host and base path (e.g. http and https
+// variants of the same server). Server variables are replaced by their default values.
+func (c openAPIv3DocumentConverter) convertServers(servers []interface{}) (string, string, []string, error) {
+	var host, basePath string
+	var schemes []string
+	for idx, s := range servers {
+		server, _ := s.(map[string]interface{})
+		serverURL, _ := server["url"].(string)
+		variables, _ := server["variables"].(map[string]interface{})
+		serverURL = serverVariableRegex.ReplaceAllStringFunc(serverURL, func(match string) string {
+			variable, _ := variables[strings.Trim(match, "{}")].(map[string]interface{})
+			if defaultValue, ok := variable["default"].(string); ok {
+				return defaultValue
+			}
+			return match
+		})
+		u, err := url.Parse(serverURL)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("server url '%s' is not valid: %s", serverURL, err)
+		}
+		serverBasePath := strings.TrimRight(u.Path, "/")
+		if idx == 0 {
+			host = u.Host
+			basePath = serverBasePath
+		} else if u.Host != host || serverBasePath != basePath {
+			log.Printf("[WARN] ignoring server '%s' since only the first server of the OpenAPI document is used", serverURL)
+			continue
+		}
+		if u.Scheme != "" {
+			schemes = append(schemes, u.Scheme)
+		}
+	}
+	return host, basePath, schemes, nil
+}
+
+func (c openAPIv3DocumentConverter) convertPathItem(path string, value interface{}) (map[string]interface{}, error) {
+	pathItem, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("path '%s' is not a valid path item", path)
+	}
+	swaggerPathItem := map[string]interface{}{}
+	for name, value := range pathItem {
+		switch {
+		case name == "parameters":
+			parameters, err := c.convertParameters(value)
+			if err != nil {
+				return nil, fmt.Errorf("path '%s' is not valid: %s", path, err)
+			}
+			swaggerPathItem[name] = parameters
+		case name == "$ref" || isExtension(name):
+			swaggerPathItem[name] = value
+		}
+	}
+	for _, method := range openAPIv3Operations {
+		if operation, ok := pathItem[method].(map[string]interface{}); ok {
+			swaggerOperation, err := c.convertOperation(operation)
+			if err != nil {
+				return nil, fmt.Errorf("operation '%s %s' is not valid: %s", strings.ToUpper(method), path, err)
+			}
+			swaggerPathItem[method] = swaggerOperation
+		}
+	}
+	return swaggerPathItem, nil
+}
+
+func (c openAPIv3DocumentConverter) convertOperation(operation map[string]interface{}) (map[string]interface{}, error) {
+	swaggerOperation := map[string]interface{}{}
+	for name, value := range operation {
+		switch name {
+		case "requestBody", "callbacks", "servers":
+		case "parameters":
+			parameters, err := c.convertParameters(value)
+			if err != nil {
+				return nil, err
+			}
+			swaggerOperation[name] = parameters
+		case "responses":
+			swaggerResponses := map[string]interface{}{}
+			responses, _ := value.(map[string]interface{})
+			for code, response := range responses {
+				swaggerResponse, err := c.convertResponse(response)
+				if err != nil {
+					return nil, fmt.Errorf("response '%s' is not valid: %s", code, err)
+				}
+				swaggerResponses[code] = swaggerResponse
+			}
+			swaggerOperation[name] = swaggerResponses
+		case "security":
+			swaggerOperation[name] = c.convertSecurityRequirements(value)
+		default:
+			swaggerOperation[name] = value
+		}
+	}
+	if requestBody, ok := operation["requestBody"]; ok {
+		bodyParameter, mediaType, err := c.convertRequestBody(requestBody)
+		if err != nil {
+			return nil, fmt.Errorf("request body is not valid: %s", err)
+		}
+		if bodyParameter != nil {
+			parameters, _ := swaggerOperation["parameters"].([]interface{})
+			swaggerOperation["parameters"] = append(parameters, bodyParameter)
+		}
+		if mediaType != "" {
+			swaggerOperation["consumes"] = []interface{}{mediaType}
+		}
+	}
+	return swaggerOperation, nil
+}
+
+func (c openAPIv3DocumentConverter) convertParameters(value interface{}) ([]interface{}, error) {
+	swaggerParameters := []interface{}{}
+	parameters, _ := value.([]interface{})
+	for _, parameter := range parameters {
+		swaggerParameter, err := c.convertParameter(parameter)
+		if err != nil {
+			return nil, err
+		}
+		if swaggerParameter != nil {
+			swaggerParameters = append(swaggerParameters, swaggerParameter)
+		}
+	}
+	return swaggerParameters, nil
+}
+
+// convertParameter translates the given parameter flattening its schema into the parameter. Cookie parameters are not
+// supported in OpenAPI v2, hence nil is returned
+func (c openAPIv3DocumentConverter) convertParameter(value interface{}) (map[string]interface{}, error) {
+	parameter, _ := value.(map[string]interface{})
+	if _, ok := parameter["$ref"]; ok {
+		return parameter, nil
+	}
+	if parameter["in"] == "cookie" {
+		log.Printf("[WARN] ignoring cookie parameter '%v' since cookie parameters are not supported", parameter["name"])
+		return nil, nil
+	}
+	swaggerParameter := map[string]interface{}{}
+	for name, value := range parameter {
+		switch {
+		case name == "name" || name == "in" || name == "description" || name == "required" || isExtension(name):
+			swaggerParameter[name] = value
+		}
+	}
+	if err := c.flattenSchema(swaggerParameter, parameter["schema"]); err != nil {
+		return nil, fmt.Errorf("parameter '%v' is not valid: %s", parameter["name"], err)
+	}
+	return swaggerParameter, nil
+}
+
+// convertRequestBody translates the given request body into a body parameter, returning as well the media type the
+// schema of the body parameter was taken from
+func (c openAPIv3DocumentConverter) convertRequestBody(value interface{}) (map[string]interface{}, string, error) {
+	requestBody := c.resolveComponentRef(value, "requestBodies")
+	schema, mediaType, err := c.getMediaTypeSchema(requestBody["content"])
+	if err != nil || schema == nil {
+		return nil, mediaType, err
+	}
+	bodyParameter := map[string]interface{}{
+		"name":   "body",
+		"in":     "body",
+		"schema": schema,
+	}
+	for name, value := range requestBody {
+		switch {
+		case name == "description" || name == "required" || isExtension(name):
+			bodyParameter[name] = value
+		}
+	}
+	return bodyParameter, mediaType, nil
+}
+
+func (c openAPIv3DocumentConverter) convertResponse(value interface{}) (map[string]interface{}, error) {
+	response, _ := value.(map[string]interface{})
+	if _, ok := response["$ref"]; ok {
+		return response, nil
+	}
+	swaggerResponse := map[string]interface{}{
+		"description": "",
+	}
+	for name, value := range response {
+		switch {
+		case name == "description" || isExtension(name):
+			swaggerResponse[name] = value
+		case name == "headers":
+			swaggerHeaders := map[string]interface{}{}
+			headers, _ := value.(map[string]interface{})
+			for headerName, h := range headers {
+				header := c.resolveComponentRef(h, "headers")
+				swaggerHeader := map[string]interface{}{}
+				if description, ok := header["description"]; ok {
+					swaggerHeader["description"] = description
+				}
+				if err := c.flattenSchema(swaggerHeader, header["schema"]); err != nil {
+					return nil, fmt.Errorf("header '%s' is not valid: %s", headerName, err)
+				}
+				swaggerHeaders[headerName] = swaggerHeader
+			}
+			swaggerResponse[name] = swaggerHeaders
+		}
+	}
+	schema, _, err := c.getMediaTypeSchema(response["content"])
+	if err != nil {
+		return nil, err
+	}
+	if schema != nil {
+		swaggerResponse["schema"] = schema
+	}
+	return swaggerResponse, nil
+}
+
+// convertSchema translates the given schema and its nested schemas. The differences that matter to the spec analyser are:
+// - OpenAPI v3.0 'nullable' field, which in OpenAPI v2 is expressed with the 'x-nullable' extension
+// - OpenAPI v3.1 (JSON Schema draft 2020-12) type arrays, where the 'null' type is also translated into 'x-nullable'. Only
+// one type other than 'null' is supported since terraform attributes can only have one type
+// - OpenAPI v3.1 'const', translated into an enum with one value
+// - OpenAPI v3.1 numeric 'exclusiveMinimum' and 'exclusiveMaximum', translated into the minimum and maximum along with the
+// boolean exclusive fields
+// - OpenAPI v3.1 'unevaluatedProperties', translated into 'additionalProperties' since there is no schema composition
+// the properties could be evaluated by
+func (c openAPIv3DocumentConverter) convertSchema(value interface{}) (interface{}, error) {
+	schema, ok := value.(map[string]interface{})
+	if !ok {
+		return value, nil
+	}
+	swaggerSchema := map[string]interface{}{}
+	for name, value := range schema {
+		switch name {
+		case "nullable":
+			swaggerSchema["x-nullable"] = value
+		case "writeOnly", "deprecated", "oneOf", "anyOf", "not", "discriminator", "examples", "prefixItems", "$schema", "$id":
+		case "type":
+			types, ok := value.([]interface{})
+			if !ok {
+				swaggerSchema[name] = value
+				continue
+			}
+			var nonNullTypes []interface{}
+			for _, t := range types {
+				if t == "null" {
+					swaggerSchema["x-nullable"] = true
+					continue
+				}
+				nonNullTypes = append(nonNullTypes, t)
+			}
+			if len(nonNullTypes) != 1 {
+				return nil, fmt.Errorf("type '%v' not supported, only one type (optionally along with 'null') is supported", types)
+			}
+			swaggerSchema[name] = nonNullTypes[0]
+		case "const":
+			swaggerSchema["enum"] = []interface{}{value}
+		case "exclusiveMinimum", "exclusiveMaximum":
+			limit, isNumber := value.(float64)
+			if !isNumber {
+				swaggerSchema[name] = value
+				continue
+			}
+			swaggerSchema[name] = true
+			if name == "exclusiveMinimum" {
+				swaggerSchema["minimum"] = limit
+			} else {
+				swaggerSchema["maximum"] = limit
+			}
+		case "unevaluatedProperties":
+			if _, ok := schema["additionalProperties"]; ok {
+				continue
+			}
+			additionalProperties, err := c.convertSchema(value)
+			if err != nil {
+				return nil, err
+			}
+			swaggerSchema["additionalProperties"] = additionalProperties
+		case "properties":
+			properties, _ := value.(map[string]interface{})
+			swaggerProperties := map[string]interface{}{}
+			for propertyName, property := range properties {
+				swaggerProperty, err := c.convertSchema(property)
+				if err != nil {
+					return nil, fmt.Errorf("property '%s' is not valid: %s", propertyName, err)
+				}
+				swaggerProperties[propertyName] = swaggerProperty
+			}
+			swaggerSchema[name] = swaggerProperties
+		case "allOf":
+			schemas, _ := value.([]interface{})
+			swaggerSchemas := []interface{}{}
+			for _, s := range schemas {
+				swaggerItem, err := c.convertSchema(s)
+				if err != nil {
+					return nil, err
+				}
+				swaggerSchemas = append(swaggerSchemas, swaggerItem)
+			}
+			swaggerSchema[name] = swaggerSchemas
+		case "items", "additionalProperties":
+			swaggerItem, err := c.convertSchema(value)
+			if err != nil {
+				return nil, err
+			}
+			swaggerSchema[name] = swaggerItem
+		default:
+			swaggerSchema[name] = value
+		}
+	}
+	return swaggerSchema, nil
+}
+
+// convertSecurityScheme translates the given security scheme into a security definition. Security schemes that have no
+// OpenAPI v2 counterpart are ignored, in which case nil is returned
+func (c openAPIv3DocumentConverter) convertSecurityScheme(name string, value interface{}) map[string]interface{} {
+	securityScheme := c.resolveComponentRef(value, "securitySchemes")
+	securityDefinition := map[string]interface{}{}
+	for field, value := range securityScheme {
+		switch {
+		case field == "type" || field == "description" || isExtension(field):
+			securityDefinition[field] = value
+		}
+	}
+	switch securityScheme["type"] {
+	case "apiKey":
+		securityDefinition["name"] = securityScheme["name"]
+		securityDefinition["in"] = securityScheme["in"]
+	case "http":
+		switch scheme, _ := securityScheme["scheme"].(string); strings.ToLower(scheme) {
+		case "basic":
+			securityDefinition["type"] = "basic"
+		case "bearer":
+			// the token is sent in the Authorization header using the Bearer authentication scheme
+			securityDefinition["type"] = "apiKey"
+			securityDefinition["in"] = "header"
+			securityDefinition["name"] = authorizationHeader
+			securityDefinition[extTfAuthenticationSchemeBearer] = true
+		default:
+			log.Printf("[WARN] ignoring security scheme '%s' since http security schemes with scheme '%s' are not supported", name, scheme)
+			return nil
+		}
+	case "oauth2":
+		flows, _ := securityScheme["flows"].(map[string]interface{})
+		// OpenAPI v2 security definitions only support one flow, the first one found in the following order is used
+		for _, f := range []struct{ v3, v2 string }{{"clientCredentials", "application"}, {"password", "password"}, {"authorizationCode", "accessCode"}, {"implicit", "implicit"}} {
+			flow, ok := flows[f.v3].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			securityDefinition["flow"] = f.v2
+			for _, field := range []string{"authorizationUrl", "tokenUrl", "scopes"} {
+				if value, ok := flow[field]; ok {
+					securityDefinition[field] = value
+				}
+			}
+			return securityDefinition
+		}
+		log.Printf("[WARN] ignoring security scheme '%s' since it does not contain any oauth2 flow", name)
+		return nil
+	default:
+		log.Printf("[WARN] ignoring security scheme '%s' since security schemes of type '%v' are not supported", name, securityScheme["type"])
+		return nil
+	}
+	return securityDefinition
+}
+
+// getSecurityDefinitionNames returns the names of the security definitions the security schemes are translated into. The
+// names are kept as is, except for the http bearer security scheme which is renamed to 'token' as long as it is the only
+// http bearer security scheme and there is no other security scheme named 'token'
+func (c openAPIv3DocumentConverter) getSecurityDefinitionNames() map[string]string {
+	securityDefinitionNames := map[string]string{}
+	var httpBearerSecuritySchemes []string
+	for name, value := range c.getComponents("securitySchemes") {
+		securityDefinitionNames[name] = name
+		securityScheme := c.resolveComponentRef(value, "securitySchemes")
+		if scheme, _ := securityScheme["scheme"].(string); securityScheme["type"] == "http" && strings.ToLower(scheme) == "bearer" {
+			httpBearerSecuritySchemes = append(httpBearerSecuritySchemes, name)
+		}
+	}
+	if len(httpBearerSecuritySchemes) != 1 {
+		return securityDefinitionNames
+	}
+	if _, exists := securityDefinitionNames[httpBearerSecurityDefinitionName]; exists {
+		log.Printf("[WARN] http bearer security scheme '%s' is not renamed to '%s' since there is another security scheme with that name", httpBearerSecuritySchemes[0], httpBearerSecurityDefinitionName)
+		return securityDefinitionNames
+	}
+	securityDefinitionNames[httpBearerSecuritySchemes[0]] = httpBearerSecurityDefinitionName
+	return securityDefinitionNames
+}
+
+// getSecurityDefinitionName returns the name of the security definition the given security scheme is translated into
+func (c openAPIv3DocumentConverter) getSecurityDefinitionName(securitySchemeName string) string {
+	if securityDefinitionName, ok := c.securityDefinitionNames[securitySchemeName]; ok {
+		return securityDefinitionName
+	}
+	return securitySchemeName
+}
+
+// convertSecurityRequirements translates the security requirements (global or operation ones) so they refer to the
+// security definitions the security schemes are translated into
+func (c openAPIv3DocumentConverter) convertSecurityRequirements(value interface{}) interface{} {
+	requirements, ok := value.([]interface{})
+	if !ok {
+		return value
+	}
+	swaggerRequirements := []interface{}{}
+	for _, requirement := range requirements {
+		securitySchemes, ok := requirement.(map[string]interface{})
+		if !ok {
+			swaggerRequirements = append(swaggerRequirements, requirement)
+			continue
+		}
+		swaggerRequirement := map[string]interface{}{}
+		for securitySchemeName, scopes := range securitySchemes {
+			swaggerRequirement[c.getSecurityDefinitionName(securitySchemeName)] = scopes
+		}
+		swaggerRequirements = append(swaggerRequirements, swaggerRequirement)
+	}
+	return swaggerRequirements
+}
+
+// getMediaTypeSchema returns the schema of the JSON media type from the given content. If the content does not contain
+// JSON media types, the first one (in alphabetical order) is used
+func (c openAPIv3DocumentConverter) getMediaTypeSchema(value interface{}) (interface{}, string, error) {
+	content, _ := value.(map[string]interface{})
+	if len(content) == 0 {
+		return nil, "", nil
+	}
+	var mediaTypes []string
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+	selected := mediaTypes[0]
+	for _, mediaType := range mediaTypes {
+		if mediaType == "application/json" {
+			selected = mediaType
+			break
+		}
+		if strings.Contains(mediaType, "json") && !strings.Contains(selected, "json") {
+			selected = mediaType
+		}
+	}
+	mediaTypeObject, _ := content[selected].(map[string]interface{})
+	schema, ok := mediaTypeObject["schema"]
+	if !ok {
+		return nil, selected, nil
+	}
+	swaggerSchema, err := c.convertSchema(schema)
+	return swaggerSchema, selected, err
+}
+
+// flattenSchema copies the fields of the given schema into the given parameter or header. References are resolved
+// since OpenAPI v2 parameters and headers can not refer to definitions
+func (c openAPIv3DocumentConverter) flattenSchema(target map[string]interface{}, value interface{}) error {
+	convertedSchema, err := c.convertSchema(c.resolveComponentRef(value, "schemas"))
+	if err != nil {
+		return err
+	}
+	schema, _ := convertedSchema.(map[string]interface{})
+	for _, field := range openAPIv3SchemaFields {
+		if value, ok := schema[field]; ok {
+			target[field] = value
+		}
+	}
+	if _, ok := target["type"]; !ok {
+		target["type"] = "string"
+	}
+	return nil
+}
+
+// resolveComponentRef returns the component the given value refers to if the value is a reference to one of the
+// components of the given type (e.g. '#/components/requestBodies/Pet'); otherwise, the value itself is returned
+func (c openAPIv3DocumentConverter) resolveComponentRef(value interface{}, componentType string) map[string]interface{} {
+	object, _ := value.(map[string]interface{})
+	ref, ok := object["$ref"].(string)
+	if !ok {
+		return object
+	}
+	prefix := fmt.Sprintf("#/components/%s/", componentType)
+	if !strings.HasPrefix(ref, prefix) {
+		return object
+	}
+	component, _ := c.getComponents(componentType)[strings.TrimPrefix(ref, prefix)].(map[string]interface{})
+	return component
+}
+
+func (c openAPIv3DocumentConverter) getComponents(componentType string) map[string]interface{} {
+	components, _ := c.components[componentType].(map[string]interface{})
+	return components
+}
+
+// replaceOpenAPIv3Refs walks the given value replacing the references to OpenAPI v3 components with references to the
+// corresponding OpenAPI v2 fields
+func replaceOpenAPIv3Refs(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if ref, ok := item.(string); ok && key == "$ref" {
+				v[key] = openAPIv3RefReplacer.Replace(ref)
+				continue
+			}
+			v[key] = replaceOpenAPIv3Refs(item)
+		}
+	case []interface{}:
+		for idx, item := range v {
+			v[idx] = replaceOpenAPIv3Refs(item)
+		}
+	}
+	return value
+}
+
+func isExtension(name string) bool {
+	return strings.HasPrefix(strings.ToLower(name), "x-")
+}
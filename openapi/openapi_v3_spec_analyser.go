@@ -0,0 +1,89 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-openapi/loads"
+)
+
+// specV3Analyser defines a SpecAnalyser implementation for OpenAPI v3 specification. The OpenAPI v3 document is translated
+// into its OpenAPI v2 equivalent (components into definitions, parameters, responses and security definitions, request
+// bodies into body parameters and servers into host, base path and schemes) which is then analysed the same way OpenAPI v2
+// documents are. Hence, the terraform compliance rules and the extensions supported are the same regardless of the
+// version of the document.
+type specV3Analyser struct {
+	*specV2Analyser
+}
+
+// newSpecAnalyserV3 creates an instance of specV3Analyser which implements the SpecAnalyser interface
+// This implementation provides an analyser that understands OpenAPI v3.0.x and v3.1.x documents
+func newSpecAnalyserV3(openAPIDocumentFilename string) (*specV3Analyser, error) {
+	if openAPIDocumentFilename == "" {
+		return nil, errors.New("open api document filename argument empty, please provide the url of the OpenAPI document")
+	}
+	apiSpec, err := loads.JSONSpec(openAPIDocumentFilename)
+	if err != nil {
+		return nil, &SpecFetchError{URL: openAPIDocumentFilename, Err: fmt.Errorf("failed to retrieve the OpenAPI document from '%s' - error = %s", openAPIDocumentFilename, err)}
+	}
+	return newSpecAnalyserV3FromDocument(openAPIDocumentFilename, apiSpec.Raw())
+}
+
+// newSpecAnalyserV3FromDocument creates an instance of specV3Analyser out of the already retrieved OpenAPI v3 document
+// (in JSON format)
+func newSpecAnalyserV3FromDocument(openAPIDocumentFilename string, document json.RawMessage) (*specV3Analyser, error) {
+	openAPIDocument := map[string]interface{}{}
+	if err := json.Unmarshal(document, &openAPIDocument); err != nil {
+		return nil, &SpecAnalysisError{Err: fmt.Errorf("failed to read the OpenAPI document from '%s' - error = %s", openAPIDocumentFilename, err)}
+	}
+	version := getOpenAPIv3DocumentVersion(openAPIDocument)
+	if !strings.HasPrefix(version, "3.0") && !strings.HasPrefix(version, "3.1") {
+		return nil, &SpecAnalysisError{Err: fmt.Errorf("OpenAPI document '%s' version '%s' not supported, the OpenAPI v3 spec analyser only supports 3.0.x and 3.1.x documents", openAPIDocumentFilename, version)}
+	}
+	openAPIDocument, _, err := resolveExternalRefs(openAPIDocumentFilename, openAPIDocument, openAPIv3SchemasPath)
+	if err != nil {
+		return nil, &SpecAnalysisError{Err: fmt.Errorf("failed to resolve the external references of the OpenAPI document from '%s' - error = %s", openAPIDocumentFilename, err)}
+	}
+	swaggerDocument, err := newOpenAPIv3DocumentConverter(openAPIDocument).convert()
+	if err != nil {
+		return nil, &SpecAnalysisError{Err: fmt.Errorf("failed to convert the OpenAPI document from '%s' - error = %s", openAPIDocumentFilename, err)}
+	}
+	swaggerJSON, err := json.Marshal(swaggerDocument)
+	if err != nil {
+		return nil, &SpecAnalysisError{Err: fmt.Errorf("failed to convert the OpenAPI document from '%s' - error = %s", openAPIDocumentFilename, err)}
+	}
+	apiSpec, err := loads.Analyzed(swaggerJSON, "")
+	if err != nil {
+		return nil, &SpecAnalysisError{Err: fmt.Errorf("failed to analyse the OpenAPI document from '%s' - error = %s", openAPIDocumentFilename, err)}
+	}
+	logExtensionsLintIssues(apiSpec.Spec())
+	apiSpec, err = apiSpec.Expanded()
+	if err != nil {
+		return nil, &SpecAnalysisError{Err: fmt.Errorf("failed to expand the OpenAPI document from '%s' - error = %s", openAPIDocumentFilename, err)}
+	}
+	return &specV3Analyser{
+		specV2Analyser: &specV2Analyser{
+			d:                  apiSpec,
+			openAPIDocumentURL: openAPIDocumentFilename,
+			documentHash:       getOpenAPIDocumentHash(document),
+		},
+	}, nil
+}
+
+// isOpenAPIv3Document returns true if the given document (in JSON format) declares the 'openapi' version field
+func isOpenAPIv3Document(document json.RawMessage) bool {
+	openAPIDocument := map[string]interface{}{}
+	if err := json.Unmarshal(document, &openAPIDocument); err != nil {
+		return false
+	}
+	return getOpenAPIv3DocumentVersion(openAPIDocument) != ""
+}
+
+// getOpenAPIv3DocumentVersion returns the value of the 'openapi' field of the document, which is only present in OpenAPI
+// v3 documents (OpenAPI v2 documents declare the 'swagger' field instead). An empty string is returned if not present
+func getOpenAPIv3DocumentVersion(openAPIDocument map[string]interface{}) string {
+	version, _ := openAPIDocument["openapi"].(string)
+	return version
+}
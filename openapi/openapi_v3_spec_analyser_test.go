@@ -0,0 +1,602 @@
+package openapi
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const openAPIv3Document = `openapi: "3.0.1"
+info:
+  title: "Dummy Service Provider"
+  version: "1.0.0"
+servers:
+  - url: "{scheme}://{environment}.api.com/api/"
+    variables:
+      scheme:
+        default: "https"
+      environment:
+        default: "dev"
+  - url: "http://dev.api.com/api"
+  - url: "https://other.api.com"
+security:
+  - apikey_auth: []
+paths:
+  /v1/cdns:
+    post:
+      parameters:
+        - $ref: "#/components/parameters/XRequestID"
+      requestBody:
+        $ref: "#/components/requestBodies/ContentDeliveryNetwork"
+      responses:
+        201:
+          description: "successful operation"
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/ContentDeliveryNetworkV1"
+  /v1/cdns/{id}:
+    get:
+      parameters:
+        - name: "id"
+          in: "path"
+          required: true
+          schema:
+            type: "string"
+        - name: "session"
+          in: "cookie"
+          schema:
+            type: "string"
+      responses:
+        200:
+          description: "successful operation"
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/ContentDeliveryNetworkV1"
+    delete:
+      parameters:
+        - name: "id"
+          in: "path"
+          required: true
+          schema:
+            type: "string"
+      responses:
+        204:
+          description: "successful operation"
+components:
+  parameters:
+    XRequestID:
+      name: "X-Request-ID"
+      in: "header"
+      required: true
+      schema:
+        type: "string"
+  requestBodies:
+    ContentDeliveryNetwork:
+      required: true
+      content:
+        application/json:
+          schema:
+            $ref: "#/components/schemas/ContentDeliveryNetworkV1"
+  schemas:
+    ContentDeliveryNetworkV1:
+      type: "object"
+      required:
+        - label
+      properties:
+        id:
+          type: "string"
+          readOnly: true
+        label:
+          type: "string"
+        description:
+          type: "string"
+          nullable: true
+  securitySchemes:
+    apikey_auth:
+      type: "apiKey"
+      name: "Authorization"
+      in: "header"
+    cookie_auth:
+      type: "apiKey"
+      name: "session"
+      in: "cookie"`
+
+func TestNewSpecAnalyserV3(t *testing.T) {
+	Convey("Given an OpenAPI v3 document containing servers, components and request bodies", t, func() {
+		file := initAPISpecFile(openAPIv3Document)
+		defer os.Remove(file.Name())
+		Convey("When newSpecAnalyserV3 method is called", func() {
+			specAnalyser, err := newSpecAnalyserV3(file.Name())
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the terraform compliant resources should be the ones described in the paths of the document", func() {
+				resources, err := specAnalyser.GetTerraformCompliantResources()
+				So(err, ShouldBeNil)
+				So(resources, ShouldHaveLength, 1)
+				So(resources[0].getResourceName(), ShouldEqual, "cdns_v1")
+			})
+			Convey("And the schema of the resources should be the component schema referenced in the request body", func() {
+				resources, _ := specAnalyser.GetTerraformCompliantResources()
+				resourceSchema, err := resources[0].getResourceSchema()
+				So(err, ShouldBeNil)
+				label, err := resourceSchema.getProperty("label")
+				So(err, ShouldBeNil)
+				So(label.Required, ShouldBeTrue)
+				id, err := resourceSchema.getProperty("id")
+				So(err, ShouldBeNil)
+				So(id.ReadOnly, ShouldBeTrue)
+				_, err = resourceSchema.getProperty("description")
+				So(err, ShouldBeNil)
+			})
+			Convey("And the backend configuration should be the one of the first server with the server variables replaced by their defaults", func() {
+				backendConfiguration, err := specAnalyser.GetAPIBackendConfiguration()
+				So(err, ShouldBeNil)
+				host, err := backendConfiguration.getHost()
+				So(err, ShouldBeNil)
+				So(host, ShouldEqual, "dev.api.com")
+				So(backendConfiguration.getBasePath(), ShouldEqual, "/api")
+				scheme, err := backendConfiguration.getHTTPScheme()
+				So(err, ShouldBeNil)
+				So(scheme, ShouldEqual, "https")
+			})
+			Convey("And the header parameters referenced from the components should be available", func() {
+				headers, err := specAnalyser.GetAllHeaderParameters()
+				So(err, ShouldBeNil)
+				So(headers, ShouldHaveLength, 1)
+				So(headers[0].Name, ShouldEqual, "X-Request-ID")
+			})
+			Convey("And the supported security schemes should be translated into security definitions", func() {
+				securityDefinitions, err := specAnalyser.GetSecurity().GetAPIKeySecurityDefinitions()
+				So(err, ShouldBeNil)
+				So(*securityDefinitions, ShouldHaveLength, 2)
+				cookieAuth := securityDefinitions.findSecurityDefinitionFor("cookie_auth")
+				So(cookieAuth, ShouldNotBeNil)
+				So(cookieAuth.getAPIKey().In, ShouldEqual, inCookie)
+				So(cookieAuth.getAPIKey().Name, ShouldEqual, "session")
+				So(securityDefinitions.findSecurityDefinitionFor("apikey_auth"), ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given an OpenAPI v3.2 document", t, func() {
+		file := initAPISpecFile(`openapi: "3.2.0"
+info:
+  title: "Dummy Service Provider"
+  version: "1.0.0"
+paths: {}`)
+		defer os.Remove(file.Name())
+		Convey("When newSpecAnalyserV3 method is called", func() {
+			_, err := newSpecAnalyserV3(file.Name())
+			Convey("Then the error returned should be a SpecAnalysisError explaining the version is not supported", func() {
+				So(err, ShouldHaveSameTypeAs, &SpecAnalysisError{})
+				So(err.Error(), ShouldEqual, "OpenAPI document '"+file.Name()+"' version '3.2.0' not supported, the OpenAPI v3 spec analyser only supports 3.0.x and 3.1.x documents")
+			})
+		})
+	})
+
+	Convey("Given an empty OpenAPI document url", t, func() {
+		Convey("When newSpecAnalyserV3 method is called", func() {
+			_, err := newSpecAnalyserV3("")
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "open api document filename argument empty, please provide the url of the OpenAPI document")
+			})
+		})
+	})
+}
+
+func TestCreateSpecAnalyserForDocument(t *testing.T) {
+	Convey("Given an OpenAPI v2 document", t, func() {
+		file := initAPISpecFile(`swagger: "2.0"`)
+		defer os.Remove(file.Name())
+		Convey("When createSpecAnalyserForDocument method is called", func() {
+			specAnalyser, err := createSpecAnalyserForDocument(file.Name())
+			Convey("Then the specAnalyser returned should be of type specV2Analyser", func() {
+				So(err, ShouldBeNil)
+				So(specAnalyser, ShouldHaveSameTypeAs, &specV2Analyser{})
+			})
+		})
+	})
+	Convey("Given an OpenAPI v3 document", t, func() {
+		file := initAPISpecFile(openAPIv3Document)
+		defer os.Remove(file.Name())
+		Convey("When createSpecAnalyserForDocument method is called", func() {
+			specAnalyser, err := createSpecAnalyserForDocument(file.Name())
+			Convey("Then the specAnalyser returned should be of type specV3Analyser", func() {
+				So(err, ShouldBeNil)
+				So(specAnalyser, ShouldHaveSameTypeAs, &specV3Analyser{})
+			})
+		})
+	})
+	Convey("Given a non valid OpenAPI document url", t, func() {
+		Convey("When createSpecAnalyserForDocument method is called", func() {
+			_, err := createSpecAnalyserForDocument("some non valid spec file")
+			Convey("Then the error should be a SpecFetchError containing the URL of the document", func() {
+				So(err, ShouldHaveSameTypeAs, &SpecFetchError{})
+				So(err.(*SpecFetchError).URL, ShouldEqual, "some non valid spec file")
+			})
+		})
+	})
+}
+
+func TestOpenAPIv3DocumentConverter(t *testing.T) {
+	Convey("Given an OpenAPI v3 document with a relative server url", t, func() {
+		converter := newOpenAPIv3DocumentConverter(map[string]interface{}{
+			"servers": []interface{}{
+				map[string]interface{}{"url": "/api/v1"},
+			},
+		})
+		Convey("When convert method is called", func() {
+			swagger, err := converter.convert()
+			Convey("Then the base path should be the server url and the host and schemes should not be set", func() {
+				So(err, ShouldBeNil)
+				So(swagger["basePath"], ShouldEqual, "/api/v1")
+				So(swagger, ShouldNotContainKey, "host")
+				So(swagger, ShouldNotContainKey, "schemes")
+			})
+		})
+	})
+	Convey("Given an OpenAPI v3 document with servers that only differ in the scheme", t, func() {
+		converter := newOpenAPIv3DocumentConverter(map[string]interface{}{
+			"servers": []interface{}{
+				map[string]interface{}{"url": "https://api.com"},
+				map[string]interface{}{"url": "http://api.com/"},
+			},
+		})
+		Convey("When convert method is called", func() {
+			swagger, err := converter.convert()
+			Convey("Then the schemes should contain the schemes of both servers", func() {
+				So(err, ShouldBeNil)
+				So(swagger["host"], ShouldEqual, "api.com")
+				So(swagger["schemes"], ShouldResemble, []string{"https", "http"})
+			})
+		})
+	})
+	Convey("Given an OpenAPI v3 document with an operation containing a request body with several media types and a nullable schema", t, func() {
+		converter := newOpenAPIv3DocumentConverter(map[string]interface{}{
+			"paths": map[string]interface{}{
+				"/v1/cdns": map[string]interface{}{
+					"x-terraform-resource-name": "cdn",
+					"post": map[string]interface{}{
+						"x-terraform-resource-timeout": "30s",
+						"requestBody": map[string]interface{}{
+							"required": true,
+							"content": map[string]interface{}{
+								"application/xml": map[string]interface{}{
+									"schema": map[string]interface{}{"type": "string"},
+								},
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{"$ref": "#/components/schemas/CDN", "nullable": true},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+		Convey("When convert method is called", func() {
+			swagger, err := converter.convert()
+			So(err, ShouldBeNil)
+			pathItem := swagger["paths"].(map[string]interface{})["/v1/cdns"].(map[string]interface{})
+			operation := pathItem["post"].(map[string]interface{})
+			Convey("Then the request body should be translated into a body parameter using the JSON media type schema", func() {
+				So(operation["parameters"], ShouldResemble, []interface{}{
+					map[string]interface{}{
+						"name":     "body",
+						"in":       "body",
+						"required": true,
+						"schema":   map[string]interface{}{"$ref": "#/definitions/CDN", "x-nullable": true},
+					},
+				})
+				So(operation["consumes"], ShouldResemble, []interface{}{"application/json"})
+			})
+			Convey("And the extensions of the path item and the operation should be kept", func() {
+				So(pathItem["x-terraform-resource-name"], ShouldEqual, "cdn")
+				So(operation["x-terraform-resource-timeout"], ShouldEqual, "30s")
+			})
+		})
+	})
+	Convey("Given an OpenAPI v3 document with an http bearer security scheme required globally and by an operation", t, func() {
+		converter := newOpenAPIv3DocumentConverter(map[string]interface{}{
+			"security": []interface{}{
+				map[string]interface{}{"bearerAuth": []interface{}{}},
+			},
+			"paths": map[string]interface{}{
+				"/v1/cdns": map[string]interface{}{
+					"get": map[string]interface{}{
+						"security": []interface{}{
+							map[string]interface{}{"bearerAuth": []interface{}{"read"}, "apikey_auth": []interface{}{}},
+						},
+					},
+				},
+			},
+			"components": map[string]interface{}{
+				"securitySchemes": map[string]interface{}{
+					"bearerAuth":  map[string]interface{}{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+					"apikey_auth": map[string]interface{}{"type": "apiKey", "name": "X-API-Key", "in": "header"},
+				},
+			},
+		})
+		Convey("When convert method is called", func() {
+			swagger, err := converter.convert()
+			So(err, ShouldBeNil)
+			Convey("Then the security scheme should be translated into a 'token' apiKey security definition sent in the Authorization header using the Bearer scheme", func() {
+				securityDefinitions := swagger["securityDefinitions"].(map[string]interface{})
+				So(securityDefinitions, ShouldNotContainKey, "bearerAuth")
+				So(securityDefinitions["token"], ShouldResemble, map[string]interface{}{
+					"type":                          "apiKey",
+					"in":                            "header",
+					"name":                          "Authorization",
+					extTfAuthenticationSchemeBearer: true,
+				})
+				So(securityDefinitions, ShouldContainKey, "apikey_auth")
+			})
+			Convey("And the security requirements should refer to the 'token' security definition", func() {
+				So(swagger["security"], ShouldResemble, []interface{}{
+					map[string]interface{}{"token": []interface{}{}},
+				})
+				operation := swagger["paths"].(map[string]interface{})["/v1/cdns"].(map[string]interface{})["get"].(map[string]interface{})
+				So(operation["security"], ShouldResemble, []interface{}{
+					map[string]interface{}{"token": []interface{}{"read"}, "apikey_auth": []interface{}{}},
+				})
+			})
+		})
+	})
+	Convey("Given an OpenAPI v3 document with several http bearer security schemes", t, func() {
+		converter := newOpenAPIv3DocumentConverter(map[string]interface{}{
+			"components": map[string]interface{}{
+				"securitySchemes": map[string]interface{}{
+					"user_token":    map[string]interface{}{"type": "http", "scheme": "Bearer"},
+					"service_token": map[string]interface{}{"type": "http", "scheme": "bearer"},
+				},
+			},
+		})
+		Convey("When convert method is called", func() {
+			swagger, err := converter.convert()
+			Convey("Then the security definitions should keep the names of the security schemes so they do not collide", func() {
+				So(err, ShouldBeNil)
+				securityDefinitions := swagger["securityDefinitions"].(map[string]interface{})
+				So(securityDefinitions, ShouldHaveLength, 2)
+				So(securityDefinitions, ShouldContainKey, "user_token")
+				So(securityDefinitions, ShouldContainKey, "service_token")
+			})
+		})
+	})
+}
+
+func TestNewSpecAnalyserV3_HTTPBearer(t *testing.T) {
+	Convey("Given an OpenAPI v3 document with an http bearer security scheme required globally", t, func() {
+		file := initAPISpecFile(`openapi: "3.0.1"
+info:
+  title: "Dummy Service Provider"
+  version: "1.0.0"
+security:
+  - bearerAuth: []
+paths: {}
+components:
+  securitySchemes:
+    bearerAuth:
+      type: "http"
+      scheme: "bearer"`)
+		defer os.Remove(file.Name())
+		Convey("When newSpecAnalyserV3 method is called", func() {
+			specAnalyser, err := newSpecAnalyserV3(file.Name())
+			So(err, ShouldBeNil)
+			Convey("Then the security definitions should contain a bearer security definition exposed as the 'token' provider property", func() {
+				securityDefinitions, err := specAnalyser.GetSecurity().GetAPIKeySecurityDefinitions()
+				So(err, ShouldBeNil)
+				So(*securityDefinitions, ShouldHaveLength, 1)
+				securityDefinition := (*securityDefinitions)[0]
+				So(securityDefinition.getTerraformConfigurationName(), ShouldEqual, "token")
+				So(securityDefinition.getAPIKey().Name, ShouldEqual, "Authorization")
+				So(securityDefinition.buildValue("someToken"), ShouldEqual, "Bearer someToken")
+			})
+			Convey("And the global security schemes should require the 'token' security definition", func() {
+				globalSecuritySchemes, err := specAnalyser.GetSecurity().GetGlobalSecuritySchemes()
+				So(err, ShouldBeNil)
+				So(globalSecuritySchemes, ShouldResemble, SpecSecuritySchemes{{Name: "token"}})
+			})
+		})
+	})
+}
+
+func TestNewSpecAnalyserV3_HTTPBasic(t *testing.T) {
+	Convey("Given an OpenAPI v3 document with an http basic security scheme required globally", t, func() {
+		file := initAPISpecFile(`openapi: "3.0.1"
+info:
+  title: "Dummy Service Provider"
+  version: "1.0.0"
+security:
+  - basicAuth: []
+paths: {}
+components:
+  securitySchemes:
+    basicAuth:
+      type: "http"
+      scheme: "basic"`)
+		defer os.Remove(file.Name())
+		Convey("When newSpecAnalyserV3 method is called", func() {
+			specAnalyser, err := newSpecAnalyserV3(file.Name())
+			So(err, ShouldBeNil)
+			Convey("Then the security definitions should contain a basic auth security definition", func() {
+				securityDefinitions, err := specAnalyser.GetSecurity().GetAPIKeySecurityDefinitions()
+				So(err, ShouldBeNil)
+				So(*securityDefinitions, ShouldHaveLength, 1)
+				securityDefinition := (*securityDefinitions)[0]
+				So(securityDefinition, ShouldHaveSameTypeAs, specBasicAuthSecurityDefinition{})
+				So(getSecurityDefinitionPropertyNames(securityDefinition), ShouldResemble, []string{"basic_auth_username", "basic_auth_password"})
+			})
+			Convey("And the global security schemes should require the basic auth security definition", func() {
+				globalSecuritySchemes, err := specAnalyser.GetSecurity().GetGlobalSecuritySchemes()
+				So(err, ShouldBeNil)
+				So(globalSecuritySchemes, ShouldResemble, SpecSecuritySchemes{{Name: "basicAuth"}})
+			})
+		})
+	})
+}
+
+func TestOpenAPIv3DocumentConverter_JSONSchema202012(t *testing.T) {
+	Convey("Given an OpenAPI v3.1 document with schemas using JSON Schema draft 2020-12 constructs", t, func() {
+		converter := newOpenAPIv3DocumentConverter(map[string]interface{}{
+			"components": map[string]interface{}{
+				"schemas": map[string]interface{}{
+					"CDN": map[string]interface{}{
+						"type":                  "object",
+						"unevaluatedProperties": false,
+						"properties": map[string]interface{}{
+							"label":    map[string]interface{}{"type": []interface{}{"string", "null"}},
+							"kind":     map[string]interface{}{"type": "string", "const": "cdn"},
+							"replicas": map[string]interface{}{"type": "integer", "exclusiveMinimum": float64(0), "exclusiveMaximum": float64(10)},
+							"tags":     map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": []interface{}{"string"}}, "examples": []interface{}{"a"}},
+						},
+					},
+				},
+			},
+		})
+		Convey("When convert method is called", func() {
+			swagger, err := converter.convert()
+			So(err, ShouldBeNil)
+			definition := swagger["definitions"].(map[string]interface{})["CDN"].(map[string]interface{})
+			properties := definition["properties"].(map[string]interface{})
+			Convey("Then type arrays containing null should be translated into the non null type flagged as nullable", func() {
+				So(properties["label"], ShouldResemble, map[string]interface{}{"type": "string", "x-nullable": true})
+			})
+			Convey("And const should be translated into an enum with one value", func() {
+				So(properties["kind"], ShouldResemble, map[string]interface{}{"type": "string", "enum": []interface{}{"cdn"}})
+			})
+			Convey("And numeric exclusive limits should be translated into the limits along with the boolean exclusive fields", func() {
+				So(properties["replicas"], ShouldResemble, map[string]interface{}{"type": "integer", "minimum": float64(0), "exclusiveMinimum": true, "maximum": float64(10), "exclusiveMaximum": true})
+			})
+			Convey("And the nested schemas should be translated too", func() {
+				So(properties["tags"], ShouldResemble, map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}})
+			})
+			Convey("And unevaluatedProperties should be translated into additionalProperties", func() {
+				So(definition["additionalProperties"], ShouldEqual, false)
+				So(definition, ShouldNotContainKey, "unevaluatedProperties")
+			})
+		})
+	})
+	Convey("Given an OpenAPI v3.1 document with a schema property that has several non null types", t, func() {
+		converter := newOpenAPIv3DocumentConverter(map[string]interface{}{
+			"components": map[string]interface{}{
+				"schemas": map[string]interface{}{
+					"CDN": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"port": map[string]interface{}{"type": []interface{}{"string", "integer"}},
+						},
+					},
+				},
+			},
+		})
+		Convey("When convert method is called", func() {
+			_, err := converter.convert()
+			Convey("Then the error returned should explain the type is not supported", func() {
+				So(err.Error(), ShouldEqual, "schema 'CDN' is not valid: property 'port' is not valid: type '[string integer]' not supported, only one type (optionally along with 'null') is supported")
+			})
+		})
+	})
+	Convey("Given an OpenAPI v3.1 document with an operation parameter that has a type array", t, func() {
+		converter := newOpenAPIv3DocumentConverter(map[string]interface{}{
+			"paths": map[string]interface{}{
+				"/v1/cdns": map[string]interface{}{
+					"get": map[string]interface{}{
+						"parameters": []interface{}{
+							map[string]interface{}{"name": "X-Request-ID", "in": "header", "schema": map[string]interface{}{"type": []interface{}{"null", "string"}}},
+						},
+					},
+				},
+			},
+		})
+		Convey("When convert method is called", func() {
+			swagger, err := converter.convert()
+			So(err, ShouldBeNil)
+			operation := swagger["paths"].(map[string]interface{})["/v1/cdns"].(map[string]interface{})["get"].(map[string]interface{})
+			Convey("Then the parameter type should be the non null type", func() {
+				So(operation["parameters"], ShouldResemble, []interface{}{map[string]interface{}{"name": "X-Request-ID", "in": "header", "type": "string"}})
+			})
+		})
+	})
+}
+
+func TestNewSpecAnalyserV3_OpenAPIv31(t *testing.T) {
+	Convey("Given an OpenAPI v3.1 document", t, func() {
+		file := initAPISpecFile(`openapi: "3.1.0"
+info:
+  title: "Dummy Service Provider"
+  version: "1.0.0"
+servers:
+  - url: "https://www.api.com"
+paths:
+  /v1/cdns:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/ContentDeliveryNetworkV1"
+      responses:
+        201:
+          description: "successful operation"
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/ContentDeliveryNetworkV1"
+  /v1/cdns/{id}:
+    get:
+      parameters:
+        - name: "id"
+          in: "path"
+          required: true
+          schema:
+            type: "string"
+      responses:
+        200:
+          description: "successful operation"
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/ContentDeliveryNetworkV1"
+webhooks:
+  cdnCreated:
+    post:
+      responses:
+        200:
+          description: "ok"
+components:
+  schemas:
+    ContentDeliveryNetworkV1:
+      type: "object"
+      unevaluatedProperties: false
+      properties:
+        id:
+          type: "string"
+          readOnly: true
+        label:
+          type: ["string", "null"]
+        tier:
+          type: "string"
+          const: "premium"`)
+		defer os.Remove(file.Name())
+		Convey("When newSpecAnalyserV3 method is called", func() {
+			specAnalyser, err := newSpecAnalyserV3(file.Name())
+			So(err, ShouldBeNil)
+			Convey("Then the resources schema should map the JSON Schema 2020-12 constructs into terraform compatible properties", func() {
+				resources, err := specAnalyser.GetTerraformCompliantResources()
+				So(err, ShouldBeNil)
+				So(resources, ShouldHaveLength, 1)
+				resourceSchema, err := resources[0].getResourceSchema()
+				So(err, ShouldBeNil)
+				label, err := resourceSchema.getProperty("label")
+				So(err, ShouldBeNil)
+				So(label.Type, ShouldEqual, typeString)
+				tier, err := resourceSchema.getProperty("tier")
+				So(err, ShouldBeNil)
+				So(tier.Enum, ShouldResemble, []interface{}{"premium"})
+			})
+		})
+	})
+}
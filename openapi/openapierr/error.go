@@ -31,3 +31,9 @@ func (e *NotFoundError) Error() string {
 func (e *NotFoundError) Code() string {
 	return NotFound
 }
+
+// Unwrap returns the original error so callers can inspect it with errors.As (e.g. the *openapi.APIError with the
+// details of the 404 response)
+func (e *NotFoundError) Unwrap() error {
+	return e.OriginalError
+}
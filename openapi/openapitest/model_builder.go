@@ -0,0 +1,110 @@
+package openapitest
+
+import (
+	"github.com/go-openapi/spec"
+)
+
+// Model builds the schema of a definition (e.g. the payload of a resource)
+type Model struct {
+	schema spec.Schema
+}
+
+// NewModel returns a Model of type object with no properties
+func NewModel() *Model {
+	return &Model{schema: *new(spec.Schema).Typed("object", "")}
+}
+
+// WithProperty adds the given optional property to the model
+func (m *Model) WithProperty(name string, property *Property) *Model {
+	m.schema.SetProperty(name, property.schema)
+	return m
+}
+
+// WithRequiredProperty adds the given property to the model and marks it as required
+func (m *Model) WithRequiredProperty(name string, property *Property) *Model {
+	m.schema.SetProperty(name, property.schema)
+	m.schema.Required = append(m.schema.Required, name)
+	return m
+}
+
+// WithExtension adds the given extension to the model
+func (m *Model) WithExtension(name string, value interface{}) *Model {
+	m.schema.AddExtension(name, value)
+	return m
+}
+
+// Property builds the schema of a model property. The methods return the Property itself so calls can be chained (e.g.
+// StringProperty().ReadOnly().WithExtension("x-terraform-field-name", "label"))
+type Property struct {
+	schema spec.Schema
+}
+
+// StringProperty returns a property of type string
+func StringProperty() *Property {
+	return &Property{schema: *spec.StringProperty()}
+}
+
+// IntegerProperty returns a property of type integer
+func IntegerProperty() *Property {
+	return &Property{schema: *new(spec.Schema).Typed("integer", "")}
+}
+
+// NumberProperty returns a property of type number
+func NumberProperty() *Property {
+	return &Property{schema: *new(spec.Schema).Typed("number", "")}
+}
+
+// BooleanProperty returns a property of type boolean
+func BooleanProperty() *Property {
+	return &Property{schema: *spec.BoolProperty()}
+}
+
+// ObjectProperty returns a property of type object with the properties of the given model
+func ObjectProperty(model *Model) *Property {
+	return &Property{schema: model.schema}
+}
+
+// ArrayProperty returns a property of type array with items of the given type
+func ArrayProperty(items *Property) *Property {
+	itemsSchema := items.schema
+	return &Property{schema: *spec.ArrayProperty(&itemsSchema)}
+}
+
+// RefProperty returns a property referring to the given definition
+func RefProperty(definitionName string) *Property {
+	return &Property{schema: *spec.RefSchema(definitionRef(definitionName))}
+}
+
+// ReadOnly marks the property as readOnly, so it is computed by the API
+func (p *Property) ReadOnly() *Property {
+	p.schema.ReadOnly = true
+	return p
+}
+
+// WithDefault sets the default value of the property
+func (p *Property) WithDefault(value interface{}) *Property {
+	p.schema.Default = value
+	return p
+}
+
+// WithEnum sets the values allowed for the property
+func (p *Property) WithEnum(values ...interface{}) *Property {
+	p.schema.Enum = values
+	return p
+}
+
+// WithDescription sets the description of the property
+func (p *Property) WithDescription(description string) *Property {
+	p.schema.Description = description
+	return p
+}
+
+// WithExtension adds the given extension (e.g. x-terraform-immutable) to the property
+func (p *Property) WithExtension(name string, value interface{}) *Property {
+	p.schema.AddExtension(name, value)
+	return p
+}
+
+func definitionRef(definitionName string) string {
+	return "#/definitions/" + definitionName
+}
@@ -0,0 +1,143 @@
+package openapitest
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/spec"
+)
+
+// ResourceOperation defines the operations of a resource
+type ResourceOperation string
+
+const (
+	// ResourceOperationCreate defines the POST operation of the resource root path (e.g. POST /v1/cdns)
+	ResourceOperationCreate ResourceOperation = "create"
+	// ResourceOperationRead defines the GET operation of the resource instance path (e.g. GET /v1/cdns/{id})
+	ResourceOperationRead ResourceOperation = "read"
+	// ResourceOperationUpdate defines the PUT operation of the resource instance path (e.g. PUT /v1/cdns/{id})
+	ResourceOperationUpdate ResourceOperation = "update"
+	// ResourceOperationDelete defines the DELETE operation of the resource instance path (e.g. DELETE /v1/cdns/{id})
+	ResourceOperationDelete ResourceOperation = "delete"
+	// ResourceOperationList defines the GET operation of the resource root path (e.g. GET /v1/cdns), only present if
+	// the resource is built WithList
+	ResourceOperationList ResourceOperation = "list"
+)
+
+// Resource builds the paths of a terraform compliant resource: the root path (e.g. /v1/cdns) with the POST operation and
+// the instance path (e.g. /v1/cdns/{id}) with the GET, PUT and DELETE operations. The request and response payloads of
+// the operations refer to the given definition
+type Resource struct {
+	path           string
+	definitionName string
+	operations     map[ResourceOperation]*spec.Operation
+}
+
+// NewResource returns a Resource with the create, read, update and delete operations for the given path, using the given
+// definition as payload
+func NewResource(path, definitionName string) *Resource {
+	ref := spec.RefSchema(definitionRef(definitionName))
+	return &Resource{
+		path:           path,
+		definitionName: definitionName,
+		operations: map[ResourceOperation]*spec.Operation{
+			ResourceOperationCreate: spec.NewOperation("").
+				AddParam(spec.BodyParam("body", ref).AsRequired()).
+				RespondsWith(http.StatusCreated, spec.NewResponse().WithDescription("resource created").WithSchema(ref)),
+			ResourceOperationRead: spec.NewOperation("").
+				AddParam(newIDPathParam()).
+				RespondsWith(http.StatusOK, spec.NewResponse().WithDescription("resource found").WithSchema(ref)),
+			ResourceOperationUpdate: spec.NewOperation("").
+				AddParam(newIDPathParam()).
+				AddParam(spec.BodyParam("body", ref).AsRequired()).
+				RespondsWith(http.StatusOK, spec.NewResponse().WithDescription("resource updated").WithSchema(ref)),
+			ResourceOperationDelete: spec.NewOperation("").
+				AddParam(newIDPathParam()).
+				RespondsWith(http.StatusNoContent, spec.NewResponse().WithDescription("resource deleted")),
+		},
+	}
+}
+
+// WithList adds the GET operation to the resource root path, returning the list of resource instances (e.g. so the
+// data source of the resource is exposed too)
+func (r *Resource) WithList() *Resource {
+	items := spec.ArrayProperty(spec.RefSchema(definitionRef(r.definitionName)))
+	r.operations[ResourceOperationList] = spec.NewOperation("").
+		RespondsWith(http.StatusOK, spec.NewResponse().WithDescription("resources found").WithSchema(items))
+	return r
+}
+
+// WithoutOperation removes the given operation from the resource (e.g. the update operation for resources that can not
+// be updated)
+func (r *Resource) WithoutOperation(operation ResourceOperation) *Resource {
+	delete(r.operations, operation)
+	return r
+}
+
+// WithOperationExtension adds the given extension (e.g. x-terraform-resource-name) to the given operation. Nothing is
+// done if the resource does not have the operation
+func (r *Resource) WithOperationExtension(operation ResourceOperation, name string, value interface{}) *Resource {
+	if op, exists := r.operations[operation]; exists {
+		op.AddExtension(name, value)
+	}
+	return r
+}
+
+// WithResponseExtension adds the given extension (e.g. x-terraform-resource-poll-enabled) to the response of the given
+// operation with the status code provided, adding the response if the operation does not document it yet. Nothing is
+// done if the resource does not have the operation
+func (r *Resource) WithResponseExtension(operation ResourceOperation, statusCode int, name string, value interface{}) *Resource {
+	op, exists := r.operations[operation]
+	if !exists {
+		return r
+	}
+	response, exists := op.Responses.StatusCodeResponses[statusCode]
+	if !exists {
+		response = *spec.NewResponse().WithDescription(http.StatusText(statusCode))
+	}
+	response.AddExtension(name, value)
+	op.Responses.StatusCodeResponses[statusCode] = response
+	return r
+}
+
+// WithHeaderParameter adds a header parameter of type string to the given operation. Nothing is done if the resource
+// does not have the operation
+func (r *Resource) WithHeaderParameter(operation ResourceOperation, name string, required bool) *Resource {
+	if op, exists := r.operations[operation]; exists {
+		param := spec.HeaderParam(name).Typed("string", "")
+		param.Required = required
+		op.AddParam(param)
+	}
+	return r
+}
+
+// WithOperationSecurity sets the security requirement of the given operation, which is made of the given security
+// definitions and overrides the global security. If no security definitions are given, the operation is declared with
+// an empty security so it is called without authentication. Nothing is done if the resource does not have the operation
+func (r *Resource) WithOperationSecurity(operation ResourceOperation, securityDefinitions ...string) *Resource {
+	if op, exists := r.operations[operation]; exists {
+		op.Security = []map[string][]string{}
+		if len(securityDefinitions) > 0 {
+			op.Security = append(op.Security, newSecurityRequirement(securityDefinitions))
+		}
+	}
+	return r
+}
+
+// pathItems returns the path items of the resource keyed by path
+func (r *Resource) pathItems() map[string]spec.PathItem {
+	root := spec.PathItem{}
+	root.Post = r.operations[ResourceOperationCreate]
+	root.Get = r.operations[ResourceOperationList]
+	instance := spec.PathItem{}
+	instance.Get = r.operations[ResourceOperationRead]
+	instance.Put = r.operations[ResourceOperationUpdate]
+	instance.Delete = r.operations[ResourceOperationDelete]
+	return map[string]spec.PathItem{
+		r.path:           root,
+		r.path + "/{id}": instance,
+	}
+}
+
+func newIDPathParam() *spec.Parameter {
+	return spec.PathParam("id").Typed("string", "")
+}
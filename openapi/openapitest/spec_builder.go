@@ -0,0 +1,156 @@
+// Package openapitest provides helpers to build swagger (OpenAPI 2.0) documents programmatically and serve them, so the
+// integrations with the provider (e.g. binaries embedding it or custom extensions) can be tested without maintaining
+// large swagger documents as string literals.
+package openapitest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-openapi/spec"
+)
+
+// specPath is the path where the Server exposes the swagger document
+const specPath = "/swagger.json"
+
+// Spec builds a swagger (OpenAPI 2.0) document. The methods return the Spec itself so calls can be chained:
+//
+//	doc := openapitest.NewSpec().
+//		WithHost(apiHost).
+//		WithDefinition("ContentDeliveryNetworkV1", openapitest.NewModel().
+//			WithProperty("id", openapitest.StringProperty().ReadOnly()).
+//			WithRequiredProperty("label", openapitest.StringProperty())).
+//		WithResource(openapitest.NewResource("/v1/cdns", "ContentDeliveryNetworkV1"))
+type Spec struct {
+	swagger *spec.Swagger
+}
+
+// NewSpec returns a Spec containing a swagger document with no paths, which is served over http unless configured otherwise
+func NewSpec() *Spec {
+	return &Spec{
+		swagger: &spec.Swagger{
+			SwaggerProps: spec.SwaggerProps{
+				Swagger: "2.0",
+				Info: &spec.Info{
+					InfoProps: spec.InfoProps{
+						Title:   "Test API",
+						Version: "1.0.0",
+					},
+				},
+				Schemes:             []string{"http"},
+				Paths:               &spec.Paths{Paths: map[string]spec.PathItem{}},
+				Definitions:         spec.Definitions{},
+				SecurityDefinitions: spec.SecurityDefinitions{},
+			},
+		},
+	}
+}
+
+// WithHost sets the host (e.g. 127.0.0.1:8080) the API calls are made to
+func (s *Spec) WithHost(host string) *Spec {
+	s.swagger.Host = host
+	return s
+}
+
+// WithBasePath sets the base path (e.g. /api) prepended to all the paths when making the API calls
+func (s *Spec) WithBasePath(basePath string) *Spec {
+	s.swagger.BasePath = basePath
+	return s
+}
+
+// WithSchemes sets the schemes (http, https) supported by the API
+func (s *Spec) WithSchemes(schemes ...string) *Spec {
+	s.swagger.Schemes = schemes
+	return s
+}
+
+// WithExtension adds the given extension (e.g. x-terraform-provider-multiregion-fqdn) to the root level of the document
+func (s *Spec) WithExtension(name string, value interface{}) *Spec {
+	s.swagger.AddExtension(name, value)
+	return s
+}
+
+// WithDefinition adds the given model to the definitions of the document, so it can be referenced by the resources
+func (s *Spec) WithDefinition(name string, model *Model) *Spec {
+	s.swagger.Definitions[name] = model.schema
+	return s
+}
+
+// WithSecurityDefinition adds the given security definition (e.g. spec.APIKeyAuth("Authorization", "header")) to the
+// document
+func (s *Spec) WithSecurityDefinition(name string, securityScheme *spec.SecurityScheme) *Spec {
+	s.swagger.SecurityDefinitions[name] = securityScheme
+	return s
+}
+
+// WithGlobalSecurity adds a global security requirement made of the given security definitions. Calling it several times
+// adds alternative requirements
+func (s *Spec) WithGlobalSecurity(securityDefinitions ...string) *Spec {
+	s.swagger.Security = append(s.swagger.Security, newSecurityRequirement(securityDefinitions))
+	return s
+}
+
+// WithResource adds the paths of the given resource to the document
+func (s *Spec) WithResource(resource *Resource) *Spec {
+	for path, pathItem := range resource.pathItems() {
+		s.swagger.Paths.Paths[path] = pathItem
+	}
+	return s
+}
+
+// WithPath adds the given path item to the document as is, for the cases not covered by the resources (e.g. paths not
+// compliant with terraform to check they are ignored)
+func (s *Spec) WithPath(path string, pathItem spec.PathItem) *Spec {
+	s.swagger.Paths.Paths[path] = pathItem
+	return s
+}
+
+// Swagger returns the swagger document built
+func (s *Spec) Swagger() *spec.Swagger {
+	return s.swagger
+}
+
+// JSON returns the swagger document built JSON encoded
+func (s *Spec) JSON() ([]byte, error) {
+	return json.Marshal(s.swagger)
+}
+
+// Serve starts a server exposing the swagger document built, which can be used as the swagger URL of the provider. The
+// document is encoded on every request, so changes made to the Spec afterwards are served too. The server must be closed
+// once done
+func (s *Spec) Serve() *Server {
+	return &Server{
+		Server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != specPath {
+				http.NotFound(w, r)
+				return
+			}
+			body, err := s.JSON()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(body)
+		})),
+	}
+}
+
+// Server is a test server exposing a swagger document
+type Server struct {
+	*httptest.Server
+}
+
+// SpecURL returns the URL of the swagger document exposed by the server
+func (s *Server) SpecURL() string {
+	return s.URL + specPath
+}
+
+func newSecurityRequirement(securityDefinitions []string) map[string][]string {
+	requirement := map[string][]string{}
+	for _, securityDefinition := range securityDefinitions {
+		requirement[securityDefinition] = []string{}
+	}
+	return requirement
+}
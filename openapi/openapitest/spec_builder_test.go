@@ -0,0 +1,116 @@
+package openapitest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/dikhan/terraform-provider-openapi/openapi"
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCDNSpec() *Spec {
+	return NewSpec().
+		WithHost("127.0.0.1:8080").
+		WithSecurityDefinition("apikey_auth", spec.APIKeyAuth("Authorization", "header")).
+		WithGlobalSecurity("apikey_auth").
+		WithDefinition("ContentDeliveryNetworkV1", NewModel().
+			WithProperty("id", StringProperty().ReadOnly()).
+			WithRequiredProperty("label", StringProperty().WithExtension("x-terraform-immutable", true)).
+			WithProperty("ips", ArrayProperty(StringProperty())).
+			WithProperty("size", IntegerProperty().WithDefault(1))).
+		WithResource(NewResource("/v1/cdns", "ContentDeliveryNetworkV1").
+			WithList().
+			WithOperationExtension(ResourceOperationCreate, "x-terraform-resource-name", "cdn").
+			WithResponseExtension(ResourceOperationCreate, http.StatusAccepted, "x-terraform-resource-poll-enabled", true).
+			WithHeaderParameter(ResourceOperationCreate, "X-Request-ID", true).
+			WithOperationSecurity(ResourceOperationRead))
+}
+
+func TestSpecJSON(t *testing.T) {
+	body, err := newCDNSpec().JSON()
+	require.NoError(t, err)
+	doc, err := loads.Analyzed(body, "")
+	require.NoError(t, err)
+	swagger := doc.Spec()
+
+	assert.Equal(t, "2.0", swagger.Swagger)
+	assert.Equal(t, "127.0.0.1:8080", swagger.Host)
+	assert.Equal(t, []string{"http"}, swagger.Schemes)
+	assert.Equal(t, []map[string][]string{{"apikey_auth": {}}}, swagger.Security)
+	require.Contains(t, swagger.SecurityDefinitions, "apikey_auth")
+	assert.Equal(t, "header", swagger.SecurityDefinitions["apikey_auth"].In)
+
+	require.Contains(t, swagger.Definitions, "ContentDeliveryNetworkV1")
+	definition := swagger.Definitions["ContentDeliveryNetworkV1"]
+	assert.Equal(t, []string{"label"}, definition.Required)
+	assert.True(t, definition.Properties["id"].ReadOnly)
+	assert.Equal(t, true, definition.Properties["label"].Extensions["x-terraform-immutable"])
+	assert.True(t, definition.Properties["ips"].Type.Contains("array"))
+	assert.Equal(t, float64(1), definition.Properties["size"].Default)
+
+	require.Contains(t, swagger.Paths.Paths, "/v1/cdns")
+	root := swagger.Paths.Paths["/v1/cdns"]
+	require.NotNil(t, root.Post)
+	require.NotNil(t, root.Get)
+	assert.Equal(t, "cdn", root.Post.Extensions["x-terraform-resource-name"])
+	assert.Equal(t, true, root.Post.Responses.StatusCodeResponses[http.StatusAccepted].Extensions["x-terraform-resource-poll-enabled"])
+	assert.Equal(t, "#/definitions/ContentDeliveryNetworkV1", root.Post.Responses.StatusCodeResponses[http.StatusCreated].Schema.Ref.String())
+	require.Len(t, root.Post.Parameters, 2)
+	assert.Equal(t, "body", root.Post.Parameters[0].In)
+	assert.Equal(t, "header", root.Post.Parameters[1].In)
+	assert.Equal(t, "X-Request-ID", root.Post.Parameters[1].Name)
+
+	require.Contains(t, swagger.Paths.Paths, "/v1/cdns/{id}")
+	instance := swagger.Paths.Paths["/v1/cdns/{id}"]
+	require.NotNil(t, instance.Get)
+	require.NotNil(t, instance.Put)
+	require.NotNil(t, instance.Delete)
+	assert.Equal(t, "id", instance.Get.Parameters[0].Name)
+	assert.True(t, instance.Get.Parameters[0].Required)
+	// the empty security of the read operation must be kept so the operation is called without authentication
+	assert.NotNil(t, instance.Get.Security)
+	assert.Empty(t, instance.Get.Security)
+	assert.Nil(t, instance.Put.Security)
+}
+
+func TestResourceWithoutOperation(t *testing.T) {
+	paths := NewResource("/v1/cdns", "ContentDeliveryNetworkV1").WithoutOperation(ResourceOperationUpdate).pathItems()
+	assert.Nil(t, paths["/v1/cdns/{id}"].Put)
+	assert.NotNil(t, paths["/v1/cdns/{id}"].Get)
+	assert.NotNil(t, paths["/v1/cdns"].Post)
+	assert.Nil(t, paths["/v1/cdns"].Get)
+}
+
+func TestSpecServe(t *testing.T) {
+	doc := newCDNSpec()
+	server := doc.Serve()
+	defer server.Close()
+
+	res, err := http.Get(server.SpecURL())
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+	var served map[string]interface{}
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&served))
+	assert.Equal(t, "2.0", served["swagger"])
+
+	res, err = http.Get(server.URL + "/other")
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func TestSpecServe_Provider(t *testing.T) {
+	server := newCDNSpec().Serve()
+	defer server.Close()
+	p := openapi.ProviderOpenAPI{ProviderName: "openapi"}
+	provider, err := p.CreateSchemaProviderFromServiceConfiguration(&openapi.ServiceConfigStub{SwaggerURL: server.SpecURL()})
+	require.NoError(t, err)
+	assert.Contains(t, provider.ResourcesMap, "openapi_cdn_v1")
+	assert.Contains(t, provider.Schema, "apikey_auth")
+}
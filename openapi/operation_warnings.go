@@ -0,0 +1,57 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// operationWarnings collects the warnings raised while a resource (or data source) operation is performed (e.g. the API
+// endpoints called are deprecated), which are returned to terraform as warning diagnostics once the operation is done so
+// they are shown to the user instead of only being logged. A nil operationWarnings is valid and means the warnings are
+// not collected.
+type operationWarnings struct {
+	mutex    sync.Mutex
+	messages []string
+}
+
+// add collects the warning with the message formatted as fmt.Sprintf does
+func (w *operationWarnings) add(format string, args ...interface{}) {
+	if w == nil {
+		return
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.messages = append(w.messages, fmt.Sprintf(format, args...))
+}
+
+// diagnostics returns the warnings collected as warning diagnostics, in the order they were raised
+func (w *operationWarnings) diagnostics() diag.Diagnostics {
+	if w == nil {
+		return nil
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	var diags diag.Diagnostics
+	for _, message := range w.messages {
+		diags = append(diags, diag.Diagnostic{Severity: diag.Warning, Summary: message})
+	}
+	return diags
+}
+
+// operationWarningsKey is the key of the context value holding the operationWarnings of the resource operation in progress
+type operationWarningsKey struct{}
+
+// withOperationWarnings returns a copy of the given context collecting the warnings raised during the resource (or data
+// source) operation it belongs to
+func withOperationWarnings(ctx context.Context) context.Context {
+	return context.WithValue(ctx, operationWarningsKey{}, &operationWarnings{})
+}
+
+// contextWarnings returns the warnings collected for the resource operation the given context belongs to, nil if none
+func contextWarnings(ctx context.Context) *operationWarnings {
+	warnings, _ := ctx.Value(operationWarningsKey{}).(*operationWarnings)
+	return warnings
+}
@@ -0,0 +1,23 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationWarnings(t *testing.T) {
+	w := &operationWarnings{}
+	assert.Nil(t, w.diagnostics())
+	w.add("resource '%s' uses an API endpoint that is deprecated", "cdn_v1")
+	w.add("some other warning")
+	assert.Equal(t, diag.Diagnostics{
+		{Severity: diag.Warning, Summary: "resource 'cdn_v1' uses an API endpoint that is deprecated"},
+		{Severity: diag.Warning, Summary: "some other warning"},
+	}, w.diagnostics())
+
+	var nilWarnings *operationWarnings
+	nilWarnings.add("some warning")
+	assert.Nil(t, nilWarnings.diagnostics())
+}
@@ -19,7 +19,9 @@ const OpenAPIPluginConfigurationFileName = "terraform-provider-openapi.yaml"
 
 const otfVarSwaggerURL = "OTF_VAR_%s_SWAGGER_URL"
 const otfVarInsecureSkipVerify = "OTF_INSECURE_SKIP_VERIFY"
+const otfVarCABundle = "OTF_CA_BUNDLE"
 const otfVarPluginConfigurationFile = "OTF_VAR_%s_PLUGIN_CONFIGURATION_FILE"
+const otfVarOffline = "OTF_VAR_%s_OFFLINE"
 
 // PluginConfiguration defines the OpenAPI plugin's configuration
 type PluginConfiguration struct {
@@ -99,6 +101,7 @@ func (p *PluginConfiguration) getServiceConfiguration() (ServiceConfiguration, e
 		log.Printf("[INFO] %s set with value %s", swaggerURLEnvVar, apiDiscoveryURL)
 		pluginConfigV1.Services = map[string]*ServiceConfigV1{}
 		pluginConfigV1.Services[p.ProviderName] = NewServiceConfigV1(apiDiscoveryURL, skipVerify)
+		pluginConfigV1.Services[p.ProviderName].CABundle = os.Getenv(otfVarCABundle)
 		serviceConfig, err = pluginConfigV1.GetServiceConfig(p.ProviderName)
 		if err != nil {
 			return nil, err
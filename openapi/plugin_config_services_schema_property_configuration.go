@@ -1,20 +1,25 @@
 package openapi
 
 import (
-	"bytes"
-	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/oliveagle/jsonpath"
 	"log"
 	"os/exec"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
 // ServiceSchemaPropertyConfiguration defines the behaviour expected for the service schema property configuration
 type ServiceSchemaPropertyConfiguration interface {
 	GetDefaultValue() (string, error)
+	GetExternalValue() (string, error)
+	GetStaticDefaultValue() string
+	GetEnvVarNames() []string
 	ExecuteCommand() error
+	ValidateValue(value string) error
 }
 
 const cmdTimeout = 10
@@ -27,6 +32,13 @@ type ServiceSchemaPropertyConfigurationV1 struct {
 	DefaultValue          string                                       `yaml:"default_value"`
 	Command               []string                                     `yaml:"cmd,flow"`
 	CommandTimeout        int                                          `yaml:"cmd_timeout"`
+	CommandCacheTTL       int                                          `yaml:"cmd_cache_ttl,omitempty"`
+	CommandWorkingDir     string                                       `yaml:"cmd_working_dir,omitempty"`
+	CommandEnv            map[string]string                            `yaml:"cmd_env,omitempty"`
+	CommandShell          string                                       `yaml:"cmd_shell,omitempty"`
+	ValidationCommand     []string                                     `yaml:"validation_cmd,flow,omitempty"`
+	ValidationTimeout     int                                          `yaml:"validation_cmd_timeout,omitempty"`
+	EnvVarNames           []string                                     `yaml:"env_vars,flow,omitempty"`
 	ExternalConfiguration ServiceSchemaPropertyExternalConfigurationV1 `yaml:"schema_property_external_configuration"`
 }
 
@@ -38,6 +50,17 @@ type ServiceSchemaPropertyExternalConfigurationV1 struct {
 	KeyName string `yaml:"key_name"`
 	// ContentType defines the type of content the File has
 	ContentType string `yaml:"content_type"` // Currently supported types: raw, json
+	// Keyring defines the secret stored in the OS keyring (macOS Keychain, Windows Credential Manager or the Linux secret
+	// service) containing the value of the schema property. It can not be used along with File
+	Keyring *ServiceSchemaPropertyKeyringV1 `yaml:"keyring,omitempty"`
+}
+
+// ServiceSchemaPropertyKeyringV1 identifies a secret stored in the OS keyring
+type ServiceSchemaPropertyKeyringV1 struct {
+	// Service defines the service the secret is stored under (e.g. terraform-provider-cdn)
+	Service string `yaml:"service"`
+	// Account defines the account of the secret within the service (e.g. apikey_auth)
+	Account string `yaml:"account"`
 }
 
 // GetDefaultValue returns the default value for the schema property configuration. The following logic defines the preference
@@ -48,30 +71,169 @@ type ServiceSchemaPropertyExternalConfigurationV1 struct {
 //      - If the 'content_type' is raw the contents of the 'file' will be used as default value
 //      - If the 'content_type' is json then the content of the 'file' must be json structure and the default value used will be the one defined in the 'key_name'
 //    - An error is thrown otherwise
+//    - If 'keyring' field is populated then the secret stored in the OS keyring will be used as default value
 func (s ServiceSchemaPropertyConfigurationV1) GetDefaultValue() (string, error) {
-	if &s.ExternalConfiguration != nil {
-		if s.ExternalConfiguration.File != "" {
-			log.Printf("[DEBUG] provider schema property '%s' configured to use as default value [ContentType=%s; File=%s, KeyName=%s]", s.SchemaPropertyName, s.ExternalConfiguration.ContentType, s.ExternalConfiguration.File, s.ExternalConfiguration.KeyName)
-			schemaFileParser, err := s.ExternalConfiguration.getFileParser()
-			if err != nil {
-				return "", fmt.Errorf("failed to read external configuration file '%s' for schema property '%s': %s", s.ExternalConfiguration.File, s.SchemaPropertyName, err)
-			}
-			defaultValue, err := schemaFileParser.getValue()
-			if err != nil {
-				return "", err
-			}
-			return defaultValue, nil
+	if s.ExternalConfiguration.File != "" || s.ExternalConfiguration.Keyring != nil {
+		return s.GetExternalValue()
+	}
+	return s.GetStaticDefaultValue(), nil
+}
+
+// GetExternalValue returns the value read from the external configuration ('schema_property_external_configuration'),
+// which is usually populated by the command ('cmd'); empty if the property does not have external configuration
+func (s ServiceSchemaPropertyConfigurationV1) GetExternalValue() (string, error) {
+	if keyring := s.ExternalConfiguration.Keyring; keyring != nil {
+		log.Printf("[DEBUG] provider schema property '%s' configured to use as default value the secret stored in the OS keyring [Service=%s; Account=%s]", s.SchemaPropertyName, keyring.Service, keyring.Account)
+		value, err := keyringSecretLookup(keyring.Service, keyring.Account)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the value of schema property '%s' from the OS keyring (service '%s', account '%s'): %s", s.SchemaPropertyName, keyring.Service, keyring.Account, err)
 		}
+		return value, nil
 	}
-	return s.DefaultValue, nil
+	if s.ExternalConfiguration.File == "" {
+		return "", nil
+	}
+	log.Printf("[DEBUG] provider schema property '%s' configured to use as default value [ContentType=%s; File=%s, KeyName=%s]", s.SchemaPropertyName, s.ExternalConfiguration.ContentType, s.ExternalConfiguration.File, s.ExternalConfiguration.KeyName)
+	schemaFileParser, err := s.ExternalConfiguration.getFileParser()
+	if err != nil {
+		return "", fmt.Errorf("failed to read external configuration file '%s' for schema property '%s': %s", s.ExternalConfiguration.File, s.SchemaPropertyName, err)
+	}
+	return schemaFileParser.getValue()
+}
+
+// GetStaticDefaultValue returns the value specified in the 'default_value' field
+func (s ServiceSchemaPropertyConfigurationV1) GetStaticDefaultValue() string {
+	return s.DefaultValue
+}
+
+// GetEnvVarNames returns the names of the environment variables specified in the 'env_vars' field, in order of precedence
+func (s ServiceSchemaPropertyConfigurationV1) GetEnvVarNames() []string {
+	return s.EnvVarNames
+}
+
+// validate makes sure the environment variable names, the command timeouts and the external configuration are valid
+func (s ServiceSchemaPropertyConfigurationV1) validate() error {
+	for _, envVarName := range s.EnvVarNames {
+		if !isValidEnvVarName(envVarName) {
+			return fmt.Errorf("schema property '%s' env_vars value '%s' is not a valid environment variable name", s.SchemaPropertyName, envVarName)
+		}
+	}
+	for envVarName := range s.CommandEnv {
+		if !isValidEnvVarName(envVarName) {
+			return fmt.Errorf("schema property '%s' cmd_env name '%s' is not a valid environment variable name", s.SchemaPropertyName, envVarName)
+		}
+	}
+	if s.CommandTimeout < 0 {
+		return fmt.Errorf("schema property '%s' cmd_timeout value '%d' is not valid, it must not be negative", s.SchemaPropertyName, s.CommandTimeout)
+	}
+	if s.ValidationTimeout < 0 {
+		return fmt.Errorf("schema property '%s' validation_cmd_timeout value '%d' is not valid, it must not be negative", s.SchemaPropertyName, s.ValidationTimeout)
+	}
+	if err := validateCommandShell(s.CommandShell); err != nil {
+		return fmt.Errorf("schema property '%s' cmd_shell %s", s.SchemaPropertyName, err)
+	}
+	return s.ExternalConfiguration.validate(s.SchemaPropertyName)
+}
+
+func isValidEnvVarName(envVarName string) bool {
+	return envVarName != "" && !strings.ContainsAny(envVarName, "= \t\n\x00")
+}
+
+// newCommandExecutor returns the executor of the commands (cmd and validation_cmd), which runs them with the shell
+// configured ('cmd_shell'), if any, in the working directory configured ('cmd_working_dir'), if any, and with the
+// environment of the provider process plus the extra environment variables configured ('cmd_env'), which take precedence
+func (s ServiceSchemaPropertyConfigurationV1) newCommandExecutor(timeout int) (commandExecutor, error) {
+	executor := commandExecutor{shell: commandShell(s.CommandShell), env: s.CommandEnv, timeout: time.Duration(timeout) * time.Second}
+	if s.CommandWorkingDir != "" {
+		dir, err := expandPath(s.CommandWorkingDir)
+		if err != nil {
+			return commandExecutor{}, fmt.Errorf("failed to resolve the working directory '%s' of schema property '%s' command: %s", s.CommandWorkingDir, s.SchemaPropertyName, err)
+		}
+		executor.workingDir = dir
+	}
+	return executor, nil
+}
+
+// commandFailureDetails returns the error the command failed with along with what the command printed to the standard
+// error, which usually explains the failure (e.g. a credential helper asking the user to log in first)
+func commandFailureDetails(err error, stderr string) string {
+	if output := strings.TrimSpace(stderr); output != "" {
+		return fmt.Sprintf("%s: %s", err, output)
+	}
+	return err.Error()
+}
+
+// commandExecution holds when a command that executed successfully was executed
+type commandExecution struct {
+	executedAt time.Time
+}
+
+// commandExecutionCache keeps track of the commands that executed successfully in this process. Terraform may instantiate
+// the provider multiple times within the same operation (e.g. plan and apply), so commands configured with a cache TTL
+// are only executed again once the TTL expires. This avoids for instance having to go through interactive logins
+// several times in the same run.
+type commandExecutionCache struct {
+	mutex      sync.Mutex
+	executions map[string]commandExecution
+}
+
+var commandCache = &commandExecutionCache{executions: map[string]commandExecution{}}
+
+// commandCacheKey returns the key identifying the command execution: the shell, the working directory and the extra
+// environment variables (sorted by name) the command runs with along with the executable and arguments. The same command
+// running with a different shell, working directory or environment is a different execution
+func (s ServiceSchemaPropertyConfigurationV1) commandCacheKey() string {
+	env := []string{}
+	for name, value := range s.CommandEnv {
+		env = append(env, fmt.Sprintf("%s=%s", name, value))
+	}
+	sort.Strings(env)
+	key := []string{s.CommandShell, s.CommandWorkingDir}
+	key = append(key, env...)
+	key = append(key, s.Command...)
+	return strings.Join(key, "\x00")
 }
 
 // ExecuteCommand run the 'Command' configured in the ServiceSchemaPropertyConfigurationV1 struct if applicable.
-// - If the command fails to execute the appropriate error will be returned including the error returned by exec
+// - If the command has a cache TTL configured ('CommandCacheTTL') and the same command already executed successfully in
+// this process within the TTL, the command is not executed again
+// - The command runs in the working directory configured ('CommandWorkingDir') with the extra environment variables
+// configured ('CommandEnv') on top of the ones of the provider process
+// - If the command fails to execute the appropriate error will be returned including the error returned by exec and what
+// the command printed to the standard error
 // - If the command execution does not finish within the expected time (either before CommandTimeout or before the default timeout 10s)
 // a timeout error will be returned
 // - Otherwise, a nil error will be returned should the command executes successfully with a clean exit code
 func (s ServiceSchemaPropertyConfigurationV1) ExecuteCommand() error {
+	if len(s.Command) > 0 && s.CommandCacheTTL > 0 {
+		return s.executeCachedCommand(commandCache)
+	}
+	return s.executeCommand()
+}
+
+// executeCachedCommand executes the command unless there is an execution of the same command in the given cache that has
+// not expired yet. The cache is locked while the command executes so concurrent provider instances wait for the
+// execution in progress instead of running the same command in parallel
+func (s ServiceSchemaPropertyConfigurationV1) executeCachedCommand(cache *commandExecutionCache) error {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	key := s.commandCacheKey()
+	ttl := time.Duration(s.CommandCacheTTL) * time.Second
+	if execution, exists := cache.executions[key]; exists && time.Since(execution.executedAt) < ttl {
+		log.Printf("[INFO] provider schema property '%s' command '%s' already executed %s ago, skipping execution (cache ttl:%s)", s.SchemaPropertyName, s.Command, time.Since(execution.executedAt), ttl)
+		return nil
+	}
+	doneChan := make(chan error)
+	go s.exec(doneChan)
+	if err := <-doneChan; err != nil {
+		delete(cache.executions, key)
+		return err
+	}
+	cache.executions[key] = commandExecution{executedAt: time.Now()}
+	return nil
+}
+
+func (s ServiceSchemaPropertyConfigurationV1) executeCommand() error {
 	doneChan := make(chan error)
 	// execute the command in a routine and wait for completion
 	go s.exec(doneChan)
@@ -82,6 +244,7 @@ func (s ServiceSchemaPropertyConfigurationV1) ExecuteCommand() error {
 	return nil
 }
 
+// exec runs the command and reports the result in the done channel
 func (s ServiceSchemaPropertyConfigurationV1) exec(doneChan chan error) {
 	if len(s.Command) > 0 {
 		start := time.Now()
@@ -92,37 +255,88 @@ func (s ServiceSchemaPropertyConfigurationV1) exec(doneChan chan error) {
 			timeout = s.CommandTimeout
 		}
 
-		// Create a new context and add a timeout to it
-		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
-		defer cancel() // The cancel should be deferred so resources are cleaned up
-
-		// Create the command with our context
-		cmd := exec.CommandContext(ctx, s.Command[0], s.Command[1:]...)
-
-		// Capture stdout and stderr
-		var stdout, stderr bytes.Buffer
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
-
-		err := cmd.Run()
-
-		// We want to check the context error to see if the timeout was executed. The error returned by cmd.Output() will be OS specific based on what
-		// happens when a process is killed.
-		if ctx.Err() == context.DeadlineExceeded {
-			doneChan <- fmt.Errorf("command '%s' did not finish executing within the expected time %ds (%s)", s.Command, timeout, err)
+		executor, err := s.newCommandExecutor(timeout)
+		if err != nil {
+			doneChan <- err
+			return
+		}
+		result, err := executor.run(s.Command, nil)
+		if result.timedOut {
+			doneChan <- fmt.Errorf("command '%s' did not finish executing within the expected time %ds (%s)", s.Command, timeout, commandFailureDetails(err, result.stderr))
 			return
 		}
 
-		// If there's no context error, we know the command completed (or errored).
+		// If the command did not time out, we know the command completed (or errored).
 		if err != nil {
-			doneChan <- fmt.Errorf("failed to execute '%s' command '%s': %s(%s)", s.SchemaPropertyName, s.Command, stderr.String(), err)
+			doneChan <- fmt.Errorf("failed to execute '%s' command '%s': %s", s.SchemaPropertyName, s.Command, commandFailureDetails(err, result.stderr))
 			return
 		}
-		log.Printf("[INFO] provider schema property '%s' command '%s' executed successfully (time:%s): %s", s.SchemaPropertyName, s.Command, time.Since(start), stdout.String())
+		log.Printf("[INFO] provider schema property '%s' command '%s' executed successfully (time:%s): %s", s.SchemaPropertyName, s.Command, time.Since(start), result.stdout)
 	}
 	doneChan <- nil
 }
 
+// ValidateValue runs the 'ValidationCommand' configured in the ServiceSchemaPropertyConfigurationV1 struct if applicable
+// to validate the value the user provided for the schema property (e.g. checking that a token has not expired yet). The
+// value is passed to the command via the standard input so it does not show up in the process list.
+// - If the command exits with a non zero exit code, the value is considered not valid and the error returned will contain
+// the output of the command (stderr, or stdout if the former is empty) so the command can explain how to fix the value
+// - If the command execution does not finish within the expected time (either before ValidationTimeout or before the default timeout 10s)
+// a timeout error will be returned
+// - Otherwise, a nil error will be returned
+func (s ServiceSchemaPropertyConfigurationV1) ValidateValue(value string) error {
+	if len(s.ValidationCommand) == 0 {
+		return nil
+	}
+	start := time.Now()
+	log.Printf("[INFO] executing '%s' validation command '%s'", s.SchemaPropertyName, s.ValidationCommand)
+
+	timeout := cmdTimeout
+	if s.ValidationTimeout > 0 {
+		timeout = s.ValidationTimeout
+	}
+	executor, err := s.newCommandExecutor(timeout)
+	if err != nil {
+		return err
+	}
+	result, err := executor.run(s.ValidationCommand, strings.NewReader(value))
+	if result.timedOut {
+		return fmt.Errorf("validation command '%s' for provider property '%s' did not finish executing within the expected time %ds (%s)", s.ValidationCommand, s.SchemaPropertyName, timeout, err)
+	}
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return fmt.Errorf("failed to execute '%s' validation command '%s': %s", s.SchemaPropertyName, s.ValidationCommand, err)
+		}
+		output := strings.TrimSpace(result.stderr)
+		if output == "" {
+			output = strings.TrimSpace(result.stdout)
+		}
+		if output == "" {
+			output = err.Error()
+		}
+		return fmt.Errorf("provider property '%s' value is not valid: %s", s.SchemaPropertyName, output)
+	}
+	log.Printf("[INFO] provider schema property '%s' value validated successfully (time:%s)", s.SchemaPropertyName, time.Since(start))
+	return nil
+}
+
+// validate makes sure the keyring secret is fully identified and not configured along with a file
+func (c ServiceSchemaPropertyExternalConfigurationV1) validate(schemaPropertyName string) error {
+	if c.Keyring == nil {
+		return nil
+	}
+	if c.File != "" {
+		return fmt.Errorf("schema property '%s' external configuration can not have both file and keyring", schemaPropertyName)
+	}
+	if c.Keyring.Service == "" {
+		return fmt.Errorf("schema property '%s' keyring service must not be empty", schemaPropertyName)
+	}
+	if c.Keyring.Account == "" {
+		return fmt.Errorf("schema property '%s' keyring account must not be empty", schemaPropertyName)
+	}
+	return nil
+}
+
 func (c ServiceSchemaPropertyExternalConfigurationV1) getFileParser() (schemaFileParser, error) {
 	schemaFileContent, err := getFileContent(c.File)
 	if err != nil {
@@ -5,7 +5,10 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestServiceSchemaConfigurationV1(t *testing.T) {
@@ -54,7 +57,7 @@ func TestServiceSchemaConfigurationV1ExecuteCommand(t *testing.T) {
 				So(err, ShouldNotBeNil)
 			})
 			Convey("And the err message returned should be the expected", func() {
-				So(err.Error(), ShouldEqual, "failed to execute 'some_property_name' command '[cat nonexistingfile]': cat: nonexistingfile: No such file or directory\n(exit status 1)")
+				So(err.Error(), ShouldEqual, "failed to execute 'some_property_name' command '[cat nonexistingfile]': exit status 1: cat: nonexistingfile: No such file or directory")
 			})
 		})
 	})
@@ -74,6 +77,210 @@ func TestServiceSchemaConfigurationV1ExecuteCommand(t *testing.T) {
 			})
 		})
 	})
+	Convey("Given a ServiceSchemaPropertyConfigurationV1 with a command (that prints why it failed to the standard error) configured", t, func() {
+		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{
+			SchemaPropertyName: "some_property_name",
+			Command:            []string{"sh", "-c", "echo 'please log in first' >&2; exit 1"},
+		}
+		Convey("When ExecuteCommand method is called", func() {
+			err := serviceSchemaConfigurationV1.ExecuteCommand()
+			Convey("Then the err message returned should contain the standard error of the command", func() {
+				So(err.Error(), ShouldEqual, "failed to execute 'some_property_name' command '[sh -c echo 'please log in first' >&2; exit 1]': exit status 1: please log in first")
+			})
+		})
+	})
+	Convey("Given a ServiceSchemaPropertyConfigurationV1 with a command configured with a working directory and extra environment variables", t, func() {
+		workingDir, err := ioutil.TempDir("", "")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(workingDir)
+		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{
+			SchemaPropertyName: "some_property_name",
+			Command:            []string{"sh", "-c", "echo \"$TOKEN_SCOPE $HOME\" > token"},
+			CommandWorkingDir:  workingDir,
+			CommandEnv:         map[string]string{"TOKEN_SCOPE": "api"},
+		}
+		Convey("When ExecuteCommand method is called", func() {
+			err := serviceSchemaConfigurationV1.ExecuteCommand()
+			Convey("Then the command should run in the working directory with the extra environment variables along with the ones of the provider process", func() {
+				So(err, ShouldBeNil)
+				output, err := ioutil.ReadFile(filepath.Join(workingDir, "token"))
+				So(err, ShouldBeNil)
+				So(string(output), ShouldEqual, fmt.Sprintf("api %s\n", os.Getenv("HOME")))
+			})
+		})
+	})
+}
+
+func TestServiceSchemaConfigurationV1ExecuteCachedCommand(t *testing.T) {
+	Convey("Given a ServiceSchemaPropertyConfigurationV1 with a command configured with a cache TTL and an empty command cache", t, func() {
+		file, err := ioutil.TempFile("", "cmd_executions")
+		So(err, ShouldBeNil)
+		defer os.Remove(file.Name())
+		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{
+			SchemaPropertyName: "some_property_name",
+			Command:            []string{"sh", "-c", fmt.Sprintf("echo executed >> %s", file.Name())},
+			CommandCacheTTL:    60,
+		}
+		cache := &commandExecutionCache{executions: map[string]commandExecution{}}
+		Convey("When executeCachedCommand method is called twice", func() {
+			err := serviceSchemaConfigurationV1.executeCachedCommand(cache)
+			So(err, ShouldBeNil)
+			err = serviceSchemaConfigurationV1.executeCachedCommand(cache)
+			So(err, ShouldBeNil)
+			Convey("Then the command should have been executed only once", func() {
+				content, err := ioutil.ReadFile(file.Name())
+				So(err, ShouldBeNil)
+				So(string(content), ShouldEqual, "executed\n")
+			})
+			Convey("And the cache should contain the command execution", func() {
+				So(cache.executions, ShouldContainKey, serviceSchemaConfigurationV1.commandCacheKey())
+			})
+		})
+		Convey("When executeCachedCommand method is called after the cached execution expired", func() {
+			cache.executions[serviceSchemaConfigurationV1.commandCacheKey()] = commandExecution{executedAt: time.Now().Add(-2 * time.Minute)}
+			err := serviceSchemaConfigurationV1.executeCachedCommand(cache)
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the command should have been executed again", func() {
+				content, err := ioutil.ReadFile(file.Name())
+				So(err, ShouldBeNil)
+				So(string(content), ShouldEqual, "executed\n")
+			})
+		})
+	})
+	Convey("Given a ServiceSchemaPropertyConfigurationV1 with a command (that exists with error) configured with a cache TTL", t, func() {
+		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{
+			SchemaPropertyName: "some_property_name",
+			Command:            []string{"cat", "nonexistingfile"},
+			CommandCacheTTL:    60,
+		}
+		cache := &commandExecutionCache{executions: map[string]commandExecution{}}
+		Convey("When executeCachedCommand method is called", func() {
+			err := serviceSchemaConfigurationV1.executeCachedCommand(cache)
+			Convey("Then the err returned should NOT be nil", func() {
+				So(err, ShouldNotBeNil)
+			})
+			Convey("And the failed execution should not be cached", func() {
+				So(cache.executions, ShouldBeEmpty)
+			})
+		})
+	})
+	Convey("Given two ServiceSchemaPropertyConfigurationV1 with the same command configured with a cache TTL but running in different working directories", t, func() {
+		file, err := ioutil.TempFile("", "cmd_executions")
+		So(err, ShouldBeNil)
+		defer os.Remove(file.Name())
+		command := []string{"sh", "-c", fmt.Sprintf("pwd >> %s", file.Name())}
+		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{SchemaPropertyName: "some_property_name", Command: command, CommandCacheTTL: 60, CommandWorkingDir: "/"}
+		otherServiceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{SchemaPropertyName: "other_property_name", Command: command, CommandCacheTTL: 60, CommandWorkingDir: os.TempDir()}
+		cache := &commandExecutionCache{executions: map[string]commandExecution{}}
+		Convey("When executeCachedCommand method is called for both", func() {
+			err := serviceSchemaConfigurationV1.executeCachedCommand(cache)
+			So(err, ShouldBeNil)
+			err = otherServiceSchemaConfigurationV1.executeCachedCommand(cache)
+			So(err, ShouldBeNil)
+			Convey("Then the command should have been executed in both working directories", func() {
+				content, err := ioutil.ReadFile(file.Name())
+				So(err, ShouldBeNil)
+				So(strings.Count(string(content), "\n"), ShouldEqual, 2)
+			})
+		})
+	})
+}
+
+func TestServiceSchemaConfigurationV1CommandCacheKey(t *testing.T) {
+	Convey("Given a ServiceSchemaPropertyConfigurationV1 with a command, shell, working directory and env configured", t, func() {
+		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{
+			Command:           []string{"login"},
+			CommandShell:      "sh",
+			CommandWorkingDir: "/tmp",
+			CommandEnv:        map[string]string{"B": "2", "A": "1"},
+		}
+		Convey("When commandCacheKey method is called", func() {
+			key := serviceSchemaConfigurationV1.commandCacheKey()
+			Convey("Then the key should be the same regardless of the order of the env variables", func() {
+				So(key, ShouldEqual, ServiceSchemaPropertyConfigurationV1{Command: []string{"login"}, CommandShell: "sh", CommandWorkingDir: "/tmp", CommandEnv: map[string]string{"A": "1", "B": "2"}}.commandCacheKey())
+			})
+			Convey("And the key should be different if the command runs with a different shell", func() {
+				So(key, ShouldNotEqual, ServiceSchemaPropertyConfigurationV1{Command: []string{"login"}, CommandShell: "default", CommandWorkingDir: "/tmp", CommandEnv: map[string]string{"A": "1", "B": "2"}}.commandCacheKey())
+			})
+			Convey("And the key should be different if the command runs in a different working directory", func() {
+				So(key, ShouldNotEqual, ServiceSchemaPropertyConfigurationV1{Command: []string{"login"}, CommandShell: "sh", CommandWorkingDir: "/", CommandEnv: map[string]string{"A": "1", "B": "2"}}.commandCacheKey())
+			})
+			Convey("And the key should be different if the command runs with different env variables", func() {
+				So(key, ShouldNotEqual, ServiceSchemaPropertyConfigurationV1{Command: []string{"login"}, CommandShell: "sh", CommandWorkingDir: "/tmp", CommandEnv: map[string]string{"A": "1", "B": "3"}}.commandCacheKey())
+			})
+		})
+	})
+}
+
+func TestServiceSchemaConfigurationV1ValidateValue(t *testing.T) {
+	Convey("Given a ServiceSchemaPropertyConfigurationV1 with no validation command configured", t, func() {
+		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{
+			SchemaPropertyName: "some_property_name",
+		}
+		Convey("When ValidateValue method is called", func() {
+			err := serviceSchemaConfigurationV1.ValidateValue("someValue")
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+	Convey("Given a ServiceSchemaPropertyConfigurationV1 with a validation command that checks the value received via stdin", t, func() {
+		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{
+			SchemaPropertyName: "some_property_name",
+			ValidationCommand:  []string{"sh", "-c", `[ "$(cat)" = "validToken" ] || { echo "token expired, please run 'login' to get a new one" >&2; exit 1; }`},
+		}
+		Convey("When ValidateValue method is called with a valid value", func() {
+			err := serviceSchemaConfigurationV1.ValidateValue("validToken")
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+		Convey("When ValidateValue method is called with a value that is not valid", func() {
+			err := serviceSchemaConfigurationV1.ValidateValue("expiredToken")
+			Convey("Then the err message returned should contain the output of the command", func() {
+				So(err.Error(), ShouldEqual, "provider property 'some_property_name' value is not valid: token expired, please run 'login' to get a new one")
+			})
+		})
+	})
+	Convey("Given a ServiceSchemaPropertyConfigurationV1 with a validation command that fails without any output", t, func() {
+		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{
+			SchemaPropertyName: "some_property_name",
+			ValidationCommand:  []string{"false"},
+		}
+		Convey("When ValidateValue method is called", func() {
+			err := serviceSchemaConfigurationV1.ValidateValue("someValue")
+			Convey("Then the err message returned should contain the exit status", func() {
+				So(err.Error(), ShouldEqual, "provider property 'some_property_name' value is not valid: exit status 1")
+			})
+		})
+	})
+	Convey("Given a ServiceSchemaPropertyConfigurationV1 with a validation command that does not exist", t, func() {
+		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{
+			SchemaPropertyName: "some_property_name",
+			ValidationCommand:  []string{"nonexistingcommand"},
+		}
+		Convey("When ValidateValue method is called", func() {
+			err := serviceSchemaConfigurationV1.ValidateValue("someValue")
+			Convey("Then the err message returned should be the expected", func() {
+				So(err.Error(), ShouldStartWith, "failed to execute 'some_property_name' validation command '[nonexistingcommand]'")
+			})
+		})
+	})
+	Convey("Given a ServiceSchemaPropertyConfigurationV1 with a validation command (that timeouts) configured", t, func() {
+		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{
+			SchemaPropertyName: "some_property_name",
+			ValidationCommand:  []string{"sleep", "2"},
+			ValidationTimeout:  1,
+		}
+		Convey("When ValidateValue method is called", func() {
+			err := serviceSchemaConfigurationV1.ValidateValue("someValue")
+			Convey("Then the err message returned should be the expected", func() {
+				So(err.Error(), ShouldEqual, "validation command '[sleep 2]' for provider property 'some_property_name' did not finish executing within the expected time 1s (signal: killed)")
+			})
+		})
+	})
 }
 
 func TestServiceSchemaConfigurationV1Exec(t *testing.T) {
@@ -104,7 +311,7 @@ func TestServiceSchemaConfigurationV1Exec(t *testing.T) {
 				So(err, ShouldNotBeNil)
 			})
 			Convey("And the err message returned should be the expected", func() {
-				So(err.Error(), ShouldEqual, "failed to execute 'some_property_name' command '[cat nonexistingfile]': cat: nonexistingfile: No such file or directory\n(exit status 1)")
+				So(err.Error(), ShouldEqual, "failed to execute 'some_property_name' command '[cat nonexistingfile]': exit status 1: cat: nonexistingfile: No such file or directory")
 			})
 		})
 	})
@@ -221,6 +428,81 @@ func TestServiceSchemaConfigurationV1GetDefaultValue(t *testing.T) {
 	})
 }
 
+func TestServiceSchemaConfigurationV1GetDefaultValueFromKeyring(t *testing.T) {
+	defer func(lookup func(service, account string) (string, error)) { keyringSecretLookup = lookup }(keyringSecretLookup)
+
+	Convey("Given a ServiceSchemaPropertyConfigurationV1 with a default value and a secret stored in the OS keyring", t, func() {
+		keyringSecretLookup = func(service, account string) (string, error) {
+			if service == "terraform-provider-cdn" && account == "apikey_auth" {
+				return "superSecret", nil
+			}
+			return "", errKeyringSecretNotFound
+		}
+		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{
+			SchemaPropertyName: "apikey_auth",
+			DefaultValue:       "defaultValue",
+			ExternalConfiguration: ServiceSchemaPropertyExternalConfigurationV1{
+				Keyring: &ServiceSchemaPropertyKeyringV1{Service: "terraform-provider-cdn", Account: "apikey_auth"},
+			},
+		}
+		Convey("When GetDefaultValue method is called", func() {
+			value, err := serviceSchemaConfigurationV1.GetDefaultValue()
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the value returned should be the secret stored in the keyring", func() {
+				So(value, ShouldEqual, "superSecret")
+			})
+		})
+		Convey("When GetDefaultValue method is called and the secret is not stored in the keyring", func() {
+			serviceSchemaConfigurationV1.ExternalConfiguration.Keyring.Account = "other"
+			_, err := serviceSchemaConfigurationV1.GetDefaultValue()
+			Convey("Then the err message should be", func() {
+				So(err.Error(), ShouldEqual, "failed to read the value of schema property 'apikey_auth' from the OS keyring (service 'terraform-provider-cdn', account 'other'): secret not found, please make sure it is stored in the OS keyring")
+			})
+		})
+	})
+}
+
+func TestServiceExternalConfigurationV1Validate(t *testing.T) {
+	Convey("Given a ServiceSchemaPropertyExternalConfigurationV1 with no keyring", t, func() {
+		externalConfiguration := ServiceSchemaPropertyExternalConfigurationV1{File: "/tmp/token", ContentType: "raw"}
+		Convey("When validate method is called", func() {
+			err := externalConfiguration.validate("apikey_auth")
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+	Convey("Given a ServiceSchemaPropertyExternalConfigurationV1 with both file and keyring", t, func() {
+		externalConfiguration := ServiceSchemaPropertyExternalConfigurationV1{File: "/tmp/token", Keyring: &ServiceSchemaPropertyKeyringV1{Service: "cdn", Account: "apikey_auth"}}
+		Convey("When validate method is called", func() {
+			err := externalConfiguration.validate("apikey_auth")
+			Convey("Then the err message should be", func() {
+				So(err.Error(), ShouldEqual, "schema property 'apikey_auth' external configuration can not have both file and keyring")
+			})
+		})
+	})
+	Convey("Given a ServiceSchemaPropertyExternalConfigurationV1 with a keyring missing the service", t, func() {
+		externalConfiguration := ServiceSchemaPropertyExternalConfigurationV1{Keyring: &ServiceSchemaPropertyKeyringV1{Account: "apikey_auth"}}
+		Convey("When validate method is called", func() {
+			err := externalConfiguration.validate("apikey_auth")
+			Convey("Then the err message should be", func() {
+				So(err.Error(), ShouldEqual, "schema property 'apikey_auth' keyring service must not be empty")
+			})
+		})
+	})
+	Convey("Given a ServiceSchemaPropertyExternalConfigurationV1 with a keyring missing the account", t, func() {
+		externalConfiguration := ServiceSchemaPropertyExternalConfigurationV1{Keyring: &ServiceSchemaPropertyKeyringV1{Service: "cdn"}}
+		Convey("When validate method is called", func() {
+			err := externalConfiguration.validate("apikey_auth")
+			Convey("Then the err message should be", func() {
+				So(err.Error(), ShouldEqual, "schema property 'apikey_auth' keyring account must not be empty")
+			})
+		})
+	})
+}
+
 func TestServiceExternalConfigurationV1GetFileParser(t *testing.T) {
 	Convey("Given a ServiceSchemaPropertyExternalConfigurationV1 configured with 'raw' content", t, func() {
 		expectedValue := "some content"
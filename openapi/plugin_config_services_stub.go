@@ -1,14 +1,43 @@
 package openapi
 
-// ServiceConfigStub implements the ServiceConfiguration interface and can be used to simplify the creation of the ProviderOpenAPI
-// provider by calling the CreateSchemaProviderWithConfiguration function passing in the stub wit the swagger URL populated
-// with the URL where the openapi doc is hosted.
+import "time"
+
+// ServiceConfigStub implements the ServiceConfiguration and ServiceConfigurationOptions interfaces and can be used to
+// simplify the creation of the ProviderOpenAPI provider by calling the CreateSchemaProviderWithConfiguration function
+// passing in the stub wit the swagger URL populated with the URL where the openapi doc is hosted.
 type ServiceConfigStub struct {
 	SwaggerURL          string
 	PluginVersion       string
 	InsecureSkipVerify  bool
+	CABundle            string
+	TLSMinVersion       uint16
+	TLSCipherSuites     []uint16
 	SchemaConfiguration []*ServiceSchemaPropertyConfigurationStub
-	Err                 error
+	RetryBudget         time.Duration
+	ApplyDeadline       time.Duration
+	PreventDestroy      []string
+	Singularize         bool
+	SingularOverrides   map[string]string
+	DuplicateStrategy   string
+	// DataSourceInstanceSuffix defaults to _instance if not set
+	DataSourceInstanceSuffix string
+	Webhooks                 []ServiceWebhook
+	MethodOverrideHeader     string
+	SwaggerURLOIDC           *ServiceOIDC
+	TLS                      *ServiceTLS
+	Retry                    *ServiceRetry
+	RateLimitMaxWait         time.Duration
+	SlowCallThreshold        time.Duration
+	MaxParallelAPICalls      int
+	ProxyURL                 string
+	NoProxy                  string
+	AccessTokenCache         bool
+	DataSourceCache          bool
+	ResponseValidation       bool
+	PropertySources          []string
+	CredentialHelper         *ServiceCredentialHelper
+	APIObjectResource        bool
+	Err                      error
 }
 
 // ServiceSchemaPropertyConfigurationStub implements the ServiceSchemaPropertyConfiguration and can be used to simplify
@@ -16,8 +45,12 @@ type ServiceConfigStub struct {
 type ServiceSchemaPropertyConfigurationStub struct {
 	SchemaPropertyName   string
 	DefaultValue         string
+	ExternalValue        string
+	EnvVarNames          []string
 	Err                  error
 	ExecuteCommandCalled bool
+	ValidateValueErr     error
+	ValidatedValue       string
 }
 
 // GetSwaggerURL returns the swagger URL value configured in the ServiceConfigStub.SwaggerURL field
@@ -35,11 +68,154 @@ func (s *ServiceConfigStub) IsInsecureSkipVerifyEnabled() bool {
 	return s.InsecureSkipVerify
 }
 
+// GetRetryBudget returns the retry budget configured in the ServiceConfigStub.RetryBudget field
+func (s *ServiceConfigStub) GetRetryBudget() time.Duration {
+	return s.RetryBudget
+}
+
+// GetApplyDeadline returns the apply deadline configured in the ServiceConfigStub.ApplyDeadline field
+func (s *ServiceConfigStub) GetApplyDeadline() time.Duration {
+	return s.ApplyDeadline
+}
+
+// GetPreventDestroyResources returns the resource names configured in the ServiceConfigStub.PreventDestroy field
+func (s *ServiceConfigStub) GetPreventDestroyResources() []string {
+	return s.PreventDestroy
+}
+
+// IsResourceNameSingularizationEnabled returns the bool configured in the ServiceConfigStub.Singularize field
+func (s *ServiceConfigStub) IsResourceNameSingularizationEnabled() bool {
+	return s.Singularize
+}
+
+// GetResourceNameSingularOverrides returns the overrides configured in the ServiceConfigStub.SingularOverrides field
+func (s *ServiceConfigStub) GetResourceNameSingularOverrides() map[string]string {
+	return s.SingularOverrides
+}
+
+// GetDuplicateResourceNameStrategy returns the strategy configured in the ServiceConfigStub.DuplicateStrategy field;
+// remove is returned if not set
+func (s *ServiceConfigStub) GetDuplicateResourceNameStrategy() string {
+	if s.DuplicateStrategy == "" {
+		return duplicateResourceNameStrategyRemove
+	}
+	return s.DuplicateStrategy
+}
+
+// GetDataSourceInstanceSuffix returns the suffix configured in the ServiceConfigStub.DataSourceInstanceSuffix field;
+// _instance is returned if not set
+func (s *ServiceConfigStub) GetDataSourceInstanceSuffix() string {
+	if s.DataSourceInstanceSuffix == "" {
+		return defaultDataSourceInstanceSuffix
+	}
+	return s.DataSourceInstanceSuffix
+}
+
+// GetWebhooks returns the webhooks configured in the ServiceConfigStub.Webhooks field
+func (s *ServiceConfigStub) GetWebhooks() []ServiceWebhook {
+	return s.Webhooks
+}
+
+// GetMethodOverrideHeader returns the header configured in the ServiceConfigStub.MethodOverrideHeader field
+func (s *ServiceConfigStub) GetMethodOverrideHeader() string {
+	return s.MethodOverrideHeader
+}
+
+// GetSwaggerURLOIDC returns the OIDC configuration configured in the ServiceConfigStub.SwaggerURLOIDC field
+func (s *ServiceConfigStub) GetSwaggerURLOIDC() *ServiceOIDC {
+	return s.SwaggerURLOIDC
+}
+
+// GetTLSConfiguration returns the mutual TLS configuration configured in the ServiceConfigStub.TLS field
+func (s *ServiceConfigStub) GetTLSConfiguration() *ServiceTLS {
+	return s.TLS
+}
+
+// GetRetryConfiguration returns the retry configuration configured in the ServiceConfigStub.Retry field
+func (s *ServiceConfigStub) GetRetryConfiguration() *ServiceRetry {
+	return s.Retry
+}
+
+// GetRateLimitMaxWait returns the max rate limit wait configured in the ServiceConfigStub.RateLimitMaxWait field. Rate
+// limited requests are not retried if not set
+func (s *ServiceConfigStub) GetRateLimitMaxWait() time.Duration {
+	return s.RateLimitMaxWait
+}
+
+// GetSlowCallThreshold returns the slow call threshold configured in the ServiceConfigStub.SlowCallThreshold field
+func (s *ServiceConfigStub) GetSlowCallThreshold() time.Duration {
+	return s.SlowCallThreshold
+}
+
+// GetMaxParallelAPICalls returns the max parallel API calls configured in the ServiceConfigStub.MaxParallelAPICalls field
+func (s *ServiceConfigStub) GetMaxParallelAPICalls() int {
+	return s.MaxParallelAPICalls
+}
+
+// GetProxyURL returns the proxy URL configured in the ServiceConfigStub.ProxyURL field
+func (s *ServiceConfigStub) GetProxyURL() string {
+	return s.ProxyURL
+}
+
+// GetNoProxy returns the hosts bypassing the proxy configured in the ServiceConfigStub.NoProxy field
+func (s *ServiceConfigStub) GetNoProxy() string {
+	return s.NoProxy
+}
+
+// GetPropertySourcePrecedence returns the sources configured in the ServiceConfigStub.PropertySources field, or the
+// default precedence if not set
+func (s *ServiceConfigStub) GetPropertySourcePrecedence() []string {
+	if len(s.PropertySources) == 0 {
+		return defaultPropertySourcePrecedence
+	}
+	return s.PropertySources
+}
+
+// GetCredentialHelper returns the credential helper configured in the ServiceConfigStub.CredentialHelper field
+func (s *ServiceConfigStub) GetCredentialHelper() *ServiceCredentialHelper {
+	return s.CredentialHelper
+}
+
+// IsAccessTokenCacheEnabled returns the value configured in the ServiceConfigStub.AccessTokenCache field
+func (s *ServiceConfigStub) IsAccessTokenCacheEnabled() bool {
+	return s.AccessTokenCache
+}
+
+// IsDataSourceCacheEnabled returns the value configured in the ServiceConfigStub.DataSourceCache field
+func (s *ServiceConfigStub) IsDataSourceCacheEnabled() bool {
+	return s.DataSourceCache
+}
+
+// IsResponseValidationEnabled returns the value configured in the ServiceConfigStub.ResponseValidation field
+func (s *ServiceConfigStub) IsResponseValidationEnabled() bool {
+	return s.ResponseValidation
+}
+
+// IsAPIObjectResourceEnabled returns the value configured in the ServiceConfigStub.APIObjectResource field
+func (s *ServiceConfigStub) IsAPIObjectResourceEnabled() bool {
+	return s.APIObjectResource
+}
+
 // Validate returns an error if the ServiceConfigStub.Err field is set with an error
 func (s *ServiceConfigStub) Validate(runningPluginVersion string) error {
 	return s.Err
 }
 
+// GetCABundle returns the CA bundle configured in the ServiceConfigStub.CABundle field
+func (s *ServiceConfigStub) GetCABundle() string {
+	return s.CABundle
+}
+
+// GetTLSMinVersion returns the min TLS version configured in the ServiceConfigStub.TLSMinVersion field
+func (s *ServiceConfigStub) GetTLSMinVersion() uint16 {
+	return s.TLSMinVersion
+}
+
+// GetTLSCipherSuites returns the cipher suites configured in the ServiceConfigStub.TLSCipherSuites field
+func (s *ServiceConfigStub) GetTLSCipherSuites() []uint16 {
+	return s.TLSCipherSuites
+}
+
 // GetSchemaPropertyConfiguration returns the service schema configuration set in the ServiceConfigStub.SchemaConfiguration field
 func (s ServiceConfigStub) GetSchemaPropertyConfiguration(schemaPropertyName string) ServiceSchemaPropertyConfiguration {
 	for _, p := range s.SchemaConfiguration {
@@ -55,9 +231,31 @@ func (s *ServiceSchemaPropertyConfigurationStub) GetDefaultValue() (string, erro
 	return s.DefaultValue, nil
 }
 
+// GetExternalValue returns the external value configured in the ServiceSchemaPropertyConfigurationStub.ExternalValue field
+func (s *ServiceSchemaPropertyConfigurationStub) GetExternalValue() (string, error) {
+	return s.ExternalValue, nil
+}
+
+// GetStaticDefaultValue returns the dafult value configured in the ServiceSchemaPropertyConfigurationStub.DefaultValue field
+func (s *ServiceSchemaPropertyConfigurationStub) GetStaticDefaultValue() string {
+	return s.DefaultValue
+}
+
+// GetEnvVarNames returns the environment variable names configured in the ServiceSchemaPropertyConfigurationStub.EnvVarNames field
+func (s *ServiceSchemaPropertyConfigurationStub) GetEnvVarNames() []string {
+	return s.EnvVarNames
+}
+
 // ExecuteCommand keeps track if the execute command method has been called and returns the configured err
 // ServiceSchemaPropertyConfigurationStub.ServiceSchemaPropertyConfigurationStub if set
 func (s *ServiceSchemaPropertyConfigurationStub) ExecuteCommand() error {
 	s.ExecuteCommandCalled = true
 	return s.Err
 }
+
+// ValidateValue keeps track of the value validated and returns the configured err
+// ServiceSchemaPropertyConfigurationStub.ValidateValueErr if set
+func (s *ServiceSchemaPropertyConfigurationStub) ValidateValue(value string) error {
+	s.ValidatedValue = value
+	return s.ValidateValueErr
+}
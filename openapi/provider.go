@@ -2,23 +2,31 @@ package openapi
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
 	"crypto/tls"
 
 	"fmt"
 	"log"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/dikhan/terraform-provider-openapi/openapi/terraformutils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 // ProviderOpenAPI defines the struct for the OpenAPI Terraform Provider
 type ProviderOpenAPI struct {
 	ProviderName string
+	// EmbeddedSpec contains the OpenAPI document (JSON) embedded in the provider binary at build time, e.g. via go:embed in
+	// the main package of the binary embedding the provider. If present, the embedded document is used when the document
+	// can not be retrieved from the swagger URL (e.g. the API or the portal hosting it is down) or the provider runs in
+	// offline mode (OTF_VAR_<provider_name>_OFFLINE env variable set to true)
+	EmbeddedSpec []byte
 	provider     *schema.Provider
 	err          error
 }
 
-// CreateSchemaProvider returns a terraform.ResourceProvider.
+// CreateSchemaProvider returns a schema.Provider.
 func (p *ProviderOpenAPI) CreateSchemaProvider() (*schema.Provider, error) {
 	serviceConfiguration, err := getServiceConfiguration(p.ProviderName)
 	if err != nil {
@@ -38,9 +46,9 @@ func (p *ProviderOpenAPI) CreateSchemaProviderFromServiceConfiguration(serviceCo
 
 	log.Printf("[DEBUG] service configuration = %+v", serviceConfiguration)
 
-	openAPISpecAnalyser, err := CreateSpecAnalyser(specAnalyserV2, serviceConfiguration.GetSwaggerURL())
+	openAPISpecAnalyser, err := p.createSpecAnalyser(serviceConfiguration)
 	if err != nil {
-		return nil, fmt.Errorf("plugin OpenAPI spec analyser error: %s", err)
+		return nil, wrapError(err, "plugin OpenAPI spec analyser error")
 	}
 
 	providerFactory, err := newProviderFactory(p.ProviderName, openAPISpecAnalyser, serviceConfiguration)
@@ -50,11 +58,67 @@ func (p *ProviderOpenAPI) CreateSchemaProviderFromServiceConfiguration(serviceCo
 
 	p.provider, err = providerFactory.createProvider()
 	if err != nil {
-		return nil, fmt.Errorf("plugin terraform-provider-%s init error while creating schema provider: %s", p.ProviderName, err)
+		if !isTypedError(err) {
+			err = &SpecAnalysisError{Err: err}
+		}
+		return nil, wrapError(err, "plugin terraform-provider-%s init error while creating schema provider", p.ProviderName)
 	}
 	return p.provider, nil
 }
 
+// openAPIDocumentSource describes where the OpenAPI document the provider runs with was loaded from
+type openAPIDocumentSource string
+
+const (
+	openAPIDocumentSourceSwaggerURL       openAPIDocumentSource = "swagger URL"
+	openAPIDocumentSourceEmbeddedOffline  openAPIDocumentSource = "embedded in the provider binary (offline mode)"
+	openAPIDocumentSourceEmbeddedFallback openAPIDocumentSource = "embedded in the provider binary (the swagger URL could not be retrieved)"
+)
+
+// createSpecAnalyser returns the spec analyser for the OpenAPI document exposed at the swagger URL. The embedded document
+// is used instead if the provider runs in offline mode, or as a fallback if the document can not be retrieved
+func (p *ProviderOpenAPI) createSpecAnalyser(serviceConfiguration ServiceConfiguration) (SpecAnalyser, error) {
+	openAPISpecAnalyser, _, err := p.loadSpecAnalyser(serviceConfiguration)
+	return openAPISpecAnalyser, err
+}
+
+// loadSpecAnalyser returns the spec analyser as createSpecAnalyser does along with where the OpenAPI document was loaded from
+func (p *ProviderOpenAPI) loadSpecAnalyser(serviceConfiguration ServiceConfiguration) (SpecAnalyser, openAPIDocumentSource, error) {
+	swaggerURL := serviceConfiguration.GetSwaggerURL()
+	if isOfflineModeEnabled(p.ProviderName) {
+		if len(p.EmbeddedSpec) == 0 {
+			return nil, "", fmt.Errorf("provider '%s' is configured to run in offline mode (%s) but it does not have an embedded OpenAPI document", p.ProviderName, fmt.Sprintf(otfVarOffline, p.ProviderName))
+		}
+		log.Printf("[INFO] provider '%s' running in offline mode, using the embedded OpenAPI document instead of '%s'", p.ProviderName, maskURLCredentials(swaggerURL))
+		openAPISpecAnalyser, err := createSpecAnalyserForEmbeddedDocument(swaggerURL, p.EmbeddedSpec)
+		return openAPISpecAnalyser, openAPIDocumentSourceEmbeddedOffline, err
+	}
+	accessToken, err := getSwaggerURLAccessToken(p.ProviderName, serviceConfiguration, swaggerURL)
+	if err == nil {
+		var openAPISpecAnalyser SpecAnalyser
+		if openAPISpecAnalyser, err = createAuthenticatedSpecAnalyserForDocument(swaggerURL, accessToken); err == nil {
+			return openAPISpecAnalyser, openAPIDocumentSourceSwaggerURL, nil
+		}
+	}
+	if _, isFetchError := err.(*SpecFetchError); isFetchError && len(p.EmbeddedSpec) > 0 {
+		log.Printf("[WARN] provider '%s' could not retrieve the OpenAPI document, falling back to the embedded OpenAPI document: %s", p.ProviderName, err)
+		openAPISpecAnalyser, err := createSpecAnalyserForEmbeddedDocument(swaggerURL, p.EmbeddedSpec)
+		return openAPISpecAnalyser, openAPIDocumentSourceEmbeddedFallback, err
+	}
+	return nil, "", err
+}
+
+// isOfflineModeEnabled checks whether the OTF_VAR_<provider_name>_OFFLINE env variable is set to true
+func isOfflineModeEnabled(providerName string) bool {
+	offlineEnvVar := fmt.Sprintf(otfVarOffline, providerName)
+	offline, err := terraformutils.MultiEnvDefaultString([]string{offlineEnvVar, strings.ToUpper(offlineEnvVar)}, "false")
+	if err != nil {
+		return false
+	}
+	enabled, _ := strconv.ParseBool(offline)
+	return enabled
+}
+
 // This function is implemented with temporary code thus it can serve as an example
 // on how the same code base can be used by binaries of this same provider named differently
 // but internally each will end up calling a different service provider's api
@@ -77,6 +141,28 @@ func getServiceConfiguration(providerName string) (ServiceConfiguration, error)
 		log.Printf("[WARN] Provider '%s' is using insecure skip verify. Please make sure you trust the aforementioned server hosting the swagger file. Otherwise, it's highly recommended avoiding the use of OTF_INSECURE_SKIP_VERIFY env variable when executing this provider", providerName)
 	}
 
+	serviceConfigurationOptions := getServiceConfigurationOptions(serviceConfiguration)
+	if caBundle := serviceConfigurationOptions.GetCABundle(); caBundle != "" {
+		rootCAs, err := newCABundleCertPool(caBundle)
+		if err != nil {
+			return nil, err
+		}
+		tr := http.DefaultTransport.(*http.Transport)
+		if tr.TLSClientConfig == nil {
+			tr.TLSClientConfig = &tls.Config{}
+		}
+		tr.TLSClientConfig.RootCAs = rootCAs
+		log.Printf("[INFO] Provider '%s' trusts the CA certificates in '%s' when fetching the swagger file", providerName, caBundle)
+	}
+
+	if serviceConfigurationOptions.GetTLSMinVersion() != 0 || len(serviceConfigurationOptions.GetTLSCipherSuites()) > 0 {
+		tr := http.DefaultTransport.(*http.Transport)
+		if tr.TLSClientConfig == nil {
+			tr.TLSClientConfig = &tls.Config{}
+		}
+		applyServiceTLSSettings(tr.TLSClientConfig, serviceConfiguration)
+	}
+
 	log.Printf("[INFO] Provider %s is using the following swagger file: %s", providerName, serviceConfiguration.GetSwaggerURL())
 	return serviceConfiguration, nil
 }
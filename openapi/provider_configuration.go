@@ -2,11 +2,35 @@ package openapi
 
 import (
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const providerPropertyRegion = "region"
 const providerPropertyEndPoints = "endpoints"
+const providerPropertyDisableResponseCache = "disable_response_cache"
+const providerPropertyOverridePreventDestroy = "override_prevent_destroy"
+const providerPropertySwaggerURL = "swagger_url"
+const providerPropertyReadOnly = "read_only"
+const providerPropertyMethodOverrideHeader = "method_override_header"
+const providerPropertyClientCertFile = "client_cert_file"
+const providerPropertyClientKeyFile = "client_key_file"
+const providerPropertyCAFile = "ca_file"
+const providerPropertyClientCertPEM = "client_cert_pem"
+const providerPropertyClientKeyPEM = "client_key_pem"
+const providerPropertyCAPEM = "ca_pem"
+const providerPropertyFullRefresh = "full_refresh"
+const providerPropertyAuthScheme = "auth_scheme"
+const providerPropertyMaxParallelAPICalls = "max_parallel_api_calls"
+const providerPropertyProxyURL = "proxy_url"
+const providerPropertyNoProxy = "no_proxy"
+const providerPropertyWaitForDeletion = "wait_for_deletion"
+
+// reservedProviderPropertyNames contains the names of the provider's built-in properties which can not be used by properties
+// coming from the OpenAPI document (e.g. security definitions or headers)
+var reservedProviderPropertyNames = []string{providerPropertyRegion, providerPropertyEndPoints, providerPropertyDisableResponseCache, providerPropertyOverridePreventDestroy, providerPropertySwaggerURL, providerPropertyReadOnly, providerPropertyMethodOverrideHeader, providerPropertyClientCertFile, providerPropertyClientKeyFile, providerPropertyCAFile, providerPropertyClientCertPEM, providerPropertyClientKeyPEM, providerPropertyCAPEM, providerPropertyFullRefresh, providerPropertyAuthScheme, providerPropertyMaxParallelAPICalls, providerPropertyProxyURL, providerPropertyNoProxy, providerPropertyWaitForDeletion}
 
 // providerConfiguration contains all the configuration related to the OpenAPI provider. The configuration at the moment
 // supports:
@@ -16,11 +40,41 @@ const providerPropertyEndPoints = "endpoints"
 // file. These headers may be sent as part of the HTTP calls if the resource requires them (as specified in the swagger doc)
 // - Endpoints contains the endpoints configured by the user, which effectively will override the default host set in the swagger file
 // - Region contains the region if user provided value for it (only supported for multi-region providers)
+// - DisableResponseCache is true when the user opted out from caching the responses of the data source reads
+// - OverridePreventDestroy is true when the user allows destroying resources protected by the service configuration prevent destroy policy
+// - GrantedScopes contains the scopes granted to the credentials of the security definitions that declare a token introspection
+// endpoint, keyed by the security definition terraform configuration name
+// - SwaggerURL contains the location of the OpenAPI document the provider (alias) should talk to, if it differs from the default one
+// - ReadOnly is true when the user does not allow the provider to create, update or delete any resource
+// - MethodOverrideHeader contains the header used to tunnel PUT, PATCH and DELETE requests via POST, if any
+// - TLS contains the client certificate (and the CA) used to call APIs protected by mutual TLS, if any
+// - FullRefresh is true when the user wants the refreshes to also fetch the properties that are refreshed on demand only
+// - MissingCredentials contains the errors describing the credentials missing for the security definitions the user did
+// not configure, keyed by the security definition terraform configuration name. These are reported when calling operations
+// that require them
+// - AuthScheme contains the name of the global security requirement selected by the user, if the API supports alternative ones
+// - MaxParallelAPICalls contains the max number of concurrent API calls the provider can make; zero means unlimited
+// - Proxy contains the proxy the API calls are routed through (and the hosts that bypass it), if any
+// - WaitForDeletion contains the names of the resources whose deletion must be confirmed by polling the instance GET
+// operation until the API returns 404 Not Found or 410 Gone, regardless of the OpenAPI document
 type providerConfiguration struct {
 	Headers                   map[string]string
 	SecuritySchemaDefinitions map[string]specAPIKeyAuthenticator
 	Endpoints                 map[string]string
 	Region                    string
+	DisableResponseCache      bool
+	OverridePreventDestroy    bool
+	SwaggerURL                string
+	GrantedScopes             map[string][]string
+	ReadOnly                  bool
+	MethodOverrideHeader      string
+	TLS                       providerTLSConfiguration
+	FullRefresh               bool
+	MissingCredentials        map[string]error
+	AuthScheme                string
+	MaxParallelAPICalls       int
+	Proxy                     providerProxyConfiguration
+	WaitForDeletion           map[string]bool
 }
 
 // createProviderConfig returns a providerConfiguration populated with the values provided by the user in the provider's terraform
@@ -30,18 +84,53 @@ func newProviderConfiguration(specAnalyser SpecAnalyser, data *schema.ResourceDa
 	providerConfiguration.Headers = map[string]string{}
 	providerConfiguration.Endpoints = map[string]string{}
 	providerConfiguration.SecuritySchemaDefinitions = map[string]specAPIKeyAuthenticator{}
+	providerConfiguration.MissingCredentials = map[string]error{}
 
 	securitySchemaDefinitions, err := specAnalyser.GetSecurity().GetAPIKeySecurityDefinitions()
 	if err != nil {
 		return nil, err
 	}
+
+	// The region is read before the credentials since the AWS Signature Version 4 authenticators sign the requests for it
+	region := data.Get(providerPropertyRegion)
+	if region != nil {
+		providerConfiguration.Region = region.(string)
+	}
+
+	// The credentials of the security definitions are optional, missing ones are only reported when calling an operation
+	// that requires them so users with partial access (e.g. only using data sources that need no auth) are not blocked
 	if securitySchemaDefinitions != nil {
 		for _, secDef := range *securitySchemaDefinitions {
 			secDefTerraformCompliantName := secDef.getTerraformConfigurationName()
+			if basicAuth, ok := secDef.(specBasicAuthSecurityDefinition); ok {
+				username, usernameExists := data.GetOkExists(basicAuth.getUsernameTerraformConfigurationName())
+				password, passwordExists := data.GetOkExists(basicAuth.getPasswordTerraformConfigurationName())
+				if !usernameExists || !passwordExists {
+					providerConfiguration.MissingCredentials[secDefTerraformCompliantName] = &AuthConfigError{Err: fmt.Errorf("security schema definition '%s' is missing the username or password values, please make sure the '%s' and '%s' values are provided in the terraform configuration", secDefTerraformCompliantName, basicAuth.getUsernameTerraformConfigurationName(), basicAuth.getPasswordTerraformConfigurationName())}
+					continue
+				}
+				providerConfiguration.SecuritySchemaDefinitions[secDefTerraformCompliantName] = createAPIKeyAuthenticator(secDef, basicAuth.buildCredentials(username.(string), password.(string)))
+				continue
+			}
+			if awsSigV4, ok := secDef.(specAWSSigV4SecurityDefinition); ok {
+				authenticator, err := newAWSSigV4AuthenticatorFromConfiguration(awsSigV4, data, providerConfiguration.Region)
+				if err != nil {
+					providerConfiguration.MissingCredentials[secDefTerraformCompliantName] = err
+					continue
+				}
+				providerConfiguration.SecuritySchemaDefinitions[secDefTerraformCompliantName] = authenticator
+				continue
+			}
 			if value, exists := data.GetOkExists(secDefTerraformCompliantName); exists {
-				providerConfiguration.SecuritySchemaDefinitions[secDefTerraformCompliantName] = createAPIKeyAuthenticator(secDef, value.(string))
+				authenticator := createAPIKeyAuthenticator(secDef, value.(string))
+				if secondaryKey, ok := secDef.(specSecondaryKeySecurityDefinition); ok {
+					if secondaryValue, exists := data.GetOkExists(secondaryKey.getSecondaryTerraformConfigurationName()); exists {
+						authenticator = newAPIKeyFailoverAuthenticator(secDefTerraformCompliantName, authenticator, createAPIKeyAuthenticator(secDef, secondaryValue.(string)))
+					}
+				}
+				providerConfiguration.SecuritySchemaDefinitions[secDefTerraformCompliantName] = authenticator
 			} else {
-				return nil, fmt.Errorf("security schema definition '%s' is missing the value, please make sure this value is provided in the terraform configuration", secDefTerraformCompliantName)
+				providerConfiguration.MissingCredentials[secDefTerraformCompliantName] = &AuthConfigError{Err: fmt.Errorf("security schema definition '%s' is missing the value, please make sure this value is provided in the terraform configuration", secDefTerraformCompliantName)}
 			}
 		}
 	}
@@ -61,23 +150,138 @@ func newProviderConfiguration(specAnalyser SpecAnalyser, data *schema.ResourceDa
 		}
 	}
 
-	region := data.Get(providerPropertyRegion)
-	if region != nil {
-		providerConfiguration.Region = region.(string)
+	if disableResponseCache, exists := data.GetOkExists(providerPropertyDisableResponseCache); exists {
+		providerConfiguration.DisableResponseCache = disableResponseCache.(bool)
+	}
+
+	if overridePreventDestroy, exists := data.GetOkExists(providerPropertyOverridePreventDestroy); exists {
+		providerConfiguration.OverridePreventDestroy = overridePreventDestroy.(bool)
+	}
+
+	if readOnly, exists := data.GetOkExists(providerPropertyReadOnly); exists {
+		providerConfiguration.ReadOnly = readOnly.(bool)
+	}
+
+	if methodOverrideHeader, exists := data.GetOkExists(providerPropertyMethodOverrideHeader); exists {
+		providerConfiguration.MethodOverrideHeader = methodOverrideHeader.(string)
+	}
+
+	providerConfiguration.TLS = newProviderTLSConfiguration(data)
+	providerConfiguration.Proxy = newProviderProxyConfiguration(data)
+
+	if fullRefresh, exists := data.GetOkExists(providerPropertyFullRefresh); exists {
+		providerConfiguration.FullRefresh = fullRefresh.(bool)
+	}
+
+	if authScheme, exists := data.GetOkExists(providerPropertyAuthScheme); exists {
+		providerConfiguration.AuthScheme = authScheme.(string)
+	}
+
+	if maxParallelAPICalls, exists := data.GetOkExists(providerPropertyMaxParallelAPICalls); exists {
+		providerConfiguration.MaxParallelAPICalls = maxParallelAPICalls.(int)
+	}
+
+	if swaggerURL, exists := data.GetOkExists(providerPropertySwaggerURL); exists {
+		providerConfiguration.SwaggerURL = swaggerURL.(string)
 	}
 
 	if providerConfigurationEndPoints != nil {
 		providerConfiguration.Endpoints = providerConfigurationEndPoints.configureEndpoints(data)
+		providerConfiguration.WaitForDeletion = providerConfigurationEndPoints.configureWaitForDeletion(data)
 	}
 
 	return providerConfiguration, nil
 }
 
+// newAWSSigV4AuthenticatorFromConfiguration creates the AWS Signature Version 4 authenticator for the given security
+// definition with the AWS credentials provided by the user. The region of multi-region providers takes preference over
+// the one set in the security definition; if none of them are set the region is resolved from the host of the API
+func newAWSSigV4AuthenticatorFromConfiguration(secDef specAWSSigV4SecurityDefinition, data *schema.ResourceData, providerRegion string) (specAPIKeyAuthenticator, error) {
+	accessKeyID, accessKeyIDExists := data.GetOkExists(secDef.getAccessKeyIDTerraformConfigurationName())
+	secretAccessKey, secretAccessKeyExists := data.GetOkExists(secDef.getSecretAccessKeyTerraformConfigurationName())
+	if !accessKeyIDExists || !secretAccessKeyExists {
+		return nil, &AuthConfigError{Err: fmt.Errorf("security schema definition '%s' is missing the AWS credentials, please make sure the '%s' and '%s' values are provided in the terraform configuration", secDef.getTerraformConfigurationName(), secDef.getAccessKeyIDTerraformConfigurationName(), secDef.getSecretAccessKeyTerraformConfigurationName())}
+	}
+	sessionToken, _ := data.Get(secDef.getSessionTokenTerraformConfigurationName()).(string)
+	region := secDef.region
+	if providerRegion != "" {
+		region = providerRegion
+	}
+	credentials := awsCredentials{
+		accessKeyID:     accessKeyID.(string),
+		secretAccessKey: secretAccessKey.(string),
+		sessionToken:    sessionToken,
+	}
+	return newAWSSigV4Authenticator(credentials, secDef.service, region), nil
+}
+
 func (p *providerConfiguration) getAuthenticatorFor(s SpecSecurityScheme) specAPIKeyAuthenticator {
 	securitySchemeConfigName := s.getTerraformConfigurationName()
 	return p.SecuritySchemaDefinitions[securitySchemeConfigName]
 }
 
+// getMissingScopes returns the scopes required by the given security scheme that have not been granted to the credentials
+// configured for it. Scopes are only known for security definitions with a token introspection endpoint, so nil is
+// returned for the rest
+func (p *providerConfiguration) getMissingScopes(s SpecSecurityScheme) []string {
+	grantedScopes, introspected := p.GrantedScopes[s.getTerraformConfigurationName()]
+	if !introspected {
+		return nil
+	}
+	var missingScopes []string
+	for _, scope := range s.Scopes {
+		granted := false
+		for _, grantedScope := range grantedScopes {
+			if scope == grantedScope {
+				granted = true
+				break
+			}
+		}
+		if !granted {
+			missingScopes = append(missingScopes, scope)
+		}
+	}
+	return missingScopes
+}
+
+// getResourceMissingScopes returns the scopes required by the security schemes declared in the operations of the given
+// resource that have not been granted to the credentials configured, grouped by security definition (e.g. 'oauth2'
+// missing scopes: cdn.write). Empty is returned if all of them have been granted. The operations with no security schemes
+// use the global ones, whose scopes are checked when the provider is configured.
+func (p *providerConfiguration) getResourceMissingScopes(resource SpecResource) string {
+	resourceOperations := resource.getResourceOperations()
+	operations := []*specResourceOperation{resourceOperations.List, resourceOperations.Post, resourceOperations.Get, resourceOperations.Put, resourceOperations.Delete}
+	if resourceOperations.Post != nil && resourceOperations.Post.OnFailureCleanup != nil {
+		operations = append(operations, resourceOperations.Post.OnFailureCleanup.operation)
+	}
+	missingScopesBySecDef := map[string]map[string]bool{}
+	for _, operation := range operations {
+		if operation == nil {
+			continue
+		}
+		for _, securityScheme := range operation.SecuritySchemes {
+			secDefName := securityScheme.getTerraformConfigurationName()
+			for _, missingScope := range p.getMissingScopes(securityScheme) {
+				if missingScopesBySecDef[secDefName] == nil {
+					missingScopesBySecDef[secDefName] = map[string]bool{}
+				}
+				missingScopesBySecDef[secDefName][missingScope] = true
+			}
+		}
+	}
+	var missingScopes []string
+	for secDefName, scopeSet := range missingScopesBySecDef {
+		var scopes []string
+		for scope := range scopeSet {
+			scopes = append(scopes, scope)
+		}
+		sort.Strings(scopes)
+		missingScopes = append(missingScopes, fmt.Sprintf("'%s' missing scopes: %s", secDefName, strings.Join(scopes, ", ")))
+	}
+	sort.Strings(missingScopes)
+	return strings.Join(missingScopes, "; ")
+}
+
 func (p *providerConfiguration) getHeaderValueFor(s SpecHeaderParam) string {
 	headerConfigName := s.GetHeaderTerraformConfigurationName()
 	return p.Headers[headerConfigName]
@@ -97,3 +301,9 @@ func (p *providerConfiguration) getEndPoint(resourceName string) string {
 	}
 	return ""
 }
+
+// isWaitForDeletionEnabled returns true if the user configured the provider to wait for the given resource to be gone
+// after deleting it
+func (p *providerConfiguration) isWaitForDeletionEnabled(resourceName string) bool {
+	return p.WaitForDeletion[resourceName]
+}
@@ -3,9 +3,11 @@ package openapi
 import (
 	"bytes"
 	"fmt"
+	"net/url"
+	"strings"
+
 	"github.com/dikhan/terraform-provider-openapi/openapi/openapiutils"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 type providerConfigurationEndPoints struct {
@@ -43,8 +45,27 @@ func (p *providerConfigurationEndPoints) endpointsValidateFunc() schema.SchemaVa
 		if openapiutils.IsValidHost(userValue) {
 			return nil, nil
 		}
-		return nil, []error{fmt.Errorf("property '%s' value '%s' is not valid, please make sure the value is a valid FQDN or well formed IP (the host may contain non standard ports too followed by a colon - e,g: www.api.com:8080). The protocol used when performing the API call will be populated based on the swagger specification", key, userValue)}
+		if u, err := url.Parse(userValue); err == nil && (u.Scheme == httpScheme || u.Scheme == httpsScheme) && openapiutils.IsValidHost(u.Host) && (u.Path == "" || u.Path == "/") && u.RawQuery == "" {
+			if u.Scheme == httpScheme {
+				warns = append(warns, fmt.Sprintf("property '%s' value '%s' forces the API calls to be made over plain http", key, userValue))
+			}
+			return warns, nil
+		}
+		return nil, []error{fmt.Errorf("property '%s' value '%s' is not valid, please make sure the value is a valid FQDN or well formed IP (the host may contain non standard ports too followed by a colon - e,g: www.api.com:8080), optionally prefixed with the http or https protocol (e,g: http://localhost:8080). If the protocol is not specified, the one used when performing the API call will be populated based on the swagger specification", key, userValue)}
+	}
+}
+
+// splitEndPoint returns the scheme and the host of the given endpoint value. The scheme is empty if the endpoint only
+// contains the host (e.g. www.api.com:8080)
+func splitEndPoint(endPoint string) (string, string) {
+	if !strings.Contains(endPoint, "://") {
+		return "", endPoint
 	}
+	u, err := url.Parse(endPoint)
+	if err != nil {
+		return "", endPoint
+	}
+	return u.Scheme, u.Host
 }
 
 // endpointsToHash calculates the unique ID used to store the endpoints element in a hash.
@@ -55,7 +76,7 @@ func (p *providerConfigurationEndPoints) endpointsToHash(resources []string) sch
 		for _, name := range resources {
 			buf.WriteString(fmt.Sprintf("%s-", m[name].(string)))
 		}
-		return hashcode.String(buf.String())
+		return schema.HashString(buf.String())
 	}
 }
 
@@ -77,3 +98,48 @@ func (p *providerConfigurationEndPoints) configureEndpoints(data *schema.Resourc
 	}
 	return nil
 }
+
+// waitForDeletionSchema returns a schema for the provider's wait_for_deletion property, which contains the names of the
+// resources whose deletion must be confirmed by polling the instance GET operation until it returns 404 or 410
+func (p *providerConfigurationEndPoints) waitForDeletionSchema() *schema.Schema {
+	if len(p.resourceNames) == 0 {
+		return nil
+	}
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Schema{
+			Type:         schema.TypeString,
+			ValidateFunc: p.resourceNameValidateFunc(),
+		},
+		Set:         schema.HashString,
+		Description: "Names of the resources (e,g: cdn_v1) whose DELETE operations wait until the API returns 404 Not Found or 410 Gone when reading the deleted instance, so dependent resources are not created while the API is still tearing it down",
+	}
+}
+
+func (p *providerConfigurationEndPoints) resourceNameValidateFunc() schema.SchemaValidateFunc {
+	return func(value interface{}, key string) (warns []string, errs []error) {
+		userValue := value.(string)
+		for _, name := range p.resourceNames {
+			if name == userValue {
+				return nil, nil
+			}
+		}
+		return nil, []error{fmt.Errorf("property '%s' value '%s' is not valid, please make sure the value is the name of one of the resources exposed by the provider (without the provider name prefix)", key, userValue)}
+	}
+}
+
+// configureWaitForDeletion returns the names of the resources the user configured in the provider's wait_for_deletion
+// property
+func (p *providerConfigurationEndPoints) configureWaitForDeletion(data *schema.ResourceData) map[string]bool {
+	waitForDeletion := map[string]bool{}
+	if len(p.resourceNames) == 0 {
+		return waitForDeletion
+	}
+	if resourceNames, ok := data.Get(providerPropertyWaitForDeletion).(*schema.Set); ok {
+		for _, resourceName := range resourceNames.List() {
+			waitForDeletion[resourceName.(string)] = true
+		}
+	}
+	return waitForDeletion
+}
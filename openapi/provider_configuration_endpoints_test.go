@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -69,7 +69,7 @@ func TestEndpointsToHash(t *testing.T) {
 				m[resourceName] = "something to get the string representation from"
 				var buf bytes.Buffer
 				buf.WriteString(fmt.Sprintf("%s-", m[resourceName].(string)))
-				So(schemaSetFunction(m), ShouldEqual, hashcode.String(buf.String()))
+				So(schemaSetFunction(m), ShouldEqual, schema.HashString(buf.String()))
 			})
 		})
 	})
@@ -105,8 +105,26 @@ func TestEndpointsValidateFunc(t *testing.T) {
 				So(errs, ShouldBeNil)
 			})
 		})
-		Convey("When endpointsValidateFunc is invoked with a whole URL (only hostnames are valid)", func() {
-			warns, errs := p.endpointsValidateFunc()("http://www.valid-domain.com", "something")
+		Convey("When endpointsValidateFunc is invoked with a host prefixed with the https protocol", func() {
+			warns, errs := p.endpointsValidateFunc()("https://www.valid-domain.com:8443", "something")
+			Convey("Then the warns should be nil", func() {
+				So(warns, ShouldBeNil)
+			})
+			Convey("Then the errs should be nil", func() {
+				So(errs, ShouldBeNil)
+			})
+		})
+		Convey("When endpointsValidateFunc is invoked with a host prefixed with the http protocol", func() {
+			warns, errs := p.endpointsValidateFunc()("http://localhost:8080", "something")
+			Convey("Then the warns should contain a warning about the use of plain http", func() {
+				So(warns, ShouldResemble, []string{"property 'something' value 'http://localhost:8080' forces the API calls to be made over plain http"})
+			})
+			Convey("Then the errs should be nil", func() {
+				So(errs, ShouldBeNil)
+			})
+		})
+		Convey("When endpointsValidateFunc is invoked with a whole URL including a path (only hostnames are valid)", func() {
+			warns, errs := p.endpointsValidateFunc()("http://www.valid-domain.com/v1", "something")
 			Convey("Then the warns should be nil", func() {
 				So(warns, ShouldBeNil)
 			})
@@ -114,12 +132,66 @@ func TestEndpointsValidateFunc(t *testing.T) {
 				So(errs, ShouldNotBeNil)
 			})
 			Convey("And the error message should be the expected one", func() {
-				So(errs[0].Error(), ShouldEqual, "property 'something' value 'http://www.valid-domain.com' is not valid, please make sure the value is a valid FQDN or well formed IP (the host may contain non standard ports too followed by a colon - e,g: www.api.com:8080). The protocol used when performing the API call will be populated based on the swagger specification")
+				So(errs[0].Error(), ShouldEqual, "property 'something' value 'http://www.valid-domain.com/v1' is not valid, please make sure the value is a valid FQDN or well formed IP (the host may contain non standard ports too followed by a colon - e,g: www.api.com:8080), optionally prefixed with the http or https protocol (e,g: http://localhost:8080). If the protocol is not specified, the one used when performing the API call will be populated based on the swagger specification")
+			})
+		})
+		Convey("When endpointsValidateFunc is invoked with a URL using a protocol other than http or https", func() {
+			_, errs := p.endpointsValidateFunc()("ftp://www.valid-domain.com", "something")
+			Convey("Then the errs should not be nil", func() {
+				So(errs, ShouldNotBeNil)
 			})
 		})
 	})
 }
 
+func TestWaitForDeletionSchema(t *testing.T) {
+	p := providerConfigurationEndPoints{resourceNames: []string{"cdn_v1"}}
+	s := p.waitForDeletionSchema()
+	assert.Equal(t, schema.TypeSet, s.Type)
+	assert.True(t, s.Optional)
+	assert.Equal(t, schema.TypeString, s.Elem.(*schema.Schema).Type)
+
+	p = providerConfigurationEndPoints{resourceNames: []string{}}
+	assert.Nil(t, p.waitForDeletionSchema())
+}
+
+func TestResourceNameValidateFunc(t *testing.T) {
+	p := providerConfigurationEndPoints{resourceNames: []string{"cdn_v1", "lb_v1"}}
+	_, errs := p.resourceNameValidateFunc()("lb_v1", "wait_for_deletion.0")
+	assert.Empty(t, errs)
+	_, errs = p.resourceNameValidateFunc()("provider_lb_v1", "wait_for_deletion.0")
+	assert.Equal(t, []error{fmt.Errorf("property 'wait_for_deletion.0' value 'provider_lb_v1' is not valid, please make sure the value is the name of one of the resources exposed by the provider (without the provider name prefix)")}, errs)
+}
+
+func TestConfigureWaitForDeletion(t *testing.T) {
+	p := providerConfigurationEndPoints{resourceNames: []string{"cdn_v1", "lb_v1"}}
+	data := schema.TestResourceDataRaw(t, map[string]*schema.Schema{providerPropertyWaitForDeletion: p.waitForDeletionSchema()}, map[string]interface{}{
+		providerPropertyWaitForDeletion: []interface{}{"lb_v1"},
+	})
+	assert.Equal(t, map[string]bool{"lb_v1": true}, p.configureWaitForDeletion(data))
+
+	data = schema.TestResourceDataRaw(t, map[string]*schema.Schema{providerPropertyWaitForDeletion: p.waitForDeletionSchema()}, map[string]interface{}{})
+	assert.Empty(t, p.configureWaitForDeletion(data))
+}
+
+func TestSplitEndPoint(t *testing.T) {
+	testCases := []struct {
+		endPoint       string
+		expectedScheme string
+		expectedHost   string
+	}{
+		{endPoint: "www.domain.com", expectedScheme: "", expectedHost: "www.domain.com"},
+		{endPoint: "localhost:8080", expectedScheme: "", expectedHost: "localhost:8080"},
+		{endPoint: "http://localhost:8080", expectedScheme: "http", expectedHost: "localhost:8080"},
+		{endPoint: "https://www.domain.com/", expectedScheme: "https", expectedHost: "www.domain.com"},
+	}
+	for _, tc := range testCases {
+		scheme, host := splitEndPoint(tc.endPoint)
+		assert.Equal(t, tc.expectedScheme, scheme, tc.endPoint)
+		assert.Equal(t, tc.expectedHost, host, tc.endPoint)
+	}
+}
+
 //func TestGetProviderConfigEndPointsFromData(t *testing.T) {
 //	Convey("Given a provider factory", t, func() {
 //		expectedResource := "resource_name"
@@ -0,0 +1,103 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// providerProxyConfiguration contains the proxy provided by the user to route the API calls through (e.g. a corporate
+// proxy) as well as the hosts that bypass it. The proxy credentials, if any, are part of the proxy URL
+type providerProxyConfiguration struct {
+	URL     string
+	NoProxy string
+}
+
+// newProviderProxyConfiguration returns the providerProxyConfiguration populated with the values provided by the user in
+// the provider's terraform configuration
+func newProviderProxyConfiguration(data *schema.ResourceData) providerProxyConfiguration {
+	proxyConfiguration := providerProxyConfiguration{}
+	if proxyURL, exists := data.GetOkExists(providerPropertyProxyURL); exists {
+		proxyConfiguration.URL = proxyURL.(string)
+	}
+	if noProxy, exists := data.GetOkExists(providerPropertyNoProxy); exists {
+		proxyConfiguration.NoProxy = noProxy.(string)
+	}
+	return proxyConfiguration
+}
+
+// isEnabled returns true if the user provided a proxy URL; false otherwise, in which case the proxy configured in the
+// HTTP_PROXY/HTTPS_PROXY environment variables (if any) is used
+func (c providerProxyConfiguration) isEnabled() bool {
+	return c.URL != ""
+}
+
+// proxyFunc returns the function used by the http transport to select the proxy for each request. The requests to the
+// hosts in the no proxy list (and to localhost) are sent directly. The no proxy list follows the same format as the
+// NO_PROXY environment variable (e.g. api.corp.com,.internal.com,10.0.0.0/8)
+func (c providerProxyConfiguration) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	if _, err := parseProxyURL(c.URL); err != nil {
+		return nil, err
+	}
+	proxyConfig := &httpproxy.Config{
+		HTTPProxy:  c.URL,
+		HTTPSProxy: c.URL,
+		NoProxy:    c.NoProxy,
+	}
+	proxyForURL := proxyConfig.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyForURL(req.URL)
+	}, nil
+}
+
+// String returns the proxy URL without the password, so it can be logged
+func (c providerProxyConfiguration) String() string {
+	proxyURL, err := url.Parse(c.URL)
+	if err != nil {
+		return ""
+	}
+	return redactURLPassword(proxyURL)
+}
+
+// parseProxyURL parses the given proxy URL making sure the scheme is supported and the host is present. The errors
+// returned do not contain the proxy password
+func parseProxyURL(proxyURL string) (*url.URL, error) {
+	parsedURL, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the proxy URL")
+	}
+	switch parsedURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("proxy URL '%s' scheme '%s' not supported, supported schemes: http, https, socks5", redactURLPassword(parsedURL), parsedURL.Scheme)
+	}
+	if parsedURL.Host == "" {
+		return nil, fmt.Errorf("proxy URL '%s' is missing the host", redactURLPassword(parsedURL))
+	}
+	return parsedURL, nil
+}
+
+// redactURLPassword returns the given URL with the password (if any) replaced with 'xxxxx'
+func redactURLPassword(u *url.URL) string {
+	if _, hasPassword := u.User.Password(); !hasPassword {
+		return u.String()
+	}
+	redactedURL := *u
+	redactedURL.User = url.UserPassword(u.User.Username(), "xxxxx")
+	return redactedURL.String()
+}
+
+// validateProxyURL is the validate function of the proxy_url provider property
+func validateProxyURL(value interface{}, key string) ([]string, []error) {
+	proxyURL := value.(string)
+	if proxyURL == "" {
+		return nil, nil
+	}
+	if _, err := parseProxyURL(proxyURL); err != nil {
+		return nil, []error{fmt.Errorf("property '%s' value is not valid: %s", key, err)}
+	}
+	return nil, nil
+}
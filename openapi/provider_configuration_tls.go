@@ -0,0 +1,237 @@
+package openapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// providerTLSConfiguration contains the client certificate (and the CA) provided by the user to call APIs protected by
+// mutual TLS. The certificates can be provided either as paths to PEM encoded files or as PEM encoded strings; if both
+// are provided the PEM encoded strings take preference
+type providerTLSConfiguration struct {
+	ClientCertFile string
+	ClientKeyFile  string
+	CAFile         string
+	ClientCertPEM  string
+	ClientKeyPEM   string
+	CAPEM          string
+}
+
+// newProviderTLSConfiguration returns the providerTLSConfiguration populated with the values provided by the user in the
+// provider's terraform configuration
+func newProviderTLSConfiguration(data *schema.ResourceData) providerTLSConfiguration {
+	getString := func(propertyName string) string {
+		if value, exists := data.GetOkExists(propertyName); exists {
+			return value.(string)
+		}
+		return ""
+	}
+	return providerTLSConfiguration{
+		ClientCertFile: getString(providerPropertyClientCertFile),
+		ClientKeyFile:  getString(providerPropertyClientKeyFile),
+		CAFile:         getString(providerPropertyCAFile),
+		ClientCertPEM:  getString(providerPropertyClientCertPEM),
+		ClientKeyPEM:   getString(providerPropertyClientKeyPEM),
+		CAPEM:          getString(providerPropertyCAPEM),
+	}
+}
+
+// isEnabled returns true if the user provided a client certificate or a CA; false otherwise
+func (t providerTLSConfiguration) isEnabled() bool {
+	return t.ClientCertFile != "" || t.ClientKeyFile != "" || t.CAFile != "" || t.ClientCertPEM != "" || t.ClientKeyPEM != "" || t.CAPEM != ""
+}
+
+// newHTTPClient returns an http client configured with the client certificate and the CA provided. If no CA is provided,
+// the system CAs along with the ones in the CA bundle of the service configuration (if any) are trusted. The rest of the
+// transport settings mirror the ones of http.DefaultTransport (e.g. proxy from environment variables)
+func (t providerTLSConfiguration) newHTTPClient(insecureSkipVerify bool, caBundle string) (*http.Client, error) {
+	tlsConfig, err := t.newTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.InsecureSkipVerify = insecureSkipVerify
+	if tlsConfig.RootCAs == nil && caBundle != "" {
+		if tlsConfig.RootCAs, err = newCABundleCertPool(caBundle); err != nil {
+			return nil, err
+		}
+	}
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			DualStack: true,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       tlsConfig,
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+func (t providerTLSConfiguration) newTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+	clientCert, err := t.getPEM(t.ClientCertPEM, t.ClientCertFile, providerPropertyClientCertFile)
+	if err != nil {
+		return nil, err
+	}
+	clientKey, err := t.getPEM(t.ClientKeyPEM, t.ClientKeyFile, providerPropertyClientKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	if clientCert != "" || clientKey != "" {
+		if clientCert == "" || clientKey == "" {
+			return nil, fmt.Errorf("mutual TLS requires both the client certificate ('%s' or '%s') and the client key ('%s' or '%s')", providerPropertyClientCertFile, providerPropertyClientCertPEM, providerPropertyClientKeyFile, providerPropertyClientKeyPEM)
+		}
+		certificate, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the mutual TLS client certificate: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	ca, err := t.getPEM(t.CAPEM, t.CAFile, providerPropertyCAFile)
+	if err != nil {
+		return nil, err
+	}
+	if ca != "" {
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM([]byte(ca)) {
+			return nil, fmt.Errorf("failed to load the CA certificates: no PEM encoded certificates found")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+	return tlsConfig, nil
+}
+
+// getPEM returns the PEM value if provided; otherwise the content of the file is returned (if provided)
+func (t providerTLSConfiguration) getPEM(pem, file, filePropertyName string) (string, error) {
+	if pem != "" || file == "" {
+		return pem, nil
+	}
+	content, err := getFileContent(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the '%s' file '%s': %s", filePropertyName, file, err)
+	}
+	return content, nil
+}
+
+// newCABundleCertPool returns the system CAs along with the CAs in the given PEM encoded CA bundle file, so servers with
+// certificates issued by private CAs are trusted as well as the public ones
+func newCABundleCertPool(caBundle string) (*x509.CertPool, error) {
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil || rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+	content, err := getFileContent(caBundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the CA bundle '%s': %s", caBundle, err)
+	}
+	if !rootCAs.AppendCertsFromPEM([]byte(content)) {
+		return nil, fmt.Errorf("failed to load the CA bundle '%s': no PEM encoded certificates found", caBundle)
+	}
+	return rootCAs, nil
+}
+
+// tlsVersions contains the TLS versions that can be configured as the min version in the service configuration
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// tlsCipherSuites contains the cipher suites that can be allowed in the service configuration, the TLS 1.3 cipher suites
+// are not configurable
+var tlsCipherSuites = map[string]uint16{
+	"TLS_RSA_WITH_AES_128_CBC_SHA":            tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+	"TLS_RSA_WITH_AES_256_CBC_SHA":            tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+	"TLS_RSA_WITH_AES_128_GCM_SHA256":         tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+	"TLS_RSA_WITH_AES_256_GCM_SHA384":         tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+	"TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA":    tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
+	"TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA":    tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA,
+	"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA":      tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+	"TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA":      tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256": tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384": tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256":   tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384":   tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305":  tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+	"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305":    tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+}
+
+// parseTLSVersion returns the TLS version matching the given version number (e.g. 1.2); zero is returned if empty
+func parseTLSVersion(version string) (uint16, error) {
+	if version == "" {
+		return 0, nil
+	}
+	tlsVersion, supported := tlsVersions[version]
+	if !supported {
+		return 0, fmt.Errorf("supported versions: 1.0, 1.1, 1.2, 1.3")
+	}
+	return tlsVersion, nil
+}
+
+// parseTLSCipherSuites returns the ids of the cipher suites with the given names
+func parseTLSCipherSuites(names []string) ([]uint16, error) {
+	var cipherSuites []uint16
+	for _, name := range names {
+		cipherSuite, supported := tlsCipherSuites[name]
+		if !supported {
+			var supportedNames []string
+			for supportedName := range tlsCipherSuites {
+				supportedNames = append(supportedNames, supportedName)
+			}
+			sort.Strings(supportedNames)
+			return nil, fmt.Errorf("cipher suite '%s' not supported, supported cipher suites: %v", name, supportedNames)
+		}
+		cipherSuites = append(cipherSuites, cipherSuite)
+	}
+	return cipherSuites, nil
+}
+
+// formatTLSVersion returns the version number (e.g. 1.2) of the given TLS version
+func formatTLSVersion(tlsVersion uint16) string {
+	for version, id := range tlsVersions {
+		if id == tlsVersion {
+			return version
+		}
+	}
+	return fmt.Sprintf("0x%04x", tlsVersion)
+}
+
+// formatTLSCipherSuites returns the names of the given cipher suites
+func formatTLSCipherSuites(cipherSuites []uint16) []string {
+	var names []string
+	for _, cipherSuite := range cipherSuites {
+		name := fmt.Sprintf("0x%04x", cipherSuite)
+		for supportedName, id := range tlsCipherSuites {
+			if id == cipherSuite {
+				name = supportedName
+				break
+			}
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// applyServiceTLSSettings sets the min TLS version and the cipher suites configured in the service configuration (if
+// any) into the given TLS configuration
+func applyServiceTLSSettings(tlsConfig *tls.Config, serviceConfiguration ServiceConfiguration) {
+	serviceConfigurationOptions := getServiceConfigurationOptions(serviceConfiguration)
+	if minVersion := serviceConfigurationOptions.GetTLSMinVersion(); minVersion != 0 {
+		tlsConfig.MinVersion = minVersion
+	}
+	if cipherSuites := serviceConfigurationOptions.GetTLSCipherSuites(); len(cipherSuites) > 0 {
+		tlsConfig.CipherSuites = cipherSuites
+	}
+}
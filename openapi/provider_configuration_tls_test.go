@@ -0,0 +1,316 @@
+package openapi
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testCertificate contains a PEM encoded certificate and private key generated for the tests
+type testCertificate struct {
+	cert     *x509.Certificate
+	key      *rsa.PrivateKey
+	certPEM  string
+	keyPEM   string
+	keyPair  tls.Certificate
+	certPool *x509.CertPool
+}
+
+// newTestCertificate generates a certificate signed by the given parent (self-signed if nil)
+func newTestCertificate(t *testing.T, commonName string, parent *testCertificate, isCA bool, extKeyUsage x509.ExtKeyUsage) *testCertificate {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{extKeyUsage},
+		BasicConstraintsValid: true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if isCA {
+		template.IsCA = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	}
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	keyPair, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPool := x509.NewCertPool()
+	certPool.AddCert(cert)
+	return &testCertificate{cert: cert, key: key, certPEM: certPEM, keyPEM: keyPEM, keyPair: keyPair, certPool: certPool}
+}
+
+func writeTestFile(t *testing.T, content string) string {
+	file, err := ioutil.TempFile("", "tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	return file.Name()
+}
+
+func TestProviderTLSConfigurationNewHTTPClient(t *testing.T) {
+	ca := newTestCertificate(t, "ca", nil, true, x509.ExtKeyUsageAny)
+	serverCert := newTestCertificate(t, "127.0.0.1", ca, false, x509.ExtKeyUsageServerAuth)
+	clientCert := newTestCertificate(t, "client", ca, false, x509.ExtKeyUsageClientAuth)
+
+	api := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	api.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert.keyPair},
+		ClientCAs:    ca.certPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	api.StartTLS()
+	defer api.Close()
+
+	Convey("Given a providerTLSConfiguration with the client certificate and CA provided as PEM encoded strings", t, func() {
+		tlsConfiguration := providerTLSConfiguration{
+			ClientCertPEM: clientCert.certPEM,
+			ClientKeyPEM:  clientCert.keyPEM,
+			CAPEM:         ca.certPEM,
+		}
+		Convey("When newHTTPClient is called and the client calls an API protected by mutual TLS", func() {
+			httpClient, err := tlsConfiguration.newHTTPClient(false, "")
+			So(err, ShouldBeNil)
+			resp, err := httpClient.Get(api.URL)
+			Convey("Then the call should succeed", func() {
+				So(err, ShouldBeNil)
+				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+			})
+		})
+	})
+
+	Convey("Given a providerTLSConfiguration with the client certificate and CA provided as files", t, func() {
+		certFile := writeTestFile(t, clientCert.certPEM)
+		defer os.Remove(certFile)
+		keyFile := writeTestFile(t, clientCert.keyPEM)
+		defer os.Remove(keyFile)
+		caFile := writeTestFile(t, ca.certPEM)
+		defer os.Remove(caFile)
+		tlsConfiguration := providerTLSConfiguration{
+			ClientCertFile: certFile,
+			ClientKeyFile:  keyFile,
+			CAFile:         caFile,
+		}
+		Convey("When newHTTPClient is called and the client calls an API protected by mutual TLS", func() {
+			httpClient, err := tlsConfiguration.newHTTPClient(false, "")
+			So(err, ShouldBeNil)
+			resp, err := httpClient.Get(api.URL)
+			Convey("Then the call should succeed", func() {
+				So(err, ShouldBeNil)
+				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+			})
+		})
+	})
+
+	Convey("Given a providerTLSConfiguration with the CA but no client certificate", t, func() {
+		tlsConfiguration := providerTLSConfiguration{
+			CAPEM: ca.certPEM,
+		}
+		Convey("When newHTTPClient is called and the client calls an API protected by mutual TLS", func() {
+			httpClient, err := tlsConfiguration.newHTTPClient(false, "")
+			So(err, ShouldBeNil)
+			_, err = httpClient.Get(api.URL)
+			Convey("Then the call should fail since the API requires a client certificate", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given a providerTLSConfiguration with a client certificate but no CA", t, func() {
+		tlsConfiguration := providerTLSConfiguration{
+			ClientCertPEM: clientCert.certPEM,
+			ClientKeyPEM:  clientCert.keyPEM,
+		}
+		Convey("When newHTTPClient is called with insecure skip verify enabled and the client calls an API protected by mutual TLS", func() {
+			httpClient, err := tlsConfiguration.newHTTPClient(true, "")
+			So(err, ShouldBeNil)
+			resp, err := httpClient.Get(api.URL)
+			Convey("Then the call should succeed since the server certificate is not verified", func() {
+				So(err, ShouldBeNil)
+				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+			})
+		})
+	})
+
+	Convey("Given a providerTLSConfiguration with the client certificate but not the client key", t, func() {
+		tlsConfiguration := providerTLSConfiguration{
+			ClientCertPEM: clientCert.certPEM,
+		}
+		Convey("When newHTTPClient is called", func() {
+			_, err := tlsConfiguration.newHTTPClient(false, "")
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "mutual TLS requires both the client certificate ('client_cert_file' or 'client_cert_pem') and the client key ('client_key_file' or 'client_key_pem')")
+			})
+		})
+	})
+
+	Convey("Given a providerTLSConfiguration with a client certificate file that does not exist", t, func() {
+		tlsConfiguration := providerTLSConfiguration{
+			ClientCertFile: "/non/existing/cert.pem",
+			ClientKeyPEM:   clientCert.keyPEM,
+		}
+		Convey("When newHTTPClient is called", func() {
+			_, err := tlsConfiguration.newHTTPClient(false, "")
+			Convey("Then the error returned should mention the file", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldStartWith, "failed to read the 'client_cert_file' file '/non/existing/cert.pem'")
+			})
+		})
+	})
+
+	Convey("Given a providerTLSConfiguration with a CA that is not PEM encoded", t, func() {
+		tlsConfiguration := providerTLSConfiguration{
+			CAPEM: "not a certificate",
+		}
+		Convey("When newHTTPClient is called", func() {
+			_, err := tlsConfiguration.newHTTPClient(false, "")
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "failed to load the CA certificates: no PEM encoded certificates found")
+			})
+		})
+	})
+}
+
+func TestProviderTLSConfigurationIsEnabled(t *testing.T) {
+	Convey("Given an empty providerTLSConfiguration", t, func() {
+		tlsConfiguration := providerTLSConfiguration{}
+		Convey("When isEnabled is called", func() {
+			Convey("Then the result should be false", func() {
+				So(tlsConfiguration.isEnabled(), ShouldBeFalse)
+			})
+		})
+	})
+	Convey("Given a providerTLSConfiguration with only the CA file", t, func() {
+		tlsConfiguration := providerTLSConfiguration{CAFile: "ca.pem"}
+		Convey("When isEnabled is called", func() {
+			Convey("Then the result should be true", func() {
+				So(tlsConfiguration.isEnabled(), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestNewCABundleCertPool(t *testing.T) {
+	ca := newTestCertificate(t, "ca", nil, true, x509.ExtKeyUsageAny)
+	serverCert := newTestCertificate(t, "127.0.0.1", ca, false, x509.ExtKeyUsageServerAuth)
+	api := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	api.TLS = &tls.Config{Certificates: []tls.Certificate{serverCert.keyPair}}
+	api.StartTLS()
+	defer api.Close()
+
+	Convey("Given a providerFactory with a service configuration containing the CA bundle that issued the API server certificate", t, func() {
+		caBundle := writeTestFile(t, ca.certPEM)
+		defer os.Remove(caBundle)
+		p := providerFactory{serviceConfiguration: &ServiceConfigStub{CABundle: caBundle}}
+		Convey("When createHTTPClient is called and the client calls the API", func() {
+			httpClient, err := p.createHTTPClient(&providerConfiguration{})
+			So(err, ShouldBeNil)
+			resp, err := httpClient.Get(api.URL)
+			Convey("Then the call should succeed since the API server certificate is trusted", func() {
+				So(err, ShouldBeNil)
+				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+			})
+		})
+	})
+
+	Convey("Given a CA bundle that does not contain PEM encoded certificates", t, func() {
+		caBundle := writeTestFile(t, "not a certificate")
+		defer os.Remove(caBundle)
+		Convey("When newCABundleCertPool is called", func() {
+			_, err := newCABundleCertPool(caBundle)
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "failed to load the CA bundle '"+caBundle+"': no PEM encoded certificates found")
+			})
+		})
+	})
+
+	Convey("Given a CA bundle that does not exist", t, func() {
+		Convey("When newCABundleCertPool is called", func() {
+			_, err := newCABundleCertPool("/non/existing/ca.pem")
+			Convey("Then the error returned should mention the file", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldStartWith, "failed to read the CA bundle '/non/existing/ca.pem'")
+			})
+		})
+	})
+}
+
+func TestCreateHTTPClient_TLSMinVersion(t *testing.T) {
+	ca := newTestCertificate(t, "ca", nil, true, x509.ExtKeyUsageAny)
+	serverCert := newTestCertificate(t, "127.0.0.1", ca, false, x509.ExtKeyUsageServerAuth)
+	api := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	api.TLS = &tls.Config{Certificates: []tls.Certificate{serverCert.keyPair}, MaxVersion: tls.VersionTLS12}
+	api.StartTLS()
+	defer api.Close()
+	caBundle := writeTestFile(t, ca.certPEM)
+	defer os.Remove(caBundle)
+
+	Convey("Given a providerFactory with a service configuration containing a TLS min version higher than the max version supported by the API", t, func() {
+		p := providerFactory{serviceConfiguration: &ServiceConfigStub{CABundle: caBundle, TLSMinVersion: tls.VersionTLS13}}
+		Convey("When createHTTPClient is called and the client calls the API", func() {
+			httpClient, err := p.createHTTPClient(&providerConfiguration{})
+			So(err, ShouldBeNil)
+			_, err = httpClient.Get(api.URL)
+			Convey("Then the call should fail since the TLS version can not be negotiated", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given a providerFactory with a service configuration containing a TLS min version and cipher suites supported by the API", t, func() {
+		p := providerFactory{serviceConfiguration: &ServiceConfigStub{CABundle: caBundle, TLSMinVersion: tls.VersionTLS12, TLSCipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}}}
+		Convey("When createHTTPClient is called and the client calls the API", func() {
+			httpClient, err := p.createHTTPClient(&providerConfiguration{})
+			So(err, ShouldBeNil)
+			resp, err := httpClient.Get(api.URL)
+			Convey("Then the call should succeed using the configured TLS version and cipher suite", func() {
+				So(err, ShouldBeNil)
+				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+				So(resp.TLS.Version, ShouldEqual, tls.VersionTLS12)
+				So(resp.TLS.CipherSuite, ShouldEqual, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256)
+			})
+		})
+	})
+}
@@ -0,0 +1,109 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// credentialHelperExpiryLeeway makes sure the credential helper is executed again before the credentials expire, so the
+// API calls made in the middle of an apply are not rejected
+const credentialHelperExpiryLeeway = 30 * time.Second
+
+// credentialHelperOutput describes the JSON document the credential helper command prints to the standard output. The
+// expiry is optional and RFC 3339 formatted (e.g. 2020-01-01T10:00:00Z)
+type credentialHelperOutput struct {
+	Headers map[string]string `json:"headers"`
+	Expiry  *time.Time        `json:"expiry,omitempty"`
+}
+
+// credentialHelper executes the credential helper command configured in the service configuration and keeps the headers
+// it returns until they are about to expire, so the command is not executed for every API call. It is shared by all the
+// provider clients configured during the run
+type credentialHelper struct {
+	sync.Mutex
+	config  ServiceCredentialHelper
+	headers map[string]string
+	expiry  time.Time
+	now     func() time.Time
+}
+
+// newCredentialHelper returns the credential helper for the given configuration; nil if not configured
+func newCredentialHelper(config *ServiceCredentialHelper) *credentialHelper {
+	if config == nil {
+		return nil
+	}
+	return &credentialHelper{
+		config: *config,
+		now:    time.Now,
+	}
+}
+
+// getHeaders returns the headers returned by the command, executing it if it has not been executed yet or the headers
+// are about to expire. The lock is held while the command executes so concurrent API calls wait for its output instead of
+// executing the command each. The returned bool is true if the headers were obtained in a previous execution
+func (c *credentialHelper) getHeaders() (map[string]string, bool, error) {
+	c.Lock()
+	defer c.Unlock()
+	if c.headers != nil && (c.expiry.IsZero() || c.now().Add(credentialHelperExpiryLeeway).Before(c.expiry)) {
+		return c.headers, true, nil
+	}
+	output, err := c.execute()
+	if err != nil {
+		return nil, false, err
+	}
+	c.headers = output.Headers
+	c.expiry = time.Time{}
+	if output.Expiry != nil {
+		c.expiry = *output.Expiry
+	}
+	return c.headers, false, nil
+}
+
+// invalidate discards the headers obtained so the command is executed again for the next API call (e.g. the API
+// rejected the credentials)
+func (c *credentialHelper) invalidate() {
+	c.Lock()
+	defer c.Unlock()
+	if c.headers != nil {
+		log.Printf("[INFO] the credentials returned by the credential helper command '%s' were rejected by the API, the command will be executed again", c.config.Command)
+		c.headers = nil
+	}
+}
+
+func (c *credentialHelper) execute() (*credentialHelperOutput, error) {
+	start := time.Now()
+	log.Printf("[INFO] executing credential helper command '%s'", c.config.Command)
+	executor := commandExecutor{shell: commandShell(c.config.Shell), env: c.config.Env, timeout: c.config.Timeout}
+	if c.config.WorkingDir != "" {
+		dir, err := expandPath(c.config.WorkingDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve the working directory '%s' of the credential helper command: %s", c.config.WorkingDir, err)
+		}
+		executor.workingDir = dir
+	}
+	result, err := executor.run(c.config.Command, nil)
+	if result.timedOut {
+		return nil, fmt.Errorf("credential helper command '%s' did not finish executing within the expected time %s (%s)", c.config.Command, c.config.Timeout, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute credential helper command '%s': %s (%s)", c.config.Command, strings.TrimSpace(result.stderr), err)
+	}
+	output := &credentialHelperOutput{}
+	if err := json.Unmarshal([]byte(result.stdout), output); err != nil {
+		return nil, fmt.Errorf("credential helper command '%s' output is not valid, expected a JSON document like {\"headers\": {...}, \"expiry\": \"2020-01-01T10:00:00Z\"}: %s", c.config.Command, err)
+	}
+	if len(output.Headers) == 0 {
+		return nil, fmt.Errorf("credential helper command '%s' output does not contain any headers", c.config.Command)
+	}
+	for headerName := range output.Headers {
+		if !isValidHeaderName(headerName) {
+			return nil, fmt.Errorf("credential helper command '%s' output contains the header '%s' which is not a valid header name", c.config.Command, headerName)
+		}
+	}
+	log.Printf("[INFO] credential helper command '%s' executed successfully (time:%s), %d headers obtained", c.config.Command, time.Since(start), len(output.Headers))
+	return output, nil
+}
@@ -0,0 +1,151 @@
+package openapi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCountingCredentialHelperCommand returns a command that prints the given output, where %d is replaced with the number
+// of times the command has been executed (tracked in the given file)
+func newCountingCredentialHelperCommand(executionsFile, output string) []string {
+	return []string{"sh", "-c", fmt.Sprintf(`echo x >> %s; n=$(wc -l < %s | tr -d ' '); printf '%s' "$n"`, executionsFile, executionsFile, strings.Replace(output, "%d", "%s", -1))}
+}
+
+func TestCredentialHelper_GetHeaders(t *testing.T) {
+	now := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name               string
+		expiry             string
+		elapsed            time.Duration
+		expectedExecutions int
+	}{
+		{
+			name:               "the headers with no expiry are reused for the whole run",
+			expiry:             "",
+			elapsed:            24 * time.Hour,
+			expectedExecutions: 1,
+		},
+		{
+			name:               "the headers are reused while they are not about to expire",
+			expiry:             `, "expiry": "2020-01-01T11:00:00Z"`,
+			elapsed:            30 * time.Minute,
+			expectedExecutions: 1,
+		},
+		{
+			name:               "the command is executed again when the headers are about to expire",
+			expiry:             `, "expiry": "2020-01-01T11:00:00Z"`,
+			elapsed:            59*time.Minute + 45*time.Second,
+			expectedExecutions: 2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			executionsFile, err := ioutil.TempFile("", "")
+			require.NoError(t, err)
+			defer os.Remove(executionsFile.Name())
+			helper := newCredentialHelper(&ServiceCredentialHelper{
+				Command: newCountingCredentialHelperCommand(executionsFile.Name(), `{"headers": {"Authorization": "Bearer token%d", "X-Tenant": "tenant"}`+tc.expiry+`}`),
+				Timeout: 10 * time.Second,
+			})
+			helper.now = func() time.Time { return now }
+
+			headers, cached, err := helper.getHeaders()
+			require.NoError(t, err)
+			assert.False(t, cached)
+			assert.Equal(t, map[string]string{"Authorization": "Bearer token1", "X-Tenant": "tenant"}, headers)
+
+			helper.now = func() time.Time { return now.Add(tc.elapsed) }
+			headers, cached, err = helper.getHeaders()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedExecutions == 1, cached)
+			assert.Equal(t, fmt.Sprintf("Bearer token%d", tc.expectedExecutions), headers["Authorization"])
+		})
+	}
+}
+
+func TestCredentialHelper_Invalidate(t *testing.T) {
+	executionsFile, err := ioutil.TempFile("", "")
+	require.NoError(t, err)
+	defer os.Remove(executionsFile.Name())
+	helper := newCredentialHelper(&ServiceCredentialHelper{
+		Command: newCountingCredentialHelperCommand(executionsFile.Name(), `{"headers": {"Authorization": "Bearer token%d"}}`),
+		Timeout: 10 * time.Second,
+	})
+	_, _, err = helper.getHeaders()
+	require.NoError(t, err)
+	helper.invalidate()
+	headers, cached, err := helper.getHeaders()
+	require.NoError(t, err)
+	assert.False(t, cached)
+	assert.Equal(t, "Bearer token2", headers["Authorization"])
+}
+
+func TestCredentialHelper_Errors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		command       []string
+		expectedError string
+	}{
+		{
+			name:          "the command fails",
+			command:       []string{"sh", "-c", "echo 'please log in first' >&2; exit 1"},
+			expectedError: "failed to execute credential helper command '[sh -c echo 'please log in first' >&2; exit 1]': please log in first (exit status 1)",
+		},
+		{
+			name:          "the command output is not a JSON document",
+			command:       []string{"echo", "token"},
+			expectedError: "credential helper command '[echo token]' output is not valid, expected a JSON document like",
+		},
+		{
+			name:          "the command output does not contain headers",
+			command:       []string{"echo", `{"expiry": "2020-01-01T11:00:00Z"}`},
+			expectedError: `credential helper command '[echo {"expiry": "2020-01-01T11:00:00Z"}]' output does not contain any headers`,
+		},
+		{
+			name:          "the command output contains a header name that is not valid",
+			command:       []string{"echo", `{"headers": {"X Tenant": "tenant"}}`},
+			expectedError: "output contains the header 'X Tenant' which is not a valid header name",
+		},
+		{
+			name:          "the command output contains an expiry that is not valid",
+			command:       []string{"echo", `{"headers": {"Authorization": "Bearer token"}, "expiry": "tomorrow"}`},
+			expectedError: "output is not valid",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper := newCredentialHelper(&ServiceCredentialHelper{Command: tc.command, Timeout: 10 * time.Second})
+			_, _, err := helper.getHeaders()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestCredentialHelper_Shell(t *testing.T) {
+	workingDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(workingDir)
+	require.NoError(t, ioutil.WriteFile(workingDir+"/tenant", []byte("tenant"), 0600))
+	helper := newCredentialHelper(&ServiceCredentialHelper{
+		Command:    []string{`printf '{"headers": {"Authorization": "Bearer %s", "X-Tenant": "%s"}}'`, "$TOKEN", "$(cat tenant)"},
+		Timeout:    10 * time.Second,
+		Shell:      "sh",
+		WorkingDir: workingDir,
+		Env:        map[string]string{"TOKEN": "token"},
+	})
+	headers, _, err := helper.getHeaders()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer token", "X-Tenant": "tenant"}, headers)
+}
+
+func TestNewCredentialHelper_NotConfigured(t *testing.T) {
+	assert.Nil(t, newCredentialHelper(nil))
+}
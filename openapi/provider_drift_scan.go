@@ -0,0 +1,461 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// DriftScanCommand is the command that makes the provider binary compare the objects returned by the API with the ones
+// tracked in a terraform state file instead of serving the plugin (e.g. terraform-provider-<provider_name> drift-scan
+// terraform.tfstate [resource_type_filter...])
+const DriftScanCommand = "drift-scan"
+
+// terraformStateSupportedVersion is the only terraform state file format version supported by the drift scan, which is
+// the one used since terraform 0.12
+const terraformStateSupportedVersion = 4
+
+// terraformState contains the subset of the terraform state file used by the drift scan
+type terraformState struct {
+	Version   int                      `json:"version"`
+	Resources []terraformStateResource `json:"resources"`
+}
+
+type terraformStateResource struct {
+	Module    string                   `json:"module"`
+	Mode      string                   `json:"mode"`
+	Type      string                   `json:"type"`
+	Name      string                   `json:"name"`
+	Instances []terraformStateInstance `json:"instances"`
+}
+
+type terraformStateInstance struct {
+	IndexKey   interface{}            `json:"index_key"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// driftScanStateInstance is a resource instance tracked in the state, along with its address in the configuration
+// (e.g. module.cdns.openapi_cdns_v1.my_cdn[0])
+type driftScanStateInstance struct {
+	address    string
+	attributes map[string]interface{}
+}
+
+// driftScanResource is a resource exposed by the provider, along with the name it is registered with
+type driftScanResource struct {
+	name     string
+	resource SpecResource
+}
+
+// driftScanner lists the objects of the resources via the collection GET operations and compares them with the ones
+// tracked in the terraform state
+type driftScanner struct {
+	client    ClientOpenAPI
+	resources []driftScanResource
+}
+
+// driftScanResult contains the number of objects found for each of the drift categories
+type driftScanResult struct {
+	unmanaged int
+	drifted   int
+	missing   int
+}
+
+func (r driftScanResult) driftFound() bool {
+	return r.unmanaged > 0 || r.drifted > 0 || r.missing > 0
+}
+
+// DriftScan writes into the given writer the objects returned by the API that differ from the ones tracked in the given
+// terraform state file:
+// - unmanaged objects: the objects returned by the collection GET operation of the resources that are not in the state
+// - drifted objects: the objects in the state whose properties were changed out of band
+// - missing objects: the objects in the state that are no longer returned by the API
+// Only the resources matching the filters (e.g. openapi_cdns_*) are scanned, all of them if no filter is provided. The
+// provider is configured with the values of the provider properties environment variables (e.g. APIKEY_AUTH) and the
+// plugin configuration file, the same way terraform would if the properties were not set in the provider block. The
+// returned bool is true if any drift was found.
+func (p *ProviderOpenAPI) DriftScan(w io.Writer, stateFile string, resourceTypeFilters []string) (bool, error) {
+	state, err := loadTerraformState(stateFile)
+	if err != nil {
+		return false, err
+	}
+	serviceConfiguration, err := getServiceConfiguration(p.ProviderName)
+	if err != nil {
+		return false, fmt.Errorf("plugin init error: %s", err)
+	}
+	openAPISpecAnalyser, err := p.createSpecAnalyser(serviceConfiguration)
+	if err != nil {
+		return false, wrapError(err, "plugin OpenAPI spec analyser error")
+	}
+	providerFactory, err := newProviderFactory(p.ProviderName, openAPISpecAnalyser, serviceConfiguration)
+	if err != nil {
+		return false, fmt.Errorf("plugin provider factory init error: %s", err)
+	}
+	provider, err := providerFactory.createProvider()
+	if err != nil {
+		return false, wrapError(err, "plugin terraform-provider-%s init error while creating schema provider", p.ProviderName)
+	}
+	if err := diagnosticsError(provider.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{}))); err != nil {
+		return false, fmt.Errorf("failed to configure the provider with the values of the provider properties environment variables: %s", err)
+	}
+	client, ok := provider.Meta().(ClientOpenAPI)
+	if !ok {
+		return false, fmt.Errorf("failed to configure the provider: unexpected client type %T", provider.Meta())
+	}
+	resources, err := providerFactory.getDriftScanResources()
+	if err != nil {
+		return false, err
+	}
+	return driftScanner{client: client, resources: resources}.scan(w, state, resourceTypeFilters)
+}
+
+// getDriftScanResources returns the resources registered in the provider sorted by name
+func (p providerFactory) getDriftScanResources() ([]driftScanResource, error) {
+	openAPIResources, err := p.specAnalyser.GetTerraformCompliantResources()
+	if err != nil {
+		return nil, err
+	}
+	namedResources, err := p.resolveDuplicateResourceNames(openAPIResources)
+	if err != nil {
+		return nil, err
+	}
+	var resources []driftScanResource
+	for _, namedResource := range namedResources {
+		resourceName, err := p.getProviderResourceName(namedResource.name)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, driftScanResource{name: resourceName, resource: namedResource.resource})
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].name < resources[j].name })
+	return resources, nil
+}
+
+// loadTerraformState reads the given terraform state file. The numbers are kept as json.Number so large integers (e.g.
+// ids) are compared exactly
+func loadTerraformState(stateFile string) (*terraformState, error) {
+	content, err := ioutil.ReadFile(stateFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the terraform state file '%s': %s", stateFile, err)
+	}
+	state := &terraformState{}
+	if err := decodeJSONPayload(content, state); err != nil {
+		return nil, fmt.Errorf("failed to parse the terraform state file '%s': %s", stateFile, err)
+	}
+	if state.Version != terraformStateSupportedVersion {
+		return nil, fmt.Errorf("terraform state file '%s' format version %d not supported, only version %d (terraform 0.12 and later) is supported", stateFile, state.Version, terraformStateSupportedVersion)
+	}
+	return state, nil
+}
+
+// getManagedInstances returns the instances of the given resource type tracked in the state, sorted by address
+func (s *terraformState) getManagedInstances(resourceType string) []driftScanStateInstance {
+	var instances []driftScanStateInstance
+	for _, resource := range s.Resources {
+		if resource.Mode != "managed" || resource.Type != resourceType {
+			continue
+		}
+		address := fmt.Sprintf("%s.%s", resource.Type, resource.Name)
+		if resource.Module != "" {
+			address = fmt.Sprintf("%s.%s", resource.Module, address)
+		}
+		for _, instance := range resource.Instances {
+			instanceAddress := address
+			switch indexKey := instance.IndexKey.(type) {
+			case json.Number:
+				instanceAddress = fmt.Sprintf("%s[%s]", address, indexKey)
+			case string:
+				instanceAddress = fmt.Sprintf("%s[%q]", address, indexKey)
+			}
+			instances = append(instances, driftScanStateInstance{address: instanceAddress, attributes: instance.Attributes})
+		}
+	}
+	sort.Slice(instances, func(i, j int) bool { return instances[i].address < instances[j].address })
+	return instances
+}
+
+func (s driftScanner) scan(w io.Writer, state *terraformState, resourceTypeFilters []string) (bool, error) {
+	for _, filter := range resourceTypeFilters {
+		if _, err := path.Match(filter, ""); err != nil {
+			return false, fmt.Errorf("resource type filter '%s' is not valid: %s", filter, err)
+		}
+	}
+	total := driftScanResult{}
+	scanned := 0
+	var failed []string
+	for _, r := range s.resources {
+		if !matchesResourceTypeFilters(r.name, resourceTypeFilters) {
+			continue
+		}
+		scanned++
+		result, err := s.scanResource(w, r, state.getManagedInstances(r.name))
+		if err != nil {
+			fmt.Fprintf(w, "%s: scan failed: %s\n", r.name, err)
+			failed = append(failed, r.name)
+			continue
+		}
+		total.unmanaged += result.unmanaged
+		total.drifted += result.drifted
+		total.missing += result.missing
+	}
+	if scanned == 0 {
+		return false, fmt.Errorf("none of the provider resources matches the resource type filters %s", resourceTypeFilters)
+	}
+	fmt.Fprintf(w, "\nDrift scan summary: %d resource types scanned, %d unmanaged, %d drifted and %d missing objects\n", scanned, total.unmanaged, total.drifted, total.missing)
+	if len(failed) > 0 {
+		return total.driftFound(), fmt.Errorf("the following resource types could not be scanned: %s", strings.Join(failed, ", "))
+	}
+	return total.driftFound(), nil
+}
+
+// matchesResourceTypeFilters checks whether the given resource type matches any of the filters, which support the
+// shell file name pattern syntax (e.g. openapi_cdns_*). Every resource type matches if there are no filters.
+func matchesResourceTypeFilters(resourceType string, resourceTypeFilters []string) bool {
+	if len(resourceTypeFilters) == 0 {
+		return true
+	}
+	for _, filter := range resourceTypeFilters {
+		if matched, _ := path.Match(filter, resourceType); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// scanResource lists the objects of the given resource and compares them with the instances tracked in the state. The
+// objects of sub-resources (and resources identified by a composite id) can only be listed for the parents referenced by
+// the instances in the state, so the unmanaged objects of other parents are not reported.
+func (s driftScanner) scanResource(w io.Writer, r driftScanResource, instances []driftScanStateInstance) (driftScanResult, error) {
+	result := driftScanResult{}
+	if r.resource.getResourceOperations().List == nil {
+		fmt.Fprintf(w, "%s: skipped, the resource does not have a collection GET operation\n", r.name)
+		return result, nil
+	}
+	resourceSchema, err := r.resource.getResourceSchema()
+	if err != nil {
+		return result, err
+	}
+
+	stateObjects := map[string]driftScanStateInstance{}
+	stateObjectKeys := make([]string, len(instances))
+	parentIDsList := [][]string{}
+	parentIDsSeen := map[string]bool{}
+	for i, instance := range instances {
+		parentIDs, err := getDriftScanParentIDs(r.resource, resourceSchema, instance.attributes)
+		if err != nil {
+			return result, fmt.Errorf("%s: %s", instance.address, err)
+		}
+		stateObjectKeys[i] = getDriftScanObjectKey(r.resource, parentIDs, identifierValueToString(instance.attributes["id"]))
+		stateObjects[stateObjectKeys[i]] = instance
+		if key := strings.Join(parentIDs, "/"); !parentIDsSeen[key] {
+			parentIDsSeen[key] = true
+			parentIDsList = append(parentIDsList, parentIDs)
+		}
+	}
+	if len(r.resource.getCompositeID()) == 0 && r.resource.getParentResourceInfo() == nil {
+		parentIDsList = [][]string{{}}
+	}
+	if len(parentIDsList) == 0 {
+		fmt.Fprintf(w, "%s: skipped, the objects can only be listed for the parents referenced in the state and there are none\n", r.name)
+		return result, nil
+	}
+
+	liveObjects := map[string]map[string]interface{}{}
+	for _, parentIDs := range parentIDsList {
+		items, err := s.listObjects(r.resource, parentIDs)
+		if err != nil {
+			return result, err
+		}
+		for _, item := range items {
+			id, err := getDriftScanObjectID(r.resource, resourceSchema, item)
+			if err != nil {
+				return result, err
+			}
+			liveObjects[getDriftScanObjectKey(r.resource, parentIDs, id)] = item
+		}
+	}
+
+	fmt.Fprintf(w, "%s: %d objects in the API, %d in the state\n", r.name, len(liveObjects), len(stateObjects))
+	for _, key := range sortedKeys(liveObjects) {
+		if _, managed := stateObjects[key]; !managed {
+			fmt.Fprintf(w, "  + unmanaged  %s\n", key)
+			result.unmanaged++
+		}
+	}
+	for i, instance := range instances {
+		key := stateObjectKeys[i]
+		liveObject, exists := liveObjects[key]
+		if !exists {
+			fmt.Fprintf(w, "  - missing    %s (%s)\n", key, instance.address)
+			result.missing++
+			continue
+		}
+		if differences := getDriftScanDifferences(resourceSchema, instance.attributes, liveObject); len(differences) > 0 {
+			fmt.Fprintf(w, "  ~ drifted    %s (%s)\n", key, instance.address)
+			for _, difference := range differences {
+				fmt.Fprintf(w, "      %s\n", difference)
+			}
+			result.drifted++
+		}
+	}
+	return result, nil
+}
+
+func (s driftScanner) listObjects(resource SpecResource, parentIDs []string) ([]map[string]interface{}, error) {
+	items := []map[string]interface{}{}
+	res, err := s.client.List(resource, &items, parentIDs...)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkHTTPStatusCode(resource, res, []int{http.StatusOK}); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// getDriftScanParentIDs returns the ids needed to resolve the collection path of the given state instance, that is the
+// parent ids for sub-resources or all the composite id values but the last one for resources identified by a composite id
+func getDriftScanParentIDs(resource SpecResource, resourceSchema *specSchemaDefinition, attributes map[string]interface{}) ([]string, error) {
+	if compositeID := resource.getCompositeID(); len(compositeID) > 0 {
+		var parentIDs []string
+		for _, propertyName := range compositeID[:len(compositeID)-1] {
+			property, err := resourceSchema.getProperty(propertyName)
+			if err != nil {
+				return nil, err
+			}
+			value := attributes[property.getTerraformCompliantPropertyName()]
+			if value == nil {
+				return nil, fmt.Errorf("missing the value of the composite id property '%s'", propertyName)
+			}
+			parentIDs = append(parentIDs, identifierValueToString(value))
+		}
+		return parentIDs, nil
+	}
+	parentResourceInfo := resource.getParentResourceInfo()
+	if parentResourceInfo == nil {
+		return []string{}, nil
+	}
+	var parentIDs []string
+	for _, parentPropertyName := range parentResourceInfo.getParentPropertiesNames() {
+		value := attributes[parentPropertyName]
+		if value == nil {
+			return nil, fmt.Errorf("missing the value of the parent property '%s'", parentPropertyName)
+		}
+		parentIDs = append(parentIDs, identifierValueToString(value))
+	}
+	return parentIDs, nil
+}
+
+// getDriftScanObjectID returns the id terraform would store in the state for the given object returned by the API
+func getDriftScanObjectID(resource SpecResource, resourceSchema *specSchemaDefinition, object map[string]interface{}) (string, error) {
+	if compositeID := resource.getCompositeID(); len(compositeID) > 0 {
+		var idValues []string
+		for _, propertyName := range compositeID {
+			if object[propertyName] == nil {
+				return "", fmt.Errorf("object returned by the API is missing the composite id property '%s'", propertyName)
+			}
+			idValues = append(idValues, identifierValueToString(object[propertyName]))
+		}
+		return strings.Join(idValues, "/"), nil
+	}
+	identifierProperty, err := resourceSchema.getResourceIdentifier()
+	if err != nil {
+		return "", err
+	}
+	if object[identifierProperty] == nil {
+		return "", fmt.Errorf("object returned by the API is missing the identifier property '%s'", identifierProperty)
+	}
+	return identifierValueToString(object[identifierProperty]), nil
+}
+
+// getDriftScanObjectKey returns the key used to match the objects returned by the API with the ones in the state. The
+// ids of the sub-resource objects are prefixed with the parent ids since they are only unique within the parent
+func getDriftScanObjectKey(resource SpecResource, parentIDs []string, id string) string {
+	if resource.getParentResourceInfo() == nil {
+		return id
+	}
+	return strings.Join(append(append([]string{}, parentIDs...), id), "/")
+}
+
+// getDriftScanDifferences returns the properties whose value in the state differs from the one returned by the API. Only
+// primitive properties and lists of primitives are compared, and only if the API returned them (the collection GET
+// operation may only return a summary of the objects). The values of sensitive properties are not shown.
+func getDriftScanDifferences(resourceSchema *specSchemaDefinition, attributes map[string]interface{}, object map[string]interface{}) []string {
+	var differences []string
+	for _, property := range resourceSchema.Properties {
+		if property.isPropertyNamedID() || property.IsParentProperty {
+			continue
+		}
+		if !property.isPrimitiveProperty() && !(property.isArrayProperty() && isPrimitiveType(property.ArrayItemsType)) {
+			continue
+		}
+		liveValue, exists := object[property.Name]
+		if !exists {
+			continue
+		}
+		stateValue, exists := attributes[property.getTerraformCompliantPropertyName()]
+		if !exists {
+			continue
+		}
+		formattedStateValue := formatDriftScanValue(stateValue, property.isSetProperty())
+		formattedLiveValue := formatDriftScanValue(liveValue, property.isSetProperty())
+		if formattedStateValue == formattedLiveValue {
+			continue
+		}
+		if property.Sensitive {
+			differences = append(differences, fmt.Sprintf("%s: (sensitive value changed)", property.getTerraformCompliantPropertyName()))
+			continue
+		}
+		differences = append(differences, fmt.Sprintf("%s: %s => %s", property.getTerraformCompliantPropertyName(), formattedStateValue, formattedLiveValue))
+	}
+	sort.Strings(differences)
+	return differences
+}
+
+func isPrimitiveType(propertyType schemaDefinitionPropertyType) bool {
+	return propertyType == typeString || propertyType == typeInt || propertyType == typeFloat || propertyType == typeBool
+}
+
+// formatDriftScanValue returns the given state or API value formatted so both can be compared (e.g. the number 1 is the
+// same whether it comes as 1 or 1.0). The items of sets are sorted since their order is not relevant
+func formatDriftScanValue(value interface{}, isSet bool) string {
+	switch v := value.(type) {
+	case nil:
+		return `""`
+	case json.Number:
+		// integers are compared as they come so large ids do not lose precision
+		if !strings.ContainsAny(v.String(), ".eE") {
+			return v.String()
+		}
+		if f, err := v.Float64(); err == nil {
+			return strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case string:
+		return strconv.Quote(v)
+	case []interface{}:
+		items := []string{}
+		for _, item := range v {
+			items = append(items, formatDriftScanValue(item, false))
+		}
+		if isSet {
+			sort.Strings(items)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,200 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDriftScanStateFile(t *testing.T, content string) string {
+	stateFile, err := ioutil.TempFile("", "terraform.tfstate")
+	require.NoError(t, err)
+	_, err = stateFile.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, stateFile.Close())
+	return stateFile.Name()
+}
+
+func TestDriftScannerScan(t *testing.T) {
+	cdnResource := newSpecStubResource("cdn_v1", "/v1/cdns", false, &specSchemaDefinition{
+		Properties: specSchemaDefinitionProperties{
+			idProperty,
+			newStringSchemaDefinitionPropertyWithDefaults("label", "", true, false, nil),
+			newStringSchemaDefinitionProperty("password", "", false, false, false, false, true, false, false, false, nil),
+			newIntSchemaDefinitionPropertyWithDefaults("port", "", false, false, nil),
+			newListSchemaDefinitionPropertyWithDefaults("ips", "", false, false, false, nil, typeString, nil),
+		},
+	})
+	cdnResource.resourceListOperation = &specResourceOperation{}
+	noListResource := newSpecStubResource("lb_v1", "/v1/lbs", false, &specSchemaDefinition{Properties: specSchemaDefinitionProperties{idProperty}})
+
+	stateFile := newDriftScanStateFile(t, `{
+  "version": 4,
+  "resources": [
+    {"mode": "managed", "type": "openapi_cdn_v1", "name": "my_cdn", "instances": [{"attributes": {"id": "cdn-1", "label": "label", "password": "secret", "port": 8080, "ips": ["10.0.0.1", "10.0.0.2"]}}]},
+    {"module": "module.cdns", "mode": "managed", "type": "openapi_cdn_v1", "name": "cdn", "instances": [{"index_key": 0, "attributes": {"id": "cdn-2", "label": "other label", "port": 80}}]},
+    {"mode": "data", "type": "openapi_cdn_v1", "name": "cdn", "instances": [{"attributes": {"id": "cdn-4"}}]}
+  ]
+}`)
+	defer os.Remove(stateFile)
+	state, err := loadTerraformState(stateFile)
+	require.NoError(t, err)
+
+	client := &clientOpenAPIStub{
+		responseListPayload: []map[string]interface{}{
+			{"id": "cdn-1", "label": "label updated", "password": "other secret", "port": float64(8080), "ips": []interface{}{"10.0.0.1", "10.0.0.2"}},
+			{"id": "cdn-3", "label": "unmanaged"},
+		},
+	}
+	scanner := driftScanner{
+		client: client,
+		resources: []driftScanResource{
+			{name: "openapi_cdn_v1", resource: cdnResource},
+			{name: "openapi_lb_v1", resource: noListResource},
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		filters        []string
+		expectedDrift  bool
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:          "all resources are scanned when there are no filters",
+			expectedDrift: true,
+			expectedOutput: `openapi_cdn_v1: 2 objects in the API, 2 in the state
+  + unmanaged  cdn-3
+  - missing    cdn-2 (module.cdns.openapi_cdn_v1.cdn[0])
+  ~ drifted    cdn-1 (openapi_cdn_v1.my_cdn)
+      label: "label" => "label updated"
+      password: (sensitive value changed)
+openapi_lb_v1: skipped, the resource does not have a collection GET operation
+
+Drift scan summary: 2 resource types scanned, 1 unmanaged, 1 drifted and 1 missing objects
+`,
+		},
+		{
+			name:          "only the resources matching the filters are scanned",
+			filters:       []string{"openapi_lb_*"},
+			expectedDrift: false,
+			expectedOutput: `openapi_lb_v1: skipped, the resource does not have a collection GET operation
+
+Drift scan summary: 1 resource types scanned, 0 unmanaged, 0 drifted and 0 missing objects
+`,
+		},
+		{
+			name:          "no resources match the filters",
+			filters:       []string{"openapi_nope_*"},
+			expectedError: "none of the provider resources matches the resource type filters [openapi_nope_*]",
+		},
+		{
+			name:          "filter is not valid",
+			filters:       []string{"openapi_[cdn"},
+			expectedError: "resource type filter 'openapi_[cdn' is not valid: syntax error in pattern",
+		},
+	}
+	for _, tc := range testCases {
+		var output bytes.Buffer
+		driftFound, err := scanner.scan(&output, state, tc.filters)
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expectedDrift, driftFound, tc.name)
+		assert.Equal(t, tc.expectedOutput, output.String(), tc.name)
+	}
+}
+
+func TestDriftScannerScan_SubResource(t *testing.T) {
+	firewallResource := newSpecStubResource("cdn_v1_firewall", "/v1/cdns/{cdn_id}/firewalls", false, &specSchemaDefinition{
+		Properties: specSchemaDefinitionProperties{
+			idProperty,
+			newStringSchemaDefinitionPropertyWithDefaults("name", "", true, false, nil),
+		},
+	})
+	firewallResource.resourceListOperation = &specResourceOperation{}
+	firewallResource.parentResourceNames = []string{"cdn_v1"}
+	firewallResource.fullParentResourceName = "cdn_v1"
+	stateFile := newDriftScanStateFile(t, `{"version": 4, "resources": [{"mode": "managed", "type": "openapi_cdn_v1_firewall", "name": "fw", "instances": [{"attributes": {"id": "fw-1", "cdn_v1_id": "cdn-1", "name": "fw"}}]}]}`)
+	defer os.Remove(stateFile)
+	state, err := loadTerraformState(stateFile)
+	require.NoError(t, err)
+	client := &clientOpenAPIStub{responseListPayload: []map[string]interface{}{{"id": "fw-1", "name": "fw"}}}
+
+	var output bytes.Buffer
+	driftFound, err := driftScanner{client: client, resources: []driftScanResource{{name: "openapi_cdn_v1_firewall", resource: firewallResource}}}.scan(&output, state, nil)
+
+	require.NoError(t, err)
+	assert.False(t, driftFound)
+	assert.Equal(t, []string{"cdn-1"}, client.parentIDsReceived)
+	assert.Equal(t, "openapi_cdn_v1_firewall: 1 objects in the API, 1 in the state\n\nDrift scan summary: 1 resource types scanned, 0 unmanaged, 0 drifted and 0 missing objects\n", output.String())
+}
+
+func TestDriftScannerScan_ListError(t *testing.T) {
+	cdnResource := newSpecStubResource("cdn_v1", "/v1/cdns", false, &specSchemaDefinition{Properties: specSchemaDefinitionProperties{idProperty}})
+	cdnResource.resourceListOperation = &specResourceOperation{}
+	state := &terraformState{Version: 4}
+	client := &clientOpenAPIStub{error: fmt.Errorf("connection refused")}
+
+	var output bytes.Buffer
+	driftFound, err := driftScanner{client: client, resources: []driftScanResource{{name: "openapi_cdn_v1", resource: cdnResource}}}.scan(&output, state, nil)
+
+	assert.EqualError(t, err, "the following resource types could not be scanned: openapi_cdn_v1")
+	assert.False(t, driftFound)
+	assert.Equal(t, "openapi_cdn_v1: scan failed: connection refused\n\nDrift scan summary: 1 resource types scanned, 0 unmanaged, 0 drifted and 0 missing objects\n", output.String())
+}
+
+func TestLoadTerraformState(t *testing.T) {
+	testCases := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{
+			name:          "state file format version not supported",
+			content:       `{"version": 3, "modules": []}`,
+			expectedError: "terraform state file '%s' format version 3 not supported, only version 4 (terraform 0.12 and later) is supported",
+		},
+		{
+			name:          "state file is not valid json",
+			content:       `{"version": `,
+			expectedError: "failed to parse the terraform state file '%s': unexpected EOF",
+		},
+	}
+	for _, tc := range testCases {
+		stateFile := newDriftScanStateFile(t, tc.content)
+		_, err := loadTerraformState(stateFile)
+		os.Remove(stateFile)
+		assert.EqualError(t, err, fmt.Sprintf(tc.expectedError, stateFile), tc.name)
+	}
+	_, err := loadTerraformState("/non/existing/terraform.tfstate")
+	assert.EqualError(t, err, "failed to read the terraform state file '/non/existing/terraform.tfstate': open /non/existing/terraform.tfstate: no such file or directory")
+}
+
+func TestFormatDriftScanValue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    interface{}
+		isSet    bool
+		expected string
+	}{
+		{name: "large integer ids keep their precision", value: json.Number("9007199254740993"), expected: "9007199254740993"},
+		{name: "decimal numbers are normalised", value: json.Number("1.0"), expected: "1"},
+		{name: "float numbers", value: float64(1), expected: "1"},
+		{name: "strings are quoted", value: "value", expected: `"value"`},
+		{name: "nil values", value: nil, expected: `""`},
+		{name: "set items are sorted", value: []interface{}{"b", "a"}, isSet: true, expected: `["a", "b"]`},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, formatDriftScanValue(tc.value, tc.isSet), tc.name)
+	}
+}
@@ -1,12 +1,18 @@
 package openapi
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -137,9 +143,15 @@ func TestCreateProvider(t *testing.T) {
 			Convey("And the provider returned should contain the expected resource resource_v1 registered", func() {
 				So(p.ResourcesMap, ShouldContainKey, "provider_resource_v1")
 			})
+			Convey("And the provider returned should NOT contain the built-in api object resource since it is not enabled", func() {
+				So(p.ResourcesMap, ShouldNotContainKey, "provider_api_object")
+			})
 			Convey("And the provider returned should contain the expected data source resource_v1_instance registered", func() {
 				So(p.DataSourcesMap, ShouldContainKey, "provider_resource_v1_instance")
 			})
+			Convey("And the provider returned should contain the built-in provider info data source registered", func() {
+				So(p.DataSourcesMap, ShouldContainKey, "provider_provider_info")
+			})
 			Convey("And the provider should have a property for the auth", func() {
 				So(p.Schema[apiKeyAuthProperty.Name], ShouldNotBeNil)
 			})
@@ -339,6 +351,49 @@ func TestCreateProvider(t *testing.T) {
 	})
 }
 
+func TestRegisterAPIObjectResource(t *testing.T) {
+	Convey("Given a provider factory", t, func() {
+		p := providerFactory{
+			name: "provider",
+		}
+		Convey("When registerAPIObjectResource is called with a resource map that does not contain an api_object resource", func() {
+			resourceMap := map[string]*schema.Resource{"provider_resource_v1": {}}
+			p.registerAPIObjectResource(resourceMap)
+			Convey("Then the built-in api object resource should be registered", func() {
+				So(resourceMap, ShouldContainKey, "provider_api_object")
+				So(resourceMap["provider_api_object"].Schema, ShouldContainKey, apiObjectPropertyPath)
+			})
+		})
+		Convey("When registerAPIObjectResource is called with a resource map already containing an api_object resource from the OpenAPI document", func() {
+			specResource := &schema.Resource{}
+			resourceMap := map[string]*schema.Resource{"provider_api_object": specResource}
+			p.registerAPIObjectResource(resourceMap)
+			Convey("Then the resource of the OpenAPI document should be kept", func() {
+				So(resourceMap["provider_api_object"], ShouldEqual, specResource)
+			})
+		})
+	})
+	Convey("Given a provider factory with a service configuration that enables the api object resource", t, func() {
+		p := providerFactory{
+			name: "provider",
+			specAnalyser: &specAnalyserStub{
+				resources:            []SpecResource{newSpecStubResource("resource_v1", "/v1/resource", false, &specSchemaDefinition{})},
+				security:             &specSecurityStub{securityDefinitions: &SpecSecurityDefinitions{}},
+				backendConfiguration: &specStubBackendConfiguration{},
+			},
+			serviceConfiguration: &ServiceConfigStub{APIObjectResource: true},
+		}
+		Convey("When createProvider is called ", func() {
+			p, err := p.createProvider()
+			Convey("Then the provider returned should contain the built-in api object resource registered", func() {
+				So(err, ShouldBeNil)
+				So(p.ResourcesMap, ShouldContainKey, "provider_resource_v1")
+				So(p.ResourcesMap, ShouldContainKey, "provider_api_object")
+			})
+		})
+	})
+}
+
 func TestCreateValidateFunc(t *testing.T) {
 	Convey("Given a provider factory", t, func() {
 		p := providerFactory{}
@@ -379,6 +434,10 @@ func TestCreateTerraformProviderSchema(t *testing.T) {
 					ExecuteCommandCalled: false,
 				},
 			},
+			MethodOverrideHeader: "X-HTTP-Method-Override",
+			MaxParallelAPICalls:  4,
+			ProxyURL:             "http://proxy.corp.com:3128",
+			NoProxy:              ".internal.com",
 		}
 		p := providerFactory{
 			name: "provider",
@@ -411,10 +470,89 @@ func TestCreateTerraformProviderSchema(t *testing.T) {
 				So(providerSchema, ShouldContainKey, apiKeyAuthProperty.Name)
 				So(providerSchema, ShouldContainKey, headerProperty.Name)
 			})
+			Convey("And the provider schema should contain the optional disable_response_cache property", func() {
+				So(providerSchema, ShouldContainKey, providerPropertyDisableResponseCache)
+				So(providerSchema[providerPropertyDisableResponseCache].Type, ShouldEqual, schema.TypeBool)
+				So(providerSchema[providerPropertyDisableResponseCache].Optional, ShouldBeTrue)
+			})
+			Convey("And the provider schema should contain the optional full_refresh property", func() {
+				So(providerSchema, ShouldContainKey, providerPropertyFullRefresh)
+				So(providerSchema[providerPropertyFullRefresh].Type, ShouldEqual, schema.TypeBool)
+				So(providerSchema[providerPropertyFullRefresh].Optional, ShouldBeTrue)
+				So(providerSchema[providerPropertyFullRefresh].Default, ShouldEqual, false)
+			})
+			Convey("And the provider schema should contain the optional override_prevent_destroy property", func() {
+				So(providerSchema, ShouldContainKey, providerPropertyOverridePreventDestroy)
+				So(providerSchema[providerPropertyOverridePreventDestroy].Type, ShouldEqual, schema.TypeBool)
+				So(providerSchema[providerPropertyOverridePreventDestroy].Optional, ShouldBeTrue)
+			})
+			Convey("And the provider schema should contain the optional read_only property", func() {
+				So(providerSchema, ShouldContainKey, providerPropertyReadOnly)
+				So(providerSchema[providerPropertyReadOnly].Type, ShouldEqual, schema.TypeBool)
+				So(providerSchema[providerPropertyReadOnly].Optional, ShouldBeTrue)
+			})
+			Convey("And the provider schema should contain the optional method_override_header property defaulting to the service configuration value", func() {
+				So(providerSchema, ShouldContainKey, providerPropertyMethodOverrideHeader)
+				So(providerSchema[providerPropertyMethodOverrideHeader].Type, ShouldEqual, schema.TypeString)
+				So(providerSchema[providerPropertyMethodOverrideHeader].Optional, ShouldBeTrue)
+				So(providerSchema[providerPropertyMethodOverrideHeader].Default, ShouldEqual, "X-HTTP-Method-Override")
+			})
+			Convey("And the method_override_header property should only accept valid header names", func() {
+				_, errs := providerSchema[providerPropertyMethodOverrideHeader].ValidateFunc("X-HTTP-Method-Override", providerPropertyMethodOverrideHeader)
+				So(errs, ShouldBeEmpty)
+				_, errs = providerSchema[providerPropertyMethodOverrideHeader].ValidateFunc("X-HTTP Method: Override", providerPropertyMethodOverrideHeader)
+				So(errs, ShouldHaveLength, 1)
+				So(errs[0].Error(), ShouldEqual, "property 'method_override_header' value 'X-HTTP Method: Override' is not a valid header name")
+			})
+			Convey("And the provider schema should contain the optional max_parallel_api_calls property defaulting to the service configuration value", func() {
+				So(providerSchema, ShouldContainKey, providerPropertyMaxParallelAPICalls)
+				So(providerSchema[providerPropertyMaxParallelAPICalls].Type, ShouldEqual, schema.TypeInt)
+				So(providerSchema[providerPropertyMaxParallelAPICalls].Optional, ShouldBeTrue)
+				So(providerSchema[providerPropertyMaxParallelAPICalls].Default, ShouldEqual, 4)
+				_, errs := providerSchema[providerPropertyMaxParallelAPICalls].ValidateFunc(0, providerPropertyMaxParallelAPICalls)
+				So(errs, ShouldBeEmpty)
+				_, errs = providerSchema[providerPropertyMaxParallelAPICalls].ValidateFunc(-1, providerPropertyMaxParallelAPICalls)
+				So(errs, ShouldHaveLength, 1)
+				So(errs[0].Error(), ShouldEqual, "property 'max_parallel_api_calls' value '-1' is not valid, it must not be negative")
+			})
+			Convey("And the provider schema should contain the optional sensitive proxy_url and no_proxy properties defaulting to the service configuration values", func() {
+				So(providerSchema, ShouldContainKey, providerPropertyProxyURL)
+				So(providerSchema[providerPropertyProxyURL].Type, ShouldEqual, schema.TypeString)
+				So(providerSchema[providerPropertyProxyURL].Optional, ShouldBeTrue)
+				So(providerSchema[providerPropertyProxyURL].Sensitive, ShouldBeTrue)
+				So(providerSchema[providerPropertyProxyURL].Default, ShouldEqual, "http://proxy.corp.com:3128")
+				_, errs := providerSchema[providerPropertyProxyURL].ValidateFunc("ftp://proxy.corp.com", providerPropertyProxyURL)
+				So(errs, ShouldHaveLength, 1)
+				So(errs[0].Error(), ShouldEqual, "property 'proxy_url' value is not valid: proxy URL 'ftp://proxy.corp.com' scheme 'ftp' not supported, supported schemes: http, https, socks5")
+				So(providerSchema, ShouldContainKey, providerPropertyNoProxy)
+				So(providerSchema[providerPropertyNoProxy].Optional, ShouldBeTrue)
+				So(providerSchema[providerPropertyNoProxy].Default, ShouldEqual, ".internal.com")
+			})
+			Convey("And the provider schema should contain the optional mutual TLS properties with the client key PEM marked as sensitive", func() {
+				for _, propertyName := range []string{providerPropertyClientCertFile, providerPropertyClientKeyFile, providerPropertyCAFile, providerPropertyClientCertPEM, providerPropertyClientKeyPEM, providerPropertyCAPEM} {
+					So(providerSchema, ShouldContainKey, propertyName)
+					So(providerSchema[propertyName].Type, ShouldEqual, schema.TypeString)
+					So(providerSchema[propertyName].Optional, ShouldBeTrue)
+				}
+				So(providerSchema[providerPropertyClientKeyPEM].Sensitive, ShouldBeTrue)
+			})
+			Convey("And the provider schema should contain the optional swagger_url property", func() {
+				So(providerSchema, ShouldContainKey, providerPropertySwaggerURL)
+				So(providerSchema[providerPropertySwaggerURL].Type, ShouldEqual, schema.TypeString)
+				So(providerSchema[providerPropertySwaggerURL].Optional, ShouldBeTrue)
+			})
 			Convey("And the provider schema default function should not be nil", func() {
 				So(providerSchema[apiKeyAuthProperty.Name].DefaultFunc, ShouldNotBeNil)
 			})
-			Convey("And the provider schema properties commands should have been executed", func() {
+			Convey("And the provider schema properties commands should not have been executed yet", func() {
+				So(serviceConfig.SchemaConfiguration[0].ExecuteCommandCalled, ShouldBeFalse)
+				So(serviceConfig.SchemaConfiguration[1].ExecuteCommandCalled, ShouldBeFalse)
+			})
+			Convey("And the provider schema properties commands should have been executed once the values of the properties are needed", func() {
+				_, err := providerSchema[apiKeyAuthProperty.Name].DefaultFunc()
+				So(err, ShouldBeNil)
+				_, err = providerSchema[headerProperty.Name].DefaultFunc()
+				So(err, ShouldBeNil)
 				So(serviceConfig.SchemaConfiguration[0].ExecuteCommandCalled, ShouldBeTrue)
 				So(serviceConfig.SchemaConfiguration[1].ExecuteCommandCalled, ShouldBeTrue)
 			})
@@ -451,14 +589,16 @@ func TestCreateTerraformProviderSchema(t *testing.T) {
 		}
 		Convey("When createTerraformProviderSchema is called with a backend configuration that is not multi-region", func() {
 			backendConfig := &specStubBackendConfiguration{}
-			_, err := p.createTerraformProviderSchema(backendConfig, nil)
-			Convey("Then the expectedValue returned should NOT be nil", func() {
-				So(err, ShouldNotBeNil)
+			providerSchema, err := p.createTerraformProviderSchema(backendConfig, nil)
+			Convey("Then the error returned should be nil as the command is not executed until the value of the property is needed", func() {
+				So(err, ShouldBeNil)
+				So(serviceConfig.SchemaConfiguration[0].ExecuteCommandCalled, ShouldBeFalse)
 			})
-			Convey("And the error message should be the expected one", func() {
+			Convey("And the default function of the property should return the error the command failed with", func() {
+				value, err := providerSchema[apiKeyAuthProperty.Name].DefaultFunc()
+				So(err, ShouldNotBeNil)
 				So(err.Error(), ShouldEqual, expectedError)
-			})
-			Convey("And the provider schema properties commands should have been executed", func() {
+				So(value, ShouldBeNil)
 				So(serviceConfig.SchemaConfiguration[0].ExecuteCommandCalled, ShouldBeTrue)
 			})
 		})
@@ -495,8 +635,8 @@ func TestCreateTerraformProviderSchema(t *testing.T) {
 				So(providerSchema, ShouldContainKey, "api_key_auth")
 				So(providerSchema, ShouldContainKey, "other_security_definition_name")
 			})
-			Convey("And the api_key_auth should be required as it's a global scheme", func() {
-				So(providerSchema["api_key_auth"].Required, ShouldBeTrue)
+			Convey("And the api_key_auth should be optional even if it's a global scheme as the credentials are only validated when calling the operations that require them", func() {
+				So(providerSchema["api_key_auth"].Optional, ShouldBeTrue)
 			})
 			Convey("And the other_security_definition_name should be optional as it's not referred in the global schemes", func() {
 				So(providerSchema["other_security_definition_name"].Optional, ShouldBeTrue)
@@ -568,6 +708,9 @@ func TestCreateTerraformProviderSchema(t *testing.T) {
 				So(providerSchema, ShouldContainKey, providerPropertyEndPoints)
 				So(providerSchema[providerPropertyEndPoints].Elem.(*schema.Resource).Schema, ShouldContainKey, "resourceName")
 			})
+			Convey("And the provider schema should contain the wait for deletion property", func() {
+				So(providerSchema, ShouldContainKey, providerPropertyWaitForDeletion)
+			})
 		})
 	})
 	Convey("Given a provider factory (testing endpoints)", t, func() {
@@ -592,6 +735,273 @@ func TestCreateTerraformProviderSchema(t *testing.T) {
 			Convey(fmt.Sprintf("And the provider schema should NOT contain the %s property", providerPropertyEndPoints), func() {
 				So(providerSchema, ShouldNotContainKey, providerPropertyEndPoints)
 			})
+			Convey(fmt.Sprintf("And the provider schema should NOT contain the %s property", providerPropertyWaitForDeletion), func() {
+				So(providerSchema, ShouldNotContainKey, providerPropertyWaitForDeletion)
+			})
+		})
+	})
+}
+
+func TestCreateTerraformProviderSchema_BasicAuth(t *testing.T) {
+	p := providerFactory{
+		name: "provider",
+		specAnalyser: &specAnalyserStub{
+			security: &specSecurityStub{
+				securityDefinitions: &SpecSecurityDefinitions{
+					newBasicAuthSecurityDefinition("basicAuth"),
+				},
+				globalSecuritySchemes: createSecuritySchemes([]map[string][]string{{"basicAuth": []string{}}}),
+			},
+		},
+		serviceConfiguration: &ServiceConfigStub{},
+	}
+	providerSchema, err := p.createTerraformProviderSchema(&specStubBackendConfiguration{}, nil)
+	require.NoError(t, err)
+	assert.NotContains(t, providerSchema, "basic_auth")
+	require.Contains(t, providerSchema, "basic_auth_username")
+	require.Contains(t, providerSchema, "basic_auth_password")
+	assert.True(t, providerSchema["basic_auth_username"].Optional)
+	assert.False(t, providerSchema["basic_auth_username"].Sensitive)
+	assert.True(t, providerSchema["basic_auth_password"].Optional)
+	assert.True(t, providerSchema["basic_auth_password"].Sensitive)
+}
+
+func TestCreateTerraformProviderSchema_AWSSigV4(t *testing.T) {
+	p := providerFactory{
+		name: "provider",
+		specAnalyser: &specAnalyserStub{
+			security: &specSecurityStub{
+				securityDefinitions: &SpecSecurityDefinitions{
+					newAWSSigV4SecurityDefinition("awsIAM", "", ""),
+				},
+				globalSecuritySchemes: createSecuritySchemes([]map[string][]string{{"awsIAM": []string{}}}),
+			},
+		},
+		serviceConfiguration: &ServiceConfigStub{},
+	}
+	providerSchema, err := p.createTerraformProviderSchema(&specStubBackendConfiguration{}, nil)
+	require.NoError(t, err)
+	assert.NotContains(t, providerSchema, "aws_iam")
+	require.Contains(t, providerSchema, "aws_iam_access_key_id")
+	require.Contains(t, providerSchema, "aws_iam_secret_access_key")
+	require.Contains(t, providerSchema, "aws_iam_session_token")
+	assert.True(t, providerSchema["aws_iam_access_key_id"].Optional)
+	assert.False(t, providerSchema["aws_iam_access_key_id"].Sensitive)
+	assert.True(t, providerSchema["aws_iam_secret_access_key"].Optional)
+	assert.True(t, providerSchema["aws_iam_secret_access_key"].Sensitive)
+	assert.False(t, providerSchema["aws_iam_session_token"].Required)
+	assert.True(t, providerSchema["aws_iam_session_token"].Optional)
+	assert.True(t, providerSchema["aws_iam_session_token"].Sensitive)
+
+	os.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	defer os.Unsetenv("AWS_ACCESS_KEY_ID")
+	value, err := providerSchema["aws_iam_access_key_id"].DefaultFunc()
+	require.NoError(t, err)
+	assert.Equal(t, "AKIDEXAMPLE", value, "the standard AWS environment variable should be used when the provider one is not set")
+}
+
+func TestCreateTerraformProviderSchema_HMAC(t *testing.T) {
+	p := providerFactory{
+		name: "provider",
+		specAnalyser: &specAnalyserStub{
+			security: &specSecurityStub{
+				securityDefinitions: &SpecSecurityDefinitions{
+					newHMACSecurityDefinition("hmacAuth", "", "", "", ""),
+				},
+				globalSecuritySchemes: createSecuritySchemes([]map[string][]string{{"hmacAuth": []string{}}}),
+			},
+		},
+		serviceConfiguration: &ServiceConfigStub{},
+	}
+	providerSchema, err := p.createTerraformProviderSchema(&specStubBackendConfiguration{}, nil)
+	require.NoError(t, err)
+	require.Contains(t, providerSchema, "hmac_auth")
+	assert.True(t, providerSchema["hmac_auth"].Optional)
+	assert.True(t, providerSchema["hmac_auth"].Sensitive)
+}
+
+func TestCreateTerraformProviderSchema_SecondaryAPIKey(t *testing.T) {
+	p := providerFactory{
+		name: "provider",
+		specAnalyser: &specAnalyserStub{
+			security: &specSecurityStub{
+				securityDefinitions: &SpecSecurityDefinitions{
+					newSecondaryKeySecurityDefinition(newAPIKeyHeaderSecurityDefinition("apikey_auth", "X-API-Key")),
+				},
+				globalSecuritySchemes: createSecuritySchemes([]map[string][]string{{"apikey_auth": []string{}}}),
+			},
+		},
+		serviceConfiguration: &ServiceConfigStub{},
+	}
+	providerSchema, err := p.createTerraformProviderSchema(&specStubBackendConfiguration{}, nil)
+	require.NoError(t, err)
+	for _, propertyName := range []string{"apikey_auth", "apikey_auth_secondary"} {
+		require.Contains(t, providerSchema, propertyName)
+		assert.True(t, providerSchema[propertyName].Optional, propertyName)
+	}
+}
+
+func TestCreateTerraformProviderSchema_AlternativeGlobalSecurityRequirements(t *testing.T) {
+	p := providerFactory{
+		name: "provider",
+		specAnalyser: &specAnalyserStub{
+			security: &specSecurityStub{
+				securityDefinitions: &SpecSecurityDefinitions{
+					newAPIKeyHeaderSecurityDefinition("apikey_auth", authorizationHeader),
+					newAPIKeyHeaderSecurityDefinition("tenant_auth", "X-Tenant"),
+					newHMACSecurityDefinition("hmac_auth", "", "", "", ""),
+				},
+				globalSecurityRequirements: createSecurityRequirements([]map[string][]string{
+					{"apikey_auth": []string{}, "tenant_auth": []string{}},
+					{"hmac_auth": []string{}, "tenant_auth": []string{}},
+				}),
+			},
+		},
+		serviceConfiguration: &ServiceConfigStub{},
+	}
+	providerSchema, err := p.createTerraformProviderSchema(&specStubBackendConfiguration{}, nil)
+	require.NoError(t, err)
+	require.Contains(t, providerSchema, providerPropertyAuthScheme)
+	assert.True(t, providerSchema[providerPropertyAuthScheme].Optional)
+	defaultAuthScheme, err := providerSchema[providerPropertyAuthScheme].DefaultFunc()
+	require.NoError(t, err)
+	assert.Equal(t, "apikey_auth+tenant_auth", defaultAuthScheme)
+	_, errs := providerSchema[providerPropertyAuthScheme].ValidateFunc("hmac_auth+tenant_auth", providerPropertyAuthScheme)
+	assert.Empty(t, errs)
+	_, errs = providerSchema[providerPropertyAuthScheme].ValidateFunc("hmac_auth", providerPropertyAuthScheme)
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "property auth_scheme value hmac_auth is not valid, please make sure the value is one of [apikey_auth+tenant_auth hmac_auth+tenant_auth]")
+	for _, secDefName := range []string{"apikey_auth", "hmac_auth", "tenant_auth"} {
+		assert.True(t, providerSchema[secDefName].Optional, secDefName)
+	}
+}
+
+func TestCreateTerraformProviderSchema_SingleGlobalSecurityRequirement(t *testing.T) {
+	p := providerFactory{
+		name: "provider",
+		specAnalyser: &specAnalyserStub{
+			security: &specSecurityStub{
+				securityDefinitions: &SpecSecurityDefinitions{
+					newAPIKeyHeaderSecurityDefinition("apikey_auth", authorizationHeader),
+				},
+				globalSecuritySchemes: createSecuritySchemes([]map[string][]string{{"apikey_auth": []string{}}}),
+			},
+		},
+		serviceConfiguration: &ServiceConfigStub{},
+	}
+	providerSchema, err := p.createTerraformProviderSchema(&specStubBackendConfiguration{}, nil)
+	require.NoError(t, err)
+	assert.NotContains(t, providerSchema, providerPropertyAuthScheme)
+	assert.True(t, providerSchema["apikey_auth"].Optional)
+}
+
+func TestProviderFactoryGetGlobalSecuritySchemes(t *testing.T) {
+	globalSecurityRequirements := createSecurityRequirements([]map[string][]string{
+		{"apikey_auth": []string{}},
+		{"hmac_auth": []string{}, "tenant_auth": []string{}},
+		{},
+	})
+	testCases := []struct {
+		name                          string
+		globalSecurityRequirements    []SpecSecuritySchemes
+		authScheme                    string
+		expectedGlobalSecuritySchemes SpecSecuritySchemes
+		expectedError                 string
+	}{
+		{
+			name:                          "no global security requirements",
+			expectedGlobalSecuritySchemes: SpecSecuritySchemes{},
+		},
+		{
+			name:                          "auth scheme not selected defaults to the first requirement",
+			globalSecurityRequirements:    globalSecurityRequirements,
+			expectedGlobalSecuritySchemes: SpecSecuritySchemes{{Name: "apikey_auth"}},
+		},
+		{
+			name:                          "auth scheme selected",
+			globalSecurityRequirements:    globalSecurityRequirements,
+			authScheme:                    "hmac_auth+tenant_auth",
+			expectedGlobalSecuritySchemes: SpecSecuritySchemes{{Name: "hmac_auth"}, {Name: "tenant_auth"}},
+		},
+		{
+			name:                          "anonymous auth scheme selected",
+			globalSecurityRequirements:    globalSecurityRequirements,
+			authScheme:                    "none",
+			expectedGlobalSecuritySchemes: SpecSecuritySchemes{},
+		},
+		{
+			name:                       "auth scheme not supported",
+			globalSecurityRequirements: globalSecurityRequirements,
+			authScheme:                 "tenant_auth",
+			expectedError:              "property auth_scheme value tenant_auth is not valid, please make sure the value is one of [apikey_auth hmac_auth+tenant_auth none]",
+		},
+	}
+	for _, tc := range testCases {
+		p := providerFactory{
+			name: "provider",
+			specAnalyser: &specAnalyserStub{
+				security: &specSecurityStub{globalSecurityRequirements: tc.globalSecurityRequirements},
+			},
+		}
+		globalSecuritySchemes, err := p.getGlobalSecuritySchemes(&providerConfiguration{AuthScheme: tc.authScheme})
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError, tc.name)
+			assert.IsType(t, &AuthConfigError{}, err, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expectedGlobalSecuritySchemes, globalSecuritySchemes, tc.name)
+	}
+}
+
+func TestCreateTerraformProviderSchema_ReservedPropertyNames(t *testing.T) {
+	newProviderFactoryWith := func(headers SpecHeaderParameters, securityDefinitions SpecSecurityDefinitions) providerFactory {
+		return providerFactory{
+			name: "provider",
+			specAnalyser: &specAnalyserStub{
+				headers: headers,
+				security: &specSecurityStub{
+					securityDefinitions:   &securityDefinitions,
+					globalSecuritySchemes: createSecuritySchemes([]map[string][]string{}),
+				},
+			},
+			serviceConfiguration: &ServiceConfigStub{},
+		}
+	}
+	Convey("Given a provider factory with a header named as the built-in endpoints property", t, func() {
+		p := newProviderFactoryWith(SpecHeaderParameters{SpecHeaderParam{Name: "endpoints"}}, SpecSecurityDefinitions{})
+		Convey("When createTerraformProviderSchema is called", func() {
+			_, err := p.createTerraformProviderSchema(&specStubBackendConfiguration{host: "api.${region}.hostname.com"}, nil)
+			Convey("Then the error returned should point to the colliding header", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "header parameter 'endpoints' collides with the provider's built-in property 'endpoints', please use the 'x-terraform-header' extension to expose the header with a different name")
+			})
+		})
+	})
+	Convey("Given a provider factory with a header named as the built-in region property", t, func() {
+		p := newProviderFactoryWith(SpecHeaderParameters{SpecHeaderParam{Name: "Region"}}, SpecSecurityDefinitions{})
+		Convey("When createTerraformProviderSchema is called with a multi-region backend configuration", func() {
+			_, err := p.createTerraformProviderSchema(&specStubBackendConfiguration{host: "api.${region}.hostname.com", regions: []string{"rst1"}}, nil)
+			Convey("Then the error returned should point to the colliding header", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "header parameter 'Region' collides with the provider's built-in property 'region', please use the 'x-terraform-header' extension to expose the header with a different name")
+			})
+		})
+		Convey("When createTerraformProviderSchema is called with a backend configuration that is not multi-region", func() {
+			_, err := p.createTerraformProviderSchema(&specStubBackendConfiguration{host: "api.${region}.hostname.com"}, nil)
+			Convey("Then the error returned should be nil since the provider does not expose the region property", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+	Convey("Given a provider factory with a security definition named as a built-in property", t, func() {
+		p := newProviderFactoryWith(SpecHeaderParameters{}, SpecSecurityDefinitions{newAPIKeyHeaderSecurityDefinition("disable_response_cache", authorizationHeader)})
+		Convey("When createTerraformProviderSchema is called", func() {
+			_, err := p.createTerraformProviderSchema(&specStubBackendConfiguration{host: "api.${region}.hostname.com"}, nil)
+			Convey("Then the error returned should point to the colliding security definition", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "security definition 'disable_response_cache' collides with the provider's built-in property 'disable_response_cache', please rename the security definition in the OpenAPI document")
+			})
 		})
 	})
 }
@@ -621,17 +1031,239 @@ func TestConfigureProvider(t *testing.T) {
 			},
 		}
 		testProviderSchema := newTestSchema(apiKeyAuthProperty, headerProperty)
-		Convey("When configureProvider is called with a backend that is not multi-region and the returned configureFunc is invoked upon ", func() {
+		Convey("When configureProviderClient is called with a backend that is not multi-region", func() {
 			backendConfig := &specStubBackendConfiguration{}
-			configureFunc := p.configureProvider(backendConfig, &providerConfigurationEndPoints{})
-			client, err := configureFunc(testProviderSchema.getResourceData(t))
-			providerClient := client.(*ProviderClient)
+			client, err := p.configureProviderClient(context.Background(), testProviderSchema.getResourceData(t), backendConfig, &providerConfigurationEndPoints{})
+			providerClient := client
 			Convey("Then error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
 			Convey("And the client should implement ClientOpenAPI interface", func() {
 				var _ ClientOpenAPI = providerClient
 			})
+			Convey("And the client should have the response cache enabled", func() {
+				So(providerClient.responseCache, ShouldNotBeNil)
+			})
+		})
+		Convey("When configureProviderClient is called with the disable_response_cache property set to true", func() {
+			disableResponseCacheProperty := newBoolSchemaDefinitionPropertyWithDefaults(providerPropertyDisableResponseCache, "", false, false, true)
+			testProviderSchema := newTestSchema(apiKeyAuthProperty, headerProperty, disableResponseCacheProperty)
+			client, err := p.configureProviderClient(context.Background(), testProviderSchema.getResourceData(t), &specStubBackendConfiguration{}, &providerConfigurationEndPoints{})
+			Convey("Then error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the client should have the response cache disabled", func() {
+				So(client.responseCache, ShouldBeNil)
+			})
+		})
+		Convey("When configureProviderClient is called with the swagger_url property pointing at a different OpenAPI document", func() {
+			swaggerFile := initAPISpecFile(`swagger: "2.0"
+host: "staging.api.com"
+basePath: "/staging"
+schemes:
+- "https"
+paths:
+  /v1/cdns:
+    post:
+      x-terraform-resource-name: "cdn"
+      parameters:
+      - in: "body"
+        name: "body"
+        required: true
+        schema:
+          $ref: "#/definitions/ContentDeliveryNetworkV1"
+      responses:
+        201:
+          schema:
+            $ref: "#/definitions/ContentDeliveryNetworkV1"
+  /v1/cdns/{id}:
+    get:
+      parameters:
+      - name: "id"
+        in: "path"
+        required: true
+        type: "string"
+      responses:
+        200:
+          schema:
+            $ref: "#/definitions/ContentDeliveryNetworkV1"
+definitions:
+  ContentDeliveryNetworkV1:
+    type: "object"
+    properties:
+      id:
+        type: "string"
+        readOnly: true
+      label:
+        type: "string"`)
+			defer os.Remove(swaggerFile.Name())
+			swaggerURLProperty := newStringSchemaDefinitionPropertyWithDefaults(providerPropertySwaggerURL, "", false, false, swaggerFile.Name())
+			testProviderSchema := newTestSchema(apiKeyAuthProperty, headerProperty, swaggerURLProperty)
+			client, err := p.configureProviderClient(context.Background(), testProviderSchema.getResourceData(t), &specStubBackendConfiguration{host: "api.com"}, &providerConfigurationEndPoints{})
+			Convey("Then error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the client should be configured with the backend configuration of the OpenAPI document provided", func() {
+				host, err := client.openAPIBackendConfiguration.getHost()
+				So(err, ShouldBeNil)
+				So(host, ShouldEqual, "staging.api.com")
+				So(client.openAPIBackendConfiguration.getBasePath(), ShouldEqual, "/staging")
+			})
+			Convey("And the client should be configured with the resources of the OpenAPI document provided", func() {
+				So(client.resourceOverrides, ShouldContainKey, "cdn_v1")
+				resourcePath, err := client.resourceOverrides["cdn_v1"].getResourcePath(nil)
+				So(err, ShouldBeNil)
+				So(resourcePath, ShouldEqual, "/v1/cdns")
+			})
+		})
+		Convey("When configureProviderClient is called with the swagger_url property pointing at a non existing OpenAPI document", func() {
+			swaggerURLProperty := newStringSchemaDefinitionPropertyWithDefaults(providerPropertySwaggerURL, "", false, false, "non-existing-swagger.yaml")
+			testProviderSchema := newTestSchema(apiKeyAuthProperty, headerProperty, swaggerURLProperty)
+			_, err := p.configureProviderClient(context.Background(), testProviderSchema.getResourceData(t), &specStubBackendConfiguration{}, &providerConfigurationEndPoints{})
+			Convey("Then the error returned should be a SpecFetchError", func() {
+				So(err, ShouldHaveSameTypeAs, &SpecFetchError{})
+				So(err.Error(), ShouldStartWith, "failed to load the OpenAPI document configured in the provider property 'swagger_url': failed to retrieve the OpenAPI document from 'non-existing-swagger.yaml'")
+			})
+		})
+		Convey("When the function returned by configureProvider is invoked", func() {
+			configureFunc := p.configureProvider(&specStubBackendConfiguration{}, &providerConfigurationEndPoints{})
+			client, diags := configureFunc(context.Background(), testProviderSchema.getResourceData(t))
+			Convey("Then the diagnostics returned should be empty", func() {
+				So(diags, ShouldBeEmpty)
+			})
+			Convey("And the client returned should be a ProviderClient", func() {
+				So(client, ShouldHaveSameTypeAs, &ProviderClient{})
+			})
+		})
+		Convey("When the function returned by configureProvider is invoked with the swagger_url property pointing at a non existing OpenAPI document", func() {
+			swaggerURLProperty := newStringSchemaDefinitionPropertyWithDefaults(providerPropertySwaggerURL, "", false, false, "non-existing-swagger.yaml")
+			testProviderSchema := newTestSchema(apiKeyAuthProperty, headerProperty, swaggerURLProperty)
+			configureFunc := p.configureProvider(&specStubBackendConfiguration{}, &providerConfigurationEndPoints{})
+			client, diags := configureFunc(context.Background(), testProviderSchema.getResourceData(t))
+			Convey("Then the error should be returned as diagnostics", func() {
+				So(client, ShouldBeNil)
+				So(diags.HasError(), ShouldBeTrue)
+				So(diagnosticsError(diags).Error(), ShouldStartWith, "failed to load the OpenAPI document configured in the provider property 'swagger_url'")
+			})
+		})
+		Convey("When configureProviderClient is called and the service configuration has validation commands for the provider properties", func() {
+			apiKeyAuthPropertyConfiguration := &ServiceSchemaPropertyConfigurationStub{SchemaPropertyName: apiKeyAuthProperty.Name}
+			headerPropertyConfiguration := &ServiceSchemaPropertyConfigurationStub{SchemaPropertyName: headerProperty.Name}
+			p.serviceConfiguration = &ServiceConfigStub{
+				SchemaConfiguration: []*ServiceSchemaPropertyConfigurationStub{apiKeyAuthPropertyConfiguration, headerPropertyConfiguration},
+			}
+			Convey("And the values are valid", func() {
+				_, err := p.configureProviderClient(context.Background(), testProviderSchema.getResourceData(t), &specStubBackendConfiguration{}, &providerConfigurationEndPoints{})
+				Convey("Then error returned should be nil", func() {
+					So(err, ShouldBeNil)
+				})
+				Convey("And the values provided by the user should have been validated", func() {
+					So(apiKeyAuthPropertyConfiguration.ValidatedValue, ShouldEqual, "someAuthValue")
+					So(headerPropertyConfiguration.ValidatedValue, ShouldEqual, "someHeaderValue")
+				})
+			})
+			Convey("And the value of one of the properties is not valid", func() {
+				apiKeyAuthPropertyConfiguration.ValidateValueErr = errors.New("provider property 'apikey_auth' value is not valid: token expired, please run 'login' to get a new one")
+				_, err := p.configureProviderClient(context.Background(), testProviderSchema.getResourceData(t), &specStubBackendConfiguration{}, &providerConfigurationEndPoints{})
+				Convey("Then the error returned should be the one returned by the validation", func() {
+					So(err.Error(), ShouldEqual, "provider property 'apikey_auth' value is not valid: token expired, please run 'login' to get a new one")
+				})
+			})
+		})
+	})
+	Convey("Given a provider factory with a global security scheme requiring scopes and a security definition declaring a token introspection endpoint", t, func() {
+		grantedScopes := "cdns:read"
+		introspectionServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(fmt.Sprintf(`{"active": true, "scope": "%s"}`, grantedScopes)))
+		}))
+		defer introspectionServer.Close()
+		apiKeyAuthProperty := newStringSchemaDefinitionPropertyWithDefaults("apikey_auth", "", true, false, "someAuthValue")
+		p := providerFactory{
+			name: "provider",
+			specAnalyser: &specAnalyserStub{
+				security: &specSecurityStub{
+					securityDefinitions: &SpecSecurityDefinitions{
+						newAPIKeyHeaderSecurityDefinition(apiKeyAuthProperty.Name, authorizationHeader),
+					},
+					globalSecuritySchemes: createSecuritySchemes([]map[string][]string{
+						{
+							apiKeyAuthProperty.Name: []string{"cdns:read"},
+						},
+					}),
+					introspectionURLs: map[string]string{apiKeyAuthProperty.Name: introspectionServer.URL},
+				},
+			},
+		}
+		testProviderSchema := newTestSchema(apiKeyAuthProperty)
+		Convey("When configureProviderClient is called and the credentials have been granted the scopes required", func() {
+			client, err := p.configureProviderClient(context.Background(), testProviderSchema.getResourceData(t), &specStubBackendConfiguration{}, &providerConfigurationEndPoints{})
+			Convey("Then error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the client should be configured with the scopes granted", func() {
+				So(client.providerConfiguration.GrantedScopes, ShouldResemble, map[string][]string{apiKeyAuthProperty.Name: {"cdns:read"}})
+			})
+			Convey("And the client configuration should report the scopes not granted that operations may require", func() {
+				operationSecurityScheme := SpecSecurityScheme{Name: apiKeyAuthProperty.Name, Scopes: []string{"cdns:read", "cdns:write"}}
+				So(client.providerConfiguration.getMissingScopes(operationSecurityScheme), ShouldResemble, []string{"cdns:write"})
+			})
+		})
+		Convey("When configureProviderClient is called and the credentials have not been granted the scopes required", func() {
+			grantedScopes = "firewalls:read"
+			_, err := p.configureProviderClient(context.Background(), testProviderSchema.getResourceData(t), &specStubBackendConfiguration{}, &providerConfigurationEndPoints{})
+			Convey("Then the error returned should be an AuthConfigError listing the missing scopes", func() {
+				So(err, ShouldHaveSameTypeAs, &AuthConfigError{})
+				So(err.Error(), ShouldEqual, "the credentials configured for the security definition 'apikey_auth' have not been granted the scopes required by the API, missing scopes: cdns:read")
+			})
+		})
+		Convey("When configureProviderClient is called with a stop context that is already done", func() {
+			stopContext, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err := p.configureProviderClient(stopContext, testProviderSchema.getResourceData(t), &specStubBackendConfiguration{}, &providerConfigurationEndPoints{})
+			Convey("Then the token introspection request should be aborted", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "token introspection POST request")
+			})
+		})
+	})
+}
+
+func TestCheckOperationScopes(t *testing.T) {
+	Convey("Given a provider factory exposing resources and data sources whose operations require scopes", t, func() {
+		writeOperation := &specResourceOperation{SecuritySchemes: SpecSecuritySchemes{{Name: "oauth_token", Scopes: []string{"cdns:write"}}}}
+		readOperation := &specResourceOperation{SecuritySchemes: SpecSecuritySchemes{{Name: "oauth_token", Scopes: []string{"cdns:read"}}}}
+		p := providerFactory{
+			name: "provider",
+			specAnalyser: &specAnalyserStub{
+				resources: []SpecResource{
+					&specStubResource{name: "cdn_v1", path: "/v1/cdns", resourcePostOperation: writeOperation, resourceGetOperation: readOperation},
+					&specStubResource{name: "lb_v1", path: "/v1/lbs", resourceGetOperation: readOperation},
+				},
+				dataSources: []SpecResource{
+					&specStubResource{name: "firewalls_v1", path: "/v1/firewalls", resourceListOperation: &specResourceOperation{SecuritySchemes: SpecSecuritySchemes{{Name: "oauth_token", Scopes: []string{"firewalls:read"}}}}},
+				},
+			},
+		}
+		Convey("When checkOperationScopes is called with the scopes granted to the credentials configured", func() {
+			diags := p.checkOperationScopes(&providerConfiguration{GrantedScopes: map[string][]string{"oauth_token": {"cdns:read"}}})
+			Convey("Then a warning listing the resources and data sources missing scopes should be returned", func() {
+				So(diags, ShouldHaveLength, 1)
+				So(diags[0].Severity, ShouldEqual, diag.Warning)
+				So(diags[0].Summary, ShouldEqual, "the credentials configured have not been granted the scopes required by some of the resources and data sources")
+				So(diags[0].Detail, ShouldEqual, "the API calls made by the following resources and data sources will fail: data source provider_firewalls_v1 ('oauth_token' missing scopes: firewalls:read), resource provider_cdn_v1 ('oauth_token' missing scopes: cdns:write)")
+			})
+		})
+		Convey("When checkOperationScopes is called with all the scopes required granted to the credentials configured", func() {
+			diags := p.checkOperationScopes(&providerConfiguration{GrantedScopes: map[string][]string{"oauth_token": {"cdns:read", "cdns:write", "firewalls:read"}}})
+			Convey("Then no diagnostics should be returned", func() {
+				So(diags, ShouldBeEmpty)
+			})
+		})
+		Convey("When checkOperationScopes is called and the credentials configured have not been introspected", func() {
+			diags := p.checkOperationScopes(&providerConfiguration{})
+			Convey("Then no diagnostics should be returned", func() {
+				So(diags, ShouldBeEmpty)
+			})
 		})
 	})
 }
@@ -877,6 +1509,176 @@ func TestCreateTerraformProviderDataSourceInstanceMap_ignore_resource(t *testing
 	assert.Empty(t, dataSourceMap)
 }
 
+func TestCreateTerraformProviderDataSourceInstanceMap_ignore_data_source_instance(t *testing.T) {
+	specResource := newSpecStubResource("resource", "/v1/resource", false, &specSchemaDefinition{})
+	specResource.ignoreDataSourceInstance = true
+	p := providerFactory{
+		name: "provider",
+		specAnalyser: &specAnalyserStub{
+			resources: []SpecResource{specResource},
+		},
+	}
+	resourceMap, dataSourceMap, err := p.createTerraformProviderResourceMapAndDataSourceInstanceMap()
+	assert.Nil(t, err)
+	assert.Contains(t, resourceMap, "provider_resource")
+	assert.Empty(t, dataSourceMap)
+}
+
+func TestCreateTerraformProviderDataSourceInstanceMap_auto_import(t *testing.T) {
+	specResource := newSpecStubResource("resource", "/v1/resource", false, &specSchemaDefinition{})
+	specResource.autoImport = true
+	specResource.ignoreDataSourceInstance = true
+	otherSpecResource := newSpecStubResource("other", "/v1/other", false, &specSchemaDefinition{})
+	p := providerFactory{
+		name: "provider",
+		specAnalyser: &specAnalyserStub{
+			resources: []SpecResource{specResource, otherSpecResource},
+		},
+	}
+	_, dataSourceMap, err := p.createTerraformProviderResourceMapAndDataSourceInstanceMap()
+	assert.Nil(t, err)
+	assert.Contains(t, dataSourceMap, "provider_resource_import")
+	assert.NotContains(t, dataSourceMap, "provider_resource_instance")
+	assert.NotContains(t, dataSourceMap, "provider_other_import")
+	assert.Contains(t, dataSourceMap, "provider_other_instance")
+}
+
+func TestCreateTerraformProviderResourceMapAndDataSourceInstanceMap_singularize_resource_names(t *testing.T) {
+	p := providerFactory{
+		name: "provider",
+		specAnalyser: &specAnalyserStub{
+			resources: []SpecResource{
+				newSpecStubResource("policies_v1", "/v1/policies", false, &specSchemaDefinition{}),
+			},
+		},
+		serviceConfiguration: &ServiceConfigStub{
+			Singularize: true,
+		},
+	}
+	resourceMap, dataSourceMap, err := p.createTerraformProviderResourceMapAndDataSourceInstanceMap()
+	assert.Nil(t, err)
+	assert.Len(t, resourceMap, 2)
+	assert.Contains(t, resourceMap, "provider_policy_v1")
+	assert.Empty(t, resourceMap["provider_policy_v1"].DeprecationMessage)
+	assert.Contains(t, resourceMap, "provider_policies_v1")
+	assert.Equal(t, "'provider_policies_v1' is deprecated, please use 'provider_policy_v1' instead", resourceMap["provider_policies_v1"].DeprecationMessage)
+	assert.Len(t, dataSourceMap, 2)
+	assert.Contains(t, dataSourceMap, "provider_policy_v1_instance")
+	assert.Contains(t, dataSourceMap, "provider_policies_v1_instance")
+}
+
+func TestCreateTerraformProviderResourceMapAndDataSourceInstanceMap_singularize_resource_names_alias_collision(t *testing.T) {
+	p := providerFactory{
+		name: "provider",
+		specAnalyser: &specAnalyserStub{
+			resources: []SpecResource{
+				newSpecStubResource("policies_v1", "/v1/policies", false, &specSchemaDefinition{}),
+				newSpecStubResource("policy_v1", "/v1/policy", false, &specSchemaDefinition{}),
+			},
+		},
+		serviceConfiguration: &ServiceConfigStub{
+			Singularize: true,
+		},
+	}
+	resourceMap, dataSourceMap, err := p.createTerraformProviderResourceMapAndDataSourceInstanceMap()
+	assert.Nil(t, err)
+	assert.Empty(t, resourceMap)
+	assert.Empty(t, dataSourceMap)
+}
+
+func TestCreateTerraformProviderResourceMapAndDataSourceInstanceMap_multiple_versions(t *testing.T) {
+	p := providerFactory{
+		name: "provider",
+		specAnalyser: &specAnalyserStub{
+			resources: []SpecResource{
+				newSpecStubResource("cdn_v1", "/v1/cdns", false, &specSchemaDefinition{}),
+				newSpecStubResource("cdn_v2", "/v2/cdns", false, &specSchemaDefinition{}),
+			},
+		},
+	}
+	resourceMap, dataSourceMap, err := p.createTerraformProviderResourceMapAndDataSourceInstanceMap()
+	assert.Nil(t, err)
+	assert.Len(t, resourceMap, 2)
+	assert.Contains(t, resourceMap, "provider_cdn_v1")
+	assert.Contains(t, resourceMap, "provider_cdn_v2")
+	assert.Len(t, dataSourceMap, 2)
+	assert.Contains(t, dataSourceMap, "provider_cdn_v1_instance")
+	assert.Contains(t, dataSourceMap, "provider_cdn_v2_instance")
+}
+
+func TestCreateTerraformProviderResourceMapAndDataSourceInstanceMap_data_source_instance_suffix(t *testing.T) {
+	p := providerFactory{
+		name: "provider",
+		specAnalyser: &specAnalyserStub{
+			resources: []SpecResource{
+				newSpecStubResource("cdn_v1", "/v1/cdns", false, &specSchemaDefinition{}),
+			},
+		},
+		serviceConfiguration: &ServiceConfigStub{
+			DataSourceInstanceSuffix: "_lookup",
+		},
+	}
+	resourceMap, dataSourceMap, err := p.createTerraformProviderResourceMapAndDataSourceInstanceMap()
+	assert.Nil(t, err)
+	assert.Len(t, resourceMap, 1)
+	assert.Contains(t, resourceMap, "provider_cdn_v1")
+	assert.Len(t, dataSourceMap, 1)
+	assert.Contains(t, dataSourceMap, "provider_cdn_v1_lookup")
+}
+
+func TestGetResourceVersionFamilies(t *testing.T) {
+	testCases := []struct {
+		name             string
+		resourceNames    []string
+		expectedFamilies map[string][]string
+	}{
+		{
+			name:             "resource registered in multiple versions",
+			resourceNames:    []string{"provider_cdn_v2", "provider_cdn_v1", "provider_lb_v1"},
+			expectedFamilies: map[string][]string{"provider_cdn": {"provider_cdn_v1", "provider_cdn_v2"}},
+		},
+		{
+			name:             "versions are sorted numerically",
+			resourceNames:    []string{"provider_cdn_v10", "provider_cdn_v2", "provider_cdn_v1"},
+			expectedFamilies: map[string][]string{"provider_cdn": {"provider_cdn_v1", "provider_cdn_v2", "provider_cdn_v10"}},
+		},
+		{
+			name:             "resources without version suffix are ignored",
+			resourceNames:    []string{"provider_cdn", "provider_cdn_v1", "provider_cdn_version"},
+			expectedFamilies: map[string][]string{},
+		},
+		{
+			name:             "no resources",
+			resourceNames:    nil,
+			expectedFamilies: map[string][]string{},
+		},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expectedFamilies, getResourceVersionFamilies(tc.resourceNames), tc.name)
+	}
+}
+
+func TestIsDestroyPrevented(t *testing.T) {
+	p := providerFactory{
+		name: "provider",
+		serviceConfiguration: &ServiceConfigStub{
+			PreventDestroy: []string{"provider_database_v1", "provider_bucket_*"},
+		},
+	}
+	assert.True(t, p.isDestroyPrevented("provider_database_v1"))
+	assert.True(t, p.isDestroyPrevented("provider_bucket_v1"))
+	assert.False(t, p.isDestroyPrevented("provider_database_v2"))
+	assert.False(t, providerFactory{name: "provider"}.isDestroyPrevented("provider_database_v1"))
+}
+
+func TestGetStopContext(t *testing.T) {
+	stopContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx := context.WithValue(context.Background(), schema.StopContextKey, stopContext)
+	assert.Equal(t, stopContext, getStopContext(ctx))
+	assert.Equal(t, context.Background(), getStopContext(context.Background()))
+}
+
 func TestCreateTerraformProviderDataSourceInstanceMap_duplicate_resource(t *testing.T) {
 	p := providerFactory{
 		name: "provider",
@@ -892,6 +1694,86 @@ func TestCreateTerraformProviderDataSourceInstanceMap_duplicate_resource(t *test
 	assert.Empty(t, dataSourceMap)
 }
 
+func TestCreateTerraformProviderResourceMapAndDataSourceInstanceMap_duplicate_resource_strategies(t *testing.T) {
+	newSpecAnalyser := func() *specAnalyserStub {
+		return &specAnalyserStub{
+			resources: []SpecResource{
+				newSpecStubResource("collision_v1", "/v1/collision_b", false, &specSchemaDefinition{}),
+				newSpecStubResource("collision_v1", "/v1/collision_a", false, &specSchemaDefinition{}),
+				newSpecStubResource("collision_v1_2", "/v1/other", false, &specSchemaDefinition{}),
+				newSpecStubResource("collision_v1", "/v1/collision_c", false, &specSchemaDefinition{}),
+			},
+		}
+	}
+	testCases := []struct {
+		name                           string
+		strategy                       string
+		expectedResourcePaths          map[string]string
+		expectedDataSourceInstanceKeys []string
+		expectedError                  string
+	}{
+		{
+			name:     "colliding resources are removed when the strategy is remove",
+			strategy: duplicateResourceNameStrategyRemove,
+			expectedResourcePaths: map[string]string{
+				"provider_collision_v1_2": "/v1/other",
+			},
+			expectedDataSourceInstanceKeys: []string{"provider_collision_v1_2_instance"},
+		},
+		{
+			name:          "an error listing the collisions is returned when the strategy is error",
+			strategy:      duplicateResourceNameStrategyError,
+			expectedError: "resource name collisions found: 'collision_v1' (/v1/collision_a, /v1/collision_b, /v1/collision_c). Please make sure the resources have unique names (e,g: using the x-terraform-resource-name extension) or configure a different duplicate resource name strategy in the service configuration",
+		},
+		{
+			name:     "the first colliding resource sorted by path is kept when the strategy is keep-first",
+			strategy: duplicateResourceNameStrategyKeepFirst,
+			expectedResourcePaths: map[string]string{
+				"provider_collision_v1":   "/v1/collision_a",
+				"provider_collision_v1_2": "/v1/other",
+			},
+			expectedDataSourceInstanceKeys: []string{"provider_collision_v1_instance", "provider_collision_v1_2_instance"},
+		},
+		{
+			name:     "the colliding resources get a numeric suffix skipping names already taken when the strategy is auto-suffix",
+			strategy: duplicateResourceNameStrategyAutoSuffix,
+			expectedResourcePaths: map[string]string{
+				"provider_collision_v1":   "/v1/collision_a",
+				"provider_collision_v1_2": "/v1/other",
+				"provider_collision_v1_3": "/v1/collision_b",
+				"provider_collision_v1_4": "/v1/collision_c",
+			},
+			expectedDataSourceInstanceKeys: []string{"provider_collision_v1_instance", "provider_collision_v1_2_instance", "provider_collision_v1_3_instance", "provider_collision_v1_4_instance"},
+		},
+	}
+	for _, tc := range testCases {
+		p := providerFactory{
+			name:                 "provider",
+			specAnalyser:         newSpecAnalyser(),
+			serviceConfiguration: &ServiceConfigStub{DuplicateStrategy: tc.strategy},
+		}
+		resourceMap, dataSourceMap, err := p.createTerraformProviderResourceMapAndDataSourceInstanceMap()
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		assert.Len(t, resourceMap, len(tc.expectedResourcePaths), tc.name)
+		for resourceName := range tc.expectedResourcePaths {
+			assert.Contains(t, resourceMap, resourceName, tc.name)
+		}
+		assert.Len(t, dataSourceMap, len(tc.expectedDataSourceInstanceKeys), tc.name)
+		for _, dataSourceInstanceName := range tc.expectedDataSourceInstanceKeys {
+			assert.Contains(t, dataSourceMap, dataSourceInstanceName, tc.name)
+		}
+		namedResources, err := p.resolveDuplicateResourceNames(newSpecAnalyser().resources)
+		require.NoError(t, err, tc.name)
+		for _, namedResource := range namedResources {
+			assert.Equal(t, tc.expectedResourcePaths["provider_"+namedResource.name], p.describeResource(namedResource.resource), tc.name)
+		}
+	}
+}
+
 func TestCreateTerraformProviderDataSourceMap(t *testing.T) {
 
 	testcases := []struct {
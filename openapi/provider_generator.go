@@ -0,0 +1,190 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/dikhan/terraform-provider-openapi/openapi/version"
+)
+
+// GenerateProviderCommand is the command that makes the provider binary generate the source code of a standalone provider
+// binary for the given provider name and swagger URL instead of serving the plugin (e.g. terraform-provider-openapi
+// generate-provider <provider_name> <swagger_url> [output_dir])
+const GenerateProviderCommand = "generate-provider"
+
+// openAPIProviderModule is the go module of the OpenAPI Terraform provider, which the generated providers depend on
+const openAPIProviderModule = "github.com/dikhan/terraform-provider-openapi"
+
+// providerNameRegex matches the provider names supported, which must follow the terraform provider binary naming
+// convention (terraform-provider-<provider_name>)
+var providerNameRegex = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+
+// releaseVersionRegex matches the versions of the OpenAPI Terraform provider that were released (e.g. 0.29.4), as opposed
+// to the binaries built locally (dev)
+var releaseVersionRegex = regexp.MustCompile(`^v?\d+\.\d+\.\d+$`)
+
+// generatedProvider contains the values the templates of the generated files are rendered with
+type generatedProvider struct {
+	ProviderName string
+	SwaggerURL   string
+	Module       string
+	// ModuleVersion is the version of the OpenAPI Terraform provider module the generated provider depends on; empty if
+	// the generator is not a released binary, in which case the latest version is used when building
+	ModuleVersion string
+}
+
+// generatedProviderFile describes each of the files generated, the go files are formatted after being rendered
+type generatedProviderFile struct {
+	name     string
+	template *template.Template
+}
+
+var generatedProviderFiles = []generatedProviderFile{
+	{name: "main.go", template: template.Must(template.New("main.go").Parse(generatedMainTemplate))},
+	{name: "go.mod", template: template.Must(template.New("go.mod").Parse(generatedGoModTemplate))},
+	{name: "Makefile", template: template.Must(template.New("Makefile").Parse(generatedMakefileTemplate))},
+	{name: OpenAPIPluginConfigurationFileName, template: template.Must(template.New(OpenAPIPluginConfigurationFileName).Parse(generatedPluginConfigurationTemplate))},
+}
+
+// GenerateProvider writes into the output directory the source code of a standalone provider binary for the given provider
+// name: the main package, its go module, a Makefile that builds the binary with the version information and the plugin
+// configuration file pointing at the swagger URL. The output directory defaults to terraform-provider-<provider_name>
+// and existing files are never overwritten. The files generated and the next steps are written into the given writer
+func GenerateProvider(w io.Writer, providerName, swaggerURL, outputDir string) error {
+	if !providerNameRegex.MatchString(providerName) {
+		return fmt.Errorf("provider name '%s' is not valid, it must only contain alphanumeric characters", providerName)
+	}
+	if strings.TrimSpace(swaggerURL) == "" {
+		return fmt.Errorf("swagger URL must not be empty")
+	}
+	if outputDir == "" {
+		outputDir = fmt.Sprintf("terraform-provider-%s", providerName)
+	}
+	provider := generatedProvider{
+		ProviderName: providerName,
+		SwaggerURL:   swaggerURL,
+		Module:       openAPIProviderModule,
+	}
+	if releaseVersionRegex.MatchString(version.Version) {
+		provider.ModuleVersion = "v" + strings.TrimPrefix(version.Version, "v")
+	}
+
+	files := map[string][]byte{}
+	for _, file := range generatedProviderFiles {
+		path := filepath.Join(outputDir, file.name)
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("file '%s' already exists, please remove it or use a different output directory", path)
+		}
+		var content bytes.Buffer
+		if err := file.template.Execute(&content, provider); err != nil {
+			return fmt.Errorf("failed to render '%s': %s", file.name, err)
+		}
+		files[path] = content.Bytes()
+		if filepath.Ext(file.name) != ".go" {
+			continue
+		}
+		formatted, err := format.Source(content.Bytes())
+		if err != nil {
+			return fmt.Errorf("failed to format '%s': %s", file.name, err)
+		}
+		files[path] = formatted
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create the output directory '%s': %s", outputDir, err)
+	}
+	fmt.Fprintf(w, "Generated the source code of terraform-provider-%s in %s:\n", providerName, outputDir)
+	for _, file := range generatedProviderFiles {
+		path := filepath.Join(outputDir, file.name)
+		if err := ioutil.WriteFile(path, files[path], 0644); err != nil {
+			return fmt.Errorf("failed to write '%s': %s", path, err)
+		}
+		fmt.Fprintf(w, "  %s\n", file.name)
+	}
+	fmt.Fprintf(w, "\nNext steps:\n")
+	fmt.Fprintf(w, "  1. Build the provider binary: cd %s && make build VERSION=<provider_version>\n", outputDir)
+	fmt.Fprintf(w, "  2. Install it along with the plugin configuration file (if there is already a %s file in the terraform plugins folder, add the '%s' service to it instead): make install\n", OpenAPIPluginConfigurationFileName, providerName)
+	return nil
+}
+
+const generatedMainTemplate = `// This file was generated by 'terraform-provider-openapi generate-provider' and can be edited freely
+
+package main
+
+import (
+	"log"
+
+	"{{.Module}}/openapi"
+	"{{.Module}}/openapi/version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+)
+
+// providerName is the name of the provider, which is used to look up its service configuration in the plugin configuration
+// file (and the OTF_VAR_{{.ProviderName}}_* environment variables). Unlike the generic OpenAPI provider binary, the name does not
+// depend on the name of the binary
+const providerName = "{{.ProviderName}}"
+
+func main() {
+	log.Printf("Running terraform-provider-%s v%s-%s; Released on: %s", providerName, version.Version, version.Commit, version.Date)
+
+	p := openapi.ProviderOpenAPI{ProviderName: providerName}
+	provider, err := p.CreateSchemaProvider()
+	if err != nil {
+		log.Fatalf("[ERROR] There was an error initialising the terraform provider: %s", err)
+	}
+
+	plugin.Serve(
+		&plugin.ServeOpts{
+			ProviderFunc: func() *schema.Provider {
+				return provider
+			},
+		})
+}
+`
+
+const generatedGoModTemplate = `module terraform-provider-{{.ProviderName}}
+
+go 1.16
+{{if .ModuleVersion}}
+require {{.Module}} {{.ModuleVersion}}
+{{end}}`
+
+const generatedMakefileTemplate = `PROVIDER_NAME = {{.ProviderName}}
+VERSION ?= dev
+COMMIT ?= $(shell git rev-parse --verify --short HEAD 2>/dev/null || echo none)
+DATE ?= $(shell date +'%FT%TZ%z')
+LDFLAGS = '-s -w -extldflags "-static" -X "{{.Module}}/openapi/version.Version=$(VERSION)" -X "{{.Module}}/openapi/version.Commit=$(COMMIT)" -X "{{.Module}}/openapi/version.Date=$(DATE)"'
+
+TF_INSTALLED_PLUGINS_PATH ?= $(HOME)/.terraform.d/plugins
+
+default: build
+
+# make build VERSION=1.0.0
+build:
+	@echo "[INFO] Building terraform-provider-$(PROVIDER_NAME) binary"
+	@go mod tidy
+	@CGO_ENABLED=0 go build -tags=netgo -ldflags=$(LDFLAGS) -o terraform-provider-$(PROVIDER_NAME)
+
+# make install
+install: build
+	@echo "[INFO] Installing terraform-provider-$(PROVIDER_NAME) in $(TF_INSTALLED_PLUGINS_PATH)"
+	@mkdir -p $(TF_INSTALLED_PLUGINS_PATH)
+	@cp terraform-provider-$(PROVIDER_NAME) $(TF_INSTALLED_PLUGINS_PATH)
+	@if [ ! -f $(TF_INSTALLED_PLUGINS_PATH)/terraform-provider-openapi.yaml ]; then cp terraform-provider-openapi.yaml $(TF_INSTALLED_PLUGINS_PATH); else echo "[WARN] $(TF_INSTALLED_PLUGINS_PATH)/terraform-provider-openapi.yaml already exists, add the '$(PROVIDER_NAME)' service to it"; fi
+
+.PHONY: default build install
+`
+
+const generatedPluginConfigurationTemplate = `version: '1'
+services:
+  {{.ProviderName}}:
+    swagger-url: {{printf "%q" .SwaggerURL}}
+`
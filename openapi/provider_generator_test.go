@@ -0,0 +1,106 @@
+package openapi
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dikhan/terraform-provider-openapi/openapi/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateProvider(t *testing.T) {
+	testCases := []struct {
+		name          string
+		version       string
+		expectedGoMod string
+	}{
+		{
+			name:          "generated by a released binary",
+			version:       "0.30.1",
+			expectedGoMod: "module terraform-provider-goa\n\ngo 1.16\n\nrequire github.com/dikhan/terraform-provider-openapi v0.30.1\n",
+		},
+		{
+			name:          "generated by a binary built locally",
+			version:       "dev",
+			expectedGoMod: "module terraform-provider-goa\n\ngo 1.16\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func(v string) { version.Version = v }(version.Version)
+			version.Version = tc.version
+			dir, err := ioutil.TempDir("", "")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			outputDir := filepath.Join(dir, "terraform-provider-goa")
+
+			var out bytes.Buffer
+			err = GenerateProvider(&out, "goa", "https://api.goa.com/swagger.yaml", outputDir)
+			require.NoError(t, err)
+			assert.Contains(t, out.String(), "Generated the source code of terraform-provider-goa in "+outputDir)
+
+			mainGo, err := ioutil.ReadFile(filepath.Join(outputDir, "main.go"))
+			require.NoError(t, err)
+			_, err = parser.ParseFile(token.NewFileSet(), "main.go", mainGo, parser.AllErrors)
+			assert.NoError(t, err)
+			assert.Contains(t, string(mainGo), `const providerName = "goa"`)
+
+			goMod, err := ioutil.ReadFile(filepath.Join(outputDir, "go.mod"))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedGoMod, string(goMod))
+
+			makefile, err := ioutil.ReadFile(filepath.Join(outputDir, "Makefile"))
+			require.NoError(t, err)
+			assert.Contains(t, string(makefile), `-X "github.com/dikhan/terraform-provider-openapi/openapi/version.Version=$(VERSION)"`)
+
+			pluginConfiguration, err := ioutil.ReadFile(filepath.Join(outputDir, OpenAPIPluginConfigurationFileName))
+			require.NoError(t, err)
+			assert.Equal(t, "version: '1'\nservices:\n  goa:\n    swagger-url: \"https://api.goa.com/swagger.yaml\"\n", string(pluginConfiguration))
+		})
+	}
+}
+
+func TestGenerateProvider_Errors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644))
+
+	testCases := []struct {
+		name          string
+		providerName  string
+		swaggerURL    string
+		expectedError string
+	}{
+		{
+			name:          "provider name not valid",
+			providerName:  "my-provider",
+			swaggerURL:    "https://api.goa.com/swagger.yaml",
+			expectedError: "provider name 'my-provider' is not valid, it must only contain alphanumeric characters",
+		},
+		{
+			name:          "swagger url empty",
+			providerName:  "goa",
+			expectedError: "swagger URL must not be empty",
+		},
+		{
+			name:          "files already exist in the output directory",
+			providerName:  "goa",
+			swaggerURL:    "https://api.goa.com/swagger.yaml",
+			expectedError: "file '" + filepath.Join(dir, "main.go") + "' already exists, please remove it or use a different output directory",
+		},
+	}
+	for _, tc := range testCases {
+		err := GenerateProvider(&bytes.Buffer{}, tc.providerName, tc.swaggerURL, dir)
+		assert.EqualError(t, err, tc.expectedError, tc.name)
+	}
+	content, err := ioutil.ReadFile(filepath.Join(dir, "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package main", string(content))
+}
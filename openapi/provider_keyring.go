@@ -0,0 +1,58 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// errKeyringSecretNotFound is returned by the keyring lookups when there is no secret stored for the service and account
+var errKeyringSecretNotFound = errors.New("secret not found, please make sure it is stored in the OS keyring")
+
+// keyringSecretLookup returns the secret stored in the OS keyring for the given service and account. The lookup is
+// specific to each OS (see lookupKeyringSecret) and can be replaced in the tests
+var keyringSecretLookup = lookupKeyringSecret
+
+// runKeyringCommand runs the given OS keyring command (e.g. security in macOS) and returns its standard output without
+// the trailing new line. If the command exits with the given not found exit code errKeyringSecretNotFound is returned
+func runKeyringCommand(notFoundExitCode int, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cmdTimeout*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("command '%s' did not finish executing within the expected time %ds (%s)", name, cmdTimeout, err)
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == notFoundExitCode {
+		return "", errKeyringSecretNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to execute command '%s': %s(%s)", name, strings.TrimSpace(stderr.String()), err)
+	}
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
+
+// decodeCredentialBlob returns the secret stored in a Windows credential. The credentials stored with cmdkey or the
+// Credential Manager UI are UTF-16LE encoded whereas most of the libraries store them UTF-8 encoded; as UTF-8 encoded
+// text never contains NUL bytes, blobs containing them are decoded as UTF-16LE
+func decodeCredentialBlob(blob []byte) string {
+	if utf8.Valid(blob) && bytes.IndexByte(blob, 0) == -1 || len(blob)%2 != 0 {
+		return string(blob)
+	}
+	chars := make([]uint16, len(blob)/2)
+	for i := range chars {
+		chars[i] = uint16(blob[2*i]) | uint16(blob[2*i+1])<<8
+	}
+	return string(utf16.Decode(chars))
+}
@@ -0,0 +1,10 @@
+package openapi
+
+// securityItemNotFoundExitCode is the exit code of the macOS security command when the item is not in the keychain
+const securityItemNotFoundExitCode = 44
+
+// lookupKeyringSecret returns the password of the generic password item stored in the macOS Keychain for the given
+// service and account (e.g. security add-generic-password -s <service> -a <account> -w <secret>)
+func lookupKeyringSecret(service, account string) (string, error) {
+	return runKeyringCommand(securityItemNotFoundExitCode, "security", "find-generic-password", "-s", service, "-a", account, "-w")
+}
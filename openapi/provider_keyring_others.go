@@ -0,0 +1,13 @@
+//go:build !darwin && !windows
+// +build !darwin,!windows
+
+package openapi
+
+// secretToolNotFoundExitCode is the exit code of secret-tool lookup when there is no secret matching the attributes
+const secretToolNotFoundExitCode = 1
+
+// lookupKeyringSecret returns the secret stored in the Linux secret service (e.g. GNOME Keyring or KWallet) with the
+// given service and account attributes (e.g. secret-tool store --label=<label> service <service> account <account>)
+func lookupKeyringSecret(service, account string) (string, error) {
+	return runKeyringCommand(secretToolNotFoundExitCode, "secret-tool", "lookup", "service", service, "account", account)
+}
@@ -0,0 +1,34 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeCredentialBlob(t *testing.T) {
+	testCases := []struct {
+		name          string
+		blob          []byte
+		expectedValue string
+	}{
+		{
+			name:          "UTF-8 encoded secret",
+			blob:          []byte("superSecret"),
+			expectedValue: "superSecret",
+		},
+		{
+			name:          "UTF-16LE encoded secret (e,g: stored with cmdkey)",
+			blob:          []byte{'s', 0, 'e', 0, 'c', 0, 'r', 0, 'e', 0, 't', 0, 0xe9, 0},
+			expectedValue: "secreté",
+		},
+		{
+			name:          "empty secret",
+			blob:          []byte{},
+			expectedValue: "",
+		},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expectedValue, decodeCredentialBlob(tc.blob), tc.name)
+	}
+}
@@ -0,0 +1,57 @@
+package openapi
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const (
+	credTypeGeneric = 1
+	// errorNotFound is the error returned by CredReadW when there is no credential for the target name
+	errorNotFound = syscall.Errno(1168)
+)
+
+var (
+	advapi32      = syscall.NewLazyDLL("advapi32.dll")
+	procCredReadW = advapi32.NewProc("CredReadW")
+	procCredFree  = advapi32.NewProc("CredFree")
+)
+
+// credential mirrors the CREDENTIALW struct returned by CredReadW
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// lookupKeyringSecret returns the secret of the generic credential stored in the Windows Credential Manager with the
+// target name <service>:<account> (e.g. cmdkey /generic:<service>:<account> /user:<account> /pass:<secret>)
+func lookupKeyringSecret(service, account string) (string, error) {
+	targetName, err := syscall.UTF16PtrFromString(service + ":" + account)
+	if err != nil {
+		return "", err
+	}
+	var cred *credential
+	ret, _, err := procCredReadW.Call(uintptr(unsafe.Pointer(targetName)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if ret == 0 {
+		if err == errorNotFound {
+			return "", errKeyringSecretNotFound
+		}
+		return "", err
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+	if cred.CredentialBlobSize == 0 {
+		return "", nil
+	}
+	blob := (*[1 << 20]byte)(unsafe.Pointer(cred.CredentialBlob))[:cred.CredentialBlobSize:cred.CredentialBlobSize]
+	return decodeCredentialBlob(blob), nil
+}
@@ -0,0 +1,71 @@
+package openapi
+
+import (
+	"log"
+	"sync"
+)
+
+// schemaPropertyCommand executes the command configured for a provider property at most once
+type schemaPropertyCommand struct {
+	config ServiceSchemaPropertyConfiguration
+	once   sync.Once
+	done   chan struct{}
+	err    error
+}
+
+// start executes the command in the background, unless it was already started
+func (c *schemaPropertyCommand) start() {
+	c.once.Do(func() {
+		go func() {
+			defer close(c.done)
+			c.err = c.config.ExecuteCommand()
+		}()
+	})
+}
+
+// wait waits for the command to finish and returns the error it failed with, if any
+func (c *schemaPropertyCommand) wait() error {
+	<-c.done
+	return c.err
+}
+
+// schemaPropertyCommands defers the execution of the commands configured for the provider properties until the value
+// of any of the properties is needed (terraform only asks for them when the provider is configured, not when it only
+// needs the provider schema). Terraform resolves all the properties that are not set in the configuration one after the
+// other, so as soon as one command is needed all of them are started in the background and they run in parallel
+// instead of in sequence.
+// Trade-off: the commands of the properties that are set in the terraform configuration are started too (and their output
+// ignored), since the SDK only calls the DefaultFunc of the unset properties one at a time, without giving access to the
+// configuration, so there is no way to tell which ones will be needed when the first one is. Starting only the command of
+// the property requested would avoid that, but would run the commands in sequence again
+type schemaPropertyCommands struct {
+	commands map[string]*schemaPropertyCommand
+	startAll sync.Once
+}
+
+func newSchemaPropertyCommands() *schemaPropertyCommands {
+	return &schemaPropertyCommands{commands: map[string]*schemaPropertyCommand{}}
+}
+
+// register registers the command of the given property. The commands must all be registered before any of them is
+// executed
+func (c *schemaPropertyCommands) register(schemaPropertyName string, config ServiceSchemaPropertyConfiguration) {
+	c.commands[schemaPropertyName] = &schemaPropertyCommand{config: config, done: make(chan struct{})}
+}
+
+// execute makes sure the command of the given property was executed, waiting for it to finish if needed, and returns the
+// error it failed with. Nothing is executed if the property has no command registered
+func (c *schemaPropertyCommands) execute(schemaPropertyName string) error {
+	command, exists := c.commands[schemaPropertyName]
+	if !exists {
+		return nil
+	}
+	command.start()
+	c.startAll.Do(func() {
+		log.Printf("[DEBUG] executing the commands of the provider properties in the background")
+		for _, other := range c.commands {
+			other.start()
+		}
+	})
+	return command.wait()
+}
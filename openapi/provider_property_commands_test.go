@@ -0,0 +1,62 @@
+package openapi
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingSchemaPropertyConfigurationStub is a schema property configuration whose command blocks until released
+type blockingSchemaPropertyConfigurationStub struct {
+	*ServiceSchemaPropertyConfigurationStub
+	started    chan string
+	release    chan struct{}
+	executions int32
+}
+
+func (s *blockingSchemaPropertyConfigurationStub) ExecuteCommand() error {
+	atomic.AddInt32(&s.executions, 1)
+	s.started <- s.SchemaPropertyName
+	<-s.release
+	return s.Err
+}
+
+func TestSchemaPropertyCommands(t *testing.T) {
+	t.Run("commands are executed in parallel and only once when the first value is needed", func(t *testing.T) {
+		started := make(chan string, 2)
+		release := make(chan struct{})
+		apiKeyConfig := &blockingSchemaPropertyConfigurationStub{ServiceSchemaPropertyConfigurationStub: &ServiceSchemaPropertyConfigurationStub{SchemaPropertyName: "apikey_auth"}, started: started, release: release}
+		headerConfig := &blockingSchemaPropertyConfigurationStub{ServiceSchemaPropertyConfigurationStub: &ServiceSchemaPropertyConfigurationStub{SchemaPropertyName: "header_name", Err: errors.New("command failed")}, started: started, release: release}
+		commands := newSchemaPropertyCommands()
+		commands.register("apikey_auth", apiKeyConfig)
+		commands.register("header_name", headerConfig)
+
+		done := make(chan error, 1)
+		go func() {
+			done <- commands.execute("apikey_auth")
+		}()
+		var startedCommands []string
+		for len(startedCommands) < 2 {
+			select {
+			case name := <-started:
+				startedCommands = append(startedCommands, name)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("the commands did not start in parallel, started: %v", startedCommands)
+			}
+		}
+		assert.ElementsMatch(t, []string{"apikey_auth", "header_name"}, startedCommands)
+		close(release)
+
+		assert.NoError(t, <-done)
+		assert.EqualError(t, commands.execute("header_name"), "command failed")
+		assert.NoError(t, commands.execute("apikey_auth"))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&apiKeyConfig.executions))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&headerConfig.executions))
+	})
+	t.Run("property with no command registered", func(t *testing.T) {
+		assert.NoError(t, newSchemaPropertyCommands().execute("apikey_auth"))
+	})
+}
@@ -0,0 +1,166 @@
+package openapi
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Sources that can supply the values of the provider properties exposed for the security definitions and the header
+// parameters when they are not set in the terraform configuration, which always takes precedence
+const (
+	// propertySourceEnvVar reads the value from the environment variable named after the property in upper case, or the
+	// first of the environment variables configured for the property (env_vars) that is set
+	propertySourceEnvVar = "env"
+	// propertySourceExternal reads the value from the external configuration of the plugin configuration file
+	// (schema_property_external_configuration), usually populated by the command (cmd)
+	propertySourceExternal = "external"
+	// propertySourceDefault uses the default_value of the plugin configuration file
+	propertySourceDefault = "default"
+)
+
+// defaultPropertySourcePrecedence is the precedence used if the service configuration does not specify one
+var defaultPropertySourcePrecedence = []string{propertySourceEnvVar, propertySourceExternal, propertySourceDefault}
+
+// validatePropertySourcePrecedence makes sure the given precedence only contains supported sources, once each
+func validatePropertySourcePrecedence(precedence []string) error {
+	sources := map[string]bool{}
+	for _, source := range precedence {
+		if source != propertySourceEnvVar && source != propertySourceExternal && source != propertySourceDefault {
+			return fmt.Errorf("source '%s' not supported, supported sources: %v", source, defaultPropertySourcePrecedence)
+		}
+		if sources[source] {
+			return fmt.Errorf("source '%s' is specified more than once", source)
+		}
+		sources[source] = true
+	}
+	return nil
+}
+
+// providerPropertySources contains the values the sources supply for a provider property, which are resolved following
+// the precedence configured in the service configuration
+type providerPropertySources struct {
+	propertyName  string
+	precedence    []string
+	envVarNames   []string
+	externalValue string
+	defaultValue  string
+}
+
+// newProviderPropertySources returns the sources of the given property. The values from the plugin configuration file are
+// read when called, whereas the environment variable is read every time the property is resolved
+func newProviderPropertySources(propertyName string, serviceConfiguration ServiceConfiguration) (*providerPropertySources, error) {
+	sources := &providerPropertySources{
+		propertyName: propertyName,
+		precedence:   defaultPropertySourcePrecedence,
+	}
+	if serviceConfiguration == nil {
+		return sources, nil
+	}
+	sources.precedence = getServiceConfigurationOptions(serviceConfiguration).GetPropertySourcePrecedence()
+	schemaPropertyConfiguration := serviceConfiguration.GetSchemaPropertyConfiguration(propertyName)
+	if schemaPropertyConfiguration == nil {
+		return sources, nil
+	}
+	externalValue, err := schemaPropertyConfiguration.GetExternalValue()
+	if err != nil {
+		return nil, err
+	}
+	sources.envVarNames = schemaPropertyConfiguration.GetEnvVarNames()
+	sources.externalValue = externalValue
+	sources.defaultValue = schemaPropertyConfiguration.GetStaticDefaultValue()
+	return sources, nil
+}
+
+func (s providerPropertySources) getEnvVarName() string {
+	return strings.ToUpper(s.propertyName)
+}
+
+// getEnvVarNames returns the environment variables the value is read from in order of precedence: the ones configured
+// for the property if any, otherwise the one named after the property
+func (s providerPropertySources) getEnvVarNames() []string {
+	if len(s.envVarNames) > 0 {
+		return s.envVarNames
+	}
+	return []string{s.getEnvVarName()}
+}
+
+// lookupEnvVar returns the value of the first environment variable that is set, along with its name. Empty values are
+// returned if none of them is set
+func (s providerPropertySources) lookupEnvVar() (string, string) {
+	for _, envVarName := range s.getEnvVarNames() {
+		if value := os.Getenv(envVarName); value != "" {
+			return value, envVarName
+		}
+	}
+	return "", ""
+}
+
+// resolve returns the value supplied by the first source in the precedence that has a value, along with the source. Empty
+// values are returned if none of the sources has a value
+func (s providerPropertySources) resolve() (string, string) {
+	for _, source := range s.precedence {
+		value := ""
+		switch source {
+		case propertySourceEnvVar:
+			value, _ = s.lookupEnvVar()
+		case propertySourceExternal:
+			value = s.externalValue
+		case propertySourceDefault:
+			value = s.defaultValue
+		}
+		if value != "" {
+			return value, source
+		}
+	}
+	return "", ""
+}
+
+// isEnabled returns true if the given source is part of the precedence
+func (s providerPropertySources) isEnabled(source string) bool {
+	for _, enabledSource := range s.precedence {
+		if enabledSource == source {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultFunc returns the function terraform calls to get the value of the property when it is not set in the terraform
+// configuration
+func (s providerPropertySources) defaultFunc() schema.SchemaDefaultFunc {
+	return func() (interface{}, error) {
+		if _, envVarName := s.lookupEnvVar(); !s.isEnabled(propertySourceEnvVar) && envVarName != "" {
+			log.Printf("[WARN] ignoring the environment variable '%s' for the provider property '%s' since the environment variables are not part of the property_source_precedence %v configured", envVarName, s.propertyName, s.precedence)
+		}
+		if value, _ := s.resolve(); value != "" {
+			return value, nil
+		}
+		return nil, nil
+	}
+}
+
+// describeSource returns the description of the given source (e.g. environment variable APIKEY_AUTH). For the environment
+// variables, the one that is set is described
+func (s providerPropertySources) describeSource(source string) string {
+	switch source {
+	case propertySourceEnvVar:
+		if _, envVarName := s.lookupEnvVar(); envVarName != "" {
+			return fmt.Sprintf("environment variable %s", envVarName)
+		}
+		return fmt.Sprintf("environment variable %s", strings.Join(s.getEnvVarNames(), ", "))
+	case propertySourceExternal:
+		return "plugin configuration file external configuration"
+	case propertySourceDefault:
+		return "plugin configuration file"
+	}
+	return "terraform configuration"
+}
+
+// formatPropertySourcePrecedence returns the full precedence of the property sources (e.g. terraform configuration > env > external > default)
+func formatPropertySourcePrecedence(precedence []string) string {
+	return strings.Join(append([]string{"terraform configuration"}, precedence...), " > ")
+}
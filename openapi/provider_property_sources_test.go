@@ -0,0 +1,181 @@
+package openapi
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewProviderPropertySources(t *testing.T) {
+	Convey("Given a service configuration with the schema configuration of a property", t, func() {
+		serviceConfiguration := &ServiceConfigStub{
+			SchemaConfiguration: []*ServiceSchemaPropertyConfigurationStub{
+				{
+					SchemaPropertyName: "apikey_auth",
+					DefaultValue:       "defaultValue",
+					ExternalValue:      "externalValue",
+					EnvVarNames:        []string{"CI_API_KEY"},
+				},
+			},
+			PropertySources: []string{propertySourceDefault, propertySourceEnvVar},
+		}
+		Convey("When newProviderPropertySources is called", func() {
+			propertySources, err := newProviderPropertySources("apikey_auth", serviceConfiguration)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the sources should contain the values of the plugin configuration file and the precedence configured", func() {
+				So(propertySources.externalValue, ShouldEqual, "externalValue")
+				So(propertySources.defaultValue, ShouldEqual, "defaultValue")
+				So(propertySources.precedence, ShouldResemble, []string{propertySourceDefault, propertySourceEnvVar})
+				So(propertySources.getEnvVarName(), ShouldEqual, "APIKEY_AUTH")
+				So(propertySources.getEnvVarNames(), ShouldResemble, []string{"CI_API_KEY"})
+			})
+		})
+	})
+	Convey("Given no service configuration", t, func() {
+		Convey("When newProviderPropertySources is called", func() {
+			propertySources, err := newProviderPropertySources("apikey_auth", nil)
+			Convey("Then the default precedence should be used", func() {
+				So(err, ShouldBeNil)
+				So(propertySources.precedence, ShouldResemble, defaultPropertySourcePrecedence)
+			})
+		})
+	})
+	Convey("Given a service configuration with the schema configuration of a property whose external configuration can not be read", t, func() {
+		serviceConfiguration := &ServiceConfigV1{
+			SchemaConfigurationV1: []ServiceSchemaPropertyConfigurationV1{
+				{
+					SchemaPropertyName: "apikey_auth",
+					ExternalConfiguration: ServiceSchemaPropertyExternalConfigurationV1{
+						File:        "/non/existing/file",
+						ContentType: "raw",
+					},
+				},
+			},
+		}
+		Convey("When newProviderPropertySources is called", func() {
+			_, err := newProviderPropertySources("apikey_auth", serviceConfiguration)
+			Convey("Then the error returned should not be nil", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestProviderPropertySourcesResolve(t *testing.T) {
+	Convey("Given the sources of a property with all the sources supplying a value", t, func() {
+		os.Setenv("APIKEY_AUTH", "envValue")
+		defer os.Unsetenv("APIKEY_AUTH")
+		propertySources := providerPropertySources{
+			propertyName:  "apikey_auth",
+			precedence:    defaultPropertySourcePrecedence,
+			externalValue: "externalValue",
+			defaultValue:  "defaultValue",
+		}
+		Convey("When resolve is called with the default precedence", func() {
+			value, source := propertySources.resolve()
+			Convey("Then the environment variable should take precedence", func() {
+				So(value, ShouldEqual, "envValue")
+				So(source, ShouldEqual, propertySourceEnvVar)
+				So(propertySources.describeSource(source), ShouldEqual, "environment variable APIKEY_AUTH")
+			})
+		})
+		Convey("When resolve is called with a precedence that does not include the environment variables", func() {
+			propertySources.precedence = []string{propertySourceExternal, propertySourceDefault}
+			value, source := propertySources.resolve()
+			Convey("Then the external configuration should take precedence", func() {
+				So(value, ShouldEqual, "externalValue")
+				So(source, ShouldEqual, propertySourceExternal)
+			})
+			Convey("And the default func should return the external configuration value", func() {
+				value, err := propertySources.defaultFunc()()
+				So(err, ShouldBeNil)
+				So(value, ShouldEqual, "externalValue")
+			})
+		})
+		Convey("When resolve is called with the default value first", func() {
+			propertySources.precedence = []string{propertySourceDefault, propertySourceEnvVar, propertySourceExternal}
+			value, source := propertySources.resolve()
+			Convey("Then the default value should take precedence", func() {
+				So(value, ShouldEqual, "defaultValue")
+				So(source, ShouldEqual, propertySourceDefault)
+			})
+		})
+	})
+	Convey("Given the sources of a property with several environment variables configured", t, func() {
+		os.Setenv("CI_API_KEY", "ciValue")
+		os.Setenv("LEGACY_API_KEY", "legacyValue")
+		os.Setenv("APIKEY_AUTH", "envValue")
+		defer os.Unsetenv("CI_API_KEY")
+		defer os.Unsetenv("LEGACY_API_KEY")
+		defer os.Unsetenv("APIKEY_AUTH")
+		propertySources := providerPropertySources{
+			propertyName: "apikey_auth",
+			precedence:   defaultPropertySourcePrecedence,
+			envVarNames:  []string{"TF_API_KEY", "LEGACY_API_KEY", "CI_API_KEY"},
+		}
+		Convey("When resolve is called", func() {
+			value, source := propertySources.resolve()
+			Convey("Then the first configured environment variable that is set should take precedence", func() {
+				So(value, ShouldEqual, "legacyValue")
+				So(source, ShouldEqual, propertySourceEnvVar)
+				So(propertySources.describeSource(source), ShouldEqual, "environment variable LEGACY_API_KEY")
+			})
+		})
+		Convey("When resolve is called and none of the configured environment variables is set", func() {
+			propertySources.envVarNames = []string{"TF_API_KEY"}
+			value, _ := propertySources.resolve()
+			Convey("Then the environment variable named after the property should not be used", func() {
+				So(value, ShouldEqual, "")
+				So(propertySources.describeSource(propertySourceEnvVar), ShouldEqual, "environment variable TF_API_KEY")
+			})
+		})
+	})
+	Convey("Given the sources of a property where none of the sources supplies a value", t, func() {
+		propertySources := providerPropertySources{
+			propertyName: "apikey_auth",
+			precedence:   []string{propertySourceExternal, propertySourceDefault},
+		}
+		Convey("When the default func is called", func() {
+			value, err := propertySources.defaultFunc()()
+			Convey("Then the value should be nil so terraform considers the property not set", func() {
+				So(err, ShouldBeNil)
+				So(value, ShouldBeNil)
+				So(propertySources.describeSource(""), ShouldEqual, "terraform configuration")
+			})
+		})
+	})
+}
+
+func TestValidatePropertySourcePrecedence(t *testing.T) {
+	Convey("Given a precedence containing the supported sources", t, func() {
+		Convey("When validatePropertySourcePrecedence is called", func() {
+			err := validatePropertySourcePrecedence([]string{propertySourceExternal, propertySourceEnvVar})
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+	Convey("Given a precedence containing the terraform configuration", t, func() {
+		Convey("When validatePropertySourcePrecedence is called", func() {
+			err := validatePropertySourcePrecedence([]string{"config", propertySourceEnvVar})
+			Convey("Then the error returned should be the expected one", func() {
+				So(err, ShouldResemble, errors.New("source 'config' not supported, supported sources: [env external default]"))
+			})
+		})
+	})
+}
+
+func TestFormatPropertySourcePrecedence(t *testing.T) {
+	Convey("Given the default precedence", t, func() {
+		Convey("When formatPropertySourcePrecedence is called", func() {
+			formatted := formatPropertySourcePrecedence(defaultPropertySourcePrecedence)
+			Convey("Then the terraform configuration should come first", func() {
+				So(formatted, ShouldEqual, "terraform configuration > env > external > default")
+			})
+		})
+	})
+}
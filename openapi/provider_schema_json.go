@@ -0,0 +1,243 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// PrintSchemaCommand is the command that makes the provider binary print its schema in the same JSON format as 'terraform
+// providers schema -json' instead of serving the plugin (e.g. terraform-provider-<provider_name> print-schema [source_address])
+const PrintSchemaCommand = "print-schema"
+
+// providerSchemaJSONFormatVersion is the version of the format of the JSON document printed by PrintSchemaJSON, which
+// follows the one printed by 'terraform providers schema -json'
+const providerSchemaJSONFormatVersion = "0.1"
+
+const descriptionKindPlain = "plain"
+
+const (
+	nestingModeSingle = "single"
+	nestingModeList   = "list"
+	nestingModeSet    = "set"
+	nestingModeMap    = "map"
+)
+
+// providerSchemasJSON describes the document printed by 'terraform providers schema -json', which is the input tools like
+// cdktf use to generate the bindings of a provider
+type providerSchemasJSON struct {
+	FormatVersion   string                         `json:"format_version"`
+	ProviderSchemas map[string]*providerSchemaJSON `json:"provider_schemas"`
+}
+
+type providerSchemaJSON struct {
+	Provider          *schemaJSON            `json:"provider"`
+	ResourceSchemas   map[string]*schemaJSON `json:"resource_schemas"`
+	DataSourceSchemas map[string]*schemaJSON `json:"data_source_schemas"`
+}
+
+type schemaJSON struct {
+	Version int        `json:"version"`
+	Block   *blockJSON `json:"block"`
+}
+
+type blockJSON struct {
+	Attributes      map[string]*attributeJSON `json:"attributes,omitempty"`
+	BlockTypes      map[string]*blockTypeJSON `json:"block_types,omitempty"`
+	Description     string                    `json:"description,omitempty"`
+	DescriptionKind string                    `json:"description_kind"`
+	Deprecated      bool                      `json:"deprecated,omitempty"`
+}
+
+type attributeJSON struct {
+	// Type contains the attribute type as serialised by terraform (e.g. "string", ["list","string"], ["object",{...}])
+	Type            interface{} `json:"type"`
+	Description     string      `json:"description,omitempty"`
+	DescriptionKind string      `json:"description_kind"`
+	Deprecated      bool        `json:"deprecated,omitempty"`
+	Required        bool        `json:"required,omitempty"`
+	Optional        bool        `json:"optional,omitempty"`
+	Computed        bool        `json:"computed,omitempty"`
+	Sensitive       bool        `json:"sensitive,omitempty"`
+}
+
+type blockTypeJSON struct {
+	NestingMode string     `json:"nesting_mode"`
+	Block       *blockJSON `json:"block"`
+	MinItems    int        `json:"min_items,omitempty"`
+	MaxItems    int        `json:"max_items,omitempty"`
+}
+
+// PrintSchemaJSON writes into the given writer the provider schema in the same JSON format 'terraform providers schema -json'
+// prints, so it can be consumed by tools that generate bindings out of the provider schema (e.g. cdktf get). The source
+// address is the key the provider schema is registered with in the document (e.g. registry.terraform.io/dikhan/openapi);
+// if empty the provider name is used.
+func (p *ProviderOpenAPI) PrintSchemaJSON(w io.Writer, sourceAddress string) error {
+	provider, err := p.CreateSchemaProvider()
+	if err != nil {
+		return err
+	}
+	if sourceAddress == "" {
+		sourceAddress = p.ProviderName
+	}
+	return printSchemaJSON(w, sourceAddress, provider)
+}
+
+func printSchemaJSON(w io.Writer, sourceAddress string, provider *schema.Provider) error {
+	providerSchema := &providerSchemaJSON{
+		Provider:          &schemaJSON{Block: newBlockJSON(provider.Schema)},
+		ResourceSchemas:   map[string]*schemaJSON{},
+		DataSourceSchemas: map[string]*schemaJSON{},
+	}
+	for resourceName, resource := range provider.ResourcesMap {
+		providerSchema.ResourceSchemas[resourceName] = &schemaJSON{Version: resource.SchemaVersion, Block: newResourceBlockJSON(resource)}
+	}
+	for dataSourceName, dataSource := range provider.DataSourcesMap {
+		providerSchema.DataSourceSchemas[dataSourceName] = &schemaJSON{Version: dataSource.SchemaVersion, Block: newResourceBlockJSON(dataSource)}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(&providerSchemasJSON{
+		FormatVersion:   providerSchemaJSONFormatVersion,
+		ProviderSchemas: map[string]*providerSchemaJSON{sourceAddress: providerSchema},
+	})
+}
+
+// newResourceBlockJSON returns the block describing the given resource (or data source), including the attributes terraform
+// adds implicitly to all of them: the id attribute and the timeouts block (only if the resource has timeouts configured)
+func newResourceBlockJSON(resource *schema.Resource) *blockJSON {
+	block := newBlockJSON(resource.Schema)
+	block.Deprecated = resource.DeprecationMessage != ""
+	if _, exists := block.Attributes["id"]; !exists {
+		block.Attributes["id"] = &attributeJSON{Type: "string", DescriptionKind: descriptionKindPlain, Optional: true, Computed: true}
+	}
+	if resource.Timeouts != nil {
+		timeouts := &blockJSON{Attributes: map[string]*attributeJSON{}, DescriptionKind: descriptionKindPlain}
+		configuredTimeouts := map[string]bool{
+			schema.TimeoutCreate:  resource.Timeouts.Create != nil,
+			schema.TimeoutRead:    resource.Timeouts.Read != nil,
+			schema.TimeoutUpdate:  resource.Timeouts.Update != nil,
+			schema.TimeoutDelete:  resource.Timeouts.Delete != nil,
+			schema.TimeoutDefault: resource.Timeouts.Default != nil,
+		}
+		for timeoutName, configured := range configuredTimeouts {
+			if configured {
+				timeouts.Attributes[timeoutName] = &attributeJSON{Type: "string", DescriptionKind: descriptionKindPlain, Optional: true}
+			}
+		}
+		if block.BlockTypes == nil {
+			block.BlockTypes = map[string]*blockTypeJSON{}
+		}
+		block.BlockTypes[schema.TimeoutsConfigKey] = &blockTypeJSON{NestingMode: nestingModeSingle, Block: timeouts}
+	}
+	return block
+}
+
+// newBlockJSON returns the block describing the given schema following the same rules terraform applies to the helper
+// schemas: properties with elements of type resource are described as nested blocks (unless they are computed only, in
+// which case they are described as attributes of type object) and the rest as attributes
+func newBlockJSON(schemaMap map[string]*schema.Schema) *blockJSON {
+	block := &blockJSON{
+		Attributes:      map[string]*attributeJSON{},
+		DescriptionKind: descriptionKindPlain,
+	}
+	for propertyName, propertySchema := range schemaMap {
+		if isNestedBlock(propertySchema) {
+			if block.BlockTypes == nil {
+				block.BlockTypes = map[string]*blockTypeJSON{}
+			}
+			block.BlockTypes[propertyName] = newBlockTypeJSON(propertySchema)
+			continue
+		}
+		block.Attributes[propertyName] = newAttributeJSON(propertySchema)
+	}
+	return block
+}
+
+func isNestedBlock(propertySchema *schema.Schema) bool {
+	if _, isResource := propertySchema.Elem.(*schema.Resource); !isResource {
+		return false
+	}
+	if propertySchema.ConfigMode == schema.SchemaConfigModeAttr {
+		return false
+	}
+	return !propertySchema.Computed || propertySchema.Optional
+}
+
+func newBlockTypeJSON(propertySchema *schema.Schema) *blockTypeJSON {
+	blockType := &blockTypeJSON{
+		Block:    newBlockJSON(propertySchema.Elem.(*schema.Resource).Schema),
+		MinItems: propertySchema.MinItems,
+		MaxItems: propertySchema.MaxItems,
+	}
+	blockType.Block.Description = propertySchema.Description
+	switch propertySchema.Type {
+	case schema.TypeSet:
+		blockType.NestingMode = nestingModeSet
+	case schema.TypeMap:
+		blockType.NestingMode = nestingModeMap
+	default:
+		blockType.NestingMode = nestingModeList
+	}
+	if propertySchema.Required && propertySchema.MinItems == 0 {
+		blockType.MinItems = 1
+	}
+	if propertySchema.Optional && propertySchema.MinItems > 0 {
+		blockType.MinItems = 0
+	}
+	return blockType
+}
+
+func newAttributeJSON(propertySchema *schema.Schema) *attributeJSON {
+	attribute := &attributeJSON{
+		Type:            newTypeJSON(propertySchema),
+		Description:     propertySchema.Description,
+		DescriptionKind: descriptionKindPlain,
+		Deprecated:      propertySchema.Deprecated != "",
+		Required:        propertySchema.Required,
+		Optional:        propertySchema.Optional,
+		Computed:        propertySchema.Computed,
+		Sensitive:       propertySchema.Sensitive,
+	}
+	// Required properties with a default func that returns a value (e.g. values coming from environment variables or the
+	// plugin configuration file) do not need to be set in the configuration, so terraform describes them as optional
+	if propertySchema.Required && propertySchema.DefaultFunc != nil {
+		if value, err := propertySchema.DefaultFunc(); err != nil || value != nil {
+			attribute.Required = false
+			attribute.Optional = true
+		}
+	}
+	return attribute
+}
+
+// newTypeJSON returns the type of the given schema as serialised by terraform
+func newTypeJSON(propertySchema *schema.Schema) interface{} {
+	switch propertySchema.Type {
+	case schema.TypeBool:
+		return "bool"
+	case schema.TypeInt, schema.TypeFloat:
+		return "number"
+	case schema.TypeList:
+		return []interface{}{"list", newElemTypeJSON(propertySchema.Elem)}
+	case schema.TypeSet:
+		return []interface{}{"set", newElemTypeJSON(propertySchema.Elem)}
+	case schema.TypeMap:
+		return []interface{}{"map", newElemTypeJSON(propertySchema.Elem)}
+	}
+	return "string"
+}
+
+func newElemTypeJSON(elem interface{}) interface{} {
+	switch e := elem.(type) {
+	case *schema.Schema:
+		return newTypeJSON(e)
+	case *schema.Resource:
+		objectType := map[string]interface{}{}
+		for propertyName, propertySchema := range e.Schema {
+			objectType[propertyName] = newTypeJSON(propertySchema)
+		}
+		return []interface{}{"object", objectType}
+	}
+	return "string"
+}
@@ -0,0 +1,99 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintSchemaJSON(t *testing.T) {
+	defaultTimeout := 10 * time.Minute
+	objectResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"origin_port": {Type: schema.TypeInt, Optional: true},
+			"protocol":    {Type: schema.TypeString, Required: true},
+		},
+	}
+	provider := &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"apikey_auth": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				DefaultFunc: func() (interface{}, error) { return "someValue", nil },
+			},
+			"x_request_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				DefaultFunc: func() (interface{}, error) { return nil, nil },
+			},
+			providerPropertyDisableResponseCache: {Type: schema.TypeBool, Optional: true, Description: "some description"},
+		},
+		ResourcesMap: map[string]*schema.Resource{
+			"openapi_cdn_v1": {
+				Schema: map[string]*schema.Schema{
+					"label":        {Type: schema.TypeString, Required: true, Description: "The label of the cdn. Allowed values: some, other"},
+					"ips":          {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+					"object_props": {Type: schema.TypeList, Required: true, MaxItems: 1, Elem: objectResource},
+					"computed_obj": {Type: schema.TypeList, Computed: true, Elem: objectResource},
+				},
+				Timeouts: &schema.ResourceTimeout{Create: &defaultTimeout, Delete: &defaultTimeout},
+			},
+			"openapi_cdns_v1": {
+				Schema:             map[string]*schema.Schema{"label": {Type: schema.TypeString, Required: true}},
+				DeprecationMessage: "'openapi_cdns_v1' is deprecated, please use 'openapi_cdn_v1' instead",
+			},
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"openapi_cdn_v1": {
+				Schema: map[string]*schema.Schema{"filter": {Type: schema.TypeSet, Optional: true, Elem: objectResource}},
+			},
+		},
+	}
+
+	var out bytes.Buffer
+	err := printSchemaJSON(&out, "registry.terraform.io/dikhan/openapi", provider)
+	require.NoError(t, err)
+
+	var document providerSchemasJSON
+	require.NoError(t, json.Unmarshal(out.Bytes(), &document))
+	assert.Equal(t, providerSchemaJSONFormatVersion, document.FormatVersion)
+	require.Contains(t, document.ProviderSchemas, "registry.terraform.io/dikhan/openapi")
+	providerSchema := document.ProviderSchemas["registry.terraform.io/dikhan/openapi"]
+
+	// provider properties
+	providerBlock := providerSchema.Provider.Block
+	assert.Equal(t, &attributeJSON{Type: "string", DescriptionKind: descriptionKindPlain, Optional: true, Sensitive: true}, providerBlock.Attributes["apikey_auth"], "required properties with a default value should be described as optional")
+	assert.Equal(t, &attributeJSON{Type: "string", DescriptionKind: descriptionKindPlain, Required: true}, providerBlock.Attributes["x_request_id"], "required properties without a default value should be described as required")
+	assert.Equal(t, &attributeJSON{Type: "bool", Description: "some description", DescriptionKind: descriptionKindPlain, Optional: true}, providerBlock.Attributes[providerPropertyDisableResponseCache])
+
+	// resources
+	resourceBlock := providerSchema.ResourceSchemas["openapi_cdn_v1"].Block
+	assert.Equal(t, &attributeJSON{Type: "string", Description: "The label of the cdn. Allowed values: some, other", DescriptionKind: descriptionKindPlain, Required: true}, resourceBlock.Attributes["label"])
+	assert.Equal(t, []interface{}{"list", "string"}, resourceBlock.Attributes["ips"].Type)
+	assert.Equal(t, &attributeJSON{Type: "string", DescriptionKind: descriptionKindPlain, Optional: true, Computed: true}, resourceBlock.Attributes["id"])
+	assert.Equal(t, []interface{}{"list", []interface{}{"object", map[string]interface{}{"origin_port": "number", "protocol": "string"}}}, resourceBlock.Attributes["computed_obj"].Type, "computed only properties with object elements should be described as attributes")
+	require.Contains(t, resourceBlock.BlockTypes, "object_props")
+	assert.Equal(t, nestingModeList, resourceBlock.BlockTypes["object_props"].NestingMode)
+	assert.Equal(t, 1, resourceBlock.BlockTypes["object_props"].MinItems)
+	assert.Equal(t, 1, resourceBlock.BlockTypes["object_props"].MaxItems)
+	assert.Contains(t, resourceBlock.BlockTypes["object_props"].Block.Attributes, "protocol")
+	require.Contains(t, resourceBlock.BlockTypes, "timeouts")
+	assert.Equal(t, nestingModeSingle, resourceBlock.BlockTypes["timeouts"].NestingMode)
+	assert.Len(t, resourceBlock.BlockTypes["timeouts"].Block.Attributes, 2)
+	assert.Contains(t, resourceBlock.BlockTypes["timeouts"].Block.Attributes, schema.TimeoutCreate)
+	assert.Contains(t, resourceBlock.BlockTypes["timeouts"].Block.Attributes, schema.TimeoutDelete)
+	assert.True(t, providerSchema.ResourceSchemas["openapi_cdns_v1"].Block.Deprecated)
+	assert.NotContains(t, providerSchema.ResourceSchemas["openapi_cdns_v1"].Block.BlockTypes, "timeouts")
+
+	// data sources
+	dataSourceBlock := providerSchema.DataSourceSchemas["openapi_cdn_v1"].Block
+	require.Contains(t, dataSourceBlock.BlockTypes, "filter")
+	assert.Equal(t, nestingModeSet, dataSourceBlock.BlockTypes["filter"].NestingMode)
+	assert.Equal(t, 0, dataSourceBlock.BlockTypes["filter"].MinItems)
+}
@@ -6,9 +6,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -48,6 +49,10 @@ func TestOpenAPIProvider(t *testing.T) {
 			Convey("And the error message returned should be", func() {
 				So(err.Error(), ShouldEqual, "plugin OpenAPI spec analyser error: failed to retrieve the OpenAPI document from '"+attemptedSwaggerURL+`' - error = could not access document at "`+attemptedSwaggerURL+`" [404 Not Found] `)
 			})
+			Convey("And the error returned should be a SpecFetchError so the failure can be identified programmatically", func() {
+				So(err, ShouldHaveSameTypeAs, &SpecFetchError{})
+				So(err.(*SpecFetchError).URL, ShouldEqual, attemptedSwaggerURL)
+			})
 			Convey("Then the schema provider returned should also be nil", func() {
 				So(tfProvider, ShouldBeNil)
 			})
@@ -210,7 +215,7 @@ definitions:
 				Convey("the provider schema should be the expected one", func() {
 					So(tfProvider.Schema, ShouldNotBeNil)
 					So(tfProvider.Schema, ShouldContainKey, "apikey_auth")
-					So(tfProvider.Schema["apikey_auth"].Required, ShouldBeTrue)
+					So(tfProvider.Schema["apikey_auth"].Optional, ShouldBeTrue)
 					So(tfProvider.Schema["apikey_auth"].Type, ShouldEqual, schema.TypeString)
 				})
 				Convey("the provider resource map should contain the cdn resource with the expected configuration", func() {
@@ -227,10 +232,10 @@ definitions:
 						So(tfProvider.ResourcesMap[resourceName].Schema["label"].Computed, ShouldBeFalse)
 					})
 					Convey("the provider cdn resource should have the expected operations configured", func() {
-						So(tfProvider.ResourcesMap[resourceName].Create, ShouldNotBeNil)
-						So(tfProvider.ResourcesMap[resourceName].Read, ShouldNotBeNil)
-						So(tfProvider.ResourcesMap[resourceName].Update, ShouldNotBeNil)
-						So(tfProvider.ResourcesMap[resourceName].Delete, ShouldNotBeNil)
+						So(tfProvider.ResourcesMap[resourceName].CreateContext, ShouldNotBeNil)
+						So(tfProvider.ResourcesMap[resourceName].ReadContext, ShouldNotBeNil)
+						So(tfProvider.ResourcesMap[resourceName].UpdateContext, ShouldNotBeNil)
+						So(tfProvider.ResourcesMap[resourceName].DeleteContext, ShouldNotBeNil)
 						So(tfProvider.ResourcesMap[resourceName].Importer, ShouldNotBeNil)
 					})
 				})
@@ -249,10 +254,10 @@ definitions:
 						So(tfProvider.DataSourcesMap[dataSourceInstanceName].Schema["label"].Computed, ShouldBeTrue)
 					})
 					Convey("the provider cdn data source instance should have the expected operations configured", func() {
-						So(tfProvider.DataSourcesMap[dataSourceInstanceName].Create, ShouldBeNil)
-						So(tfProvider.DataSourcesMap[dataSourceInstanceName].Read, ShouldNotBeNil)
-						So(tfProvider.DataSourcesMap[dataSourceInstanceName].Update, ShouldBeNil)
-						So(tfProvider.DataSourcesMap[dataSourceInstanceName].Delete, ShouldBeNil)
+						So(tfProvider.DataSourcesMap[dataSourceInstanceName].CreateContext, ShouldBeNil)
+						So(tfProvider.DataSourcesMap[dataSourceInstanceName].ReadContext, ShouldNotBeNil)
+						So(tfProvider.DataSourcesMap[dataSourceInstanceName].UpdateContext, ShouldBeNil)
+						So(tfProvider.DataSourcesMap[dataSourceInstanceName].DeleteContext, ShouldBeNil)
 						So(tfProvider.DataSourcesMap[dataSourceInstanceName].Importer, ShouldBeNil)
 					})
 				})
@@ -269,10 +274,10 @@ definitions:
 						So(tfProvider.ResourcesMap[resourceName].Schema["name"].Computed, ShouldBeFalse)
 					})
 					Convey("the provider cdn resource should have the expected operations configured", func() {
-						So(tfProvider.ResourcesMap[resourceName].Create, ShouldNotBeNil)
-						So(tfProvider.ResourcesMap[resourceName].Read, ShouldNotBeNil)
-						So(tfProvider.ResourcesMap[resourceName].Update, ShouldNotBeNil)
-						So(tfProvider.ResourcesMap[resourceName].Delete, ShouldNotBeNil)
+						So(tfProvider.ResourcesMap[resourceName].CreateContext, ShouldNotBeNil)
+						So(tfProvider.ResourcesMap[resourceName].ReadContext, ShouldNotBeNil)
+						So(tfProvider.ResourcesMap[resourceName].UpdateContext, ShouldNotBeNil)
+						So(tfProvider.ResourcesMap[resourceName].DeleteContext, ShouldNotBeNil)
 						So(tfProvider.ResourcesMap[resourceName].Importer, ShouldNotBeNil)
 					})
 				})
@@ -291,15 +296,15 @@ definitions:
 						So(tfProvider.DataSourcesMap[dataSourceInstanceName].Schema["name"].Computed, ShouldBeTrue)
 					})
 					Convey("the provider cdn data source instance should have the expected operations configured", func() {
-						So(tfProvider.DataSourcesMap[dataSourceInstanceName].Create, ShouldBeNil)
-						So(tfProvider.DataSourcesMap[dataSourceInstanceName].Read, ShouldNotBeNil)
-						So(tfProvider.DataSourcesMap[dataSourceInstanceName].Update, ShouldBeNil)
-						So(tfProvider.DataSourcesMap[dataSourceInstanceName].Delete, ShouldBeNil)
+						So(tfProvider.DataSourcesMap[dataSourceInstanceName].CreateContext, ShouldBeNil)
+						So(tfProvider.DataSourcesMap[dataSourceInstanceName].ReadContext, ShouldNotBeNil)
+						So(tfProvider.DataSourcesMap[dataSourceInstanceName].UpdateContext, ShouldBeNil)
+						So(tfProvider.DataSourcesMap[dataSourceInstanceName].DeleteContext, ShouldBeNil)
 						So(tfProvider.DataSourcesMap[dataSourceInstanceName].Importer, ShouldBeNil)
 					})
 				})
 				Convey("the provider configuration function should not be nil", func() {
-					So(tfProvider.ConfigureFunc, ShouldNotBeNil)
+					So(tfProvider.ConfigureContextFunc, ShouldNotBeNil)
 				})
 			})
 		})
@@ -377,12 +382,14 @@ definitions:
 				Convey("the provider schema should be the expected one", func() {
 					So(tfProvider.Schema, ShouldNotBeNil)
 					So(tfProvider.Schema, ShouldContainKey, "apikey_auth")
-					So(tfProvider.Schema["apikey_auth"].Required, ShouldBeTrue)
+					So(tfProvider.Schema["apikey_auth"].Optional, ShouldBeTrue)
 					So(tfProvider.Schema["apikey_auth"].Type, ShouldEqual, schema.TypeString)
 				})
 				Convey("the provider dataSource map should contain the cdn resource with the expected configuration", func() {
 					So(tfProvider.DataSourcesMap, ShouldNotBeNil)
-					So(len(tfProvider.DataSourcesMap), ShouldEqual, 1)
+					// the cdn data source plus the built-in provider info data source
+					So(len(tfProvider.DataSourcesMap), ShouldEqual, 2)
+					So(tfProvider.DataSourcesMap, ShouldContainKey, fmt.Sprintf("%s_provider_info", providerName))
 
 					resourceName := fmt.Sprintf("%s_cdn_datasource_v1", providerName)
 					So(tfProvider.DataSourcesMap, ShouldContainKey, resourceName)
@@ -408,9 +415,9 @@ definitions:
 						So(elements["values"].Type, ShouldEqual, schema.TypeList)
 					})
 					Convey("the provider cdn-datasource data source should have only the READ operation configured", func() {
-						So(tfProvider.DataSourcesMap[resourceName].Read, ShouldNotBeNil)
-						So(tfProvider.DataSourcesMap[resourceName].Create, ShouldBeNil)
-						So(tfProvider.DataSourcesMap[resourceName].Delete, ShouldBeNil)
+						So(tfProvider.DataSourcesMap[resourceName].ReadContext, ShouldNotBeNil)
+						So(tfProvider.DataSourcesMap[resourceName].CreateContext, ShouldBeNil)
+						So(tfProvider.DataSourcesMap[resourceName].DeleteContext, ShouldBeNil)
 					})
 
 					Convey("and the provider resource map must be nil as no resources are configured in the swagger", func() {
@@ -418,7 +425,7 @@ definitions:
 					})
 				})
 				Convey("the provider configuration function should not be nil", func() {
-					So(tfProvider.ConfigureFunc, ShouldNotBeNil)
+					So(tfProvider.ConfigureContextFunc, ShouldNotBeNil)
 				})
 			})
 		})
@@ -471,7 +478,9 @@ definitions:
 				})
 				Convey("the provider dataSource map should contain the cdn resource with the expected configuration", func() {
 					So(tfProvider.DataSourcesMap, ShouldNotBeNil)
-					So(len(tfProvider.DataSourcesMap), ShouldEqual, 1)
+					// the cdn data source plus the built-in provider info data source
+					So(len(tfProvider.DataSourcesMap), ShouldEqual, 2)
+					So(tfProvider.DataSourcesMap, ShouldContainKey, fmt.Sprintf("%s_provider_info", providerName))
 
 					dataSourceName := fmt.Sprintf("%s_cdns_v1_firewalls", providerName)
 					So(tfProvider.DataSourcesMap, ShouldContainKey, dataSourceName)
@@ -497,7 +506,7 @@ definitions:
 						So(elements["values"].Type, ShouldEqual, schema.TypeList)
 					})
 					Convey("the provider cdn-datasource data source should have only the READ operation configured", func() {
-						So(tfProvider.DataSourcesMap[dataSourceName].Read, ShouldNotBeNil)
+						So(tfProvider.DataSourcesMap[dataSourceName].ReadContext, ShouldNotBeNil)
 					})
 
 					Convey("and the provider resource map must be nil as no resources are configured in the swagger", func() {
@@ -505,7 +514,7 @@ definitions:
 					})
 				})
 				Convey("the provider configuration function should not be nil", func() {
-					So(tfProvider.ConfigureFunc, ShouldNotBeNil)
+					So(tfProvider.ConfigureContextFunc, ShouldNotBeNil)
 				})
 			})
 		})
@@ -604,19 +613,18 @@ definitions:
 						assertTerraformSchemaProperty(t, nestedObject.Elem.(*schema.Resource).Schema["name"], schema.TypeString, false, true)
 						assertTerraformSchemaProperty(t, nestedObject.Elem.(*schema.Resource).Schema["object_property"], schema.TypeMap, false, false)
 						object := nestedObject.Elem.(*schema.Resource).Schema["object_property"]
-						assertTerraformSchemaProperty(t, object.Elem.(*schema.Resource).Schema["account"], schema.TypeString, false, false)
-						assertTerraformSchemaProperty(t, object.Elem.(*schema.Resource).Schema["read_only"], schema.TypeString, false, true)
+						So(object.Elem, ShouldResemble, &schema.Schema{Type: schema.TypeString})
 					})
 					Convey("the provider cdn resource should have the expected operations configured", func() {
-						So(tfProvider.ResourcesMap[resourceName].Create, ShouldNotBeNil)
-						So(tfProvider.ResourcesMap[resourceName].Read, ShouldNotBeNil)
-						So(tfProvider.ResourcesMap[resourceName].Update, ShouldNotBeNil)
-						So(tfProvider.ResourcesMap[resourceName].Delete, ShouldNotBeNil)
+						So(tfProvider.ResourcesMap[resourceName].CreateContext, ShouldNotBeNil)
+						So(tfProvider.ResourcesMap[resourceName].ReadContext, ShouldNotBeNil)
+						So(tfProvider.ResourcesMap[resourceName].UpdateContext, ShouldNotBeNil)
+						So(tfProvider.ResourcesMap[resourceName].DeleteContext, ShouldNotBeNil)
 						So(tfProvider.ResourcesMap[resourceName].Importer, ShouldNotBeNil)
 					})
 				})
 				Convey("the provider configuration function should not be nil", func() {
-					So(tfProvider.ConfigureFunc, ShouldNotBeNil)
+					So(tfProvider.ConfigureContextFunc, ShouldNotBeNil)
 				})
 			})
 		})
@@ -846,3 +854,65 @@ func TestGetServiceConfiguration(t *testing.T) {
 		})
 	})
 }
+
+func TestCreateSpecAnalyser_EmbeddedSpec(t *testing.T) {
+	remoteSpec := `{"swagger":"2.0","host":"localhost","paths":{"/v1/cdns":{"post":{"x-terraform-resource-name":"cdn","parameters":[{"in":"body","name":"body","schema":{"$ref":"#/definitions/CDN"}}],"responses":{"201":{"description":"created","schema":{"$ref":"#/definitions/CDN"}}}}},"/v1/cdns/{id}":{"get":{"parameters":[{"in":"path","name":"id","type":"string","required":true}],"responses":{"200":{"description":"ok","schema":{"$ref":"#/definitions/CDN"}}}}}},"definitions":{"CDN":{"type":"object","properties":{"id":{"type":"string","readOnly":true},"label":{"type":"string"}}}}}`
+	embeddedSpec := strings.Replace(remoteSpec, "cdn", "lb", -1)
+	Convey("Given a provider with an embedded OpenAPI document", t, func() {
+		providerName := "embedded"
+		offlineEnvVar := fmt.Sprintf(otfVarOffline, providerName)
+		defer os.Unsetenv(offlineEnvVar)
+		apiAvailable := true
+		swaggerServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !apiAvailable {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(remoteSpec))
+		}))
+		defer swaggerServer.Close()
+		p := ProviderOpenAPI{ProviderName: providerName, EmbeddedSpec: []byte(embeddedSpec)}
+		serviceConfiguration := &ServiceConfigStub{SwaggerURL: swaggerServer.URL}
+		Convey("When createSpecAnalyser is called and the OpenAPI document can be retrieved from the swagger URL", func() {
+			specAnalyser, err := p.createSpecAnalyser(serviceConfiguration)
+			Convey("Then the spec analyser returned should be the one of the remote document", func() {
+				So(err, ShouldBeNil)
+				resources, err := specAnalyser.GetTerraformCompliantResources()
+				So(err, ShouldBeNil)
+				So(resources, ShouldHaveLength, 1)
+				So(resources[0].getResourceName(), ShouldEqual, "cdn_v1")
+			})
+		})
+		Convey("When createSpecAnalyser is called and the OpenAPI document can not be retrieved from the swagger URL", func() {
+			apiAvailable = false
+			specAnalyser, err := p.createSpecAnalyser(serviceConfiguration)
+			Convey("Then the spec analyser returned should be the one of the embedded document", func() {
+				So(err, ShouldBeNil)
+				resources, err := specAnalyser.GetTerraformCompliantResources()
+				So(err, ShouldBeNil)
+				So(resources, ShouldHaveLength, 1)
+				So(resources[0].getResourceName(), ShouldEqual, "lb_v1")
+			})
+		})
+		Convey("When createSpecAnalyser is called with the provider configured to run in offline mode", func() {
+			os.Setenv(offlineEnvVar, "true")
+			specAnalyser, err := p.createSpecAnalyser(serviceConfiguration)
+			Convey("Then the spec analyser returned should be the one of the embedded document even though the remote document is available", func() {
+				So(err, ShouldBeNil)
+				resources, err := specAnalyser.GetTerraformCompliantResources()
+				So(err, ShouldBeNil)
+				So(resources, ShouldHaveLength, 1)
+				So(resources[0].getResourceName(), ShouldEqual, "lb_v1")
+			})
+		})
+		Convey("When createSpecAnalyser is called with the provider configured to run in offline mode but without embedded document", func() {
+			os.Setenv(offlineEnvVar, "true")
+			p.EmbeddedSpec = nil
+			_, err := p.createSpecAnalyser(serviceConfiguration)
+			Convey("Then the error returned should explain the provider can not run in offline mode", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "provider 'embedded' is configured to run in offline mode (OTF_VAR_embedded_OFFLINE) but it does not have an embedded OpenAPI document")
+			})
+		})
+	})
+}
@@ -0,0 +1,166 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	apiDefaultPath      = "path"
+	apiDefaultValuePath = "value_path"
+)
+
+// apiDefault defines where the default value of a property configured with the x-terraform-default-from-api extension is
+// fetched from: the API endpoint path (e.g. /v1/defaults) and the path of the value in the response payload (e.g.
+// $.network.id), which is the property name if not specified
+type apiDefault struct {
+	path      string
+	valuePath string
+}
+
+// parseAPIDefaultExtension parses the value of the x-terraform-default-from-api extension, which is an object containing
+// the path of the API endpoint returning the defaults and optionally the path of the value in the response payload (e.g.
+// {path: /v1/defaults, value_path: $.network.id})
+func parseAPIDefaultExtension(value interface{}) (*apiDefault, error) {
+	properties, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected an object value but got '%v'", value)
+	}
+	d := &apiDefault{}
+	var names []string
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var err error
+		property, isString := properties[name].(string)
+		switch {
+		case name != apiDefaultPath && name != apiDefaultValuePath:
+			err = fmt.Errorf("property not supported, supported properties: %s", strings.Join([]string{apiDefaultPath, apiDefaultValuePath}, ", "))
+		case !isString:
+			err = fmt.Errorf("expected a string value but got '%v'", properties[name])
+		case name == apiDefaultPath:
+			if !strings.HasPrefix(property, "/") || strings.ContainsAny(property, "{}?") {
+				err = fmt.Errorf("the path must start with '/' and can not contain path parameters nor query parameters")
+			}
+			d.path = property
+		case name == apiDefaultValuePath:
+			_, err = parsePayloadPath(property)
+			d.valuePath = property
+		}
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not valid: %s", name, err)
+		}
+	}
+	if d.path == "" {
+		return nil, fmt.Errorf("'%s' is required", apiDefaultPath)
+	}
+	return d, nil
+}
+
+// validateAPIDefaultExtension is the Validate hook of the x-terraform-default-from-api extension
+func validateAPIDefaultExtension(value interface{}) error {
+	_, err := parseAPIDefaultExtension(value)
+	return err
+}
+
+// apiDefaults fetches the default values of the properties configured with the x-terraform-default-from-api extension.
+// The API responses are cached by provider client and path so each endpoint is only called once per provider (provider
+// aliases may talk to different deployments of the API) during the terraform run, regardless of the number of properties
+// and resources defaulting to its values. A nil apiDefaults is valid and means the responses are not cached.
+type apiDefaults struct {
+	mutex sync.Mutex
+	// responses contains the payloads returned by the API keyed by provider client and path
+	responses map[ClientOpenAPI]map[string]map[string]interface{}
+}
+
+func newAPIDefaults() *apiDefaults {
+	return &apiDefaults{responses: map[ClientOpenAPI]map[string]map[string]interface{}{}}
+}
+
+// getDefaultValue returns the default value of the given property fetched from the API with the given provider client
+func (a *apiDefaults) getDefaultValue(ctx context.Context, providerClient ClientOpenAPI, resourceName string, property *specSchemaDefinitionProperty) (interface{}, error) {
+	value, err := a.getValue(ctx, providerClient, property)
+	if err != nil {
+		return nil, fmt.Errorf("[resource='%s'] failed to fetch the default value of property '%s' from %s: %s", resourceName, property.Name, property.DefaultFromAPI.path, err)
+	}
+	if value != nil {
+		log.Printf("[DEBUG] [resource='%s'] property '%s' defaults to the value returned by %s: %v", resourceName, property.Name, property.DefaultFromAPI.path, value)
+	}
+	return value, nil
+}
+
+// getValue returns the default value of the property found in the payload returned by the API, converted to the type
+// terraform expects for the property
+func (a *apiDefaults) getValue(ctx context.Context, providerClient ClientOpenAPI, property *specSchemaDefinitionProperty) (interface{}, error) {
+	payload, err := a.getPayload(ctx, providerClient, property.DefaultFromAPI.path)
+	if err != nil || payload == nil {
+		return nil, err
+	}
+	valuePath := property.DefaultFromAPI.valuePath
+	if valuePath == "" {
+		valuePath = property.Name
+	}
+	value, err := getPayloadValue(valuePath, payload)
+	if err != nil {
+		return nil, err
+	}
+	value, err = convertPayloadToLocalStateDataValue(property, value, false)
+	if err != nil {
+		return nil, err
+	}
+	if value != nil && !isAPIDefaultValueOfType(property, value) {
+		return nil, fmt.Errorf("value '%v' is not of type %s", value, property.Type)
+	}
+	return value, nil
+}
+
+// getPayload returns the payload returned by the API for the given path when called with the given provider client
+func (a *apiDefaults) getPayload(ctx context.Context, providerClient ClientOpenAPI, path string) (map[string]interface{}, error) {
+	if a != nil {
+		a.mutex.Lock()
+		defer a.mutex.Unlock()
+		if payload, exists := a.responses[providerClient][path]; exists {
+			return payload, nil
+		}
+	}
+	collectionPath, id := apiObjectResourceFactory{}.splitPath(path)
+	resource := apiObjectSpecResource{path: collectionPath}
+	payload := map[string]interface{}{}
+	res, err := contextClient(providerClient).GetContext(ctx, resource, id, &payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkHTTPStatusCode(resource, res, []int{http.StatusOK}); err != nil {
+		return nil, err
+	}
+	if a != nil {
+		if a.responses[providerClient] == nil {
+			a.responses[providerClient] = map[string]map[string]interface{}{}
+		}
+		a.responses[providerClient][path] = payload
+	}
+	return payload, nil
+}
+
+// isAPIDefaultValueOfType returns true if the given value is of the type terraform expects for the property
+func isAPIDefaultValueOfType(property *specSchemaDefinitionProperty, value interface{}) bool {
+	switch property.Type {
+	case typeString:
+		return reflect.TypeOf(value).Kind() == reflect.String
+	case typeInt:
+		return reflect.TypeOf(value).Kind() == reflect.Int
+	case typeFloat:
+		return reflect.TypeOf(value).Kind() == reflect.Float64
+	case typeBool:
+		return reflect.TypeOf(value).Kind() == reflect.Bool
+	}
+	return false
+}
@@ -0,0 +1,253 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clientOpenAPIGetCounter counts the GET requests made through the stub client
+type clientOpenAPIGetCounter struct {
+	*clientOpenAPIStub
+	gets int
+}
+
+func (c *clientOpenAPIGetCounter) Get(resource SpecResource, id string, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	c.gets++
+	return c.clientOpenAPIStub.Get(resource, id, responsePayload, parentIDs...)
+}
+
+func TestParseAPIDefaultExtension(t *testing.T) {
+	testCases := []struct {
+		name               string
+		value              interface{}
+		expectedAPIDefault *apiDefault
+		expectedError      string
+	}{
+		{
+			name:               "all properties",
+			value:              map[string]interface{}{"path": "/v1/defaults", "value_path": "$.network.id"},
+			expectedAPIDefault: &apiDefault{path: "/v1/defaults", valuePath: "$.network.id"},
+		},
+		{
+			name:               "value path not specified",
+			value:              map[string]interface{}{"path": "/v1/defaults"},
+			expectedAPIDefault: &apiDefault{path: "/v1/defaults"},
+		},
+		{
+			name:          "value is not an object",
+			value:         "/v1/defaults",
+			expectedError: "expected an object value but got '/v1/defaults'",
+		},
+		{
+			name:          "path missing",
+			value:         map[string]interface{}{"value_path": "network.id"},
+			expectedError: "'path' is required",
+		},
+		{
+			name:          "path with path parameters",
+			value:         map[string]interface{}{"path": "/v1/projects/{id}/defaults"},
+			expectedError: "'path' is not valid: the path must start with '/' and can not contain path parameters nor query parameters",
+		},
+		{
+			name:          "value path not valid",
+			value:         map[string]interface{}{"path": "/v1/defaults", "value_path": "$network"},
+			expectedError: "'value_path' is not valid: path '$network' is not valid",
+		},
+		{
+			name:          "property not supported",
+			value:         map[string]interface{}{"path": "/v1/defaults", "method": "POST"},
+			expectedError: "'method' is not valid: property not supported, supported properties: path, value_path",
+		},
+	}
+	for _, tc := range testCases {
+		d, err := parseAPIDefaultExtension(tc.value)
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError, tc.name)
+			assert.Nil(t, d, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expectedAPIDefault, d, tc.name)
+	}
+}
+
+func TestAPIDefaultsGetDefaultValue(t *testing.T) {
+	responsePayload := map[string]interface{}{
+		"network_id": "net-1",
+		"network":    map[string]interface{}{"id": "net-2", "mtu": json.Number("1500")},
+	}
+	testCases := []struct {
+		name          string
+		property      *specSchemaDefinitionProperty
+		client        *clientOpenAPIStub
+		expectedValue interface{}
+		expectedError string
+	}{
+		{
+			name:          "value path not specified",
+			property:      &specSchemaDefinitionProperty{Name: "network_id", Type: typeString, DefaultFromAPI: &apiDefault{path: "/v1/defaults"}},
+			client:        &clientOpenAPIStub{responsePayload: responsePayload},
+			expectedValue: "net-1",
+		},
+		{
+			name:          "value path specified",
+			property:      &specSchemaDefinitionProperty{Name: "network_id", Type: typeString, DefaultFromAPI: &apiDefault{path: "/v1/defaults", valuePath: "$.network.id"}},
+			client:        &clientOpenAPIStub{responsePayload: responsePayload},
+			expectedValue: "net-2",
+		},
+		{
+			name:          "number converted to the property type",
+			property:      &specSchemaDefinitionProperty{Name: "mtu", Type: typeInt, DefaultFromAPI: &apiDefault{path: "/v1/defaults", valuePath: "network.mtu"}},
+			client:        &clientOpenAPIStub{responsePayload: responsePayload},
+			expectedValue: 1500,
+		},
+		{
+			name:          "value not found in the payload",
+			property:      &specSchemaDefinitionProperty{Name: "subnet_id", Type: typeString, DefaultFromAPI: &apiDefault{path: "/v1/defaults"}},
+			client:        &clientOpenAPIStub{responsePayload: responsePayload},
+			expectedError: "[resource='servers_v1'] failed to fetch the default value of property 'subnet_id' from /v1/defaults: path 'subnet_id' not found in payload",
+		},
+		{
+			name:          "value not of the property type",
+			property:      &specSchemaDefinitionProperty{Name: "network_id", Type: typeBool, DefaultFromAPI: &apiDefault{path: "/v1/defaults"}},
+			client:        &clientOpenAPIStub{responsePayload: responsePayload},
+			expectedError: "[resource='servers_v1'] failed to fetch the default value of property 'network_id' from /v1/defaults: value 'net-1' is not of type boolean",
+		},
+		{
+			name:          "API call fails",
+			property:      &specSchemaDefinitionProperty{Name: "network_id", Type: typeString, DefaultFromAPI: &apiDefault{path: "/v1/defaults"}},
+			client:        &clientOpenAPIStub{error: errors.New("connection refused")},
+			expectedError: "[resource='servers_v1'] failed to fetch the default value of property 'network_id' from /v1/defaults: connection refused",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := newAPIDefaults().getDefaultValue(context.Background(), tc.client, "servers_v1", tc.property)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedValue, value)
+			assert.Equal(t, apiObjectSpecResource{path: "/v1"}, tc.client.resourceReceived)
+			assert.Equal(t, "defaults", tc.client.idReceived)
+		})
+	}
+
+	t.Run("the API responses are cached by provider client and path", func(t *testing.T) {
+		client := &clientOpenAPIGetCounter{clientOpenAPIStub: &clientOpenAPIStub{responsePayload: responsePayload}}
+		otherClient := &clientOpenAPIGetCounter{clientOpenAPIStub: &clientOpenAPIStub{responsePayload: map[string]interface{}{"network_id": "net-3"}}}
+		a := newAPIDefaults()
+		for _, property := range []*specSchemaDefinitionProperty{
+			{Name: "network_id", Type: typeString, DefaultFromAPI: &apiDefault{path: "/v1/defaults"}},
+			{Name: "mtu", Type: typeInt, DefaultFromAPI: &apiDefault{path: "/v1/defaults", valuePath: "network.mtu"}},
+		} {
+			_, err := a.getDefaultValue(context.Background(), client, "servers_v1", property)
+			require.NoError(t, err)
+		}
+		value, err := a.getDefaultValue(context.Background(), otherClient, "servers_v1", &specSchemaDefinitionProperty{Name: "network_id", Type: typeString, DefaultFromAPI: &apiDefault{path: "/v1/defaults"}})
+		require.NoError(t, err)
+		assert.Equal(t, "net-3", value)
+		assert.Equal(t, 1, client.gets)
+		assert.Equal(t, 1, otherClient.gets)
+	})
+
+	t.Run("nil apiDefaults does not cache the API responses", func(t *testing.T) {
+		var a *apiDefaults
+		client := &clientOpenAPIGetCounter{clientOpenAPIStub: &clientOpenAPIStub{responsePayload: responsePayload}}
+		property := &specSchemaDefinitionProperty{Name: "network_id", Type: typeString, DefaultFromAPI: &apiDefault{path: "/v1/defaults"}}
+		for i := 0; i < 2; i++ {
+			value, err := a.getDefaultValue(context.Background(), client, "servers_v1", property)
+			require.NoError(t, err)
+			assert.Equal(t, "net-1", value)
+		}
+		assert.Equal(t, 2, client.gets)
+	})
+}
+
+func TestCreateTerraformResourceSchema_DefaultFromAPI(t *testing.T) {
+	r := newResourceFactory(&specStubResource{
+		name: "servers_v1",
+		schemaDefinition: &specSchemaDefinition{
+			Properties: specSchemaDefinitionProperties{
+				&specSchemaDefinitionProperty{Name: "name", Type: typeString, Required: true},
+				&specSchemaDefinitionProperty{Name: "network_id", Type: typeString, DefaultFromAPI: &apiDefault{path: "/v1/defaults"}},
+			},
+		},
+	})
+	s, err := r.createTerraformResourceSchema()
+	require.NoError(t, err)
+	assert.False(t, s["name"].Computed)
+	assert.True(t, s["network_id"].Optional)
+	assert.True(t, s["network_id"].Computed)
+}
+
+func TestSetAPIDefaults(t *testing.T) {
+	r := newResourceFactory(newSpecStubResource("servers_v1", "/v1/servers", false, &specSchemaDefinition{
+		Properties: specSchemaDefinitionProperties{
+			&specSchemaDefinitionProperty{Name: "name", Type: typeString, Required: true},
+			&specSchemaDefinitionProperty{Name: "network_id", Type: typeString, DefaultFromAPI: &apiDefault{path: "/v1/defaults"}},
+		},
+	}))
+	r.apiDefaults = newAPIDefaults()
+	resource, err := r.createTerraformResource()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		config        map[string]interface{}
+		rawConfig     cty.Value
+		client        interface{}
+		expectedValue string
+		expectedError string
+	}{
+		{
+			name:          "property not configured defaults to the value returned by the API",
+			config:        map[string]interface{}{"name": "server1"},
+			rawConfig:     cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("server1"), "network_id": cty.NullVal(cty.String)}),
+			client:        &clientOpenAPIStub{responsePayload: map[string]interface{}{"network_id": "net-1"}},
+			expectedValue: "net-1",
+		},
+		{
+			name:          "property configured keeps the value configured",
+			config:        map[string]interface{}{"name": "server1", "network_id": "net-2"},
+			rawConfig:     cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("server1"), "network_id": cty.StringVal("net-2")}),
+			client:        &clientOpenAPIStub{responsePayload: map[string]interface{}{"network_id": "net-1"}},
+			expectedValue: "net-2",
+		},
+		{
+			name:          "each provider client fetches its own default value",
+			config:        map[string]interface{}{"name": "server1"},
+			rawConfig:     cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("server1"), "network_id": cty.NullVal(cty.String)}),
+			client:        &clientOpenAPIStub{responsePayload: map[string]interface{}{"network_id": "net-3"}},
+			expectedValue: "net-3",
+		},
+		{
+			name:          "provider not configured",
+			config:        map[string]interface{}{"name": "server1"},
+			rawConfig:     cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("server1"), "network_id": cty.NullVal(cty.String)}),
+			expectedError: "[resource='servers_v1'] can not fetch the default value of property 'network_id' since the provider is not configured",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			state := &terraform.InstanceState{RawConfig: tc.rawConfig}
+			diff, err := resource.Diff(context.Background(), state, terraform.NewResourceConfigRaw(tc.config), tc.client)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, diff)
+			assert.Equal(t, tc.expectedValue, diff.Attributes["network_id"].New)
+		})
+	}
+}
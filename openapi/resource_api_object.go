@@ -0,0 +1,367 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/dikhan/terraform-provider-openapi/openapi/openapierr"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// apiObjectResourceName is the name (without the provider name prefix) of the built-in resource that enables users to
+// manage endpoints that are not represented as resources by the provider (e.g. endpoints that are not terraform compliant)
+const apiObjectResourceName = "api_object"
+
+const (
+	apiObjectPropertyPath         = "path"
+	apiObjectPropertyCreateMethod = "create_method"
+	apiObjectPropertyData         = "data"
+	apiObjectPropertyIDPath       = "id_path"
+	apiObjectPropertyResponse     = "response"
+)
+
+const apiObjectDefaultIDPath = "id"
+
+// apiObjectSpecResource is the SpecResource used by the api_object resource to make the API calls against the path
+// configured by the user. The operations do not declare any security scheme nor header, hence the provider global security
+// schemes apply
+type apiObjectSpecResource struct {
+	path string
+}
+
+func (a apiObjectSpecResource) getResourceName() string {
+	return apiObjectResourceName
+}
+
+func (a apiObjectSpecResource) getHost() (string, error) {
+	return "", nil
+}
+
+func (a apiObjectSpecResource) getScheme() (string, error) {
+	return "", nil
+}
+
+func (a apiObjectSpecResource) getResourcePath(parentIDs []string) (string, error) {
+	return a.path, nil
+}
+
+func (a apiObjectSpecResource) getResourceSchema() (*specSchemaDefinition, error) {
+	return &specSchemaDefinition{}, nil
+}
+
+func (a apiObjectSpecResource) shouldIgnoreResource() bool {
+	return false
+}
+
+func (a apiObjectSpecResource) shouldIgnoreDataSourceInstance() bool {
+	return true
+}
+
+func (a apiObjectSpecResource) getResourceOperations() specResourceOperations {
+	return specResourceOperations{
+		List:   &specResourceOperation{},
+		Post:   &specResourceOperation{},
+		Get:    &specResourceOperation{},
+		Put:    &specResourceOperation{},
+		Delete: &specResourceOperation{},
+	}
+}
+
+func (a apiObjectSpecResource) getTimeouts() (*specTimeouts, error) {
+	return &specTimeouts{}, nil
+}
+
+func (a apiObjectSpecResource) getConsoleURLTemplate() string {
+	return ""
+}
+
+func (a apiObjectSpecResource) isImportOnly() bool {
+	return false
+}
+
+func (a apiObjectSpecResource) isAutoImportEnabled() bool {
+	return false
+}
+
+func (a apiObjectSpecResource) getCompositeID() []string {
+	return nil
+}
+
+func (a apiObjectSpecResource) getParentResourceInfo() *parentResourceInfo {
+	return nil
+}
+
+// apiObjectResourceFactory creates the api_object resource, a generic resource taking the path, the method and the JSON
+// payload to send to the API. It is an escape hatch for endpoints the provider is not able to represent as resources: the
+// payload is sent as is and the instance is tracked using the identifier found in the response under the id_path
+// configured.
+type apiObjectResourceFactory struct{}
+
+// createTerraformResource returns the api object resource. No importer is configured on purpose: the path, create method and
+// data payload the object is managed with can not be derived from the id of an existing object
+func (a apiObjectResourceFactory) createTerraformResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			apiObjectPropertyPath: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Path (relative to the API base path) the object is created at (e,g: /v1/widgets). If the create method is POST the object is then managed at '<path>/<id>'; if it is PUT the object is managed at the path itself",
+				ValidateFunc: a.validatePath,
+			},
+			apiObjectPropertyCreateMethod: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      string(httpPost),
+				Description:  fmt.Sprintf("HTTP method used to create the object. Allowed values: %s, %s", httpPost, httpPut),
+				ValidateFunc: a.validateCreateMethod,
+			},
+			apiObjectPropertyData: {
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "JSON payload of the object sent in the create and update (PUT) requests",
+				ValidateFunc:     a.validateJSON,
+				DiffSuppressFunc: a.suppressEquivalentJSON,
+			},
+			apiObjectPropertyIDPath: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     apiObjectDefaultIDPath,
+				Description: "JSONPath (dot notation, e,g: $.data.id) of the object identifier in the create response. If the response does not contain it, the identifier is looked up in the data payload",
+			},
+			apiObjectPropertyResponse: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "JSON response returned by the API the last time the object was read",
+			},
+		},
+		CreateContext: a.create,
+		ReadContext:   a.read,
+		UpdateContext: a.update,
+		DeleteContext: a.delete,
+	}
+}
+
+// create is the CreateContext function of the resource, see createObject
+func (a apiObjectResourceFactory) create(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	ctx, cancel := operationContext(ctx, i)
+	defer cancel()
+	return operationDiagnostics(ctx, data, "", a.createObject(ctx, data, i))
+}
+
+// createObject creates the object making the API calls with the given context
+func (a apiObjectResourceFactory) createObject(ctx context.Context, data *schema.ResourceData, i interface{}) error {
+	if err := checkWriteAllowed(i, apiObjectResourceName, "create"); err != nil {
+		return err
+	}
+	providerClient := i.(ClientOpenAPI)
+	objectPath := data.Get(apiObjectPropertyPath).(string)
+	requestPayload, err := a.getRequestPayload(data)
+	if err != nil {
+		return err
+	}
+	responsePayload := map[string]interface{}{}
+	var res *http.Response
+	if data.Get(apiObjectPropertyCreateMethod).(string) == string(httpPut) {
+		collectionPath, objectID := a.splitPath(objectPath)
+		res, err = contextClient(providerClient).PutContext(ctx, apiObjectSpecResource{path: collectionPath}, objectID, requestPayload, &responsePayload)
+	} else {
+		res, err = contextClient(providerClient).PostContext(ctx, apiObjectSpecResource{path: objectPath}, requestPayload, &responsePayload)
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkHTTPStatusCode(apiObjectSpecResource{path: objectPath}, res, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent}); err != nil {
+		return wrapError(err, "[resource='%s'] %s %s failed", apiObjectResourceName, data.Get(apiObjectPropertyCreateMethod), objectPath)
+	}
+	idPath := data.Get(apiObjectPropertyIDPath).(string)
+	id, err := a.lookupID(idPath, responsePayload)
+	if err != nil {
+		if id, err = a.lookupID(idPath, requestPayload); err != nil {
+			return fmt.Errorf("[resource='%s'] could not find the object identifier '%s' neither in the response returned by the API nor in the data payload", apiObjectResourceName, idPath)
+		}
+	}
+	data.SetId(id)
+	log.Printf("[INFO] Resource '%s' ID: %s", objectPath, data.Id())
+	return a.readObject(ctx, data, i)
+}
+
+// read is the ReadContext function of the resource, see readObject
+func (a apiObjectResourceFactory) read(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	ctx, cancel := operationContext(ctx, i)
+	defer cancel()
+	return operationDiagnostics(ctx, data, "", a.readObject(ctx, data, i))
+}
+
+// readObject reads the object making the API calls with the given context, removing it from the state if it no longer
+// exists
+func (a apiObjectResourceFactory) readObject(ctx context.Context, data *schema.ResourceData, i interface{}) error {
+	providerClient := i.(ClientOpenAPI)
+	resource, objectID := a.getObjectLocation(data)
+	responsePayload := map[string]interface{}{}
+	res, err := contextClient(providerClient).GetContext(ctx, resource, objectID, &responsePayload)
+	if err != nil {
+		return err
+	}
+	if err := checkHTTPStatusCode(resource, res, []int{http.StatusOK}); err != nil {
+		if openapiErr, ok := err.(openapierr.Error); ok && openapierr.NotFound == openapiErr.Code() {
+			log.Printf("[WARN] [resource='%s'] object %s/%s no longer exists, removing it from the state", apiObjectResourceName, resource.path, objectID)
+			data.SetId("")
+			return nil
+		}
+		return wrapError(err, "[resource='%s'] GET %s/%s failed", apiObjectResourceName, resource.path, objectID)
+	}
+	response, err := json.Marshal(responsePayload)
+	if err != nil {
+		return err
+	}
+	return data.Set(apiObjectPropertyResponse, string(response))
+}
+
+// update is the UpdateContext function of the resource, see updateObject
+func (a apiObjectResourceFactory) update(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	ctx, cancel := operationContext(ctx, i)
+	defer cancel()
+	return operationDiagnostics(ctx, data, "", a.updateObject(ctx, data, i))
+}
+
+// updateObject updates the object making the API calls with the given context
+func (a apiObjectResourceFactory) updateObject(ctx context.Context, data *schema.ResourceData, i interface{}) error {
+	if err := checkWriteAllowed(i, apiObjectResourceName, "update"); err != nil {
+		return err
+	}
+	providerClient := i.(ClientOpenAPI)
+	resource, objectID := a.getObjectLocation(data)
+	requestPayload, err := a.getRequestPayload(data)
+	if err != nil {
+		return err
+	}
+	responsePayload := map[string]interface{}{}
+	res, err := contextClient(providerClient).PutContext(ctx, resource, objectID, requestPayload, &responsePayload)
+	if err != nil {
+		return err
+	}
+	if err := checkHTTPStatusCode(resource, res, []int{http.StatusOK, http.StatusAccepted, http.StatusNoContent}); err != nil {
+		return wrapError(err, "[resource='%s'] UPDATE %s/%s failed", apiObjectResourceName, resource.path, objectID)
+	}
+	return a.readObject(ctx, data, i)
+}
+
+// delete is the DeleteContext function of the resource, see deleteObject
+func (a apiObjectResourceFactory) delete(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	ctx, cancel := operationContext(ctx, i)
+	defer cancel()
+	return operationDiagnostics(ctx, data, "", a.deleteObject(ctx, data, i))
+}
+
+// deleteObject deletes the object making the API calls with the given context
+func (a apiObjectResourceFactory) deleteObject(ctx context.Context, data *schema.ResourceData, i interface{}) error {
+	if err := checkWriteAllowed(i, apiObjectResourceName, "delete"); err != nil {
+		return err
+	}
+	providerClient := i.(ClientOpenAPI)
+	resource, objectID := a.getObjectLocation(data)
+	res, err := contextClient(providerClient).DeleteContext(ctx, resource, objectID)
+	if err != nil {
+		return err
+	}
+	if err := checkHTTPStatusCode(resource, res, []int{http.StatusNoContent, http.StatusOK, http.StatusAccepted}); err != nil {
+		if openapiErr, ok := err.(openapierr.Error); ok && openapierr.NotFound == openapiErr.Code() {
+			return nil
+		}
+		return wrapError(err, "[resource='%s'] DELETE %s/%s failed", apiObjectResourceName, resource.path, objectID)
+	}
+	return nil
+}
+
+// getObjectLocation returns the resource and the id the API calls for the object instance should be made with. Objects
+// created with POST live under the path configured ('<path>/<id>') whereas objects created with PUT live at the path itself
+func (a apiObjectResourceFactory) getObjectLocation(data *schema.ResourceData) (apiObjectSpecResource, string) {
+	objectPath := data.Get(apiObjectPropertyPath).(string)
+	if data.Get(apiObjectPropertyCreateMethod).(string) == string(httpPut) {
+		collectionPath, objectID := a.splitPath(objectPath)
+		return apiObjectSpecResource{path: collectionPath}, objectID
+	}
+	return apiObjectSpecResource{path: objectPath}, data.Id()
+}
+
+// splitPath splits the given path into the parent path and the last path segment (e.g. /v1/widgets/my_widget results
+// into /v1/widgets and my_widget)
+func (a apiObjectResourceFactory) splitPath(objectPath string) (string, string) {
+	objectPath = strings.TrimSuffix(objectPath, "/")
+	return path.Dir(objectPath), path.Base(objectPath)
+}
+
+func (a apiObjectResourceFactory) getRequestPayload(data *schema.ResourceData) (map[string]interface{}, error) {
+	requestPayload := map[string]interface{}{}
+	if err := decodeJSONPayload([]byte(data.Get(apiObjectPropertyData).(string)), &requestPayload); err != nil {
+		return nil, fmt.Errorf("[resource='%s'] failed to decode the '%s' JSON payload: %s", apiObjectResourceName, apiObjectPropertyData, err)
+	}
+	return requestPayload, nil
+}
+
+// lookupID returns the value found in the given payload following the id path (see parsePayloadPath) as a string
+func (a apiObjectResourceFactory) lookupID(idPath string, payload map[string]interface{}) (string, error) {
+	value, err := getPayloadValue(idPath, payload)
+	if err != nil {
+		return "", fmt.Errorf("id %s", err)
+	}
+	switch id := value.(type) {
+	case string:
+		if id != "" {
+			return id, nil
+		}
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64), nil
+	case json.Number:
+		return id.String(), nil
+	}
+	return "", fmt.Errorf("id path '%s' value '%v' is not a valid identifier", idPath, value)
+}
+
+func (a apiObjectResourceFactory) validatePath(v interface{}, k string) ([]string, []error) {
+	objectPath := v.(string)
+	if !strings.HasPrefix(objectPath, "/") || strings.TrimSuffix(objectPath, "/") == "" {
+		return nil, []error{fmt.Errorf("property %s value '%s' must be an absolute path (e,g: /v1/widgets)", k, objectPath)}
+	}
+	return nil, nil
+}
+
+func (a apiObjectResourceFactory) validateCreateMethod(v interface{}, k string) ([]string, []error) {
+	method := v.(string)
+	if method != string(httpPost) && method != string(httpPut) {
+		return nil, []error{fmt.Errorf("property %s value %s is not valid, please make sure the value is one of [%s %s]", k, method, httpPost, httpPut)}
+	}
+	return nil, nil
+}
+
+func (a apiObjectResourceFactory) validateJSON(v interface{}, k string) ([]string, []error) {
+	payload := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(v.(string)), &payload); err != nil {
+		return nil, []error{fmt.Errorf("property %s must be a JSON object: %s", k, err)}
+	}
+	return nil, nil
+}
+
+// suppressEquivalentJSON suppresses the diff when both values are JSON documents with the same content, so changes in the
+// formatting of the data payload (e.g. indentation or keys order) do not result into updates
+func (a apiObjectResourceFactory) suppressEquivalentJSON(k, old, new string, d *schema.ResourceData) bool {
+	var oldPayload, newPayload interface{}
+	if err := json.Unmarshal([]byte(old), &oldPayload); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(new), &newPayload); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(oldPayload, newPayload)
+}
@@ -0,0 +1,135 @@
+package openapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIObjectResourceSchema(t *testing.T) {
+	resource := apiObjectResourceFactory{}.createTerraformResource()
+	assert.NoError(t, resource.InternalValidate(nil, true))
+	assert.True(t, resource.Schema[apiObjectPropertyPath].Required)
+	assert.True(t, resource.Schema[apiObjectPropertyPath].ForceNew)
+	assert.True(t, resource.Schema[apiObjectPropertyData].Required)
+	assert.Equal(t, "POST", resource.Schema[apiObjectPropertyCreateMethod].Default)
+	assert.Equal(t, "id", resource.Schema[apiObjectPropertyIDPath].Default)
+	assert.True(t, resource.Schema[apiObjectPropertyResponse].Computed)
+}
+
+func TestAPIObjectResourceCreate(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		config               map[string]interface{}
+		responsePayload      map[string]interface{}
+		expectedID           string
+		expectedResourcePath string
+		expectedIDReceived   string
+		expectedError        string
+	}{
+		{
+			name:                 "object created with POST and the id in the response",
+			config:               map[string]interface{}{apiObjectPropertyPath: "/v1/widgets", apiObjectPropertyData: `{"name": "my_widget"}`},
+			responsePayload:      map[string]interface{}{"id": "someID", "name": "my_widget"},
+			expectedID:           "someID",
+			expectedResourcePath: "/v1/widgets",
+			expectedIDReceived:   "someID",
+		},
+		{
+			name:                 "object created with POST and a numeric id nested in the response",
+			config:               map[string]interface{}{apiObjectPropertyPath: "/v1/widgets", apiObjectPropertyData: `{"name": "my_widget"}`, apiObjectPropertyIDPath: "$.data.id"},
+			responsePayload:      map[string]interface{}{"data": map[string]interface{}{"id": float64(12345678)}},
+			expectedID:           "12345678",
+			expectedResourcePath: "/v1/widgets",
+			expectedIDReceived:   "12345678",
+		},
+		{
+			name:                 "object created with PUT and the id in the data payload",
+			config:               map[string]interface{}{apiObjectPropertyPath: "/v1/widgets/my_widget", apiObjectPropertyCreateMethod: "PUT", apiObjectPropertyData: `{"name": "my_widget"}`, apiObjectPropertyIDPath: "name"},
+			responsePayload:      map[string]interface{}{},
+			expectedID:           "my_widget",
+			expectedResourcePath: "/v1/widgets",
+			expectedIDReceived:   "my_widget",
+		},
+		{
+			name:            "object id not found",
+			config:          map[string]interface{}{apiObjectPropertyPath: "/v1/widgets", apiObjectPropertyData: `{"name": "my_widget"}`},
+			responsePayload: map[string]interface{}{"name": "my_widget"},
+			expectedError:   "[resource='api_object'] could not find the object identifier 'id' neither in the response returned by the API nor in the data payload",
+		},
+	}
+	for _, tc := range testCases {
+		r := apiObjectResourceFactory{}
+		data := schema.TestResourceDataRaw(t, r.createTerraformResource().Schema, tc.config)
+		client := &clientOpenAPIStub{responsePayload: tc.responsePayload}
+		err := r.createObject(context.Background(), data, client)
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expectedID, data.Id(), tc.name)
+		assert.Equal(t, tc.expectedIDReceived, client.idReceived, tc.name)
+		resourcePath, _ := client.resourceReceived.getResourcePath(nil)
+		assert.Equal(t, tc.expectedResourcePath, resourcePath, tc.name)
+		assert.NotEmpty(t, data.Get(apiObjectPropertyResponse), tc.name)
+	}
+}
+
+func TestAPIObjectResourceRead(t *testing.T) {
+	r := apiObjectResourceFactory{}
+	t.Run("happy path -- the response is stored in the state", func(t *testing.T) {
+		data := schema.TestResourceDataRaw(t, r.createTerraformResource().Schema, map[string]interface{}{apiObjectPropertyPath: "/v1/widgets", apiObjectPropertyData: `{"name": "my_widget"}`})
+		data.SetId("someID")
+		client := &clientOpenAPIStub{responsePayload: map[string]interface{}{"id": "someID", "name": "my_widget"}}
+		err := r.readObject(context.Background(), data, client)
+		require.NoError(t, err)
+		assert.Equal(t, "someID", client.idReceived)
+		assert.JSONEq(t, `{"id": "someID", "name": "my_widget"}`, data.Get(apiObjectPropertyResponse).(string))
+	})
+	t.Run("crappy path -- the object no longer exists and is removed from the state", func(t *testing.T) {
+		data := schema.TestResourceDataRaw(t, r.createTerraformResource().Schema, map[string]interface{}{apiObjectPropertyPath: "/v1/widgets", apiObjectPropertyData: `{"name": "my_widget"}`})
+		data.SetId("someID")
+		client := &clientOpenAPIStub{returnHTTPCode: http.StatusNotFound}
+		err := r.readObject(context.Background(), data, client)
+		require.NoError(t, err)
+		assert.Empty(t, data.Id())
+	})
+}
+
+func TestAPIObjectResourceUpdateAndDelete(t *testing.T) {
+	r := apiObjectResourceFactory{}
+	data := schema.TestResourceDataRaw(t, r.createTerraformResource().Schema, map[string]interface{}{apiObjectPropertyPath: "/v1/widgets", apiObjectPropertyData: `{"name": "my_widget"}`})
+	data.SetId("someID")
+	client := &clientOpenAPIStub{responsePayload: map[string]interface{}{"id": "someID", "name": "my_widget"}}
+	assert.NoError(t, r.updateObject(context.Background(), data, client))
+	assert.Equal(t, "someID", client.idReceived)
+	assert.NoError(t, r.deleteObject(context.Background(), data, client))
+	assert.Equal(t, "someID", client.idReceived)
+	client = &clientOpenAPIStub{returnHTTPCode: http.StatusInternalServerError}
+	assert.EqualError(t, r.deleteObject(context.Background(), data, client), "[resource='api_object'] DELETE /v1/widgets/someID failed: [resource='api_object'] HTTP Response Status Code 500 not matching expected one [204 200 202] ()")
+}
+
+func TestAPIObjectResourceValidations(t *testing.T) {
+	r := apiObjectResourceFactory{}
+	_, errs := r.validatePath("/v1/widgets", apiObjectPropertyPath)
+	assert.Empty(t, errs)
+	_, errs = r.validatePath("v1/widgets", apiObjectPropertyPath)
+	assert.Len(t, errs, 1)
+	_, errs = r.validatePath("/", apiObjectPropertyPath)
+	assert.Len(t, errs, 1)
+	_, errs = r.validateCreateMethod("PUT", apiObjectPropertyCreateMethod)
+	assert.Empty(t, errs)
+	_, errs = r.validateCreateMethod("PATCH", apiObjectPropertyCreateMethod)
+	assert.Len(t, errs, 1)
+	_, errs = r.validateJSON(`{"name": "my_widget"}`, apiObjectPropertyData)
+	assert.Empty(t, errs)
+	_, errs = r.validateJSON(`["my_widget"]`, apiObjectPropertyData)
+	assert.Len(t, errs, 1)
+	assert.True(t, r.suppressEquivalentJSON(apiObjectPropertyData, `{"a": 1, "b": "c"}`, "{\n  \"b\": \"c\",\n  \"a\": 1\n}", nil))
+	assert.False(t, r.suppressEquivalentJSON(apiObjectPropertyData, `{"a": 1}`, `{"a": 2}`, nil))
+}
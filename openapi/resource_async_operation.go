@@ -0,0 +1,79 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// asyncOperationClient defines the behaviour expected from the clients able to poll the asynchronous operations returned
+// by the API (e.g. 202 Accepted responses with a Location header), which is not part of the ClientOpenAPI interface
+type asyncOperationClient interface {
+	GetAsyncOperation(ctx context.Context, resource SpecResource, operationURL string, responsePayload interface{}, parentIDs ...string) (*http.Response, error)
+}
+
+// getAsyncOperationURL returns the URL of the asynchronous operation to poll if the response that enabled the polling
+// defines the header containing it; empty if the resource instance should be polled instead, which is also the case if
+// the API did not return the header
+func (r resourceFactory) getAsyncOperationURL(response *specResponse, responseHeader http.Header, providerClient ClientOpenAPI) string {
+	if response.pollLocationHeader == "" {
+		return ""
+	}
+	if _, ok := providerClient.(asyncOperationClient); !ok {
+		log.Printf("[WARN] the asynchronous operations of resource '%s' can not be polled by the client, polling the resource instead", r.openAPIResource.getResourceName())
+		return ""
+	}
+	operationURL := responseHeader.Get(response.pollLocationHeader)
+	if operationURL == "" {
+		log.Printf("[WARN] the API did not return the '%s' header with the asynchronous operation of resource '%s', polling the resource instead", response.pollLocationHeader, r.openAPIResource.getResourceName())
+	}
+	return operationURL
+}
+
+// asyncOperationStateRefreshFunc returns the function used by the polling mechanism to read the status of the asynchronous
+// operation at the given URL. The status is read from the operation payload following the status path of the response
+// that enabled the polling (or the status property of the resource schema if not defined). Statuses configured as failed
+// make the polling fail straight away.
+func (r resourceFactory) asyncOperationStateRefreshFunc(ctx context.Context, operationURL string, resourceLocalData *schema.ResourceData, providerClient ClientOpenAPI, response *specResponse) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		parentIDs, err := getParentIDs(r.openAPIResource, resourceLocalData)
+		if err != nil {
+			return nil, "", err
+		}
+		operationPayload := map[string]interface{}{}
+		resp, err := providerClient.(asyncOperationClient).GetAsyncOperation(ctx, r.openAPIResource, operationURL, &operationPayload, parentIDs...)
+		if err != nil {
+			return nil, "", fmt.Errorf("error on retrieving the asynchronous operation '%s' of resource '%s' (%s) when waiting: %s", operationURL, r.openAPIResource.getResourceName(), resourceLocalData.Id(), err)
+		}
+		if err := checkHTTPStatusCode(r.openAPIResource, resp, []int{http.StatusOK}); err != nil {
+			return nil, "", fmt.Errorf("error on retrieving the asynchronous operation '%s' of resource '%s' (%s) when waiting: %s", operationURL, r.openAPIResource.getResourceName(), resourceLocalData.Id(), err)
+		}
+		status, err := r.getPollStatusValueFromPayload(operationPayload, response)
+		if err != nil {
+			return nil, "", fmt.Errorf("error occurred while retrieving status identifier value from the asynchronous operation '%s' payload of resource '%s' (%s): %s", operationURL, r.openAPIResource.getResourceName(), resourceLocalData.Id(), err)
+		}
+		if response.isPollFailedStatus(status) {
+			return nil, "", fmt.Errorf("the asynchronous operation '%s' of resource '%s' (%s) reached the failed status '%s'", operationURL, r.openAPIResource.getResourceName(), resourceLocalData.Id(), status)
+		}
+		log.Printf("[INFO] the asynchronous operation '%s' of resource '%s' (%s) is still being processed - status: %s", operationURL, r.openAPIResource.getResourceName(), resourceLocalData.Id(), status)
+		return operationPayload, status, nil
+	}
+}
+
+// readRemoteAfterPolling reads the resource instance once the asynchronous operation completed, retrying as per the given
+// eventual consistency policy (if any) while the resource is not found
+func (r resourceFactory) readRemoteAfterPolling(ctx context.Context, resourceLocalData *schema.ResourceData, providerClient ClientOpenAPI, eventualConsistency *eventualConsistencyPolicy) (map[string]interface{}, error) {
+	parentIDs, err := getParentIDs(r.openAPIResource, resourceLocalData)
+	if err != nil {
+		return nil, err
+	}
+	instanceID, err := getResourceInstanceID(r.openAPIResource, resourceLocalData)
+	if err != nil {
+		return nil, err
+	}
+	return r.readRemoteEventuallyConsistent(ctx, eventualConsistency, instanceID, providerClient, parentIDs...)
+}
@@ -0,0 +1,55 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// clientOpenAPIWithoutAsyncOperations hides the optional GetAsyncOperation method of the stub client
+type clientOpenAPIWithoutAsyncOperations struct {
+	ClientOpenAPI
+}
+
+func TestGetAsyncOperationURL(t *testing.T) {
+	header := http.Header{}
+	header.Set("Operation-Location", "/v1/operations/op1")
+	testCases := []struct {
+		name                 string
+		response             *specResponse
+		client               ClientOpenAPI
+		expectedOperationURL string
+	}{
+		{
+			name:                 "the response defines the header and the API returned it",
+			response:             &specResponse{pollLocationHeader: "Operation-Location"},
+			client:               &clientOpenAPIStub{},
+			expectedOperationURL: "/v1/operations/op1",
+		},
+		{
+			name:                 "the response does not define the header",
+			response:             &specResponse{},
+			client:               &clientOpenAPIStub{},
+			expectedOperationURL: "",
+		},
+		{
+			name:                 "the API did not return the header",
+			response:             &specResponse{pollLocationHeader: "Location"},
+			client:               &clientOpenAPIStub{},
+			expectedOperationURL: "",
+		},
+		{
+			name:                 "the client does not support asynchronous operations",
+			response:             &specResponse{pollLocationHeader: "Operation-Location"},
+			client:               clientOpenAPIWithoutAsyncOperations{&clientOpenAPIStub{}},
+			expectedOperationURL: "",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newResourceFactory(&specStubResource{name: "cdns_v1"})
+			assert.Equal(t, tc.expectedOperationURL, r.getAsyncOperationURL(tc.response, header, tc.client))
+		})
+	}
+}
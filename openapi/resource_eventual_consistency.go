@@ -0,0 +1,119 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dikhan/terraform-provider-openapi/openapi/openapierr"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const (
+	eventualConsistencyGracePeriod   = "grace_period"
+	eventualConsistencyRetryInterval = "retry_interval"
+)
+
+// defaultEventualConsistencyRetryInterval is the time to wait before reading again a resource that was not found yet
+// if the x-terraform-eventual-consistency extension does not define it
+var defaultEventualConsistencyRetryInterval = time.Duration(2 * time.Second)
+
+// eventualConsistencyPolicy defines how long the reads made right after creating a resource are retried while the API
+// returns 404 Not Found, for backends that do not find the resources they just created for a short window
+type eventualConsistencyPolicy struct {
+	gracePeriod   time.Duration
+	retryInterval time.Duration
+}
+
+// parseEventualConsistencyExtension parses the value of the x-terraform-eventual-consistency extension, which is an
+// object containing the grace period during which the reads are retried and optionally the time to wait between them
+// (e.g. {grace_period: 30s, retry_interval: 5s})
+func parseEventualConsistencyExtension(value interface{}) (*eventualConsistencyPolicy, error) {
+	properties, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected an object value but got '%v'", value)
+	}
+	policy := &eventualConsistencyPolicy{retryInterval: defaultEventualConsistencyRetryInterval}
+	var names []string
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var err error
+		switch property := properties[name]; name {
+		case eventualConsistencyGracePeriod:
+			policy.gracePeriod, err = parseExtensionDuration(property)
+		case eventualConsistencyRetryInterval:
+			policy.retryInterval, err = parseExtensionDuration(property)
+		default:
+			err = fmt.Errorf("property not supported, supported properties: %s", strings.Join([]string{eventualConsistencyGracePeriod, eventualConsistencyRetryInterval}, ", "))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not valid: %s", name, err)
+		}
+	}
+	if policy.gracePeriod == 0 {
+		return nil, fmt.Errorf("'%s' is required", eventualConsistencyGracePeriod)
+	}
+	return policy, nil
+}
+
+// validateEventualConsistencyExtension is the Validate hook of the x-terraform-eventual-consistency extension
+func validateEventualConsistencyExtension(value interface{}) error {
+	_, err := parseEventualConsistencyExtension(value)
+	return err
+}
+
+// retryWhileNotFound calls the given function, which returns true if the resource was not found, until the resource is
+// found or the grace period counted from start expires. The wait between the calls is interrupted if the context is done
+func (p *eventualConsistencyPolicy) retryWhileNotFound(ctx context.Context, start time.Time, resourceName, instanceID string, call func() bool) error {
+	for call() && time.Since(start) < p.gracePeriod {
+		log.Printf("[DEBUG] resource '%s' (%s) not found yet, reading it again in %s (eventual consistency grace period: %s)", resourceName, instanceID, p.retryInterval, p.gracePeriod)
+		select {
+		case <-time.After(p.retryInterval):
+		case <-ctx.Done():
+			return contextError(ctx)
+		}
+	}
+	return nil
+}
+
+// refreshFunc returns the given polling refresh function retrying the reads that report the resource as destroyed (the
+// API returned 404 Not Found) during the grace period, which starts when the refresh function is created
+func (p *eventualConsistencyPolicy) refreshFunc(ctx context.Context, resourceName, instanceID string, refresh resource.StateRefreshFunc) resource.StateRefreshFunc {
+	start := time.Now()
+	return func() (interface{}, string, error) {
+		var result interface{}
+		var status string
+		var err error
+		if retryErr := p.retryWhileNotFound(ctx, start, resourceName, instanceID, func() bool {
+			result, status, err = refresh()
+			return err == nil && status == defaultDestroyStatus
+		}); retryErr != nil {
+			return nil, "", retryErr
+		}
+		return result, status, err
+	}
+}
+
+// readRemoteEventuallyConsistent reads the resource instance as readRemote does, retrying the reads that return 404 Not
+// Found during the grace period of the given policy. The resource is read only once if the policy is nil
+func (r resourceFactory) readRemoteEventuallyConsistent(ctx context.Context, policy *eventualConsistencyPolicy, instanceID string, providerClient ClientOpenAPI, parentIDs ...string) (map[string]interface{}, error) {
+	if policy == nil {
+		return r.readRemote(ctx, instanceID, providerClient, parentIDs...)
+	}
+	var remoteData map[string]interface{}
+	var err error
+	if retryErr := policy.retryWhileNotFound(ctx, time.Now(), r.openAPIResource.getResourceName(), instanceID, func() bool {
+		remoteData, err = r.readRemote(ctx, instanceID, providerClient, parentIDs...)
+		openapiErr, ok := err.(openapierr.Error)
+		return ok && openapierr.NotFound == openapiErr.Code()
+	}); retryErr != nil {
+		return nil, retryErr
+	}
+	return remoteData, err
+}
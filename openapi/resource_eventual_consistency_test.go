@@ -0,0 +1,163 @@
+package openapi
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clientOpenAPINotFoundFirst returns 404 Not Found for the first instance GET requests before delegating to the stub client
+type clientOpenAPINotFoundFirst struct {
+	*clientOpenAPIStub
+	notFoundReads int
+	reads         int
+}
+
+func (c *clientOpenAPINotFoundFirst) Get(resource SpecResource, id string, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	c.reads++
+	if c.reads <= c.notFoundReads {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+	}
+	return c.clientOpenAPIStub.Get(resource, id, responsePayload, parentIDs...)
+}
+
+func TestParseEventualConsistencyExtension(t *testing.T) {
+	testCases := []struct {
+		name           string
+		value          interface{}
+		expectedPolicy *eventualConsistencyPolicy
+		expectedError  string
+	}{
+		{
+			name:           "all properties",
+			value:          map[string]interface{}{"grace_period": "30s", "retry_interval": float64(5)},
+			expectedPolicy: &eventualConsistencyPolicy{gracePeriod: 30 * time.Second, retryInterval: 5 * time.Second},
+		},
+		{
+			name:           "retry interval default",
+			value:          map[string]interface{}{"grace_period": "1m"},
+			expectedPolicy: &eventualConsistencyPolicy{gracePeriod: time.Minute, retryInterval: defaultEventualConsistencyRetryInterval},
+		},
+		{
+			name:          "value is not an object",
+			value:         "30s",
+			expectedError: "expected an object value but got '30s'",
+		},
+		{
+			name:          "grace period missing",
+			value:         map[string]interface{}{"retry_interval": "5s"},
+			expectedError: "'grace_period' is required",
+		},
+		{
+			name:          "retry interval not valid",
+			value:         map[string]interface{}{"grace_period": "30s", "retry_interval": "-1s"},
+			expectedError: "'retry_interval' is not valid: invalid duration value: '-1s'. The value must be either a duration (e,g: 30s, 1.5m, 1h30m) or an integer number of seconds (e,g: 30); negative and zero durations are not allowed: the duration must be greater than zero",
+		},
+		{
+			name:          "property not supported",
+			value:         map[string]interface{}{"grace_period": "30s", "max_attempts": float64(3)},
+			expectedError: "'max_attempts' is not valid: property not supported, supported properties: grace_period, retry_interval",
+		},
+	}
+	for _, tc := range testCases {
+		policy, err := parseEventualConsistencyExtension(tc.value)
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError, tc.name)
+			assert.Nil(t, policy, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expectedPolicy, policy, tc.name)
+	}
+}
+
+func TestReadRemoteEventuallyConsistent(t *testing.T) {
+	testCases := []struct {
+		name          string
+		policy        *eventualConsistencyPolicy
+		notFoundReads int
+		expectedReads int
+		expectedError string
+	}{
+		{
+			name:          "resource found after a few reads within the grace period",
+			policy:        &eventualConsistencyPolicy{gracePeriod: time.Minute, retryInterval: time.Millisecond},
+			notFoundReads: 2,
+			expectedReads: 3,
+		},
+		{
+			name:          "resource not found once the grace period expires",
+			policy:        &eventualConsistencyPolicy{gracePeriod: 20 * time.Millisecond, retryInterval: 5 * time.Millisecond},
+			notFoundReads: 1000,
+			expectedError: "HTTP Response Status Code 404 - Not Found",
+		},
+		{
+			name:          "reads are not retried without policy",
+			notFoundReads: 1,
+			expectedReads: 1,
+			expectedError: "HTTP Response Status Code 404 - Not Found",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newResourceFactory(&specStubResource{name: "cdns_v1"})
+			client := &clientOpenAPINotFoundFirst{clientOpenAPIStub: &clientOpenAPIStub{responsePayload: map[string]interface{}{"id": "id"}}, notFoundReads: tc.notFoundReads}
+			remoteData, err := r.readRemoteEventuallyConsistent(context.Background(), tc.policy, "id", client)
+			if tc.expectedReads > 0 {
+				assert.Equal(t, tc.expectedReads, client.reads)
+			}
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, map[string]interface{}{"id": "id"}, remoteData)
+		})
+	}
+}
+
+func TestEventualConsistencyPolicyRefreshFunc(t *testing.T) {
+	t.Run("the not found reads are retried within the grace period", func(t *testing.T) {
+		refreshes := 0
+		refresh := func() (interface{}, string, error) {
+			refreshes++
+			if refreshes < 3 {
+				return 0, defaultDestroyStatus, nil
+			}
+			return map[string]interface{}{}, "pending", nil
+		}
+		policy := &eventualConsistencyPolicy{gracePeriod: time.Minute, retryInterval: time.Millisecond}
+		_, status, err := policy.refreshFunc(context.Background(), "cdns_v1", "id", refresh)()
+		assert.NoError(t, err)
+		assert.Equal(t, "pending", status)
+		assert.Equal(t, 3, refreshes)
+	})
+
+	t.Run("the not found status is returned once the grace period expires", func(t *testing.T) {
+		refresh := func() (interface{}, string, error) {
+			return 0, defaultDestroyStatus, nil
+		}
+		policy := &eventualConsistencyPolicy{gracePeriod: 20 * time.Millisecond, retryInterval: 5 * time.Millisecond}
+		_, status, err := policy.refreshFunc(context.Background(), "cdns_v1", "id", refresh)()
+		assert.NoError(t, err)
+		assert.Equal(t, defaultDestroyStatus, status)
+	})
+
+	t.Run("the wait is interrupted if the context is done", func(t *testing.T) {
+		refresh := func() (interface{}, string, error) {
+			return 0, defaultDestroyStatus, nil
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		policy := &eventualConsistencyPolicy{gracePeriod: time.Hour, retryInterval: time.Hour}
+		_, _, err := policy.refreshFunc(ctx, "cdns_v1", "id", refresh)()
+		assert.Equal(t, errOperationCancelled, err)
+	})
+}
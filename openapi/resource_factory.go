@@ -1,18 +1,24 @@
 package openapi
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/dikhan/terraform-provider-openapi/openapi/openapierr"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 type resourceFactory struct {
@@ -21,11 +27,29 @@ type resourceFactory struct {
 	defaultPollInterval   time.Duration
 	defaultPollMinTimeout time.Duration
 	defaultPollDelay      time.Duration
+	retryBudget           *retryBudget
+	// apiDefaults fetches the default values of the properties configured with the x-terraform-default-from-api extension
+	apiDefaults *apiDefaults
+	// preventDestroy is true when the resource is protected by the service configuration prevent destroy policy
+	preventDestroy bool
 }
 
 // only applicable when remote resource no longer exists and GET operations return 404 NotFound
 const defaultDestroyStatus = "destroyed"
 
+// deletePendingStatus is the status used when confirming the deletion via the collection GET operation while the resource
+// instance is still listed, or via the instance GET operation while the resource instance is still found
+const deletePendingStatus = "deleting"
+
+// consoleURLPropertyName is the name of the computed attribute containing the URL of the resource instance in the vendor's
+// console, only added to resources configured with the x-terraform-console-url-template extension
+const consoleURLPropertyName = "console_url"
+
+// consoleURLRegionPlaceholder is the placeholder in the console URL template that is replaced with the region
+const consoleURLRegionPlaceholder = "{region}"
+
+var consoleURLPlaceholderRegex = regexp.MustCompile(`{(\w+)}`)
+
 var defaultPollInterval = time.Duration(5 * time.Second)
 var defaultPollMinTimeout = time.Duration(10 * time.Second)
 var defaultPollDelay = time.Duration(1 * time.Second)
@@ -52,17 +76,103 @@ func (r resourceFactory) createTerraformResource() (*schema.Resource, error) {
 	if err != nil {
 		return nil, err
 	}
+	scheme, err := r.openAPIResource.getScheme()
+	if err != nil {
+		return nil, err
+	}
+	if scheme == httpScheme {
+		log.Printf("[WARN] resource '%s' is configured to be managed over plain http ('%s' extension)", r.openAPIResource.getResourceName(), extTfResourceScheme)
+	}
 	return &schema.Resource{
-		Schema:   s,
-		Create:   r.create,
-		Read:     r.read,
-		Delete:   r.delete,
-		Update:   r.update,
-		Importer: r.importer(),
-		Timeouts: timeouts,
+		Schema:        s,
+		CreateContext: r.create,
+		ReadContext:   r.read,
+		DeleteContext: r.delete,
+		UpdateContext: r.update,
+		Importer:      r.importer(),
+		Timeouts:      timeouts,
+		CustomizeDiff: r.customizeDiff,
 	}, nil
 }
 
+// customizeDiff sets the default values fetched from the API, validates the plan and reports why the resource is
+// replaced, if any of the properties forcing the replacement documents the reason
+func (r resourceFactory) customizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if err := r.setAPIDefaults(ctx, diff, meta); err != nil {
+		return err
+	}
+	if err := r.checkRequiredIfProperties(diff, meta); err != nil {
+		return err
+	}
+	return r.logForceNewReasons(diff)
+}
+
+// logForceNewReasons logs the reasons documented for the top level properties whose change forces the replacement of the
+// existing resource, so the user understands in API terms why the resource is recreated
+func (r resourceFactory) logForceNewReasons(diff *schema.ResourceDiff) error {
+	if diff.Id() == "" {
+		return nil
+	}
+	resourceSchema, err := r.openAPIResource.getResourceSchema()
+	if err != nil {
+		return err
+	}
+	for _, property := range resourceSchema.Properties {
+		if !property.ForceNew || property.ForceNewReason == "" {
+			continue
+		}
+		if diff.HasChange(property.getTerraformCompliantPropertyName()) {
+			log.Printf("[WARN] resource '%s' (%s) will be replaced since the property '%s' changed: %s", r.openAPIResource.getResourceName(), diff.Id(), property.getTerraformCompliantPropertyName(), property.ForceNewReason)
+		}
+	}
+	return nil
+}
+
+// checkRequiredIfProperties fails the plan if any of the properties configured with the x-terraform-required-if extension
+// is not set while its condition is met, so the user gets a clear error instead of the API rejecting the request. The
+// conditions depending on values not known until apply are not checked
+func (r resourceFactory) checkRequiredIfProperties(diff *schema.ResourceDiff, meta interface{}) error {
+	resourceSchema, err := r.openAPIResource.getResourceSchema()
+	if err != nil {
+		return err
+	}
+	var missingProperties []string
+	for _, property := range resourceSchema.Properties {
+		if !property.isConditionallyRequired() {
+			continue
+		}
+		conditionProperty, err := resourceSchema.getProperty(property.RequiredIfProperty)
+		if err != nil {
+			return err
+		}
+		conditionPropertyName := conditionProperty.getTerraformCompliantPropertyName()
+		if !diff.NewValueKnown(conditionPropertyName) {
+			continue
+		}
+		conditionValue := fmt.Sprintf("%v", diff.Get(conditionPropertyName))
+		conditionMet := false
+		for _, value := range property.RequiredIfValues {
+			if value == conditionValue {
+				conditionMet = true
+				break
+			}
+		}
+		if !conditionMet {
+			continue
+		}
+		propertyName := property.getTerraformCompliantPropertyName()
+		if _, exists := diff.GetOkExists(propertyName); exists || !diff.NewValueKnown(propertyName) {
+			continue
+		}
+		missingProperties = append(missingProperties, fmt.Sprintf("'%s' is required when '%s' is '%s'", propertyName, conditionPropertyName, conditionValue))
+	}
+	if len(missingProperties) > 0 {
+		sort.Strings(missingProperties)
+		return fmt.Errorf("resource '%s' configuration is not valid: %s", r.openAPIResource.getResourceName(), strings.Join(missingProperties, "; "))
+	}
+	return nil
+}
+
 func (r resourceFactory) createSchemaResourceTimeout() (*schema.ResourceTimeout, error) {
 	var timeouts *specTimeouts
 	var err error
@@ -84,11 +194,266 @@ func (r resourceFactory) createTerraformResourceSchema() (map[string]*schema.Sch
 		return nil, err
 	}
 	log.Printf("[DEBUG] resource '%s' schemaDefinition: %s", r.openAPIResource.getResourceName(), sPrettyPrint(schemaDefinition))
-	return schemaDefinition.createResourceSchema()
+	s, err := schemaDefinition.createResourceSchema()
+	if err != nil {
+		return nil, err
+	}
+	r.appendRequiredHeadersSchema(s)
+	if err := r.appendConsoleURLSchema(s); err != nil {
+		return nil, err
+	}
+	if err := r.setCompositeIDSchemaForceNew(schemaDefinition, s); err != nil {
+		return nil, err
+	}
+	r.setAPIDefaultsComputed(schemaDefinition, s)
+	return s, nil
+}
+
+// setAPIDefaultsComputed marks as computed the optional properties whose default value is fetched from the API
+// (x-terraform-default-from-api extension), so the value can be set when planning if the user does not provide one (see
+// setAPIDefaults)
+func (r resourceFactory) setAPIDefaultsComputed(schemaDefinition *specSchemaDefinition, s map[string]*schema.Schema) {
+	for _, property := range schemaDefinition.Properties {
+		if !property.isDefaultFromAPI() {
+			continue
+		}
+		propertySchema, exists := s[property.getTerraformCompliantPropertyName()]
+		if !exists || !propertySchema.Optional || propertySchema.Computed {
+			log.Printf("[WARN] resource '%s' property '%s' can not default to a value returned by the API since it is not optional or it is computed, ignoring the '%s' extension", r.openAPIResource.getResourceName(), property.Name, extTfDefaultFromAPI)
+			continue
+		}
+		propertySchema.Computed = true
+	}
+}
+
+// setAPIDefaults sets in the plan the values fetched from the API, with the client of the provider the resource is
+// managed with, of the properties configured with the x-terraform-default-from-api extension that the user does not
+// provide a value for
+func (r resourceFactory) setAPIDefaults(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	config := diff.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+	resourceSchema, err := r.openAPIResource.getResourceSchema()
+	if err != nil {
+		return err
+	}
+	// terraform does not cancel the context of the plan when it interrupts the provider
+	ctx, cancel := withStopContext(ctx, clientStopContext(meta))
+	defer cancel()
+	for _, property := range resourceSchema.Properties {
+		if !property.isDefaultFromAPI() {
+			continue
+		}
+		propertyName := property.getTerraformCompliantPropertyName()
+		if config.Type().HasAttribute(propertyName) && !config.GetAttr(propertyName).IsNull() {
+			continue
+		}
+		providerClient, ok := meta.(ClientOpenAPI)
+		if !ok {
+			return fmt.Errorf("[resource='%s'] can not fetch the default value of property '%s' since the provider is not configured", r.openAPIResource.getResourceName(), property.Name)
+		}
+		value, err := r.apiDefaults.getDefaultValue(ctx, providerClient, r.openAPIResource.getResourceName(), property)
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			continue
+		}
+		if err := diff.SetNew(propertyName, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setCompositeIDSchemaForceNew marks the configurable properties that compose the id of the resource (x-terraform-composite-id
+// extension) as force new, since updating any of them means referring to a different resource instance
+func (r resourceFactory) setCompositeIDSchemaForceNew(schemaDefinition *specSchemaDefinition, s map[string]*schema.Schema) error {
+	for _, propertyName := range r.openAPIResource.getCompositeID() {
+		property, err := schemaDefinition.getProperty(propertyName)
+		if err != nil {
+			return err
+		}
+		if propertySchema, exists := s[property.getTerraformCompliantPropertyName()]; exists && (propertySchema.Required || propertySchema.Optional) {
+			propertySchema.ForceNew = true
+		}
+	}
+	return nil
+}
+
+// appendConsoleURLSchema adds the computed console_url attribute to the resource schema if the resource is configured
+// with a console URL template. The placeholders in the template must refer to either the id, the region or any of the
+// resource attributes.
+func (r resourceFactory) appendConsoleURLSchema(s map[string]*schema.Schema) error {
+	consoleURLTemplate := r.openAPIResource.getConsoleURLTemplate()
+	if consoleURLTemplate == "" {
+		return nil
+	}
+	if _, exists := s[consoleURLPropertyName]; exists {
+		log.Printf("[WARN] resource '%s' is configured with a console URL template but the attribute '%s' can not be added since its name collides with an existing resource property", r.openAPIResource.getResourceName(), consoleURLPropertyName)
+		return nil
+	}
+	for _, match := range consoleURLPlaceholderRegex.FindAllStringSubmatch(consoleURLTemplate, -1) {
+		placeholder := match[1]
+		if _, exists := s[placeholder]; !exists && placeholder != "id" && match[0] != consoleURLRegionPlaceholder {
+			return fmt.Errorf("console URL template '%s' refers to '%s' which is not an attribute of the resource '%s'", consoleURLTemplate, placeholder, r.openAPIResource.getResourceName())
+		}
+	}
+	s[consoleURLPropertyName] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "URL of the resource in the console",
+	}
+	return nil
+}
+
+// setConsoleURL populates the console_url attribute interpolating the resource's console URL template with the id, the
+// region and the attributes of the resource instance. If any of the values is not known yet, the attribute is left empty.
+func (r resourceFactory) setConsoleURL(data *schema.ResourceData, providerClient ClientOpenAPI) error {
+	consoleURLTemplate := r.openAPIResource.getConsoleURLTemplate()
+	if consoleURLTemplate == "" {
+		return nil
+	}
+	if resourceSchema, err := r.openAPIResource.getResourceSchema(); err == nil {
+		if _, err := resourceSchema.getPropertyBasedOnTerraformName(consoleURLPropertyName); err == nil {
+			return nil
+		}
+	}
+	var region string
+	if client, ok := providerClient.(*ProviderClient); ok {
+		var err error
+		if region, err = client.getRegion(); err != nil {
+			return err
+		}
+	}
+	consoleURL := consoleURLTemplate
+	for _, match := range consoleURLPlaceholderRegex.FindAllStringSubmatch(consoleURLTemplate, -1) {
+		var value string
+		switch {
+		case match[0] == consoleURLRegionPlaceholder:
+			value = region
+		case match[1] == "id":
+			value = data.Id()
+		default:
+			if v, exists := data.GetOk(match[1]); exists {
+				value = fmt.Sprintf("%v", v)
+			}
+		}
+		if value == "" {
+			log.Printf("[WARN] [resource='%s'] console URL can not be built since the value for '%s' is not known", r.openAPIResource.getResourceName(), match[1])
+			return data.Set(consoleURLPropertyName, "")
+		}
+		consoleURL = strings.Replace(consoleURL, match[0], url.PathEscape(value), -1)
+	}
+	return data.Set(consoleURLPropertyName, consoleURL)
+}
+
+// updateState updates the state with the given payload and populates the attributes that are not part of the payload
+// (e.g. the console URL)
+func (r resourceFactory) updateState(payload map[string]interface{}, data *schema.ResourceData, providerClient ClientOpenAPI) error {
+	if err := updateStateWithPayloadData(r.openAPIResource, payload, data); err != nil {
+		return err
+	}
+	return r.setConsoleURL(data, providerClient)
+}
+
+// appendRequiredHeadersSchema adds an optional attribute to the resource schema for each required header of the resource
+// operations. This allows users to override per resource instance the header value configured in the provider
+func (r resourceFactory) appendRequiredHeadersSchema(s map[string]*schema.Schema) {
+	for _, headerParam := range r.getRequiredHeaderParameters() {
+		headerTerraformCompliantName := headerParam.GetHeaderTerraformConfigurationName()
+		if _, exists := s[headerTerraformCompliantName]; exists {
+			continue
+		}
+		s[headerTerraformCompliantName] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: fmt.Sprintf("Value for the '%s' header sent in the API calls for this resource. If not set, the value configured in the provider's '%s' property is used", headerParam.Name, headerTerraformCompliantName),
+		}
+	}
+}
+
+// getRequiredHeaderParameters returns the required header parameters of all the resource operations (with no duplicates).
+// Headers whose terraform name collides with a property of the resource schema are left out.
+func (r resourceFactory) getRequiredHeaderParameters() SpecHeaderParameters {
+	requiredHeaderParameters := SpecHeaderParameters{}
+	resourceSchema, err := r.openAPIResource.getResourceSchema()
+	if err != nil {
+		return requiredHeaderParameters
+	}
+	operations := r.openAPIResource.getResourceOperations()
+	for _, operation := range []*specResourceOperation{operations.Post, operations.Get, operations.Put, operations.Delete} {
+		if operation == nil {
+			continue
+		}
+		for _, headerParam := range operation.RequiredHeaderParameters {
+			if requiredHeaderParameters.specHeaderExists(headerParam) {
+				continue
+			}
+			if _, err := resourceSchema.getPropertyBasedOnTerraformName(headerParam.GetHeaderTerraformConfigurationName()); err == nil {
+				log.Printf("[WARN] resource '%s' required header '%s' can not be configured per resource instance since its name collides with an existing resource property", r.openAPIResource.getResourceName(), headerParam.GetHeaderTerraformConfigurationName())
+				continue
+			}
+			requiredHeaderParameters = append(requiredHeaderParameters, headerParam)
+		}
+	}
+	return requiredHeaderParameters
 }
 
-func (r resourceFactory) create(data *schema.ResourceData, i interface{}) error {
+// getInstanceClient returns the client that should be used to perform the API calls for the given resource instance. If the
+// user configured values for any of the resource required headers in the resource instance, the client returned will
+// send those values instead of the ones configured in the provider.
+func (r resourceFactory) getInstanceClient(data *schema.ResourceData, i interface{}) ClientOpenAPI {
 	providerClient := i.(ClientOpenAPI)
+	headerOverrides := map[string]string{}
+	for _, headerParam := range r.getRequiredHeaderParameters() {
+		headerTerraformCompliantName := headerParam.GetHeaderTerraformConfigurationName()
+		if value, exists := data.GetOk(headerTerraformCompliantName); exists {
+			if headerValue, ok := value.(string); ok {
+				headerOverrides[headerTerraformCompliantName] = headerValue
+			}
+		}
+	}
+	if len(headerOverrides) == 0 {
+		return providerClient
+	}
+	if client, ok := providerClient.(*ProviderClient); ok {
+		return client.withHeaderOverrides(headerOverrides)
+	}
+	return providerClient
+}
+
+// checkOpenAPIResource returns an error if the resource factory was created with no openAPIResource, which the resource
+// operations rely on
+func (r resourceFactory) checkOpenAPIResource() error {
+	if r.openAPIResource == nil {
+		return errors.New("can't perform resource operations with a resourceFactory with no openAPIResource")
+	}
+	return nil
+}
+
+// create is the CreateContext function of the resource, see createResource
+func (r resourceFactory) create(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	ctx, cancel := operationContext(ctx, i)
+	defer cancel()
+	return operationDiagnostics(ctx, data, schema.TimeoutCreate, r.createResource(ctx, data, i))
+}
+
+// createResource creates the resource instance making the API calls with the given context, waiting for it to be ready
+// if the API creates it asynchronously
+func (r resourceFactory) createResource(ctx context.Context, data *schema.ResourceData, i interface{}) error {
+	if err := r.checkOpenAPIResource(); err != nil {
+		return err
+	}
+	if r.openAPIResource.isImportOnly() {
+		return messages.error(MessageImportOnlyCreate, messageArgs{"resource": r.openAPIResource.getResourceName()})
+	}
+	if err := checkWriteAllowed(i, r.openAPIResource.getResourceName(), "create"); err != nil {
+		return err
+	}
+	r.retryBudget.start()
+	providerClient := r.getInstanceClient(data, i)
 
 	parentIDs, resourcePath, err := getParentIDsAndResourcePath(r.openAPIResource, data)
 	if err != nil {
@@ -99,12 +464,12 @@ func (r resourceFactory) create(data *schema.ResourceData, i interface{}) error
 	requestPayload := r.createPayloadFromLocalStateData(data)
 	responsePayload := map[string]interface{}{}
 
-	res, err := providerClient.Post(r.openAPIResource, requestPayload, &responsePayload, parentIDs...)
+	res, err := contextClient(providerClient).PostContext(ctx, r.openAPIResource, requestPayload, &responsePayload, parentIDs...)
 	if err != nil {
 		return err
 	}
 	if err := checkHTTPStatusCode(r.openAPIResource, res, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted}); err != nil {
-		return fmt.Errorf("[resource='%s'] POST %s failed: %s", r.openAPIResource.getResourceName(), resourcePath, err)
+		return wrapError(err, "%s", messages.format(MessageOperationFailed, messageArgs{"resource": r.openAPIResource.getResourceName(), "operation": "POST", "path": resourcePath}))
 	}
 
 	err = setStateID(r.openAPIResource, data, responsePayload)
@@ -113,23 +478,133 @@ func (r resourceFactory) create(data *schema.ResourceData, i interface{}) error
 	}
 	log.Printf("[INFO] Resource '%s' ID: %s", resourcePath, data.Id())
 
-	err = r.handlePollingIfConfigured(&responsePayload, data, providerClient, operation, res.StatusCode, schema.TimeoutCreate)
+	err = r.handlePollingIfConfigured(ctx, &responsePayload, data, providerClient, operation, res.StatusCode, res.Header, schema.TimeoutCreate)
+	if err != nil {
+		return r.cleanupOnFailure(ctx, data, providerClient, parentIDs, fmt.Errorf("polling mechanism failed after POST %s call with response status code (%d): %s", resourcePath, res.StatusCode, err))
+	}
+
+	responsePayload, err = r.resolveResponsePayload(ctx, responsePayload, data, providerClient, operation, res.StatusCode, parentIDs...)
+	if err != nil {
+		return r.cleanupOnFailure(ctx, data, providerClient, parentIDs, fmt.Errorf("GET %s/%s failed after POST %s call returned a summary of the resource with response status code (%d): %s", resourcePath, data.Id(), resourcePath, res.StatusCode, err))
+	}
+
+	return r.updateState(responsePayload, data, providerClient)
+}
+
+// resolveResponsePayload returns the payload the state should be updated with given the response received for the
+// operation:
+// - if the response is documented as a summary of the resource (x-terraform-resource-summary-response), the resource is
+// read again so all the computed properties get populated
+// - if the response documents its own schema, the properties whose type diverges from the one in the resource schema
+// (e.g. a summary returning just the id of a nested object) are left out so they do not corrupt the state
+// Responses that enabled the polling mechanism are returned as is since the payload already comes from the resource GET.
+func (r resourceFactory) resolveResponsePayload(ctx context.Context, responsePayload map[string]interface{}, data *schema.ResourceData, providerClient ClientOpenAPI, operation *specResourceOperation, responseStatusCode int, parentIDs ...string) (map[string]interface{}, error) {
+	if operation == nil {
+		return responsePayload, nil
+	}
+	response := operation.responses.getResponse(responseStatusCode)
+	if response == nil || response.isPollingEnabled {
+		return responsePayload, nil
+	}
+	if response.isSummary {
+		log.Printf("[INFO] [resource='%s'] response status code (%d) returns a summary of the resource, reading the resource '%s' to populate all its properties", r.openAPIResource.getResourceName(), responseStatusCode, data.Id())
+		instanceID, err := getResourceInstanceID(r.openAPIResource, data)
+		if err != nil {
+			return nil, err
+		}
+		return r.readRemoteEventuallyConsistent(ctx, operation.EventualConsistency, instanceID, providerClient, parentIDs...)
+	}
+	if response.schema == nil {
+		return responsePayload, nil
+	}
+	resourceSchema, err := r.openAPIResource.getResourceSchema()
+	if err != nil {
+		return nil, err
+	}
+	payload := map[string]interface{}{}
+	for propertyName, propertyValue := range responsePayload {
+		responseProperty, err := response.schema.getProperty(propertyName)
+		if err != nil {
+			payload[propertyName] = propertyValue
+			continue
+		}
+		resourceProperty, err := resourceSchema.getProperty(propertyName)
+		if err != nil {
+			payload[propertyName] = propertyValue
+			continue
+		}
+		if responseProperty.Type != resourceProperty.Type {
+			log.Printf("[WARN] [resource='%s'] property '%s' returned with response status code (%d) is of type '%s' while the resource schema defines it as '%s', ignoring the value returned", r.openAPIResource.getResourceName(), propertyName, responseStatusCode, responseProperty.Type, resourceProperty.Type)
+			continue
+		}
+		payload[propertyName] = propertyValue
+	}
+	return payload, nil
+}
+
+// cleanupOnFailure calls the cleanup operation referenced by the POST operation via the x-terraform-on-failure-cleanup
+// extension (e.g. POST /v1/resource/{id}/abort) so failed creates that already returned an id do not leave orphan resources
+// behind. If the cleanup succeeds the resource is removed from the state. The create error is always returned, including
+// the cleanup error if it failed too.
+func (r resourceFactory) cleanupOnFailure(ctx context.Context, data *schema.ResourceData, providerClient ClientOpenAPI, parentIDs []string, createErr error) error {
+	operations := r.openAPIResource.getResourceOperations()
+	if operations.Post == nil || operations.Post.OnFailureCleanup == nil {
+		return createErr
+	}
+	cleanup := operations.Post.OnFailureCleanup
+	client, ok := providerClient.(onFailureCleanupClient)
+	if !ok {
+		log.Printf("[WARN] [resource='%s'] the cleanup operation can not be called by the client, skipping cleanup of the partially created resource '%s'", r.openAPIResource.getResourceName(), data.Id())
+		return createErr
+	}
+	log.Printf("[INFO] [resource='%s'] create failed, cleaning up the partially created resource '%s' (%s %s)", r.openAPIResource.getResourceName(), data.Id(), cleanup.method, cleanup.path)
+	instanceID, err := getResourceInstanceID(r.openAPIResource, data)
+	if err != nil {
+		return fmt.Errorf("%s; the cleanup of the partially created resource '%s' failed too and it may need to be cleaned up manually: %s", createErr, data.Id(), err)
+	}
+	// the create may have failed because its timeout expired, so the cleanup is not bound to the context of the create
+	cleanupCtx, cancel := cleanupContext(ctx, providerClient)
+	defer cancel()
+	res, err := client.CallCleanupOperation(cleanupCtx, r.openAPIResource, instanceID, cleanup, parentIDs...)
+	if err == nil {
+		err = checkHTTPStatusCode(r.openAPIResource, res, []int{http.StatusNoContent, http.StatusOK, http.StatusAccepted})
+	}
 	if err != nil {
-		return fmt.Errorf("polling mechanism failed after POST %s call with response status code (%d): %s", resourcePath, res.StatusCode, err)
+		return fmt.Errorf("%s; the cleanup of the partially created resource '%s' failed too and it may need to be cleaned up manually: %s", createErr, data.Id(), err)
 	}
+	data.SetId("")
+	return createErr
+}
 
-	return updateStateWithPayloadData(r.openAPIResource, responsePayload, data)
+// read is the ReadContext function of the resource, see readResource
+func (r resourceFactory) read(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	ctx, cancel := operationContext(ctx, i)
+	defer cancel()
+	return operationDiagnostics(ctx, data, schema.TimeoutRead, r.readResource(ctx, data, i))
 }
 
-func (r resourceFactory) read(data *schema.ResourceData, i interface{}) error {
-	openAPIClient := i.(ClientOpenAPI)
+// readResource refreshes the state of the resource instance making the API calls with the given context. The state is
+// left as is if the resource instance is not found
+func (r resourceFactory) readResource(ctx context.Context, data *schema.ResourceData, i interface{}) error {
+	if err := r.checkOpenAPIResource(); err != nil {
+		return err
+	}
+	openAPIClient := r.getInstanceClient(data, i)
 
 	parentsIDs, resourcePath, err := getParentIDsAndResourcePath(r.openAPIResource, data)
 	if err != nil {
 		return err
 	}
+	instanceID, err := getResourceInstanceID(r.openAPIResource, data)
+	if err != nil {
+		return err
+	}
 
-	remoteData, err := r.readRemote(data.Id(), openAPIClient, parentsIDs...)
+	skippedProperties := r.getRefreshOnDemandPropertiesToSkip(data, i)
+	remoteData := r.batchReadRemote(ctx, data, openAPIClient, parentsIDs...)
+	if remoteData == nil {
+		remoteData, err = r.readRemote(ctx, instanceID, r.withRefreshFieldsQuery(openAPIClient, skippedProperties), parentsIDs...)
+	}
 
 	if err != nil {
 		if openapiErr, ok := err.(openapierr.Error); ok {
@@ -137,16 +612,82 @@ func (r resourceFactory) read(data *schema.ResourceData, i interface{}) error {
 				return nil
 			}
 		}
-		return fmt.Errorf("[resource='%s'] GET %s/%s failed: %s", r.openAPIResource.getResourceName(), resourcePath, data.Id(), err)
+		return wrapError(err, "%s", messages.format(MessageOperationFailed, messageArgs{"resource": r.openAPIResource.getResourceName(), "operation": "GET", "path": resourcePath + "/" + instanceID}))
 	}
 
-	return updateStateWithPayloadData(r.openAPIResource, remoteData, data)
+	return r.updateState(withoutProperties(remoteData, skippedProperties), data, openAPIClient)
 }
 
-func (r resourceFactory) readRemote(id string, providerClient ClientOpenAPI, parentIDs ...string) (map[string]interface{}, error) {
+// getRefreshOnDemandPropertiesToSkip returns the properties marked with the x-terraform-refresh-on-demand extension that
+// should not be refreshed, that is the ones that already have a value in the state. Properties without a value (e.g. the
+// resource is being imported) are always refreshed, and so are all of them if the provider is configured to perform full
+// refreshes
+func (r resourceFactory) getRefreshOnDemandPropertiesToSkip(data *schema.ResourceData, i interface{}) map[string]*specSchemaDefinitionProperty {
+	if isFullRefreshEnabled(i) {
+		return nil
+	}
+	resourceSchema, err := r.openAPIResource.getResourceSchema()
+	if err != nil {
+		return nil
+	}
+	skippedProperties := map[string]*specSchemaDefinitionProperty{}
+	for _, property := range resourceSchema.Properties {
+		if !property.RefreshOnDemand {
+			continue
+		}
+		if _, exists := data.GetOk(property.getTerraformCompliantPropertyName()); exists {
+			log.Printf("[DEBUG] [resource='%s'] property '%s' is refreshed on demand only, keeping the value stored in the state", r.openAPIResource.getResourceName(), property.Name)
+			skippedProperties[property.Name] = property
+		}
+	}
+	return skippedProperties
+}
+
+// withRefreshFieldsQuery returns a client that sends in the instance GET request the names of the properties that need
+// to be refreshed (the ones not skipped) if the GET operation supports limiting the properties returned by the API
+// (x-terraform-refresh-fields-query-param extension); otherwise the given client is returned
+func (r resourceFactory) withRefreshFieldsQuery(providerClient ClientOpenAPI, skippedProperties map[string]*specSchemaDefinitionProperty) ClientOpenAPI {
+	getOperation := r.openAPIResource.getResourceOperations().Get
+	if len(skippedProperties) == 0 || getOperation == nil || getOperation.RefreshFieldsQueryParam == "" {
+		return providerClient
+	}
+	client, ok := providerClient.(*ProviderClient)
+	if !ok {
+		return providerClient
+	}
+	resourceSchema, err := r.openAPIResource.getResourceSchema()
+	if err != nil {
+		return providerClient
+	}
+	var fields []string
+	for _, property := range resourceSchema.Properties {
+		if _, skipped := skippedProperties[property.Name]; skipped || property.isDerivedProperty() {
+			continue
+		}
+		fields = append(fields, property.Name)
+	}
+	return client.withQueryParameters(url.Values{getOperation.RefreshFieldsQueryParam: {strings.Join(fields, ",")}})
+}
+
+// withoutProperties returns a copy of the payload without the given properties. A copy is returned since the payload might
+// be shared (e.g. the cached collection response used for batch reads)
+func withoutProperties(payload map[string]interface{}, properties map[string]*specSchemaDefinitionProperty) map[string]interface{} {
+	if len(properties) == 0 {
+		return payload
+	}
+	filteredPayload := map[string]interface{}{}
+	for propertyName, propertyValue := range payload {
+		if _, exists := properties[propertyName]; !exists {
+			filteredPayload[propertyName] = propertyValue
+		}
+	}
+	return filteredPayload
+}
+
+func (r resourceFactory) readRemote(ctx context.Context, id string, providerClient ClientOpenAPI, parentIDs ...string) (map[string]interface{}, error) {
 	var err error
 	responsePayload := map[string]interface{}{}
-	resp, err := providerClient.Get(r.openAPIResource, id, &responsePayload, parentIDs...)
+	resp, err := contextClient(providerClient).GetContext(ctx, r.openAPIResource, id, &responsePayload, parentIDs...)
 	if err != nil {
 		return nil, err
 	}
@@ -159,6 +700,89 @@ func (r resourceFactory) readRemote(id string, providerClient ClientOpenAPI, par
 	return responsePayload, nil
 }
 
+// batchReadRemote returns the remote data for the given resource instance out of the collection GET response if the
+// resource has batch read enabled (x-terraform-batch-read extension in the collection GET operation). The collection
+// response is cached by the client during the terraform run so refreshing many instances of the same resource only
+// results in one API call. Nil is returned if batch read is not enabled, the client does not cache responses or the
+// instance could not be found in the collection response (e.g. the collection is paginated), in which case the caller
+// is expected to fall back to reading the instance individually. Nil is also returned if the collection response is
+// documented as a summary of the resource (x-terraform-resource-summary-response), since its items do not contain all
+// the properties of the resource and storing them would wipe the rest from the state.
+// The collection GET goes through the same request path as any other call, so it is subject to the max parallel API calls
+// limit and waits when the API rate limits the requests (429) like the individual reads would.
+func (r resourceFactory) batchReadRemote(ctx context.Context, data *schema.ResourceData, providerClient ClientOpenAPI, parentIDs ...string) map[string]interface{} {
+	listOperation := r.openAPIResource.getResourceOperations().List
+	if listOperation == nil || !listOperation.BatchRead {
+		return nil
+	}
+	if response := listOperation.responses.getResponse(http.StatusOK); response != nil && response.isSummary {
+		log.Printf("[DEBUG] [resource='%s'] batch read skipped as the collection response is a summary of the resource", r.openAPIResource.getResourceName())
+		return nil
+	}
+	if client, ok := providerClient.(*ProviderClient); ok && client.responseCache == nil {
+		log.Printf("[DEBUG] [resource='%s'] batch read skipped as the response cache is disabled", r.openAPIResource.getResourceName())
+		return nil
+	}
+	id := data.Id()
+	isInstance, err := r.listItemMatcher(data)
+	if err != nil {
+		return nil
+	}
+	responsePayload := []map[string]interface{}{}
+	resp, err := contextClient(providerClient).ListContext(ctx, r.openAPIResource, &responsePayload, parentIDs...)
+	if err == nil {
+		err = checkHTTPStatusCode(r.openAPIResource, resp, []int{http.StatusOK})
+	}
+	if err != nil {
+		log.Printf("[WARN] [resource='%s'] batch read failed, falling back to reading the instance '%s' individually: %s", r.openAPIResource.getResourceName(), id, err)
+		return nil
+	}
+	for _, item := range responsePayload {
+		if isInstance(item) {
+			log.Printf("[DEBUG] [resource='%s'] instance '%s' found in the collection response", r.openAPIResource.getResourceName(), id)
+			return item
+		}
+	}
+	log.Printf("[DEBUG] [resource='%s'] instance '%s' not found in the collection response, falling back to reading it individually", r.openAPIResource.getResourceName(), id)
+	return nil
+}
+
+// listItemMatcher returns the function that tells whether an item of the collection GET response is the given resource
+// instance. Resources identified by a composite id (x-terraform-composite-id extension) are matched on the values of all
+// the composite id properties, since the last one alone may not be unique across the collection (e.g. things with the
+// same name in different namespaces); the rest are matched on the resource identifier
+func (r resourceFactory) listItemMatcher(data *schema.ResourceData) (func(item map[string]interface{}) bool, error) {
+	compositeIDValues, err := getCompositeIDValues(r.openAPIResource, data)
+	if err != nil {
+		return nil, err
+	}
+	if compositeIDValues != nil {
+		compositeID := r.openAPIResource.getCompositeID()
+		return func(item map[string]interface{}) bool {
+			for idx, propertyName := range compositeID {
+				value, exists := item[propertyName]
+				if !exists || identifierValueToString(value) != compositeIDValues[idx] {
+					return false
+				}
+			}
+			return true
+		}, nil
+	}
+	resourceSchema, err := r.openAPIResource.getResourceSchema()
+	if err != nil {
+		return nil, err
+	}
+	identifierProperty, err := resourceSchema.getResourceIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	id := data.Id()
+	return func(item map[string]interface{}) bool {
+		itemID, exists := item[identifierProperty]
+		return exists && identifierValueToString(itemID) == id
+	}, nil
+}
+
 func (r resourceFactory) getParentIDs(data *schema.ResourceData) ([]string, error) {
 	if r.openAPIResource == nil {
 		return []string{}, errors.New("can't get parent ids from a resourceFactory with no openAPIResource")
@@ -182,8 +806,27 @@ func (r resourceFactory) getParentIDs(data *schema.ResourceData) ([]string, erro
 	return []string{}, nil
 }
 
-func (r resourceFactory) update(data *schema.ResourceData, i interface{}) error {
-	providerClient := i.(ClientOpenAPI)
+// update is the UpdateContext function of the resource, see updateResource
+func (r resourceFactory) update(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	ctx, cancel := operationContext(ctx, i)
+	defer cancel()
+	return operationDiagnostics(ctx, data, schema.TimeoutUpdate, r.updateResource(ctx, data, i))
+}
+
+// updateResource updates the resource instance making the API calls with the given context, waiting for the update to
+// complete if the API performs it asynchronously
+func (r resourceFactory) updateResource(ctx context.Context, data *schema.ResourceData, i interface{}) error {
+	if err := r.checkOpenAPIResource(); err != nil {
+		return err
+	}
+	if r.openAPIResource.isImportOnly() {
+		return messages.error(MessageImportOnlyUpdate, messageArgs{"resource": r.openAPIResource.getResourceName()})
+	}
+	if err := checkWriteAllowed(i, r.openAPIResource.getResourceName(), "update"); err != nil {
+		return err
+	}
+	r.retryBudget.start()
+	providerClient := r.getInstanceClient(data, i)
 
 	parentsIDs, resourcePath, err := getParentIDsAndResourcePath(r.openAPIResource, data)
 	if err != nil {
@@ -194,29 +837,117 @@ func (r resourceFactory) update(data *schema.ResourceData, i interface{}) error
 	if operation == nil {
 		return fmt.Errorf("[resource='%s'] resource does not support PUT operation, check the swagger file exposed on '%s'", r.openAPIResource.getResourceName(), resourcePath)
 	}
-	requestPayload := r.createPayloadFromLocalStateData(data)
+	instanceID, err := getResourceInstanceID(r.openAPIResource, data)
+	if err != nil {
+		return err
+	}
 	responsePayload := map[string]interface{}{}
-	if err := r.checkImmutableFields(data, providerClient, parentsIDs...); err != nil {
+	if err := r.checkImmutableFields(ctx, data, providerClient, parentsIDs...); err != nil {
 		return err
 	}
-	res, err := providerClient.Put(r.openAPIResource, data.Id(), requestPayload, &responsePayload, parentsIDs...)
+	var res *http.Response
+	updateClient := r.withUpdateMask(providerClient, operation, data)
+	if operation.UpdateStrategy == updateStrategyJSONPatch {
+		patchClient, ok := updateClient.(jsonPatchClient)
+		if !ok {
+			return fmt.Errorf("[resource='%s'] the client does not support the '%s' update strategy", r.openAPIResource.getResourceName(), updateStrategyJSONPatch)
+		}
+		patchOperations := r.createJSONPatchFromLocalStateData(data)
+		res, err = patchClient.Patch(ctx, r.openAPIResource, instanceID, patchOperations, &responsePayload, parentsIDs...)
+	} else {
+		requestPayload := r.createPayloadFromLocalStateData(data)
+		res, err = contextClient(updateClient).PutContext(ctx, r.openAPIResource, instanceID, requestPayload, &responsePayload, parentsIDs...)
+	}
 	if err != nil {
 		return err
 	}
-	if err := checkHTTPStatusCode(r.openAPIResource, res, []int{http.StatusOK, http.StatusAccepted}); err != nil {
-		return fmt.Errorf("[resource='%s'] UPDATE %s/%s failed: %s", r.openAPIResource.getResourceName(), resourcePath, data.Id(), err)
+	if err := checkHTTPStatusCode(r.openAPIResource, res, []int{http.StatusOK, http.StatusAccepted, http.StatusNoContent}); err != nil {
+		return wrapError(err, "%s", messages.format(MessageOperationFailed, messageArgs{"resource": r.openAPIResource.getResourceName(), "operation": "UPDATE", "path": resourcePath + "/" + instanceID}))
 	}
 
-	err = r.handlePollingIfConfigured(&responsePayload, data, providerClient, operation, res.StatusCode, schema.TimeoutUpdate)
+	err = r.handlePollingIfConfigured(ctx, &responsePayload, data, providerClient, operation, res.StatusCode, res.Header, schema.TimeoutUpdate)
 	if err != nil {
 		return fmt.Errorf("polling mechanism failed after PUT %s call with response status code (%d): %s", resourcePath, res.StatusCode, err)
 	}
 
-	return updateStateWithPayloadData(r.openAPIResource, responsePayload, data)
+	// If the API did not return the updated resource (e.g. 204 No Content), the remote resource is read so the state
+	// contains the values computed by the API rather than just the ones sent in the request
+	if len(responsePayload) == 0 {
+		log.Printf("[DEBUG] [resource='%s'] PUT %s/%s response (%d) did not contain a body, reading the remote resource to refresh the state", r.openAPIResource.getResourceName(), resourcePath, instanceID, res.StatusCode)
+		responsePayload, err = r.readRemote(ctx, instanceID, providerClient, parentsIDs...)
+		if err != nil {
+			return fmt.Errorf("[resource='%s'] GET %s/%s after UPDATE failed: %s", r.openAPIResource.getResourceName(), resourcePath, instanceID, err)
+		}
+	}
+
+	return r.updateState(responsePayload, data, providerClient)
 }
 
-func (r resourceFactory) delete(data *schema.ResourceData, i interface{}) error {
-	providerClient := i.(ClientOpenAPI)
+// withUpdateMask returns a client that sends in the update request the mask with the names of the properties changed if
+// the update operation expects so (query parameter marked with the x-terraform-update-mask extension); otherwise the given
+// client is returned
+func (r resourceFactory) withUpdateMask(providerClient ClientOpenAPI, operation *specResourceOperation, data *schema.ResourceData) ClientOpenAPI {
+	if operation.UpdateMaskQueryParam == "" {
+		return providerClient
+	}
+	client, ok := providerClient.(*ProviderClient)
+	if !ok {
+		return providerClient
+	}
+	updateMask := r.getUpdateMask(data)
+	if len(updateMask) == 0 {
+		return providerClient
+	}
+	log.Printf("[DEBUG] [resource='%s'] update mask: %s", r.openAPIResource.getResourceName(), strings.Join(updateMask, ","))
+	return client.withQueryParameters(url.Values{operation.UpdateMaskQueryParam: {strings.Join(updateMask, ",")}})
+}
+
+// getUpdateMask returns the names (as named in the API) of the top level properties changed in the terraform configuration.
+// ReadOnly and parent properties are never part of the mask since they are not sent to the API
+func (r resourceFactory) getUpdateMask(data *schema.ResourceData) []string {
+	resourceSchema, err := r.openAPIResource.getResourceSchema()
+	if err != nil {
+		return nil
+	}
+	var updateMask []string
+	for _, property := range resourceSchema.Properties {
+		if property.isReadOnly() || property.IsParentProperty {
+			continue
+		}
+		if data.HasChange(property.getTerraformCompliantPropertyName()) {
+			updateMask = append(updateMask, property.Name)
+		}
+	}
+	return updateMask
+}
+
+// delete is the DeleteContext function of the resource, see deleteResource
+func (r resourceFactory) delete(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	ctx, cancel := operationContext(ctx, i)
+	defer cancel()
+	return operationDiagnostics(ctx, data, schema.TimeoutDelete, r.deleteResource(ctx, data, i))
+}
+
+// deleteResource deletes the resource instance making the API calls with the given context, waiting for the deletion to
+// complete if the API performs it asynchronously or the deletion must be confirmed
+func (r resourceFactory) deleteResource(ctx context.Context, data *schema.ResourceData, i interface{}) error {
+	if err := r.checkOpenAPIResource(); err != nil {
+		return err
+	}
+	// Import-only resources are never deleted, destroying them only removes them from the terraform state
+	if r.openAPIResource.isImportOnly() {
+		log.Printf("[INFO] [resource='%s'] resource is import-only, removing '%s' from the state without deleting the remote instance", r.openAPIResource.getResourceName(), data.Id())
+		return nil
+	}
+	if err := checkWriteAllowed(i, r.openAPIResource.getResourceName(), "delete"); err != nil {
+		return err
+	}
+	r.retryBudget.start()
+	if err := r.checkDestroyAllowed(i); err != nil {
+		return err
+	}
+
+	providerClient := r.getInstanceClient(data, i)
 
 	parentsIDs, resourcePath, err := getParentIDsAndResourcePath(r.openAPIResource, data)
 	if err != nil {
@@ -227,7 +958,11 @@ func (r resourceFactory) delete(data *schema.ResourceData, i interface{}) error
 	if operation == nil {
 		return fmt.Errorf("[resource='%s'] resource does not support DELETE operation, check the swagger file exposed on '%s'", r.openAPIResource.getResourceName(), resourcePath)
 	}
-	res, err := providerClient.Delete(r.openAPIResource, data.Id(), parentsIDs...)
+	instanceID, err := getResourceInstanceID(r.openAPIResource, data)
+	if err != nil {
+		return err
+	}
+	res, err := contextClient(providerClient).DeleteContext(ctx, r.openAPIResource, instanceID, parentsIDs...)
 	if err != nil {
 		return err
 	}
@@ -237,22 +972,172 @@ func (r resourceFactory) delete(data *schema.ResourceData, i interface{}) error
 				return nil
 			}
 		}
-		return fmt.Errorf("[resource='%s'] DELETE %s/%s failed: %s", r.openAPIResource.getResourceName(), resourcePath, data.Id(), err)
+		return wrapError(err, "%s", messages.format(MessageOperationFailed, messageArgs{"resource": r.openAPIResource.getResourceName(), "operation": "DELETE", "path": resourcePath + "/" + instanceID}))
 	}
 
-	err = r.handlePollingIfConfigured(nil, data, providerClient, operation, res.StatusCode, schema.TimeoutDelete)
+	err = r.handlePollingIfConfigured(ctx, nil, data, providerClient, operation, res.StatusCode, res.Header, schema.TimeoutDelete)
 	if err != nil {
 		return fmt.Errorf("polling mechanism failed after DELETE %s call with response status code (%d): %s", resourcePath, res.StatusCode, err)
 	}
 
+	if operation.ConfirmDeleteViaList {
+		if err := r.waitForListAbsence(ctx, data, providerClient, parentsIDs...); err != nil {
+			return fmt.Errorf("[resource='%s'] failed to confirm DELETE %s/%s completion: %s", r.openAPIResource.getResourceName(), resourcePath, instanceID, err)
+		}
+	}
+
+	if r.isWaitForNotFoundEnabled(operation, i) {
+		if err := r.waitForNotFound(ctx, data, providerClient, instanceID, parentsIDs...); err != nil {
+			return fmt.Errorf("[resource='%s'] failed to confirm DELETE %s/%s completion: %s", r.openAPIResource.getResourceName(), resourcePath, instanceID, err)
+		}
+	}
+
 	return nil
 }
 
+// isWaitForNotFoundEnabled returns true if the deletion of the resource must be confirmed by reading the instance until the
+// API no longer finds it, either because the DELETE operation is documented to do so (x-terraform-delete-wait-for-not-found
+// extension) or the user configured the provider's wait_for_deletion property with the resource
+func (r resourceFactory) isWaitForNotFoundEnabled(operation *specResourceOperation, i interface{}) bool {
+	if operation.WaitForNotFound {
+		return true
+	}
+	if providerClient, ok := i.(*ProviderClient); ok {
+		return providerClient.providerConfiguration.isWaitForDeletionEnabled(r.openAPIResource.getResourceName())
+	}
+	return false
+}
+
+// waitForNotFound polls the resource instance GET operation until the API returns 404 Not Found or 410 Gone, so the DELETE
+// does not complete (and dependent resources are not created) while the API is still tearing the instance down
+func (r resourceFactory) waitForNotFound(ctx context.Context, data *schema.ResourceData, providerClient ClientOpenAPI, instanceID string, parentIDs ...string) error {
+	timeout, err := r.retryBudget.timeoutFor(data.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Waiting for resource '%s' instance '%s' to no longer be found by the instance GET operation", r.openAPIResource.getResourceName(), data.Id())
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{deletePendingStatus},
+		Target:       []string{defaultDestroyStatus},
+		Refresh:      r.notFoundRefreshFunc(ctx, instanceID, providerClient, parentIDs...),
+		Timeout:      timeout,
+		PollInterval: r.defaultPollInterval,
+		MinTimeout:   r.defaultPollMinTimeout,
+		Delay:        r.defaultPollDelay,
+	}
+	start := time.Now()
+	_, err = waitForState(ctx, stateConf)
+	r.retryBudget.consume(time.Since(start))
+	return err
+}
+
+// notFoundRefreshFunc returns the function used by waitForNotFound to check whether the resource instance still exists
+func (r resourceFactory) notFoundRefreshFunc(ctx context.Context, instanceID string, providerClient ClientOpenAPI, parentIDs ...string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		responsePayload := map[string]interface{}{}
+		resp, err := contextClient(providerClient).GetContext(ctx, r.openAPIResource, instanceID, &responsePayload, parentIDs...)
+		if err != nil {
+			return nil, "", err
+		}
+		if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+			return 0, defaultDestroyStatus, nil
+		}
+		if err := checkHTTPStatusCode(r.openAPIResource, resp, []int{http.StatusOK}); err != nil {
+			return nil, "", err
+		}
+		log.Printf("[DEBUG] [resource='%s'] instance '%s' is still returned by the instance GET operation", r.openAPIResource.getResourceName(), instanceID)
+		return responsePayload, deletePendingStatus, nil
+	}
+}
+
+// waitForListAbsence polls the resource collection GET operation until the given resource instance is no longer listed. This
+// is used to confirm the deletion of resources whose API does not expose the instance once the DELETE is accepted (so the
+// regular polling mechanism can not be used) but keeps listing it until the deletion is actually completed.
+func (r resourceFactory) waitForListAbsence(ctx context.Context, data *schema.ResourceData, providerClient ClientOpenAPI, parentIDs ...string) error {
+	if r.openAPIResource.getResourceOperations().List == nil {
+		return fmt.Errorf("the resource does not support the collection GET operation needed to confirm the deletion (%s extension)", extTfDeleteConfirmViaList)
+	}
+	isInstance, err := r.listItemMatcher(data)
+	if err != nil {
+		return err
+	}
+	timeout, err := r.retryBudget.timeoutFor(data.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Waiting for resource '%s' instance '%s' to no longer be listed in the collection GET response", r.openAPIResource.getResourceName(), data.Id())
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{deletePendingStatus},
+		Target:       []string{defaultDestroyStatus},
+		Refresh:      r.listAbsenceRefreshFunc(ctx, data.Id(), isInstance, providerClient, parentIDs...),
+		Timeout:      timeout,
+		PollInterval: r.defaultPollInterval,
+		MinTimeout:   r.defaultPollMinTimeout,
+		Delay:        r.defaultPollDelay,
+	}
+	start := time.Now()
+	_, err = waitForState(ctx, stateConf)
+	r.retryBudget.consume(time.Since(start))
+	return err
+}
+
+// listAbsenceRefreshFunc returns the function used by waitForListAbsence to check whether the resource instance is still
+// listed, that is whether any of the items of the collection matches isInstance. The response cache is invalidated before
+// every call so each poll gets the current collection from the API.
+func (r resourceFactory) listAbsenceRefreshFunc(ctx context.Context, id string, isInstance func(item map[string]interface{}) bool, providerClient ClientOpenAPI, parentIDs ...string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		if client, ok := providerClient.(*ProviderClient); ok && client.responseCache != nil {
+			client.responseCache.invalidate()
+		}
+		responsePayload := []map[string]interface{}{}
+		resp, err := contextClient(providerClient).ListContext(ctx, r.openAPIResource, &responsePayload, parentIDs...)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := checkHTTPStatusCode(r.openAPIResource, resp, []int{http.StatusOK}); err != nil {
+			return nil, "", err
+		}
+		for _, item := range responsePayload {
+			if isInstance(item) {
+				log.Printf("[DEBUG] [resource='%s'] instance '%s' is still listed in the collection response", r.openAPIResource.getResourceName(), id)
+				return item, deletePendingStatus, nil
+			}
+		}
+		return 0, defaultDestroyStatus, nil
+	}
+}
+
+// checkDestroyAllowed returns an error if the resource is protected by the service configuration prevent destroy policy
+// and the user has not set the provider's override_prevent_destroy property
+func (r resourceFactory) checkDestroyAllowed(i interface{}) error {
+	if !r.preventDestroy {
+		return nil
+	}
+	if providerClient, ok := i.(*ProviderClient); ok && providerClient.providerConfiguration.OverridePreventDestroy {
+		log.Printf("[WARN] resource '%s' is protected by the prevent_destroy policy but the provider is configured to override it, proceeding with the DELETE", r.openAPIResource.getResourceName())
+		return nil
+	}
+	return messages.error(MessageDestroyPrevented, messageArgs{"resource": r.openAPIResource.getResourceName(), "provider_property": providerPropertyOverridePreventDestroy})
+}
+
 func (r resourceFactory) importer() *schema.ResourceImporter {
 	return &schema.ResourceImporter{
-		State: func(data *schema.ResourceData, i interface{}) ([]*schema.ResourceData, error) {
+		StateContext: func(ctx context.Context, data *schema.ResourceData, i interface{}) ([]*schema.ResourceData, error) {
+			ctx, cancel := operationContext(ctx, i)
+			defer cancel()
 			results := make([]*schema.ResourceData, 1, 1)
 			results[0] = data
+			if compositeID := r.openAPIResource.getCompositeID(); len(compositeID) > 0 {
+				// The expected format for the ID provided when importing a resource identified by a composite id is ns1/thing1
+				// where ns1 and thing1 would be the values of the composite id properties in the order they are listed
+				if err := r.setCompositeIDProperties(data, compositeID); err != nil {
+					return results, err
+				}
+				err := r.readResource(ctx, data, i)
+				return results, err
+			}
 			parentResourceInfo := r.openAPIResource.getParentResourceInfo()
 			if parentResourceInfo != nil {
 				parentPropertyNames := parentResourceInfo.getParentPropertiesNames()
@@ -276,13 +1161,46 @@ func (r resourceFactory) importer() *schema.ResourceImporter {
 			}
 			// If the resources is NOT a sub-resource and just a top level resource then the array passed in will just contain
 			// 	the data object we get from terraform core without any updates.
-			err := r.read(data, i)
+			err := r.readResource(ctx, data, i)
 			return results, err
 		},
 	}
 }
 
-func (r resourceFactory) handlePollingIfConfigured(responsePayload *map[string]interface{}, resourceLocalData *schema.ResourceData, providerClient ClientOpenAPI, operation *specResourceOperation, responseStatusCode int, timeoutFor string) error {
+// setCompositeIDProperties populates the composite id properties of the resource being imported with the values contained
+// in the id provided by the user, so the resource instance path can be resolved when the resource is read
+func (r resourceFactory) setCompositeIDProperties(data *schema.ResourceData, compositeID []string) error {
+	idValues, err := splitCompositeID(compositeID, data.Id())
+	if err != nil {
+		return fmt.Errorf("can not import the resource: %s", err)
+	}
+	resourceSchema, err := r.openAPIResource.getResourceSchema()
+	if err != nil {
+		return err
+	}
+	for idx, propertyName := range compositeID {
+		property, err := resourceSchema.getProperty(propertyName)
+		if err != nil {
+			return err
+		}
+		var value interface{} = idValues[idx]
+		if property.Type == typeInt {
+			if value, err = strconv.Atoi(idValues[idx]); err != nil {
+				return fmt.Errorf("can not import the resource: composite id property '%s' value '%s' is not an integer", propertyName, idValues[idx])
+			}
+		}
+		if err := data.Set(property.getTerraformCompliantPropertyName(), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handlePollingIfConfigured waits for the resource to reach a completion status if the response returned with the given
+// status code enables the polling mechanism. The resource instance is polled unless the response defines the header
+// containing the URL of the asynchronous operation (e.g. Location), in which case the operation is polled instead and the
+// resource is read once the operation completes (except for DELETE operations)
+func (r resourceFactory) handlePollingIfConfigured(ctx context.Context, responsePayload *map[string]interface{}, resourceLocalData *schema.ResourceData, providerClient ClientOpenAPI, operation *specResourceOperation, responseStatusCode int, responseHeader http.Header, timeoutFor string) error {
 	response := operation.responses.getResponse(responseStatusCode)
 
 	if response == nil || !response.isPollingEnabled {
@@ -291,13 +1209,30 @@ func (r resourceFactory) handlePollingIfConfigured(responsePayload *map[string]i
 
 	targetStatuses := response.pollTargetStatuses
 	pendingStatuses := response.pollPendingStatuses
+	refresh := r.resourceStateRefreshFunc(ctx, resourceLocalData, providerClient, response)
+
+	// Resources that may not be found for a short window after being created are read again during the grace period
+	var eventualConsistency *eventualConsistencyPolicy
+	if timeoutFor == schema.TimeoutCreate && operation.EventualConsistency != nil {
+		eventualConsistency = operation.EventualConsistency
+		refresh = eventualConsistency.refreshFunc(ctx, r.openAPIResource.getResourceName(), resourceLocalData.Id(), refresh)
+	}
+
+	operationURL := r.getAsyncOperationURL(response, responseHeader, providerClient)
+	if operationURL != "" {
+		if len(targetStatuses) == 0 {
+			return fmt.Errorf("the completed statuses of the asynchronous operation '%s' are not configured, please use the '%s' extension", operationURL, extTfResourcePollTargetStatuses)
+		}
+		log.Printf("[INFO] polling the asynchronous operation '%s' of resource '%s'", operationURL, r.openAPIResource.getResourceName())
+		refresh = r.asyncOperationStateRefreshFunc(ctx, operationURL, resourceLocalData, providerClient, response)
+	}
 
 	// This is a use case where payload does not contain payload data and hence status field is not available; e,g: DELETE operations
 	// The default behaviour for this case is to consider the resource as destroyed. Hence, the below code pre-populates
 	// the target extension with the expected status that the polling mechanism expects when dealing with NotFound resources (should only happen on delete operations).
 	// Since this is internal behaviour it is not expected that the service provider will populate this field; and if so, it
 	// will be overridden
-	if responsePayload == nil {
+	if responsePayload == nil && operationURL == "" {
 		if len(targetStatuses) > 0 {
 			log.Printf("[WARN] resource speficied poll target statuses for a DELETE operation. This is not expected as the normal behaviour is the resource to no longer exists once the DELETE operation is completed; hence subsequent GET calls should return 404 NotFound instead")
 		}
@@ -308,24 +1243,53 @@ func (r resourceFactory) handlePollingIfConfigured(responsePayload *map[string]i
 	log.Printf("[DEBUG] target statuses (%s); pending statuses (%s)", targetStatuses, pendingStatuses)
 	log.Printf("[INFO] Waiting for resource '%s' to reach a completion status (%s)", r.openAPIResource.getResourceName(), targetStatuses)
 
+	timeout, err := r.retryBudget.timeoutFor(resourceLocalData.Timeout(timeoutFor))
+	if err != nil {
+		return fmt.Errorf("error waiting for resource to reach a completion status (%s) [valid pending statuses (%s)]: %s", targetStatuses, pendingStatuses, err)
+	}
+
+	pollInterval := r.defaultPollInterval
+	if response.pollInterval > 0 {
+		pollInterval = response.pollInterval
+	}
+
 	stateConf := &resource.StateChangeConf{
 		Pending:      pendingStatuses,
 		Target:       targetStatuses,
-		Refresh:      r.resourceStateRefreshFunc(resourceLocalData, providerClient),
-		Timeout:      resourceLocalData.Timeout(timeoutFor),
-		PollInterval: r.defaultPollInterval,
+		Refresh:      refresh,
+		Timeout:      timeout,
+		PollInterval: pollInterval,
 		MinTimeout:   r.defaultPollMinTimeout,
 		Delay:        r.defaultPollDelay,
 	}
+	backoff := newPollBackoff(response, pollInterval)
+	if !backoff.isConstant() {
+		stop := make(chan struct{})
+		defer close(stop)
+		stateConf.Refresh = backoff.refreshFunc(ctx, stop, r.openAPIResource.getResourceName(), stateConf.Refresh)
+		stateConf.PollInterval = pollBackoffTick
+	}
+
+	eventsConsumer := r.startPollEventsConsumer(ctx, resourceLocalData, providerClient, response, pollInterval)
 
 	// Wait, catching any errors
-	remoteData, err := stateConf.WaitForState()
+	start := time.Now()
+	remoteData, err := waitForState(ctx, stateConf)
+	r.retryBudget.consume(time.Since(start))
+	lastEvent := eventsConsumer.stop()
 	if err != nil {
 		return fmt.Errorf("error waiting for resource to reach a completion status (%s) [valid pending statuses (%s)]: %s", targetStatuses, pendingStatuses, err)
 	}
+	if responsePayload != nil && operationURL != "" {
+		// the payload of the operation does not describe the resource, hence the resource is read once it completes
+		if remoteData, err = r.readRemoteAfterPolling(ctx, resourceLocalData, providerClient, eventualConsistency); err != nil {
+			return fmt.Errorf("failed to read the resource after the asynchronous operation '%s' completed: %s", operationURL, err)
+		}
+	}
 	if responsePayload != nil {
 		remoteDataCasted, ok := remoteData.(map[string]interface{})
 		if ok {
+			r.setPollEventsProperty(remoteDataCasted, response, lastEvent)
 			*responsePayload = remoteDataCasted
 		} else {
 			return fmt.Errorf("failed to convert remote data (%s) to map[string]interface{}", reflect.TypeOf(remoteData))
@@ -334,10 +1298,71 @@ func (r resourceFactory) handlePollingIfConfigured(responsePayload *map[string]i
 	return nil
 }
 
-func (r resourceFactory) resourceStateRefreshFunc(resourceLocalData *schema.ResourceData, providerClient ClientOpenAPI) resource.StateRefreshFunc {
+// startPollEventsConsumer starts consuming the progress events of the resource if the response that enabled the polling
+// defines an events path; nil is returned otherwise or if the events can not be streamed
+func (r resourceFactory) startPollEventsConsumer(ctx context.Context, resourceLocalData *schema.ResourceData, providerClient ClientOpenAPI, response *specResponse, pollInterval time.Duration) *pollEventsConsumer {
+	if response.pollEventsPath == "" {
+		return nil
+	}
+	streamer, ok := providerClient.(pollEventsStreamer)
+	if !ok {
+		log.Printf("[WARN] the progress events of resource '%s' can not be streamed by the client, ignoring the events path '%s'", r.openAPIResource.getResourceName(), response.pollEventsPath)
+		return nil
+	}
+	parentIDs, err := getParentIDs(r.openAPIResource, resourceLocalData)
+	if err != nil {
+		log.Printf("[WARN] the progress events of resource '%s' (%s) can not be streamed: %s", r.openAPIResource.getResourceName(), resourceLocalData.Id(), err)
+		return nil
+	}
+	instanceID, err := getResourceInstanceID(r.openAPIResource, resourceLocalData)
+	if err != nil {
+		log.Printf("[WARN] the progress events of resource '%s' (%s) can not be streamed: %s", r.openAPIResource.getResourceName(), resourceLocalData.Id(), err)
+		return nil
+	}
+	consumer := newPollEventsConsumer(r.openAPIResource.getResourceName(), resourceLocalData.Id())
+	consumer.start(ctx, func(ctx context.Context, onEvent func(event json.RawMessage)) error {
+		return streamer.StreamEvents(ctx, r.openAPIResource, instanceID, response.pollEventsPath, onEvent, parentIDs...)
+	}, pollInterval)
+	return consumer
+}
+
+// setPollEventsProperty sets the last progress event received while polling into the payload, as the value of the property
+// configured in the response (if any). The property must be a computed string property of the resource schema
+func (r resourceFactory) setPollEventsProperty(payload map[string]interface{}, response *specResponse, lastEvent json.RawMessage) {
+	if response.pollEventsProperty == "" || lastEvent == nil {
+		return
+	}
+	resourceSchema, err := r.openAPIResource.getResourceSchema()
+	if err != nil {
+		log.Printf("[WARN] the last progress event of resource '%s' can not be stored: %s", r.openAPIResource.getResourceName(), err)
+		return
+	}
+	property, err := resourceSchema.getProperty(response.pollEventsProperty)
+	if err != nil || property.Type != typeString || !property.isComputed() {
+		log.Printf("[WARN] the last progress event of resource '%s' can not be stored: '%s' extension value '%s' must be the name of a computed string property of the resource", r.openAPIResource.getResourceName(), extTfResourcePollEventsProperty, response.pollEventsProperty)
+		return
+	}
+	payload[property.Name] = string(lastEvent)
+}
+
+// resourceStateRefreshFunc returns the function used by the polling mechanism to read the resource status. If the response
+// that enabled the polling defines a status path, the status is read from the payload following it; otherwise the status
+// property of the resource schema is used. Statuses configured as failed make the polling fail straight away. While the
+// resource is in a pending status, the status message returned by the API (if the resource schema has one configured) is
+// reported to the user as a warning every time it changes.
+func (r resourceFactory) resourceStateRefreshFunc(ctx context.Context, resourceLocalData *schema.ResourceData, providerClient ClientOpenAPI, response *specResponse) resource.StateRefreshFunc {
+	var lastStatusMessage string
 	return func() (interface{}, string, error) {
+		parentIDs, err := getParentIDs(r.openAPIResource, resourceLocalData)
+		if err != nil {
+			return nil, "", err
+		}
+		instanceID, err := getResourceInstanceID(r.openAPIResource, resourceLocalData)
+		if err != nil {
+			return nil, "", err
+		}
 
-		remoteData, err := r.readRemote(resourceLocalData.Id(), providerClient)
+		remoteData, err := r.readRemote(ctx, instanceID, providerClient, parentIDs...)
 		if err != nil {
 			if openapiErr, ok := err.(openapierr.Error); ok {
 				if openapierr.NotFound == openapiErr.Code() {
@@ -347,18 +1372,41 @@ func (r resourceFactory) resourceStateRefreshFunc(resourceLocalData *schema.Reso
 			return nil, "", fmt.Errorf("error on retrieving resource '%s' (%s) when waiting: %s", r.openAPIResource.getResourceName(), resourceLocalData.Id(), err)
 		}
 
-		newStatus, err := r.getStatusValueFromPayload(remoteData)
+		newStatus, err := r.getPollStatusValueFromPayload(remoteData, response)
 		if err != nil {
 			return nil, "", fmt.Errorf("error occurred while retrieving status identifier value from payload for resource '%s' (%s): %s", r.openAPIResource.getResourceName(), resourceLocalData.Id(), err)
 		}
 
 		log.Printf("[DEBUG] resource status '%s' (%s): %s", r.openAPIResource.getResourceName(), resourceLocalData.Id(), newStatus)
+		statusMessage := r.getStatusMessageFromPayload(remoteData)
+		if response != nil && response.isPollFailedStatus(newStatus) {
+			if statusMessage != "" {
+				return nil, "", fmt.Errorf("resource '%s' (%s) reached the failed status '%s': %s", r.openAPIResource.getResourceName(), resourceLocalData.Id(), newStatus, statusMessage)
+			}
+			return nil, "", fmt.Errorf("resource '%s' (%s) reached the failed status '%s'", r.openAPIResource.getResourceName(), resourceLocalData.Id(), newStatus)
+		}
+		if response == nil || !response.isPollPendingStatus(newStatus) {
+			return remoteData, newStatus, nil
+		}
+		if statusMessage == "" {
+			log.Printf("[INFO] resource '%s' (%s) is still being processed - status: %s", r.openAPIResource.getResourceName(), resourceLocalData.Id(), newStatus)
+			return remoteData, newStatus, nil
+		}
+		log.Printf("[INFO] resource '%s' (%s) is still being processed - status: %s, message: %s", r.openAPIResource.getResourceName(), resourceLocalData.Id(), newStatus, statusMessage)
+		if statusMessage != lastStatusMessage {
+			contextWarnings(ctx).add("resource '%s' (%s) is still being processed - status: %s, message: %s", r.openAPIResource.getResourceName(), resourceLocalData.Id(), newStatus, statusMessage)
+			lastStatusMessage = statusMessage
+		}
 		return remoteData, newStatus, nil
 	}
 }
 
-func (r resourceFactory) checkImmutableFields(updatedResourceLocalData *schema.ResourceData, openAPIClient ClientOpenAPI, parentIDs ...string) error {
-	remoteData, err := r.readRemote(updatedResourceLocalData.Id(), openAPIClient, parentIDs...)
+func (r resourceFactory) checkImmutableFields(ctx context.Context, updatedResourceLocalData *schema.ResourceData, openAPIClient ClientOpenAPI, parentIDs ...string) error {
+	instanceID, err := getResourceInstanceID(r.openAPIResource, updatedResourceLocalData)
+	if err != nil {
+		return err
+	}
+	remoteData, err := r.readRemote(ctx, instanceID, openAPIClient, parentIDs...)
 	if err != nil {
 		return err
 	}
@@ -373,12 +1421,22 @@ func (r resourceFactory) checkImmutableFields(updatedResourceLocalData *schema.R
 			if updateError != nil {
 				return updateError
 			}
-			return fmt.Errorf("validation for immutable properties failed: %s. Update operation was aborted; no updates were performed", err)
+			return messages.error(MessageImmutablePropertyEdit, messageArgs{"error": err})
 		}
 	}
 	return nil
 }
 
+// sortSetElements returns a copy of the given payload set elements sorted by their hash code
+func sortSetElements(property *specSchemaDefinitionProperty, elements []interface{}) []interface{} {
+	hashCode := func(element interface{}) int {
+		return property.setElementHashCode(element, func(p *specSchemaDefinitionProperty) string { return p.Name })
+	}
+	sorted := append([]interface{}{}, elements...)
+	sort.SliceStable(sorted, func(i, j int) bool { return hashCode(sorted[i]) < hashCode(sorted[j]) })
+	return sorted
+}
+
 func (r resourceFactory) validateImmutableProperty(property *specSchemaDefinitionProperty, remoteData interface{}, localData interface{}, checkObjectPropertiesUpdates bool) error {
 	if property.ReadOnly || property.IsParentProperty {
 		return nil
@@ -391,10 +1449,20 @@ func (r resourceFactory) validateImmutableProperty(property *specSchemaDefinitio
 			if len(localList) != len(remoteList) {
 				return fmt.Errorf("user attempted to update an immutable list property ('%s') size: [user input list size: %d; actual list size: %d]", property.Name, len(localList), len(remoteList))
 			}
+			// The order of the set elements is not relevant, both lists are sorted so the same elements are compared
+			if property.isSetProperty() {
+				localList = sortSetElements(property, localList)
+				remoteList = sortSetElements(property, remoteList)
+			}
 			if isListOfPrimitives, _ := property.isTerraformListOfSimpleValues(); isListOfPrimitives {
 
+				itemProperty := &specSchemaDefinitionProperty{Name: property.Name, Type: property.ArrayItemsType}
 				for idx, elem := range localList {
-					if elem != remoteList[idx] {
+					remoteElem := remoteList[idx]
+					if number, isNumber := remoteElem.(json.Number); isNumber {
+						remoteElem, _ = convertPayloadNumberToLocalStateDataValue(itemProperty, number, false)
+					}
+					if elem != remoteElem {
 						return fmt.Errorf("user attempted to update an immutable list property ('%s') element: [user input: %+v; actual: %+v]", property.Name, localList, remoteList)
 					}
 				}
@@ -423,7 +1491,15 @@ func (r resourceFactory) validateImmutableProperty(property *specSchemaDefinitio
 		}
 	default:
 		if property.Immutable || checkObjectPropertiesUpdates { // checkObjectPropertiesUpdates covers the recursive call from objects that are immutable which also make all its properties immutable
-			switch remoteData.(type) {
+			switch remoteNumber := remoteData.(type) {
+			case json.Number: // the API responses are decoded keeping the numbers as json.Number so they are not rounded
+				remoteValue, err := convertPayloadNumberToLocalStateDataValue(property, remoteNumber, false)
+				if err != nil {
+					return err
+				}
+				if localData != remoteValue {
+					return fmt.Errorf("user attempted to update an immutable property ('%s'): [user input: %v; actual: %s]", property.Name, localData, remoteNumber)
+				}
 			case float64: // this is due to the json marshalling always mapping ints to float64d
 				if property.Type == typeFloat {
 					if localData != remoteData {
@@ -472,10 +1548,74 @@ func (r resourceFactory) createPayloadFromLocalStateData(resourceLocalData *sche
 			log.Printf("[DEBUG] [resource='%s'] property payload [propertyName: %s; propertyValue: %+v]", r.openAPIResource.getResourceName(), propertyName, input[propertyName])
 		}
 	}
+	r.copyPayloadValues(input, resourceSchema)
 	log.Printf("[DEBUG] [resource='%s'] buildPayloadFromLocalStateDataForPostOperation: %s", r.openAPIResource.getResourceName(), sPrettyPrint(input))
 	return input
 }
 
+// createJSONPatchFromLocalStateData returns the JSON Patch (RFC 6902) operations describing the changes between the payload
+// built from the values stored in the state before the update and the payload built from the desired state, which is
+// what createPayloadFromLocalStateData returns. Properties with zero values in the state are considered not present in
+// the remote resource, hence these are added rather than replaced (which per RFC 6902 also replaces existing values)
+func (r resourceFactory) createJSONPatchFromLocalStateData(resourceLocalData *schema.ResourceData) []jsonPatchOperation {
+	oldPayload := map[string]interface{}{}
+	resourceSchema, _ := r.openAPIResource.getResourceSchema()
+	for _, property := range resourceSchema.Properties {
+		if property.isReadOnly() || property.IsParentProperty {
+			continue
+		}
+		oldValue, _ := resourceLocalData.GetChange(property.getTerraformCompliantPropertyName())
+		if isZeroStateValue(oldValue) {
+			continue
+		}
+		if err := r.populatePayload(oldPayload, property, oldValue); err != nil {
+			log.Printf("[ERROR] [resource='%s'] error when creating the property prior state payload for property '%s': %s", r.openAPIResource.getResourceName(), property.Name, err)
+		}
+	}
+	r.copyPayloadValues(oldPayload, resourceSchema)
+	patchOperations := getJSONPatchOperations(oldPayload, r.createPayloadFromLocalStateData(resourceLocalData))
+	log.Printf("[DEBUG] [resource='%s'] JSON patch: %s", r.openAPIResource.getResourceName(), sPrettyPrint(patchOperations))
+	return patchOperations
+}
+
+// isZeroStateValue checks whether the given state value is the zero value of its type, including empty lists, sets and maps
+func isZeroStateValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	if set, ok := value.(*schema.Set); ok {
+		return set.Len() == 0
+	}
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	default:
+		return reflect.DeepEqual(value, reflect.Zero(v.Type()).Interface())
+	}
+}
+
+// copyPayloadValues mirrors the values of the properties configured with the x-terraform-field-copy-to extension into the
+// payload fields listed in the extension. Fields that are already present in the payload (e.g. the user populated them
+// explicitly) are not overridden.
+func (r resourceFactory) copyPayloadValues(input map[string]interface{}, resourceSchema *specSchemaDefinition) {
+	if resourceSchema == nil {
+		return
+	}
+	for _, property := range resourceSchema.Properties {
+		value, exists := input[property.Name]
+		if !exists {
+			continue
+		}
+		for _, fieldName := range property.CopyTo {
+			if _, alreadyPopulated := input[fieldName]; alreadyPopulated {
+				log.Printf("[DEBUG] [resource='%s'] payload field '%s' already populated, skipping copy from property '%s'", r.openAPIResource.getResourceName(), fieldName, property.Name)
+				continue
+			}
+			input[fieldName] = value
+		}
+	}
+}
+
 func (r resourceFactory) populatePayload(input map[string]interface{}, property *specSchemaDefinitionProperty, dataValue interface{}) error {
 	if property.isReadOnly() {
 		return nil
@@ -483,6 +1623,10 @@ func (r resourceFactory) populatePayload(input map[string]interface{}, property
 	if dataValue == nil {
 		return fmt.Errorf("property '%s' has a nil state dataValue", property.Name)
 	}
+	// Set properties hold their elements in a schema.Set, the payload is built from the list of elements
+	if set, ok := dataValue.(*schema.Set); ok {
+		dataValue = set.List()
+	}
 	dataValueKind := reflect.TypeOf(dataValue).Kind()
 	switch dataValueKind {
 	case reflect.Map:
@@ -568,6 +1712,25 @@ func (r resourceFactory) populatePayload(input map[string]interface{}, property
 	return nil
 }
 
+// getPollStatusValueFromPayload returns the status value from the payload following the status path of the given
+// response, falling back to the status property of the resource schema if the response does not define one
+func (r resourceFactory) getPollStatusValueFromPayload(payload map[string]interface{}, response *specResponse) (string, error) {
+	if response == nil || response.pollStatusPath == "" {
+		return r.getStatusValueFromPayload(payload)
+	}
+	value, err := getPayloadValue(response.pollStatusPath, payload)
+	if err != nil {
+		return "", fmt.Errorf("status %s", err)
+	}
+	switch status := value.(type) {
+	case string:
+		return status, nil
+	case bool, float64, json.Number:
+		return fmt.Sprintf("%v", status), nil
+	}
+	return "", fmt.Errorf("status path '%s' value '%v' does not have a supported type [string/number/bool]", response.pollStatusPath, value)
+}
+
 func (r resourceFactory) getStatusValueFromPayload(payload map[string]interface{}) (string, error) {
 	resourceSchema, err := r.openAPIResource.getResourceSchema()
 	if err != nil {
@@ -583,11 +1746,11 @@ func (r resourceFactory) getStatusValueFromPayload(payload map[string]interface{
 		if !statusExistsInPayload {
 			return "", fmt.Errorf("payload does not match resouce schema, could not find the status field: %s", statuses)
 		}
-		switch reflect.TypeOf(propertyValue).Kind() {
-		case reflect.Map:
-			property = propertyValue.(map[string]interface{})
-		case reflect.String:
-			return propertyValue.(string), nil
+		switch value := propertyValue.(type) {
+		case map[string]interface{}:
+			property = value
+		case string:
+			return value, nil
 		default:
 			return "", fmt.Errorf("status property value '%s' does not have a supported type [string/map]", statuses)
 		}
@@ -595,12 +1758,46 @@ func (r resourceFactory) getStatusValueFromPayload(payload map[string]interface{
 	return "", fmt.Errorf("could not find status value [%s] in the payload provided", statuses)
 }
 
-// getResourceDataOK returns the data for the given schemaDefinitionPropertyName using the terraform compliant property name
+// getStatusMessageFromPayload returns the value of the status message property (if the resource schema has one configured)
+// from the payload provided. An empty string is returned if the message can not be found
+func (r resourceFactory) getStatusMessageFromPayload(payload map[string]interface{}) string {
+	resourceSchema, err := r.openAPIResource.getResourceSchema()
+	if err != nil {
+		return ""
+	}
+	statusMessageProperty := resourceSchema.getStatusMessageIdentifier()
+	if statusMessageProperty == "" {
+		return ""
+	}
+	if statusMessage, ok := payload[statusMessageProperty].(string); ok {
+		return statusMessage
+	}
+	return ""
+}
+
+// getResourceDataOKExists returns the data for the given schemaDefinitionPropertyName using the terraform compliant property
+// name (or any of its aliases), and whether the value has been set
 func (r resourceFactory) getResourceDataOKExists(schemaDefinitionPropertyName string, resourceLocalData *schema.ResourceData) (interface{}, bool) {
 	resourceSchema, _ := r.openAPIResource.getResourceSchema()
 	schemaDefinitionProperty, err := resourceSchema.getProperty(schemaDefinitionPropertyName)
 	if err != nil {
 		return nil, false
 	}
-	return resourceLocalData.GetOkExists(schemaDefinitionProperty.getTerraformCompliantPropertyName())
+	propertyName := schemaDefinitionProperty.getTerraformCompliantPropertyName()
+	// When the property has aliases the value may be configured under any of the names, the one that changed takes
+	// preference since the rest hold the value previously stored in the state
+	for _, alias := range schemaDefinitionProperty.Aliases {
+		if resourceLocalData.HasChange(alias) && !resourceLocalData.HasChange(propertyName) {
+			return resourceLocalData.GetOkExists(alias)
+		}
+	}
+	if value, exists := resourceLocalData.GetOkExists(propertyName); exists || len(schemaDefinitionProperty.Aliases) == 0 {
+		return value, exists
+	}
+	for _, alias := range schemaDefinitionProperty.Aliases {
+		if value, exists := resourceLocalData.GetOkExists(alias); exists {
+			return value, exists
+		}
+	}
+	return nil, false
 }
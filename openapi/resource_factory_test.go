@@ -1,12 +1,18 @@
 package openapi
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -14,7 +20,9 @@ import (
 	"github.com/go-openapi/spec"
 
 	"encoding/json"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -63,20 +71,20 @@ func TestCreateTerraformResource(t *testing.T) {
 				So(schemaResource.Schema, ShouldNotBeEmpty)
 			})
 			Convey("And the create function is invokable and returns nil error", func() {
-				err := schemaResource.Create(resourceData, client)
-				So(err, ShouldBeNil)
+				diags := schemaResource.CreateContext(context.Background(), resourceData, client)
+				So(diags, ShouldBeEmpty)
 			})
 			Convey("And the read function is invokable and returns nil error", func() {
-				err := schemaResource.Read(resourceData, client)
-				So(err, ShouldBeNil)
+				diags := schemaResource.ReadContext(context.Background(), resourceData, client)
+				So(diags, ShouldBeEmpty)
 			})
 			Convey("And the update function is invokable and returns nil error", func() {
-				err := schemaResource.Update(resourceData, client)
-				So(err, ShouldBeNil)
+				diags := schemaResource.UpdateContext(context.Background(), resourceData, client)
+				So(diags, ShouldBeEmpty)
 			})
 			Convey("And the delete function is invokable and returns nil error", func() {
-				err := schemaResource.Delete(resourceData, client)
-				So(err, ShouldBeNil)
+				diags := schemaResource.DeleteContext(context.Background(), resourceData, client)
+				So(diags, ShouldBeEmpty)
 			})
 		})
 	})
@@ -100,6 +108,77 @@ func TestCreateTerraformResourceSchema(t *testing.T) {
 	})
 }
 
+func TestCreateTerraformResourceSchema_RequiredHeaders(t *testing.T) {
+	Convey("Given a resource factory with operations that have required headers", t, func() {
+		requiredHeader := SpecHeaderParam{Name: "X-Project-ID"}
+		operation := &specResourceOperation{
+			HeaderParameters:         SpecHeaderParameters{requiredHeader},
+			RequiredHeaderParameters: SpecHeaderParameters{requiredHeader},
+		}
+		r := newResourceFactory(newSpecStubResourceWithOperations("resourceName", "/v1/resource", false, newTestSchema(idProperty, stringProperty).getSchemaDefinition(), operation, operation, operation, operation))
+		Convey("When createTerraformResourceSchema is called", func() {
+			s, err := r.createTerraformResourceSchema()
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the schema returned should contain an optional attribute for the required header", func() {
+				So(s, ShouldContainKey, "x_project_id")
+				So(s["x_project_id"].Type, ShouldEqual, schema.TypeString)
+				So(s["x_project_id"].Optional, ShouldBeTrue)
+			})
+		})
+	})
+	Convey("Given a resource factory with operations that have a required header that collides with a resource property", t, func() {
+		requiredHeader := SpecHeaderParam{Name: stringProperty.Name}
+		operation := &specResourceOperation{
+			HeaderParameters:         SpecHeaderParameters{requiredHeader},
+			RequiredHeaderParameters: SpecHeaderParameters{requiredHeader},
+		}
+		r := newResourceFactory(newSpecStubResourceWithOperations("resourceName", "/v1/resource", false, newTestSchema(idProperty, stringProperty).getSchemaDefinition(), operation, operation, operation, operation))
+		Convey("When getRequiredHeaderParameters is called", func() {
+			requiredHeaderParameters := r.getRequiredHeaderParameters()
+			Convey("Then the colliding header should not be returned", func() {
+				So(requiredHeaderParameters, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestGetInstanceClient(t *testing.T) {
+	Convey("Given a resource factory with operations that have required headers and a provider client", t, func() {
+		requiredHeader := SpecHeaderParam{Name: "X-Project-ID"}
+		operation := &specResourceOperation{
+			HeaderParameters:         SpecHeaderParameters{requiredHeader},
+			RequiredHeaderParameters: SpecHeaderParameters{requiredHeader},
+		}
+		r := newResourceFactory(newSpecStubResourceWithOperations("resourceName", "/v1/resource", false, newTestSchema(idProperty, stringProperty).getSchemaDefinition(), operation, operation, operation, operation))
+		resourceSchema, err := r.createTerraformResourceSchema()
+		So(err, ShouldBeNil)
+		providerClient := &ProviderClient{
+			providerConfiguration: providerConfiguration{
+				Headers: map[string]string{"x_project_id": "providerProject"},
+			},
+		}
+		Convey("When getInstanceClient is called with resource data containing a value for the required header", func() {
+			data := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{"x_project_id": "instanceProject"})
+			client := r.getInstanceClient(data, providerClient)
+			Convey("Then the client returned should use the resource instance header value", func() {
+				So(client.(*ProviderClient).providerConfiguration.getHeaderValueFor(requiredHeader), ShouldEqual, "instanceProject")
+			})
+			Convey("And the provider client header configuration should remain untouched", func() {
+				So(providerClient.providerConfiguration.getHeaderValueFor(requiredHeader), ShouldEqual, "providerProject")
+			})
+		})
+		Convey("When getInstanceClient is called with resource data that does not contain a value for the required header", func() {
+			data := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{})
+			client := r.getInstanceClient(data, providerClient)
+			Convey("Then the client returned should be the provider client", func() {
+				So(client, ShouldEqual, providerClient)
+			})
+		})
+	})
+}
+
 func TestCreate(t *testing.T) {
 	Convey("Given a resource factory", t, func() {
 		r, resourceData := testCreateResourceFactory(t, idProperty, stringProperty)
@@ -110,7 +189,7 @@ func TestCreate(t *testing.T) {
 					stringProperty.Name: "someExtraValueThatProvesResponseDataIsPersisted",
 				},
 			}
-			err := r.create(resourceData, client)
+			err := r.createResource(context.Background(), resourceData, client)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -119,6 +198,23 @@ func TestCreate(t *testing.T) {
 				So(resourceData.Get(stringProperty.Name), ShouldEqual, client.responsePayload[stringProperty.Name])
 			})
 		})
+		Convey("When create is called with a retry budget configured with an apply deadline that has not been started yet", func() {
+			r.retryBudget = newRetryBudget(0, time.Hour)
+			deadlineBeforeCreate := r.retryBudget.deadline
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					idProperty.Name: "someID",
+				},
+			}
+			err := r.createResource(context.Background(), resourceData, client)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the apply deadline clock should be started by the create", func() {
+				So(deadlineBeforeCreate.IsZero(), ShouldBeTrue)
+				So(r.retryBudget.deadline.After(time.Now()), ShouldBeTrue)
+			})
+		})
 		Convey("When create is called with resource data and a client configured to return an error when POST is called", func() {
 			createError := fmt.Errorf("some error when deleting")
 			client := &clientOpenAPIStub{
@@ -127,7 +223,7 @@ func TestCreate(t *testing.T) {
 				},
 				error: createError,
 			}
-			err := r.create(resourceData, client)
+			err := r.createResource(context.Background(), resourceData, client)
 			Convey("Then the error returned should not be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -141,7 +237,7 @@ func TestCreate(t *testing.T) {
 				responsePayload: map[string]interface{}{},
 				returnHTTPCode:  http.StatusInternalServerError,
 			}
-			err := r.create(resourceData, client)
+			err := r.createResource(context.Background(), resourceData, client)
 			Convey("Then the error returned should NOT be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -154,7 +250,7 @@ func TestCreate(t *testing.T) {
 			client := &clientOpenAPIStub{
 				responsePayload: map[string]interface{}{},
 			}
-			err := r.create(resourceData, client)
+			err := r.createResource(context.Background(), resourceData, client)
 			Convey("Then the error returned should NOT be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -168,7 +264,7 @@ func TestCreate(t *testing.T) {
 		r := resourceFactory{}
 		Convey("When create is called with empty data and a empty client", func() {
 			client := &clientOpenAPIStub{}
-			err := r.create(nil, client)
+			err := r.createResource(context.Background(), nil, client)
 			Convey("Then the error should not be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -192,7 +288,7 @@ func TestCreate(t *testing.T) {
 					stringProperty.Name: "someExtraValueThatProvesResponseDataIsPersisted",
 				},
 			}
-			err := r.create(resourceData, client)
+			err := r.createResource(context.Background(), resourceData, client)
 			Convey("Then the error returned should be the expected one", func() {
 				So(err.Error(), ShouldEqual, "polling mechanism failed after POST /v1/resource call with response status code (202): error waiting for resource to reach a completion status ([]) [valid pending statuses ([])]: error on retrieving resource 'resourceName' (someID) when waiting: [resource='resourceName'] HTTP Response Status Code 202 not matching expected one [200] ()")
 			})
@@ -209,7 +305,7 @@ func TestRead(t *testing.T) {
 					stringProperty.Name: "someOtherStringValue",
 				},
 			}
-			err := r.read(resourceData, client)
+			err := r.readResource(context.Background(), resourceData, client)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -248,7 +344,7 @@ func TestRead(t *testing.T) {
 					someOtherProperty.Name: "someOtherStringValue",
 				},
 			}
-			err := r.read(resourceData, client)
+			err := r.readResource(context.Background(), resourceData, client)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -262,7 +358,7 @@ func TestRead(t *testing.T) {
 		r := resourceFactory{}
 		Convey("When create is called with empty data and a empty client", func() {
 			client := &clientOpenAPIStub{}
-			err := r.read(nil, client)
+			err := r.readResource(context.Background(), nil, client)
 			Convey("Then the error should not be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -270,6 +366,280 @@ func TestRead(t *testing.T) {
 	})
 }
 
+func TestBatchReadRemote(t *testing.T) {
+	listPayload := []map[string]interface{}{
+		{idProperty.Name: "otherID", stringProperty.Name: "otherValue"},
+		{idProperty.Name: "id", stringProperty.Name: "remoteValue"},
+	}
+	testCases := []struct {
+		name             string
+		listOperation    *specResourceOperation
+		client           ClientOpenAPI
+		expectedResponse map[string]interface{}
+	}{
+		{
+			name:          "resource without batch read enabled",
+			listOperation: &specResourceOperation{},
+			client:        &clientOpenAPIStub{responseListPayload: listPayload},
+		},
+		{
+			name:   "resource without collection GET operation",
+			client: &clientOpenAPIStub{responseListPayload: listPayload},
+		},
+		{
+			name:             "resource with batch read enabled and the instance is in the collection response",
+			listOperation:    &specResourceOperation{BatchRead: true},
+			client:           &clientOpenAPIStub{responseListPayload: listPayload},
+			expectedResponse: map[string]interface{}{idProperty.Name: "id", stringProperty.Name: "remoteValue"},
+		},
+		{
+			name:          "resource with batch read enabled and the instance is not in the collection response",
+			listOperation: &specResourceOperation{BatchRead: true},
+			client:        &clientOpenAPIStub{responseListPayload: listPayload[:1]},
+		},
+		{
+			name:          "resource with batch read enabled and the collection GET fails",
+			listOperation: &specResourceOperation{BatchRead: true},
+			client:        &clientOpenAPIStub{responseListPayload: listPayload, returnHTTPCode: http.StatusInternalServerError},
+		},
+		{
+			name:          "resource with batch read enabled and the collection response is a summary of the resource",
+			listOperation: &specResourceOperation{BatchRead: true, responses: specResponses{http.StatusOK: &specResponse{isSummary: true}}},
+			client:        &clientOpenAPIStub{responseListPayload: listPayload},
+		},
+		{
+			name:          "resource with batch read enabled and a provider client with the response cache disabled",
+			listOperation: &specResourceOperation{BatchRead: true},
+			client:        &ProviderClient{},
+		},
+	}
+	for _, tc := range testCases {
+		testSchema := newTestSchema(idProperty, stringProperty)
+		specResource := newSpecStubResourceWithOperations("resourceName", "/v1/resource", false, testSchema.getSchemaDefinition(), nil, nil, &specResourceOperation{}, nil)
+		specResource.resourceListOperation = tc.listOperation
+		r := newResourceFactory(specResource)
+		resourceData := testSchema.getResourceData(t)
+		resourceData.SetId("id")
+		remoteData := r.batchReadRemote(context.Background(), resourceData, tc.client)
+		assert.Equal(t, tc.expectedResponse, remoteData, tc.name)
+	}
+}
+
+func TestListItemMatcher_CompositeID(t *testing.T) {
+	namespaceProperty := newStringSchemaDefinitionPropertyWithDefaults("namespace", "", true, false, nil)
+	nameProperty := newStringSchemaDefinitionPropertyWithDefaults("name", "", true, false, nil)
+	testSchema := newTestSchema(namespaceProperty, nameProperty, stringProperty)
+	listPayload := []map[string]interface{}{
+		{"namespace": "ns1", "name": "thing1", stringProperty.Name: "otherValue"},
+		{"namespace": "ns2", "name": "thing1", stringProperty.Name: "remoteValue"},
+	}
+	specResource := newSpecStubResourceWithOperations("things_v1", "/v1/ns/{namespace}/things", false, testSchema.getSchemaDefinition(), nil, nil, &specResourceOperation{}, &specResourceOperation{ConfirmDeleteViaList: true})
+	specResource.compositeID = []string{"namespace", "name"}
+	specResource.resourceListOperation = &specResourceOperation{BatchRead: true}
+	r := newResourceFactory(specResource)
+	r.defaultPollDelay = 0
+	r.defaultPollInterval = time.Millisecond
+	r.defaultPollMinTimeout = time.Millisecond
+
+	t.Run("batch read matches the instance on all the composite id properties", func(t *testing.T) {
+		resourceData := testSchema.getResourceData(t)
+		resourceData.SetId("ns2/thing1")
+		remoteData := r.batchReadRemote(context.Background(), resourceData, &clientOpenAPIStub{responseListPayload: listPayload})
+		assert.Equal(t, listPayload[1], remoteData)
+	})
+
+	t.Run("batch read does not match instances sharing only the last composite id property value", func(t *testing.T) {
+		resourceData := testSchema.getResourceData(t)
+		resourceData.SetId("ns3/thing1")
+		assert.Nil(t, r.batchReadRemote(context.Background(), resourceData, &clientOpenAPIStub{responseListPayload: listPayload}))
+	})
+
+	t.Run("delete confirm via list only waits for the instance matching all the composite id properties", func(t *testing.T) {
+		resourceData := testSchema.getResourceData(t)
+		resourceData.SetId("ns3/thing1")
+		client := &clientOpenAPIStub{responseListPayload: listPayload}
+		assert.NoError(t, r.deleteResource(context.Background(), resourceData, client))
+		assert.Equal(t, "thing1", client.idReceived)
+	})
+
+	t.Run("delete confirm via list waits while the instance is listed", func(t *testing.T) {
+		resourceData := testSchema.getResourceData(t)
+		resourceData.SetId("ns2/thing1")
+		r.retryBudget = newRetryBudget(50*time.Millisecond, 0)
+		defer func() { r.retryBudget = nil }()
+		err := r.deleteResource(context.Background(), resourceData, &clientOpenAPIStub{responseListPayload: listPayload})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "timeout while waiting for state to become 'destroyed' (last state: 'deleting'")
+	})
+}
+
+func TestDeleteConfirmViaList(t *testing.T) {
+	testCases := []struct {
+		name          string
+		listOperation *specResourceOperation
+		client        *clientOpenAPIStub
+		retryBudget   *retryBudget
+		expectedError string
+	}{
+		{
+			name:          "instance no longer listed in the collection response",
+			listOperation: &specResourceOperation{},
+			client:        &clientOpenAPIStub{responseListPayload: []map[string]interface{}{{idProperty.Name: "otherID"}}},
+		},
+		{
+			name:          "instance still listed in the collection response",
+			listOperation: &specResourceOperation{},
+			client:        &clientOpenAPIStub{responseListPayload: []map[string]interface{}{{idProperty.Name: "id"}}},
+			retryBudget:   newRetryBudget(50*time.Millisecond, 0),
+			expectedError: "[resource='resourceName'] failed to confirm DELETE /v1/resource/id completion: timeout while waiting for state to become 'destroyed' (last state: 'deleting'",
+		},
+		{
+			name:          "collection GET fails",
+			listOperation: &specResourceOperation{},
+			client: &clientOpenAPIStub{funcList: func() (*http.Response, error) {
+				return nil, errors.New("some error")
+			}},
+			expectedError: "[resource='resourceName'] failed to confirm DELETE /v1/resource/id completion: some error",
+		},
+		{
+			name:          "resource without collection GET operation",
+			client:        &clientOpenAPIStub{},
+			expectedError: "[resource='resourceName'] failed to confirm DELETE /v1/resource/id completion: the resource does not support the collection GET operation needed to confirm the deletion (x-terraform-delete-confirm-via-list extension)",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testSchema := newTestSchema(idProperty)
+			resourceData := testSchema.getResourceData(t)
+			resourceData.SetId("id")
+			specResource := newSpecStubResourceWithOperations("resourceName", "/v1/resource", false, testSchema.getSchemaDefinition(), nil, nil, nil, &specResourceOperation{ConfirmDeleteViaList: true})
+			specResource.resourceListOperation = tc.listOperation
+			r := newResourceFactory(specResource)
+			r.defaultPollDelay = 0
+			r.defaultPollInterval = time.Millisecond
+			r.defaultPollMinTimeout = time.Millisecond
+			r.retryBudget = tc.retryBudget
+			err := r.deleteResource(context.Background(), resourceData, tc.client)
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "id", tc.client.idReceived)
+		})
+	}
+}
+
+func TestDeleteWaitForNotFound(t *testing.T) {
+	newGetFunc := func(statusCodes ...int) func() (*http.Response, error) {
+		calls := 0
+		return func() (*http.Response, error) {
+			statusCode := statusCodes[len(statusCodes)-1]
+			if calls < len(statusCodes) {
+				statusCode = statusCodes[calls]
+			}
+			calls++
+			return &http.Response{StatusCode: statusCode, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+		}
+	}
+	testCases := []struct {
+		name          string
+		funcGet       func() (*http.Response, error)
+		retryBudget   *retryBudget
+		expectedError string
+	}{
+		{
+			name:    "instance not found straight away",
+			funcGet: newGetFunc(http.StatusNotFound),
+		},
+		{
+			name:    "instance gone after being returned a few times",
+			funcGet: newGetFunc(http.StatusOK, http.StatusOK, http.StatusGone),
+		},
+		{
+			name:          "instance still returned",
+			funcGet:       newGetFunc(http.StatusOK),
+			retryBudget:   newRetryBudget(50*time.Millisecond, 0),
+			expectedError: "[resource='resourceName'] failed to confirm DELETE /v1/resource/id completion: timeout while waiting for state to become 'destroyed' (last state: 'deleting'",
+		},
+		{
+			name:          "instance GET returns an unexpected status code",
+			funcGet:       newGetFunc(http.StatusInternalServerError),
+			expectedError: "[resource='resourceName'] failed to confirm DELETE /v1/resource/id completion: [resource='resourceName'] HTTP Response Status Code 500 not matching expected one [200] ()",
+		},
+		{
+			name: "instance GET fails",
+			funcGet: func() (*http.Response, error) {
+				return nil, errors.New("some error")
+			},
+			expectedError: "[resource='resourceName'] failed to confirm DELETE /v1/resource/id completion: some error",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testSchema := newTestSchema(idProperty)
+			resourceData := testSchema.getResourceData(t)
+			resourceData.SetId("id")
+			specResource := newSpecStubResourceWithOperations("resourceName", "/v1/resource", false, testSchema.getSchemaDefinition(), nil, nil, nil, &specResourceOperation{WaitForNotFound: true})
+			r := newResourceFactory(specResource)
+			r.defaultPollDelay = 0
+			r.defaultPollInterval = time.Millisecond
+			r.defaultPollMinTimeout = time.Millisecond
+			r.retryBudget = tc.retryBudget
+			client := &clientOpenAPIStub{funcGet: tc.funcGet}
+			err := r.deleteResource(context.Background(), resourceData, client)
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "id", client.idReceived)
+		})
+	}
+}
+
+func TestIsWaitForNotFoundEnabled(t *testing.T) {
+	testCases := []struct {
+		name           string
+		operation      *specResourceOperation
+		client         interface{}
+		expectedResult bool
+	}{
+		{
+			name:           "delete operation documented to wait for the instance to be gone",
+			operation:      &specResourceOperation{WaitForNotFound: true},
+			client:         &clientOpenAPIStub{},
+			expectedResult: true,
+		},
+		{
+			name:           "provider configured to wait for the resource to be gone",
+			operation:      &specResourceOperation{},
+			client:         &ProviderClient{providerConfiguration: providerConfiguration{WaitForDeletion: map[string]bool{"resourceName": true}}},
+			expectedResult: true,
+		},
+		{
+			name:           "provider configured to wait for other resources to be gone",
+			operation:      &specResourceOperation{},
+			client:         &ProviderClient{providerConfiguration: providerConfiguration{WaitForDeletion: map[string]bool{"otherResourceName": true}}},
+			expectedResult: false,
+		},
+		{
+			name:           "wait for deletion not configured",
+			operation:      &specResourceOperation{},
+			client:         &clientOpenAPIStub{},
+			expectedResult: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newResourceFactory(&specStubResource{name: "resourceName"})
+			assert.Equal(t, tc.expectedResult, r.isWaitForNotFoundEnabled(tc.operation, tc.client))
+		})
+	}
+}
+
 func TestReadRemote(t *testing.T) {
 
 	Convey("Given a resource factory", t, func() {
@@ -281,7 +651,7 @@ func TestReadRemote(t *testing.T) {
 					stringProperty.Name: "someOtherStringValue",
 				},
 			}
-			response, err := r.readRemote("", client)
+			response, err := r.readRemote(context.Background(), "", client)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -300,7 +670,7 @@ func TestReadRemote(t *testing.T) {
 				responsePayload: map[string]interface{}{},
 				returnHTTPCode:  http.StatusInternalServerError,
 			}
-			_, err := r.readRemote("", client)
+			_, err := r.readRemote(context.Background(), "", client)
 			Convey("Then the error returned should NOT be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -318,7 +688,7 @@ func TestReadRemote(t *testing.T) {
 					stringProperty.Name: "someOtherStringValue",
 				},
 			}
-			response, err := r.readRemote(expectedID, client, expectedParentID)
+			response, err := r.readRemote(context.Background(), expectedID, client, expectedParentID)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -346,7 +716,7 @@ func TestUpdate(t *testing.T) {
 					immutableProperty.Name: immutableProperty.Default,
 				},
 			}
-			err := r.update(resourceData, client)
+			err := r.updateResource(context.Background(), resourceData, client)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -366,7 +736,7 @@ func TestUpdate(t *testing.T) {
 					immutableProperty.Name: "immutableOriginalValue",
 				},
 			}
-			err := r.update(resourceData, client)
+			err := r.updateResource(context.Background(), resourceData, client)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -387,7 +757,7 @@ func TestUpdate(t *testing.T) {
 				},
 				error: updateError,
 			}
-			err := r.update(resourceData, client)
+			err := r.updateResource(context.Background(), resourceData, client)
 			Convey("Then the error returned should not be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -412,9 +782,30 @@ func TestUpdate(t *testing.T) {
 					}, nil
 				},
 			}
-			err := r.update(resourceData, client)
+			err := r.updateResource(context.Background(), resourceData, client)
 			Convey("And the error returned should be the expected one", func() {
-				So(err.Error(), ShouldEqual, "[resource='resourceName'] UPDATE /v1/resource/id failed: [resource='resourceName'] HTTP Response Status Code 500 not matching expected one [200 202] ()")
+				So(err.Error(), ShouldEqual, "[resource='resourceName'] UPDATE /v1/resource/id failed: [resource='resourceName'] HTTP Response Status Code 500 not matching expected one [200 202 204] ()")
+			})
+		})
+		Convey("When update is called with resource data and a client that returns 204 No Content", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					idProperty.Name:     "id",
+					stringProperty.Name: "someValueComputedByTheAPI",
+				},
+				funcPut: func() (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusNoContent,
+						Body:       ioutil.NopCloser(strings.NewReader("")),
+					}, nil
+				},
+			}
+			err := r.updateResource(context.Background(), resourceData, client)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And resourceData should be populated with the values returned by the follow-up GET", func() {
+				So(resourceData.Get(stringProperty.Name), ShouldEqual, "someValueComputedByTheAPI")
 			})
 		})
 		Convey("When update is called with resource data and a client returns a non expected error", func() {
@@ -428,7 +819,7 @@ func TestUpdate(t *testing.T) {
 					return nil, fmt.Errorf(expectedError)
 				},
 			}
-			err := r.update(resourceData, client)
+			err := r.updateResource(context.Background(), resourceData, client)
 			Convey("And the error returned should be the expected one", func() {
 				So(err.Error(), ShouldEqual, expectedError)
 			})
@@ -440,7 +831,7 @@ func TestUpdate(t *testing.T) {
 		r := newResourceFactory(specResource)
 		Convey("When update is called with resource data and a client", func() {
 			client := &clientOpenAPIStub{}
-			err := r.update(nil, client)
+			err := r.updateResource(context.Background(), nil, client)
 			Convey("Then the expectedValue returned should be true", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -454,7 +845,7 @@ func TestUpdate(t *testing.T) {
 		r := resourceFactory{}
 		Convey("When create is called with empty data and a empty client", func() {
 			client := &clientOpenAPIStub{}
-			err := r.update(nil, client)
+			err := r.updateResource(context.Background(), nil, client)
 			Convey("Then the error should not be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -483,7 +874,7 @@ func TestUpdate(t *testing.T) {
 					stringProperty.Name: "someValue",
 				},
 			}
-			err := r.update(resourceData, client)
+			err := r.updateResource(context.Background(), resourceData, client)
 			Convey("Then the error returned should be the expected one", func() {
 				So(err.Error(), ShouldEqual, "polling mechanism failed after PUT /v1/resource call with response status code (202): error waiting for resource to reach a completion status ([]) [valid pending statuses ([])]: error occurred while retrieving status identifier value from payload for resource 'resourceName' (): could not find any status property. Please make sure the resource schema definition has either one property named 'status' or one property is marked with IsStatusIdentifier set to true")
 			})
@@ -491,6 +882,87 @@ func TestUpdate(t *testing.T) {
 	})
 }
 
+func TestCleanupOnFailure(t *testing.T) {
+	createErr := errors.New("polling mechanism failed")
+	abortOperation := &onFailureCleanupOperation{path: "/v1/resource/{id}/abort", method: httpPost, subPath: "/abort", operation: &specResourceOperation{}}
+	testCases := []struct {
+		name             string
+		onFailureCleanup *onFailureCleanupOperation
+		client           ClientOpenAPI
+		expectedError    string
+		expectedID       string
+		expectedCleanup  *onFailureCleanupOperation
+	}{
+		{
+			name:          "post operation not configured to cleanup on failure",
+			client:        &clientOpenAPIStub{responsePayload: map[string]interface{}{"id": "id"}},
+			expectedError: "polling mechanism failed",
+			expectedID:    "id",
+		},
+		{
+			name:             "post operation configured to cleanup on failure and the cleanup succeeds",
+			onFailureCleanup: abortOperation,
+			client:           &clientOpenAPIStub{responsePayload: map[string]interface{}{"id": "id"}},
+			expectedError:    "polling mechanism failed",
+			expectedID:       "",
+			expectedCleanup:  abortOperation,
+		},
+		{
+			name:             "post operation configured to cleanup on failure but the client can not call the cleanup operation",
+			onFailureCleanup: abortOperation,
+			client:           &clientOpenAPIWithoutCleanupOperations{&clientOpenAPIStub{responsePayload: map[string]interface{}{"id": "id"}}},
+			expectedError:    "polling mechanism failed",
+			expectedID:       "id",
+		},
+		{
+			name:             "post operation configured to cleanup on failure and the cleanup fails",
+			onFailureCleanup: abortOperation,
+			client:           &clientOpenAPIStub{responsePayload: map[string]interface{}{"id": "id"}, returnHTTPCode: http.StatusInternalServerError},
+			expectedError:    "polling mechanism failed; the cleanup of the partially created resource 'id' failed too and it may need to be cleaned up manually: [resource='resourceName'] HTTP Response Status Code 500 not matching expected one [204 200 202] ()",
+			expectedID:       "id",
+			expectedCleanup:  abortOperation,
+		},
+	}
+	for _, tc := range testCases {
+		testSchema := newTestSchema(idProperty)
+		resourceData := testSchema.getResourceData(t)
+		resourceData.SetId("id")
+		specResource := newSpecStubResourceWithOperations("resourceName", "/v1/resource", false, testSchema.getSchemaDefinition(), &specResourceOperation{OnFailureCleanup: tc.onFailureCleanup}, nil, &specResourceOperation{}, &specResourceOperation{})
+		r := newResourceFactory(specResource)
+		err := r.cleanupOnFailure(context.Background(), resourceData, tc.client, []string{}, createErr)
+		assert.EqualError(t, err, tc.expectedError, tc.name)
+		assert.Equal(t, tc.expectedID, resourceData.Id(), tc.name)
+		if stub, ok := tc.client.(*clientOpenAPIStub); ok {
+			assert.Equal(t, tc.expectedCleanup, stub.cleanupReceived, tc.name)
+		}
+	}
+}
+
+func TestCleanupOnFailure_CreateTimedOut(t *testing.T) {
+	var cleanupRequest string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cleanupRequest = fmt.Sprintf("%s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer api.Close()
+	testSchema := newTestSchema(idProperty)
+	resourceData := testSchema.getResourceData(t)
+	resourceData.SetId("id")
+	abortOperation := &onFailureCleanupOperation{path: "/v1/resource/{id}/abort", method: httpPost, subPath: "/abort", operation: &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}}}
+	specResource := newSpecStubResourceWithOperations("resourceName", "/v1/resource", false, testSchema.getSchemaDefinition(), &specResourceOperation{OnFailureCleanup: abortOperation}, nil, &specResourceOperation{}, nil)
+	r := newResourceFactory(specResource)
+	createContext, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-createContext.Done()
+	createClient := newCancellableProviderClient(api.URL, context.Background())
+
+	err := r.cleanupOnFailure(createContext, resourceData, createClient, []string{}, errOperationTimedOut)
+
+	assert.Equal(t, errOperationTimedOut, err)
+	assert.Equal(t, "POST /v1/resource/id/abort", cleanupRequest, "the partially created resource should be cleaned up even though the create context expired")
+	assert.Equal(t, "", resourceData.Id())
+}
+
 func TestDelete(t *testing.T) {
 	Convey("Given a resource factory", t, func() {
 		r, resourceData := testCreateResourceFactoryWithID(t, idProperty)
@@ -500,7 +972,7 @@ func TestDelete(t *testing.T) {
 					idProperty.Name: idProperty.Default,
 				},
 			}
-			err := r.delete(resourceData, client)
+			err := r.deleteResource(context.Background(), resourceData, client)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -516,7 +988,7 @@ func TestDelete(t *testing.T) {
 				},
 				error: deleteError,
 			}
-			err := r.delete(resourceData, client)
+			err := r.deleteResource(context.Background(), resourceData, client)
 			Convey("Then the error returned should not be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -530,7 +1002,7 @@ func TestDelete(t *testing.T) {
 				responsePayload: map[string]interface{}{},
 				returnHTTPCode:  http.StatusInternalServerError,
 			}
-			err := r.delete(resourceData, client)
+			err := r.deleteResource(context.Background(), resourceData, client)
 			Convey("Then the error returned should NOT be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -544,19 +1016,47 @@ func TestDelete(t *testing.T) {
 				responsePayload: map[string]interface{}{},
 				returnHTTPCode:  http.StatusNotFound,
 			}
-			err := r.delete(resourceData, client)
+			err := r.deleteResource(context.Background(), resourceData, client)
 			Convey("Then the error returned should NOT be nil", func() {
 				So(err, ShouldBeNil)
 			})
 		})
 	})
 
+	Convey("Given a resource factory protected by the prevent destroy policy", t, func() {
+		r, resourceData := testCreateResourceFactoryWithID(t, idProperty)
+		r.preventDestroy = true
+		Convey("When delete is called with resource data and a client", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					idProperty.Name: idProperty.Default,
+				},
+			}
+			err := r.deleteResource(context.Background(), resourceData, client)
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "[resource='resourceName'] resource is protected by the prevent_destroy policy defined in the service configuration and can not be destroyed; set the provider property 'override_prevent_destroy' to true to allow it")
+			})
+			Convey("And the remote resource should not have been deleted", func() {
+				So(client.responsePayload, ShouldContainKey, idProperty.Name)
+			})
+		})
+		Convey("When checkDestroyAllowed is called with a provider client configured to override the policy", func() {
+			client := &ProviderClient{
+				providerConfiguration: providerConfiguration{OverridePreventDestroy: true},
+			}
+			err := r.checkDestroyAllowed(client)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+
 	Convey("Given a resource factory with no delete operation configured", t, func() {
 		specResource := newSpecStubResource("resourceName", "/v1/resource", false, nil)
 		r := newResourceFactory(specResource)
 		Convey("When delete is called with resource data and a client", func() {
 			client := &clientOpenAPIStub{}
-			err := r.delete(nil, client)
+			err := r.deleteResource(context.Background(), nil, client)
 			Convey("Then the expectedValue returned should be true", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -570,7 +1070,7 @@ func TestDelete(t *testing.T) {
 		r := resourceFactory{}
 		Convey("When delete is called with empty data and a empty client", func() {
 			client := &clientOpenAPIStub{}
-			err := r.delete(nil, client)
+			err := r.deleteResource(context.Background(), nil, client)
 			Convey("Then the error should not be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -594,7 +1094,7 @@ func TestDelete(t *testing.T) {
 					stringProperty.Name: "someExtraValueThatProvesResponseDataIsPersisted",
 				},
 			}
-			err := r.delete(resourceData, client)
+			err := r.deleteResource(context.Background(), resourceData, client)
 			Convey("Then the error returned should be the expected one", func() {
 				So(err.Error(), ShouldEqual, "polling mechanism failed after DELETE /v1/resource call with response status code (202): error waiting for resource to reach a completion status ([destroyed]) [valid pending statuses ([])]: error on retrieving resource 'resourceName' () when waiting: [resource='resourceName'] HTTP Response Status Code 202 not matching expected one [200] ()")
 			})
@@ -617,7 +1117,7 @@ func TestImporter(t *testing.T) {
 				So(resourceImporter, ShouldNotBeNil)
 			})
 			Convey("And when the resourceImporter State method is invoked with data resource and the provider client", func() {
-				data, err := resourceImporter.State(resourceData, client)
+				data, err := resourceImporter.StateContext(context.Background(), resourceData, client)
 				Convey("Then the err returned should be nil", func() {
 					So(err, ShouldBeNil)
 				})
@@ -657,7 +1157,7 @@ func TestImporter(t *testing.T) {
 				So(resourceImporter, ShouldNotBeNil)
 			})
 			Convey("And when the resourceImporter State method is invoked with the provider client and resource data for one item", func() {
-				data, err := resourceImporter.State(resourceData, client)
+				data, err := resourceImporter.StateContext(context.Background(), resourceData, client)
 				Convey("Then the err returned should be nil", func() {
 					So(err, ShouldBeNil)
 				})
@@ -696,7 +1196,7 @@ func TestImporter(t *testing.T) {
 				So(resourceImporter, ShouldNotBeNil)
 			})
 			Convey("And when the resourceImporter State method is invoked with data resource and the provider client", func() {
-				_, err := resourceImporter.State(resourceData, client)
+				_, err := resourceImporter.StateContext(context.Background(), resourceData, client)
 				Convey("Then the err returned should be the expected one", func() {
 					So(err.Error(), ShouldEqual, "can not import a subresource without providing all the parent IDs (1) and the instance ID")
 				})
@@ -722,7 +1222,7 @@ func TestImporter(t *testing.T) {
 				So(resourceImporter, ShouldNotBeNil)
 			})
 			Convey("And when the resourceImporter State method is invoked with data resource and the provider client", func() {
-				_, err := resourceImporter.State(resourceData, client)
+				_, err := resourceImporter.StateContext(context.Background(), resourceData, client)
 				Convey("Then the err returned should be the expected one", func() {
 					So(err.Error(), ShouldEqual, "the number of parent IDs provided 3 is greater than the expected number of parent IDs 1")
 				})
@@ -747,7 +1247,7 @@ func TestImporter(t *testing.T) {
 				So(resourceImporter, ShouldNotBeNil)
 			})
 			Convey("And when the resourceImporter State method is invoked with data resource and the provider client", func() {
-				_, err := resourceImporter.State(resourceData, client)
+				_, err := resourceImporter.StateContext(context.Background(), resourceData, client)
 				Convey("Then the err returned should be the expected one", func() {
 					So(err.Error(), ShouldEqual, "can not import a subresource without all the parent ids, expected 2 and got 1 parent IDs")
 				})
@@ -772,7 +1272,7 @@ func TestImporter(t *testing.T) {
 				So(resourceImporter, ShouldNotBeNil)
 			})
 			Convey("And when the resourceImporter State method is invoked with data resource and the provider client", func() {
-				_, err := resourceImporter.State(resourceData, client)
+				_, err := resourceImporter.StateContext(context.Background(), resourceData, client)
 				Convey("Then the err returned should be the expected one", func() {
 					So(err.Error(), ShouldEqual, "could not find ID value in the state file for subresource parent property 'cdns_v1_id'")
 				})
@@ -807,7 +1307,7 @@ func TestHandlePollingIfConfigured(t *testing.T) {
 					},
 				},
 			}
-			err := r.handlePollingIfConfigured(&responsePayload, resourceData, client, operation, responseStatusCode, schema.TimeoutCreate)
+			err := r.handlePollingIfConfigured(context.Background(), &responsePayload, resourceData, client, operation, responseStatusCode, nil, schema.TimeoutCreate)
 			Convey("Then the err returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -835,7 +1335,7 @@ func TestHandlePollingIfConfigured(t *testing.T) {
 					},
 				},
 			}
-			err := r.handlePollingIfConfigured(nil, resourceData, client, operation, responseStatusCode, schema.TimeoutCreate)
+			err := r.handlePollingIfConfigured(context.Background(), nil, resourceData, client, operation, responseStatusCode, nil, schema.TimeoutCreate)
 			Convey("Then the err returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -852,7 +1352,7 @@ func TestHandlePollingIfConfigured(t *testing.T) {
 			operation := &specResourceOperation{
 				responses: map[int]*specResponse{},
 			}
-			err := r.handlePollingIfConfigured(nil, resourceData, client, operation, responseStatusCode, schema.TimeoutCreate)
+			err := r.handlePollingIfConfigured(context.Background(), nil, resourceData, client, operation, responseStatusCode, nil, schema.TimeoutCreate)
 			Convey("Then the err  should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -870,7 +1370,7 @@ func TestHandlePollingIfConfigured(t *testing.T) {
 					},
 				},
 			}
-			err := r.handlePollingIfConfigured(nil, resourceData, client, operation, responseStatusCode, schema.TimeoutCreate)
+			err := r.handlePollingIfConfigured(context.Background(), nil, resourceData, client, operation, responseStatusCode, nil, schema.TimeoutCreate)
 			Convey("Then the err returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -898,11 +1398,122 @@ func TestHandlePollingIfConfigured(t *testing.T) {
 				},
 				error: fmt.Errorf("some error"),
 			}
-			err := r.handlePollingIfConfigured(nil, resourceData, client, operation, expectedReturnCode, schema.TimeoutCreate)
+			err := r.handlePollingIfConfigured(context.Background(), nil, resourceData, client, operation, expectedReturnCode, nil, schema.TimeoutCreate)
 			Convey("Then the error returned should be the expected one", func() {
 				So(err.Error(), ShouldEqual, "error waiting for resource to reach a completion status ([destroyed]) [valid pending statuses ([pending])]: error on retrieving resource 'resourceName' (id) when waiting: some error")
 			})
 		})
+
+		Convey("When handlePollingIfConfigured is called with polling enabled but the resource factory retry budget is already exhausted", func() {
+			r.retryBudget = &retryBudget{budget: time.Minute, spent: time.Minute}
+			client := &clientOpenAPIStub{}
+			operation := &specResourceOperation{
+				responses: map[int]*specResponse{
+					http.StatusAccepted: {
+						isPollingEnabled:    true,
+						pollPendingStatuses: []string{"pending"},
+						pollTargetStatuses:  []string{"deployed"},
+					},
+				},
+			}
+			responsePayload := map[string]interface{}{}
+			err := r.handlePollingIfConfigured(context.Background(), &responsePayload, resourceData, client, operation, http.StatusAccepted, nil, schema.TimeoutCreate)
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "error waiting for resource to reach a completion status ([deployed]) [valid pending statuses ([pending])]: retry budget of 1m0s exhausted")
+			})
+		})
+
+		Convey("When handlePollingIfConfigured is called with polling enabled AND the response returned the header with the asynchronous operation URL", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					idProperty.Name:     idProperty.Default,
+					stringProperty.Name: "someValueReadAfterTheOperationCompleted",
+					statusProperty.Name: "deployed",
+				},
+				asyncOperationPayloads: []map[string]interface{}{
+					{"status": "succeeded"},
+				},
+			}
+			operation := &specResourceOperation{
+				responses: map[int]*specResponse{
+					http.StatusAccepted: {
+						isPollingEnabled:    true,
+						pollPendingStatuses: []string{"running"},
+						pollTargetStatuses:  []string{"succeeded"},
+						pollLocationHeader:  "Operation-Location",
+					},
+				},
+			}
+			responseHeader := http.Header{}
+			responseHeader.Set("Operation-Location", "/v1/operations/op1")
+			responsePayload := map[string]interface{}{}
+			err := r.handlePollingIfConfigured(context.Background(), &responsePayload, resourceData, client, operation, http.StatusAccepted, responseHeader, schema.TimeoutCreate)
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the asynchronous operation should have been polled", func() {
+				So(client.asyncOperationURLReceived, ShouldEqual, "/v1/operations/op1")
+			})
+			Convey("And the remote data should be the resource read once the operation completed", func() {
+				So(client.idReceived, ShouldEqual, idProperty.Default)
+				So(responsePayload[stringProperty.Name], ShouldEqual, "someValueReadAfterTheOperationCompleted")
+				So(responsePayload[statusProperty.Name], ShouldEqual, "deployed")
+			})
+		})
+
+		Convey("When handlePollingIfConfigured is called for a DELETE operation with polling enabled AND the response returned the header with the asynchronous operation URL", func() {
+			client := &clientOpenAPIStub{
+				asyncOperationPayloads: []map[string]interface{}{
+					{"status": "succeeded"},
+				},
+			}
+			operation := &specResourceOperation{
+				responses: map[int]*specResponse{
+					http.StatusAccepted: {
+						isPollingEnabled:    true,
+						pollPendingStatuses: []string{"running"},
+						pollTargetStatuses:  []string{"succeeded"},
+						pollLocationHeader:  "Location",
+					},
+				},
+			}
+			responseHeader := http.Header{}
+			responseHeader.Set("Location", "https://api.example.com/v1/operations/op1")
+			err := r.handlePollingIfConfigured(context.Background(), nil, resourceData, client, operation, http.StatusAccepted, responseHeader, schema.TimeoutDelete)
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the asynchronous operation should have been polled instead of the resource", func() {
+				So(client.asyncOperationURLReceived, ShouldEqual, "https://api.example.com/v1/operations/op1")
+				So(client.idReceived, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When handlePollingIfConfigured is called with polling enabled AND the asynchronous operation reaches a failed status", func() {
+			client := &clientOpenAPIStub{
+				asyncOperationPayloads: []map[string]interface{}{
+					{"status": "failed"},
+				},
+			}
+			operation := &specResourceOperation{
+				responses: map[int]*specResponse{
+					http.StatusAccepted: {
+						isPollingEnabled:    true,
+						pollPendingStatuses: []string{"running"},
+						pollTargetStatuses:  []string{"succeeded"},
+						pollFailedStatuses:  []string{"failed"},
+						pollLocationHeader:  "Location",
+					},
+				},
+			}
+			responseHeader := http.Header{}
+			responseHeader.Set("Location", "/v1/operations/op1")
+			responsePayload := map[string]interface{}{}
+			err := r.handlePollingIfConfigured(context.Background(), &responsePayload, resourceData, client, operation, http.StatusAccepted, responseHeader, schema.TimeoutCreate)
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "error waiting for resource to reach a completion status ([succeeded]) [valid pending statuses ([running])]: the asynchronous operation '/v1/operations/op1' of resource 'resourceName' (id) reached the failed status 'failed'")
+			})
+		})
 	})
 
 }
@@ -918,7 +1529,7 @@ func TestResourceStateRefreshFunc(t *testing.T) {
 					statusProperty.Name: statusProperty.Default,
 				},
 			}
-			stateRefreshFunc := r.resourceStateRefreshFunc(resourceData, client)
+			stateRefreshFunc := r.resourceStateRefreshFunc(context.Background(), resourceData, client, nil)
 			remoteData, newStatus, err := stateRefreshFunc()
 			Convey("Then the err returned should be nil", func() {
 				So(err, ShouldBeNil)
@@ -937,7 +1548,7 @@ func TestResourceStateRefreshFunc(t *testing.T) {
 			client := &clientOpenAPIStub{
 				returnHTTPCode: http.StatusNotFound,
 			}
-			stateRefreshFunc := r.resourceStateRefreshFunc(resourceData, client)
+			stateRefreshFunc := r.resourceStateRefreshFunc(context.Background(), resourceData, client, nil)
 			_, newStatus, err := stateRefreshFunc()
 			Convey("Then the err returned should be nil", func() {
 				So(err, ShouldBeNil)
@@ -952,7 +1563,7 @@ func TestResourceStateRefreshFunc(t *testing.T) {
 			client := &clientOpenAPIStub{
 				error: errors.New(expectedError),
 			}
-			stateRefreshFunc := r.resourceStateRefreshFunc(resourceData, client)
+			stateRefreshFunc := r.resourceStateRefreshFunc(context.Background(), resourceData, client, nil)
 			remoteData, newStatus, err := stateRefreshFunc()
 			Convey("Then the err returned should not be nil", func() {
 				So(err, ShouldNotBeNil)
@@ -978,7 +1589,7 @@ func TestResourceStateRefreshFunc(t *testing.T) {
 					stringProperty.Name: stringProperty.Default,
 				},
 			}
-			stateRefreshFunc := r.resourceStateRefreshFunc(resourceData, client)
+			stateRefreshFunc := r.resourceStateRefreshFunc(context.Background(), resourceData, client, nil)
 			remoteData, newStatus, err := stateRefreshFunc()
 			Convey("Then the err returned should not be nil", func() {
 				So(err, ShouldNotBeNil)
@@ -1004,7 +1615,7 @@ func TestResourceStateRefreshFunc(t *testing.T) {
 					stringProperty.Name: stringProperty.Default,
 				},
 			}
-			stateRefreshFunc := r.resourceStateRefreshFunc(resourceData, client)
+			stateRefreshFunc := r.resourceStateRefreshFunc(context.Background(), resourceData, client, nil)
 			remoteData, newStatus, err := stateRefreshFunc()
 			Convey("Then the err returned should not be nil", func() {
 				So(err, ShouldNotBeNil)
@@ -1461,7 +2072,7 @@ func TestCheckImmutableFields(t *testing.T) {
 
 	for _, tc := range testCases {
 		r, resourceData := testCreateResourceFactory(t, tc.inputProps...)
-		err := r.checkImmutableFields(resourceData, &tc.client)
+		err := r.checkImmutableFields(context.Background(), resourceData, &tc.client)
 		if tc.expectedError == nil {
 			assert.NoError(t, err, tc.name)
 		} else {
@@ -1513,6 +2124,22 @@ func TestCreatePayloadFromLocalStateData(t *testing.T) {
 				stringProperty.getTerraformCompliantPropertyName(): stringProperty.Default,
 			},
 		},
+		{
+			name: "properties configured with copy to fields have their value mirrored into those fields unless they are already populated",
+			inputProps: []*specSchemaDefinitionProperty{
+				func() *specSchemaDefinitionProperty {
+					nameProperty := newStringSchemaDefinitionPropertyWithDefaults("name", "", true, false, "someName")
+					nameProperty.CopyTo = []string{"display_name", "title"}
+					return nameProperty
+				}(),
+				newStringSchemaDefinitionPropertyWithDefaults("title", "", true, false, "someTitle"),
+			},
+			expectedPayload: map[string]interface{}{
+				"name":         "someName",
+				"display_name": "someName",
+				"title":        "someTitle",
+			},
+		},
 		{
 			// - Representation of resourceData configuration containing an object
 			// {
@@ -1881,6 +2508,45 @@ func TestGetPropertyPayload(t *testing.T) {
 		})
 	})
 
+	Convey("Given a resource factory initialized with a schema definition containing an array of objects property configured as a set", t, func() {
+		objectSchemaDefinition := &specSchemaDefinition{
+			Properties: specSchemaDefinitionProperties{
+				newIntSchemaDefinitionPropertyWithDefaults("origin_port", "", true, false, nil),
+				newStringSchemaDefinitionPropertyWithDefaults("protocol", "", true, false, nil),
+			},
+		}
+		arrayObjectDefault := []interface{}{
+			map[string]interface{}{
+				"origin_port": 80,
+				"protocol":    "http",
+			},
+			map[string]interface{}{
+				"origin_port": 80,
+				"protocol":    "HTTP",
+			},
+			map[string]interface{}{
+				"origin_port": 443,
+				"protocol":    "https",
+			},
+		}
+		setObjectProperty := newListSchemaDefinitionPropertyWithDefaults("set_object_property", "", true, false, false, arrayObjectDefault, typeObject, objectSchemaDefinition)
+		setObjectProperty.SetHashKeys = []string{"origin_port", "protocol"}
+		setObjectProperty.SetHashIgnoreCase = true
+		r, resourceData := testCreateResourceFactory(t, setObjectProperty)
+		Convey("When populatePayload is called with an empty map, the set property in the resource schema and it's state data value", func() {
+			payload := map[string]interface{}{}
+			dataValue, _ := resourceData.GetOkExists(setObjectProperty.getTerraformCompliantPropertyName())
+			err := r.populatePayload(payload, setObjectProperty, dataValue)
+			Convey("Then the error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the payload should contain the list of unique set elements, where elements that only differ in case are considered the same", func() {
+				So(payload, ShouldContainKey, setObjectProperty.Name)
+				So(payload[setObjectProperty.Name], ShouldHaveLength, 2)
+			})
+		})
+	})
+
 	Convey("Given a resource factory initialized with a schema definition containing a slice of strings property", t, func() {
 		// Use case - slice of srings (terraform configuration pseudo representation below):
 		// slice_property = ["some_value"]
@@ -2158,6 +2824,45 @@ func TestGetStatusValueFromPayload(t *testing.T) {
 
 }
 
+func TestGetStatusMessageFromPayload(t *testing.T) {
+	Convey("Given a resource factory with a schema definition that has a status message property", t, func() {
+		r := resourceFactory{
+			openAPIResource: newSpecStubResource("resourceName", "/v1/resource", false, &specSchemaDefinition{
+				Properties: specSchemaDefinitionProperties{
+					&specSchemaDefinitionProperty{
+						Name:     statusDefaultPropertyName,
+						Type:     typeString,
+						ReadOnly: true,
+					},
+					&specSchemaDefinitionProperty{
+						Name:            "status_details",
+						Type:            typeString,
+						ReadOnly:        true,
+						IsStatusMessage: true,
+					},
+				},
+			}),
+		}
+		Convey("When getStatusMessageFromPayload method is called with a payload containing the status message", func() {
+			statusMessage := r.getStatusMessageFromPayload(map[string]interface{}{
+				statusDefaultPropertyName: "provisioning",
+				"status_details":          "allocating resources (2/5)",
+			})
+			Convey("Then the value returned should be the status message", func() {
+				So(statusMessage, ShouldEqual, "allocating resources (2/5)")
+			})
+		})
+		Convey("When getStatusMessageFromPayload method is called with a payload that does not contain the status message", func() {
+			statusMessage := r.getStatusMessageFromPayload(map[string]interface{}{
+				statusDefaultPropertyName: "provisioning",
+			})
+			Convey("Then the value returned should be empty", func() {
+				So(statusMessage, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
 func TestGetResourceDataOKExists(t *testing.T) {
 	Convey("Given a resource factory initialized with a spec resource with some schema definition and resource data", t, func() {
 		r, resourceData := testCreateResourceFactory(t, stringProperty, stringWithPreferredNameProperty)
@@ -2202,6 +2907,24 @@ func TestGetResourceDataOKExists(t *testing.T) {
 			})
 		})
 	})
+
+	Convey("Given a resource factory initialized with a spec resource with a property with aliases and resource data where the value is configured using an alias", t, func() {
+		nameProperty := newStringSchemaDefinitionPropertyWithDefaults("name", "", true, false, nil)
+		nameProperty.Aliases = []string{"label"}
+		resourceSchema, err := (&specSchemaDefinition{Properties: specSchemaDefinitionProperties{nameProperty}}).createResourceSchema()
+		So(err, ShouldBeNil)
+		resourceData := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{"label": "someLabel"})
+		r := newResourceFactory(newSpecStubResource("resourceName", "/v1/resource", false, &specSchemaDefinition{Properties: specSchemaDefinitionProperties{nameProperty}}))
+		Convey("When getResourceDataOKExists is called with the schema definition property name", func() {
+			value, exists := r.getResourceDataOKExists(nameProperty.Name, resourceData)
+			Convey("Then the bool returned should be true", func() {
+				So(exists, ShouldBeTrue)
+			})
+			Convey("And the value returned should be the one configured using the alias", func() {
+				So(value, ShouldEqual, "someLabel")
+			})
+		})
+	})
 }
 
 // testCreateResourceFactoryWithID configures the resourceData with the Id field. This is used for tests that rely on the
@@ -2231,3 +2954,681 @@ func testCreateSubResourceFactory(t *testing.T, path string, parentResourceNames
 	specResource.fullParentResourceName = fullParentResourceName
 	return newResourceFactory(specResource), resourceData
 }
+
+func TestResolveResponsePayload(t *testing.T) {
+	summarySchema := &specSchemaDefinition{
+		Properties: specSchemaDefinitionProperties{
+			idProperty,
+			newStringSchemaDefinitionPropertyWithDefaults(intProperty.Name, "", false, true, nil),
+		},
+	}
+	testCases := []struct {
+		name               string
+		response           *specResponse
+		responsePayload    map[string]interface{}
+		remotePayload      map[string]interface{}
+		expectedPayload    map[string]interface{}
+		expectedIDReceived string
+	}{
+		{
+			name:            "response not documented",
+			response:        nil,
+			responsePayload: map[string]interface{}{"id": "someID", intProperty.Name: "12"},
+			expectedPayload: map[string]interface{}{"id": "someID", intProperty.Name: "12"},
+		},
+		{
+			name:            "response with polling enabled is returned as is",
+			response:        &specResponse{isPollingEnabled: true, isSummary: true, schema: summarySchema},
+			responsePayload: map[string]interface{}{"id": "someID", intProperty.Name: "12"},
+			expectedPayload: map[string]interface{}{"id": "someID", intProperty.Name: "12"},
+		},
+		{
+			name:               "summary response is replaced with the resource read from the API",
+			response:           &specResponse{isSummary: true},
+			responsePayload:    map[string]interface{}{"id": "someID"},
+			remotePayload:      map[string]interface{}{"id": "someID", intProperty.Name: 12, stringProperty.Name: "someValue"},
+			expectedPayload:    map[string]interface{}{"id": "someID", intProperty.Name: 12, stringProperty.Name: "someValue"},
+			expectedIDReceived: "someID",
+		},
+		{
+			name:            "properties with a type diverging from the resource schema are left out",
+			response:        &specResponse{schema: summarySchema},
+			responsePayload: map[string]interface{}{"id": "someID", intProperty.Name: "12", stringProperty.Name: "someValue"},
+			expectedPayload: map[string]interface{}{"id": "someID", stringProperty.Name: "someValue"},
+		},
+	}
+	for _, tc := range testCases {
+		r, resourceData := testCreateResourceFactory(t, idProperty, stringProperty, intProperty)
+		resourceData.SetId("someID")
+		operation := &specResourceOperation{responses: specResponses{http.StatusCreated: tc.response}}
+		client := &clientOpenAPIStub{responsePayload: tc.remotePayload}
+		payload, err := r.resolveResponsePayload(context.Background(), tc.responsePayload, resourceData, client, operation, http.StatusCreated)
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expectedPayload, payload, tc.name)
+		assert.Equal(t, tc.expectedIDReceived, client.idReceived, tc.name)
+	}
+}
+
+func TestResourceStateRefreshFunc_ProgressMessages(t *testing.T) {
+	statusMessageProperty := &specSchemaDefinitionProperty{Name: "status_details", Type: typeString, ReadOnly: true, IsStatusMessage: true}
+	payloads := []string{
+		`{"id":"id","status":"pending","status_details":"allocating resources (1/2)"}`,
+		`{"id":"id","status":"pending","status_details":"allocating resources (1/2)"}`,
+		`{"id":"id","status":"pending","status_details":"allocating resources (2/2)"}`,
+		`{"id":"id","status":"deployed","status_details":"resources allocated"}`,
+	}
+	requests := 0
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(payloads[requests]))
+		requests++
+	}))
+	defer api.Close()
+	testSchema := newTestSchema(idProperty, statusProperty, statusMessageProperty)
+	resourceData := testSchema.getResourceData(t)
+	resourceData.SetId("id")
+	specResource := newSpecStubResourceWithOperations("resourceName", "/v1/resource", false, testSchema.getSchemaDefinition(), nil, nil, &specResourceOperation{responses: specResponses{}, SecuritySchemes: SpecSecuritySchemes{}}, nil)
+	r := newResourceFactory(specResource)
+	client := newCancellableProviderClient(api.URL, context.Background())
+	ctx, cancel := operationContext(context.Background(), client)
+	defer cancel()
+	response := &specResponse{pollPendingStatuses: []string{"pending"}, pollTargetStatuses: []string{"deployed"}}
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	refresh := r.resourceStateRefreshFunc(ctx, resourceData, client, response)
+	for range payloads {
+		_, _, err := refresh()
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, diag.Diagnostics{
+		{Severity: diag.Warning, Summary: "resource 'resourceName' (id) is still being processed - status: pending, message: allocating resources (1/2)"},
+		{Severity: diag.Warning, Summary: "resource 'resourceName' (id) is still being processed - status: pending, message: allocating resources (2/2)"},
+	}, contextWarnings(ctx).diagnostics(), "only the pending statuses with a new message should be reported")
+	assert.Equal(t, 3, strings.Count(out.String(), "is still being processed"))
+	assert.NotContains(t, out.String(), "status: deployed")
+}
+
+func TestResourceStateRefreshFunc_StatusPathAndFailedStatuses(t *testing.T) {
+	testCases := []struct {
+		name           string
+		response       *specResponse
+		remotePayload  map[string]interface{}
+		expectedStatus string
+		expectedError  string
+	}{
+		{
+			name:           "status read from the nested path configured in the response",
+			response:       &specResponse{pollStatusPath: "$.operations[0].state"},
+			remotePayload:  map[string]interface{}{"id": "someID", "operations": []interface{}{map[string]interface{}{"state": "running"}}},
+			expectedStatus: "running",
+		},
+		{
+			name:           "boolean status read from the path configured in the response",
+			response:       &specResponse{pollStatusPath: "operation.done"},
+			remotePayload:  map[string]interface{}{"id": "someID", "operation": map[string]interface{}{"done": true}},
+			expectedStatus: "true",
+		},
+		{
+			name:          "status path not found in the payload",
+			response:      &specResponse{pollStatusPath: "operation.state"},
+			remotePayload: map[string]interface{}{"id": "someID"},
+			expectedError: "error occurred while retrieving status identifier value from payload for resource 'resourceName' (id): status path 'operation.state' not found in payload",
+		},
+		{
+			name:          "status path with a value of a not supported type",
+			response:      &specResponse{pollStatusPath: "operation"},
+			remotePayload: map[string]interface{}{"id": "someID", "operation": map[string]interface{}{}},
+			expectedError: "error occurred while retrieving status identifier value from payload for resource 'resourceName' (id): status path 'operation' value 'map[]' does not have a supported type [string/number/bool]",
+		},
+		{
+			name:          "status configured as failed",
+			response:      &specResponse{pollStatusPath: "operation.state", pollFailedStatuses: []string{"failed", "error"}},
+			remotePayload: map[string]interface{}{"id": "someID", "operation": map[string]interface{}{"state": "error"}},
+			expectedError: "resource 'resourceName' (id) reached the failed status 'error'",
+		},
+		{
+			name:           "status not configured as failed",
+			response:       &specResponse{pollFailedStatuses: []string{"failed"}},
+			remotePayload:  map[string]interface{}{"id": "someID", statusProperty.Name: "deployed"},
+			expectedStatus: "deployed",
+		},
+	}
+	for _, tc := range testCases {
+		r, resourceData := testCreateResourceFactoryWithID(t, idProperty, statusProperty)
+		client := &clientOpenAPIStub{responsePayload: tc.remotePayload}
+		_, status, err := r.resourceStateRefreshFunc(context.Background(), resourceData, client, tc.response)()
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError, tc.name)
+			continue
+		}
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expectedStatus, status, tc.name)
+	}
+}
+
+func TestHandlePollingIfConfigured_ProgressEvents(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		pollEventsProperty    string
+		streamedEvents        []json.RawMessage
+		expectedEventsPayload interface{}
+	}{
+		{
+			name:                  "last event stored in the computed property configured",
+			pollEventsProperty:    computedProperty.Name,
+			streamedEvents:        []json.RawMessage{json.RawMessage(`{"step":"provisioning"}`), json.RawMessage(`{"step":"done","progress":100}`)},
+			expectedEventsPayload: `{"step":"done","progress":100}`,
+		},
+		{
+			name:               "no events streamed",
+			pollEventsProperty: computedProperty.Name,
+		},
+		{
+			name:               "events property configured is not computed",
+			pollEventsProperty: stringProperty.Name,
+			streamedEvents:     []json.RawMessage{json.RawMessage(`{"step":"done"}`)},
+		},
+		{
+			name:           "events property not configured",
+			streamedEvents: []json.RawMessage{json.RawMessage(`{"step":"done"}`)},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, resourceData := testCreateResourceFactoryWithID(t, idProperty, stringProperty, computedProperty, statusProperty)
+			r.defaultPollDelay = 100 * time.Millisecond
+			r.defaultPollInterval = time.Millisecond
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{idProperty.Name: idProperty.Default, statusProperty.Name: "deployed"},
+				streamedEvents:  tc.streamedEvents,
+			}
+			operation := &specResourceOperation{
+				responses: map[int]*specResponse{
+					http.StatusAccepted: {
+						isPollingEnabled:   true,
+						pollTargetStatuses: []string{"deployed"},
+						pollEventsPath:     "events",
+						pollEventsProperty: tc.pollEventsProperty,
+					},
+				},
+			}
+			responsePayload := map[string]interface{}{}
+			err := r.handlePollingIfConfigured(context.Background(), &responsePayload, resourceData, client, operation, http.StatusAccepted, nil, schema.TimeoutCreate)
+			require.NoError(t, err)
+			assert.Equal(t, "events", client.eventsPathReceived)
+			assert.Equal(t, tc.expectedEventsPayload, responsePayload[computedProperty.Name])
+			assert.Nil(t, responsePayload[stringProperty.Name])
+		})
+	}
+}
+
+func TestResourceFactoryReadOnlyMode(t *testing.T) {
+	r, resourceData := testCreateResourceFactoryWithID(t, idProperty, stringProperty)
+	client := &ProviderClient{
+		providerConfiguration: providerConfiguration{ReadOnly: true},
+	}
+	assert.EqualError(t, r.createResource(context.Background(), resourceData, client), "[resource='resourceName'] create is not allowed as the provider is configured in read-only mode; set the provider property 'read_only' to false to allow changes")
+	assert.EqualError(t, r.updateResource(context.Background(), resourceData, client), "[resource='resourceName'] update is not allowed as the provider is configured in read-only mode; set the provider property 'read_only' to false to allow changes")
+	assert.EqualError(t, r.deleteResource(context.Background(), resourceData, client), "[resource='resourceName'] delete is not allowed as the provider is configured in read-only mode; set the provider property 'read_only' to false to allow changes")
+	assert.Equal(t, "id", resourceData.Id())
+}
+
+func TestCreateTerraformResourceSchema_ConsoleURL(t *testing.T) {
+	testCases := []struct {
+		name               string
+		consoleURLTemplate string
+		properties         []*specSchemaDefinitionProperty
+		expectConsoleURL   bool
+		expectedError      string
+	}{
+		{name: "no console url template", properties: []*specSchemaDefinitionProperty{idProperty, stringProperty}},
+		{name: "console url template referring to the id, region and resource attributes", consoleURLTemplate: "https://console.api.com/{region}/cdns/{id}?label={string_property}", properties: []*specSchemaDefinitionProperty{idProperty, stringProperty}, expectConsoleURL: true},
+		{name: "console url template referring to an attribute that does not exist", consoleURLTemplate: "https://console.api.com/cdns/{non_existing}", properties: []*specSchemaDefinitionProperty{idProperty, stringProperty}, expectedError: "console URL template 'https://console.api.com/cdns/{non_existing}' refers to 'non_existing' which is not an attribute of the resource 'resourceName'"},
+		{name: "resource already has a console_url property", consoleURLTemplate: "https://console.api.com/cdns/{id}", properties: []*specSchemaDefinitionProperty{idProperty, newStringSchemaDefinitionPropertyWithDefaults(consoleURLPropertyName, "", false, false, nil)}},
+	}
+	for _, tc := range testCases {
+		specResource := newSpecStubResource("resourceName", "/v1/resource", false, newTestSchema(tc.properties...).getSchemaDefinition())
+		specResource.consoleURLTemplate = tc.consoleURLTemplate
+		r := newResourceFactory(specResource)
+		s, err := r.createTerraformResourceSchema()
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		if tc.expectConsoleURL {
+			require.Contains(t, s, consoleURLPropertyName, tc.name)
+			assert.True(t, s[consoleURLPropertyName].Computed, tc.name)
+			assert.Equal(t, schema.TypeString, s[consoleURLPropertyName].Type, tc.name)
+		} else if s[consoleURLPropertyName] != nil {
+			assert.False(t, s[consoleURLPropertyName].Computed, tc.name)
+		}
+	}
+}
+
+func TestSetConsoleURL(t *testing.T) {
+	testCases := []struct {
+		name               string
+		consoleURLTemplate string
+		providerClient     ClientOpenAPI
+		id                 string
+		stringValue        string
+		expectedConsoleURL string
+		expectedError      string
+	}{
+		{name: "template with the id and an attribute", consoleURLTemplate: "https://console.api.com/cdns/{id}?label={string_property}", providerClient: &clientOpenAPIStub{}, id: "someID", stringValue: "some label", expectedConsoleURL: "https://console.api.com/cdns/someID?label=some%20label"},
+		{name: "template with the region configured in the provider", consoleURLTemplate: "https://{region}.console.api.com/cdns/{id}", providerClient: &ProviderClient{openAPIBackendConfiguration: &specStubBackendConfiguration{regions: []string{"rst1", "dub1"}}, providerConfiguration: providerConfiguration{Region: "dub1"}}, id: "someID", expectedConsoleURL: "https://dub1.console.api.com/cdns/someID"},
+		{name: "template with the default region", consoleURLTemplate: "https://{region}.console.api.com/cdns/{id}", providerClient: &ProviderClient{openAPIBackendConfiguration: &specStubBackendConfiguration{regions: []string{"rst1", "dub1"}}}, id: "someID", expectedConsoleURL: "https://rst1.console.api.com/cdns/someID"},
+		{name: "template with the region but the backend is not multi-region", consoleURLTemplate: "https://{region}.console.api.com/cdns/{id}", providerClient: &ProviderClient{openAPIBackendConfiguration: &specStubBackendConfiguration{}}, id: "someID", expectedConsoleURL: ""},
+		{name: "template with an attribute that has no value", consoleURLTemplate: "https://console.api.com/cdns/{id}?label={string_property}", providerClient: &clientOpenAPIStub{}, id: "someID", expectedConsoleURL: ""},
+		{name: "template with an id that needs to be escaped", consoleURLTemplate: "https://console.api.com/cdns/{id}", providerClient: &clientOpenAPIStub{}, id: "some/ID", expectedConsoleURL: "https://console.api.com/cdns/some%2FID"},
+		{name: "region can not be resolved", consoleURLTemplate: "https://{region}.console.api.com/cdns/{id}", providerClient: &ProviderClient{openAPIBackendConfiguration: &specStubBackendConfiguration{err: errors.New("some error")}}, id: "someID", expectedError: "some error"},
+	}
+	for _, tc := range testCases {
+		specResource := newSpecStubResource("resourceName", "/v1/resource", false, newTestSchema(idProperty, stringProperty).getSchemaDefinition())
+		specResource.consoleURLTemplate = tc.consoleURLTemplate
+		r := newResourceFactory(specResource)
+		resource, err := r.createTerraformResource()
+		require.NoError(t, err, tc.name)
+		data := resource.TestResourceData()
+		data.SetId(tc.id)
+		data.Set(stringProperty.Name, tc.stringValue)
+		err = r.setConsoleURL(data, tc.providerClient)
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError, tc.name)
+			continue
+		}
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expectedConsoleURL, data.Get(consoleURLPropertyName), tc.name)
+	}
+}
+
+func TestRead_ConsoleURL(t *testing.T) {
+	specResource := newSpecStubResource("resourceName", "/v1/resource", false, newTestSchema(idProperty, stringProperty).getSchemaDefinition())
+	specResource.consoleURLTemplate = "https://console.api.com/cdns/{id}/{string_property}"
+	r := newResourceFactory(specResource)
+	resource, err := r.createTerraformResource()
+	require.NoError(t, err)
+	data := resource.TestResourceData()
+	data.SetId("someID")
+	client := &clientOpenAPIStub{
+		responsePayload: map[string]interface{}{
+			stringProperty.Name: "someLabel",
+		},
+	}
+	err = r.readResource(context.Background(), data, client)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://console.api.com/cdns/someID/someLabel", data.Get(consoleURLPropertyName))
+}
+
+func TestRead_RefreshOnDemand(t *testing.T) {
+	expensiveProperty := newStringSchemaDefinitionPropertyWithDefaults("usage_report", "", false, true, nil)
+	expensiveProperty.RefreshOnDemand = true
+	testCases := []struct {
+		name          string
+		stateValue    string
+		expectedValue string
+	}{
+		{
+			name:          "routine refresh keeps the value stored in the state",
+			stateValue:    "previous report",
+			expectedValue: "previous report",
+		},
+		{
+			name:          "refresh with no value in the state (e,g: import) populates the value",
+			expectedValue: "current report",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			specResource := newSpecStubResource("resourceName", "/v1/resource", false, newTestSchema(idProperty, stringProperty, expensiveProperty).getSchemaDefinition())
+			r := newResourceFactory(specResource)
+			resource, err := r.createTerraformResource()
+			require.NoError(t, err)
+			data := resource.TestResourceData()
+			data.SetId("someID")
+			if tc.stateValue != "" {
+				require.NoError(t, data.Set(expensiveProperty.Name, tc.stateValue))
+			}
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					stringProperty.Name:    "someValue",
+					expensiveProperty.Name: "current report",
+				},
+			}
+			err = r.readResource(context.Background(), data, client)
+			assert.NoError(t, err)
+			assert.Equal(t, "someValue", data.Get(stringProperty.Name))
+			assert.Equal(t, tc.expectedValue, data.Get(expensiveProperty.Name))
+			assert.Equal(t, "current report", client.responsePayload[expensiveProperty.Name], "the response payload should not be modified")
+		})
+	}
+}
+
+func TestGetRefreshOnDemandPropertiesToSkip(t *testing.T) {
+	expensiveProperty := newStringSchemaDefinitionPropertyWithDefaults("usage_report", "", false, true, nil)
+	expensiveProperty.RefreshOnDemand = true
+	r := newResourceFactory(newSpecStubResource("resourceName", "/v1/resource", false, newTestSchema(idProperty, computedProperty, expensiveProperty).getSchemaDefinition()))
+	resource, err := r.createTerraformResource()
+	require.NoError(t, err)
+	data := resource.TestResourceData()
+	require.NoError(t, data.Set(computedProperty.Name, "someValue"))
+	require.NoError(t, data.Set(expensiveProperty.Name, "previous report"))
+
+	skippedProperties := r.getRefreshOnDemandPropertiesToSkip(data, &ProviderClient{})
+	assert.Equal(t, map[string]*specSchemaDefinitionProperty{expensiveProperty.Name: expensiveProperty}, skippedProperties)
+
+	skippedProperties = r.getRefreshOnDemandPropertiesToSkip(data, &ProviderClient{providerConfiguration: providerConfiguration{FullRefresh: true}})
+	assert.Empty(t, skippedProperties, "no properties should be skipped when the provider is configured to perform full refreshes")
+}
+
+func TestWithRefreshFieldsQuery(t *testing.T) {
+	expensiveProperty := newStringSchemaDefinitionPropertyWithDefaults("usage_report", "", false, true, nil)
+	expensiveProperty.RefreshOnDemand = true
+	derivedProperty := newStringSchemaDefinitionPropertyWithDefaults("endpoint", "", false, true, nil)
+	derivedProperty.DerivedTemplate = "https://{string_property}"
+	skippedProperties := map[string]*specSchemaDefinitionProperty{expensiveProperty.Name: expensiveProperty}
+	testSchema := newTestSchema(idProperty, stringProperty, expensiveProperty, derivedProperty)
+	testCases := []struct {
+		name                    string
+		getOperation            *specResourceOperation
+		skippedProperties       map[string]*specSchemaDefinitionProperty
+		expectedQueryParameters url.Values
+	}{
+		{
+			name:                    "GET operation supporting the fields query parameter",
+			getOperation:            &specResourceOperation{RefreshFieldsQueryParam: "fields"},
+			skippedProperties:       skippedProperties,
+			expectedQueryParameters: url.Values{"fields": {"id,string_property"}},
+		},
+		{
+			name:              "GET operation not supporting the fields query parameter",
+			getOperation:      &specResourceOperation{},
+			skippedProperties: skippedProperties,
+		},
+		{
+			name:         "no properties skipped",
+			getOperation: &specResourceOperation{RefreshFieldsQueryParam: "fields"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newResourceFactory(newSpecStubResourceWithOperations("resourceName", "/v1/resource", false, testSchema.getSchemaDefinition(), nil, nil, tc.getOperation, nil))
+			client := r.withRefreshFieldsQuery(&ProviderClient{}, tc.skippedProperties)
+			assert.Equal(t, tc.expectedQueryParameters, client.(*ProviderClient).queryParameters)
+		})
+	}
+}
+
+func TestWithUpdateMask(t *testing.T) {
+	descriptionProperty := newStringSchemaDefinitionPropertyWithDefaults("description", "", false, false, nil)
+	preferredNameProperty := newStringSchemaDefinitionPropertyWithDefaults("displayName", "display_name", false, false, "some name")
+	testSchema := newTestSchema(stringProperty, computedProperty, descriptionProperty, preferredNameProperty)
+	testCases := []struct {
+		name                    string
+		operation               *specResourceOperation
+		expectedQueryParameters url.Values
+	}{
+		{
+			name:                    "update operation expecting an update mask",
+			operation:               &specResourceOperation{UpdateMaskQueryParam: "update_mask"},
+			expectedQueryParameters: url.Values{"update_mask": {"string_property,displayName"}},
+		},
+		{
+			name:      "update operation not expecting an update mask",
+			operation: &specResourceOperation{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newResourceFactory(newSpecStubResourceWithOperations("resourceName", "/v1/resource", false, testSchema.getSchemaDefinition(), nil, tc.operation, nil, nil))
+			client := r.withUpdateMask(&ProviderClient{}, tc.operation, testSchema.getResourceData(t))
+			assert.Equal(t, tc.expectedQueryParameters, client.(*ProviderClient).queryParameters)
+		})
+	}
+}
+
+func TestUpdate_JSONPatch(t *testing.T) {
+	testSchema := newTestSchema(idProperty, stringProperty, intProperty)
+	updateOperation := &specResourceOperation{UpdateStrategy: updateStrategyJSONPatch}
+	r := newResourceFactory(newSpecStubResourceWithOperations("resourceName", "/v1/resource", false, testSchema.getSchemaDefinition(), &specResourceOperation{}, updateOperation, &specResourceOperation{}, &specResourceOperation{}))
+	resourceData := testSchema.getResourceData(t)
+	resourceData.SetId("id")
+	client := &clientOpenAPIStub{
+		responsePayload: map[string]interface{}{
+			idProperty.Name:     "id",
+			stringProperty.Name: "valueReturnedByTheAPI",
+			intProperty.Name:    12,
+		},
+	}
+	err := r.updateResource(context.Background(), resourceData, client)
+	require.NoError(t, err)
+	assert.Equal(t, "id", client.idReceived)
+	assert.Equal(t, []jsonPatchOperation{
+		{Op: jsonPatchOpAdd, Path: "/id", Value: "id"},
+		{Op: jsonPatchOpAdd, Path: "/int_property", Value: 12},
+		{Op: jsonPatchOpAdd, Path: "/string_property", Value: "updatedValue"},
+	}, client.patchOperationsReceived)
+	assert.Equal(t, "valueReturnedByTheAPI", resourceData.Get(stringProperty.Name))
+}
+
+func TestUpdate_JSONPatchNotSupportedByTheClient(t *testing.T) {
+	testSchema := newTestSchema(idProperty, stringProperty)
+	updateOperation := &specResourceOperation{UpdateStrategy: updateStrategyJSONPatch}
+	r := newResourceFactory(newSpecStubResourceWithOperations("resourceName", "/v1/resource", false, testSchema.getSchemaDefinition(), &specResourceOperation{}, updateOperation, &specResourceOperation{}, &specResourceOperation{}))
+	resourceData := testSchema.getResourceData(t)
+	resourceData.SetId("id")
+	client := &clientOpenAPIStub{responsePayload: map[string]interface{}{idProperty.Name: "id"}}
+	err := r.updateResource(context.Background(), resourceData, clientOpenAPIWithoutPatch{client})
+	assert.EqualError(t, err, "[resource='resourceName'] the client does not support the 'json-patch' update strategy")
+	assert.Empty(t, client.patchOperationsReceived)
+}
+
+func TestIsZeroStateValue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    interface{}
+		expected bool
+	}{
+		{name: "nil value", value: nil, expected: true},
+		{name: "empty string", value: "", expected: true},
+		{name: "non empty string", value: "some value", expected: false},
+		{name: "zero int", value: 0, expected: true},
+		{name: "false bool", value: false, expected: true},
+		{name: "empty list", value: []interface{}{}, expected: true},
+		{name: "non empty list", value: []interface{}{"item"}, expected: false},
+		{name: "empty map", value: map[string]interface{}{}, expected: true},
+		{name: "empty set", value: schema.NewSet(schema.HashString, nil), expected: true},
+		{name: "non empty set", value: schema.NewSet(schema.HashString, []interface{}{"item"}), expected: false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, isZeroStateValue(tc.value), tc.name)
+	}
+}
+
+func TestImportOnlyResource(t *testing.T) {
+	specResource := newSpecStubResource("tenant_v1", "/v1/tenants", false, newTestSchema(idProperty, stringProperty).getSchemaDefinition())
+	specResource.importOnly = true
+	r := newResourceFactory(specResource)
+	resource, err := r.createTerraformResource()
+	require.NoError(t, err)
+	require.NotNil(t, resource.Importer)
+
+	data := resource.TestResourceData()
+	data.SetId("someID")
+	client := &clientOpenAPIStub{
+		responsePayload: map[string]interface{}{
+			stringProperty.Name: "someValue",
+		},
+	}
+
+	err = r.createResource(context.Background(), data, client)
+	assert.EqualError(t, err, "[resource='tenant_v1'] resource is import-only and can not be created by terraform; import the existing instance instead (terraform import <resource_address> <id>)")
+	assert.Nil(t, client.resourceReceived)
+
+	err = r.updateResource(context.Background(), data, client)
+	assert.EqualError(t, err, "[resource='tenant_v1'] resource is import-only and can not be updated by terraform; make the changes outside terraform and update the configuration to match the remote instance")
+	assert.Nil(t, client.resourceReceived)
+
+	err = r.deleteResource(context.Background(), data, client)
+	assert.NoError(t, err)
+	assert.Empty(t, client.idReceived, "the remote instance should not be deleted")
+
+	err = r.readResource(context.Background(), data, client)
+	assert.NoError(t, err)
+	assert.Equal(t, "someValue", data.Get(stringProperty.Name))
+}
+
+func TestCompositeIDResource(t *testing.T) {
+	namespaceProperty := newStringSchemaDefinitionPropertyWithDefaults("namespace", "", true, false, "ns1")
+	nameProperty := newStringSchemaDefinitionPropertyWithDefaults("name", "", true, false, "thing1")
+	testSchema := newTestSchema(namespaceProperty, nameProperty, stringProperty)
+	specResource := newSpecStubResourceWithOperations("things_v1", "/v1/ns/{namespace}/things", false, testSchema.getSchemaDefinition(), &specResourceOperation{}, &specResourceOperation{}, &specResourceOperation{}, &specResourceOperation{})
+	specResource.compositeID = []string{"namespace", "name"}
+	r := newResourceFactory(specResource)
+	resource, err := r.createTerraformResource()
+	require.NoError(t, err)
+	assert.True(t, resource.Schema["namespace"].ForceNew)
+	assert.True(t, resource.Schema["name"].ForceNew)
+
+	data := testSchema.getResourceData(t)
+	client := &clientOpenAPIStub{
+		responsePayload: map[string]interface{}{
+			"namespace":         "ns1",
+			"name":              "thing1",
+			stringProperty.Name: "someValue",
+		},
+	}
+	err = r.createResource(context.Background(), data, client)
+	require.NoError(t, err)
+	assert.Equal(t, "ns1/thing1", data.Id())
+	assert.Equal(t, []string{"ns1"}, client.parentIDsReceived)
+
+	for name, operation := range map[string]func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics{"read": resource.ReadContext, "update": resource.UpdateContext, "delete": resource.DeleteContext} {
+		client.idReceived, client.parentIDsReceived = "", nil
+		diags := operation(context.Background(), data, client)
+		require.Empty(t, diags, name)
+		assert.Equal(t, "thing1", client.idReceived, name)
+		assert.Equal(t, []string{"ns1"}, client.parentIDsReceived, name)
+	}
+
+	importedData := resource.TestResourceData()
+	importedData.SetId("ns2/thing2")
+	client.responsePayload = map[string]interface{}{"namespace": "ns2", "name": "thing2", stringProperty.Name: "someOtherValue"}
+	results, err := resource.Importer.StateContext(context.Background(), importedData, client)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "ns2/thing2", results[0].Id())
+	assert.Equal(t, "ns2", results[0].Get("namespace"))
+	assert.Equal(t, "thing2", results[0].Get("name"))
+	assert.Equal(t, "someOtherValue", results[0].Get(stringProperty.Name))
+	assert.Equal(t, "thing2", client.idReceived)
+	assert.Equal(t, []string{"ns2"}, client.parentIDsReceived)
+
+	importedData = resource.TestResourceData()
+	importedData.SetId("thing2")
+	_, err = resource.Importer.StateContext(context.Background(), importedData, client)
+	assert.EqualError(t, err, "can not import the resource: id 'thing2' does not match the expected composite id format 'namespace/name'")
+}
+
+func TestCheckRequiredIfProperties(t *testing.T) {
+	typeProperty := newStringSchemaDefinitionPropertyWithDefaults("type", "", true, false, nil)
+	preSharedKeyProperty := newStringSchemaDefinitionPropertyWithDefaults("pre_shared_key", "", false, false, nil)
+	preSharedKeyProperty.RequiredIfProperty = "type"
+	preSharedKeyProperty.RequiredIfValues = []string{"vpn", "ipsec"}
+	portProperty := newIntSchemaDefinitionPropertyWithDefaults("port", "", false, false, nil)
+	portProperty.RequiredIfProperty = "type"
+	portProperty.RequiredIfValues = []string{"vpn"}
+	specResource := newSpecStubResource("tunnels_v1", "/v1/tunnels", false, &specSchemaDefinition{
+		Properties: specSchemaDefinitionProperties{typeProperty, preSharedKeyProperty, portProperty},
+	})
+	resource, err := newResourceFactory(specResource).createTerraformResource()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		config        map[string]interface{}
+		expectedError string
+	}{
+		{
+			name:   "condition not met",
+			config: map[string]interface{}{"type": "gre"},
+		},
+		{
+			name:   "condition met and the conditionally required properties are set",
+			config: map[string]interface{}{"type": "vpn", "pre_shared_key": "secret", "port": 500},
+		},
+		{
+			name:          "condition met and the conditionally required properties are not set",
+			config:        map[string]interface{}{"type": "vpn"},
+			expectedError: "resource 'tunnels_v1' configuration is not valid: 'port' is required when 'type' is 'vpn'; 'pre_shared_key' is required when 'type' is 'vpn'",
+		},
+		{
+			name:          "condition met with another value and the conditionally required property is not set",
+			config:        map[string]interface{}{"type": "ipsec"},
+			expectedError: "resource 'tunnels_v1' configuration is not valid: 'pre_shared_key' is required when 'type' is 'ipsec'",
+		},
+	}
+	for _, tc := range testCases {
+		_, err := resource.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tc.config), nil)
+		if tc.expectedError == "" {
+			assert.NoError(t, err, tc.name)
+		} else {
+			assert.EqualError(t, err, tc.expectedError, tc.name)
+		}
+	}
+}
+
+func TestLogForceNewReasons(t *testing.T) {
+	regionProperty := newStringSchemaDefinitionPropertyWithDefaults("region", "", true, false, nil)
+	regionProperty.ForceNew = true
+	regionProperty.ForceNewReason = "the API does not support moving databases across regions"
+	sizeProperty := newStringSchemaDefinitionPropertyWithDefaults("size", "", true, false, nil)
+	sizeProperty.ForceNew = true
+	specResource := newSpecStubResource("databases_v1", "/v1/databases", false, &specSchemaDefinition{
+		Properties: specSchemaDefinitionProperties{regionProperty, sizeProperty},
+	})
+	resource, err := newResourceFactory(specResource).createTerraformResource()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name                string
+		state               *terraform.InstanceState
+		config              map[string]interface{}
+		expectedLogContains string
+	}{
+		{
+			name:                "property with a force new reason changed",
+			state:               &terraform.InstanceState{ID: "1234", Attributes: map[string]string{"id": "1234", "region": "eu", "size": "small"}},
+			config:              map[string]interface{}{"region": "us", "size": "small"},
+			expectedLogContains: "resource 'databases_v1' (1234) will be replaced since the property 'region' changed: the API does not support moving databases across regions",
+		},
+		{
+			name:   "property without a force new reason changed",
+			state:  &terraform.InstanceState{ID: "1234", Attributes: map[string]string{"id": "1234", "region": "eu", "size": "small"}},
+			config: map[string]interface{}{"region": "eu", "size": "large"},
+		},
+		{
+			name:   "resource not created yet",
+			config: map[string]interface{}{"region": "us", "size": "small"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			log.SetOutput(&out)
+			defer log.SetOutput(os.Stderr)
+			diff, err := resource.Diff(context.Background(), tc.state, terraform.NewResourceConfigRaw(tc.config), nil)
+			require.NoError(t, err)
+			require.NotNil(t, diff)
+			if tc.expectedLogContains != "" {
+				assert.Contains(t, out.String(), tc.expectedLogContains)
+				assert.True(t, diff.RequiresNew())
+			} else {
+				assert.NotContains(t, out.String(), "will be replaced")
+			}
+		})
+	}
+}
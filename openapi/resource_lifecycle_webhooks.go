@@ -0,0 +1,142 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Statuses reported in the lifecycle events
+const (
+	lifecycleEventStatusSucceeded = "succeeded"
+	lifecycleEventStatusFailed    = "failed"
+)
+
+// lifecycleEvent describes the payload POSTed to the webhooks when a resource operation completes
+type lifecycleEvent struct {
+	Event        string `json:"event"`
+	ResourceType string `json:"resource_type"`
+	ID           string `json:"id"`
+	Status       string `json:"status"`
+	Error        string `json:"error,omitempty"`
+	DurationMs   int64  `json:"duration_ms"`
+	Timestamp    string `json:"timestamp"`
+}
+
+// lifecycleNotifier notifies the webhooks configured in the service configuration when resource operations (create,
+// update and delete) complete, so external systems (e.g. a CMDB) can keep track of the changes made by the provider.
+// Failing to notify a webhook never fails the operation itself, the error is only logged.
+type lifecycleNotifier struct {
+	webhooks   []ServiceWebhook
+	httpClient *http.Client
+}
+
+func newLifecycleNotifier(webhooks []ServiceWebhook) *lifecycleNotifier {
+	return &lifecycleNotifier{
+		webhooks:   webhooks,
+		httpClient: &http.Client{},
+	}
+}
+
+// wrapResource makes the create, update and delete operations of the given resource notify the webhooks when they complete
+func (n *lifecycleNotifier) wrapResource(resourceType string, resource *schema.Resource) {
+	if resource.CreateContext != nil {
+		resource.CreateContext = n.wrap(webhookEventCreate, resourceType, resource.CreateContext)
+	}
+	if resource.UpdateContext != nil {
+		resource.UpdateContext = n.wrap(webhookEventUpdate, resourceType, resource.UpdateContext)
+	}
+	if resource.DeleteContext != nil {
+		resource.DeleteContext = n.wrap(webhookEventDelete, resourceType, resource.DeleteContext)
+	}
+}
+
+func (n *lifecycleNotifier) wrap(event, resourceType string, operation func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+		id := data.Id()
+		start := time.Now()
+		diags := operation(ctx, data, i)
+		if data.Id() != "" {
+			id = data.Id()
+		}
+		lifecycleEvent := lifecycleEvent{
+			Event:        event,
+			ResourceType: resourceType,
+			ID:           id,
+			Status:       lifecycleEventStatusSucceeded,
+			DurationMs:   int64(time.Since(start) / time.Millisecond),
+			Timestamp:    time.Now().UTC().Format(time.RFC3339),
+		}
+		if err := diagnosticsError(diags); err != nil {
+			lifecycleEvent.Status = lifecycleEventStatusFailed
+			lifecycleEvent.Error = err.Error()
+		}
+		n.notify(lifecycleEvent)
+		return diags
+	}
+}
+
+// notify POSTs the given event to the webhooks subscribed to it
+func (n *lifecycleNotifier) notify(event lifecycleEvent) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("[WARN] failed to build the '%s' lifecycle event for resource '%s' (%s): %s", event.Event, event.ResourceType, event.ID, err)
+		return
+	}
+	for _, webhook := range n.webhooks {
+		if !n.isSubscribed(webhook, event.Event) {
+			continue
+		}
+		if err := n.post(webhook, payload); err != nil {
+			log.Printf("[WARN] failed to notify the webhook '%s' about the '%s' lifecycle event for resource '%s' (%s): %s", webhook.URL, event.Event, event.ResourceType, event.ID, err)
+			continue
+		}
+		log.Printf("[DEBUG] webhook '%s' notified about the '%s' lifecycle event for resource '%s' (%s)", webhook.URL, event.Event, event.ResourceType, event.ID)
+	}
+}
+
+func (n *lifecycleNotifier) isSubscribed(webhook ServiceWebhook, event string) bool {
+	if len(webhook.Events) == 0 {
+		return true
+	}
+	for _, webhookEvent := range webhook.Events {
+		if webhookEvent == event {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *lifecycleNotifier) post(webhook ServiceWebhook, payload []byte) error {
+	timeout := webhook.Timeout
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set(contentTypeHeader, "application/json")
+	for name, value := range webhook.Headers {
+		req.Header.Set(name, value)
+	}
+	res, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with status code %d", res.StatusCode)
+	}
+	return nil
+}
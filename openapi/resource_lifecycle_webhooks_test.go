@@ -0,0 +1,89 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifecycleNotifierWrapResource(t *testing.T) {
+	var events []lifecycleEvent
+	var authorizationHeaders []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event := lifecycleEvent{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		events = append(events, event)
+		authorizationHeaders = append(authorizationHeaders, r.Header.Get(authorizationHeader))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	failingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failingServer.Close()
+
+	resource := &schema.Resource{
+		Schema: map[string]*schema.Schema{"name": {Type: schema.TypeString, Optional: true}},
+		CreateContext: func(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+			data.SetId("someID")
+			return nil
+		},
+		UpdateContext: func(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+			return diag.Errorf("update failed")
+		},
+		DeleteContext: func(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+			data.SetId("")
+			return nil
+		},
+	}
+	notifier := newLifecycleNotifier([]ServiceWebhook{
+		{URL: server.URL, Headers: map[string]string{authorizationHeader: "Bearer token"}, Timeout: time.Second},
+		{URL: server.URL, Events: []string{webhookEventDelete}},
+		{URL: failingServer.URL},
+	})
+	notifier.wrapResource("openapi_cdn_v1", resource)
+
+	data := resource.TestResourceData()
+	assert.Empty(t, resource.CreateContext(context.Background(), data, nil))
+	require.Len(t, events, 1)
+	assert.Equal(t, webhookEventCreate, events[0].Event)
+	assert.Equal(t, "openapi_cdn_v1", events[0].ResourceType)
+	assert.Equal(t, "someID", events[0].ID)
+	assert.Equal(t, lifecycleEventStatusSucceeded, events[0].Status)
+	assert.Empty(t, events[0].Error)
+	assert.NotEmpty(t, events[0].Timestamp)
+	assert.Equal(t, "Bearer token", authorizationHeaders[0])
+
+	assert.EqualError(t, diagnosticsError(resource.UpdateContext(context.Background(), data, nil)), "update failed")
+	require.Len(t, events, 2)
+	assert.Equal(t, webhookEventUpdate, events[1].Event)
+	assert.Equal(t, lifecycleEventStatusFailed, events[1].Status)
+	assert.Equal(t, "update failed", events[1].Error)
+
+	assert.Empty(t, resource.DeleteContext(context.Background(), data, nil))
+	require.Len(t, events, 4)
+	for _, event := range events[2:] {
+		assert.Equal(t, webhookEventDelete, event.Event)
+		assert.Equal(t, "someID", event.ID)
+		assert.Equal(t, lifecycleEventStatusSucceeded, event.Status)
+	}
+	assert.Equal(t, []string{"Bearer token", "Bearer token", "Bearer token", ""}, authorizationHeaders)
+}
+
+func TestLifecycleNotifierNotify_WebhookNotReachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+	notifier := newLifecycleNotifier([]ServiceWebhook{{URL: server.URL, Timeout: 10 * time.Millisecond}})
+	err := notifier.post(notifier.webhooks[0], []byte(`{}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,52 @@
+package openapi
+
+import (
+	"regexp"
+	"strings"
+)
+
+var resourceNameVersionRegex = regexp.MustCompile(`^v\d+$`)
+var singularizableWordRegex = regexp.MustCompile(`^[a-zA-Z]+$`)
+
+// singularizeResourceName returns the singular form of the given resource name (e.g. policies_v1 -> policy_v1). The name is
+// split into words by underscores and only the words that name a collection are singularized, that is the ones preceding
+// a version (e.g. cdns_v1_firewalls_v1 -> cdn_v1_firewall_v1) and the last one (e.g. cdns -> cdn). The overrides map
+// allows specifying the singular form of any word (e.g. people -> person), taking preference over the built-in rules.
+func singularizeResourceName(resourceName string, overrides map[string]string) string {
+	words := strings.Split(resourceName, "_")
+	for i, word := range words {
+		if resourceNameVersionRegex.MatchString(word) {
+			if i > 0 && !resourceNameVersionRegex.MatchString(words[i-1]) {
+				words[i-1] = singularize(words[i-1], overrides)
+			}
+			continue
+		}
+		if i == len(words)-1 {
+			words[i] = singularize(word, overrides)
+		}
+	}
+	return strings.Join(words, "_")
+}
+
+// singularize returns the singular form of the given word following basic english rules. Words that are not made of letters
+// only, are too short to be safely singularized (e.g. dns) or look already singular (e.g. status, access) are returned as is.
+func singularize(word string, overrides map[string]string) string {
+	if singular, exists := overrides[word]; exists {
+		return singular
+	}
+	if len(word) <= 3 || !singularizableWordRegex.MatchString(word) {
+		return word
+	}
+	lowerCaseWord := strings.ToLower(word)
+	switch {
+	case strings.HasSuffix(lowerCaseWord, "ies"):
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(lowerCaseWord, "sses"), strings.HasSuffix(lowerCaseWord, "shes"), strings.HasSuffix(lowerCaseWord, "ches"), strings.HasSuffix(lowerCaseWord, "xes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(lowerCaseWord, "ss"), strings.HasSuffix(lowerCaseWord, "us"), strings.HasSuffix(lowerCaseWord, "is"):
+		return word
+	case strings.HasSuffix(lowerCaseWord, "s"):
+		return word[:len(word)-1]
+	}
+	return word
+}
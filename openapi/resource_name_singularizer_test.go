@@ -0,0 +1,31 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSingularizeResourceName(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		resourceName         string
+		overrides            map[string]string
+		expectedResourceName string
+	}{
+		{name: "non versioned resource name", resourceName: "cdns", expectedResourceName: "cdn"},
+		{name: "versioned resource name", resourceName: "policies_v1", expectedResourceName: "policy_v1"},
+		{name: "subresource name", resourceName: "cdns_v1_firewalls_v1", expectedResourceName: "cdn_v1_firewall_v1"},
+		{name: "multi word resource name where only the last word is singularized", resourceName: "access_keys_v1", expectedResourceName: "access_key_v1"},
+		{name: "region based resource name where the region is not singularized", resourceName: "cdns_v1_rst1", expectedResourceName: "cdn_v1_rst1"},
+		{name: "resource name that is already singular", resourceName: "status_v1", expectedResourceName: "status_v1"},
+		{name: "resource name ending with es", resourceName: "addresses_v1", expectedResourceName: "address_v1"},
+		{name: "resource name too short to be singularized", resourceName: "dns_v1", expectedResourceName: "dns_v1"},
+		{name: "resource name with an override", resourceName: "people_v1", overrides: map[string]string{"people": "person"}, expectedResourceName: "person_v1"},
+		{name: "resource name with an override that keeps the name as is", resourceName: "news_v1", overrides: map[string]string{"news": "news"}, expectedResourceName: "news_v1"},
+	}
+	for _, tc := range testCases {
+		resourceName := singularizeResourceName(tc.resourceName, tc.overrides)
+		assert.Equal(t, tc.expectedResourceName, resourceName, tc.name)
+	}
+}
@@ -0,0 +1,115 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+const (
+	onFailureCleanupPath   = "path"
+	onFailureCleanupMethod = "method"
+)
+
+// onFailureCleanupOperation defines the operation called to clean up a resource whose create failed after the API already
+// returned its id (e.g. POST /v1/resource/{id}/abort), as configured via the x-terraform-on-failure-cleanup extension
+type onFailureCleanupOperation struct {
+	// path is the path of the operation as documented in the OpenAPI document (e.g. /v1/resource/{id}/abort)
+	path   string
+	method httpMethodSupported
+	// subPath is the part of the path that follows the resource instance path (e.g. /abort); empty if the operation is
+	// documented in the resource instance path itself (e.g. DELETE /v1/resource/{id})
+	subPath string
+	// operation contains the definition of the operation in the OpenAPI document, which defines among other things how
+	// the request is authenticated
+	operation *specResourceOperation
+}
+
+// onFailureCleanupClient defines the behaviour expected from the clients able to call the cleanup operation of the
+// resources, which is not part of the ClientOpenAPI interface
+type onFailureCleanupClient interface {
+	CallCleanupOperation(ctx context.Context, resource SpecResource, id string, cleanup *onFailureCleanupOperation, parentIDs ...string) (*http.Response, error)
+}
+
+// parseOnFailureCleanupExtension parses the value of the x-terraform-on-failure-cleanup extension, which is an object
+// containing the path and the method of the cleanup operation (e.g. {path: /v1/resource/{id}/abort, method: post})
+func parseOnFailureCleanupExtension(value interface{}) (*onFailureCleanupOperation, error) {
+	properties, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected an object value but got '%v'", value)
+	}
+	cleanup := &onFailureCleanupOperation{}
+	var names []string
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		property, ok := properties[name].(string)
+		if !ok {
+			return nil, fmt.Errorf("'%s' is not valid: expected a string value but got '%v'", name, properties[name])
+		}
+		switch name {
+		case onFailureCleanupPath:
+			cleanup.path = property
+		case onFailureCleanupMethod:
+			switch method := httpMethodSupported(strings.ToUpper(property)); method {
+			case httpPost, httpPut, httpPatch, httpDelete:
+				cleanup.method = method
+			default:
+				return nil, fmt.Errorf("'%s' is not valid: method '%s' not supported, supported methods: post, put, patch, delete", name, property)
+			}
+		default:
+			return nil, fmt.Errorf("'%s' is not valid: property not supported, supported properties: %s", name, strings.Join([]string{onFailureCleanupMethod, onFailureCleanupPath}, ", "))
+		}
+	}
+	if cleanup.path == "" {
+		return nil, fmt.Errorf("'%s' is required", onFailureCleanupPath)
+	}
+	if cleanup.method == "" {
+		return nil, fmt.Errorf("'%s' is required", onFailureCleanupMethod)
+	}
+	return cleanup, nil
+}
+
+// validateOnFailureCleanupExtension is the Validate hook of the x-terraform-on-failure-cleanup extension
+func validateOnFailureCleanupExtension(value interface{}) error {
+	_, err := parseOnFailureCleanupExtension(value)
+	return err
+}
+
+// resolve looks up the cleanup operation in the given paths of the OpenAPI document. The operation must be documented in
+// the instance path of the resource with the given root path (e.g. /v1/resource/{id}) or in a sub-path of it (e.g.
+// /v1/resource/{id}/abort) so it can be called with the id the API returned for the resource
+func (c *onFailureCleanupOperation) resolve(resourceRootPath string, paths map[string]spec.PathItem) (*spec.Operation, error) {
+	instancePathRegex := regexp.MustCompile(fmt.Sprintf(`^%s/\{[^/]+\}(/.*)?$`, regexp.QuoteMeta(strings.TrimRight(resourceRootPath, "/"))))
+	matches := instancePathRegex.FindStringSubmatch(c.path)
+	if matches == nil {
+		return nil, fmt.Errorf("path '%s' is neither the resource instance path nor a sub-path of it", c.path)
+	}
+	pathItem, exists := paths[c.path]
+	if !exists {
+		return nil, fmt.Errorf("path '%s' not found in the OpenAPI document", c.path)
+	}
+	var operation *spec.Operation
+	switch c.method {
+	case httpPost:
+		operation = pathItem.Post
+	case httpPut:
+		operation = pathItem.Put
+	case httpPatch:
+		operation = pathItem.Patch
+	case httpDelete:
+		operation = pathItem.Delete
+	}
+	if operation == nil {
+		return nil, fmt.Errorf("operation %s %s not found in the OpenAPI document", c.method, c.path)
+	}
+	c.subPath = strings.TrimRight(matches[1], "/")
+	return operation, nil
+}
@@ -0,0 +1,73 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clientOpenAPIWithoutCleanupOperations hides the optional CallCleanupOperation method of the stub client
+type clientOpenAPIWithoutCleanupOperations struct {
+	ClientOpenAPI
+}
+
+func TestParseOnFailureCleanupExtension(t *testing.T) {
+	testCases := []struct {
+		name            string
+		value           interface{}
+		expectedCleanup *onFailureCleanupOperation
+		expectedError   string
+	}{
+		{
+			name:            "all properties",
+			value:           map[string]interface{}{"path": "/v1/resource/{id}/abort", "method": "post"},
+			expectedCleanup: &onFailureCleanupOperation{path: "/v1/resource/{id}/abort", method: httpPost},
+		},
+		{
+			name:            "method in upper case",
+			value:           map[string]interface{}{"path": "/v1/resource/{id}", "method": "DELETE"},
+			expectedCleanup: &onFailureCleanupOperation{path: "/v1/resource/{id}", method: httpDelete},
+		},
+		{
+			name:          "value is not an object",
+			value:         true,
+			expectedError: "expected an object value but got 'true'",
+		},
+		{
+			name:          "path missing",
+			value:         map[string]interface{}{"method": "post"},
+			expectedError: "'path' is required",
+		},
+		{
+			name:          "method missing",
+			value:         map[string]interface{}{"path": "/v1/resource/{id}/abort"},
+			expectedError: "'method' is required",
+		},
+		{
+			name:          "method not supported",
+			value:         map[string]interface{}{"path": "/v1/resource/{id}/abort", "method": "get"},
+			expectedError: "'method' is not valid: method 'get' not supported, supported methods: post, put, patch, delete",
+		},
+		{
+			name:          "path not a string",
+			value:         map[string]interface{}{"path": float64(1), "method": "post"},
+			expectedError: "'path' is not valid: expected a string value but got '1'",
+		},
+		{
+			name:          "property not supported",
+			value:         map[string]interface{}{"path": "/v1/resource/{id}/abort", "method": "post", "body": "{}"},
+			expectedError: "'body' is not valid: property not supported, supported properties: method, path",
+		},
+	}
+	for _, tc := range testCases {
+		cleanup, err := parseOnFailureCleanupExtension(tc.value)
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError, tc.name)
+			assert.Nil(t, cleanup, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expectedCleanup, cleanup, tc.name)
+	}
+}
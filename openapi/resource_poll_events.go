@@ -0,0 +1,120 @@
+package openapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+// ndjsonContentType is the content type of the newline delimited JSON streams, where each line is a JSON document
+const ndjsonContentType = "application/x-ndjson"
+
+// maxNDJSONEventSize is the max size of each of the events of the NDJSON streams
+const maxNDJSONEventSize = 1024 * 1024
+
+// pollEventsStreamer defines the behaviour expected from the clients able to stream the progress events of the resources,
+// which is not part of the ClientOpenAPI interface
+type pollEventsStreamer interface {
+	StreamEvents(ctx context.Context, resource SpecResource, id string, eventsPath string, onEvent func(event json.RawMessage), parentIDs ...string) error
+}
+
+// readNDJSONEvents calls onEvent with each of the events read from the given NDJSON stream until the stream ends. Blank
+// lines are ignored
+func readNDJSONEvents(stream io.Reader, onEvent func(event json.RawMessage)) error {
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), maxNDJSONEventSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if !json.Valid(line) {
+			return fmt.Errorf("event '%s' is not a valid JSON document", line)
+		}
+		// the scanner reuses its buffer for the following lines, so the event is copied before handing it over
+		event := make(json.RawMessage, len(line))
+		copy(event, line)
+		onEvent(event)
+	}
+	return scanner.Err()
+}
+
+// pollEventsConsumer logs the progress events streamed by the API while a resource is being polled and keeps the last
+// one. If the API closes the stream before the polling completes, the stream is opened again; the API is expected to send
+// all the events of the operation every time, the ones already received are not logged again
+type pollEventsConsumer struct {
+	sync.Mutex
+	resourceName string
+	id           string
+	received     int
+	lastEvent    json.RawMessage
+	cancel       context.CancelFunc
+	done         chan struct{}
+}
+
+func newPollEventsConsumer(resourceName, id string) *pollEventsConsumer {
+	return &pollEventsConsumer{
+		resourceName: resourceName,
+		id:           id,
+	}
+}
+
+// start consumes the events returned by the given stream function in the background until stopped or the given context is
+// done, opening the stream again after the given interval every time it ends
+func (c *pollEventsConsumer) start(ctx context.Context, stream func(ctx context.Context, onEvent func(event json.RawMessage)) error, reopenInterval time.Duration) {
+	ctx, cancel := context.WithCancel(ctx)
+	c.cancel = cancel
+	c.done = make(chan struct{})
+	go func() {
+		defer close(c.done)
+		for {
+			streamed := 0
+			err := stream(ctx, func(event json.RawMessage) {
+				streamed++
+				c.onEvent(streamed, event)
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				log.Printf("[WARN] failed to stream the progress events of resource '%s' (%s): %s", c.resourceName, c.id, err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(reopenInterval):
+			}
+		}
+	}()
+}
+
+// onEvent logs the given event, unless it was already received in a previous stream
+func (c *pollEventsConsumer) onEvent(position int, event json.RawMessage) {
+	c.Lock()
+	defer c.Unlock()
+	if position <= c.received {
+		return
+	}
+	c.received = position
+	c.lastEvent = event
+	log.Printf("[INFO] resource '%s' (%s) progress event: %s", c.resourceName, c.id, event)
+}
+
+// stop stops consuming the events, waiting for the stream to be closed, and returns the last event received; nil if no
+// events were received or the consumer is nil
+func (c *pollEventsConsumer) stop() json.RawMessage {
+	if c == nil {
+		return nil
+	}
+	c.cancel()
+	<-c.done
+	c.Lock()
+	defer c.Unlock()
+	return c.lastEvent
+}
@@ -0,0 +1,97 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadNDJSONEvents(t *testing.T) {
+	testCases := []struct {
+		name           string
+		stream         string
+		expectedEvents []json.RawMessage
+		expectedError  string
+	}{
+		{
+			name:           "events separated by new lines",
+			stream:         "{\"step\":\"provisioning\"}\n{\"step\":\"done\"}\n",
+			expectedEvents: []json.RawMessage{json.RawMessage(`{"step":"provisioning"}`), json.RawMessage(`{"step":"done"}`)},
+		},
+		{
+			name:           "blank lines and carriage returns are ignored",
+			stream:         "{\"step\":\"provisioning\"}\r\n\r\n  \n{\"step\":\"done\"}",
+			expectedEvents: []json.RawMessage{json.RawMessage(`{"step":"provisioning"}`), json.RawMessage(`{"step":"done"}`)},
+		},
+		{
+			name:   "empty stream",
+			stream: "",
+		},
+		{
+			name:           "event that is not valid JSON",
+			stream:         "{\"step\":\"provisioning\"}\nnot json\n{\"step\":\"done\"}\n",
+			expectedEvents: []json.RawMessage{json.RawMessage(`{"step":"provisioning"}`)},
+			expectedError:  "event 'not json' is not a valid JSON document",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var events []json.RawMessage
+			err := readNDJSONEvents(strings.NewReader(tc.stream), func(event json.RawMessage) {
+				events = append(events, event)
+			})
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedEvents, events)
+		})
+	}
+}
+
+func TestPollEventsConsumer(t *testing.T) {
+	t.Run("events replayed when the stream is opened again are not received twice", func(t *testing.T) {
+		streams := [][]json.RawMessage{
+			{json.RawMessage(`{"step":1}`)},
+			{json.RawMessage(`{"step":1}`), json.RawMessage(`{"step":2}`)},
+			{json.RawMessage(`{"step":1}`), json.RawMessage(`{"step":2}`), json.RawMessage(`{"step":3}`)},
+		}
+		opened := make(chan int, len(streams))
+		consumer := newPollEventsConsumer("cdn", "1234")
+		consumer.start(context.Background(), func(ctx context.Context, onEvent func(event json.RawMessage)) error {
+			stream := streams[len(opened)]
+			for _, event := range stream {
+				onEvent(event)
+			}
+			opened <- len(stream)
+			if len(opened) == len(streams) {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			return errors.New("stream closed")
+		}, time.Millisecond)
+		for len(opened) < len(streams) {
+			time.Sleep(time.Millisecond)
+		}
+		assert.Equal(t, json.RawMessage(`{"step":3}`), consumer.stop())
+		assert.Equal(t, 3, consumer.received)
+	})
+	t.Run("no events received", func(t *testing.T) {
+		consumer := newPollEventsConsumer("cdn", "1234")
+		consumer.start(context.Background(), func(ctx context.Context, onEvent func(event json.RawMessage)) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, time.Millisecond)
+		assert.Nil(t, consumer.stop())
+	})
+	t.Run("nil consumer", func(t *testing.T) {
+		var consumer *pollEventsConsumer
+		assert.Nil(t, consumer.stop())
+	})
+}
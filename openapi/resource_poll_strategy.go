@@ -0,0 +1,102 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// pollStrategy defines how the time to wait between the polling requests evolves
+type pollStrategy string
+
+const (
+	// pollStrategyConstant waits the poll interval between all the polling requests
+	pollStrategyConstant pollStrategy = "constant"
+	// pollStrategyLinear waits the poll interval after the first request, twice the poll interval after the second, etc
+	pollStrategyLinear pollStrategy = "linear"
+	// pollStrategyExponential waits the poll interval after the first request and doubles the wait after each request
+	pollStrategyExponential pollStrategy = "exponential"
+)
+
+// defaultPollMaxInterval is the max time to wait between the polling requests of the linear and exponential strategies
+// if the response does not define it
+var defaultPollMaxInterval = time.Duration(5 * time.Minute)
+
+// pollBackoffTick is the poll interval handed over to the state change conf when the waits are computed by the pollBackoff,
+// which waits in the refresh function instead
+const pollBackoffTick = time.Millisecond
+
+// errPollingStopped is returned by the refresh functions of the pollBackoff once the polling completed
+var errPollingStopped = errors.New("polling stopped")
+
+// pollBackoff computes the time to wait between the polling requests as per the poll strategy of the response. The
+// state change conf used for polling only supports constant intervals (or its own built-in backoff), hence the linear
+// and exponential strategies are implemented by waiting in the refresh function before each polling request but the
+// first one
+type pollBackoff struct {
+	strategy    pollStrategy
+	interval    time.Duration
+	maxInterval time.Duration
+}
+
+func newPollBackoff(response *specResponse, interval time.Duration) pollBackoff {
+	maxInterval := defaultPollMaxInterval
+	if response.pollMaxInterval > 0 {
+		maxInterval = response.pollMaxInterval
+	}
+	if maxInterval < interval {
+		maxInterval = interval
+	}
+	strategy := response.pollStrategy
+	if strategy == "" {
+		strategy = pollStrategyConstant
+	}
+	return pollBackoff{strategy: strategy, interval: interval, maxInterval: maxInterval}
+}
+
+// isConstant returns true if the time to wait between the polling requests is always the poll interval
+func (b pollBackoff) isConstant() bool {
+	return b.strategy != pollStrategyLinear && b.strategy != pollStrategyExponential
+}
+
+// wait returns the time to wait after the given polling request (starting at 1)
+func (b pollBackoff) wait(request int) time.Duration {
+	wait := b.interval
+	switch b.strategy {
+	case pollStrategyLinear:
+		wait = b.interval * time.Duration(request)
+	case pollStrategyExponential:
+		for i := 1; i < request && wait < b.maxInterval; i++ {
+			wait *= 2
+		}
+	}
+	if wait > b.maxInterval || wait <= 0 {
+		return b.maxInterval
+	}
+	return wait
+}
+
+// refreshFunc returns the given refresh function waiting, before each polling request but the first one, the time
+// computed for the previous request. The wait is interrupted if the context is done, and no more polling requests are
+// made once the stop channel is closed (e.g. the state change conf timed out)
+func (b pollBackoff) refreshFunc(ctx context.Context, stop <-chan struct{}, resourceName string, refresh resource.StateRefreshFunc) resource.StateRefreshFunc {
+	requests := 0
+	return func() (interface{}, string, error) {
+		if requests > 0 {
+			wait := b.wait(requests)
+			log.Printf("[DEBUG] waiting %s before polling resource '%s' again (%s poll strategy)", wait, resourceName, b.strategy)
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return nil, "", contextError(ctx)
+			case <-stop:
+				return nil, "", errPollingStopped
+			}
+		}
+		requests++
+		return refresh()
+	}
+}
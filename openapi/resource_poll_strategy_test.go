@@ -0,0 +1,132 @@
+package openapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPollBackoff(t *testing.T) {
+	testCases := []struct {
+		name            string
+		response        *specResponse
+		interval        time.Duration
+		expectedBackoff pollBackoff
+	}{
+		{
+			name:            "no strategy configured",
+			response:        &specResponse{},
+			interval:        5 * time.Second,
+			expectedBackoff: pollBackoff{strategy: pollStrategyConstant, interval: 5 * time.Second, maxInterval: defaultPollMaxInterval},
+		},
+		{
+			name:            "strategy and max interval configured",
+			response:        &specResponse{pollStrategy: pollStrategyExponential, pollMaxInterval: time.Minute},
+			interval:        5 * time.Second,
+			expectedBackoff: pollBackoff{strategy: pollStrategyExponential, interval: 5 * time.Second, maxInterval: time.Minute},
+		},
+		{
+			name:            "max interval lower than the poll interval",
+			response:        &specResponse{pollStrategy: pollStrategyLinear, pollMaxInterval: time.Second},
+			interval:        5 * time.Second,
+			expectedBackoff: pollBackoff{strategy: pollStrategyLinear, interval: 5 * time.Second, maxInterval: 5 * time.Second},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedBackoff, newPollBackoff(tc.response, tc.interval))
+		})
+	}
+}
+
+func TestPollBackoffWait(t *testing.T) {
+	testCases := []struct {
+		name          string
+		backoff       pollBackoff
+		expectedWaits []time.Duration
+	}{
+		{
+			name:          "constant",
+			backoff:       pollBackoff{strategy: pollStrategyConstant, interval: 5 * time.Second, maxInterval: time.Minute},
+			expectedWaits: []time.Duration{5 * time.Second, 5 * time.Second, 5 * time.Second, 5 * time.Second},
+		},
+		{
+			name:          "linear",
+			backoff:       pollBackoff{strategy: pollStrategyLinear, interval: 5 * time.Second, maxInterval: 12 * time.Second},
+			expectedWaits: []time.Duration{5 * time.Second, 10 * time.Second, 12 * time.Second, 12 * time.Second},
+		},
+		{
+			name:          "exponential",
+			backoff:       pollBackoff{strategy: pollStrategyExponential, interval: 5 * time.Second, maxInterval: time.Minute},
+			expectedWaits: []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var waits []time.Duration
+			for request := 1; request <= len(tc.expectedWaits); request++ {
+				waits = append(waits, tc.backoff.wait(request))
+			}
+			assert.Equal(t, tc.expectedWaits, waits)
+			assert.LessOrEqual(t, tc.backoff.wait(1000000), tc.backoff.maxInterval, "the wait should never exceed the max interval")
+		})
+	}
+}
+
+func TestPollBackoffRefreshFunc(t *testing.T) {
+	refreshes := 0
+	refresh := func() (interface{}, string, error) {
+		refreshes++
+		return nil, "pending", nil
+	}
+
+	t.Run("the first polling request is not delayed", func(t *testing.T) {
+		refreshes = 0
+		backoff := pollBackoff{strategy: pollStrategyExponential, interval: time.Hour, maxInterval: time.Hour}
+		_, status, err := backoff.refreshFunc(context.Background(), make(chan struct{}), "cdns_v1", refresh)()
+		assert.NoError(t, err)
+		assert.Equal(t, "pending", status)
+		assert.Equal(t, 1, refreshes)
+	})
+
+	t.Run("the following polling requests wait as per the strategy", func(t *testing.T) {
+		refreshes = 0
+		backoff := pollBackoff{strategy: pollStrategyLinear, interval: 10 * time.Millisecond, maxInterval: time.Second}
+		refreshFunc := backoff.refreshFunc(context.Background(), make(chan struct{}), "cdns_v1", refresh)
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			_, _, err := refreshFunc()
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 3, refreshes)
+		assert.True(t, time.Since(start) >= 30*time.Millisecond, "the polling requests should have waited 10ms and 20ms")
+	})
+
+	t.Run("no more polling requests are made once the polling stopped", func(t *testing.T) {
+		refreshes = 0
+		stop := make(chan struct{})
+		backoff := pollBackoff{strategy: pollStrategyExponential, interval: time.Hour, maxInterval: time.Hour}
+		refreshFunc := backoff.refreshFunc(context.Background(), stop, "cdns_v1", refresh)
+		_, _, err := refreshFunc()
+		assert.NoError(t, err)
+		close(stop)
+		_, _, err = refreshFunc()
+		assert.Equal(t, errPollingStopped, err)
+		assert.Equal(t, 1, refreshes)
+	})
+
+	t.Run("the wait is interrupted if the context is done", func(t *testing.T) {
+		refreshes = 0
+		ctx, cancel := context.WithCancel(context.Background())
+		backoff := pollBackoff{strategy: pollStrategyExponential, interval: time.Hour, maxInterval: time.Hour}
+		refreshFunc := backoff.refreshFunc(ctx, make(chan struct{}), "cdns_v1", refresh)
+		_, _, err := refreshFunc()
+		assert.NoError(t, err)
+		cancel()
+		_, _, err = refreshFunc()
+		assert.Equal(t, errOperationCancelled, err)
+		assert.Equal(t, 1, refreshes)
+	})
+}
@@ -0,0 +1,88 @@
+package openapi
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// retryBudget keeps track of the time spent waiting on remote resources across the whole terraform run (e.g. polling
+// until a resource reaches a completion status) so a pathological API outage fails the run in a bounded time instead of
+// waiting for each resource's timeout. A nil retryBudget is valid and means no limits are applied.
+type retryBudget struct {
+	mutex sync.Mutex
+	// budget is the max cumulative time that can be spent waiting across the run; zero means unlimited
+	budget time.Duration
+	// spent is the time spent waiting so far
+	spent time.Duration
+	// applyDeadline is the max time allowed since the first create, update or delete of the run; zero means no deadline
+	applyDeadline time.Duration
+	// deadline is the point in time after which no more waiting is allowed; zero value means no deadline or that the
+	// apply has not started yet
+	deadline time.Time
+}
+
+// newRetryBudget returns a retryBudget configured with the given budget and apply deadline. The apply deadline clock is not
+// started until start is called. Nil is returned if neither the budget nor the deadline are configured.
+func newRetryBudget(budget time.Duration, applyDeadline time.Duration) *retryBudget {
+	if budget <= 0 && applyDeadline <= 0 {
+		return nil
+	}
+	return &retryBudget{budget: budget, applyDeadline: applyDeadline}
+}
+
+// start starts the apply deadline clock if it has not been started yet. It is called on every create, update and delete
+// so the deadline is relative to the first one of the run and not to the time the provider was started (e.g. plans, or
+// long refreshes before the apply begins do not count towards the deadline)
+func (r *retryBudget) start() {
+	if r == nil || r.applyDeadline <= 0 {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.deadline.IsZero() {
+		r.deadline = time.Now().Add(r.applyDeadline)
+	}
+}
+
+// timeoutFor returns the timeout that should be used when waiting, which is the given timeout capped by the remaining retry
+// budget and the time left until the apply deadline. An error is returned if there's no time left.
+func (r *retryBudget) timeoutFor(timeout time.Duration) (time.Duration, error) {
+	if r == nil {
+		return timeout, nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.budget > 0 {
+		remaining := r.budget - r.spent
+		if remaining <= 0 {
+			return 0, fmt.Errorf("retry budget of %s exhausted", r.budget)
+		}
+		if remaining < timeout {
+			log.Printf("[DEBUG] capping timeout %s to the remaining retry budget %s", timeout, remaining)
+			timeout = remaining
+		}
+	}
+	if !r.deadline.IsZero() {
+		remaining := time.Until(r.deadline)
+		if remaining <= 0 {
+			return 0, fmt.Errorf("apply deadline exceeded")
+		}
+		if remaining < timeout {
+			log.Printf("[DEBUG] capping timeout %s to the time left until the apply deadline %s", timeout, remaining)
+			timeout = remaining
+		}
+	}
+	return timeout, nil
+}
+
+// consume records the given time as spent from the retry budget
+func (r *retryBudget) consume(d time.Duration) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.spent += d
+}
@@ -0,0 +1,134 @@
+package openapi
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewRetryBudget(t *testing.T) {
+	Convey("Given no retry budget nor apply deadline", t, func() {
+		Convey("When newRetryBudget is called", func() {
+			r := newRetryBudget(0, 0)
+			Convey("Then the retry budget returned should be nil", func() {
+				So(r, ShouldBeNil)
+			})
+		})
+	})
+	Convey("Given a retry budget and an apply deadline", t, func() {
+		Convey("When newRetryBudget is called", func() {
+			r := newRetryBudget(time.Minute, time.Hour)
+			Convey("Then the retry budget returned should be configured with the budget", func() {
+				So(r.budget, ShouldEqual, time.Minute)
+			})
+			Convey("And the deadline should not be started yet", func() {
+				So(r.applyDeadline, ShouldEqual, time.Hour)
+				So(r.deadline.IsZero(), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestRetryBudgetStart(t *testing.T) {
+	Convey("Given a nil retry budget", t, func() {
+		var r *retryBudget
+		Convey("When start is called", func() {
+			Convey("Then it should not panic", func() {
+				So(r.start, ShouldNotPanic)
+			})
+		})
+	})
+	Convey("Given a retry budget with an apply deadline", t, func() {
+		r := newRetryBudget(0, time.Hour)
+		Convey("When start is called", func() {
+			r.start()
+			Convey("Then the deadline should be set in the future", func() {
+				So(r.deadline.After(time.Now()), ShouldBeTrue)
+			})
+		})
+		Convey("When start is called again after the deadline was started", func() {
+			r.start()
+			deadline := r.deadline
+			r.start()
+			Convey("Then the deadline should not be moved", func() {
+				So(r.deadline, ShouldEqual, deadline)
+			})
+		})
+	})
+	Convey("Given a retry budget without apply deadline", t, func() {
+		r := newRetryBudget(time.Minute, 0)
+		Convey("When start is called", func() {
+			r.start()
+			Convey("Then no deadline should be set", func() {
+				So(r.deadline.IsZero(), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestRetryBudgetTimeoutFor(t *testing.T) {
+	Convey("Given a nil retry budget", t, func() {
+		var r *retryBudget
+		Convey("When timeoutFor is called", func() {
+			timeout, err := r.timeoutFor(10 * time.Minute)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the timeout returned should be the one passed in", func() {
+				So(timeout, ShouldEqual, 10*time.Minute)
+			})
+		})
+	})
+	Convey("Given a retry budget with some budget already spent", t, func() {
+		r := newRetryBudget(5*time.Minute, 0)
+		r.consume(3 * time.Minute)
+		Convey("When timeoutFor is called with a timeout bigger than the remaining budget", func() {
+			timeout, err := r.timeoutFor(10 * time.Minute)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the timeout returned should be capped to the remaining budget", func() {
+				So(timeout, ShouldEqual, 2*time.Minute)
+			})
+		})
+		Convey("When timeoutFor is called with a timeout smaller than the remaining budget", func() {
+			timeout, err := r.timeoutFor(time.Minute)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the timeout returned should be the one passed in", func() {
+				So(timeout, ShouldEqual, time.Minute)
+			})
+		})
+		Convey("When the rest of the budget is consumed and timeoutFor is called", func() {
+			r.consume(2 * time.Minute)
+			_, err := r.timeoutFor(time.Minute)
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "retry budget of 5m0s exhausted")
+			})
+		})
+	})
+	Convey("Given a retry budget with an apply deadline", t, func() {
+		r := newRetryBudget(0, time.Minute)
+		r.start()
+		Convey("When timeoutFor is called with a timeout bigger than the time left until the deadline", func() {
+			timeout, err := r.timeoutFor(10 * time.Minute)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the timeout returned should be capped to the time left", func() {
+				So(timeout, ShouldBeLessThanOrEqualTo, time.Minute)
+			})
+		})
+	})
+	Convey("Given a retry budget with an apply deadline that has already passed", t, func() {
+		r := &retryBudget{deadline: time.Now().Add(-time.Second)}
+		Convey("When timeoutFor is called", func() {
+			_, err := r.timeoutFor(10 * time.Minute)
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "apply deadline exceeded")
+			})
+		})
+	})
+}
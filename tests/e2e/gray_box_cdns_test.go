@@ -10,11 +10,10 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/stretchr/testify/require"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 
 	"github.com/dikhan/terraform-provider-openapi/openapi"
 	"github.com/stretchr/testify/assert"
@@ -451,7 +450,7 @@ func TestAccCDN_Create_and_UpdateSubResource(t *testing.T) {
 		openAPIResourceNameCDN:         fmt.Sprintf("%s/v1/cdns", api.apiHost),
 	}
 
-	var testAccProviders = map[string]terraform.ResourceProvider{providerName: provider}
+	var testAccProviders = map[string]*schema.Provider{providerName: provider}
 	resource.Test(t, resource.TestCase{
 		IsUnitTest:   true,
 		Providers:    testAccProviders,
@@ -535,7 +534,7 @@ func TestAcc_Create_MissingRequiredParentPropertyInTFConfigurationFile(t *testin
 	assert.NoError(t, err)
 	assertProviderSchema(t, provider)
 
-	var testAccProviders = map[string]terraform.ResourceProvider{providerName: provider}
+	var testAccProviders = map[string]*schema.Provider{providerName: provider}
 	testCDNCreateMissingParentPropertyInFW := fmt.Sprintf(`
 		# URI /v1/cdns/
 		resource "%s" "%s" {
@@ -583,7 +582,7 @@ func TestAccCDN_ImportSubResource(t *testing.T) {
 		openAPIResourceNameCDN:         fmt.Sprintf("%s/v1/cdns", api.apiHost),
 	}
 
-	var testAccProviders = map[string]terraform.ResourceProvider{providerName: provider}
+	var testAccProviders = map[string]*schema.Provider{providerName: provider}
 	resource.Test(t, resource.TestCase{
 		IsUnitTest:   true,
 		Providers:    testAccProviders,
@@ -624,7 +623,7 @@ func TestAccCDN_DataSource(t *testing.T) {
 	assert.NoError(t, err)
 	assertProviderSchema(t, provider)
 
-	var testAccProviders = map[string]terraform.ResourceProvider{providerName: provider}
+	var testAccProviders = map[string]*schema.Provider{providerName: provider}
 	resource.Test(t, resource.TestCase{
 		IsUnitTest: true,
 		Providers:  testAccProviders,
@@ -669,7 +668,7 @@ func TestAccCDN_DataSourceInstance(t *testing.T) {
 	assert.NoError(t, err)
 	assertProviderSchema(t, provider)
 
-	var testAccProviders = map[string]terraform.ResourceProvider{providerName: provider}
+	var testAccProviders = map[string]*schema.Provider{providerName: provider}
 	resource.Test(t, resource.TestCase{
 		IsUnitTest: true,
 		Providers:  testAccProviders,
@@ -2,13 +2,12 @@ package integration
 
 import (
 	"github.com/dikhan/terraform-provider-openapi/openapi"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"log"
 	"os"
 	"testing"
 
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 )
 
 var exampleSwaggerFile string
@@ -20,7 +19,7 @@ var otfVarSwaggerURLEnvVariableValue = "https://localhost:8443/swagger.yaml"
 var otfVarInsecureSkipVerifyEnvVariable = "OTF_INSECURE_SKIP_VERIFY"
 
 var testAccProvider = getAPIProviderInitWithPluginEnvVariables()
-var testAccProviders = map[string]terraform.ResourceProvider{providerName: testAccProvider}
+var testAccProviders = map[string]*schema.Provider{providerName: testAccProvider}
 
 func TestOpenAPIProvider(t *testing.T) {
 	if err := testAccProvider.InternalValidate(); err != nil {
@@ -29,7 +28,7 @@ func TestOpenAPIProvider(t *testing.T) {
 }
 
 func TestProvider_impl(t *testing.T) {
-	var _ terraform.ResourceProvider = testAccProvider
+	var _ *schema.Provider = testAccProvider
 }
 
 func getAPIProviderInitWithPluginEnvVariables() *schema.Provider {
@@ -37,10 +36,10 @@ func getAPIProviderInitWithPluginEnvVariables() *schema.Provider {
 	return getAPIProvider()
 }
 
-func getTestAccProviderInitWithPluginConfigurationFile(pluginConfigFile string) map[string]terraform.ResourceProvider {
+func getTestAccProviderInitWithPluginConfigurationFile(pluginConfigFile string) map[string]*schema.Provider {
 	initPluginWithExternalConfigFile(pluginConfigFile)
 	testAccProvider = getAPIProvider()
-	return map[string]terraform.ResourceProvider{
+	return map[string]*schema.Provider{
 		providerName: testAccProvider,
 	}
 }
@@ -8,8 +8,8 @@ import (
 	"regexp"
 
 	"github.com/dikhan/terraform-provider-openapi/examples/swaggercodegen/api/api"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 const resourcePathCDN = "/v1/cdns"